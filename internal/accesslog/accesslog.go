@@ -0,0 +1,133 @@
+package accesslog
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"log/slog"
+	"math/rand"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"nadmon-backend/internal/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Config controls which requests are logged and how they are scrubbed
+type Config struct {
+	// SampleRates maps a route pattern (e.g. "/api/nfts/:tokenId") to the share of requests logged
+	SampleRates map[string]float64
+	// Exclude lists route patterns that are never logged
+	Exclude []string
+	// HashAddresses replaces wallet addresses with a short hash
+	HashAddresses bool
+}
+
+// ParseSampleRates parses "route=rate,route=rate" into a sample rate map
+func ParseSampleRates(spec string) map[string]float64 {
+	rates := make(map[string]float64)
+	for _, entry := range strings.Split(spec, ",") {
+		route, rate, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok {
+			continue
+		}
+		value, err := strconv.ParseFloat(rate, 64)
+		if err != nil {
+			log.Printf("Warning: invalid access log sample rate %q", entry)
+			continue
+		}
+		rates[strings.TrimSpace(route)] = value
+	}
+	return rates
+}
+
+// sensitiveParams are query parameters whose values are never logged
+var sensitiveParams = map[string]bool{
+	"token":     true,
+	"signature": true,
+	"sig":       true,
+	"key":       true,
+	"api_key":   true,
+	"password":  true,
+	"secret":    true,
+}
+
+var addressPattern = regexp.MustCompile(`0x[0-9a-fA-F]{40}`)
+
+// Middleware logs completed requests as structured records tagged with the request ID,
+// applying sampling and scrubbing
+func Middleware(cfg Config) gin.HandlerFunc {
+	excluded := make(map[string]bool, len(cfg.Exclude))
+	for _, route := range cfg.Exclude {
+		excluded[route] = true
+	}
+
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if excluded[route] {
+			return
+		}
+
+		status := c.Writer.Status()
+		// Server errors are always logged, whatever the sample rate
+		if rate, ok := cfg.SampleRates[route]; ok && status < 500 && rand.Float64() >= rate {
+			return
+		}
+
+		level := slog.LevelInfo
+		if status >= 500 {
+			level = slog.LevelError
+		}
+		logging.FromContext(c.Request.Context()).Log(c.Request.Context(), level, "request",
+			"status", status,
+			"latency_ms", float64(time.Since(start).Microseconds())/1000,
+			"ip", c.ClientIP(),
+			"method", c.Request.Method,
+			"path", cfg.scrub(c.Request.URL),
+			"route", route,
+		)
+	}
+}
+
+// scrub returns the request path and query with sensitive values removed
+func (cfg Config) scrub(u *url.URL) string {
+	path := u.Path
+	if cfg.HashAddresses {
+		path = addressPattern.ReplaceAllStringFunc(path, hashAddress)
+	}
+	if u.RawQuery == "" {
+		return path
+	}
+
+	query := u.Query()
+	for key, values := range query {
+		for i, value := range values {
+			switch {
+			case sensitiveParams[strings.ToLower(key)]:
+				values[i] = "REDACTED"
+			case cfg.HashAddresses:
+				values[i] = addressPattern.ReplaceAllStringFunc(value, hashAddress)
+			}
+		}
+	}
+
+	// Encode escapes the placeholder characters, keep the log readable
+	decoded, err := url.QueryUnescape(query.Encode())
+	if err != nil {
+		return path + "?" + query.Encode()
+	}
+	return path + "?" + decoded
+}
+
+// hashAddress replaces an address with a stable, non-reversible short form
+func hashAddress(address string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(address)))
+	return "addr:" + hex.EncodeToString(sum[:6])
+}
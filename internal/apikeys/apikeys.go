@@ -0,0 +1,398 @@
+// Package apikeys authenticates partner integrations (marketplaces, analytics sites) sending
+// an API key with their requests, enforces the key's scopes, rate limit and daily quota, and
+// accounts for its usage
+package apikeys
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"nadmon-backend/internal/cache"
+	"nadmon-backend/internal/models"
+	"nadmon-backend/internal/ratelimit"
+	"nadmon-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Header carries a partner's API key; the Authorization header stays free for session tokens
+const Header = "X-API-Key"
+
+// ContextKey holds the *models.APIKey of a request authenticated with an API key
+const ContextKey = "apikeys.key"
+
+// keyPrefix starts every issued key, so leaked keys are easy to spot in logs and code
+const keyPrefix = "nk_"
+
+// cacheTTL bounds how long a replica keeps serving a key revoked on another replica
+const cacheTTL = 30 * time.Second
+
+// maxUnknownKeys caps the unknown keys remembered, so guessing keys neither costs a query per
+// request nor grows the cache without bound
+const maxUnknownKeys = 10000
+
+// DefaultAnalyticsPaths are the route fragments needing the analytics scope: the aggregate
+// stats and analytics endpoints
+var DefaultAnalyticsPaths = []string{
+	"/analytics/",
+	"/stats/game",
+	"/stats/pack-distribution",
+	"/stats/concentration",
+	"/stats/supply",
+	"/stats/elements",
+	"/stats/rarities",
+	"/stats/floor",
+}
+
+// Service checks API keys and counts their usage. Keys are cached for cacheTTL; usage is
+// counted in memory and written to the store by Flush, so daily quotas are exact on one replica
+// and may overshoot by one flush interval's requests across several.
+type Service struct {
+	store          repository.APIKeyStore
+	limiter        ratelimit.Limiter
+	ipLimit        ratelimit.Limit
+	analyticsPaths []string
+	now            func() time.Time
+	unknown        *cache.LRU[string, struct{}] // hashes of keys not in the store
+
+	mu      sync.Mutex
+	keys    map[string]*cachedKey // by hash, only keys found in the store
+	pending map[usageKey]*counter
+}
+
+// cachedKey is a key looked up in the store and its requests counted today; key is nil for
+// unknown keys
+type cachedKey struct {
+	key     *models.APIKey
+	fetched time.Time
+	day     string
+	today   int64
+}
+
+// usageKey identifies one key's usage on one UTC day
+type usageKey struct {
+	id  int64
+	day string
+}
+
+// counter is usage not yet written to the store
+type counter struct {
+	requests int64
+	rejected int64
+	lastUsed time.Time
+}
+
+// NewService creates an API key service drawing per-key rate limits from limiter. Keys not
+// known to be valid draw from the client's per-IP default bucket with ipLimit before being
+// looked up. Routes matching analyticsPaths (DefaultAnalyticsPaths when empty) need the
+// analytics scope.
+func NewService(store repository.APIKeyStore, limiter ratelimit.Limiter, ipLimit ratelimit.Limit, analyticsPaths []string) *Service {
+	if len(analyticsPaths) == 0 {
+		analyticsPaths = DefaultAnalyticsPaths
+	}
+	return &Service{
+		store:          store,
+		limiter:        limiter,
+		ipLimit:        ipLimit,
+		analyticsPaths: analyticsPaths,
+		now:            time.Now,
+		unknown:        cache.NewLRU[string, struct{}](maxUnknownKeys, cacheTTL),
+		keys:           make(map[string]*cachedKey),
+		pending:        make(map[usageKey]*counter),
+	}
+}
+
+// Hash returns the digest keys are stored and looked up by. Keys are long random strings, so
+// a plain SHA-256 is enough.
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Issue creates a key with the settings of template; the returned key carries the secret,
+// which is never available again
+func (s *Service) Issue(ctx context.Context, template models.APIKey) (*models.APIKey, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+	secret := keyPrefix + hex.EncodeToString(b)
+
+	template.Prefix = secret[:len(keyPrefix)+8]
+	key, err := s.store.CreateAPIKey(ctx, template, Hash(secret))
+	if err != nil {
+		return nil, err
+	}
+	key.Key = secret
+	return key, nil
+}
+
+// Revoke revokes a key; this replica stops accepting it right away, others within cacheTTL
+func (s *Service) Revoke(ctx context.Context, id int64) (bool, error) {
+	found, err := s.store.RevokeAPIKey(ctx, id)
+	if err != nil || !found {
+		return found, err
+	}
+
+	s.mu.Lock()
+	for hash, cached := range s.keys {
+		if cached.key.ID == id {
+			delete(s.keys, hash)
+		}
+	}
+	s.mu.Unlock()
+	return true, nil
+}
+
+// Middleware authenticates requests sending an API key; requests without one pass through
+// untouched. Keyed requests must be reads within the key's scopes, draw from the key's bucket
+// instead of the per-IP ones, and count against its daily quota.
+func (s *Service) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		secret := c.GetHeader(Header)
+		if secret == "" {
+			c.Next()
+			return
+		}
+
+		// Unknown keys are refused before the per-IP limits run, so keys not known to be
+		// valid draw from the client's bucket first: guessing keys is limited like any traffic
+		hash := Hash(secret)
+		if !s.known(hash) && !s.allowIP(c) {
+			return
+		}
+
+		cached, err := s.lookup(c.Request.Context(), hash)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to check API key: " + err.Error()})
+			return
+		}
+		if cached.key == nil || !cached.key.Active() {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or revoked API key"})
+			return
+		}
+		key := cached.key
+
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "API keys are read-only, writes need a session token"})
+			return
+		}
+		if scope := s.scope(c.FullPath()); !key.HasScope(scope) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "API key lacks the " + scope + " scope"})
+			return
+		}
+
+		if !s.admit(cached) {
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Daily quota of " + strconv.FormatInt(key.DailyQuota, 10) + " requests exceeded"})
+			return
+		}
+
+		limit := ratelimit.Limit{Rate: key.RateLimit, Burst: key.Burst}
+		if limit.Enabled() {
+			allowed, retryAfter, err := s.limiter.Allow("apikey:"+strconv.FormatInt(key.ID, 10), limit)
+			if err != nil {
+				log.Printf("Warning: rate limiter unavailable: %v", err)
+			} else if !allowed {
+				s.count(cached, false)
+				ratelimit.Reject(c, retryAfter)
+				return
+			}
+		}
+
+		s.count(cached, true)
+		c.Set(ContextKey, key)
+		c.Set(ratelimit.ExemptKey, true)
+		c.Next()
+	}
+}
+
+// scope returns the scope a route needs
+func (s *Service) scope(route string) string {
+	for _, fragment := range s.analyticsPaths {
+		if strings.Contains(route, fragment) {
+			return models.APIKeyScopeAnalytics
+		}
+	}
+	return models.APIKeyScopeRead
+}
+
+// known reports whether the key with the given hash is cached as an active key
+func (s *Service) known(hash string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cached, ok := s.keys[hash]
+	return ok && s.now().Sub(cached.fetched) < cacheTTL && cached.key.Active()
+}
+
+// allowIP takes a token from the client's per-IP default bucket, rejecting the request when
+// it is empty; limiter errors let the request through
+func (s *Service) allowIP(c *gin.Context) bool {
+	if !s.ipLimit.Enabled() {
+		return true
+	}
+	allowed, retryAfter, err := s.limiter.Allow(ratelimit.IPBucket(c.ClientIP(), "default"), s.ipLimit)
+	if err != nil {
+		log.Printf("Warning: rate limiter unavailable: %v", err)
+		return true
+	}
+	if !allowed {
+		ratelimit.Reject(c, retryAfter)
+		return false
+	}
+	return true
+}
+
+// lookup returns the cached key with the given hash
+func (s *Service) lookup(ctx context.Context, hash string) (*cachedKey, error) {
+	now := s.now()
+
+	s.mu.Lock()
+	cached, ok := s.keys[hash]
+	s.mu.Unlock()
+	if ok && now.Sub(cached.fetched) < cacheTTL {
+		return cached, nil
+	}
+	if _, ok := s.unknown.Get(hash); ok {
+		return &cachedKey{}, nil
+	}
+
+	key, err := s.store.GetAPIKeyByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil {
+		s.unknown.Add(hash, struct{}{})
+		return &cachedKey{}, nil
+	}
+
+	// The stored count includes what this replica flushed; add what it hasn't yet
+	day := utcDay(now)
+	cached = &cachedKey{key: key, fetched: now, day: day, today: key.Usage.Today}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if pending, ok := s.pending[usageKey{key.ID, day}]; ok {
+		cached.today += pending.requests
+	}
+	s.keys[hash] = cached
+	return cached, nil
+}
+
+// admit reports whether the key has quota left today, counting the request as rejected if not
+func (s *Service) admit(cached *cachedKey) bool {
+	if cached.key.DailyQuota <= 0 {
+		return true
+	}
+
+	day := utcDay(s.now())
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cached.day != day {
+		cached.day, cached.today = day, 0
+	}
+	if cached.today >= cached.key.DailyQuota {
+		s.add(cached.key.ID, day, false)
+		return false
+	}
+	return true
+}
+
+// count records a request made with the key
+func (s *Service) count(cached *cachedKey, admitted bool) {
+	day := utcDay(s.now())
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if admitted {
+		if cached.day != day {
+			cached.day, cached.today = day, 0
+		}
+		cached.today++
+	}
+	s.add(cached.key.ID, day, admitted)
+}
+
+// add counts one request in the pending usage; the caller holds s.mu
+func (s *Service) add(id int64, day string, admitted bool) {
+	k := usageKey{id, day}
+	pending, ok := s.pending[k]
+	if !ok {
+		pending = &counter{}
+		s.pending[k] = pending
+	}
+	if admitted {
+		pending.requests++
+		pending.lastUsed = s.now()
+	} else {
+		pending.rejected++
+	}
+}
+
+// Flush writes the usage counted since the last flush to the store; usage that fails to be
+// written is kept for the next flush
+func (s *Service) Flush(ctx context.Context) error {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = make(map[usageKey]*counter)
+	s.mu.Unlock()
+
+	var firstErr error
+	for k, usage := range pending {
+		lastUsed := usage.lastUsed
+		if lastUsed.IsZero() {
+			lastUsed = s.now()
+		}
+		err := s.store.RecordAPIKeyUsage(ctx, k.id, k.day, usage.requests, usage.rejected, lastUsed)
+		if err == nil {
+			continue
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+
+		s.mu.Lock()
+		if current, ok := s.pending[k]; ok {
+			current.requests += usage.requests
+			current.rejected += usage.rejected
+			if usage.lastUsed.After(current.lastUsed) {
+				current.lastUsed = usage.lastUsed
+			}
+		} else {
+			s.pending[k] = usage
+		}
+		s.mu.Unlock()
+	}
+	return firstErr
+}
+
+// Run flushes usage every interval until stop is closed, then flushes once more
+func (s *Service) Run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := s.Flush(context.Background()); err != nil {
+				log.Printf("Warning: API key usage flush: %v", err)
+			}
+		case <-stop:
+			if err := s.Flush(context.Background()); err != nil {
+				log.Printf("Warning: API key usage flush: %v", err)
+			}
+			return
+		}
+	}
+}
+
+// utcDay formats the UTC day of t as YYYY-MM-DD
+func utcDay(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
@@ -0,0 +1,246 @@
+package apikeys
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"nadmon-backend/internal/models"
+	"nadmon-backend/internal/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// memoryStore is an in-memory APIKeyStore
+type memoryStore struct {
+	mu      sync.Mutex
+	keys    map[string]*models.APIKey
+	usage   map[usageKey]*models.APIKeyUsage
+	nextID  int64
+	lookups int
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{keys: make(map[string]*models.APIKey), usage: make(map[usageKey]*models.APIKeyUsage)}
+}
+
+func (m *memoryStore) CreateAPIKey(ctx context.Context, key models.APIKey, hash string) (*models.APIKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextID++
+	key.ID = m.nextID
+	key.CreatedAt = time.Now()
+	m.keys[hash] = &key
+	saved := key
+	return &saved, nil
+}
+
+func (m *memoryStore) GetAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	return nil, nil
+}
+
+func (m *memoryStore) GetAPIKeyByHash(ctx context.Context, hash string) (*models.APIKey, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lookups++
+	key, ok := m.keys[hash]
+	if !ok {
+		return nil, nil
+	}
+	found := *key
+	found.Usage = &models.APIKeyUsageSummary{}
+	if usage, ok := m.usage[usageKey{key.ID, utcDay(time.Now())}]; ok {
+		found.Usage.Today = usage.Requests
+	}
+	return &found, nil
+}
+
+func (m *memoryStore) RevokeAPIKey(ctx context.Context, id int64) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range m.keys {
+		if key.ID == id {
+			now := time.Now()
+			key.RevokedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (m *memoryStore) RecordAPIKeyUsage(ctx context.Context, id int64, day string, requests, rejected int64, lastUsed time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	usage, ok := m.usage[usageKey{id, day}]
+	if !ok {
+		usage = &models.APIKeyUsage{Day: day}
+		m.usage[usageKey{id, day}] = usage
+	}
+	usage.Requests += requests
+	usage.Rejected += rejected
+	return nil
+}
+
+func (m *memoryStore) GetAPIKeyUsage(ctx context.Context, id int64, days int) ([]models.APIKeyUsage, error) {
+	return nil, nil
+}
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+	store := newMemoryStore()
+	limiter := ratelimit.NewMemory()
+	defer limiter.Close()
+	service := NewService(store, limiter, ratelimit.Limit{}, nil)
+
+	readKey, err := service.Issue(ctx, models.APIKey{Name: "marketplace", Scopes: []string{models.APIKeyScopeRead}, RateLimit: 100, Burst: 100, DailyQuota: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	analyticsKey, err := service.Issue(ctx, models.APIKey{Name: "analytics", Scopes: models.APIKeyScopes, RateLimit: 0.001, Burst: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if readKey.Key == "" || readKey.Prefix != readKey.Key[:len(readKey.Prefix)] || readKey.Key == analyticsKey.Key {
+		t.Fatalf("expected distinct secrets starting with their prefix, got %+v and %+v", readKey, analyticsKey)
+	}
+
+	r := gin.New()
+	api := r.Group("/api", service.Middleware())
+	api.Use(ratelimit.Middleware(limiter, ratelimit.Rules{Default: ratelimit.Limit{Rate: 0.001, Burst: 1}}))
+	ok := func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"data": "ok"}) }
+	api.GET("/nfts/:tokenId", ok)
+	api.GET("/stats/game", ok)
+	api.POST("/trades", ok)
+
+	request := func(method, path, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		if key != "" {
+			req.Header.Set(Header, key)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Without a key the per-IP limit applies
+	if w := request(http.MethodGet, "/api/nfts/1", ""); w.Code != http.StatusOK {
+		t.Fatalf("expected 200 without a key, got %d", w.Code)
+	}
+	if w := request(http.MethodGet, "/api/nfts/1", ""); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected the per-IP limit without a key, got %d", w.Code)
+	}
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		key    string
+		status int
+	}{
+		{"keyed request skips the per-IP limit", http.MethodGet, "/api/nfts/1", readKey.Key, http.StatusOK},
+		{"unknown key", http.MethodGet, "/api/nfts/1", "nk_unknown", http.StatusUnauthorized},
+		{"writes are refused", http.MethodPost, "/api/trades", readKey.Key, http.StatusForbidden},
+		{"analytics needs its scope", http.MethodGet, "/api/stats/game", readKey.Key, http.StatusForbidden},
+		{"analytics scope", http.MethodGet, "/api/stats/game", analyticsKey.Key, http.StatusOK},
+		{"read scope", http.MethodGet, "/api/nfts/2", analyticsKey.Key, http.StatusOK},
+		{"per-key rate limit", http.MethodGet, "/api/nfts/3", analyticsKey.Key, http.StatusTooManyRequests},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if w := request(tc.method, tc.path, tc.key); w.Code != tc.status {
+				t.Errorf("expected %d, got %d: %s", tc.status, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	t.Run("daily quota", func(t *testing.T) {
+		// One request was made above; the quota counts the flushed usage too
+		if err := service.Flush(ctx); err != nil {
+			t.Fatal(err)
+		}
+		for i := 0; i < 4; i++ {
+			if w := request(http.MethodGet, "/api/nfts/1", readKey.Key); w.Code != http.StatusOK {
+				t.Fatalf("expected request %d within the quota, got %d", i+2, w.Code)
+			}
+		}
+		if w := request(http.MethodGet, "/api/nfts/1", readKey.Key); w.Code != http.StatusTooManyRequests {
+			t.Errorf("expected 429 over the daily quota, got %d", w.Code)
+		}
+	})
+
+	t.Run("usage is flushed", func(t *testing.T) {
+		if err := service.Flush(ctx); err != nil {
+			t.Fatal(err)
+		}
+		usage := store.usage[usageKey{readKey.ID, utcDay(time.Now())}]
+		if usage == nil || usage.Requests != 5 || usage.Rejected != 1 {
+			t.Errorf("expected 5 requests and 1 rejected, got %+v", usage)
+		}
+	})
+
+	t.Run("revoked key", func(t *testing.T) {
+		if found, err := service.Revoke(ctx, analyticsKey.ID); err != nil || !found {
+			t.Fatalf("expected the key to be revoked, got %v, %v", found, err)
+		}
+		if w := request(http.MethodGet, "/api/nfts/1", analyticsKey.Key); w.Code != http.StatusUnauthorized {
+			t.Errorf("expected 401 for a revoked key, got %d", w.Code)
+		}
+	})
+}
+
+func TestUnknownKeysLimitedPerIP(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := newMemoryStore()
+	limiter := ratelimit.NewMemory()
+	defer limiter.Close()
+	service := NewService(store, limiter, ratelimit.Limit{Rate: 0.001, Burst: 2}, nil)
+
+	r := gin.New()
+	r.GET("/api/nfts/:tokenId", service.Middleware(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	// Each fresh key would cost a lookup; past the client's bucket they are refused unlooked
+	for i, want := range []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests} {
+		req := httptest.NewRequest(http.MethodGet, "/api/nfts/1", nil)
+		req.Header.Set(Header, "nk_guess"+strconv.Itoa(i))
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Errorf("key %d: expected %d, got %d", i, want, w.Code)
+		}
+	}
+	if store.lookups != 2 {
+		t.Errorf("expected 2 lookups, got %d", store.lookups)
+	}
+}
+
+func TestUnknownKeysBounded(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryStore()
+	service := NewService(store, nil, ratelimit.Limit{}, nil)
+
+	for i := 0; i < maxUnknownKeys+100; i++ {
+		if _, err := service.lookup(ctx, Hash("nk_guess"+strconv.Itoa(i))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if n := service.unknown.Len(); n != maxUnknownKeys {
+		t.Errorf("expected %d unknown keys cached, got %d", maxUnknownKeys, n)
+	}
+	if n := len(service.keys); n != 0 {
+		t.Errorf("expected unknown keys kept out of the key cache, got %d", n)
+	}
+
+	// A cached unknown key isn't looked up again
+	lookups := store.lookups
+	if _, err := service.lookup(ctx, Hash("nk_guess"+strconv.Itoa(maxUnknownKeys+99))); err != nil {
+		t.Fatal(err)
+	}
+	if store.lookups != lookups {
+		t.Errorf("expected the unknown key served from the cache")
+	}
+}
@@ -0,0 +1,210 @@
+// Package apiversion negotiates the API response format. Version 1 wraps every JSON response
+// in an envelope with data, meta and a machine-readable error; unversioned /api requests keep
+// the legacy bodies the handlers write.
+package apiversion
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Header selects the response format of /api requests and reports the one served
+const Header = "API-Version"
+
+// V1 is the enveloped response format
+const V1 = "1"
+
+// Supported lists the versions clients may request
+var Supported = []string{V1}
+
+// Error codes of enveloped error responses, by HTTP status; handlers may set a more specific
+// one with a "code" field next to "error"
+const (
+	CodeBadRequest         = "BAD_REQUEST"
+	CodeUnauthorized       = "UNAUTHORIZED"
+	CodeForbidden          = "FORBIDDEN"
+	CodeNotFound           = "NOT_FOUND"
+	CodeConflict           = "CONFLICT"
+	CodePayloadTooLarge    = "PAYLOAD_TOO_LARGE"
+	CodeRateLimited        = "RATE_LIMITED"
+	CodeInternal           = "INTERNAL_ERROR"
+	CodeNotImplemented     = "NOT_IMPLEMENTED"
+	CodeUpstream           = "UPSTREAM_ERROR"
+	CodeUnavailable        = "UNAVAILABLE"
+	CodeTimeout            = "TIMEOUT"
+	CodeUnsupportedVersion = "UNSUPPORTED_VERSION"
+)
+
+var statusCodes = map[int]string{
+	http.StatusBadRequest:            CodeBadRequest,
+	http.StatusUnauthorized:          CodeUnauthorized,
+	http.StatusForbidden:             CodeForbidden,
+	http.StatusNotFound:              CodeNotFound,
+	http.StatusConflict:              CodeConflict,
+	http.StatusRequestEntityTooLarge: CodePayloadTooLarge,
+	http.StatusTooManyRequests:       CodeRateLimited,
+	http.StatusInternalServerError:   CodeInternal,
+	http.StatusNotImplemented:        CodeNotImplemented,
+	http.StatusBadGateway:            CodeUpstream,
+	http.StatusServiceUnavailable:    CodeUnavailable,
+	http.StatusGatewayTimeout:        CodeTimeout,
+}
+
+// Envelope is the body of every version 1 JSON response
+type Envelope struct {
+	Data  interface{}            `json:"data"`
+	Meta  map[string]interface{} `json:"meta"`
+	Error *Error                 `json:"error"`
+}
+
+// Error describes a failed version 1 request
+type Error struct {
+	Code    string                 `json:"code"`
+	Message string                 `json:"message"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// Middleware serves the routes below it in version pinned, or for an empty pinned in the
+// version the API-Version header asks for (legacy bodies without one). Unsupported versions
+// are rejected with a 400.
+func Middleware(pinned string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Responses differ by the header, so shared caches must keep them apart
+		c.Writer.Header().Add("Vary", Header)
+
+		version := c.GetHeader(Header)
+		if version != "" && (!supported(version) || pinned != "" && version != pinned) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error":     "Unsupported API version, expected one of: " + strings.Join(Supported, ", "),
+				"code":      CodeUnsupportedVersion,
+				"supported": Supported,
+			})
+			return
+		}
+		if pinned != "" {
+			version = pinned
+		}
+		if version == "" {
+			c.Next()
+			return
+		}
+
+		c.Header(Header, version)
+		writer := &envelopeWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		if writer.body.Len() == 0 {
+			return
+		}
+		body, err := wrap(writer.Status(), version, writer.body.Bytes())
+		if err != nil {
+			// Not JSON after all: send it as the handler wrote it
+			body = writer.body.Bytes()
+		}
+		c.Writer.Header().Del("Content-Length")
+		c.Writer.Write(body)
+	}
+}
+
+// wrap moves a legacy JSON body into the envelope. Fields next to "data" (total, page, ...)
+// become meta; the "error" message of failures and the fields next to it become the error.
+func wrap(status int, version string, legacy []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(legacy))
+	decoder.UseNumber()
+	var payload interface{}
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, err
+	}
+
+	envelope := Envelope{Meta: map[string]interface{}{"api_version": version}}
+	fields, isObject := payload.(map[string]interface{})
+	switch {
+	case status >= http.StatusBadRequest:
+		envelope.Error = &Error{Code: statusCodes[status], Message: http.StatusText(status)}
+		switch {
+		case envelope.Error.Code != "":
+		case status >= http.StatusInternalServerError:
+			envelope.Error.Code = CodeInternal
+		default:
+			envelope.Error.Code = CodeBadRequest
+		}
+		if !isObject {
+			break
+		}
+		if message, ok := fields["error"].(string); ok {
+			envelope.Error.Message = message
+			delete(fields, "error")
+		}
+		if code, ok := fields["code"].(string); ok {
+			envelope.Error.Code = code
+			delete(fields, "code")
+		}
+		if len(fields) > 0 {
+			envelope.Error.Details = fields
+		}
+	case isObject && hasData(fields):
+		envelope.Data = fields["data"]
+		for key, value := range fields {
+			if key != "data" {
+				envelope.Meta[key] = value
+			}
+		}
+	default:
+		envelope.Data = payload
+	}
+	return json.Marshal(envelope)
+}
+
+// hasData reports whether a legacy body is a {data, ...} response
+func hasData(fields map[string]interface{}) bool {
+	_, ok := fields["data"]
+	return ok
+}
+
+// supported reports whether clients may request version
+func supported(version string) bool {
+	for _, candidate := range Supported {
+		if version == candidate {
+			return true
+		}
+	}
+	return false
+}
+
+// envelopeWriter holds JSON bodies back until they can be wrapped; anything else (images,
+// CSV exports, event streams) is written through
+type envelopeWriter struct {
+	gin.ResponseWriter
+	body    bytes.Buffer
+	decided bool
+	buffer  bool
+}
+
+// buffering decides on the first write, once the handler has set the content type
+func (w *envelopeWriter) buffering() bool {
+	if !w.decided {
+		w.decided = true
+		w.buffer = strings.HasPrefix(w.Header().Get("Content-Type"), "application/json")
+	}
+	return w.buffer
+}
+
+func (w *envelopeWriter) Write(data []byte) (int, error) {
+	if !w.buffering() {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.body.Write(data)
+}
+
+func (w *envelopeWriter) WriteString(s string) (int, error) {
+	if !w.buffering() {
+		return w.ResponseWriter.WriteString(s)
+	}
+	return w.body.WriteString(s)
+}
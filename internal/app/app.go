@@ -0,0 +1,1347 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"nadmon-backend/internal/accesslog"
+	"nadmon-backend/internal/apikeys"
+	"nadmon-backend/internal/audit"
+	"nadmon-backend/internal/auth"
+	"nadmon-backend/internal/cache"
+	"nadmon-backend/internal/chaos"
+	"nadmon-backend/internal/clickhouse"
+	"nadmon-backend/internal/compress"
+	"nadmon-backend/internal/config"
+	"nadmon-backend/internal/database"
+	"nadmon-backend/internal/database/envio"
+	"nadmon-backend/internal/events"
+	"nadmon-backend/internal/fanout"
+	"nadmon-backend/internal/gamedata"
+	"nadmon-backend/internal/graphql"
+	"nadmon-backend/internal/handlers"
+	"nadmon-backend/internal/indexer"
+	"nadmon-backend/internal/logging"
+	"nadmon-backend/internal/metrics"
+	"nadmon-backend/internal/names"
+	"nadmon-backend/internal/origins"
+	"nadmon-backend/internal/ratelimit"
+	"nadmon-backend/internal/replay"
+	"nadmon-backend/internal/repository"
+	"nadmon-backend/internal/slowlog"
+	"nadmon-backend/internal/status"
+	"nadmon-backend/internal/webhooks"
+	"nadmon-backend/internal/websocket"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// Notifier pushes real-time messages to connected players
+type Notifier interface {
+	NotifyUser(address string, messageType string, data interface{})
+	NotifyUserOnChain(chain, address string, messageType string, data interface{})
+	BroadcastToAll(messageType string, data interface{})
+	BroadcastOnChain(chain, messageType string, data interface{})
+}
+
+// App is the composition root: it builds every subsystem from config and owns their lifecycle
+type App struct {
+	Config *config.Config
+
+	DB          *database.EnvioDB
+	Repo        repository.Store
+	WS          *websocket.Manager
+	Notifier    Notifier
+	Shadow      *repository.ShadowStore
+	Status      *status.Monitor
+	Events      *events.Pipeline
+	Indexer     *indexer.Monitor
+	Cache       *repository.CachedStore
+	MicroCache  *repository.MicroCachedStore
+	GameStats   *repository.StatsRefresher
+	Auth        *auth.Service
+	Profiles    *repository.ProfileRepository
+	Teams       *repository.TeamRepository
+	Favorites   *repository.FavoriteRepository
+	Trades      *repository.TradeRepository
+	Webhooks    *repository.WebhookRepository
+	APIKeys     *repository.APIKeyRepository
+	Audit       *repository.AuditRepository
+	Rarity      *repository.RarityRepository
+	Standings   *repository.LeaderboardRepository
+	PlayerStats *repository.PlayerStatsRepository
+	Battles     *repository.BattleRepository
+	Names       *names.Service
+
+	Router *gin.Engine
+
+	replayPlayer   *replay.Player
+	replayRecorder *replay.Recorder
+
+	// extraCollections holds the stores of non-default collections by name
+	extraCollections map[string]repository.Store
+
+	// extraChains holds the deployments on other chains by chain ID
+	extraChains map[string]*chain
+
+	// limiter backs the /api rate limits; nil when rate limiting is off
+	limiter ratelimit.Limiter
+
+	// apiKeys authenticates partner API keys; nil without the database
+	apiKeys *apikeys.Service
+
+	// auditLog records reads of player data; nil when the audit log is off
+	auditLog *audit.Logger
+
+	// origins is the browser allow-list shared by CORS and the WebSocket upgrader
+	origins *origins.List
+
+	// chaos holds the faults to inject; zero value when fault injection is off
+	chaos chaos.Config
+
+	// slowLog records slow queries and requests over their latency budget
+	slowLog *slowlog.Log
+	budgets slowlog.Budgets
+
+	// redis is the cache's Redis connection, checked by /readyz; nil without the cache
+	redis *cache.Redis
+
+	// eventsLeader decides which replica pushes indexer events; nil without fan-out
+	eventsLeader *fanout.Leader
+
+	// webhookDispatcher delivers indexer events to registered webhooks; nil without them
+	webhookDispatcher *webhooks.Dispatcher
+
+	// closers run in reverse order on shutdown
+	closers []func() error
+}
+
+// New wires all subsystems together according to cfg
+func New(cfg *config.Config) (*App, error) {
+	a := &App{Config: cfg, origins: origins.New(cfg.AllowedOrigins)}
+
+	a.provideChaos()
+	if err := a.provideGameData(); err != nil {
+		return nil, err
+	}
+	if err := a.provideSlowLog(); err != nil {
+		return nil, err
+	}
+	a.provideAuth()
+	if err := a.provideData(); err != nil {
+		a.Close()
+		return nil, err
+	}
+	a.provideCache()
+	a.provideMicroCache()
+	a.provideDedup()
+	a.provideGameStats()
+	a.provideWebSocket()
+	a.provideWebhooks()
+	if err := a.provideEvents(); err != nil {
+		a.Close()
+		return nil, err
+	}
+	if err := a.provideChainEvents(); err != nil {
+		a.Close()
+		return nil, err
+	}
+	a.provideIndexer()
+	a.provideRarity()
+	a.provideStandings()
+	a.provideNames()
+	a.provideStatus()
+	a.provideRateLimit()
+	a.provideAPIKeys()
+	a.provideAudit()
+	if err := a.provideRouter(); err != nil {
+		a.Close()
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// provideGameData loads the game data file; an invalid file stops startup rather than
+// serving stats derived with the wrong formulas
+func (a *App) provideGameData() error {
+	data, err := gamedata.Load(a.Config.GameDataFile)
+	if err != nil {
+		return err
+	}
+	gamedata.Set(data)
+	log.Printf("🎮 Game data version %s loaded from %s", data.Version, data.Source)
+	return nil
+}
+
+// provideAuth sets up Sign-In With Ethereum and JWT issuing
+func (a *App) provideAuth() {
+	secret := []byte(a.Config.AuthJWTSecret)
+	if len(secret) == 0 {
+		// Tokens signed with a random secret stop working on restart and across replicas
+		log.Printf("Warning: AUTH_JWT_SECRET is not set, using a random secret")
+		secret = auth.RandomSecret()
+	}
+	a.Auth = auth.NewService(secret, a.Config.SIWEDomain, a.Config.AuthTokenTTL, a.Config.WSTokenTTL)
+}
+
+// provideData sets up either the live database and repository or the replay bundle
+func (a *App) provideData() error {
+	// In replay mode the API is served entirely from a recorded bundle, without a database
+	if a.Config.DataMode == replay.ModeReplay {
+		bundle, err := replay.LoadBundle(a.Config.ReplayBundlePath)
+		if err != nil {
+			return err
+		}
+		a.replayPlayer = replay.NewPlayer(bundle, "/healthz", "/readyz", "/metrics", "/docs", "/api/openapi.json")
+		log.Printf("📼 Replay mode: serving %d recorded responses from %s", len(bundle.Entries), a.Config.ReplayBundlePath)
+		return nil
+	}
+
+	if err := a.provideStore(); err != nil {
+		return err
+	}
+
+	if a.Config.DataMode == replay.ModeRecord {
+		a.replayRecorder = replay.NewRecorder(a.Config.ReplayBundlePath)
+		a.closers = append(a.closers, a.replayRecorder.Save)
+		log.Printf("⏺️ Record mode: responses will be saved to %s on shutdown", a.Config.ReplayBundlePath)
+	}
+
+	return nil
+}
+
+// provideSlowLog sets up the log of slow queries and requests over their latency budget
+func (a *App) provideSlowLog() error {
+	routes, err := slowlog.ParseBudgets(a.Config.LatencyBudgets)
+	if err != nil {
+		return err
+	}
+	a.budgets = slowlog.Budgets{Default: a.Config.LatencyBudget, Routes: routes}
+	a.slowLog = slowlog.New(a.Config.SlowQueryThreshold, a.Config.SlowOperationsMax, a.Config.SlowOperationsWindow)
+	return nil
+}
+
+// provideChaos enables fault injection from config, refusing to do so in production
+func (a *App) provideChaos() {
+	if !a.Config.ChaosEnabled {
+		return
+	}
+	if a.Config.IsProduction() {
+		log.Printf("Warning: CHAOS_ENABLED is ignored when APP_ENV=production")
+		return
+	}
+
+	a.chaos = chaos.Config{
+		DBLatency:     a.Config.ChaosDBLatency,
+		DBLatencyRate: a.Config.ChaosDBLatencyRate,
+		ErrorRate:     a.Config.ChaosErrorRate,
+		WSDropRate:    a.Config.ChaosWSDropRate,
+	}
+	if a.chaos.Enabled() {
+		a.chaos.LogEnabled()
+	}
+}
+
+// poolConfig returns the Envio connection pool settings from the config
+func (a *App) poolConfig() database.PoolConfig {
+	return database.PoolConfig{
+		MaxOpenConns:     a.Config.DBMaxOpenConns,
+		MaxIdleConns:     a.Config.DBMaxIdleConns,
+		ConnMaxLifetime:  a.Config.DBConnMaxLifetime,
+		StatementTimeout: a.Config.DBStatementTimeout,
+	}
+}
+
+// provideStore connects the storage backend selected by STORAGE_BACKEND
+func (a *App) provideStore() error {
+	switch a.Config.StorageBackend {
+	case repository.BackendEnvioPostgres, "":
+		// Connect to Envio database
+		envioDB, err := database.ConnectToEnvioWithPool(a.Config.DatabaseURL, a.poolConfig())
+		if err != nil {
+			return err
+		}
+		a.DB = envioDB
+		a.closers = append(a.closers, envioDB.Close)
+
+		// Test database connection
+		if err := envioDB.TestConnection(); err != nil {
+			return err
+		}
+		a.provideBreaker(envioDB)
+
+		// Create indexes for better performance
+		if err := envioDB.CreateIndexes(); err != nil {
+			log.Printf("Warning: Failed to create some indexes: %v", err)
+		}
+
+		a.provideProfiles(envioDB)
+
+		if a.Config.TimescaleEnabled {
+			a.provideTimescale(envioDB)
+		}
+
+		if a.Config.CurrentStateEnabled {
+			a.provideCurrentState(envioDB)
+		}
+
+		// Initialize repository layer
+		primary := repository.NewNadmonRepositoryWithConn(envioDB, a.conn(envioDB))
+		if len(a.Config.ExcludedAddresses) > 0 {
+			primary.SetExcludedAddresses(a.Config.ExcludedAddresses)
+			log.Printf("🚫 Excluding %d addresses from leaderboards and stats", len(a.Config.ExcludedAddresses))
+		}
+		a.Repo = primary
+		if a.Config.ReplicaDatabaseURL != "" {
+			if replica, err := a.provideReplica(envioDB); err != nil {
+				log.Printf("Warning: read replica disabled, reading from the primary: %v", err)
+			} else {
+				a.Repo = repository.WithReplica(primary, replica)
+			}
+		}
+		if a.Config.MetricsEnabled {
+			a.Repo = repository.NewInstrumentedStore(a.Repo)
+		}
+
+		if err := a.provideCollections(envioDB); err != nil {
+			return err
+		}
+		if err := a.provideChains(); err != nil {
+			return err
+		}
+
+		// Candidate backends validated by shadow reads before they serve traffic
+		candidates := make(map[string]repository.Store)
+
+		if a.Config.ClickHouseURL != "" {
+			analytics, err := a.provideClickHouse(envioDB)
+			if err != nil {
+				log.Printf("Warning: ClickHouse analytics disabled: %v", err)
+			} else {
+				candidates[ShadowClickHouse] = repository.WithAnalytics(primary, analytics)
+			}
+		}
+
+		return a.provideShadow(candidates)
+
+	case repository.BackendEnvioGraphQL:
+		return a.provideGraphQL()
+
+	default:
+		return fmt.Errorf("unknown storage backend %q", a.Config.StorageBackend)
+	}
+}
+
+// provideReplica connects to the read replica of the Envio database, which serves the
+// leaderboards, analytics, search and exports. Its backend-owned tables are replicated from
+// the primary, so it reads them once the primary has set them up.
+func (a *App) provideReplica(envioDB *database.EnvioDB) (*repository.NadmonRepository, error) {
+	replicaDB, err := database.ConnectToEnvioWithPool(a.Config.ReplicaDatabaseURL, a.poolConfig())
+	if err != nil {
+		return nil, err
+	}
+	a.closers = append(a.closers, replicaDB.Close)
+	if err := replicaDB.TestConnection(); err != nil {
+		return nil, err
+	}
+	a.provideBreaker(replicaDB)
+	replicaDB.Timescale = envioDB.Timescale
+	replicaDB.CurrentState = envioDB.CurrentState
+
+	replica := repository.NewNadmonRepositoryWithConn(replicaDB, a.conn(replicaDB))
+	replica.SetExcludedAddresses(a.Config.ExcludedAddresses)
+	log.Printf("📚 Leaderboards, analytics, search and exports read from the read replica")
+	return replica, nil
+}
+
+// provideGraphQL serves data mirrored from the GraphQL endpoint of a hosted Envio indexer.
+// There is no database then, so the backend-owned schema (profiles, teams, webhooks, rarity
+// and player ranks), the event pipeline and the indexer monitor are off.
+func (a *App) provideGraphQL() error {
+	if a.Config.EnvioGraphQLURL == "" {
+		return fmt.Errorf("ENVIO_GRAPHQL_URL is required with STORAGE_BACKEND=%s", repository.BackendEnvioGraphQL)
+	}
+	if len(a.Config.Collections) > 0 {
+		log.Printf("Warning: COLLECTIONS is ignored with STORAGE_BACKEND=%s", repository.BackendEnvioGraphQL)
+	}
+	if len(a.Config.ChainDatabases) > 0 {
+		log.Printf("Warning: CHAIN_DATABASE_URLS is ignored with STORAGE_BACKEND=%s", repository.BackendEnvioGraphQL)
+	}
+
+	store := graphql.NewStore(graphql.NewClient(a.Config.EnvioGraphQLURL, a.Config.EnvioGraphQLAdminSecret))
+	store.SetExcludedAddresses(a.Config.ExcludedAddresses)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	mirrored, err := store.Sync(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to mirror Envio GraphQL endpoint: %w", err)
+	}
+	log.Printf("🛰️ Mirrored %d Envio events from %s, syncing every %s", mirrored, a.Config.EnvioGraphQLURL, a.Config.EnvioGraphQLSyncInterval)
+
+	stop := make(chan struct{})
+	a.closers = append(a.closers, func() error {
+		close(stop)
+		return nil
+	})
+	go store.Run(a.Config.EnvioGraphQLSyncInterval, stop)
+
+	a.Repo = store
+	if a.Config.MetricsEnabled {
+		a.Repo = repository.NewInstrumentedStore(store)
+	}
+	return nil
+}
+
+// provideBreaker guards the Envio connection with a circuit breaker fed by periodic pings
+func (a *App) provideBreaker(envioDB *database.EnvioDB) {
+	envioDB.Breaker = database.NewBreaker(a.Config.DBBreakerThreshold, a.Config.DBBreakerCooldown)
+
+	stop := make(chan struct{})
+	a.closers = append(a.closers, func() error {
+		close(stop)
+		return nil
+	})
+	go envioDB.Monitor(a.Config.DBHealthCheckInterval, stop)
+
+	log.Printf("🔌 Database circuit breaker enabled (opens after %d connection failures, %s cooldown)",
+		a.Config.DBBreakerThreshold, a.Config.DBBreakerCooldown)
+}
+
+// conn returns the connection repositories query through: the pool, with injected latency
+// in chaos mode, timed for the slow query log, behind the circuit breaker
+func (a *App) conn(envioDB *database.EnvioDB) envio.DBTX {
+	var conn database.Conn = envioDB.DB
+	if a.chaos.Enabled() {
+		conn = chaos.WrapDB(envioDB.DB, a.chaos)
+	}
+	return envioDB.Guard(a.slowLog.Wrap(conn))
+}
+
+// provideCollections creates a repository for every additional collection in COLLECTIONS
+func (a *App) provideCollections(envioDB *database.EnvioDB) error {
+	prefixes, err := repository.ParseCollections(a.Config.Collections)
+	if err != nil {
+		return err
+	}
+
+	a.extraCollections = make(map[string]repository.Store, len(prefixes))
+	for name, prefix := range prefixes {
+		if name == strings.ToLower(a.Config.DefaultCollection) {
+			return fmt.Errorf("collection %s is the default collection and is always served", name)
+		}
+
+		repo := repository.NewCollectionRepository(envioDB, a.conn(envioDB), prefix)
+		repo.SetExcludedAddresses(a.Config.ExcludedAddresses)
+
+		a.extraCollections[name] = repo
+		if a.Config.MetricsEnabled {
+			a.extraCollections[name] = repository.NewInstrumentedStore(repo)
+		}
+		log.Printf("🗃️ Collection %s reads %s_* tables", name, prefix)
+	}
+	return nil
+}
+
+// collections returns every collection's store by name, including the default collection
+func (a *App) collections() map[string]repository.Store {
+	collections := map[string]repository.Store{strings.ToLower(a.Config.DefaultCollection): a.Repo}
+	for name, store := range a.extraCollections {
+		collections[name] = store
+	}
+	return collections
+}
+
+// provideTimescale sets up continuous aggregates and keeps the analytics hypertable in sync
+func (a *App) provideTimescale(envioDB *database.EnvioDB) {
+	ok, err := envioDB.SetupTimescale()
+	if err != nil {
+		log.Printf("Warning: TimescaleDB setup failed, using date_trunc fallback: %v", err)
+		return
+	}
+	if !ok {
+		return
+	}
+
+	stop := make(chan struct{})
+	a.closers = append(a.closers, func() error {
+		close(stop)
+		return nil
+	})
+
+	go func() {
+		ticker := time.NewTicker(a.Config.TimescaleSyncInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := envioDB.SyncTimescaleEvents(); err != nil {
+					log.Printf("Warning: %v", err)
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// provideProfiles sets up the backend-owned schema holding players' display profiles, teams,
+// favorites, trade offers, webhooks, NFT rarity ranks, the collector leaderboard snapshot and
+// per-player aggregates
+func (a *App) provideProfiles(envioDB *database.EnvioDB) {
+	if err := envioDB.SetupAppSchema(); err != nil {
+		log.Printf("Warning: display profiles, teams, favorites, trades, webhooks, API keys, audit log, rarity ranks, player ranks, player stats and battles disabled: %v", err)
+		return
+	}
+	a.Profiles = repository.NewProfileRepository(envioDB.DB)
+	a.Teams = repository.NewTeamRepository(envioDB.DB)
+	a.Favorites = repository.NewFavoriteRepository(envioDB.DB)
+	a.Trades = repository.NewTradeRepository(envioDB.DB, a.Config.TradeOfferTTL)
+	a.Webhooks = repository.NewWebhookRepository(envioDB.DB)
+	a.APIKeys = repository.NewAPIKeyRepository(envioDB.DB)
+	if a.Config.AuditLogEnabled {
+		a.Audit = repository.NewAuditRepository(envioDB.DB)
+	}
+	a.Battles = repository.NewBattleRepository(envioDB.DB)
+	if a.Config.RarityRefreshInterval > 0 {
+		a.Rarity = repository.NewRarityRepository(envioDB.DB)
+	}
+	if a.Config.LeaderboardSnapshotInterval > 0 {
+		a.Standings = repository.NewLeaderboardRepository(envioDB.DB, a.Config.ExcludedAddresses)
+	}
+	// The event pipeline keeps the aggregates up to date, so they need it running
+	if a.Config.PlayerStatsEnabled && a.Config.EventsMode != EventsOff {
+		a.PlayerStats = repository.NewPlayerStatsRepository(envioDB.DB)
+	}
+}
+
+// provideRarity recomputes the NFT rarity ranks right away and then periodically
+func (a *App) provideRarity() {
+	if a.Rarity == nil {
+		return
+	}
+
+	refresh := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), a.Config.RarityRefreshInterval)
+		defer cancel()
+		ranked, err := a.Rarity.Refresh(ctx)
+		if err != nil {
+			log.Printf("Warning: %v", err)
+			return
+		}
+		log.Printf("💎 Ranked %d NFTs by rarity", ranked)
+	}
+
+	stop := make(chan struct{})
+	a.closers = append(a.closers, func() error {
+		close(stop)
+		return nil
+	})
+
+	go func() {
+		refresh()
+		ticker := time.NewTicker(a.Config.RarityRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				refresh()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// provideStandings snapshots the collector leaderboard right away and then periodically
+func (a *App) provideStandings() {
+	if a.Standings == nil {
+		return
+	}
+
+	snapshot := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), a.Config.LeaderboardSnapshotInterval)
+		defer cancel()
+		ranked, err := a.Standings.Refresh(ctx)
+		if err != nil {
+			log.Printf("Warning: %v", err)
+			return
+		}
+		log.Printf("🏆 Snapshotted the leaderboard standings of %d collectors", ranked)
+	}
+
+	stop := make(chan struct{})
+	a.closers = append(a.closers, func() error {
+		close(stop)
+		return nil
+	})
+
+	go func() {
+		snapshot()
+		ticker := time.NewTicker(a.Config.LeaderboardSnapshotInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				snapshot()
+			case <-stop:
+				return
+			}
+		}
+	}()
+}
+
+// provideCurrentState backfills the current-state table and keeps it in sync with the Envio tables
+func (a *App) provideCurrentState(envioDB *database.EnvioDB) {
+	if err := envioDB.SetupCurrentState(); err != nil {
+		log.Printf("Warning: current-state table setup failed, reading through CTEs: %v", err)
+		return
+	}
+
+	stop := make(chan struct{})
+	a.closers = append(a.closers, func() error {
+		close(stop)
+		return nil
+	})
+
+	go func() {
+		ticker := time.NewTicker(a.Config.CurrentStateSyncInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := envioDB.SyncCurrentState(); err != nil {
+					log.Printf("Warning: %v", err)
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	log.Printf("🗂️ Current-state table syncing every %s", a.Config.CurrentStateSyncInterval)
+}
+
+// Shadow candidates accepted by SHADOW_BACKEND
+const (
+	ShadowClickHouse = "clickhouse"
+)
+
+// provideShadow decides how candidate backends are used. The candidate named by
+// SHADOW_BACKEND only receives sampled shadow reads; without shadowing, an available
+// candidate serves traffic directly.
+func (a *App) provideShadow(candidates map[string]repository.Store) error {
+	if a.Config.ShadowBackend == "" {
+		if candidate, ok := candidates[ShadowClickHouse]; ok {
+			a.Repo = candidate
+		}
+		return nil
+	}
+
+	candidate, ok := candidates[a.Config.ShadowBackend]
+	if !ok {
+		return fmt.Errorf("shadow backend %q is not configured", a.Config.ShadowBackend)
+	}
+
+	shadowStore := repository.NewShadowStore(a.Repo, candidate, a.Config.ShadowSampleRate)
+	a.Repo = shadowStore
+	a.Shadow = shadowStore
+	log.Printf("👥 Shadow reads enabled: %.1f%% of calls compared against %s", a.Config.ShadowSampleRate*100, a.Config.ShadowBackend)
+	return nil
+}
+
+// provideClickHouse mirrors Envio events into ClickHouse and returns the analytics store reading from it
+func (a *App) provideClickHouse(envioDB *database.EnvioDB) (repository.AnalyticsStore, error) {
+	client := clickhouse.NewClient(a.Config.ClickHouseURL, a.Config.ClickHouseDatabase, a.Config.ClickHouseUser, a.Config.ClickHousePassword)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx); err != nil {
+		return nil, err
+	}
+
+	sink := clickhouse.NewSink(client, envioDB.DB)
+	if err := sink.Setup(); err != nil {
+		return nil, err
+	}
+
+	stop := make(chan struct{})
+	a.closers = append(a.closers, func() error {
+		close(stop)
+		return nil
+	})
+	go sink.Run(a.Config.ClickHouseSyncInterval, stop)
+
+	log.Printf("📈 ClickHouse analytics sink enabled (%s)", a.Config.ClickHouseURL)
+	return clickhouse.NewAnalytics(client, a.Config.ExcludedAddresses), nil
+}
+
+// provideWebSocket starts the WebSocket manager for real-time updates
+func (a *App) provideWebSocket() {
+	a.WS = websocket.NewManager(a.origins)
+	a.WS.SetSendBuffer(a.Config.WSSendBuffer)
+	a.WS.SetReplay(a.Config.WSReplayBuffer, a.Config.WSReplayWindow)
+	for messageType, payload := range events.Payloads {
+		a.WS.RegisterPayload(messageType, payload)
+	}
+	a.Notifier = a.WS
+	if a.chaos.WSDropRate > 0 {
+		a.WS.SetDropFunc(a.chaos.ShouldDropMessage)
+	}
+	if a.Config.MetricsEnabled {
+		metrics.RegisterWebSocketGauge(a.WS.ConnectedCount)
+	}
+	go a.WS.Start()
+
+	a.provideFanout()
+}
+
+const (
+	FanoutLocal = "local"
+	FanoutRedis = "redis"
+)
+
+// eventsLeaseTTL is how long the replica pushing indexer events holds its lease; after a
+// crash another replica takes over within this time
+const eventsLeaseTTL = 15 * time.Second
+
+// provideFanout publishes notifications through Redis so every replica delivers them to the
+// clients it holds, and elects one replica to push indexer events
+func (a *App) provideFanout() {
+	switch a.Config.WSFanout {
+	case FanoutRedis:
+		if a.Config.RedisURL == "" {
+			log.Printf("Warning: WS_FANOUT=redis needs REDIS_URL, notifying local clients only")
+			return
+		}
+	case FanoutLocal, "":
+		return
+	default:
+		log.Printf("Warning: unknown WebSocket fan-out %q, notifying local clients only", a.Config.WSFanout)
+		return
+	}
+
+	fan, err := fanout.NewRedis(a.Config.RedisURL, a.Config.WSFanoutChannel, a.WS)
+	if err != nil {
+		log.Printf("Warning: WebSocket fan-out disabled: %v", err)
+		return
+	}
+	a.Notifier = fan
+	a.eventsLeader = fan.Leader(a.Config.WSFanoutChannel+":events", eventsLeaseTTL)
+
+	// Closers run in reverse, so both loops stop (releasing the lease) before Redis closes
+	stop := make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		a.eventsLeader.Run(stop)
+	}()
+	go func() {
+		defer wg.Done()
+		fan.Run(stop)
+	}()
+	a.closers = append(a.closers, fan.Close, func() error {
+		close(stop)
+		wg.Wait()
+		return nil
+	})
+
+	log.Printf("📣 WebSocket fan-out through Redis channel %s", a.Config.WSFanoutChannel)
+}
+
+// provideCache wraps the store with the Redis cache when REDIS_URL is set
+func (a *App) provideCache() {
+	if a.Config.RedisURL == "" || a.Repo == nil {
+		return
+	}
+
+	redis, err := cache.NewRedis(a.Config.RedisURL)
+	if err != nil {
+		log.Printf("Warning: Redis cache disabled: %v", err)
+		return
+	}
+	a.closers = append(a.closers, redis.Close)
+	a.redis = redis
+
+	a.Cache = repository.NewCachedStore(a.Repo, redis, repository.CacheTTLs{
+		Player:    a.Config.CacheTTLPlayer,
+		NFT:       a.Config.CacheTTLNFT,
+		Aggregate: a.Config.CacheTTLAggregate,
+	})
+	a.Repo = a.Cache
+	log.Printf("⚡ Redis cache enabled")
+}
+
+// provideMicroCache puts the in-process micro-cache in front of every other store layer
+func (a *App) provideMicroCache() {
+	if !a.Config.MicroCacheEnabled || a.Repo == nil {
+		return
+	}
+
+	a.MicroCache = repository.NewMicroCachedStore(a.Repo, a.Config.MicroCacheSize, a.Config.MicroCacheTTL)
+	a.Repo = a.MicroCache
+	log.Printf("⚡ Micro-cache enabled (%d NFTs, %s)", a.Config.MicroCacheSize, a.Config.MicroCacheTTL)
+}
+
+// provideDedup collapses concurrent identical reads in front of every other store layer, so
+// they also share Redis and micro-cache misses
+func (a *App) provideDedup() {
+	if !a.Config.RequestDedupEnabled || a.Repo == nil {
+		return
+	}
+
+	a.Repo = repository.NewDedupedStore(a.Repo)
+	log.Printf("⚡ Request deduplication enabled")
+}
+
+// provideGameStats serves the game stats from a snapshot recomputed right away and then
+// periodically, in front of every other store layer
+func (a *App) provideGameStats() {
+	if a.Config.GameStatsRefreshInterval <= 0 || a.Repo == nil {
+		return
+	}
+
+	a.GameStats = repository.NewStatsRefresher(a.Repo)
+	a.Repo = a.GameStats
+
+	refresh := func() {
+		ctx, cancel := context.WithTimeout(context.Background(), a.Config.GameStatsRefreshInterval)
+		defer cancel()
+		if _, err := a.GameStats.Refresh(ctx); err != nil {
+			log.Printf("Warning: failed to refresh game stats: %v", err)
+		}
+	}
+
+	stop := make(chan struct{})
+	a.closers = append(a.closers, func() error {
+		close(stop)
+		return nil
+	})
+
+	go func() {
+		refresh()
+		ticker := time.NewTicker(a.Config.GameStatsRefreshInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				refresh()
+			case <-stop:
+				return
+			}
+		}
+	}()
+	log.Printf("📊 Game stats refreshed every %s", a.Config.GameStatsRefreshInterval)
+}
+
+// provideWebhooks starts the workers delivering indexer events to registered webhooks; the
+// event pipeline feeds them
+func (a *App) provideWebhooks() {
+	if a.Webhooks == nil {
+		return
+	}
+	if a.Config.EventsMode == EventsOff {
+		log.Printf("Warning: EVENTS_MODE=off, registered webhooks receive no deliveries")
+	}
+
+	dispatcher := webhooks.NewDispatcher(a.Webhooks, webhooks.Config{
+		Workers:      a.Config.WebhookWorkers,
+		MaxAttempts:  a.Config.WebhookMaxAttempts,
+		RetryBackoff: a.Config.WebhookRetryBackoff,
+		Timeout:      a.Config.WebhookTimeout,
+		LogRetention: a.Config.WebhookLogRetention,
+	})
+	if err := dispatcher.Reload(context.Background()); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+	// With fan-out every replica sees each event, but only the lease holder delivers it
+	if a.eventsLeader != nil {
+		dispatcher.SetLeader(a.eventsLeader.IsLeader)
+	}
+	a.webhookDispatcher = dispatcher
+
+	stop := make(chan struct{})
+	a.closers = append(a.closers, func() error {
+		close(stop)
+		return nil
+	})
+	go dispatcher.Run(stop)
+
+	log.Printf("🪝 Webhook delivery started (%d workers, %d attempts)", a.Config.WebhookWorkers, a.Config.WebhookMaxAttempts)
+}
+
+// reloadWebhooks makes the dispatcher pick up a registered or deleted webhook right away
+func (a *App) reloadWebhooks() {
+	if a.webhookDispatcher == nil {
+		return
+	}
+	if err := a.webhookDispatcher.Reload(context.Background()); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
+
+// Event pipeline modes accepted by EVENTS_MODE
+const (
+	EventsPoll   = "poll"
+	EventsNotify = "notify"
+	EventsOff    = "off"
+)
+
+// provideEvents pushes new indexer rows to the affected players over WebSocket
+func (a *App) provideEvents() error {
+	if a.DB == nil || a.Config.EventsMode == EventsOff {
+		return nil
+	}
+
+	// Every replica polls for its own cache and current-state upkeep, but with fan-out only
+	// the lease holder pushes notifications, so each player gets an event once
+	pipeline := events.NewPipeline(a.DB.DB, a.chainNotifier(a.Config.ChainID))
+	if err := pipeline.Init(); err != nil {
+		return err
+	}
+	a.Events = pipeline
+	pipeline.SetNadmonLoader(a.Repo)
+
+	// Apply new rows to the current-state table before anything reads it back
+	if a.DB.CurrentState {
+		pipeline.Subscribe(func(event events.Event) {
+			if err := a.DB.SyncCurrentStateThrough(event.OccurredAt); err != nil {
+				log.Printf("Warning: %v", err)
+			}
+		})
+	}
+
+	a.providePlayerStats(pipeline)
+
+	// New rows make cached results stale
+	if a.Cache != nil {
+		pipeline.Subscribe(func(event events.Event) {
+			a.Cache.Invalidate(event.Addresses, event.TokenIDs)
+		})
+	}
+	if a.MicroCache != nil {
+		pipeline.Subscribe(func(event events.Event) {
+			a.MicroCache.Invalidate(event.TokenIDs)
+		})
+	}
+
+	// Subscribed after the caches, so receivers reading back get fresh data
+	if a.webhookDispatcher != nil {
+		pipeline.Subscribe(a.webhookDispatcher.Handle)
+	}
+
+	return a.runPipeline(pipeline, a.DB, a.Config.DatabaseURL)
+}
+
+// providePlayerStats backfills the per-player aggregates and has pipeline recompute the
+// players its events touch. The backfill runs after the pipeline's cursors are positioned,
+// so rows indexed meanwhile are applied again rather than missed.
+func (a *App) providePlayerStats(pipeline *events.Pipeline) {
+	if a.PlayerStats == nil {
+		return
+	}
+
+	players, err := a.PlayerStats.Backfill(context.Background())
+	if err != nil {
+		log.Printf("Warning: player stats computed per request: %v", err)
+		a.PlayerStats = nil
+		return
+	}
+	log.Printf("📈 Aggregated the stats of %d players", players)
+
+	pipeline.Subscribe(func(event events.Event) {
+		if err := a.PlayerStats.Refresh(context.Background(), event.Addresses); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	})
+}
+
+// runPipeline polls envioDB for pipeline until shutdown; in notify mode it is also woken by
+// LISTEN/NOTIFY on databaseURL
+func (a *App) runPipeline(pipeline *events.Pipeline, envioDB *database.EnvioDB, databaseURL string) error {
+	var wake <-chan struct{}
+	switch a.Config.EventsMode {
+	case EventsNotify:
+		if err := events.InstallTriggers(envioDB.DB); err != nil {
+			log.Printf("Warning: falling back to polling: %v", err)
+			break
+		}
+		ch, closeListener, err := events.Listen(databaseURL)
+		if err != nil {
+			log.Printf("Warning: falling back to polling: %v", err)
+			break
+		}
+		wake = ch
+		a.closers = append(a.closers, closeListener)
+	case EventsPoll:
+	default:
+		return fmt.Errorf("unknown events mode %q", a.Config.EventsMode)
+	}
+
+	stop := make(chan struct{})
+	a.closers = append(a.closers, func() error {
+		close(stop)
+		return nil
+	})
+	go pipeline.Run(a.Config.EventsPollInterval, wake, stop)
+
+	log.Printf("📡 Event pipeline started (%s mode, polling every %s)", a.Config.EventsMode, a.Config.EventsPollInterval)
+	return nil
+}
+
+// provideIndexer starts tracking how far the indexer lags behind
+func (a *App) provideIndexer() {
+	if a.DB == nil || a.Config.IndexerLagInterval <= 0 {
+		return
+	}
+
+	a.Indexer = indexer.NewMonitor(a.DB.DB, a.Config.IndexerLagInterval, a.Config.IndexerStaleAfter)
+	if a.Config.MetricsEnabled {
+		metrics.RegisterIndexerLagGauge(func() float64 {
+			lag, ok := a.Indexer.Lag()
+			if !ok {
+				return math.NaN()
+			}
+			return lag.Seconds()
+		})
+	}
+
+	stop := make(chan struct{})
+	a.closers = append(a.closers, func() error {
+		close(stop)
+		return nil
+	})
+	go a.Indexer.Run(stop)
+
+	log.Printf("⏱️ Indexer lag checks every %s (stale after %s)", a.Config.IndexerLagInterval, a.Config.IndexerStaleAfter)
+}
+
+// provideNames resolves name service names of players in the background when
+// NAME_SERVICE_RPC_URL is set
+func (a *App) provideNames() {
+	if a.Config.NameServiceRPCURL == "" {
+		return
+	}
+
+	resolver := names.NewENSResolver(a.Config.NameServiceRPCURL, a.Config.NameServiceRegistry)
+	a.Names = names.NewService(resolver, a.Config.NameCacheTTL, a.Config.NameRefreshInterval, a.Config.NameRefreshBatch)
+
+	stop := make(chan struct{})
+	a.closers = append(a.closers, func() error {
+		close(stop)
+		return nil
+	})
+	go a.Names.Run(stop)
+
+	log.Printf("📛 Name resolution enabled (cached for %s, refreshed every %s)", a.Config.NameCacheTTL, a.Config.NameRefreshInterval)
+}
+
+// provideStatus records periodic health samples for the status page
+func (a *App) provideStatus() {
+	probe := func() error { return nil } // replay mode has no dependencies to check
+	if a.DB != nil {
+		probe = func() error {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			return a.DB.DB.PingContext(ctx)
+		}
+	}
+
+	a.Status = status.NewMonitor(probe, a.Config.HealthSampleInterval)
+
+	stop := make(chan struct{})
+	a.closers = append(a.closers, func() error {
+		close(stop)
+		return nil
+	})
+	go a.Status.Run(stop)
+}
+
+// Rate limit backends accepted by RATE_LIMIT_BACKEND
+const (
+	RateLimitMemory = "memory"
+	RateLimitRedis  = "redis"
+)
+
+// provideRateLimit creates the limiter for the /api rate limits, falling back to
+// in-memory buckets when Redis is unavailable
+func (a *App) provideRateLimit() {
+	if !a.Config.RateLimitEnabled {
+		return
+	}
+
+	switch a.Config.RateLimitBackend {
+	case RateLimitRedis:
+		if a.Config.RedisURL == "" {
+			log.Printf("Warning: RATE_LIMIT_BACKEND=redis needs REDIS_URL, using in-memory rate limits")
+			break
+		}
+		limiter, err := ratelimit.NewRedis(a.Config.RedisURL)
+		if err != nil {
+			log.Printf("Warning: using in-memory rate limits: %v", err)
+			break
+		}
+		a.limiter = limiter
+		a.closers = append(a.closers, limiter.Close)
+	case RateLimitMemory, "":
+	default:
+		log.Printf("Warning: unknown rate limit backend %q, using in-memory rate limits", a.Config.RateLimitBackend)
+	}
+
+	if a.limiter == nil {
+		limiter := ratelimit.NewMemory()
+		a.limiter = limiter
+		a.closers = append(a.closers, limiter.Close)
+	}
+
+	log.Printf("🚦 Rate limiting: %.0f req/s per IP (%.0f on expensive routes), %.0f req/s per address",
+		a.Config.RateLimitRPS, a.Config.RateLimitExpensiveRPS, a.Config.RateLimitAddressRPS)
+}
+
+// rateLimitRules builds the rate limit buckets; /search and /leaderboard are expensive unless configured otherwise
+func (a *App) rateLimitRules() ratelimit.Rules {
+	expensive := a.Config.RateLimitExpensivePaths
+	if len(expensive) == 0 {
+		expensive = []string{"/search", "/leaderboard", "/analytics", "/export"}
+	}
+
+	return ratelimit.Rules{
+		Default:        ratelimit.Limit{Rate: a.Config.RateLimitRPS, Burst: a.Config.RateLimitBurst},
+		Expensive:      ratelimit.Limit{Rate: a.Config.RateLimitExpensiveRPS, Burst: a.Config.RateLimitExpensiveBurst},
+		Address:        ratelimit.Limit{Rate: a.Config.RateLimitAddressRPS, Burst: a.Config.RateLimitAddressBurst},
+		ExpensivePaths: expensive,
+	}
+}
+
+// provideAPIKeys starts checking partner API keys and flushing their usage. Keys draw from
+// the rate limiter's buckets, or from in-memory ones when rate limiting is off; keys not yet
+// known to be valid also draw from the per-IP default bucket.
+func (a *App) provideAPIKeys() {
+	if a.APIKeys == nil {
+		return
+	}
+
+	limiter := a.limiter
+	if limiter == nil {
+		memory := ratelimit.NewMemory()
+		limiter = memory
+		a.closers = append(a.closers, memory.Close)
+	}
+	a.apiKeys = apikeys.NewService(a.APIKeys, limiter, a.rateLimitRules().Default, nil)
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	a.closers = append(a.closers, func() error {
+		close(stop)
+		<-done
+		return nil
+	})
+	go func() {
+		defer close(done)
+		a.apiKeys.Run(a.Config.APIKeyUsageFlushInterval, stop)
+	}()
+
+	log.Printf("🔑 Partner API keys enabled (usage flushed every %s)", a.Config.APIKeyUsageFlushInterval)
+}
+
+// provideAudit starts recording reads of player data, written in the background so auditing
+// never slows requests down
+func (a *App) provideAudit() {
+	if a.Audit == nil {
+		return
+	}
+
+	a.auditLog = audit.NewLogger(a.Audit, audit.Config{
+		FlushInterval: a.Config.AuditLogFlushInterval,
+		Retention:     a.Config.AuditLogRetention,
+	})
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	a.closers = append(a.closers, func() error {
+		close(stop)
+		<-done
+		return nil
+	})
+	go func() {
+		defer close(done)
+		a.auditLog.Run(stop)
+	}()
+
+	log.Printf("📋 Audit log of player data reads enabled (kept for %s)", a.Config.AuditLogRetention)
+}
+
+// provideRouter builds the Gin router with middleware and routes
+func (a *App) provideRouter() error {
+	log.Printf("🌐 CORS allowed origins: %v", a.origins.Strings())
+
+	r := gin.New()
+	// X-Forwarded-For is only believed from configured proxies; otherwise any client could
+	// pick its own IP for the rate limits and the audit log
+	if err := r.SetTrustedProxies(a.Config.TrustedProxies); err != nil {
+		return fmt.Errorf("invalid TRUSTED_PROXIES: %w", err)
+	}
+	if len(a.Config.TrustedProxies) > 0 {
+		log.Printf("🌐 Trusted proxies: %v", a.Config.TrustedProxies)
+	}
+	r.Use(logging.Middleware())
+	r.Use(logging.Recovery())
+	r.Use(a.Status.Middleware())
+	if a.Config.MetricsEnabled {
+		r.Use(metrics.Middleware())
+	}
+	if a.Config.AccessLogEnabled {
+		r.Use(accesslog.Middleware(a.accessLogConfig()))
+	}
+	if a.budgets.Default > 0 || len(a.budgets.Routes) > 0 {
+		r.Use(a.slowLog.Middleware(a.budgets))
+	}
+
+	r.Use(cors.New(cors.Config{
+		AllowOriginFunc:  a.origins.Allowed,
+		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "If-None-Match", apikeys.Header, logging.RequestIDHeader},
+		ExposeHeaders:    []string{"Content-Length", "Retry-After", "ETag", logging.RequestIDHeader, indexer.LagHeader},
+		AllowCredentials: true,
+		MaxAge:           12 * time.Hour,
+	}))
+
+	// Compressed before the replay recorder wraps the writer, so recordings stay uncompressed
+	if a.Config.CompressionEnabled {
+		r.Use(compress.Middleware(a.Config.CompressionMinSize))
+	}
+
+	// Record or replay API responses depending on data mode
+	if a.replayRecorder != nil {
+		r.Use(a.replayRecorder.Middleware())
+	}
+	if a.replayPlayer != nil {
+		r.Use(a.replayPlayer.Middleware())
+	}
+
+	nadmonHandler := handlers.NewNadmonHandler(a.Repo)
+	nadmonHandler.SetInventoryStreamThreshold(a.Config.InventoryStreamThreshold)
+	nadmonHandler.SetLimits(handlers.Limits{
+		MaxBatchIDs:      a.Config.MaxBatchIDs,
+		DefaultPageSize:  a.Config.DefaultPageSize,
+		MaxPageSize:      a.Config.MaxPageSize,
+		MaxTeamSize:      a.Config.TeamMaxSize,
+		MaxTeams:         a.Config.TeamMaxCount,
+		MaxFavorites:     a.Config.FavoriteMaxCount,
+		MaxTradeSize:     a.Config.TradeMaxSize,
+		MaxPendingTrades: a.Config.TradeMaxPending,
+	})
+	if a.Profiles != nil {
+		nadmonHandler.SetProfileStore(a.Profiles)
+		nadmonHandler.SetTeamStore(a.Teams)
+		nadmonHandler.SetFavoriteStore(a.Favorites)
+		nadmonHandler.SetTradeStore(a.Trades, a.Notifier)
+		nadmonHandler.SetBattleStore(a.Battles)
+	}
+	if a.Rarity != nil {
+		nadmonHandler.SetRarityStore(a.Rarity)
+	}
+	if a.Standings != nil {
+		nadmonHandler.SetLeaderboardStore(a.Standings)
+	}
+	if a.PlayerStats != nil {
+		nadmonHandler.SetPlayerStatsStore(a.PlayerStats)
+	}
+	if a.Names != nil {
+		nadmonHandler.SetNameService(a.Names)
+	}
+	chainHandler := handlers.NewChainHandler(a.chains(), a.Config.ChainID)
+	wsHandler := handlers.NewWebSocketHandler(a.WS, a.Auth, chainHandler, a.Config.WSPublicEnabled)
+	// Admin notices reach the clients of every replica when notifications fan out through Redis
+	wsHandler.SetBroadcaster(a.Notifier)
+	a.registerRoutes(r, nadmonHandler, chainHandler, wsHandler)
+	a.Router = r
+	return nil
+}
+
+// accessLogConfig builds the access logger settings; health checks are excluded unless configured otherwise
+func (a *App) accessLogConfig() accesslog.Config {
+	exclude := a.Config.AccessLogExclude
+	if len(exclude) == 0 {
+		exclude = []string{"/healthz", "/readyz", "/metrics"}
+	}
+
+	return accesslog.Config{
+		SampleRates:   accesslog.ParseSampleRates(a.Config.AccessLogSampleRates),
+		Exclude:       exclude,
+		HashAddresses: a.Config.AccessLogHashAddresses,
+	}
+}
+
+// Run starts the HTTP server and blocks until SIGINT/SIGTERM, then shuts down gracefully
+func (a *App) Run() error {
+	port := a.Config.Port
+	if port == "" {
+		port = "8080"
+	}
+
+	srv := &http.Server{
+		Addr:              ":" + port,
+		Handler:           a.Router,
+		ReadTimeout:       a.Config.HTTPReadTimeout,
+		ReadHeaderTimeout: a.Config.HTTPReadHeaderTimeout,
+		WriteTimeout:      a.Config.HTTPWriteTimeout,
+		IdleTimeout:       a.Config.HTTPIdleTimeout,
+		MaxHeaderBytes:    a.Config.HTTPMaxHeaderBytes,
+	}
+	srv.SetKeepAlivesEnabled(a.Config.HTTPKeepAlive)
+
+	errCh := make(chan error, 3)
+	redirectSrv := a.listen(srv, errCh)
+	grpcSrv := a.listenGRPC(errCh)
+
+	logStartup(port)
+
+	// Wait for interrupt signal to gracefully shutdown
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+
+	select {
+	case err := <-errCh:
+		a.Close()
+		return err
+	case <-quit:
+	}
+
+	log.Println("🛑 Shutting down server...")
+
+	// Shutdown server with timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if redirectSrv != nil {
+		redirectSrv.Shutdown(ctx)
+	}
+	if grpcSrv != nil {
+		stopGRPC(ctx, grpcSrv)
+	}
+	err := srv.Shutdown(ctx)
+	a.Close()
+	return err
+}
+
+// Close releases all subsystems in reverse order of creation
+func (a *App) Close() {
+	for i := len(a.closers) - 1; i >= 0; i-- {
+		if err := a.closers[i](); err != nil {
+			log.Printf("Warning: %v", err)
+		}
+	}
+	a.closers = nil
+}
@@ -0,0 +1,111 @@
+package app
+
+import (
+	"fmt"
+	"log"
+
+	"nadmon-backend/internal/database"
+	"nadmon-backend/internal/events"
+	"nadmon-backend/internal/fanout"
+	"nadmon-backend/internal/repository"
+)
+
+// chain is a deployment on another chain, indexed into its own Envio database
+type chain struct {
+	db    *database.EnvioDB
+	url   string
+	store repository.Store
+}
+
+// provideChains connects the Envio database of every chain in CHAIN_DATABASE_URLS. Like
+// other collections, they are read straight from their Envio tables, without the Redis
+// cache, current-state table or continuous aggregates.
+func (a *App) provideChains() error {
+	urls, err := repository.ParseChains(a.Config.ChainDatabases)
+	if err != nil {
+		return err
+	}
+	if len(urls) > 0 && !repository.ValidChainID(a.Config.ChainID) {
+		return fmt.Errorf("invalid CHAIN_ID %q, expected a decimal chain ID", a.Config.ChainID)
+	}
+
+	a.extraChains = make(map[string]*chain, len(urls))
+	for id, url := range urls {
+		if id == a.Config.ChainID {
+			return fmt.Errorf("chain %s is the default chain and is read from DATABASE_URL", id)
+		}
+
+		envioDB, err := database.ConnectToEnvioWithPool(url, a.poolConfig())
+		if err != nil {
+			return fmt.Errorf("failed to connect to chain %s: %w", id, err)
+		}
+		a.closers = append(a.closers, envioDB.Close)
+		if err := envioDB.TestConnection(); err != nil {
+			return fmt.Errorf("failed to connect to chain %s: %w", id, err)
+		}
+		a.provideBreaker(envioDB)
+		if err := envioDB.CreateIndexes(); err != nil {
+			log.Printf("Warning: Failed to create some indexes on chain %s: %v", id, err)
+		}
+
+		repo := repository.NewNadmonRepositoryWithConn(envioDB, a.conn(envioDB))
+		repo.SetExcludedAddresses(a.Config.ExcludedAddresses)
+
+		var store repository.Store = repo
+		if a.Config.MetricsEnabled {
+			store = repository.NewInstrumentedStore(repo)
+		}
+		a.extraChains[id] = &chain{db: envioDB, url: url, store: store}
+		log.Printf("⛓️ Chain %s reads its own Envio database", id)
+	}
+	return nil
+}
+
+// chains returns every chain's store by chain ID, including the default chain
+func (a *App) chains() map[string]repository.Store {
+	chains := map[string]repository.Store{a.Config.ChainID: a.Repo}
+	for id, c := range a.extraChains {
+		chains[id] = c.store
+	}
+	return chains
+}
+
+// provideChainEvents pushes the new indexer rows of every other chain to the affected players,
+// tagged with the chain ID. Caches and webhooks only follow the default chain.
+func (a *App) provideChainEvents() error {
+	if a.Config.EventsMode == EventsOff {
+		return nil
+	}
+
+	for id, c := range a.extraChains {
+		pipeline := events.NewPipeline(c.db.DB, a.chainNotifier(id))
+		if err := pipeline.Init(); err != nil {
+			return fmt.Errorf("failed to start events of chain %s: %w", id, err)
+		}
+		pipeline.SetNadmonLoader(c.store)
+		if err := a.runPipeline(pipeline, c.db, c.url); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// chainNotifier returns the notifier of an event pipeline on chain: its messages are tagged
+// with the chain ID, and with fan-out only the lease holder pushes them
+func (a *App) chainNotifier(chain string) events.Notifier {
+	var notifier events.Notifier = &chainNotifier{notifier: a.Notifier, chain: chain}
+	if a.eventsLeader != nil {
+		notifier = fanout.LeaderOnly(notifier, a.eventsLeader)
+	}
+	return notifier
+}
+
+// chainNotifier tags the notifications it passes on with the chain they happened on
+type chainNotifier struct {
+	notifier Notifier
+	chain    string
+}
+
+func (n *chainNotifier) NotifyUser(address string, messageType string, data interface{}) {
+	n.notifier.NotifyUserOnChain(n.chain, address, messageType, data)
+}
@@ -0,0 +1,44 @@
+package app
+
+import (
+	"context"
+	"log"
+	"net"
+
+	"google.golang.org/grpc"
+
+	"nadmon-backend/internal/grpcapi"
+)
+
+// listenGRPC starts the gRPC API on GRPC_PORT, serving the same store as the REST API; it
+// returns nil when the port is not configured
+func (a *App) listenGRPC(errCh chan<- error) *grpc.Server {
+	if a.Config.GRPCPort == "" {
+		return nil
+	}
+
+	lis, err := net.Listen("tcp", ":"+a.Config.GRPCPort)
+	if err != nil {
+		errCh <- err
+		return nil
+	}
+
+	srv := grpcapi.NewServer(a.Repo, a.Config.RequestTimeout, a.Config.MaxBatchIDs)
+	log.Printf("🛰️ gRPC API on :%s (reflection enabled)", a.Config.GRPCPort)
+	go serve(func() error { return srv.Serve(lis) }, errCh)
+	return srv
+}
+
+// stopGRPC lets in-flight calls finish until ctx is done, then closes remaining connections
+func stopGRPC(ctx context.Context, srv *grpc.Server) {
+	done := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		srv.Stop()
+	}
+}
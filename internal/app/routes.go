@@ -0,0 +1,526 @@
+package app
+
+import (
+	"context"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"nadmon-backend/internal/apiversion"
+	"nadmon-backend/internal/auth"
+	"nadmon-backend/internal/chaos"
+	"nadmon-backend/internal/database"
+	"nadmon-backend/internal/etag"
+	"nadmon-backend/internal/handlers"
+	"nadmon-backend/internal/i18n"
+	"nadmon-backend/internal/images"
+	"nadmon-backend/internal/metrics"
+	"nadmon-backend/internal/ratelimit"
+	"nadmon-backend/internal/timeout"
+	"nadmon-backend/internal/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+// registerRoutes registers all HTTP routes on the router
+func (a *App) registerRoutes(r *gin.Engine, nadmonHandler *handlers.NadmonHandler, chainHandler *handlers.ChainHandler, wsHandler *handlers.WebSocketHandler) {
+	avatarHandler := handlers.NewAvatarHandler()
+	i18nHandler := handlers.NewI18nHandler(i18n.MustLoad())
+	gameDataHandler := handlers.NewGameDataHandler(a.Config.GameDataFile)
+	statusHandler := handlers.NewStatusHandler(a.Status)
+	metadataHandler := handlers.NewMetadataHandler(a.Repo, a.Config.PublicBaseURL)
+	imageHandler := handlers.NewImageHandler(a.Repo, images.NewResolver(images.Config{
+		Dir:        a.Config.ImageDir,
+		CID:        a.Config.ImageIPFSCID,
+		Gateways:   a.Config.IPFSGateways,
+		Timeout:    a.Config.ImageFetchTimeout,
+		CacheBytes: int64(a.Config.ImageCacheMB) << 20,
+	}))
+	authHandler := handlers.NewAuthHandler(a.Auth)
+	collectionHandler := handlers.NewCollectionHandler(a.collections(), a.Config.DefaultCollection)
+	docsHandler := handlers.NewDocsHandler()
+
+	// Kubernetes probes: liveness, and readiness with dependency detail
+	r.GET("/healthz", a.healthz)
+	r.GET("/readyz", a.readyz)
+
+	// Prometheus metrics
+	if a.Config.MetricsEnabled {
+		r.GET("/metrics", metrics.Handler())
+	}
+
+	// Database stats endpoint
+	r.GET("/stats", a.requireDatabase(), timeout.Middleware(a.Config.RequestTimeout), nadmonHandler.GetGameStats)
+
+	// Swagger UI; the OpenAPI spec itself is served under /api
+	r.GET("/docs", docsHandler.GetUI)
+
+	// API routes, registered twice: /api answers with the handlers' bodies unless the
+	// API-Version header asks for the v1 envelope, /api/v1 always answers in the envelope
+	registerAPI := func(api *gin.RouterGroup) {
+		// The admin API key unlocks admin-only options such as ?nocache=1
+		api.Use(auth.MarkAPIKey(a.Config.AdminAPIKey))
+		// Partner API keys go first: keyed requests draw from the key's bucket instead of the
+		// per-IP ones
+		if a.apiKeys != nil {
+			api.Use(a.apiKeys.Middleware())
+		}
+		// Audited after key authentication, so entries carry the key; rejected keys read nothing
+		if a.auditLog != nil {
+			api.Use(a.auditLog.Middleware())
+		}
+		if a.limiter != nil {
+			api.Use(ratelimit.Middleware(a.limiter, a.rateLimitRules()))
+		}
+		if a.chaos.ErrorRate > 0 {
+			api.Use(chaos.Middleware(a.chaos))
+		}
+		if a.Indexer != nil {
+			api.Use(a.Indexer.Middleware())
+		}
+		// Shared path and query parameters are checked before any handler runs, so clients get
+		// the same error codes everywhere
+		api.Use(validation.Middleware(validation.Rules{
+			MaxPageSize:    a.Config.MaxPageSize,
+			MaxBatchIDs:    a.Config.MaxBatchIDs,
+			MaxQueryLength: a.Config.MaxQueryLength,
+			MaxBodyBytes:   int64(a.Config.MaxBodyBytes),
+			RouteLimits: map[string]int{
+				"/search/suggestions":      handlers.MaxSearchSuggestions,
+				"/webhooks/:id/deliveries": handlers.MaxWebhookLog,
+			},
+		}))
+
+		// Endpoints reading the database fail fast while it is down and time out after
+		// REQUEST_TIMEOUT
+		data := api.Group("", a.requireDatabase(), timeout.Middleware(a.Config.RequestTimeout))
+
+		// Endpoints reading indexed data from the chain named by ?chain=, the default chain
+		// without it
+		onChain := data.Group("", chainHandler.Query())
+
+		// Collection-scoped endpoints; at the root they serve the default collection
+		registerCollectionRoutes(onChain, nadmonHandler, metadataHandler)
+
+		// Other collections, e.g. /api/collections/items/nfts/1
+		api.GET("/collections", collectionHandler.GetCollections)
+		registerCollectionRoutes(onChain.Group("/collections/:collection", collectionHandler.Resolve()), nadmonHandler, metadataHandler)
+
+		// Deployments on other chains, e.g. /api/chains/1/nfts/1
+		api.GET("/chains", chainHandler.GetChains)
+		registerCollectionRoutes(data.Group("/chains/:chain", chainHandler.Resolve()), nadmonHandler, metadataHandler)
+
+		// Ownership checks gating game-server battles, on the deployment picked by ?chain=
+		onChain.GET("/verify/ownership", nadmonHandler.VerifyOwnership)
+
+		// Player avatars, display profiles, teams and favorites don't depend on the collection
+		api.GET("/players/:address/avatar.png", avatarHandler.GetAvatar)
+		data.PUT("/players/:address/profile", a.Auth.RequireOwner(), nadmonHandler.UpdateDisplayProfile)
+		data.GET("/players/:address/teams", a.Auth.RequireOwner(), nadmonHandler.GetTeams)
+		data.POST("/players/:address/teams", a.Auth.RequireOwner(), nadmonHandler.CreateTeam)
+		data.DELETE("/players/:address/teams/:teamId", a.Auth.RequireOwner(), nadmonHandler.DeleteTeam)
+		data.GET("/players/:address/favorites", nadmonHandler.GetFavorites)
+		data.POST("/players/:address/favorites/:tokenId", a.Auth.RequireOwner(), nadmonHandler.AddFavorite)
+		data.DELETE("/players/:address/favorites/:tokenId", a.Auth.RequireOwner(), nadmonHandler.RemoveFavorite)
+
+		// Trade offers between players of the default collection, settled on-chain once accepted
+		trades := data.Group("/trades", a.Auth.RequireAuth())
+		trades.POST("", nadmonHandler.CreateTrade)
+		trades.GET("", nadmonHandler.GetTrades)
+		trades.GET("/:tradeId", nadmonHandler.GetTrade)
+		trades.POST("/:tradeId/accept", nadmonHandler.AcceptTrade)
+		trades.POST("/:tradeId/reject", nadmonHandler.RejectTrade)
+		trades.POST("/:tradeId/cancel", nadmonHandler.CancelTrade)
+
+		// Name service names (ENS-style) of addresses and addresses of names, without the database
+		api.GET("/resolve/:addressOrName", timeout.Middleware(a.Config.RequestTimeout), nadmonHandler.ResolveName)
+
+		// Artwork is published for the default collection
+		data.GET("/images/:tokenId", imageHandler.GetImage)
+
+		// Rarity ranks are computed for the default collection, so comparisons including
+		// them are too
+		data.GET("/nfts/:tokenId/rank", nadmonHandler.GetNFTRank)
+		data.GET("/nfts/compare", nadmonHandler.CompareNFTs)
+		data.GET("/leaderboard/rarest-nfts", nadmonHandler.GetRarestNFTs)
+
+		// Collector ranks come from the default collection's leaderboard snapshot
+		data.GET("/players/:address/rank", nadmonHandler.GetPlayerRank)
+
+		// Off-chain battle results, reported by the game server with requests signed with
+		// BATTLE_SIGNING_SECRET, and the PvP ratings computed from them
+		if a.Config.BattleSigningSecret != "" {
+			data.POST("/battles", auth.RequireSignature(a.Config.BattleSigningSecret, a.Config.BattleSignatureMaxAge), nadmonHandler.RecordBattle)
+		}
+		data.GET("/players/:address/battles", nadmonHandler.GetPlayerBattles)
+		data.GET("/leaderboard/pvp", nadmonHandler.GetPvPLeaderboard)
+
+		// Webhook subscriptions for third-party integrations, managed with a session token or
+		// the admin API key
+		if a.Webhooks != nil {
+			webhookHandler := handlers.NewWebhookHandler(a.Webhooks, a.Config.WebhookMaxPerWallet, a.reloadWebhooks)
+			hooks := data.Group("/webhooks", a.Auth.RequireAuthOrAPIKey(a.Config.AdminAPIKey))
+			hooks.POST("", webhookHandler.CreateWebhook)
+			hooks.GET("", webhookHandler.GetWebhooks)
+			hooks.DELETE("/:id", webhookHandler.DeleteWebhook)
+			hooks.GET("/:id/deliveries", webhookHandler.GetDeliveries)
+		}
+
+		// OpenAPI spec
+		api.GET("/openapi.json", docsHandler.GetSpec)
+
+		// Status page
+		api.GET("/status/history", statusHandler.GetHistory)
+
+		// Localized labels
+		api.GET("/i18n/:locale", i18nHandler.GetCatalog)
+
+		// Element colors, matchups and derived-stat formulas
+		api.GET("/game-data", etag.Middleware(), gameDataHandler.GetGameData)
+
+		// Sign-In With Ethereum
+		api.POST("/auth/nonce", authHandler.GetNonce)
+		api.POST("/auth/verify", authHandler.Verify)
+		api.GET("/auth/session", a.Auth.RequireAuth(), authHandler.GetSession)
+		api.POST("/auth/stream-token", a.Auth.RequireAuth(), authHandler.GetStreamToken)
+
+		// Legacy endpoints for backward compatibility
+		onChain.GET("/inventory/:address", etag.Middleware(), nadmonHandler.GetInventory)
+		onChain.GET("/inventory/:address/search", nadmonHandler.SearchNFTs)
+		onChain.GET("/nft/:tokenId", etag.Middleware(), nadmonHandler.GetNFT)
+		onChain.GET("/stats/:address", nadmonHandler.GetStats)
+
+		// WebSocket endpoint for real-time updates; a ?token= stream token opens the
+		// wallet's private channel, without one only public broadcasts are sent, and ?chain=
+		// limits them to one chain's events
+		// Server-Sent Events fallback for networks that block WebSockets
+		api.GET("/ws", wsHandler.HandleConnection)
+		api.GET("/ws/schemas", wsHandler.GetSchemas)
+		api.GET("/ws/:address", wsHandler.HandleConnection)
+		api.GET("/sse", wsHandler.HandleStream)
+		api.GET("/sse/:address", wsHandler.HandleStream)
+	}
+	registerAPI(r.Group("/api", apiversion.Middleware("")))
+	registerAPI(r.Group("/api/v1", apiversion.Middleware(apiversion.V1)))
+
+	// Operational endpoints, only served when ADMIN_API_KEY is set
+	if a.Config.AdminAPIKey != "" {
+		adminHandler := handlers.NewAdminHandler(a.DB, a.Cache, a.slowLog)
+		admin := r.Group("/admin", auth.RequireAPIKey(a.Config.AdminAPIKey))
+		admin.GET("/websocket", wsHandler.GetConnectedUsers)
+		admin.GET("/websocket/connections", wsHandler.ListConnections)
+		admin.DELETE("/websocket/connections/:address", wsHandler.DisconnectAddress)
+		admin.POST("/websocket/broadcast", wsHandler.BroadcastNotice)
+		admin.POST("/cache/flush", adminHandler.FlushCache)
+		admin.POST("/game-data/reload", gameDataHandler.ReloadGameData)
+		admin.POST("/indexes/rebuild", a.requireDatabase(), adminHandler.RebuildIndexes)
+		admin.GET("/slow-queries", a.requireDatabase(), adminHandler.GetSlowQueries)
+		admin.GET("/slow-operations", adminHandler.GetSlowOperations)
+		admin.GET("/export/snapshot", a.requireDatabase(), nadmonHandler.ExportSnapshot)
+		admin.GET("/snapshot", a.requireDatabase(), nadmonHandler.GetHolderSnapshot)
+		if a.apiKeys != nil {
+			apiKeyHandler := handlers.NewAPIKeyHandler(a.apiKeys, a.APIKeys, a.Config.APIKeyRateLimitRPS, a.Config.APIKeyRateLimitBurst)
+			keys := admin.Group("/api-keys", a.requireDatabase())
+			keys.POST("", apiKeyHandler.CreateAPIKey)
+			keys.GET("", apiKeyHandler.GetAPIKeys)
+			keys.GET("/:id/usage", apiKeyHandler.GetAPIKeyUsage)
+			keys.DELETE("/:id", apiKeyHandler.RevokeAPIKey)
+		}
+		if a.Audit != nil {
+			auditHandler := handlers.NewAuditHandler(a.Audit)
+			admin.GET("/audit-log", a.requireDatabase(), auditHandler.GetAuditLog)
+		}
+		log.Printf("🔐 Admin API enabled at /admin")
+	}
+}
+
+// registerCollectionRoutes registers the read endpoints that exist once per collection
+func registerCollectionRoutes(g *gin.RouterGroup, nadmonHandler *handlers.NadmonHandler, metadataHandler *handlers.MetadataHandler) {
+	// Player endpoints; inventory and NFT reads answer If-None-Match with 304s
+	g.GET("/players/:address/nadmons", etag.Middleware(), nadmonHandler.GetInventory)
+	g.GET("/players/:address/nadmons/changes", nadmonHandler.GetInventoryChanges)
+	g.GET("/players/:address/profile", nadmonHandler.GetPlayerProfile)
+	g.GET("/players/:address/packs", nadmonHandler.GetPlayerPacks)
+	g.GET("/players/:address/packs/summary", nadmonHandler.GetPlayerPackSummary)
+	g.GET("/players/:address/stats", nadmonHandler.GetStats)
+	g.GET("/players/:address/search", nadmonHandler.SearchNFTs)
+	g.GET("/players/:address/transfers", nadmonHandler.GetPlayerTransfers)
+	g.GET("/players/:address/dex", nadmonHandler.GetPlayerDex)
+	g.GET("/players/:address/activity", nadmonHandler.GetPlayerActivity)
+	g.GET("/players/:address/fusion-candidates", nadmonHandler.GetFusionCandidates)
+	g.GET("/players/:address/export", nadmonHandler.ExportPlayer)
+
+	// NFT endpoints
+	g.GET("/nfts/search", nadmonHandler.SearchAllNFTs) // Search every player's NFTs
+	g.GET("/nfts/:tokenId", etag.Middleware(), nadmonHandler.GetNFT)
+	g.GET("/nfts/:tokenId/history", nadmonHandler.GetNFT) // Same endpoint, returns history
+	g.GET("/nfts/:tokenId/stats/timeline", etag.Middleware(), nadmonHandler.GetStatTimeline)
+	g.GET("/nfts/:tokenId/lineage", etag.Middleware(), nadmonHandler.GetNFTLineage)
+	g.GET("/nfts/:tokenId/transfers", nadmonHandler.GetNFTTransfers)
+	g.GET("/nfts/:tokenId/sales", nadmonHandler.GetNFTSales)
+	g.GET("/nfts", nadmonHandler.GetNFTsByIDs) // Batch fetch NFTs by IDs
+
+	// ERC-721 metadata for wallets and marketplaces
+	g.GET("/metadata/:tokenId", metadataHandler.GetMetadata)
+
+	// Pack endpoints
+	g.GET("/packs/:packId", nadmonHandler.GetPackDetails)
+	g.GET("/packs/:packId/reveal", nadmonHandler.GetPackReveal)
+
+	// Game data endpoints
+	g.GET("/packs/recent", nadmonHandler.GetRecentPacks)
+	g.GET("/activity", nadmonHandler.GetActivity)
+	g.GET("/leaderboard/collectors", nadmonHandler.GetLeaderboard)
+	g.GET("/leaderboard/:type", nadmonHandler.GetLeaderboardByType)
+	g.GET("/stats/game", nadmonHandler.GetGameStats)
+	g.GET("/stats/pack-distribution", nadmonHandler.GetPackDistribution)
+	g.GET("/stats/concentration", nadmonHandler.GetOwnershipConcentration)
+	g.GET("/stats/supply", nadmonHandler.GetSupplyStats)
+	g.GET("/stats/elements", nadmonHandler.GetElementStats)
+	g.GET("/stats/rarities", nadmonHandler.GetRarityStats)
+	g.GET("/stats/floor", nadmonHandler.GetFloorStats)
+	g.GET("/analytics/timeseries", nadmonHandler.GetTimeSeries)
+	g.GET("/analytics/packs", nadmonHandler.GetPackOdds)
+
+	// Search endpoints
+	g.GET("/search/suggestions", nadmonHandler.GetSearchSuggestions)
+}
+
+// healthz is the liveness probe: it answers as long as the process serves HTTP and checks
+// no dependencies, so a database outage never gets the pod restarted
+func (a *App) healthz(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":    "ok",
+		"timestamp": time.Now(),
+	})
+}
+
+// readinessTimeout bounds each dependency check of /readyz
+const readinessTimeout = 2 * time.Second
+
+// dependencyStatus is the result of one /readyz dependency check
+type dependencyStatus struct {
+	Status    string  `json:"status"` // ok, error, lagging or disabled
+	Error     string  `json:"error,omitempty"`
+	LatencyMs float64 `json:"latency_ms,omitempty"`
+}
+
+// readyz is the readiness probe. The database and the event pipeline must be healthy; an
+// unreachable cache only degrades the response, since reads then go to the database, and so
+// does the database of another chain, since only its routes fail.
+func (a *App) readyz(c *gin.Context) {
+	response := gin.H{"timestamp": time.Now()}
+	if a.DB == nil {
+		response["status"] = "ready"
+		response["mode"] = a.Config.DataMode
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+
+	checks := map[string]dependencyStatus{
+		"database": a.checkDatabase(ctx, a.DB),
+		"cache":    a.checkCache(ctx),
+	}
+	degraded := checks["cache"].Status == "error"
+	for id, c := range a.extraChains {
+		check := a.checkDatabase(ctx, c.db)
+		checks["chain:"+id] = check
+		degraded = degraded || check.Status == "error"
+	}
+	eventsCheck, indexerLag := a.checkEvents()
+	checks["events"] = eventsCheck
+
+	ready := checks["database"].Status == "ok" && (eventsCheck.Status == "ok" || eventsCheck.Status == "disabled")
+	response["checks"] = checks
+	response["circuit_breaker"] = a.DB.Breaker.Stats()
+	// The indexer monitor reads every event table; the pipeline only knows the rows it polled
+	if a.Indexer != nil {
+		progress := a.Indexer.Progress()
+		response["indexer"] = progress
+		if !progress.NewestWrite.IsZero() {
+			indexerLag = &progress.LagSeconds
+		}
+	}
+	if indexerLag != nil {
+		response["indexer_lag_seconds"] = *indexerLag
+	}
+	if a.Shadow != nil {
+		response["shadow"] = a.Shadow.Stats()
+	}
+
+	switch {
+	case !ready:
+		response["status"] = "not_ready"
+		c.JSON(http.StatusServiceUnavailable, response)
+		return
+	case degraded:
+		response["status"] = "degraded"
+	default:
+		response["status"] = "ready"
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// checkDatabase pings envioDB unless its circuit breaker is open
+func (a *App) checkDatabase(ctx context.Context, envioDB *database.EnvioDB) dependencyStatus {
+	if envioDB.Breaker.Stats().State == database.BreakerOpen {
+		return dependencyStatus{Status: "error", Error: database.ErrCircuitOpen.Error()}
+	}
+	start := time.Now()
+	if err := envioDB.DB.PingContext(ctx); err != nil {
+		return dependencyStatus{Status: "error", Error: err.Error()}
+	}
+	return dependencyStatus{Status: "ok", LatencyMs: elapsedMs(start)}
+}
+
+// checkCache pings the Redis cache
+func (a *App) checkCache(ctx context.Context) dependencyStatus {
+	if a.redis == nil {
+		return dependencyStatus{Status: "disabled"}
+	}
+	start := time.Now()
+	if err := a.redis.Ping(ctx); err != nil {
+		return dependencyStatus{Status: "error", Error: err.Error()}
+	}
+	return dependencyStatus{Status: "ok", LatencyMs: elapsedMs(start)}
+}
+
+// checkEvents reports whether the event pipeline keeps polling, along with the indexer lag:
+// the age of the newest row it has seen, or nil before it has seen any
+func (a *App) checkEvents() (dependencyStatus, *float64) {
+	if a.Events == nil {
+		return dependencyStatus{Status: "disabled"}, nil
+	}
+
+	pipeline := a.Events.Status()
+	var indexerLag *float64
+	if !pipeline.NewestRow.IsZero() {
+		lag := time.Since(pipeline.NewestRow).Seconds()
+		indexerLag = &lag
+	}
+
+	sinceLastPoll := time.Since(pipeline.LastPoll)
+	switch {
+	case a.Config.ReadyMaxEventsLag > 0 && sinceLastPoll > a.Config.ReadyMaxEventsLag:
+		check := dependencyStatus{Status: "lagging", Error: "no successful poll for " + sinceLastPoll.Round(time.Second).String()}
+		if pipeline.Err != nil {
+			check.Error += ": " + pipeline.Err.Error()
+		}
+		return check, indexerLag
+	case pipeline.Err != nil:
+		// A single failed poll is retried on the next tick
+		return dependencyStatus{Status: "ok", Error: pipeline.Err.Error()}, indexerLag
+	}
+	return dependencyStatus{Status: "ok"}, indexerLag
+}
+
+// elapsedMs returns the milliseconds since start
+func elapsedMs(start time.Time) float64 {
+	return float64(time.Since(start).Microseconds()) / 1000
+}
+
+// requireDatabase answers 503 with Retry-After while the database circuit breaker is open,
+// instead of letting requests wait on a database that is down
+func (a *App) requireDatabase() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if a.DB == nil {
+			c.Next()
+			return
+		}
+
+		wait, err := a.DB.Breaker.Allow()
+		if err != nil {
+			seconds := int(math.Ceil(wait.Seconds()))
+			if seconds < 1 {
+				seconds = 1
+			}
+			c.Header("Retry-After", strconv.Itoa(seconds))
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "Database unavailable, retry in " + strconv.Itoa(seconds) + "s"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// logStartup prints the listening address and a summary of the API
+func logStartup(port string) {
+	log.Printf("🚀 Nadmon Backend started on port %s", port)
+	log.Printf("📊 Health checks: http://localhost:%s/healthz (liveness), /readyz (readiness)", port)
+	log.Printf("📈 Metrics: http://localhost:%s/metrics", port)
+	log.Printf("🔌 WebSocket: ws://localhost:%s/api/ws?token={stream token} (&encoding=msgpack for binary frames, schemas at /api/ws/schemas)", port)
+	log.Printf("📡 Server-Sent Events: http://localhost:%s/api/sse?token={stream token}", port)
+	log.Printf("📖 API docs: http://localhost:%s/docs (spec at /api/openapi.json)", port)
+	log.Printf("🏷️  API v1: every /api route under /api/v1 answers in the {data, meta, error} envelope (or send API-Version: 1)")
+	log.Printf("📋 API Documentation:")
+	log.Printf("   GET /api/players/{address}/nadmons    - Get player's NFTs")
+	log.Printf("   GET /api/players/{address}/nadmons/changes - Get NFTs changed since a sync sequence (?since_sequence=)")
+	log.Printf("   GET /api/players/{address}/profile    - Get player profile")
+	log.Printf("   PUT /api/players/{address}/profile    - Set nickname, avatar and bio (SIWE)")
+	log.Printf("   GET/POST /api/players/{address}/teams - List or save battle teams (SIWE)")
+	log.Printf("   DELETE /api/players/{address}/teams/{teamId} - Delete a saved team (SIWE)")
+	log.Printf("   GET /api/players/{address}/favorites  - Get player's starred Nadmons")
+	log.Printf("   POST/DELETE /api/players/{address}/favorites/{tokenId} - Star or unstar a Nadmon (SIWE)")
+	log.Printf("   GET/POST /api/trades                  - List or propose trade offers (SIWE)")
+	log.Printf("   GET /api/trades/{tradeId}             - Get a trade offer with the Nadmons that changed hands (SIWE)")
+	log.Printf("   POST /api/trades/{tradeId}/{action}   - Accept, reject or cancel a trade offer (SIWE)")
+	log.Printf("   GET /api/players/{address}/packs      - Get player's pack history")
+	log.Printf("   GET /api/players/{address}/packs/summary - Get pack totals per payment type and newest packs")
+	log.Printf("   GET /api/players/{address}/stats      - Get player statistics")
+	log.Printf("   GET /api/players/{address}/avatar.png - Get generated identicon avatar")
+	log.Printf("   GET /api/players/{address}/transfers  - Get player's transfer history")
+	log.Printf("   GET /api/players/{address}/dex        - Get player's Nadmondex completion")
+	log.Printf("   GET /api/players/{address}/activity   - Get player's activity feed (?types=mint,pack)")
+	log.Printf("   GET /api/players/{address}/fusion-candidates - Get which of player's NFTs can fuse or evolve")
+	log.Printf("   GET /api/players/{address}/export     - Download player's inventory and history (?format=csv|ndjson)")
+	log.Printf("   GET /api/nfts/search                  - Search every player's NFTs (?element=Fire&owner=0xdead&cursor=)")
+	log.Printf("   GET /api/nfts/{tokenId}               - Get NFT details and history")
+	log.Printf("   GET /api/nfts/{tokenId}/stats/timeline - Get NFT stats over time for charts (?metric=hp,attack)")
+	log.Printf("   GET /api/nfts/{tokenId}/lineage       - Get the Nadmons fused into an NFT, recursively (?depth=5)")
+	log.Printf("   GET /api/nfts/{tokenId}/transfers     - Get NFT ownership history")
+	log.Printf("   GET /api/nfts/{tokenId}/sales         - Get NFT marketplace sales")
+	log.Printf("   GET /api/nfts/{tokenId}/rank          - Get NFT rarity score and rank")
+	log.Printf("   GET /api/nfts/compare?ids=12,87       - Compare 2-5 NFTs: stats, deltas, ranks and history")
+	log.Printf("   GET /api/verify/ownership?address=&tokenIds= - Check which tokens an address owns (?min_rarity=)")
+	log.Printf("   GET /api/metadata/{tokenId}           - Get ERC-721 token metadata")
+	log.Printf("   GET /api/images/{tokenId}             - Get NFT artwork for its evolution stage")
+	log.Printf("   GET /api/packs/{packId}               - Get pack details with NFTs")
+	log.Printf("   GET /api/packs/{packId}/reveal        - Get pack cards in reveal order (?revealed=N opens the first N)")
+	log.Printf("   GET /api/nfts?ids=1,2,3               - Get multiple NFTs by IDs")
+	log.Printf("   GET /api/packs/recent                 - Get recent pack purchases")
+	log.Printf("   GET /api/activity?type=mint,pack      - Get the global activity feed")
+	log.Printf("   GET /api/collections                  - List collections (routes above also under /api/collections/{name})")
+	log.Printf("   GET /api/chains                       - List chains (routes above also under /api/chains/{chainId} or with ?chain=)")
+	log.Printf("   GET /api/leaderboard/collectors       - Get top collectors")
+	log.Printf("   GET /api/leaderboard/{type}           - Get evolutions, fusion, packs or rarity_score rankings")
+	log.Printf("   GET /api/leaderboard/rarest-nfts      - Get NFTs ranked by rarity score")
+	log.Printf("   GET /api/players/{address}/rank       - Get a player's collector rank and its change")
+	log.Printf("   GET /api/players/{address}/battles    - Get a player's battles and PvP record")
+	log.Printf("   GET /api/leaderboard/pvp              - Get players ranked by PvP (ELO) rating")
+	log.Printf("   GET /api/stats/game                   - Get game statistics")
+	log.Printf("   GET /api/stats/pack-distribution      - Get packs-per-player histogram")
+	log.Printf("   GET /api/stats/concentration          - Get ownership concentration metrics")
+	log.Printf("   GET /api/stats/supply                 - Get minted/burned/circulating supply by rarity and element")
+	log.Printf("   GET /api/stats/elements               - Get counts, average stats and evolution rates per element")
+	log.Printf("   GET /api/stats/rarities               - Get counts, average stats and evolution rates per rarity")
+	log.Printf("   GET /api/stats/floor?rarity=          - Get floor price, last sale and volume by rarity and element")
+	log.Printf("   GET /api/analytics/timeseries?metric= - Get mints, packs, transfers or players per hour/day")
+	log.Printf("   GET /api/analytics/packs              - Get rarity and element drop rates per payment type")
+	log.Printf("   GET /api/search/suggestions?q=        - Get matching types, elements and rarities")
+	log.Printf("   GET /api/i18n/{locale}                - Get translated labels")
+	log.Printf("   GET /api/game-data                    - Get element colors, matchups and derived-stat formulas")
+	log.Printf("   GET /api/resolve/{addressOrName}      - Resolve an address to its name service name or back")
+	log.Printf("   GET /api/status/history               - Get health history and uptime")
+	log.Printf("   POST /api/auth/nonce                  - Get a Sign-In With Ethereum nonce")
+	log.Printf("   POST /api/auth/verify                 - Exchange a signed SIWE message for a token")
+	log.Printf("   GET /api/auth/session                 - Get the address bound to a token")
+	log.Printf("   POST /api/auth/stream-token           - Issue a stream token for the private WebSocket/SSE channel")
+	log.Printf("   GET/POST /api/webhooks                - List or register webhooks (SIWE or admin key)")
+	log.Printf("   DELETE /api/webhooks/{id}             - Delete a webhook")
+	log.Printf("   GET /api/webhooks/{id}/deliveries     - Get a webhook's delivery log")
+	log.Printf("   POST /api/battles                     - Record a battle result (signed by the game server)")
+}
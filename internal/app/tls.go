@@ -0,0 +1,86 @@
+package app
+
+import (
+	"crypto/tls"
+	"log"
+	"net"
+	"net/http"
+	"strings"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// listen starts srv with plain HTTP or built-in TLS depending on config. When TLS is on
+// and HTTP_REDIRECT_PORT is set, a second server redirects plain HTTP to HTTPS and
+// answers ACME HTTP-01 challenges. The returned server, if any, must be shut down too.
+func (a *App) listen(srv *http.Server, errCh chan<- error) *http.Server {
+	if !a.Config.TLSEnabled() {
+		go serve(srv.ListenAndServe, errCh)
+		return nil
+	}
+
+	_, port, _ := net.SplitHostPort(srv.Addr)
+	var redirect http.Handler = redirectToHTTPS(port)
+
+	if len(a.Config.TLSAutocertDomains) > 0 {
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(a.Config.TLSAutocertDomains...),
+			Cache:      autocert.DirCache(a.Config.TLSAutocertCacheDir),
+			Email:      a.Config.TLSAutocertEmail,
+		}
+		srv.TLSConfig = manager.TLSConfig()
+		redirect = manager.HTTPHandler(redirect)
+		log.Printf("🔒 TLS enabled with automatic certificates for %v", a.Config.TLSAutocertDomains)
+		go serve(func() error { return srv.ListenAndServeTLS("", "") }, errCh)
+	} else {
+		srv.TLSConfig = &tls.Config{MinVersion: tls.VersionTLS12}
+		log.Printf("🔒 TLS enabled with certificate %s", a.Config.TLSCertFile)
+		go serve(func() error { return srv.ListenAndServeTLS(a.Config.TLSCertFile, a.Config.TLSKeyFile) }, errCh)
+	}
+
+	if a.Config.HTTPRedirectPort == "" {
+		return nil
+	}
+
+	// Plain HTTP is as exposed to slow clients as HTTPS, so it gets the same timeouts
+	redirectSrv := &http.Server{
+		Addr:              ":" + a.Config.HTTPRedirectPort,
+		Handler:           redirect,
+		ReadTimeout:       a.Config.HTTPReadTimeout,
+		ReadHeaderTimeout: a.Config.HTTPReadHeaderTimeout,
+		WriteTimeout:      a.Config.HTTPWriteTimeout,
+		IdleTimeout:       a.Config.HTTPIdleTimeout,
+		MaxHeaderBytes:    a.Config.HTTPMaxHeaderBytes,
+	}
+	log.Printf("↪️ Redirecting HTTP on :%s to HTTPS", a.Config.HTTPRedirectPort)
+	go serve(redirectSrv.ListenAndServe, errCh)
+	return redirectSrv
+}
+
+// serve runs a blocking listen function and reports unexpected errors
+func serve(listen func() error, errCh chan<- error) {
+	if err := listen(); err != nil && err != http.ErrServerClosed {
+		errCh <- err
+	}
+}
+
+// redirectToHTTPS permanently redirects plain HTTP requests to the same URL over HTTPS on
+// port, which is left out of the URL when it is the default 443
+func redirectToHTTPS(port string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		} else {
+			host = strings.Trim(host, "[]")
+		}
+
+		if port != "" && port != "443" {
+			host = net.JoinHostPort(host, port)
+		} else if strings.Contains(host, ":") {
+			host = "[" + host + "]" // IPv6
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+	}
+}
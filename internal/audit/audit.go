@@ -0,0 +1,163 @@
+// Package audit records which partner API key or client IP read which player's data and
+// when, for partners whose compliance rules require an access trail. Entries are queued by
+// the request middleware and written in batches, so auditing never delays a response.
+package audit
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"nadmon-backend/internal/apikeys"
+	"nadmon-backend/internal/ethaddr"
+	"nadmon-backend/internal/logging"
+	"nadmon-backend/internal/models"
+	"nadmon-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// queueSize caps the entries waiting to be written; entries beyond it are dropped and counted
+const queueSize = 8192
+
+// batchSize caps the entries written by one insert
+const batchSize = 500
+
+// pruneInterval is how often entries older than the retention are dropped
+const pruneInterval = time.Hour
+
+// Config tunes the audit log
+type Config struct {
+	FlushInterval time.Duration // how often queued entries are written
+	Retention     time.Duration // age after which entries are pruned; 0 keeps them
+}
+
+// Logger queues audit entries and writes them to the store
+type Logger struct {
+	store   repository.AuditStore
+	config  Config
+	entries chan models.AuditEntry
+	dropped atomic.Int64
+}
+
+// NewLogger creates an audit logger writing to store; call Run to start writing
+func NewLogger(store repository.AuditStore, config Config) *Logger {
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = 5 * time.Second
+	}
+	return &Logger{store: store, config: config, entries: make(chan models.AuditEntry, queueSize)}
+}
+
+// Middleware audits reads of a player's data: GET requests naming an address in the route
+// (:address) or the query (?address=). Entries are recorded once the response is written,
+// with its status.
+func (l *Logger) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			return
+		}
+		address := c.Param("address")
+		if address == "" {
+			address = c.Query("address")
+		}
+		if !ethaddr.Valid(address) {
+			return
+		}
+
+		entry := models.AuditEntry{
+			Address:     ethaddr.Normalize(address),
+			ClientIP:    c.ClientIP(),
+			RemoteAddr:  c.Request.RemoteAddr,
+			Method:      c.Request.Method,
+			Route:       c.FullPath(),
+			Status:      c.Writer.Status(),
+			RequestID:   logging.RequestID(c.Request.Context()),
+			RequestedAt: start.UTC(),
+		}
+		if value, ok := c.Get(apikeys.ContextKey); ok {
+			entry.APIKeyID = value.(*models.APIKey).ID
+		}
+		l.Record(entry)
+	}
+}
+
+// Record queues entry without blocking, dropping it when the queue is full
+func (l *Logger) Record(entry models.AuditEntry) {
+	select {
+	case l.entries <- entry:
+	default:
+		l.dropped.Add(1)
+	}
+}
+
+// Flush writes the queued entries; a batch that fails to be written is lost and logged
+func (l *Logger) Flush(ctx context.Context) error {
+	if dropped := l.dropped.Swap(0); dropped > 0 {
+		log.Printf("Warning: audit queue full, dropped %d entries", dropped)
+	}
+
+	var firstErr error
+	for {
+		batch := l.drain()
+		if len(batch) == 0 {
+			return firstErr
+		}
+		if err := l.store.RecordAuditEntries(ctx, batch); err != nil {
+			log.Printf("Warning: lost %d audit entries: %v", len(batch), err)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+}
+
+// drain takes up to batchSize queued entries
+func (l *Logger) drain() []models.AuditEntry {
+	var batch []models.AuditEntry
+	for len(batch) < batchSize {
+		select {
+		case entry := <-l.entries:
+			batch = append(batch, entry)
+		default:
+			return batch
+		}
+	}
+	return batch
+}
+
+// Run writes queued entries every flush interval and prunes expired ones until stop is
+// closed, then flushes once more
+func (l *Logger) Run(stop <-chan struct{}) {
+	flush := time.NewTicker(l.config.FlushInterval)
+	defer flush.Stop()
+	prune := time.NewTicker(pruneInterval)
+	defer prune.Stop()
+
+	l.prune()
+	for {
+		select {
+		case <-flush.C:
+			l.Flush(context.Background())
+		case <-prune.C:
+			l.prune()
+		case <-stop:
+			l.Flush(context.Background())
+			return
+		}
+	}
+}
+
+// prune drops entries older than the retention
+func (l *Logger) prune() {
+	if l.config.Retention <= 0 {
+		return
+	}
+	if _, err := l.store.PruneAuditEntries(context.Background(), time.Now().Add(-l.config.Retention)); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package audit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"nadmon-backend/internal/apikeys"
+	"nadmon-backend/internal/models"
+	"nadmon-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// memoryStore is an in-memory AuditStore recording each batch written
+type memoryStore struct {
+	mu      sync.Mutex
+	batches [][]models.AuditEntry
+}
+
+func (m *memoryStore) RecordAuditEntries(ctx context.Context, entries []models.AuditEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.batches = append(m.batches, entries)
+	return nil
+}
+
+func (m *memoryStore) GetAuditEntries(ctx context.Context, filter repository.AuditFilter, limit int) ([]models.AuditEntry, error) {
+	return nil, nil
+}
+
+func (m *memoryStore) PruneAuditEntries(ctx context.Context, before time.Time) (int64, error) {
+	return 0, nil
+}
+
+func (m *memoryStore) entries() []models.AuditEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var all []models.AuditEntry
+	for _, batch := range m.batches {
+		all = append(all, batch...)
+	}
+	return all
+}
+
+const player = "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := &memoryStore{}
+	logger := NewLogger(store, Config{})
+
+	r := gin.New()
+	if err := r.SetTrustedProxies(nil); err != nil {
+		t.Fatal(err)
+	}
+	r.Use(func(c *gin.Context) {
+		if c.GetHeader(apikeys.Header) != "" {
+			c.Set(apikeys.ContextKey, &models.APIKey{ID: 7})
+		}
+	})
+	r.Use(logger.Middleware())
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/api/players/:address/nadmons", ok)
+	r.POST("/api/players/:address/profile", ok)
+	r.GET("/api/verify/ownership", ok)
+	r.GET("/api/nfts/:tokenId", ok)
+
+	requests := []struct {
+		method, path string
+		keyed        bool
+	}{
+		{http.MethodGet, "/api/players/" + player + "/nadmons", true},
+		{http.MethodGet, "/api/verify/ownership?address=0xAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA", false},
+		{http.MethodPost, "/api/players/" + player + "/profile", false},
+		{http.MethodGet, "/api/players/not-an-address/nadmons", false},
+		{http.MethodGet, "/api/nfts/1", false},
+	}
+	for _, req := range requests {
+		httpReq := httptest.NewRequest(req.method, req.path, nil)
+		// Forged, as no proxy is trusted
+		httpReq.Header.Set("X-Forwarded-For", "6.6.6.6")
+		if req.keyed {
+			httpReq.Header.Set(apikeys.Header, "nk_test")
+		}
+		r.ServeHTTP(httptest.NewRecorder(), httpReq)
+	}
+
+	if err := logger.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	entries := store.entries()
+	if len(entries) != 2 {
+		t.Fatalf("expected the two player data reads audited, got %+v", entries)
+	}
+	if e := entries[0]; e.Address != player || e.APIKeyID != 7 || e.Route != "/api/players/:address/nadmons" || e.Status != http.StatusOK || e.ClientIP == "" || e.RemoteAddr == "" {
+		t.Errorf("unexpected keyed entry %+v", e)
+	}
+	if e := entries[0]; e.ClientIP != "192.0.2.1" || e.RemoteAddr != "192.0.2.1:1234" {
+		t.Errorf("expected the connection's address rather than the forged X-Forwarded-For, got %+v", e)
+	}
+	if e := entries[1]; e.Address != player || e.APIKeyID != 0 || e.Route != "/api/verify/ownership" {
+		t.Errorf("expected the ?address= read with a normalized address and no key, got %+v", e)
+	}
+}
+
+func TestFlushBatchesAndDrops(t *testing.T) {
+	store := &memoryStore{}
+	logger := NewLogger(store, Config{})
+
+	for i := 0; i < queueSize+10; i++ {
+		logger.Record(models.AuditEntry{Address: player, RequestedAt: time.Now()})
+	}
+	if logger.dropped.Load() != 10 {
+		t.Errorf("expected 10 entries dropped with a full queue, got %d", logger.dropped.Load())
+	}
+
+	if err := logger.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(store.entries()) != queueSize {
+		t.Errorf("expected %d entries written, got %d", queueSize, len(store.entries()))
+	}
+	for _, batch := range store.batches {
+		if len(batch) > batchSize {
+			t.Fatalf("expected batches of at most %d entries, got %d", batchSize, len(batch))
+		}
+	}
+	if logger.dropped.Load() != 0 {
+		t.Errorf("expected the drop count reset by the flush, got %d", logger.dropped.Load())
+	}
+}
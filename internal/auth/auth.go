@@ -0,0 +1,267 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"nadmon-backend/internal/cache"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// nonceTTL is how long a nonce can be used to sign in
+const nonceTTL = 10 * time.Minute
+
+// maxNonces caps the nonces awaiting a sign-in; issuing one more evicts the oldest, so a
+// client looping on the public nonce endpoint can't grow memory without bound
+const maxNonces = 100000
+
+// AddressKey is the gin context key holding the authenticated wallet address
+const AddressKey = "auth.address"
+
+// AdminKey is the gin context key set to true when a request authenticated with the admin
+// API key instead of a wallet
+const AdminKey = "auth.admin"
+
+// StreamAudience marks stream tokens, which only open real-time connections and are not
+// accepted as session tokens
+const StreamAudience = "stream"
+
+// Service issues SIWE nonces and verifies signed messages into JWTs
+type Service struct {
+	secret         []byte
+	domain         string // expected message domain; empty accepts any
+	tokenTTL       time.Duration
+	streamTokenTTL time.Duration
+
+	mu     sync.Mutex // makes consuming a nonce atomic
+	nonces *cache.LRU[string, struct{}]
+}
+
+// NewService creates an auth service signing JWTs with secret
+func NewService(secret []byte, domain string, tokenTTL, streamTokenTTL time.Duration) *Service {
+	return &Service{
+		secret:         secret,
+		domain:         domain,
+		tokenTTL:       tokenTTL,
+		streamTokenTTL: streamTokenTTL,
+		nonces:         cache.NewLRU[string, struct{}](maxNonces, nonceTTL),
+	}
+}
+
+// RandomSecret returns a random JWT signing secret, used when none is configured
+func RandomSecret() []byte {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic(err)
+	}
+	return secret
+}
+
+// Nonce issues a single-use nonce for a SIWE message
+func (s *Service) Nonce() (string, time.Time, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	nonce := hex.EncodeToString(b)
+	expiresAt := time.Now().Add(nonceTTL)
+	s.nonces.Add(nonce, struct{}{})
+	return nonce, expiresAt, nil
+}
+
+// consumeNonce removes a nonce and reports whether it was valid
+func (s *Service) consumeNonce(nonce string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.nonces.Get(nonce)
+	s.nonces.Remove(nonce)
+	return ok
+}
+
+// Verify checks a signed SIWE message and returns a JWT bound to the signing address
+func (s *Service) Verify(message, signature string) (token string, address string, expiresAt time.Time, err error) {
+	msg, err := ParseSIWEMessage(message)
+	if err != nil {
+		return "", "", time.Time{}, err
+	}
+	if s.domain != "" && msg.Domain != s.domain {
+		return "", "", time.Time{}, fmt.Errorf("unexpected domain %q", msg.Domain)
+	}
+	if err := msg.ValidAt(time.Now()); err != nil {
+		return "", "", time.Time{}, err
+	}
+
+	signer, err := RecoverAddress(message, signature)
+	if err != nil {
+		return "", "", time.Time{}, err
+	}
+	if !strings.EqualFold(signer, msg.Address) {
+		return "", "", time.Time{}, errors.New("signature does not match address")
+	}
+
+	// Consume the nonce last so a bad signature doesn't burn it
+	if !s.consumeNonce(msg.Nonce) {
+		return "", "", time.Time{}, errors.New("unknown or expired nonce")
+	}
+
+	address = strings.ToLower(msg.Address)
+	expiresAt = time.Now().Add(s.tokenTTL)
+	token, err = jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   address,
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}).SignedString(s.secret)
+	if err != nil {
+		return "", "", time.Time{}, fmt.Errorf("failed to sign token: %w", err)
+	}
+
+	return token, address, expiresAt, nil
+}
+
+// ParseToken validates a session JWT and returns the wallet address it is bound to
+func (s *Service) ParseToken(token string) (string, error) {
+	claims, err := s.parse(token)
+	if err != nil {
+		return "", err
+	}
+	if len(claims.Audience) > 0 {
+		return "", errors.New("not a session token")
+	}
+	return claims.Subject, nil
+}
+
+// IssueStreamToken returns a short-lived token that lets address open its private
+// WebSocket or event stream. It is passed as a query parameter, where it can end up in
+// proxy logs, so it expires quickly and cannot be used as a session token.
+func (s *Service) IssueStreamToken(address string) (string, time.Time, error) {
+	expiresAt := time.Now().Add(s.streamTokenTTL)
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   strings.ToLower(address),
+		Audience:  jwt.ClaimStrings{StreamAudience},
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}).SignedString(s.secret)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign stream token: %w", err)
+	}
+	return token, expiresAt, nil
+}
+
+// ParseStreamToken validates a token from IssueStreamToken and returns its address
+func (s *Service) ParseStreamToken(token string) (string, error) {
+	claims, err := s.parse(token, jwt.WithAudience(StreamAudience))
+	if err != nil {
+		return "", err
+	}
+	return claims.Subject, nil
+}
+
+// parse verifies a token's signature and expiry
+func (s *Service) parse(token string, opts ...jwt.ParserOption) (*jwt.RegisteredClaims, error) {
+	var claims jwt.RegisteredClaims
+	opts = append(opts, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	_, err := jwt.ParseWithClaims(token, &claims, func(*jwt.Token) (interface{}, error) {
+		return s.secret, nil
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &claims, nil
+}
+
+// tokenFromRequest reads the bearer token from the Authorization header. Session tokens are
+// never read from the query string, where they would end up in proxy logs; streams take a
+// stream token there instead.
+func tokenFromRequest(c *gin.Context) string {
+	if header := c.GetHeader("Authorization"); strings.HasPrefix(header, "Bearer ") {
+		return strings.TrimPrefix(header, "Bearer ")
+	}
+	return ""
+}
+
+// RequireAuth rejects requests without a valid token and stores the authenticated address
+func (s *Service) RequireAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		address, err := s.ParseToken(tokenFromRequest(c))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			return
+		}
+		c.Set(AddressKey, address)
+		c.Next()
+	}
+}
+
+// RequireOwner is RequireAuth plus a check that the :address route parameter is the
+// authenticated wallet
+func (s *Service) RequireOwner() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		address, err := s.ParseToken(tokenFromRequest(c))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			return
+		}
+		if !strings.EqualFold(address, c.Param("address")) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Token does not belong to this address"})
+			return
+		}
+		c.Set(AddressKey, address)
+		c.Next()
+	}
+}
+
+// RequireAPIKey rejects requests that don't send key as a bearer token; an empty key rejects
+// every request
+func RequireAPIKey(key string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if key == "" || subtle.ConstantTimeCompare([]byte(token), []byte(key)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// MarkAPIKey sets AdminKey on requests sending key as a bearer token and lets every request
+// through, so handlers can offer admin-only options; an empty key marks no request
+func MarkAPIKey(key string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if key != "" && subtle.ConstantTimeCompare([]byte(token), []byte(key)) == 1 {
+			c.Set(AdminKey, true)
+		}
+		c.Next()
+	}
+}
+
+// RequireAuthOrAPIKey accepts either the admin API key or a session token. Admin requests set
+// AdminKey, wallet requests AddressKey; an empty key only accepts session tokens.
+func (s *Service) RequireAuthOrAPIKey(key string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		// Like RequireAPIKey, the key is only accepted in the header, never the query string
+		header := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if key != "" && subtle.ConstantTimeCompare([]byte(header), []byte(key)) == 1 {
+			c.Set(AdminKey, true)
+			c.Next()
+			return
+		}
+		address, err := s.ParseToken(tokenFromRequest(c))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Authentication required"})
+			return
+		}
+		c.Set(AddressKey, address)
+		c.Next()
+	}
+}
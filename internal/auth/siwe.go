@@ -0,0 +1,147 @@
+package auth
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"nadmon-backend/internal/ethaddr"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"golang.org/x/crypto/sha3"
+)
+
+// SIWEMessage holds the fields of an EIP-4361 Sign-In With Ethereum message
+type SIWEMessage struct {
+	Domain         string
+	Address        string
+	Statement      string
+	URI            string
+	Version        string
+	ChainID        string
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime *time.Time
+	NotBefore      *time.Time
+}
+
+const siweHeaderSuffix = " wants you to sign in with your Ethereum account:"
+
+// ParseSIWEMessage parses the plain-text EIP-4361 message a wallet signed
+func ParseSIWEMessage(text string) (*SIWEMessage, error) {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+	if len(lines) < 3 || !strings.HasSuffix(lines[0], siweHeaderSuffix) {
+		return nil, errors.New("not a Sign-In With Ethereum message")
+	}
+
+	msg := &SIWEMessage{
+		Domain:  strings.TrimSuffix(lines[0], siweHeaderSuffix),
+		Address: strings.TrimSpace(lines[1]),
+	}
+	if !ethaddr.Valid(msg.Address) {
+		return nil, fmt.Errorf("invalid address %q", msg.Address)
+	}
+
+	for _, line := range lines[2:] {
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			if line != "" && msg.Statement == "" {
+				msg.Statement = line
+			}
+			continue
+		}
+
+		var err error
+		switch key {
+		case "URI":
+			msg.URI = value
+		case "Version":
+			msg.Version = value
+		case "Chain ID":
+			msg.ChainID = value
+		case "Nonce":
+			msg.Nonce = value
+		case "Issued At":
+			msg.IssuedAt, err = time.Parse(time.RFC3339, value)
+		case "Expiration Time":
+			msg.ExpirationTime, err = parseOptionalTime(value)
+		case "Not Before":
+			msg.NotBefore, err = parseOptionalTime(value)
+		default:
+			if msg.Statement == "" && msg.URI == "" {
+				msg.Statement = line
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", key, err)
+		}
+	}
+
+	if msg.Version != "1" {
+		return nil, fmt.Errorf("unsupported version %q", msg.Version)
+	}
+	if msg.Nonce == "" || msg.URI == "" || msg.ChainID == "" {
+		return nil, errors.New("message is missing URI, chain ID or nonce")
+	}
+	return msg, nil
+}
+
+func parseOptionalTime(value string) (*time.Time, error) {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// ValidAt checks the message's validity window
+func (m *SIWEMessage) ValidAt(now time.Time) error {
+	if m.ExpirationTime != nil && now.After(*m.ExpirationTime) {
+		return errors.New("message has expired")
+	}
+	if m.NotBefore != nil && now.Before(*m.NotBefore) {
+		return errors.New("message is not valid yet")
+	}
+	return nil
+}
+
+// RecoverAddress returns the lowercased address that produced an EIP-191 personal_sign
+// signature (0x-prefixed hex, 65 bytes R || S || V) over message
+func RecoverAddress(message, signature string) (string, error) {
+	sig, err := hex.DecodeString(strings.TrimPrefix(signature, "0x"))
+	if err != nil || len(sig) != 65 {
+		return "", errors.New("signature must be 65 bytes of hex")
+	}
+
+	// Wallets encode the recovery id as 27/28 (legacy) or 0/1
+	v := sig[64]
+	if v >= 27 {
+		v -= 27
+	}
+	if v > 1 {
+		return "", errors.New("invalid signature recovery id")
+	}
+
+	// Compact format expected by the recovery: <27 + recovery id> || R || S
+	compact := make([]byte, 65)
+	compact[0] = 27 + v
+	copy(compact[1:], sig[:64])
+
+	prefixed := fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(message), message)
+	publicKey, _, err := ecdsa.RecoverCompact(compact, keccak256([]byte(prefixed)))
+	if err != nil {
+		return "", fmt.Errorf("failed to recover signer: %w", err)
+	}
+
+	// The address is the last 20 bytes of the Keccak-256 of the uncompressed key without its 0x04 prefix
+	hash := keccak256(publicKey.SerializeUncompressed()[1:])
+	return "0x" + hex.EncodeToString(hash[12:]), nil
+}
+
+func keccak256(data []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(data)
+	return h.Sum(nil)
+}
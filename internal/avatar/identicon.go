@@ -0,0 +1,137 @@
+package avatar
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strings"
+	"sync"
+)
+
+const (
+	gridSize = 5  // cells per row and column
+	cellSize = 40 // pixels per cell
+	padding  = 20 // pixels around the grid
+
+	// maxCached bounds the number of rendered avatars kept in memory
+	maxCached = 10000
+)
+
+var background = color.RGBA{R: 0xf0, G: 0xf0, B: 0xf0, A: 0xff}
+
+// Identicon renders a deterministic, horizontally symmetric 5x5 identicon PNG for an address
+func Identicon(address string) ([]byte, error) {
+	hash := sha256.Sum256([]byte(strings.ToLower(address)))
+	fg := hueColor(float64(uint16(hash[0])<<8|uint16(hash[1])) / 65535 * 360)
+
+	size := gridSize*cellSize + 2*padding
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: background}, image.Point{}, draw.Src)
+
+	// The left three columns come from the hash, the right two mirror them
+	half := (gridSize + 1) / 2
+	for row := 0; row < gridSize; row++ {
+		for col := 0; col < half; col++ {
+			if hash[2+row*half+col]%2 == 0 {
+				continue
+			}
+			fillCell(img, row, col, fg)
+			fillCell(img, row, gridSize-1-col, fg)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode identicon: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// fillCell paints one grid cell
+func fillCell(img *image.RGBA, row, col int, c color.RGBA) {
+	x0, y0 := padding+col*cellSize, padding+row*cellSize
+	for y := y0; y < y0+cellSize; y++ {
+		for x := x0; x < x0+cellSize; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
+
+// hueColor converts a hue in degrees to a saturated, mid-lightness color
+func hueColor(hue float64) color.RGBA {
+	const s, l = 0.65, 0.5
+	chroma := (1 - abs(2*l-1)) * s
+	x := chroma * (1 - abs(mod(hue/60, 2)-1))
+	m := l - chroma/2
+
+	var r, g, b float64
+	switch {
+	case hue < 60:
+		r, g, b = chroma, x, 0
+	case hue < 120:
+		r, g, b = x, chroma, 0
+	case hue < 180:
+		r, g, b = 0, chroma, x
+	case hue < 240:
+		r, g, b = 0, x, chroma
+	case hue < 300:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+
+	return color.RGBA{R: uint8((r + m) * 255), G: uint8((g + m) * 255), B: uint8((b + m) * 255), A: 0xff}
+}
+
+func abs(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+func mod(a, b float64) float64 {
+	return a - b*float64(int(a/b))
+}
+
+// Cache keeps rendered identicons in memory, keyed by lowercased address
+type Cache struct {
+	mu     sync.RWMutex
+	images map[string][]byte
+}
+
+// NewCache creates an empty identicon cache
+func NewCache() *Cache {
+	return &Cache{images: make(map[string][]byte)}
+}
+
+// Get returns the identicon for an address, rendering and caching it on first use
+func (c *Cache) Get(address string) ([]byte, error) {
+	key := strings.ToLower(address)
+
+	c.mu.RLock()
+	img, ok := c.images[key]
+	c.mu.RUnlock()
+	if ok {
+		return img, nil
+	}
+
+	img, err := Identicon(key)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	// Identicons are cheap to regenerate, so simply start over when the cache is full
+	if len(c.images) >= maxCached {
+		c.images = make(map[string][]byte)
+	}
+	c.images[key] = img
+	c.mu.Unlock()
+
+	return img, nil
+}
@@ -0,0 +1,106 @@
+package chaos
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Config controls which faults are injected and how often. Rates are probabilities in 0..1.
+type Config struct {
+	DBLatency     time.Duration // maximum artificial delay added to a query
+	DBLatencyRate float64
+	ErrorRate     float64 // share of API requests answered with a random 5xx
+	WSDropRate    float64 // share of WebSocket messages silently dropped
+}
+
+// Enabled reports whether any fault is configured
+func (c Config) Enabled() bool {
+	return (c.DBLatency > 0 && c.DBLatencyRate > 0) || c.ErrorRate > 0 || c.WSDropRate > 0
+}
+
+// hit returns true with probability rate
+func hit(rate float64) bool {
+	return rate > 0 && rand.Float64() < rate
+}
+
+// injectedStatuses are the 5xx responses returned by the error fault
+var injectedStatuses = []int{
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// Middleware answers a share of requests with a random 5xx before they reach the handlers
+func Middleware(cfg Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !hit(cfg.ErrorRate) {
+			c.Next()
+			return
+		}
+
+		status := injectedStatuses[rand.Intn(len(injectedStatuses))]
+		c.Header("X-Chaos-Injected", "error")
+		c.AbortWithStatusJSON(status, gin.H{"error": "Injected fault: " + http.StatusText(status)})
+	}
+}
+
+// DB wraps a database connection and delays a share of queries
+type DB struct {
+	*sql.DB
+	cfg Config
+}
+
+// WrapDB returns a connection that injects query latency according to cfg
+func WrapDB(db *sql.DB, cfg Config) *DB {
+	return &DB{DB: db, cfg: cfg}
+}
+
+// delay sleeps for a random duration up to DBLatency, honoring context cancellation
+func (d *DB) delay(ctx context.Context) {
+	if d.cfg.DBLatency <= 0 || !hit(d.cfg.DBLatencyRate) {
+		return
+	}
+
+	select {
+	case <-time.After(time.Duration(rand.Int63n(int64(d.cfg.DBLatency)))):
+	case <-ctx.Done():
+	}
+}
+
+func (d *DB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	d.delay(ctx)
+	return d.DB.ExecContext(ctx, query, args...)
+}
+
+func (d *DB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	d.delay(ctx)
+	return d.DB.PrepareContext(ctx, query)
+}
+
+func (d *DB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	d.delay(ctx)
+	return d.DB.QueryContext(ctx, query, args...)
+}
+
+func (d *DB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	d.delay(ctx)
+	return d.DB.QueryRowContext(ctx, query, args...)
+}
+
+// ShouldDropMessage reports whether a WebSocket message should be dropped
+func (c Config) ShouldDropMessage() bool {
+	return hit(c.WSDropRate)
+}
+
+// LogEnabled prints the active faults at startup
+func (c Config) LogEnabled() {
+	log.Printf("💥 Chaos mode enabled: db latency up to %s on %.0f%% of queries, %.0f%% 5xx responses, %.0f%% dropped WS messages",
+		c.DBLatency, c.DBLatencyRate*100, c.ErrorRate*100, c.WSDropRate*100)
+}
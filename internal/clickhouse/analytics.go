@@ -0,0 +1,160 @@
+package clickhouse
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"nadmon-backend/internal/models"
+)
+
+// Analytics serves the heavy aggregate queries from the ClickHouse mirror
+type Analytics struct {
+	client *Client
+
+	// excluded is the ClickHouse array literal of lowercased addresses left out of concentration metrics
+	excluded string
+}
+
+// NewAnalytics creates a ClickHouse-backed analytics store. Holders in excluded
+// (treasury, deployer, escrow) are left out of concentration metrics.
+func NewAnalytics(client *Client, excluded []string) *Analytics {
+	quoted := make([]string, len(excluded))
+	for i, address := range excluded {
+		quoted[i] = "'" + strings.ToLower(address) + "'"
+	}
+	return &Analytics{client: client, excluded: "[" + strings.Join(quoted, ",") + "]"}
+}
+
+// currentHolders yields one row per live token with its current holder
+const currentHolders = `
+	SELECT m.tokenId AS tokenId, if(t.owner != '', t.owner, m.owner) AS holder
+	FROM nadmon_mints AS m FINAL
+	LEFT JOIN (
+		SELECT tokenId, argMax(to, ts) AS owner
+		FROM nadmon_transfers FINAL
+		GROUP BY tokenId
+	) AS t ON m.tokenId = t.tokenId
+	WHERE holder != '0x0000000000000000000000000000000000000000'
+`
+
+// GetPackDistribution retrieves a histogram of how many packs each player has bought
+func (a *Analytics) GetPackDistribution(ctx context.Context) (*models.PackDistribution, error) {
+	var row struct {
+		Single  int     `json:"single"`
+		Small   int     `json:"small"`
+		Medium  int     `json:"medium"`
+		Whale   int     `json:"whale"`
+		Players int     `json:"players"`
+		Packs   int     `json:"packs"`
+		Mean    float64 `json:"mean"`
+		Median  float64 `json:"median"`
+	}
+
+	query := `
+		SELECT
+			countIf(packs = 1) AS single,
+			countIf(packs BETWEEN 2 AND 5) AS small,
+			countIf(packs BETWEEN 6 AND 20) AS medium,
+			countIf(packs > 20) AS whale,
+			count() AS players,
+			sum(packs) AS packs,
+			if(players = 0, 0, avg(packs)) AS mean,
+			if(players = 0, 0, quantileExactInclusive(0.5)(packs)) AS median
+		FROM (SELECT player, count() AS packs FROM nadmon_packs FINAL GROUP BY player)
+	`
+	if err := a.client.QueryRow(ctx, query, nil, &row); err != nil {
+		return nil, fmt.Errorf("failed to query pack distribution: %w", err)
+	}
+
+	return models.NewPackDistribution(
+		[4]int{row.Single, row.Small, row.Medium, row.Whale},
+		row.Players, row.Packs, row.Mean, row.Median,
+	), nil
+}
+
+// GetOwnershipConcentration computes the supply share of the top holders and the Gini coefficient
+func (a *Analytics) GetOwnershipConcentration(ctx context.Context) (*models.OwnershipConcentration, error) {
+	query := `
+		SELECT count() AS balance FROM (` + currentHolders + `)
+		WHERE NOT has({excluded:Array(String)}, lower(holder))
+		GROUP BY holder
+		ORDER BY balance DESC
+	`
+	params := map[string]string{"excluded": a.excluded}
+
+	var balances []int
+	err := a.client.Query(ctx, query, params, func(line []byte) error {
+		var row struct {
+			Balance int `json:"balance"`
+		}
+		if err := json.Unmarshal(line, &row); err != nil {
+			return err
+		}
+		balances = append(balances, row.Balance)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query holder balances: %w", err)
+	}
+
+	return models.NewOwnershipConcentration(balances), nil
+}
+
+// timeSeriesTables maps time-series metrics to mirrored tables
+var timeSeriesTables = map[string]string{
+	"mints":     "nadmon_mints",
+	"packs":     "nadmon_packs",
+	"transfers": "nadmon_transfers",
+	"players":   "nadmon_packs",
+}
+
+// GetEventTimeSeries counts events of a metric, or distinct pack buyers for players, per hour or day
+func (a *Analytics) GetEventTimeSeries(ctx context.Context, metric, interval string, from, to time.Time) ([]models.TimeSeriesPoint, error) {
+	table, ok := timeSeriesTables[metric]
+	if !ok {
+		return nil, fmt.Errorf("unknown metric %q", metric)
+	}
+	count := "count()"
+	if metric == models.MetricPlayers {
+		count = "uniqExact(player)"
+	}
+
+	bucket := map[string]string{"hour": "toStartOfHour", "day": "toStartOfDay"}[interval]
+	if bucket == "" {
+		return nil, fmt.Errorf("unknown interval %q", interval)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT toUnixTimestamp(%s(ts, 'UTC')) AS bucket, %s AS count
+		FROM %s FINAL
+		WHERE ts >= {from:DateTime64(6)} AND ts < {to:DateTime64(6)}
+		GROUP BY bucket
+		ORDER BY bucket
+	`, bucket, count, table)
+
+	params := map[string]string{
+		"from": from.UTC().Format("2006-01-02 15:04:05.000000"),
+		"to":   to.UTC().Format("2006-01-02 15:04:05.000000"),
+	}
+
+	points := []models.TimeSeriesPoint{}
+	err := a.client.Query(ctx, query, params, func(line []byte) error {
+		var row struct {
+			Bucket int64 `json:"bucket"`
+			Count  int64 `json:"count"`
+		}
+		if err := json.Unmarshal(line, &row); err != nil {
+			return err
+		}
+		points = append(points, models.TimeSeriesPoint{Bucket: time.Unix(row.Bucket, 0).UTC(), Count: row.Count})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s time series: %w", metric, err)
+	}
+
+	return points, nil
+}
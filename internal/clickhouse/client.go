@@ -0,0 +1,128 @@
+package clickhouse
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client talks to ClickHouse over its HTTP interface
+type Client struct {
+	baseURL  string
+	database string
+	user     string
+	password string
+	http     *http.Client
+}
+
+// NewClient creates a ClickHouse HTTP client
+func NewClient(baseURL, database, user, password string) *Client {
+	return &Client{
+		baseURL:  strings.TrimRight(baseURL, "/"),
+		database: database,
+		user:     user,
+		password: password,
+		http:     &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// do sends a query with an optional request body and named parameters ({name:Type} placeholders).
+// Cancelling ctx aborts the HTTP request, which makes ClickHouse cancel the query.
+func (c *Client) do(ctx context.Context, query string, body io.Reader, params map[string]string) (*http.Response, error) {
+	values := url.Values{}
+	values.Set("database", c.database)
+	// Return 64-bit integers as JSON numbers instead of strings
+	values.Set("output_format_json_quote_64bit_integers", "0")
+	for name, value := range params {
+		values.Set("param_"+name, value)
+	}
+
+	var req *http.Request
+	var err error
+	if body == nil {
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/?"+values.Encode(), strings.NewReader(query))
+	} else {
+		values.Set("query", query)
+		req, err = http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/?"+values.Encode(), body)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if c.user != "" {
+		req.Header.Set("X-ClickHouse-User", c.user)
+		req.Header.Set("X-ClickHouse-Key", c.password)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return nil, fmt.Errorf("clickhouse: %s", strings.TrimSpace(string(message)))
+	}
+	return resp, nil
+}
+
+// Exec runs a statement that returns no rows
+func (c *Client) Exec(ctx context.Context, query string) error {
+	resp, err := c.do(ctx, query, nil, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Insert streams JSONEachRow data into a table
+func (c *Client) Insert(ctx context.Context, table string, rows io.Reader) error {
+	resp, err := c.do(ctx, "INSERT INTO "+table+" FORMAT JSONEachRow", rows, nil)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Query runs a SELECT and decodes each JSONEachRow line with scan
+func (c *Client) Query(ctx context.Context, query string, params map[string]string, scan func(line []byte) error) error {
+	resp, err := c.do(ctx, query+" FORMAT JSONEachRow", nil, params)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		if err := scan(scanner.Bytes()); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// QueryRow runs a SELECT returning a single row and decodes it into v
+func (c *Client) QueryRow(ctx context.Context, query string, params map[string]string, v interface{}) error {
+	found := false
+	err := c.Query(ctx, query, params, func(line []byte) error {
+		found = true
+		return json.Unmarshal(line, v)
+	})
+	if err == nil && !found {
+		return fmt.Errorf("clickhouse: query returned no rows")
+	}
+	return err
+}
+
+// Ping checks that the server is reachable
+func (c *Client) Ping(ctx context.Context) error {
+	return c.Exec(ctx, "SELECT 1")
+}
@@ -0,0 +1,179 @@
+package clickhouse
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// mirroredTable describes how an Envio table is mirrored into ClickHouse
+type mirroredTable struct {
+	name   string // ClickHouse table
+	source string // Envio table
+	ddl    string // ClickHouse column definitions
+	json   string // Postgres json_build_object arguments producing one ClickHouse row
+}
+
+// syncBatchSize caps how many rows are copied per insert
+const syncBatchSize = 10000
+
+var mirroredTables = []mirroredTable{
+	{
+		name:   "nadmon_mints",
+		source: `"NadmonNFT_NadmonMinted"`,
+		ddl: `id String, owner String, tokenId UInt64, packId UInt64,
+			nadmonType LowCardinality(String), element LowCardinality(String), rarity LowCardinality(String),
+			hp UInt32, attack UInt32, defense UInt32, crit UInt32, fusion UInt32, evo UInt32,
+			ts DateTime64(6)`,
+		json: `'id', id, 'owner', LOWER(owner), 'tokenId', "tokenId"::bigint, 'packId', "packId"::bigint,
+			'nadmonType', "nadmonType", 'element', element, 'rarity', rarity,
+			'hp', hp::bigint, 'attack', attack::bigint, 'defense', defense::bigint,
+			'crit', crit::bigint, 'fusion', fusion::bigint, 'evo', evo::bigint`,
+	},
+	{
+		name:   "nadmon_packs",
+		source: `"NadmonNFT_PackMinted"`,
+		ddl: `id String, player String, packId UInt64, tokenIds Array(UInt64),
+			paymentType LowCardinality(String), ts DateTime64(6)`,
+		json: `'id', id, 'player', LOWER(player), 'packId', "packId"::bigint,
+			'tokenIds', "tokenIds"::bigint[], 'paymentType', "paymentType"`,
+	},
+	{
+		name:   "nadmon_transfers",
+		source: `"NadmonNFT_Transfer"`,
+		ddl:    `id String, from String, to String, tokenId UInt64, ts DateTime64(6)`,
+		json:   `'id', id, 'from', LOWER("from"), 'to', LOWER("to"), 'tokenId', "tokenId"::bigint`,
+	},
+	{
+		name:   "nadmon_stats_changes",
+		source: `"NadmonNFT_StatsChanged"`,
+		ddl: `id String, tokenId UInt64, sequence UInt64, changeType LowCardinality(String),
+			newFusion UInt32, newEvo UInt32, ts DateTime64(6)`,
+		json: `'id', id, 'tokenId', "tokenId"::bigint, 'sequence', sequence::bigint, 'changeType', "changeType",
+			'newFusion', "newFusion"::bigint, 'newEvo', "newEvo"::bigint`,
+	},
+}
+
+// Sink mirrors Envio events from Postgres into ClickHouse
+type Sink struct {
+	client *Client
+	db     *sql.DB
+}
+
+// NewSink creates a sink copying from the Envio database into ClickHouse
+func NewSink(client *Client, db *sql.DB) *Sink {
+	return &Sink{client: client, db: db}
+}
+
+// Setup creates the ClickHouse tables. ReplacingMergeTree keyed by the Envio row id makes
+// re-copied rows idempotent.
+func (s *Sink) Setup() error {
+	for _, table := range mirroredTables {
+		ddl := fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (%s) ENGINE = ReplacingMergeTree ORDER BY id`, table.name, table.ddl)
+		if err := s.client.Exec(context.Background(), ddl); err != nil {
+			return fmt.Errorf("failed to create clickhouse table %s: %w", table.name, err)
+		}
+	}
+	return nil
+}
+
+// Sync copies Envio rows newer than each ClickHouse table's latest row
+func (s *Sink) Sync() error {
+	for _, table := range mirroredTables {
+		if err := s.syncTable(table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// syncTable copies a table's new rows in pages keyed on (db_write_timestamp, id), starting
+// after the latest row in ClickHouse and continuing until a page comes back short. Envio
+// writes a whole batch with one timestamp, so paging on the timestamp alone would stall on
+// batches larger than a page.
+func (s *Sink) syncTable(table mirroredTable) error {
+	// Zero values on an empty table: 1970-01-01 and "", before every row
+	var cursor struct {
+		TS string `json:"ts"`
+		ID string `json:"id"`
+	}
+	if err := s.client.QueryRow(context.Background(), "SELECT toString(tupleElement(max((ts, id)), 1)) AS ts, tupleElement(max((ts, id)), 2) AS id FROM "+table.name, nil, &cursor); err != nil {
+		return fmt.Errorf("failed to read %s watermark: %w", table.name, err)
+	}
+
+	// Ids compare bytewise (COLLATE "C") as they do in ClickHouse
+	query := fmt.Sprintf(`
+		SELECT json_build_object(%s, 'ts', to_char(db_write_timestamp, 'YYYY-MM-DD HH24:MI:SS.US'))::text,
+			to_char(db_write_timestamp, 'YYYY-MM-DD HH24:MI:SS.US'), id
+		FROM %s
+		WHERE (db_write_timestamp, id COLLATE "C") > ($1::timestamp, $2 COLLATE "C")
+		ORDER BY db_write_timestamp, id COLLATE "C"
+		LIMIT %d
+	`, table.json, table.source, syncBatchSize)
+
+	for {
+		count, err := s.syncPage(table, query, &cursor.TS, &cursor.ID)
+		if err != nil {
+			return err
+		}
+		if count < syncBatchSize {
+			return nil
+		}
+	}
+}
+
+// syncPage copies one page of rows after the (ts, id) cursor and moves the cursor to its
+// last row
+func (s *Sink) syncPage(table mirroredTable, query string, ts, id *string) (int, error) {
+	rows, err := s.db.Query(query, *ts, *id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read %s for clickhouse: %w", table.source, err)
+	}
+	defer rows.Close()
+
+	var batch strings.Builder
+	count := 0
+	var lastTS, lastID string
+	for rows.Next() {
+		var line string
+		if err := rows.Scan(&line, &lastTS, &lastID); err != nil {
+			return 0, fmt.Errorf("failed to scan %s row: %w", table.source, err)
+		}
+		batch.WriteString(line)
+		batch.WriteByte('\n')
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if count == 0 {
+		return 0, nil
+	}
+
+	if err := s.client.Insert(context.Background(), table.name, strings.NewReader(batch.String())); err != nil {
+		return 0, fmt.Errorf("failed to insert into %s: %w", table.name, err)
+	}
+	*ts, *id = lastTS, lastID
+	return count, nil
+}
+
+// Run syncs on every tick until stop is closed
+func (s *Sink) Run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.Sync(); err != nil {
+			log.Printf("Warning: clickhouse sync failed: %v", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// IndexRebuild reports the outcome of rebuilding one index
+type IndexRebuild struct {
+	Name       string  `json:"name"`
+	DurationMs float64 `json:"duration_ms"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// RebuildIndexes creates missing app-owned indexes and rebuilds every one of them with
+// REINDEX CONCURRENTLY, which doesn't block the indexer's writes. A failing index is reported
+// in its result and the remaining indexes are still rebuilt.
+func (edb *EnvioDB) RebuildIndexes(ctx context.Context) ([]IndexRebuild, error) {
+	if err := edb.CreateIndexes(); err != nil {
+		return nil, fmt.Errorf("failed to create indexes: %w", err)
+	}
+
+	results := make([]IndexRebuild, 0, len(envioIndexes))
+	err := edb.withoutStatementTimeout(ctx, func(conn *sql.Conn) error {
+		for _, index := range envioIndexes {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			start := time.Now()
+			result := IndexRebuild{Name: index.name}
+			if _, err := conn.ExecContext(ctx, "REINDEX INDEX CONCURRENTLY "+index.name); err != nil {
+				result.Error = err.Error()
+			}
+			result.DurationMs = float64(time.Since(start).Microseconds()) / 1000
+			results = append(results, result)
+		}
+		return nil
+	})
+	if err != nil {
+		return results, err
+	}
+
+	log.Printf("🔧 Rebuilt %d indexes on Envio tables", len(results))
+	return results, nil
+}
+
+// SlowQuery is a normalized statement from pg_stat_statements with its timings
+type SlowQuery struct {
+	Query       string  `json:"query"`
+	Calls       int64   `json:"calls"`
+	MeanMs      float64 `json:"mean_ms"`
+	MaxMs       float64 `json:"max_ms"`
+	TotalMs     float64 `json:"total_ms"`
+	Rows        int64   `json:"rows"`
+	SharedReads int64   `json:"shared_blocks_read"`
+}
+
+// ErrStatStatementsUnavailable is returned by SlowQueries when pg_stat_statements is not installed
+var ErrStatStatementsUnavailable = errors.New("pg_stat_statements extension is not installed")
+
+// SlowQueries returns the statements with the highest mean execution time in the current
+// database, as recorded by the pg_stat_statements extension (PostgreSQL 13+)
+func (edb *EnvioDB) SlowQueries(ctx context.Context, limit int) ([]SlowQuery, error) {
+	var installed bool
+	err := edb.DB.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'pg_stat_statements')`).Scan(&installed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect pg_stat_statements: %w", err)
+	}
+	if !installed {
+		return nil, ErrStatStatementsUnavailable
+	}
+
+	rows, err := edb.DB.QueryContext(ctx, `
+		SELECT query, calls, mean_exec_time, max_exec_time, total_exec_time, rows, shared_blks_read
+		FROM pg_stat_statements
+		WHERE dbid = (SELECT oid FROM pg_database WHERE datname = current_database())
+		ORDER BY mean_exec_time DESC
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pg_stat_statements: %w", err)
+	}
+	defer rows.Close()
+
+	queries := []SlowQuery{}
+	for rows.Next() {
+		var q SlowQuery
+		if err := rows.Scan(&q.Query, &q.Calls, &q.MeanMs, &q.MaxMs, &q.TotalMs, &q.Rows, &q.SharedReads); err != nil {
+			return nil, fmt.Errorf("failed to scan slow query: %w", err)
+		}
+		queries = append(queries, q)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read slow queries: %w", err)
+	}
+
+	return queries, nil
+}
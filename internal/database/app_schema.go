@@ -0,0 +1,191 @@
+package database
+
+import (
+	"fmt"
+	"log"
+)
+
+// AppSchema is the Postgres schema holding data owned by the backend rather than the
+// indexer, so it never collides with Envio's tables or gets dropped by a reindex
+const AppSchema = "nadmon_app"
+
+// appSchemaStatements create the backend-owned tables; each is idempotent
+var appSchemaStatements = []string{
+	`CREATE SCHEMA IF NOT EXISTS ` + AppSchema,
+	`CREATE TABLE IF NOT EXISTS ` + AppSchema + `.player_profiles (
+		address TEXT PRIMARY KEY,
+		nickname TEXT,
+		avatar_token_id BIGINT,
+		bio TEXT NOT NULL DEFAULT '',
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	)`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_player_profiles_nickname
+		ON ` + AppSchema + `.player_profiles (LOWER(nickname))`,
+	`CREATE TABLE IF NOT EXISTS ` + AppSchema + `.teams (
+		id BIGSERIAL PRIMARY KEY,
+		address TEXT NOT NULL,
+		name TEXT NOT NULL,
+		token_ids BIGINT[] NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	)`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_teams_address_name
+		ON ` + AppSchema + `.teams (address, LOWER(name))`,
+	`CREATE TABLE IF NOT EXISTS ` + AppSchema + `.favorites (
+		address TEXT NOT NULL,
+		token_id BIGINT NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		PRIMARY KEY (address, token_id)
+	)`,
+	`CREATE TABLE IF NOT EXISTS ` + AppSchema + `.trade_offers (
+		id BIGSERIAL PRIMARY KEY,
+		proposer TEXT NOT NULL,
+		counterparty TEXT NOT NULL,
+		offered_token_ids BIGINT[] NOT NULL,
+		requested_token_ids BIGINT[] NOT NULL,
+		status TEXT NOT NULL DEFAULT 'pending',
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		expires_at TIMESTAMPTZ NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_trade_offers_proposer
+		ON ` + AppSchema + `.trade_offers (proposer, created_at DESC)`,
+	`CREATE INDEX IF NOT EXISTS idx_trade_offers_counterparty
+		ON ` + AppSchema + `.trade_offers (counterparty, created_at DESC)`,
+	`CREATE TABLE IF NOT EXISTS ` + AppSchema + `.webhooks (
+		id BIGSERIAL PRIMARY KEY,
+		url TEXT NOT NULL,
+		owner TEXT NOT NULL DEFAULT '',
+		events TEXT[] NOT NULL,
+		addresses TEXT[] NOT NULL DEFAULT '{}',
+		secret TEXT NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_webhooks_owner ON ` + AppSchema + `.webhooks (owner)`,
+	`CREATE TABLE IF NOT EXISTS ` + AppSchema + `.webhook_deliveries (
+		id BIGSERIAL PRIMARY KEY,
+		webhook_id BIGINT NOT NULL REFERENCES ` + AppSchema + `.webhooks (id) ON DELETE CASCADE,
+		delivery_id TEXT NOT NULL,
+		event TEXT NOT NULL,
+		attempt INTEGER NOT NULL,
+		status_code INTEGER NOT NULL DEFAULT 0,
+		error TEXT NOT NULL DEFAULT '',
+		duration_ms BIGINT NOT NULL,
+		succeeded BOOLEAN NOT NULL,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook
+		ON ` + AppSchema + `.webhook_deliveries (webhook_id, id DESC)`,
+	`CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_created
+		ON ` + AppSchema + `.webhook_deliveries (created_at)`,
+	`CREATE TABLE IF NOT EXISTS ` + AppSchema + `.nft_rarity (
+		token_id BIGINT PRIMARY KEY,
+		nadmon_type TEXT NOT NULL,
+		element TEXT NOT NULL,
+		rarity TEXT NOT NULL,
+		evo BIGINT NOT NULL,
+		type_count BIGINT NOT NULL,
+		element_count BIGINT NOT NULL,
+		rarity_count BIGINT NOT NULL,
+		evo_count BIGINT NOT NULL,
+		combination_count BIGINT NOT NULL,
+		total BIGINT NOT NULL,
+		score DOUBLE PRECISION NOT NULL,
+		rank BIGINT NOT NULL,
+		computed_at TIMESTAMPTZ NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_nft_rarity_rank ON ` + AppSchema + `.nft_rarity (rank, token_id)`,
+	`CREATE TABLE IF NOT EXISTS ` + AppSchema + `.collector_ranks (
+		address TEXT PRIMARY KEY,
+		nft_count BIGINT NOT NULL,
+		rank BIGINT NOT NULL,
+		previous_nft_count BIGINT,
+		previous_rank BIGINT,
+		snapshot_at TIMESTAMPTZ NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_collector_ranks_rank ON ` + AppSchema + `.collector_ranks (rank, address)`,
+	`CREATE TABLE IF NOT EXISTS ` + AppSchema + `.player_stats (
+		address TEXT PRIMARY KEY,
+		nft_count BIGINT NOT NULL,
+		packs_bought BIGINT NOT NULL,
+		evolutions_performed BIGINT NOT NULL,
+		evolved_nfts BIGINT NOT NULL,
+		rarity_counts JSONB NOT NULL,
+		element_counts JSONB NOT NULL,
+		rarest_token_id BIGINT,
+		rarest_rarity TEXT,
+		last_active TIMESTAMP,
+		updated_at TIMESTAMPTZ NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS ` + AppSchema + `.pvp_ratings (
+		address TEXT PRIMARY KEY,
+		rating BIGINT NOT NULL,
+		wins BIGINT NOT NULL DEFAULT 0,
+		losses BIGINT NOT NULL DEFAULT 0,
+		draws BIGINT NOT NULL DEFAULT 0,
+		last_battle_at TIMESTAMPTZ
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_pvp_ratings_rating ON ` + AppSchema + `.pvp_ratings (rating DESC, address)`,
+	`CREATE TABLE IF NOT EXISTS ` + AppSchema + `.battles (
+		battle_id TEXT PRIMARY KEY,
+		player1 TEXT NOT NULL,
+		player2 TEXT NOT NULL,
+		winner TEXT,
+		player1_team BIGINT[] NOT NULL,
+		player2_team BIGINT[] NOT NULL,
+		player1_rating_before BIGINT NOT NULL,
+		player1_rating_after BIGINT NOT NULL,
+		player2_rating_before BIGINT NOT NULL,
+		player2_rating_after BIGINT NOT NULL,
+		fought_at TIMESTAMPTZ NOT NULL,
+		recorded_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_battles_player1 ON ` + AppSchema + `.battles (player1, fought_at DESC)`,
+	`CREATE INDEX IF NOT EXISTS idx_battles_player2 ON ` + AppSchema + `.battles (player2, fought_at DESC)`,
+	`CREATE TABLE IF NOT EXISTS ` + AppSchema + `.api_keys (
+		id BIGSERIAL PRIMARY KEY,
+		name TEXT NOT NULL,
+		prefix TEXT NOT NULL,
+		key_hash TEXT NOT NULL UNIQUE,
+		scopes TEXT[] NOT NULL,
+		rate_limit DOUBLE PRECISION NOT NULL,
+		burst INTEGER NOT NULL,
+		daily_quota BIGINT NOT NULL DEFAULT 0,
+		created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+		revoked_at TIMESTAMPTZ,
+		last_used_at TIMESTAMPTZ
+	)`,
+	`CREATE TABLE IF NOT EXISTS ` + AppSchema + `.api_key_usage (
+		key_id BIGINT NOT NULL REFERENCES ` + AppSchema + `.api_keys (id) ON DELETE CASCADE,
+		day DATE NOT NULL,
+		requests BIGINT NOT NULL DEFAULT 0,
+		rejected BIGINT NOT NULL DEFAULT 0,
+		PRIMARY KEY (key_id, day)
+	)`,
+	`CREATE TABLE IF NOT EXISTS ` + AppSchema + `.audit_log (
+		id BIGSERIAL PRIMARY KEY,
+		address TEXT NOT NULL,
+		api_key_id BIGINT,
+		client_ip TEXT NOT NULL,
+		remote_addr TEXT NOT NULL,
+		method TEXT NOT NULL,
+		route TEXT NOT NULL,
+		status INTEGER NOT NULL,
+		request_id TEXT NOT NULL DEFAULT '',
+		requested_at TIMESTAMPTZ NOT NULL
+	)`,
+	`CREATE INDEX IF NOT EXISTS idx_audit_log_address ON ` + AppSchema + `.audit_log (address, id DESC)`,
+	`CREATE INDEX IF NOT EXISTS idx_audit_log_api_key ON ` + AppSchema + `.audit_log (api_key_id, id DESC)`,
+	`CREATE INDEX IF NOT EXISTS idx_audit_log_requested ON ` + AppSchema + `.audit_log (requested_at)`,
+}
+
+// SetupAppSchema creates the backend-owned schema and its tables
+func (edb *EnvioDB) SetupAppSchema() error {
+	for _, statement := range appSchemaStatements {
+		if _, err := edb.DB.Exec(statement); err != nil {
+			return fmt.Errorf("failed to set up %s schema: %w", AppSchema, err)
+		}
+	}
+
+	log.Printf("✅ Backend-owned schema %s ready", AppSchema)
+	return nil
+}
@@ -0,0 +1,260 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// Circuit breaker states
+const (
+	BreakerClosed   = "closed"    // queries flow normally
+	BreakerOpen     = "open"      // the database is down, calls fail fast
+	BreakerHalfOpen = "half_open" // cooldown elapsed, trial calls decide whether to close
+)
+
+// ErrCircuitOpen is returned instead of querying while the database is known to be down
+var ErrCircuitOpen = errors.New("database unavailable")
+
+// errPingTimeout marks a health check ping that got no answer in time. Unlike a request's
+// deadline, it means the database is unreachable, e.g. behind a network partition.
+var errPingTimeout = errors.New("database ping timed out")
+
+// Breaker trips after consecutive connection failures and fails calls fast until the
+// database answers again. A nil *Breaker never trips.
+type Breaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu        sync.Mutex
+	state     string
+	failures  int
+	openedAt  time.Time
+	lastError string
+	trips     int64
+}
+
+// NewBreaker creates a breaker that opens after threshold consecutive connection failures
+// and lets trial calls through once cooldown has passed
+func NewBreaker(threshold int, cooldown time.Duration) *Breaker {
+	if threshold < 1 {
+		threshold = 1
+	}
+	return &Breaker{threshold: threshold, cooldown: cooldown, state: BreakerClosed}
+}
+
+// Allow returns ErrCircuitOpen while the breaker is open, and how long until trial calls
+// are let through
+func (b *Breaker) Allow() (time.Duration, error) {
+	if b == nil {
+		return 0, nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state != BreakerOpen {
+		return 0, nil
+	}
+	if wait := b.cooldown - time.Since(b.openedAt); wait > 0 {
+		return wait, ErrCircuitOpen
+	}
+	b.state = BreakerHalfOpen
+	return 0, nil
+}
+
+// Record updates the breaker with the outcome of a call; only connection failures count
+// against the database. It reports whether the call changed the breaker between open and
+// closed.
+func (b *Breaker) Record(err error) (changed bool) {
+	if b == nil {
+		return false
+	}
+	if errors.Is(err, ErrCircuitOpen) {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !IsConnectionError(err) {
+		// Any answer from the server, even an error, means it is up
+		changed = b.state != BreakerClosed
+		b.state = BreakerClosed
+		b.failures = 0
+		return changed
+	}
+
+	b.failures++
+	b.lastError = err.Error()
+	if b.state == BreakerHalfOpen || (b.state == BreakerClosed && b.failures >= b.threshold) {
+		changed = b.state == BreakerClosed
+		if changed {
+			b.trips++
+		}
+		b.state = BreakerOpen
+		b.openedAt = time.Now()
+	}
+	return changed
+}
+
+// State returns the current breaker state
+func (b *Breaker) State() string {
+	if b == nil {
+		return BreakerClosed
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// BreakerStats describes the breaker for /health
+type BreakerStats struct {
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	Trips               int64      `json:"trips"`
+	OpenedAt            *time.Time `json:"opened_at,omitempty"`
+	LastError           string     `json:"last_error,omitempty"`
+}
+
+// Stats returns the breaker's current state and counters
+func (b *Breaker) Stats() BreakerStats {
+	if b == nil {
+		return BreakerStats{State: BreakerClosed}
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	stats := BreakerStats{
+		State:               b.state,
+		ConsecutiveFailures: b.failures,
+		Trips:               b.trips,
+		LastError:           b.lastError,
+	}
+	if b.state != BreakerClosed {
+		openedAt := b.openedAt
+		stats.OpenedAt = &openedAt
+	}
+	return stats
+}
+
+// IsConnectionError reports whether err means the database could not be reached, as
+// opposed to a failing query or a cancelled request
+func IsConnectionError(err error) bool {
+	if errors.Is(err, errPingTimeout) {
+		return true
+	}
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) {
+		return true
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// Class 08 is connection exceptions; 57P01-57P03 are shutdowns and startup
+		return strings.HasPrefix(pgErr.Code, "08") || pgErr.Code == "57P01" || pgErr.Code == "57P02" || pgErr.Code == "57P03"
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// Monitor pings the database every interval until stop is closed, feeding the breaker so
+// it opens while the database is down and closes once it answers again. Idle connections
+// are dropped on recovery so requests don't pick up connections broken by the restart.
+func (edb *EnvioDB) Monitor(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		err := edb.DB.PingContext(ctx)
+		if err != nil && ctx.Err() != nil {
+			err = fmt.Errorf("%w after %s: %v", errPingTimeout, interval, err)
+		}
+		cancel()
+
+		if !edb.Breaker.Record(err) {
+			continue
+		}
+		if err != nil {
+			log.Printf("🔌 Database unavailable, failing requests fast: %v", err)
+			continue
+		}
+
+		edb.DB.SetMaxIdleConns(0)
+		edb.DB.SetMaxIdleConns(edb.maxIdleConns)
+		edb.Pool.Reset()
+		log.Println("✅ Database connection recovered")
+	}
+}
+
+// Conn is the query interface shared by *sql.DB and the generated query code
+type Conn interface {
+	ExecContext(context.Context, string, ...interface{}) (sql.Result, error)
+	PrepareContext(context.Context, string) (*sql.Stmt, error)
+	QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error)
+	QueryRowContext(context.Context, string, ...interface{}) *sql.Row
+}
+
+// guardedConn fails calls fast while the breaker is open and feeds call outcomes to it
+type guardedConn struct {
+	Conn
+	breaker *Breaker
+}
+
+// Guard returns conn wrapped with the database's circuit breaker. QueryRowContext can't
+// fail early or report its error, so it is passed through; the health monitor and the
+// other calls still trip and close the breaker.
+func (edb *EnvioDB) Guard(conn Conn) Conn {
+	if edb.Breaker == nil {
+		return conn
+	}
+	return &guardedConn{Conn: conn, breaker: edb.Breaker}
+}
+
+func (g *guardedConn) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	if _, err := g.breaker.Allow(); err != nil {
+		return nil, err
+	}
+	result, err := g.Conn.ExecContext(ctx, query, args...)
+	g.breaker.Record(err)
+	return result, err
+}
+
+func (g *guardedConn) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	if _, err := g.breaker.Allow(); err != nil {
+		return nil, err
+	}
+	stmt, err := g.Conn.PrepareContext(ctx, query)
+	g.breaker.Record(err)
+	return stmt, err
+}
+
+func (g *guardedConn) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	if _, err := g.breaker.Allow(); err != nil {
+		return nil, err
+	}
+	rows, err := g.Conn.QueryContext(ctx, query, args...)
+	g.breaker.Record(err)
+	return rows, err
+}
@@ -0,0 +1,173 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+)
+
+// currentStateOverlap re-reads rows this far behind the last synced timestamp, so rows from
+// indexer transactions that committed late are still applied. Re-applying rows is a no-op.
+const currentStateOverlap = time.Minute
+
+// SetupCurrentState creates the denormalized nadmon_current_state table (owner and latest
+// stats per token) and backfills it from the full Envio history
+func (edb *EnvioDB) SetupCurrentState() error {
+	log.Println("🗂️ Preparing materialized current-state table...")
+
+	statements := []string{
+		`CREATE TABLE IF NOT EXISTS nadmon_current_state (
+			token_id BIGINT PRIMARY KEY,
+			owner TEXT NOT NULL,
+			pack_id BIGINT NOT NULL,
+			nadmon_type TEXT NOT NULL,
+			element TEXT NOT NULL,
+			rarity TEXT NOT NULL,
+			hp BIGINT NOT NULL,
+			attack BIGINT NOT NULL,
+			defense BIGINT NOT NULL,
+			crit BIGINT NOT NULL,
+			fusion BIGINT NOT NULL,
+			evo BIGINT NOT NULL,
+			stats_sequence BIGINT NOT NULL DEFAULT -1,
+			created_at TIMESTAMP,
+			last_updated TIMESTAMP,
+			owner_changed_at TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_current_state_owner ON nadmon_current_state(owner)`,
+		`CREATE TABLE IF NOT EXISTS nadmon_current_state_sync (
+			id BOOLEAN PRIMARY KEY DEFAULT TRUE CHECK (id),
+			synced_to TIMESTAMP NOT NULL
+		)`,
+	}
+
+	for _, statement := range statements {
+		if _, err := edb.DB.Exec(statement); err != nil {
+			return fmt.Errorf("failed to set up current-state table: %w", err)
+		}
+	}
+
+	if err := edb.SyncCurrentState(); err != nil {
+		return err
+	}
+
+	edb.CurrentState = true
+	log.Println("✅ Materialized current-state table ready")
+	return nil
+}
+
+// SyncCurrentState applies Envio rows written since the last sync to nadmon_current_state:
+// new mints are inserted, the latest transfer sets the owner and the StatsChanged row with
+// the highest sequence sets the stats. All three steps read one consistent snapshot.
+func (edb *EnvioDB) SyncCurrentState() error {
+	edb.stateMu.Lock()
+	defer edb.stateMu.Unlock()
+
+	tx, err := edb.DB.BeginTx(context.Background(), &sql.TxOptions{Isolation: sql.LevelRepeatableRead})
+	if err != nil {
+		return fmt.Errorf("failed to begin current-state sync: %w", err)
+	}
+	defer tx.Rollback()
+
+	var syncedTo sql.NullTime
+	err = tx.QueryRow(`SELECT synced_to FROM nadmon_current_state_sync`).Scan(&syncedTo)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to read current-state cursor: %w", err)
+	}
+	since := time.Time{} // zero time reads the full history
+	if syncedTo.Valid {
+		since = syncedTo.Time.Add(-currentStateOverlap)
+	}
+
+	var latest sql.NullTime
+	err = tx.QueryRow(`SELECT GREATEST(
+		(SELECT MAX(db_write_timestamp) FROM "NadmonNFT_NadmonMinted"),
+		(SELECT MAX(db_write_timestamp) FROM "NadmonNFT_Transfer"),
+		(SELECT MAX(db_write_timestamp) FROM "NadmonNFT_StatsChanged")
+	)`).Scan(&latest)
+	if err != nil {
+		return fmt.Errorf("failed to read latest envio timestamp: %w", err)
+	}
+	if !latest.Valid {
+		return nil // Nothing indexed yet
+	}
+
+	steps := []struct {
+		name  string
+		query string
+	}{
+		{"mints", `
+			INSERT INTO nadmon_current_state (token_id, owner, pack_id, nadmon_type, element, rarity,
+				hp, attack, defense, crit, fusion, evo, created_at, last_updated)
+			SELECT DISTINCT ON (m."tokenId")
+				m."tokenId"::bigint, LOWER(m.owner), m."packId"::bigint, m."nadmonType", m.element, m.rarity,
+				m.hp::bigint, m.attack::bigint, m.defense::bigint, m.crit::bigint, m.fusion::bigint, m.evo::bigint,
+				m.db_write_timestamp, m.db_write_timestamp
+			FROM "NadmonNFT_NadmonMinted" m
+			WHERE m.db_write_timestamp >= $1
+			ORDER BY m."tokenId", m.db_write_timestamp
+			ON CONFLICT (token_id) DO NOTHING`},
+		{"transfers", `
+			UPDATE nadmon_current_state cs
+			SET owner = lt.owner, owner_changed_at = lt.transferred_at
+			FROM (
+				SELECT DISTINCT ON (t."tokenId")
+					t."tokenId"::bigint AS token_id, LOWER(t."to") AS owner, t.db_write_timestamp AS transferred_at
+				FROM "NadmonNFT_Transfer" t
+				WHERE t.db_write_timestamp >= $1
+				ORDER BY t."tokenId", t.db_write_timestamp DESC
+			) lt
+			WHERE cs.token_id = lt.token_id
+				AND (cs.owner_changed_at IS NULL OR lt.transferred_at >= cs.owner_changed_at)`},
+		{"stats", `
+			UPDATE nadmon_current_state cs
+			SET hp = ls."newHp"::bigint, attack = ls."newAttack"::bigint, defense = ls."newDefense"::bigint,
+				crit = ls."newCrit"::bigint, fusion = ls."newFusion"::bigint, evo = ls."newEvo"::bigint,
+				stats_sequence = ls.sequence::bigint, last_updated = ls.db_write_timestamp
+			FROM (
+				SELECT DISTINCT ON (s."tokenId")
+					s."tokenId"::bigint AS token_id, s.sequence, s."newHp", s."newAttack", s."newDefense",
+					s."newCrit", s."newFusion", s."newEvo", s.db_write_timestamp
+				FROM "NadmonNFT_StatsChanged" s
+				WHERE s.db_write_timestamp >= $1
+				ORDER BY s."tokenId", s.sequence DESC
+			) ls
+			WHERE cs.token_id = ls.token_id AND ls.sequence > cs.stats_sequence`},
+	}
+
+	for _, step := range steps {
+		if _, err := tx.Exec(step.query, since); err != nil {
+			return fmt.Errorf("failed to sync %s into current state: %w", step.name, err)
+		}
+	}
+
+	_, err = tx.Exec(`
+		INSERT INTO nadmon_current_state_sync (id, synced_to) VALUES (TRUE, $1)
+		ON CONFLICT (id) DO UPDATE SET synced_to = GREATEST(nadmon_current_state_sync.synced_to, EXCLUDED.synced_to)
+	`, latest.Time)
+	if err != nil {
+		return fmt.Errorf("failed to save current-state cursor: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit current-state sync: %w", err)
+	}
+
+	edb.stateSyncedTo = latest.Time
+	return nil
+}
+
+// SyncCurrentStateThrough syncs only if rows written at ts may not have been applied yet,
+// so callers reacting to a batch of new events trigger at most one sync
+func (edb *EnvioDB) SyncCurrentStateThrough(ts time.Time) error {
+	edb.stateMu.Lock()
+	synced := !ts.After(edb.stateSyncedTo)
+	edb.stateMu.Unlock()
+
+	if synced {
+		return nil
+	}
+	return edb.SyncCurrentState()
+}
@@ -1,73 +1,185 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
+	"strconv"
+	"sync"
 	"time"
 
-	_ "github.com/lib/pq"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/pgx/v5/stdlib"
 )
 
 // EnvioDB wraps a SQL database connection for querying Envio tables
 type EnvioDB struct {
+	// Pool is the pgx connection pool; use it directly for batches and native array decoding
+	Pool *pgxpool.Pool
+
+	// DB is a database/sql handle on Pool for the generated queries
 	DB *sql.DB
+
+	// Timescale is true once the TimescaleDB continuous aggregates are ready
+	Timescale bool
+
+	// CurrentState is true once nadmon_current_state is backfilled and can serve reads
+	CurrentState  bool
+	stateMu       sync.Mutex // serializes current-state syncs
+	stateSyncedTo time.Time  // newest Envio timestamp applied to the current-state table
+
+	// Breaker fails queries fast while the database is down; nil disables it
+	Breaker *Breaker
+
+	maxIdleConns     int           // idle database/sql connections, restored after an outage
+	statementTimeout time.Duration // configured statement_timeout, lifted for index maintenance
 }
 
-// ConnectToEnvio establishes a connection to the Envio PostgreSQL database
+// PoolConfig sizes the connection pool of an Envio database
+type PoolConfig struct {
+	MaxOpenConns     int
+	MaxIdleConns     int           // idle database/sql connections kept checked out of the pool
+	ConnMaxLifetime  time.Duration
+	StatementTimeout time.Duration // Postgres statement_timeout of every connection (0 disables it)
+}
+
+// DefaultPoolConfig returns the pool settings used when none are configured
+func DefaultPoolConfig() PoolConfig {
+	return PoolConfig{MaxOpenConns: 50, MaxIdleConns: 10, ConnMaxLifetime: 5 * time.Minute}
+}
+
+// ConnectToEnvio establishes a connection to the Envio PostgreSQL database with the default pool
 func ConnectToEnvio(databaseURL string) (*EnvioDB, error) {
-	db, err := sql.Open("postgres", databaseURL)
+	return ConnectToEnvioWithPool(databaseURL, DefaultPoolConfig())
+}
+
+// ConnectToEnvioWithPool establishes a connection to the Envio PostgreSQL database with the
+// given pool settings
+func ConnectToEnvioWithPool(databaseURL string, poolConfig PoolConfig) (*EnvioDB, error) {
+	config, err := pgxpool.ParseConfig(databaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	// By default pgx prepares each statement once per connection and caches it
+	// (default_query_exec_mode and statement_cache_capacity in the URL override this).
+	if poolConfig.MaxOpenConns > 0 {
+		config.MaxConns = int32(poolConfig.MaxOpenConns)
+	}
+	if poolConfig.ConnMaxLifetime > 0 {
+		config.MaxConnLifetime = poolConfig.ConnMaxLifetime
+	}
+	if poolConfig.StatementTimeout > 0 {
+		config.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(poolConfig.StatementTimeout.Milliseconds(), 10)
+	}
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), config)
 	if err != nil {
 		return nil, err
 	}
 
-	// Configure connection pool for high performance
-	db.SetMaxIdleConns(10)
-	db.SetMaxOpenConns(50)
-	db.SetConnMaxLifetime(5 * time.Minute)
+	// Idle connections beyond the pool size would never be used
+	maxIdle := min(poolConfig.MaxIdleConns, int(config.MaxConns))
+	db := stdlib.OpenDBFromPool(pool)
+	db.SetMaxIdleConns(maxIdle)
 
 	// Test the connection
 	if err := db.Ping(); err != nil {
+		db.Close()
+		pool.Close()
 		return nil, err
 	}
 
-	log.Println("✅ Connected to Envio PostgreSQL database")
-	return &EnvioDB{DB: db}, nil
+	log.Printf("✅ Connected to Envio PostgreSQL database (max %d connections, %d idle, %s lifetime, statement timeout %s)",
+		config.MaxConns, maxIdle, config.MaxConnLifetime, statementTimeoutString(poolConfig.StatementTimeout))
+	return &EnvioDB{Pool: pool, DB: db, maxIdleConns: maxIdle, statementTimeout: poolConfig.StatementTimeout}, nil
+}
+
+// statementTimeoutString formats a statement timeout for the startup log
+func statementTimeoutString(timeout time.Duration) string {
+	if timeout <= 0 {
+		return "off"
+	}
+	return timeout.String()
+}
+
+// withoutStatementTimeout runs fn on a dedicated connection with statement_timeout lifted,
+// for index maintenance that may legitimately run longer than any request query
+func (edb *EnvioDB) withoutStatementTimeout(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := edb.DB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to acquire connection: %w", err)
+	}
+	defer conn.Close()
+
+	if edb.statementTimeout > 0 {
+		if _, err := conn.ExecContext(ctx, "SET statement_timeout = 0"); err != nil {
+			return fmt.Errorf("failed to lift statement timeout: %w", err)
+		}
+		// The connection returns to the pool afterwards, so restore the configured timeout
+		defer conn.ExecContext(context.Background(), "RESET statement_timeout")
+	}
+	return fn(conn)
 }
 
 // Close closes the database connection
 func (edb *EnvioDB) Close() error {
-	return edb.DB.Close()
+	err := edb.DB.Close()
+	edb.Pool.Close()
+	return err
+}
+
+// envioIndex is an app-owned index on an Envio table
+type envioIndex struct {
+	name string
+	on   string // table and columns
+}
+
+// envioIndexes are the indexes CreateIndexes maintains on the Envio tables
+var envioIndexes = []envioIndex{
+	// Indexes for common queries on NadmonMinted
+	{"idx_nadmon_minted_owner", `"NadmonNFT_NadmonMinted"(owner)`},
+	{"idx_nadmon_minted_tokenid", `"NadmonNFT_NadmonMinted"("tokenId")`},
+	{"idx_nadmon_minted_owner_sequence", `"NadmonNFT_NadmonMinted"(owner, sequence DESC)`},
+	{"idx_nadmon_minted_owner_lower", `"NadmonNFT_NadmonMinted"(LOWER(owner))`},
+
+	// Indexes for PackMinted queries
+	{"idx_pack_minted_player", `"NadmonNFT_PackMinted"(player)`},
+	{"idx_pack_minted_player_lower", `"NadmonNFT_PackMinted"(LOWER(player))`},
+	{"idx_pack_minted_sequence", `"NadmonNFT_PackMinted"(sequence DESC)`},
+
+	// Indexes for StatsChanged queries
+	{"idx_stats_changed_tokenid", `"NadmonNFT_StatsChanged"("tokenId")`},
+	{"idx_stats_changed_tokenid_sequence", `"NadmonNFT_StatsChanged"("tokenId", sequence DESC)`},
+
+	// Indexes for Transfer queries
+	{"idx_transfer_to", `"NadmonNFT_Transfer"("to")`},
+	{"idx_transfer_to_lower", `"NadmonNFT_Transfer"(LOWER("to"))`},
+	{"idx_transfer_from_lower", `"NadmonNFT_Transfer"(LOWER("from"))`},
+	{"idx_transfer_tokenid", `"NadmonNFT_Transfer"("tokenId")`},
+
+	// Write-time indexes for incremental syncs and the event pipeline
+	{"idx_nadmon_minted_write_ts", `"NadmonNFT_NadmonMinted"(db_write_timestamp)`},
+	{"idx_stats_changed_write_ts", `"NadmonNFT_StatsChanged"(db_write_timestamp)`},
+	{"idx_transfer_write_ts", `"NadmonNFT_Transfer"(db_write_timestamp)`},
 }
 
 // CreateIndexes creates additional indexes for optimal query performance on Envio tables
 func (edb *EnvioDB) CreateIndexes() error {
 	log.Println("🔧 Creating indexes on Envio tables...")
 
-	indexes := []string{
-		// Indexes for common queries on NadmonMinted
-		`CREATE INDEX IF NOT EXISTS idx_nadmon_minted_owner ON "NadmonNFT_NadmonMinted"(owner)`,
-		`CREATE INDEX IF NOT EXISTS idx_nadmon_minted_tokenid ON "NadmonNFT_NadmonMinted"("tokenId")`,
-		`CREATE INDEX IF NOT EXISTS idx_nadmon_minted_owner_sequence ON "NadmonNFT_NadmonMinted"(owner, sequence DESC)`,
-		
-		// Indexes for PackMinted queries
-		`CREATE INDEX IF NOT EXISTS idx_pack_minted_player ON "NadmonNFT_PackMinted"(player)`,
-		`CREATE INDEX IF NOT EXISTS idx_pack_minted_sequence ON "NadmonNFT_PackMinted"(sequence DESC)`,
-		
-		// Indexes for StatsChanged queries
-		`CREATE INDEX IF NOT EXISTS idx_stats_changed_tokenid ON "NadmonNFT_StatsChanged"("tokenId")`,
-		`CREATE INDEX IF NOT EXISTS idx_stats_changed_tokenid_sequence ON "NadmonNFT_StatsChanged"("tokenId", sequence DESC)`,
-		
-		// Indexes for Transfer queries
-		`CREATE INDEX IF NOT EXISTS idx_transfer_to ON "NadmonNFT_Transfer"("to")`,
-		`CREATE INDEX IF NOT EXISTS idx_transfer_tokenid ON "NadmonNFT_Transfer"("tokenId")`,
-	}
-
-	for _, index := range indexes {
-		if _, err := edb.DB.Exec(index); err != nil {
-			log.Printf("Warning: Failed to create index: %v", err)
-			// Continue with other indexes even if one fails
+	err := edb.withoutStatementTimeout(context.Background(), func(conn *sql.Conn) error {
+		for _, index := range envioIndexes {
+			if _, err := conn.ExecContext(context.Background(), fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s", index.name, index.on)); err != nil {
+				log.Printf("Warning: Failed to create index: %v", err)
+				// Continue with other indexes even if one fails
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	log.Println("✅ Database indexes created")
@@ -75,12 +187,12 @@ func (edb *EnvioDB) CreateIndexes() error {
 }
 
 // GetStats returns database statistics from Envio tables
-func (edb *EnvioDB) GetStats() (map[string]interface{}, error) {
+func (edb *EnvioDB) GetStats(ctx context.Context) (map[string]interface{}, error) {
 	stats := make(map[string]interface{})
 
 	// Count total NFTs
 	var totalNFTs int
-	err := edb.DB.QueryRow(`SELECT COUNT(*) FROM "NadmonNFT_NadmonMinted"`).Scan(&totalNFTs)
+	err := edb.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM "NadmonNFT_NadmonMinted"`).Scan(&totalNFTs)
 	if err != nil {
 		return nil, err
 	}
@@ -88,7 +200,7 @@ func (edb *EnvioDB) GetStats() (map[string]interface{}, error) {
 
 	// Count total packs
 	var totalPacks int
-	err = edb.DB.QueryRow(`SELECT COUNT(*) FROM "NadmonNFT_PackMinted"`).Scan(&totalPacks)
+	err = edb.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM "NadmonNFT_PackMinted"`).Scan(&totalPacks)
 	if err != nil {
 		return nil, err
 	}
@@ -96,7 +208,7 @@ func (edb *EnvioDB) GetStats() (map[string]interface{}, error) {
 
 	// Count unique players
 	var uniquePlayers int
-	err = edb.DB.QueryRow(`SELECT COUNT(DISTINCT player) FROM "NadmonNFT_PackMinted"`).Scan(&uniquePlayers)
+	err = edb.DB.QueryRowContext(ctx, `SELECT COUNT(DISTINCT player) FROM "NadmonNFT_PackMinted"`).Scan(&uniquePlayers)
 	if err != nil {
 		return nil, err
 	}
@@ -104,7 +216,7 @@ func (edb *EnvioDB) GetStats() (map[string]interface{}, error) {
 
 	// Count total evolutions
 	var totalEvolutions int
-	err = edb.DB.QueryRow(`SELECT COUNT(*) FROM "NadmonNFT_StatsChanged" WHERE "changeType" = 'evolution'`).Scan(&totalEvolutions)
+	err = edb.DB.QueryRowContext(ctx, `SELECT COUNT(*) FROM "NadmonNFT_StatsChanged" WHERE "changeType" = 'evolution'`).Scan(&totalEvolutions)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,158 @@
+-- Global activity feed: mints, secondary transfers (including burns), stat changes and pack
+-- purchases merged newest first. Mint transfers from the zero address are reported as mints.
+
+-- name: GetActivityFeed :many
+SELECT id, activity_type, player, counterparty, token_id, pack_id, detail, occurred_at
+FROM (
+	SELECT
+		m.id,
+		'mint' AS activity_type,
+		LOWER(m.owner) AS player,
+		'' AS counterparty,
+		m."tokenId"::bigint AS token_id,
+		m."packId"::bigint AS pack_id,
+		m.rarity AS detail,
+		m.db_write_timestamp AS occurred_at
+	FROM "NadmonNFT_NadmonMinted" m
+	UNION ALL
+	SELECT
+		t.id,
+		'transfer',
+		LOWER(t."to"),
+		LOWER(t."from"),
+		t."tokenId"::bigint,
+		0::bigint,
+		CASE WHEN t."to" = '0x0000000000000000000000000000000000000000' THEN 'burn' ELSE 'transfer' END,
+		t.db_write_timestamp
+	FROM "NadmonNFT_Transfer" t
+	WHERE t."from" != '0x0000000000000000000000000000000000000000'
+	UNION ALL
+	SELECT
+		s.id,
+		'evolution',
+		'',
+		'',
+		s."tokenId"::bigint,
+		0::bigint,
+		s."changeType",
+		s.db_write_timestamp
+	FROM "NadmonNFT_StatsChanged" s
+	UNION ALL
+	SELECT
+		p.id,
+		'pack',
+		LOWER(p.player),
+		'',
+		0::bigint,
+		p."packId"::bigint,
+		p."paymentType",
+		p.db_write_timestamp
+	FROM "NadmonNFT_PackMinted" p
+) activity
+WHERE activity_type = ANY(@types::text[])
+ORDER BY occurred_at DESC, id DESC
+LIMIT @max_results::int OFFSET @skip::int;
+
+-- name: CountActivityFeed :one
+SELECT
+	(CASE WHEN 'mint' = ANY(@types::text[])
+		THEN (SELECT COUNT(*) FROM "NadmonNFT_NadmonMinted") ELSE 0 END) +
+	(CASE WHEN 'transfer' = ANY(@types::text[])
+		THEN (SELECT COUNT(*) FROM "NadmonNFT_Transfer" WHERE "from" != '0x0000000000000000000000000000000000000000') ELSE 0 END) +
+	(CASE WHEN 'evolution' = ANY(@types::text[])
+		THEN (SELECT COUNT(*) FROM "NadmonNFT_StatsChanged") ELSE 0 END) +
+	(CASE WHEN 'pack' = ANY(@types::text[])
+		THEN (SELECT COUNT(*) FROM "NadmonNFT_PackMinted") ELSE 0 END) AS total;
+
+-- Player activity feed: the player's mints, transfers in and out (including burns), stat
+-- changes of Nadmons the player owned at the time and pack purchases merged newest first.
+
+-- name: GetPlayerActivity :many
+SELECT id, activity_type, counterparty, token_id, pack_id, detail, occurred_at
+FROM (
+	SELECT
+		m.id,
+		'mint' AS activity_type,
+		'' AS counterparty,
+		m."tokenId"::bigint AS token_id,
+		m."packId"::bigint AS pack_id,
+		m.rarity AS detail,
+		m.db_write_timestamp AS occurred_at
+	FROM "NadmonNFT_NadmonMinted" m
+	WHERE LOWER(m.owner) = @player::text
+	UNION ALL
+	SELECT
+		t.id,
+		'transfer_in',
+		LOWER(t."from"),
+		t."tokenId"::bigint,
+		0::bigint,
+		'transfer',
+		t.db_write_timestamp
+	FROM "NadmonNFT_Transfer" t
+	WHERE LOWER(t."to") = @player::text
+		AND t."from" != '0x0000000000000000000000000000000000000000'
+	UNION ALL
+	SELECT
+		t.id,
+		'transfer_out',
+		LOWER(t."to"),
+		t."tokenId"::bigint,
+		0::bigint,
+		CASE WHEN t."to" = '0x0000000000000000000000000000000000000000' THEN 'burn' ELSE 'transfer' END,
+		t.db_write_timestamp
+	FROM "NadmonNFT_Transfer" t
+	WHERE LOWER(t."from") = @player::text
+	UNION ALL
+	SELECT
+		s.id,
+		'evolution',
+		'',
+		s."tokenId"::bigint,
+		0::bigint,
+		s."changeType",
+		s.db_write_timestamp
+	FROM "NadmonNFT_StatsChanged" s
+	JOIN LATERAL (
+		SELECT t."to"
+		FROM "NadmonNFT_Transfer" t
+		WHERE t."tokenId" = s."tokenId" AND t.db_write_timestamp <= s.db_write_timestamp
+		ORDER BY t.db_write_timestamp DESC, t.id DESC
+		LIMIT 1
+	) holder ON LOWER(holder."to") = @player::text
+	UNION ALL
+	SELECT
+		p.id,
+		'pack',
+		'',
+		0::bigint,
+		p."packId"::bigint,
+		p."paymentType",
+		p.db_write_timestamp
+	FROM "NadmonNFT_PackMinted" p
+	WHERE LOWER(p.player) = @player::text
+) activity
+WHERE activity_type = ANY(@types::text[])
+ORDER BY occurred_at DESC, id DESC
+LIMIT @max_results::int OFFSET @skip::int;
+
+-- name: CountPlayerActivity :one
+SELECT
+	(CASE WHEN 'mint' = ANY(@types::text[])
+		THEN (SELECT COUNT(*) FROM "NadmonNFT_NadmonMinted" WHERE LOWER(owner) = @player::text) ELSE 0 END) +
+	(CASE WHEN 'transfer_in' = ANY(@types::text[])
+		THEN (SELECT COUNT(*) FROM "NadmonNFT_Transfer"
+			WHERE LOWER("to") = @player::text AND "from" != '0x0000000000000000000000000000000000000000') ELSE 0 END) +
+	(CASE WHEN 'transfer_out' = ANY(@types::text[])
+		THEN (SELECT COUNT(*) FROM "NadmonNFT_Transfer" WHERE LOWER("from") = @player::text) ELSE 0 END) +
+	(CASE WHEN 'evolution' = ANY(@types::text[])
+		THEN (SELECT COUNT(*) FROM "NadmonNFT_StatsChanged" s
+			JOIN LATERAL (
+				SELECT t."to"
+				FROM "NadmonNFT_Transfer" t
+				WHERE t."tokenId" = s."tokenId" AND t.db_write_timestamp <= s.db_write_timestamp
+				ORDER BY t.db_write_timestamp DESC, t.id DESC
+				LIMIT 1
+			) holder ON LOWER(holder."to") = @player::text) ELSE 0 END) +
+	(CASE WHEN 'pack' = ANY(@types::text[])
+		THEN (SELECT COUNT(*) FROM "NadmonNFT_PackMinted" WHERE LOWER(player) = @player::text) ELSE 0 END) AS total;
@@ -0,0 +1,206 @@
+-- Reads served from nadmon_current_state, the materialized owner and latest stats per token.
+-- Owners are stored lowercased; burned tokens stay in the table with the zero address as owner.
+
+-- name: GetPlayerNadmonsFromState :many
+SELECT token_id, owner, pack_id, nadmon_type, element, rarity,
+	hp, attack, defense, crit, fusion, evo, created_at, last_updated
+FROM nadmon_current_state
+WHERE owner = @owner::text
+ORDER BY token_id;
+
+-- name: GetNadmonsByIDsFromState :many
+SELECT token_id, owner, pack_id, nadmon_type, element, rarity,
+	hp, attack, defense, crit, fusion, evo, created_at, last_updated
+FROM nadmon_current_state
+WHERE token_id = ANY(@token_ids::bigint[])
+	AND owner != '0x0000000000000000000000000000000000000000'
+ORDER BY token_id;
+
+-- name: GetNadmonSnapshotFromState :many
+SELECT token_id, owner, pack_id, nadmon_type, element, rarity,
+	hp, attack, defense, crit, fusion, evo, created_at, last_updated
+FROM nadmon_current_state
+WHERE token_id > @after_token_id::bigint
+	AND owner != '0x0000000000000000000000000000000000000000'
+ORDER BY token_id
+LIMIT @max_results::int;
+
+-- name: GetSingleNadmonFromState :one
+SELECT token_id, owner, pack_id, nadmon_type, element, rarity,
+	hp, attack, defense, crit, fusion, evo, created_at, last_updated
+FROM nadmon_current_state
+WHERE token_id = @token_id::bigint
+	AND owner != '0x0000000000000000000000000000000000000000';
+
+-- name: GetNadmonStatusesFromState :many
+SELECT
+	token_id,
+	(owner = '0x0000000000000000000000000000000000000000')::bool AS burned,
+	CASE WHEN owner = '0x0000000000000000000000000000000000000000' THEN owner_changed_at END AS burned_at
+FROM nadmon_current_state
+WHERE token_id = ANY(@token_ids::bigint[])
+ORDER BY token_id;
+
+-- name: GetTopCollectorsFromState :many
+SELECT owner, COUNT(*) AS nft_count
+FROM nadmon_current_state
+WHERE owner != '0x0000000000000000000000000000000000000000'
+	AND owner != ALL(@excluded_addresses::text[])
+GROUP BY owner
+ORDER BY nft_count DESC
+LIMIT @max_results::int;
+
+-- name: GetHolderBalancesFromState :many
+SELECT COUNT(*) AS nft_count
+FROM nadmon_current_state
+WHERE owner != '0x0000000000000000000000000000000000000000'
+	AND owner != ALL(@excluded_addresses::text[])
+GROUP BY owner
+ORDER BY nft_count DESC;
+
+-- name: CountCirculatingNadmonsFromState :one
+SELECT COUNT(*) FROM nadmon_current_state
+WHERE owner != '0x0000000000000000000000000000000000000000';
+
+-- name: CountUniqueCollectorsFromState :one
+SELECT COUNT(DISTINCT owner) FROM nadmon_current_state
+WHERE owner != '0x0000000000000000000000000000000000000000'
+	AND owner != ALL(@excluded_addresses::text[]);
+
+-- name: GetPlayerProfileFromState :many
+WITH owned AS (
+	SELECT token_id, pack_id, nadmon_type, element, rarity,
+		hp, attack, defense, crit, fusion, evo, created_at, last_updated
+	FROM nadmon_current_state
+	WHERE owner = @player::text
+),
+summary AS (
+	SELECT
+		(SELECT COUNT(*) FROM "NadmonNFT_PackMinted" p WHERE LOWER(p.player) = @player::text) AS pack_count,
+		GREATEST(
+			(SELECT MAX(p.db_write_timestamp) FROM "NadmonNFT_PackMinted" p WHERE LOWER(p.player) = @player::text),
+			(SELECT MAX(s.db_write_timestamp) FROM "NadmonNFT_StatsChanged" s WHERE s."tokenId" IN (SELECT token_id FROM owned))
+		)::timestamp AS last_active
+)
+SELECT summary.pack_count, summary.last_active,
+	o.token_id, o.pack_id, o.nadmon_type, o.element, o.rarity,
+	o.hp, o.attack, o.defense, o.crit, o.fusion, o.evo, o.created_at, o.last_updated
+FROM summary
+LEFT JOIN owned o ON true
+ORDER BY o.token_id;
+
+-- name: GetPlayerSummaryFromState :many
+WITH owned AS (
+	SELECT token_id, element, rarity, evo
+	FROM nadmon_current_state
+	WHERE owner = @player::text
+),
+summary AS (
+	SELECT
+		(SELECT COUNT(*) FROM "NadmonNFT_PackMinted" p WHERE LOWER(p.player) = @player::text) AS pack_count,
+		GREATEST(
+			(SELECT MAX(p.db_write_timestamp) FROM "NadmonNFT_PackMinted" p WHERE LOWER(p.player) = @player::text),
+			(SELECT MAX(s.db_write_timestamp) FROM "NadmonNFT_StatsChanged" s WHERE s."tokenId" IN (SELECT token_id FROM owned))
+		)::timestamp AS last_active
+)
+SELECT summary.pack_count, summary.last_active, o.rarity, o.element,
+	COUNT(o.token_id) AS nft_count,
+	COUNT(*) FILTER (WHERE o.evo > 1) AS evolved_count
+FROM summary
+LEFT JOIN owned o ON true
+GROUP BY summary.pack_count, summary.last_active, o.rarity, o.element
+ORDER BY o.rarity, o.element;
+
+-- name: GetSearchSuggestionsFromState :many
+WITH circulating AS (
+	SELECT nadmon_type, element, rarity
+	FROM nadmon_current_state
+	WHERE owner != '0x0000000000000000000000000000000000000000'
+),
+suggestions AS (
+	SELECT 'type'::text AS kind, nadmon_type::text AS value, COUNT(*) AS circulating FROM circulating GROUP BY nadmon_type
+	UNION ALL
+	SELECT 'element'::text, element::text, COUNT(*) FROM circulating GROUP BY element
+	UNION ALL
+	SELECT 'rarity'::text, rarity::text, COUNT(*) FROM circulating GROUP BY rarity
+)
+SELECT kind, value, circulating
+FROM suggestions
+WHERE value ILIKE '%' || @query::text || '%'
+ORDER BY (value ILIKE @query::text || '%') DESC, circulating DESC, value
+LIMIT @max_results::int;
+
+-- name: GetRarityScoreLeaderboardFromState :many
+WITH scores AS (
+	SELECT
+		owner,
+		SUM(CASE rarity
+			WHEN 'Legendary' THEN 25
+			WHEN 'Epic' THEN 10
+			WHEN 'Rare' THEN 5
+			WHEN 'Uncommon' THEN 2
+			ELSE 1
+		END)::bigint AS score
+	FROM nadmon_current_state
+	WHERE owner != '0x0000000000000000000000000000000000000000'
+		AND owner != ALL(@excluded_addresses::text[])
+	GROUP BY owner
+),
+ranked AS (
+	SELECT owner, score, RANK() OVER (ORDER BY score DESC) AS rank, COUNT(*) OVER () AS total_players
+	FROM scores
+)
+SELECT rank, owner, score, total_players
+FROM ranked
+ORDER BY rank, owner
+LIMIT @max_results::int OFFSET @skip::int;
+
+-- name: GetRarityScoreRankFromState :one
+WITH scores AS (
+	SELECT
+		owner,
+		SUM(CASE rarity
+			WHEN 'Legendary' THEN 25
+			WHEN 'Epic' THEN 10
+			WHEN 'Rare' THEN 5
+			WHEN 'Uncommon' THEN 2
+			ELSE 1
+		END)::bigint AS score
+	FROM nadmon_current_state
+	WHERE owner != '0x0000000000000000000000000000000000000000'
+		AND owner != ALL(@excluded_addresses::text[])
+	GROUP BY owner
+),
+ranked AS (
+	SELECT owner, score, RANK() OVER (ORDER BY score DESC) AS rank
+	FROM scores
+)
+SELECT rank, score
+FROM ranked
+WHERE owner = @player::text;
+
+-- name: GetSupplyBreakdownFromState :many
+WITH supply AS (
+	SELECT rarity, element, owner = '0x0000000000000000000000000000000000000000' AS burned
+	FROM nadmon_current_state
+)
+SELECT 'rarity'::text AS dimension, rarity::text AS value, COUNT(*) AS minted, COUNT(*) FILTER (WHERE burned) AS burned
+FROM supply GROUP BY rarity
+UNION ALL
+SELECT 'element'::text, element::text, COUNT(*), COUNT(*) FILTER (WHERE burned)
+FROM supply GROUP BY element
+ORDER BY dimension, value;
+
+-- name: GetTraitStatsFromState :many
+SELECT
+	(CASE WHEN @dimension::text = 'rarity' THEN rarity ELSE element END)::text AS value,
+	COUNT(*) AS nadmons,
+	SUM(hp)::bigint AS total_hp,
+	SUM(attack)::bigint AS total_attack,
+	SUM(defense)::bigint AS total_defense,
+	SUM(crit)::bigint AS total_crit,
+	COUNT(*) FILTER (WHERE evo > 1) AS evolved
+FROM nadmon_current_state
+WHERE owner != '0x0000000000000000000000000000000000000000'
+GROUP BY 1
+ORDER BY nadmons DESC, value;
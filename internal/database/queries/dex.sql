@@ -0,0 +1,7 @@
+-- Nadmondex: every type/element/rarity combination ever minted, burned tokens included.
+
+-- name: GetDexSpecies :many
+SELECT "nadmonType"::text AS nadmon_type, element::text AS element, rarity::text AS rarity
+FROM "NadmonNFT_NadmonMinted"
+GROUP BY "nadmonType", element, rarity
+ORDER BY "nadmonType", element, rarity;
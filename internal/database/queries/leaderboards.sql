@@ -0,0 +1,148 @@
+-- Leaderboard variants. Players are ranked with RANK(), so ties share a rank.
+-- Stat changes are credited to the token's owner at the time of the change.
+
+-- name: GetStatChangeLeaderboard :many
+WITH scores AS (
+	SELECT LOWER(o.owner)::text AS owner, COUNT(*) AS score
+	FROM "NadmonNFT_StatsChanged" s
+	CROSS JOIN LATERAL (
+		SELECT t."to" AS owner
+		FROM "NadmonNFT_Transfer" t
+		WHERE t."tokenId" = s."tokenId" AND t.db_write_timestamp <= s.db_write_timestamp
+		ORDER BY t.db_write_timestamp DESC
+		LIMIT 1
+	) o
+	WHERE s."changeType" = @change_type::text
+		AND LOWER(o.owner) != ALL(@excluded_addresses::text[])
+	GROUP BY LOWER(o.owner)
+),
+ranked AS (
+	SELECT owner, score, RANK() OVER (ORDER BY score DESC) AS rank, COUNT(*) OVER () AS total_players
+	FROM scores
+)
+SELECT rank, owner, score, total_players
+FROM ranked
+ORDER BY rank, owner
+LIMIT @max_results::int OFFSET @skip::int;
+
+-- name: GetStatChangeRank :one
+WITH scores AS (
+	SELECT LOWER(o.owner)::text AS owner, COUNT(*) AS score
+	FROM "NadmonNFT_StatsChanged" s
+	CROSS JOIN LATERAL (
+		SELECT t."to" AS owner
+		FROM "NadmonNFT_Transfer" t
+		WHERE t."tokenId" = s."tokenId" AND t.db_write_timestamp <= s.db_write_timestamp
+		ORDER BY t.db_write_timestamp DESC
+		LIMIT 1
+	) o
+	WHERE s."changeType" = @change_type::text
+		AND LOWER(o.owner) != ALL(@excluded_addresses::text[])
+	GROUP BY LOWER(o.owner)
+),
+ranked AS (
+	SELECT owner, score, RANK() OVER (ORDER BY score DESC) AS rank
+	FROM scores
+)
+SELECT rank, score
+FROM ranked
+WHERE owner = @player::text;
+
+-- name: GetPackLeaderboard :many
+WITH scores AS (
+	SELECT LOWER(player)::text AS owner, COUNT(*) AS score
+	FROM "NadmonNFT_PackMinted"
+	WHERE LOWER(player) != ALL(@excluded_addresses::text[])
+	GROUP BY LOWER(player)
+),
+ranked AS (
+	SELECT owner, score, RANK() OVER (ORDER BY score DESC) AS rank, COUNT(*) OVER () AS total_players
+	FROM scores
+)
+SELECT rank, owner, score, total_players
+FROM ranked
+ORDER BY rank, owner
+LIMIT @max_results::int OFFSET @skip::int;
+
+-- name: GetPackRank :one
+WITH scores AS (
+	SELECT LOWER(player)::text AS owner, COUNT(*) AS score
+	FROM "NadmonNFT_PackMinted"
+	WHERE LOWER(player) != ALL(@excluded_addresses::text[])
+	GROUP BY LOWER(player)
+),
+ranked AS (
+	SELECT owner, score, RANK() OVER (ORDER BY score DESC) AS rank
+	FROM scores
+)
+SELECT rank, score
+FROM ranked
+WHERE owner = @player::text;
+
+-- Rarity score: each held NFT is worth its rarity tier's weight
+-- (Common 1, Uncommon 2, Rare 5, Epic 10, Legendary 25)
+
+-- name: GetRarityScoreLeaderboard :many
+WITH current_owners AS (
+	SELECT DISTINCT ON (t."tokenId")
+		t."tokenId",
+		t."to" AS current_owner
+	FROM "NadmonNFT_Transfer" t
+	ORDER BY t."tokenId", t.db_write_timestamp DESC
+),
+scores AS (
+	SELECT
+		LOWER(COALESCE(co.current_owner, m.owner))::text AS owner,
+		SUM(CASE m.rarity
+			WHEN 'Legendary' THEN 25
+			WHEN 'Epic' THEN 10
+			WHEN 'Rare' THEN 5
+			WHEN 'Uncommon' THEN 2
+			ELSE 1
+		END)::bigint AS score
+	FROM "NadmonNFT_NadmonMinted" m
+	LEFT JOIN current_owners co ON m."tokenId" = co."tokenId"
+	WHERE COALESCE(co.current_owner, m.owner) != '0x0000000000000000000000000000000000000000'
+		AND LOWER(COALESCE(co.current_owner, m.owner)) != ALL(@excluded_addresses::text[])
+	GROUP BY LOWER(COALESCE(co.current_owner, m.owner))
+),
+ranked AS (
+	SELECT owner, score, RANK() OVER (ORDER BY score DESC) AS rank, COUNT(*) OVER () AS total_players
+	FROM scores
+)
+SELECT rank, owner, score, total_players
+FROM ranked
+ORDER BY rank, owner
+LIMIT @max_results::int OFFSET @skip::int;
+
+-- name: GetRarityScoreRank :one
+WITH current_owners AS (
+	SELECT DISTINCT ON (t."tokenId")
+		t."tokenId",
+		t."to" AS current_owner
+	FROM "NadmonNFT_Transfer" t
+	ORDER BY t."tokenId", t.db_write_timestamp DESC
+),
+scores AS (
+	SELECT
+		LOWER(COALESCE(co.current_owner, m.owner))::text AS owner,
+		SUM(CASE m.rarity
+			WHEN 'Legendary' THEN 25
+			WHEN 'Epic' THEN 10
+			WHEN 'Rare' THEN 5
+			WHEN 'Uncommon' THEN 2
+			ELSE 1
+		END)::bigint AS score
+	FROM "NadmonNFT_NadmonMinted" m
+	LEFT JOIN current_owners co ON m."tokenId" = co."tokenId"
+	WHERE COALESCE(co.current_owner, m.owner) != '0x0000000000000000000000000000000000000000'
+		AND LOWER(COALESCE(co.current_owner, m.owner)) != ALL(@excluded_addresses::text[])
+	GROUP BY LOWER(COALESCE(co.current_owner, m.owner))
+),
+ranked AS (
+	SELECT owner, score, RANK() OVER (ORDER BY score DESC) AS rank
+	FROM scores
+)
+SELECT rank, score
+FROM ranked
+WHERE owner = @player::text;
@@ -0,0 +1,442 @@
+-- Current state of a Nadmon is derived from three Envio tables:
+--   owner = latest Transfer."to" (falls back to the minter)
+--   stats = latest StatsChanged.new* by sequence (falls back to mint stats)
+-- Tokens whose current owner is the zero address are burned and excluded.
+-- Envio keeps addresses in mixed case, so owners are compared and returned lowercased.
+
+-- name: GetPlayerNadmons :many
+WITH current_owners AS (
+	SELECT DISTINCT ON (t."tokenId")
+		t."tokenId",
+		t."to" AS current_owner
+	FROM "NadmonNFT_Transfer" t
+	ORDER BY t."tokenId", t.db_write_timestamp DESC
+),
+latest_stats AS (
+	SELECT DISTINCT ON (s."tokenId")
+		s."tokenId", s."newHp", s."newAttack", s."newDefense",
+		s."newCrit", s."newFusion", s."newEvo", s.db_write_timestamp
+	FROM "NadmonNFT_StatsChanged" s
+	ORDER BY s."tokenId", s.sequence DESC
+)
+SELECT
+	m."tokenId"::bigint AS token_id,
+	LOWER(COALESCE(co.current_owner, m.owner))::text AS owner,
+	m."packId"::bigint AS pack_id,
+	m."nadmonType" AS nadmon_type,
+	m.element,
+	m.rarity,
+	COALESCE(ls."newHp", m.hp)::bigint AS hp,
+	COALESCE(ls."newAttack", m.attack)::bigint AS attack,
+	COALESCE(ls."newDefense", m.defense)::bigint AS defense,
+	COALESCE(ls."newCrit", m.crit)::bigint AS crit,
+	COALESCE(ls."newFusion", m.fusion)::bigint AS fusion,
+	COALESCE(ls."newEvo", m.evo)::bigint AS evo,
+	m.db_write_timestamp AS created_at,
+	COALESCE(ls.db_write_timestamp, m.db_write_timestamp) AS last_updated
+FROM "NadmonNFT_NadmonMinted" m
+LEFT JOIN current_owners co ON m."tokenId" = co."tokenId"
+LEFT JOIN latest_stats ls ON m."tokenId" = ls."tokenId"
+WHERE LOWER(COALESCE(co.current_owner, m.owner)) = @owner::text
+	AND COALESCE(co.current_owner, m.owner) != '0x0000000000000000000000000000000000000000'
+ORDER BY m."tokenId";
+
+-- name: GetNadmonsByIDs :many
+WITH current_owners AS (
+	SELECT DISTINCT ON (t."tokenId")
+		t."tokenId",
+		t."to" AS current_owner
+	FROM "NadmonNFT_Transfer" t
+	ORDER BY t."tokenId", t.db_write_timestamp DESC
+),
+latest_stats AS (
+	SELECT DISTINCT ON (s."tokenId")
+		s."tokenId", s."newHp", s."newAttack", s."newDefense",
+		s."newCrit", s."newFusion", s."newEvo", s.db_write_timestamp
+	FROM "NadmonNFT_StatsChanged" s
+	ORDER BY s."tokenId", s.sequence DESC
+)
+SELECT DISTINCT ON (m."tokenId")
+	m."tokenId"::bigint AS token_id,
+	LOWER(COALESCE(co.current_owner, m.owner))::text AS owner,
+	m."packId"::bigint AS pack_id,
+	m."nadmonType" AS nadmon_type,
+	m.element,
+	m.rarity,
+	COALESCE(ls."newHp", m.hp)::bigint AS hp,
+	COALESCE(ls."newAttack", m.attack)::bigint AS attack,
+	COALESCE(ls."newDefense", m.defense)::bigint AS defense,
+	COALESCE(ls."newCrit", m.crit)::bigint AS crit,
+	COALESCE(ls."newFusion", m.fusion)::bigint AS fusion,
+	COALESCE(ls."newEvo", m.evo)::bigint AS evo,
+	m.db_write_timestamp AS created_at,
+	COALESCE(ls.db_write_timestamp, m.db_write_timestamp) AS last_updated
+FROM "NadmonNFT_NadmonMinted" m
+LEFT JOIN current_owners co ON m."tokenId" = co."tokenId"
+LEFT JOIN latest_stats ls ON m."tokenId" = ls."tokenId"
+WHERE m."tokenId" = ANY(@token_ids::bigint[])
+	AND COALESCE(co.current_owner, m.owner) != '0x0000000000000000000000000000000000000000'
+ORDER BY m."tokenId";
+
+-- Circulating Nadmons after a token ID, so full-collection snapshots can be paged by token ID
+
+-- name: GetNadmonSnapshot :many
+WITH current_owners AS (
+	SELECT DISTINCT ON (t."tokenId")
+		t."tokenId",
+		t."to" AS current_owner
+	FROM "NadmonNFT_Transfer" t
+	ORDER BY t."tokenId", t.db_write_timestamp DESC
+),
+latest_stats AS (
+	SELECT DISTINCT ON (s."tokenId")
+		s."tokenId", s."newHp", s."newAttack", s."newDefense",
+		s."newCrit", s."newFusion", s."newEvo", s.db_write_timestamp
+	FROM "NadmonNFT_StatsChanged" s
+	ORDER BY s."tokenId", s.sequence DESC
+)
+SELECT
+	m."tokenId"::bigint AS token_id,
+	LOWER(COALESCE(co.current_owner, m.owner))::text AS owner,
+	m."packId"::bigint AS pack_id,
+	m."nadmonType" AS nadmon_type,
+	m.element,
+	m.rarity,
+	COALESCE(ls."newHp", m.hp)::bigint AS hp,
+	COALESCE(ls."newAttack", m.attack)::bigint AS attack,
+	COALESCE(ls."newDefense", m.defense)::bigint AS defense,
+	COALESCE(ls."newCrit", m.crit)::bigint AS crit,
+	COALESCE(ls."newFusion", m.fusion)::bigint AS fusion,
+	COALESCE(ls."newEvo", m.evo)::bigint AS evo,
+	m.db_write_timestamp AS created_at,
+	COALESCE(ls.db_write_timestamp, m.db_write_timestamp) AS last_updated
+FROM "NadmonNFT_NadmonMinted" m
+LEFT JOIN current_owners co ON m."tokenId" = co."tokenId"
+LEFT JOIN latest_stats ls ON m."tokenId" = ls."tokenId"
+WHERE m."tokenId" > @after_token_id::bigint
+	AND COALESCE(co.current_owner, m.owner) != '0x0000000000000000000000000000000000000000'
+ORDER BY m."tokenId"
+LIMIT @max_results::int;
+
+-- name: GetSingleNadmon :one
+WITH current_owners AS (
+	SELECT DISTINCT ON (t."tokenId")
+		t."tokenId",
+		t."to" AS current_owner
+	FROM "NadmonNFT_Transfer" t
+	WHERE t."tokenId" = @token_id::bigint
+	ORDER BY t."tokenId", t.db_write_timestamp DESC
+),
+latest_stats AS (
+	SELECT DISTINCT ON (s."tokenId")
+		s."tokenId", s."newHp", s."newAttack", s."newDefense",
+		s."newCrit", s."newFusion", s."newEvo", s.db_write_timestamp
+	FROM "NadmonNFT_StatsChanged" s
+	WHERE s."tokenId" = @token_id::bigint
+	ORDER BY s."tokenId", s.sequence DESC
+)
+SELECT
+	m."tokenId"::bigint AS token_id,
+	LOWER(COALESCE(co.current_owner, m.owner))::text AS owner,
+	m."packId"::bigint AS pack_id,
+	m."nadmonType" AS nadmon_type,
+	m.element,
+	m.rarity,
+	COALESCE(ls."newHp", m.hp)::bigint AS hp,
+	COALESCE(ls."newAttack", m.attack)::bigint AS attack,
+	COALESCE(ls."newDefense", m.defense)::bigint AS defense,
+	COALESCE(ls."newCrit", m.crit)::bigint AS crit,
+	COALESCE(ls."newFusion", m.fusion)::bigint AS fusion,
+	COALESCE(ls."newEvo", m.evo)::bigint AS evo,
+	m.db_write_timestamp AS created_at,
+	COALESCE(ls.db_write_timestamp, m.db_write_timestamp) AS last_updated
+FROM "NadmonNFT_NadmonMinted" m
+LEFT JOIN current_owners co ON m."tokenId" = co."tokenId"
+LEFT JOIN latest_stats ls ON m."tokenId" = ls."tokenId"
+WHERE m."tokenId" = @token_id::bigint
+	AND COALESCE(co.current_owner, m.owner) != '0x0000000000000000000000000000000000000000'
+LIMIT 1;
+
+-- name: GetNadmonHistory :many
+SELECT
+	"tokenId"::bigint AS token_id,
+	"changeType" AS change_type,
+	sequence::bigint AS sequence,
+	"newHp"::bigint AS new_hp,
+	"newAttack"::bigint AS new_attack,
+	"newDefense"::bigint AS new_defense,
+	"newCrit"::bigint AS new_crit,
+	"newFusion"::bigint AS new_fusion,
+	"newEvo"::bigint AS new_evo,
+	"oldHp"::bigint AS old_hp,
+	"oldAttack"::bigint AS old_attack,
+	"oldDefense"::bigint AS old_defense,
+	"oldCrit"::bigint AS old_crit,
+	"oldFusion"::bigint AS old_fusion,
+	"oldEvo"::bigint AS old_evo,
+	db_write_timestamp AS changed_at
+FROM "NadmonNFT_StatsChanged"
+WHERE "tokenId" = @token_id::bigint
+ORDER BY sequence ASC;
+
+-- Fusions of the given tokens, each with its candidate burns: Nadmons of the fused Nadmon's
+-- species burned by its owner at the time, indexed within @window_seconds of the fusion. One
+-- row per fusion and candidate; consumed_token_id is 0 for a fusion without candidates.
+
+-- name: GetFusionBurns :many
+WITH fusions AS (
+	SELECT
+		s."tokenId",
+		s.sequence,
+		s."oldFusion",
+		s."newFusion",
+		s.db_write_timestamp,
+		sp."nadmonType",
+		sp.element,
+		(
+			SELECT LOWER(t."to")
+			FROM "NadmonNFT_Transfer" t
+			WHERE t."tokenId" = s."tokenId" AND t.db_write_timestamp <= s.db_write_timestamp
+			ORDER BY t.db_write_timestamp DESC
+			LIMIT 1
+		) AS owner
+	FROM "NadmonNFT_StatsChanged" s
+	CROSS JOIN LATERAL (
+		SELECT m."nadmonType", m.element
+		FROM "NadmonNFT_NadmonMinted" m
+		WHERE m."tokenId" = s."tokenId"
+		LIMIT 1
+	) sp
+	WHERE s."tokenId" = ANY(@token_ids::bigint[])
+		AND s."changeType" = 'fusion'
+)
+SELECT
+	f."tokenId"::bigint AS token_id,
+	f.sequence::bigint AS sequence,
+	f."oldFusion"::bigint AS fusion_before,
+	f."newFusion"::bigint AS fusion_after,
+	f.db_write_timestamp AS fused_at,
+	COALESCE(c."tokenId", 0)::bigint AS consumed_token_id,
+	COALESCE(c."packId", 0)::bigint AS consumed_pack_id,
+	COALESCE(c."nadmonType", '')::text AS consumed_nadmon_type,
+	COALESCE(c.element, '')::text AS consumed_element,
+	COALESCE(c.rarity, '')::text AS consumed_rarity,
+	COALESCE(ls."newHp", c.hp, 0)::bigint AS consumed_hp,
+	COALESCE(ls."newAttack", c.attack, 0)::bigint AS consumed_attack,
+	COALESCE(ls."newDefense", c.defense, 0)::bigint AS consumed_defense,
+	COALESCE(ls."newCrit", c.crit, 0)::bigint AS consumed_crit,
+	COALESCE(ls."newFusion", c.fusion, 0)::bigint AS consumed_fusion,
+	COALESCE(ls."newEvo", c.evo, 0)::bigint AS consumed_evo,
+	c.burned_at
+FROM fusions f
+LEFT JOIN LATERAL (
+	SELECT DISTINCT ON (m."tokenId")
+		m."tokenId", m."packId", m."nadmonType", m.element, m.rarity,
+		m.hp, m.attack, m.defense, m.crit, m.fusion, m.evo,
+		b.db_write_timestamp AS burned_at
+	FROM "NadmonNFT_Transfer" b
+	JOIN "NadmonNFT_NadmonMinted" m ON m."tokenId" = b."tokenId"
+	WHERE b."to" = '0x0000000000000000000000000000000000000000'
+		AND LOWER(b."from") = f.owner
+		AND b."tokenId" <> f."tokenId"
+		AND b.db_write_timestamp BETWEEN f.db_write_timestamp - make_interval(secs => @window_seconds::float8)
+			AND f.db_write_timestamp + make_interval(secs => @window_seconds::float8)
+		AND m."nadmonType" = f."nadmonType"
+		AND m.element = f.element
+	ORDER BY m."tokenId"
+) c ON true
+LEFT JOIN LATERAL (
+	SELECT s."newHp", s."newAttack", s."newDefense", s."newCrit", s."newFusion", s."newEvo"
+	FROM "NadmonNFT_StatsChanged" s
+	WHERE s."tokenId" = c."tokenId"
+	ORDER BY s.sequence DESC
+	LIMIT 1
+) ls ON true
+ORDER BY f.db_write_timestamp, f.sequence, c."tokenId";
+
+-- name: GetNadmonStatuses :many
+WITH latest_transfers AS (
+	SELECT DISTINCT ON (t."tokenId")
+		t."tokenId",
+		t."to",
+		t.db_write_timestamp
+	FROM "NadmonNFT_Transfer" t
+	WHERE t."tokenId" = ANY(@token_ids::bigint[])
+	ORDER BY t."tokenId", t.db_write_timestamp DESC
+)
+SELECT DISTINCT ON (m."tokenId")
+	m."tokenId"::bigint AS token_id,
+	COALESCE(lt."to" = '0x0000000000000000000000000000000000000000', false)::bool AS burned,
+	CASE WHEN lt."to" = '0x0000000000000000000000000000000000000000' THEN lt.db_write_timestamp END AS burned_at
+FROM "NadmonNFT_NadmonMinted" m
+LEFT JOIN latest_transfers lt ON m."tokenId" = lt."tokenId"
+WHERE m."tokenId" = ANY(@token_ids::bigint[])
+ORDER BY m."tokenId";
+
+-- Player profiles read in one statement: the player's tokens are resolved once (owned) and
+-- shared by the inventory, the NFT count and the last activity, which is the latest of their
+-- pack purchases and the stat changes of the tokens they hold. The summary CTE always yields
+-- one row, so a player without Nadmons still gets their pack count.
+
+-- name: GetPlayerProfile :many
+WITH current_owners AS (
+	SELECT DISTINCT ON (t."tokenId")
+		t."tokenId",
+		t."to" AS current_owner
+	FROM "NadmonNFT_Transfer" t
+	ORDER BY t."tokenId", t.db_write_timestamp DESC
+),
+owned AS (
+	SELECT m."tokenId", m."packId", m."nadmonType", m.element, m.rarity,
+		m.hp, m.attack, m.defense, m.crit, m.fusion, m.evo, m.db_write_timestamp
+	FROM "NadmonNFT_NadmonMinted" m
+	LEFT JOIN current_owners co ON m."tokenId" = co."tokenId"
+	WHERE LOWER(COALESCE(co.current_owner, m.owner)) = @player::text
+		AND COALESCE(co.current_owner, m.owner) != '0x0000000000000000000000000000000000000000'
+),
+latest_stats AS (
+	SELECT DISTINCT ON (s."tokenId")
+		s."tokenId", s."newHp", s."newAttack", s."newDefense",
+		s."newCrit", s."newFusion", s."newEvo", s.db_write_timestamp
+	FROM "NadmonNFT_StatsChanged" s
+	WHERE s."tokenId" IN (SELECT "tokenId" FROM owned)
+	ORDER BY s."tokenId", s.sequence DESC
+),
+summary AS (
+	SELECT
+		(SELECT COUNT(*) FROM "NadmonNFT_PackMinted" p WHERE LOWER(p.player) = @player::text) AS pack_count,
+		GREATEST(
+			(SELECT MAX(p.db_write_timestamp) FROM "NadmonNFT_PackMinted" p WHERE LOWER(p.player) = @player::text),
+			(SELECT MAX(s.db_write_timestamp) FROM "NadmonNFT_StatsChanged" s WHERE s."tokenId" IN (SELECT "tokenId" FROM owned))
+		)::timestamp AS last_active
+)
+SELECT
+	summary.pack_count,
+	summary.last_active,
+	o."tokenId"::bigint AS token_id,
+	o."packId"::bigint AS pack_id,
+	o."nadmonType" AS nadmon_type,
+	o.element,
+	o.rarity,
+	COALESCE(ls."newHp", o.hp)::bigint AS hp,
+	COALESCE(ls."newAttack", o.attack)::bigint AS attack,
+	COALESCE(ls."newDefense", o.defense)::bigint AS defense,
+	COALESCE(ls."newCrit", o.crit)::bigint AS crit,
+	COALESCE(ls."newFusion", o.fusion)::bigint AS fusion,
+	COALESCE(ls."newEvo", o.evo)::bigint AS evo,
+	o.db_write_timestamp AS created_at,
+	COALESCE(ls.db_write_timestamp, o.db_write_timestamp) AS last_updated
+FROM summary
+LEFT JOIN owned o ON true
+LEFT JOIN latest_stats ls ON o."tokenId" = ls."tokenId"
+ORDER BY o."tokenId";
+
+-- name: GetPlayerSummary :many
+WITH current_owners AS (
+	SELECT DISTINCT ON (t."tokenId")
+		t."tokenId",
+		t."to" AS current_owner
+	FROM "NadmonNFT_Transfer" t
+	ORDER BY t."tokenId", t.db_write_timestamp DESC
+),
+owned AS (
+	SELECT m."tokenId", m.element, m.rarity, m.evo
+	FROM "NadmonNFT_NadmonMinted" m
+	LEFT JOIN current_owners co ON m."tokenId" = co."tokenId"
+	WHERE LOWER(COALESCE(co.current_owner, m.owner)) = @player::text
+		AND COALESCE(co.current_owner, m.owner) != '0x0000000000000000000000000000000000000000'
+),
+latest_evo AS (
+	SELECT DISTINCT ON (s."tokenId")
+		s."tokenId", s."newEvo"
+	FROM "NadmonNFT_StatsChanged" s
+	WHERE s."tokenId" IN (SELECT "tokenId" FROM owned)
+	ORDER BY s."tokenId", s.sequence DESC
+),
+summary AS (
+	SELECT
+		(SELECT COUNT(*) FROM "NadmonNFT_PackMinted" p WHERE LOWER(p.player) = @player::text) AS pack_count,
+		GREATEST(
+			(SELECT MAX(p.db_write_timestamp) FROM "NadmonNFT_PackMinted" p WHERE LOWER(p.player) = @player::text),
+			(SELECT MAX(s.db_write_timestamp) FROM "NadmonNFT_StatsChanged" s WHERE s."tokenId" IN (SELECT "tokenId" FROM owned))
+		)::timestamp AS last_active
+)
+SELECT
+	summary.pack_count,
+	summary.last_active,
+	o.rarity,
+	o.element,
+	COUNT(o."tokenId") AS nft_count,
+	COUNT(*) FILTER (WHERE COALESCE(le."newEvo", o.evo) > 1) AS evolved_count
+FROM summary
+LEFT JOIN owned o ON true
+LEFT JOIN latest_evo le ON o."tokenId" = le."tokenId"
+GROUP BY summary.pack_count, summary.last_active, o.rarity, o.element
+ORDER BY o.rarity, o.element;
+
+-- Delta sync of a player's inventory. Positions are indexer write times: Envio's sequences
+-- are counted per table and transfers have none.
+
+-- name: GetNewestEventWrite :one
+SELECT GREATEST(
+	(SELECT MAX(db_write_timestamp) FROM "NadmonNFT_NadmonMinted"),
+	(SELECT MAX(db_write_timestamp) FROM "NadmonNFT_Transfer"),
+	(SELECT MAX(db_write_timestamp) FROM "NadmonNFT_StatsChanged")
+)::timestamp AS newest;
+
+-- Tokens written after @since that the player holds (minted, received or changed stats) or
+-- no longer holds (transferred away or burned); removed tells them apart
+
+-- name: GetInventoryChanges :many
+WITH touched AS (
+	SELECT t."tokenId"
+	FROM "NadmonNFT_Transfer" t
+	WHERE t.db_write_timestamp > @since::timestamp
+		AND (LOWER(t."to") = @owner::text OR LOWER(t."from") = @owner::text)
+	UNION
+	SELECT s."tokenId"
+	FROM "NadmonNFT_StatsChanged" s
+	WHERE s.db_write_timestamp > @since::timestamp
+),
+current_owners AS (
+	SELECT DISTINCT ON (t."tokenId")
+		t."tokenId",
+		t."to" AS current_owner
+	FROM "NadmonNFT_Transfer" t
+	WHERE t."tokenId" IN (SELECT "tokenId" FROM touched)
+	ORDER BY t."tokenId", t.db_write_timestamp DESC
+),
+latest_stats AS (
+	SELECT DISTINCT ON (s."tokenId")
+		s."tokenId", s."newHp", s."newAttack", s."newDefense",
+		s."newCrit", s."newFusion", s."newEvo", s.db_write_timestamp
+	FROM "NadmonNFT_StatsChanged" s
+	WHERE s."tokenId" IN (SELECT "tokenId" FROM touched)
+	ORDER BY s."tokenId", s.sequence DESC
+)
+SELECT DISTINCT ON (m."tokenId")
+	m."tokenId"::bigint AS token_id,
+	LOWER(COALESCE(co.current_owner, m.owner))::text AS owner,
+	m."packId"::bigint AS pack_id,
+	m."nadmonType" AS nadmon_type,
+	m.element,
+	m.rarity,
+	COALESCE(ls."newHp", m.hp)::bigint AS hp,
+	COALESCE(ls."newAttack", m.attack)::bigint AS attack,
+	COALESCE(ls."newDefense", m.defense)::bigint AS defense,
+	COALESCE(ls."newCrit", m.crit)::bigint AS crit,
+	COALESCE(ls."newFusion", m.fusion)::bigint AS fusion,
+	COALESCE(ls."newEvo", m.evo)::bigint AS evo,
+	m.db_write_timestamp AS created_at,
+	COALESCE(ls.db_write_timestamp, m.db_write_timestamp) AS last_updated,
+	(LOWER(COALESCE(co.current_owner, m.owner)) != @owner::text)::boolean AS removed
+FROM "NadmonNFT_NadmonMinted" m
+LEFT JOIN current_owners co ON m."tokenId" = co."tokenId"
+LEFT JOIN latest_stats ls ON m."tokenId" = ls."tokenId"
+WHERE m."tokenId" IN (SELECT "tokenId" FROM touched)
+	AND (LOWER(COALESCE(co.current_owner, m.owner)) = @owner::text OR EXISTS (
+		SELECT 1 FROM "NadmonNFT_Transfer" t
+		WHERE t."tokenId" = m."tokenId" AND t.db_write_timestamp > @since::timestamp
+			AND LOWER(t."from") = @owner::text
+	))
+ORDER BY m."tokenId";
@@ -0,0 +1,71 @@
+-- name: GetPlayerPacks :many
+SELECT
+	"packId"::bigint AS pack_id,
+	LOWER(player) AS player,
+	"tokenIds"::bigint[] AS token_ids,
+	"paymentType" AS payment_type,
+	db_write_timestamp AS purchased_at
+FROM "NadmonNFT_PackMinted"
+WHERE LOWER(player) = @player::text
+ORDER BY sequence DESC;
+
+-- name: GetPackByID :one
+SELECT
+	"packId"::bigint AS pack_id,
+	LOWER(player) AS player,
+	"tokenIds"::bigint[] AS token_ids,
+	"paymentType" AS payment_type,
+	db_write_timestamp AS purchased_at
+FROM "NadmonNFT_PackMinted"
+WHERE "packId" = @pack_id::bigint;
+
+-- name: GetRecentPacks :many
+SELECT
+	"packId"::bigint AS pack_id,
+	LOWER(player) AS player,
+	"tokenIds"::bigint[] AS token_ids,
+	"paymentType" AS payment_type,
+	db_write_timestamp AS purchased_at
+FROM "NadmonNFT_PackMinted"
+ORDER BY sequence DESC
+LIMIT @max_results::int;
+
+-- name: GetPlayerPackSummary :many
+-- One row per recent pack (or a single row with NULL pack columns when the player has none),
+-- each carrying the same totals so the summary comes back in one round trip
+WITH player_packs AS (
+	SELECT "packId", "tokenIds", "paymentType", sequence, db_write_timestamp
+	FROM "NadmonNFT_PackMinted"
+	WHERE LOWER(player) = @player::text
+),
+payment_counts AS (
+	SELECT "paymentType" AS payment_type, COUNT(*) AS packs
+	FROM player_packs
+	GROUP BY "paymentType"
+),
+summary AS (
+	SELECT
+		(SELECT COUNT(*) FROM player_packs) AS total_packs,
+		(SELECT MIN(db_write_timestamp) FROM player_packs)::timestamp AS first_purchase_at,
+		(SELECT MAX(db_write_timestamp) FROM player_packs)::timestamp AS last_purchase_at,
+		ARRAY(SELECT payment_type FROM payment_counts ORDER BY payment_type)::text[] AS payment_types,
+		ARRAY(SELECT packs FROM payment_counts ORDER BY payment_type)::bigint[] AS payment_type_counts
+),
+recent AS (
+	SELECT "packId", "tokenIds", "paymentType", sequence, db_write_timestamp FROM player_packs
+	ORDER BY sequence DESC
+	LIMIT @max_recent::int
+)
+SELECT
+	summary.total_packs,
+	summary.first_purchase_at,
+	summary.last_purchase_at,
+	summary.payment_types,
+	summary.payment_type_counts,
+	r."packId"::bigint AS pack_id,
+	r."tokenIds"::bigint[] AS token_ids,
+	r."paymentType" AS payment_type,
+	r.db_write_timestamp AS purchased_at
+FROM summary
+LEFT JOIN recent r ON TRUE
+ORDER BY r.sequence DESC;
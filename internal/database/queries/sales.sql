@@ -0,0 +1,41 @@
+-- Marketplace sales, indexed by Envio from the NadmonMarketplace contract's Sale events.
+-- Prices are in wei; they are returned as text so they never overflow.
+
+-- name: GetNadmonSales :many
+SELECT
+	id,
+	"tokenId"::bigint AS token_id,
+	LOWER(seller)::text AS seller,
+	LOWER(buyer)::text AS buyer,
+	price::text AS price,
+	db_write_timestamp AS sold_at
+FROM "NadmonMarketplace_Sale"
+WHERE "tokenId" = @token_id::bigint
+ORDER BY db_write_timestamp DESC, id DESC
+LIMIT @max_results::int OFFSET @skip::int;
+
+-- name: CountNadmonSales :one
+SELECT COUNT(*) FROM "NadmonMarketplace_Sale" WHERE "tokenId" = @token_id::bigint;
+
+-- Market stats overall, per rarity and per element of the sold Nadmons, optionally limited to
+-- one rarity and/or element. The floor is the lowest sale since floor_since.
+
+-- name: GetMarketStats :many
+WITH sales AS (
+	SELECT s.price, s.db_write_timestamp, m.rarity, m.element
+	FROM "NadmonMarketplace_Sale" s
+	JOIN "NadmonNFT_NadmonMinted" m ON m."tokenId" = s."tokenId"
+	WHERE (@rarity::text = '' OR m.rarity = @rarity::text)
+		AND (@element::text = '' OR m.element = @element::text)
+)
+SELECT
+	(CASE WHEN GROUPING(rarity) = 0 THEN 'rarity' WHEN GROUPING(element) = 0 THEN 'element' ELSE 'all' END)::text AS dimension,
+	COALESCE(rarity, element, '')::text AS value,
+	COUNT(*) AS sales,
+	COALESCE(SUM(price), 0)::text AS volume,
+	(MIN(price) FILTER (WHERE db_write_timestamp AT TIME ZONE 'UTC' >= @floor_since::timestamptz))::text AS floor_price,
+	((ARRAY_AGG(price ORDER BY db_write_timestamp DESC))[1])::text AS last_price,
+	MAX(db_write_timestamp) AS last_sold_at
+FROM sales
+GROUP BY GROUPING SETS ((), (rarity), (element))
+ORDER BY dimension, value;
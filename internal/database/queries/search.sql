@@ -0,0 +1,26 @@
+-- name: GetSearchSuggestions :many
+WITH current_owners AS (
+	SELECT DISTINCT ON (t."tokenId")
+		t."tokenId",
+		t."to" AS current_owner
+	FROM "NadmonNFT_Transfer" t
+	ORDER BY t."tokenId", t.db_write_timestamp DESC
+),
+circulating AS (
+	SELECT m."nadmonType", m.element, m.rarity
+	FROM "NadmonNFT_NadmonMinted" m
+	LEFT JOIN current_owners co ON m."tokenId" = co."tokenId"
+	WHERE COALESCE(co.current_owner, m.owner) != '0x0000000000000000000000000000000000000000'
+),
+suggestions AS (
+	SELECT 'type'::text AS kind, "nadmonType"::text AS value, COUNT(*) AS circulating FROM circulating GROUP BY "nadmonType"
+	UNION ALL
+	SELECT 'element'::text, element::text, COUNT(*) FROM circulating GROUP BY element
+	UNION ALL
+	SELECT 'rarity'::text, rarity::text, COUNT(*) FROM circulating GROUP BY rarity
+)
+SELECT kind, value, circulating
+FROM suggestions
+WHERE value ILIKE '%' || @query::text || '%'
+ORDER BY (value ILIKE @query::text || '%') DESC, circulating DESC, value
+LIMIT @max_results::int;
@@ -0,0 +1,197 @@
+-- name: GetTopCollectors :many
+WITH current_owners AS (
+	SELECT DISTINCT ON (t."tokenId")
+		t."tokenId",
+		t."to" AS current_owner
+	FROM "NadmonNFT_Transfer" t
+	ORDER BY t."tokenId", t.db_write_timestamp DESC
+)
+SELECT
+	LOWER(COALESCE(co.current_owner, m.owner))::text AS owner,
+	COUNT(*) AS nft_count
+FROM "NadmonNFT_NadmonMinted" m
+LEFT JOIN current_owners co ON m."tokenId" = co."tokenId"
+WHERE COALESCE(co.current_owner, m.owner) != '0x0000000000000000000000000000000000000000'
+	AND LOWER(COALESCE(co.current_owner, m.owner)) != ALL(@excluded_addresses::text[])
+GROUP BY LOWER(COALESCE(co.current_owner, m.owner))
+ORDER BY nft_count DESC
+LIMIT @max_results::int;
+
+-- name: GetHolderBalances :many
+WITH current_owners AS (
+	SELECT DISTINCT ON (t."tokenId")
+		t."tokenId",
+		t."to" AS current_owner
+	FROM "NadmonNFT_Transfer" t
+	ORDER BY t."tokenId", t.db_write_timestamp DESC
+)
+SELECT COUNT(*) AS nft_count
+FROM "NadmonNFT_NadmonMinted" m
+LEFT JOIN current_owners co ON m."tokenId" = co."tokenId"
+WHERE COALESCE(co.current_owner, m.owner) != '0x0000000000000000000000000000000000000000'
+	AND LOWER(COALESCE(co.current_owner, m.owner)) != ALL(@excluded_addresses::text[])
+GROUP BY LOWER(COALESCE(co.current_owner, m.owner))
+ORDER BY nft_count DESC;
+
+-- Holders as of @as_of, replayed from the transfers written by then: a token minted by then
+-- belongs to the recipient of its latest transfer (or its minter), and burned tokens are left out.
+-- name: GetHolderSnapshot :many
+WITH owners_at AS (
+	SELECT DISTINCT ON (t."tokenId")
+		t."tokenId",
+		t."to" AS owner
+	FROM "NadmonNFT_Transfer" t
+	WHERE t.db_write_timestamp AT TIME ZONE 'UTC' <= @as_of::timestamptz
+	ORDER BY t."tokenId", t.db_write_timestamp DESC
+)
+SELECT
+	LOWER(COALESCE(o.owner, m.owner))::text AS owner,
+	COUNT(*) AS balance,
+	ARRAY_AGG(m."tokenId"::bigint ORDER BY m."tokenId")::bigint[] AS token_ids
+FROM "NadmonNFT_NadmonMinted" m
+LEFT JOIN owners_at o ON m."tokenId" = o."tokenId"
+WHERE m.db_write_timestamp AT TIME ZONE 'UTC' <= @as_of::timestamptz
+	AND COALESCE(o.owner, m.owner) != '0x0000000000000000000000000000000000000000'
+GROUP BY LOWER(COALESCE(o.owner, m.owner))
+ORDER BY balance DESC, owner;
+
+-- name: CountCirculatingNadmons :one
+WITH current_owners AS (
+	SELECT DISTINCT ON (t."tokenId")
+		t."tokenId",
+		t."to" AS current_owner
+	FROM "NadmonNFT_Transfer" t
+	ORDER BY t."tokenId", t.db_write_timestamp DESC
+)
+SELECT COUNT(*)
+FROM "NadmonNFT_NadmonMinted" m
+LEFT JOIN current_owners co ON m."tokenId" = co."tokenId"
+WHERE COALESCE(co.current_owner, m.owner) != '0x0000000000000000000000000000000000000000';
+
+-- name: CountUniqueCollectors :one
+WITH current_owners AS (
+	SELECT DISTINCT ON (t."tokenId")
+		t."tokenId",
+		t."to" AS current_owner
+	FROM "NadmonNFT_Transfer" t
+	ORDER BY t."tokenId", t.db_write_timestamp DESC
+)
+SELECT COUNT(DISTINCT LOWER(COALESCE(co.current_owner, m.owner)))
+FROM "NadmonNFT_NadmonMinted" m
+LEFT JOIN current_owners co ON m."tokenId" = co."tokenId"
+WHERE COALESCE(co.current_owner, m.owner) != '0x0000000000000000000000000000000000000000'
+	AND LOWER(COALESCE(co.current_owner, m.owner)) != ALL(@excluded_addresses::text[]);
+
+-- name: CountPacks :one
+SELECT COUNT(*) FROM "NadmonNFT_PackMinted";
+
+-- name: CountPackBuyers :one
+SELECT COUNT(DISTINCT LOWER(player)) FROM "NadmonNFT_PackMinted";
+
+-- name: CountEvolutions :one
+SELECT COUNT(*) FROM "NadmonNFT_StatsChanged" WHERE "changeType" = 'evolution';
+
+-- name: GetPackDistribution :one
+WITH per_player AS (
+	SELECT LOWER(player) AS player, COUNT(*) AS packs
+	FROM "NadmonNFT_PackMinted"
+	GROUP BY LOWER(player)
+)
+SELECT
+	COUNT(*) FILTER (WHERE packs = 1) AS single_pack,
+	COUNT(*) FILTER (WHERE packs BETWEEN 2 AND 5) AS two_to_five,
+	COUNT(*) FILTER (WHERE packs BETWEEN 6 AND 20) AS six_to_twenty,
+	COUNT(*) FILTER (WHERE packs > 20) AS over_twenty,
+	COUNT(*) AS total_players,
+	COALESCE(SUM(packs), 0)::bigint AS total_packs,
+	COALESCE(AVG(packs), 0)::float8 AS mean,
+	COALESCE(PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY packs), 0)::float8 AS median
+FROM per_player;
+
+-- name: GetSupplyBreakdown :many
+WITH current_owners AS (
+	SELECT DISTINCT ON (t."tokenId")
+		t."tokenId",
+		t."to" AS current_owner
+	FROM "NadmonNFT_Transfer" t
+	ORDER BY t."tokenId", t.db_write_timestamp DESC
+),
+supply AS (
+	SELECT
+		m.rarity,
+		m.element,
+		COALESCE(co.current_owner, m.owner) = '0x0000000000000000000000000000000000000000' AS burned
+	FROM "NadmonNFT_NadmonMinted" m
+	LEFT JOIN current_owners co ON m."tokenId" = co."tokenId"
+)
+SELECT 'rarity'::text AS dimension, rarity::text AS value, COUNT(*) AS minted, COUNT(*) FILTER (WHERE burned) AS burned
+FROM supply GROUP BY rarity
+UNION ALL
+SELECT 'element'::text, element::text, COUNT(*), COUNT(*) FILTER (WHERE burned)
+FROM supply GROUP BY element
+ORDER BY dimension, value;
+
+-- Pack odds: Nadmons dropped by packs bought in [from_time, to_time) per payment type,
+-- rarity and element. A NULL bound leaves that side of the window open.
+
+-- name: GetTraitStats :many
+WITH current_owners AS (
+	SELECT DISTINCT ON (t."tokenId")
+		t."tokenId",
+		t."to" AS current_owner
+	FROM "NadmonNFT_Transfer" t
+	ORDER BY t."tokenId", t.db_write_timestamp DESC
+),
+latest_stats AS (
+	SELECT DISTINCT ON (s."tokenId")
+		s."tokenId", s."newHp", s."newAttack", s."newDefense", s."newCrit", s."newEvo"
+	FROM "NadmonNFT_StatsChanged" s
+	ORDER BY s."tokenId", s.sequence DESC
+),
+circulating AS (
+	SELECT
+		CASE WHEN @dimension::text = 'rarity' THEN m.rarity ELSE m.element END AS value,
+		COALESCE(ls."newHp", m.hp) AS hp,
+		COALESCE(ls."newAttack", m.attack) AS attack,
+		COALESCE(ls."newDefense", m.defense) AS defense,
+		COALESCE(ls."newCrit", m.crit) AS crit,
+		COALESCE(ls."newEvo", m.evo) AS evo
+	FROM "NadmonNFT_NadmonMinted" m
+	LEFT JOIN current_owners co ON m."tokenId" = co."tokenId"
+	LEFT JOIN latest_stats ls ON m."tokenId" = ls."tokenId"
+	WHERE COALESCE(co.current_owner, m.owner) != '0x0000000000000000000000000000000000000000'
+)
+SELECT
+	value::text AS value,
+	COUNT(*) AS nadmons,
+	SUM(hp)::bigint AS total_hp,
+	SUM(attack)::bigint AS total_attack,
+	SUM(defense)::bigint AS total_defense,
+	SUM(crit)::bigint AS total_crit,
+	COUNT(*) FILTER (WHERE evo > 1) AS evolved
+FROM circulating
+GROUP BY value
+ORDER BY nadmons DESC, value;
+
+-- name: GetPackOdds :many
+WITH drops AS (
+	SELECT p."paymentType" AS payment_type, m.rarity, m.element
+	FROM "NadmonNFT_PackMinted" p
+	JOIN "NadmonNFT_NadmonMinted" m ON m."packId" = p."packId"
+	WHERE (@from_time::timestamptz IS NULL OR p.db_write_timestamp AT TIME ZONE 'UTC' >= @from_time::timestamptz)
+		AND (@to_time::timestamptz IS NULL OR p.db_write_timestamp AT TIME ZONE 'UTC' < @to_time::timestamptz)
+)
+SELECT payment_type::text AS payment_type, 'rarity'::text AS dimension, rarity::text AS value, COUNT(*) AS drops
+FROM drops GROUP BY payment_type, rarity
+UNION ALL
+SELECT payment_type::text, 'element'::text, element::text, COUNT(*)
+FROM drops GROUP BY payment_type, element
+ORDER BY payment_type, dimension, value;
+
+-- name: CountPacksByPaymentType :many
+SELECT "paymentType"::text AS payment_type, COUNT(*) AS packs
+FROM "NadmonNFT_PackMinted"
+WHERE (@from_time::timestamptz IS NULL OR db_write_timestamp AT TIME ZONE 'UTC' >= @from_time::timestamptz)
+	AND (@to_time::timestamptz IS NULL OR db_write_timestamp AT TIME ZONE 'UTC' < @to_time::timestamptz)
+GROUP BY "paymentType"
+ORDER BY "paymentType";
@@ -0,0 +1,31 @@
+-- Transfer history, newest first. Mints come from and burns go to the zero address.
+
+-- name: GetNadmonTransfers :many
+SELECT
+	id,
+	LOWER("from") AS from_address,
+	LOWER("to") AS to_address,
+	"tokenId"::bigint AS token_id,
+	db_write_timestamp AS transferred_at
+FROM "NadmonNFT_Transfer"
+WHERE "tokenId" = @token_id::bigint
+ORDER BY db_write_timestamp DESC, id DESC
+LIMIT @max_results::int OFFSET @skip::int;
+
+-- name: CountNadmonTransfers :one
+SELECT COUNT(*) FROM "NadmonNFT_Transfer" WHERE "tokenId" = @token_id::bigint;
+
+-- name: GetPlayerTransfers :many
+SELECT
+	id,
+	LOWER("from") AS from_address,
+	LOWER("to") AS to_address,
+	"tokenId"::bigint AS token_id,
+	db_write_timestamp AS transferred_at
+FROM "NadmonNFT_Transfer"
+WHERE LOWER("from") = @player::text OR LOWER("to") = @player::text
+ORDER BY db_write_timestamp DESC, id DESC
+LIMIT @max_results::int OFFSET @skip::int;
+
+-- name: CountPlayerTransfers :one
+SELECT COUNT(*) FROM "NadmonNFT_Transfer" WHERE LOWER("from") = @player::text OR LOWER("to") = @player::text;
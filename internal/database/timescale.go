@@ -0,0 +1,89 @@
+package database
+
+import (
+	"fmt"
+	"log"
+)
+
+// Event kinds mirrored into the analytics hypertable, keyed to their Envio source table
+var TimescaleEventSources = map[string]string{
+	"mints":     `"NadmonNFT_NadmonMinted"`,
+	"packs":     `"NadmonNFT_PackMinted"`,
+	"transfers": `"NadmonNFT_Transfer"`,
+}
+
+// SetupTimescale prepares the analytics hypertable and its hourly continuous aggregate.
+// It returns false without error when the TimescaleDB extension is not installed.
+func (edb *EnvioDB) SetupTimescale() (bool, error) {
+	var installed bool
+	err := edb.DB.QueryRow(`SELECT EXISTS (SELECT 1 FROM pg_extension WHERE extname = 'timescaledb')`).Scan(&installed)
+	if err != nil {
+		return false, fmt.Errorf("failed to detect timescaledb: %w", err)
+	}
+	if !installed {
+		return false, nil
+	}
+
+	log.Println("⏱️ TimescaleDB detected, preparing continuous aggregates...")
+
+	statements := []string{
+		// Envio tables are plain tables, so event timestamps are mirrored into a hypertable
+		`CREATE TABLE IF NOT EXISTS nadmon_analytics_events (
+			time TIMESTAMPTZ NOT NULL,
+			kind TEXT NOT NULL,
+			source_id TEXT NOT NULL,
+			UNIQUE (kind, source_id, time)
+		)`,
+		`SELECT create_hypertable('nadmon_analytics_events', 'time', if_not_exists => TRUE)`,
+		`CREATE MATERIALIZED VIEW IF NOT EXISTS nadmon_analytics_hourly
+		WITH (timescaledb.continuous, timescaledb.materialized_only = false) AS
+			SELECT time_bucket(INTERVAL '1 hour', time) AS bucket, kind, COUNT(*) AS events
+			FROM nadmon_analytics_events
+			GROUP BY bucket, kind
+		WITH NO DATA`,
+		`SELECT add_continuous_aggregate_policy('nadmon_analytics_hourly',
+			start_offset => INTERVAL '3 days',
+			end_offset => INTERVAL '1 hour',
+			schedule_interval => INTERVAL '15 minutes',
+			if_not_exists => TRUE)`,
+	}
+
+	for _, statement := range statements {
+		if _, err := edb.DB.Exec(statement); err != nil {
+			return false, fmt.Errorf("failed to set up timescale analytics: %w", err)
+		}
+	}
+
+	// Backfill the hypertable and materialize the full history once
+	if err := edb.SyncTimescaleEvents(); err != nil {
+		return false, err
+	}
+	if _, err := edb.DB.Exec(`CALL refresh_continuous_aggregate('nadmon_analytics_hourly', NULL, NULL)`); err != nil {
+		return false, fmt.Errorf("failed to refresh continuous aggregate: %w", err)
+	}
+
+	edb.Timescale = true
+	log.Println("✅ TimescaleDB continuous aggregates ready")
+	return true, nil
+}
+
+// SyncTimescaleEvents copies Envio events newer than the last mirrored timestamp into the hypertable
+func (edb *EnvioDB) SyncTimescaleEvents() error {
+	for kind, table := range TimescaleEventSources {
+		query := fmt.Sprintf(`
+			INSERT INTO nadmon_analytics_events (time, kind, source_id)
+			SELECT e.db_write_timestamp AT TIME ZONE 'UTC', $1, e.id
+			FROM %s e
+			WHERE e.db_write_timestamp AT TIME ZONE 'UTC' >= COALESCE(
+				(SELECT MAX(time) FROM nadmon_analytics_events WHERE kind = $1),
+				'-infinity'::timestamptz
+			)
+			ON CONFLICT DO NOTHING
+		`, table)
+
+		if _, err := edb.DB.Exec(query, kind); err != nil {
+			return fmt.Errorf("failed to sync %s into timescale: %w", kind, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package etag
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Middleware tags successful GET responses with a strong ETag computed from the body and
+// answers 304 Not Modified when it matches If-None-Match, so polling clients only download
+// responses that changed
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Next()
+			return
+		}
+
+		writer := &bufferedWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		if writer.skipped {
+			return
+		}
+		if writer.Status() != http.StatusOK {
+			writer.ResponseWriter.Write(writer.body.Bytes())
+			return
+		}
+
+		sum := sha256.Sum256(writer.body.Bytes())
+		tag := `"` + hex.EncodeToString(sum[:16]) + `"`
+		c.Header("ETag", tag)
+		if c.Writer.Header().Get("Cache-Control") == "" {
+			// Clients may keep the response but must revalidate before using it
+			c.Header("Cache-Control", "no-cache")
+		}
+
+		if matches(c.GetHeader("If-None-Match"), tag) {
+			c.Writer.Header().Del("Content-Type")
+			c.Writer.WriteHeader(http.StatusNotModified)
+			c.Writer.WriteHeaderNow()
+			return
+		}
+		c.Writer.Write(writer.body.Bytes())
+	}
+}
+
+// Skip sends the rest of the response without an ETag, writing through instead of
+// buffering; handlers streaming large bodies call it before writing
+func Skip(c *gin.Context) {
+	writer, ok := c.Writer.(*bufferedWriter)
+	if !ok || writer.skipped {
+		return
+	}
+	writer.skipped = true
+	writer.ResponseWriter.Write(writer.body.Bytes())
+	writer.body.Reset()
+}
+
+// matches reports whether an If-None-Match header lists tag; weak comparison is used, as
+// RFC 9110 requires for If-None-Match
+func matches(header, tag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// bufferedWriter holds the body back until the ETag is known
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body    bytes.Buffer
+	skipped bool
+}
+
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	if w.skipped {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.body.Write(data)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	if w.skipped {
+		return w.ResponseWriter.WriteString(s)
+	}
+	return w.body.WriteString(s)
+}
@@ -0,0 +1,53 @@
+// Package ethaddr validates and normalizes Ethereum addresses.
+package ethaddr
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// Valid reports whether address is a 0x-prefixed, 20-byte hex address. Mixed-case
+// addresses must carry a valid EIP-55 checksum; all-lowercase and all-uppercase
+// addresses carry none and are accepted as is.
+func Valid(address string) bool {
+	if len(address) != 42 || !strings.HasPrefix(address, "0x") {
+		return false
+	}
+	digits := address[2:]
+	if _, err := hex.DecodeString(digits); err != nil {
+		return false
+	}
+	if digits == strings.ToLower(digits) || digits == strings.ToUpper(digits) {
+		return true
+	}
+	return address == Checksum(address)
+}
+
+// Normalize returns the lowercase form used for lookups, cache keys and the WebSocket registry
+func Normalize(address string) string {
+	return strings.ToLower(address)
+}
+
+// Checksum returns the EIP-55 mixed-case encoding of address
+func Checksum(address string) string {
+	lower := strings.ToLower(strings.TrimPrefix(address, "0x"))
+
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte(lower))
+	hash := h.Sum(nil)
+
+	// Uppercase each letter whose corresponding hash nibble is 8 or higher
+	result := []byte(lower)
+	for i, c := range result {
+		nibble := hash[i/2]
+		if i%2 == 0 {
+			nibble >>= 4
+		}
+		if c >= 'a' && c <= 'f' && nibble&0x0f >= 8 {
+			result[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(result)
+}
@@ -0,0 +1,130 @@
+package events
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"nadmon-backend/internal/models"
+)
+
+// Event types of new indexer rows, pushed over WebSocket as messages of the same type except
+// for transfers
+const (
+	TypeNFTMinted      = "nft_minted"
+	TypePackMinted     = "pack_minted"
+	TypeNFTTransferred = "nft_transferred"
+	TypeStatsChanged   = "stats_changed"
+)
+
+// WebSocket message types a transfer is pushed as, so each side knows which way the Nadmon went
+const (
+	TypeNFTSent     = "nft_sent"
+	TypeNFTReceived = "nft_received"
+)
+
+const zeroAddress = "0x0000000000000000000000000000000000000000"
+
+// Event is a new Envio row translated into a message for the affected players
+type Event struct {
+	Type       string
+	Addresses  []string // lowercased recipients
+	TokenIDs   []int64  // tokens whose state changed
+	Data       interface{}
+	OccurredAt time.Time
+}
+
+// Notifier pushes real-time messages to connected players
+type Notifier interface {
+	NotifyUser(address string, messageType string, data interface{})
+}
+
+// NadmonLoader reads a Nadmon's current state, or nil when it doesn't exist or was burned
+type NadmonLoader interface {
+	GetSingleNadmon(ctx context.Context, tokenID int64) (*models.Nadmon, error)
+}
+
+// PackMintedEvent is the payload of a pack_minted message
+type PackMintedEvent struct {
+	PackID      int64   `json:"packId"`
+	Player      string  `json:"player"`
+	TokenIDs    []int64 `json:"tokenIds"`
+	PaymentType string  `json:"paymentType"`
+}
+
+// NFTMintedEvent is the payload of an nft_minted message
+type NFTMintedEvent struct {
+	TokenID    int64  `json:"tokenId"`
+	Owner      string `json:"owner"`
+	PackID     int64  `json:"packId"`
+	NadmonType string `json:"nadmonType"`
+	Element    string `json:"element"`
+	Rarity     string `json:"rarity"`
+}
+
+// NFTTransferred is the data of an nft_transferred event
+type NFTTransferred struct {
+	TokenID int64  `json:"tokenId"`
+	From    string `json:"from"`
+	To      string `json:"to"`
+	Burned  bool   `json:"burned"`
+}
+
+// TransferEvent is the payload of nft_sent and nft_received messages: the transfer and the
+// Nadmon's full current state, nil when it was burned or couldn't be read
+type TransferEvent struct {
+	NFTTransferred
+	Nadmon *models.Nadmon `json:"nadmon"`
+}
+
+// StatsChangedEvent is the payload of a stats_changed message
+type StatsChangedEvent struct {
+	TokenID    int64  `json:"tokenId"`
+	Owner      string `json:"owner"`
+	ChangeType string `json:"changeType"`
+	NewHP      int64  `json:"newHp"`
+	NewAttack  int64  `json:"newAttack"`
+	NewDefense int64  `json:"newDefense"`
+	NewCrit    int64  `json:"newCrit"`
+	NewFusion  int64  `json:"newFusion"`
+	NewEvo     int64  `json:"newEvo"`
+}
+
+// Payload schema versions, sent with every message as v. A version is bumped when a field is
+// removed or changes meaning; added fields keep it.
+const (
+	PackMintedVersion   = 1
+	NFTMintedVersion    = 1
+	TransferVersion     = 1
+	StatsChangedVersion = 1
+)
+
+func (PackMintedEvent) SchemaVersion() int   { return PackMintedVersion }
+func (NFTMintedEvent) SchemaVersion() int    { return NFTMintedVersion }
+func (TransferEvent) SchemaVersion() int     { return TransferVersion }
+func (StatsChangedEvent) SchemaVersion() int { return StatsChangedVersion }
+
+// Payloads maps the message types the pipeline sends to their payload types
+var Payloads = map[string]interface{ SchemaVersion() int }{
+	TypePackMinted:   PackMintedEvent{},
+	TypeNFTMinted:    NFTMintedEvent{},
+	TypeNFTSent:      TransferEvent{},
+	TypeNFTReceived:  TransferEvent{},
+	TypeStatsChanged: StatsChangedEvent{},
+	TypePackReady:    PackReadyEvent{},
+}
+
+// recipients lowercases addresses and drops the zero address and duplicates
+func recipients(addresses ...string) []string {
+	var result []string
+	seen := make(map[string]bool, len(addresses))
+	for _, address := range addresses {
+		address = strings.ToLower(address)
+		if address == "" || address == zeroAddress || seen[address] {
+			continue
+		}
+		seen[address] = true
+		result = append(result, address)
+	}
+	return result
+}
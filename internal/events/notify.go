@@ -0,0 +1,70 @@
+package events
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// notifyChannel is the Postgres channel the insert triggers notify on
+const notifyChannel = "nadmon_events"
+
+// InstallTriggers adds statement-level insert triggers on the Envio tables that NOTIFY
+// notifyChannel. Notifications only wake the pipeline; rows are still read by cursor, so a
+// missed notification (e.g. after the indexer recreates its tables) only delays delivery.
+func InstallTriggers(db *sql.DB) error {
+	function := fmt.Sprintf(`
+		CREATE OR REPLACE FUNCTION nadmon_notify_event() RETURNS trigger AS $$
+		BEGIN
+			PERFORM pg_notify('%s', TG_TABLE_NAME);
+			RETURN NULL;
+		END
+		$$ LANGUAGE plpgsql
+	`, notifyChannel)
+	if _, err := db.Exec(function); err != nil {
+		return fmt.Errorf("failed to create notify function: %w", err)
+	}
+
+	for _, src := range sources {
+		statements := []string{
+			fmt.Sprintf(`DROP TRIGGER IF EXISTS nadmon_notify ON %q`, src.table),
+			fmt.Sprintf(`CREATE TRIGGER nadmon_notify AFTER INSERT ON %q FOR EACH STATEMENT EXECUTE PROCEDURE nadmon_notify_event()`, src.table),
+		}
+		for _, statement := range statements {
+			if _, err := db.Exec(statement); err != nil {
+				return fmt.Errorf("failed to install notify trigger on %s: %w", src.table, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Listen subscribes to notifyChannel and returns a channel that fires when new rows land,
+// plus a function closing the listener
+func Listen(databaseURL string) (<-chan struct{}, func() error, error) {
+	listener := pq.NewListener(databaseURL, time.Second, time.Minute, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Printf("Warning: event listener: %v", err)
+		}
+	})
+	if err := listener.Listen(notifyChannel); err != nil {
+		listener.Close()
+		return nil, nil, fmt.Errorf("failed to listen on %s: %w", notifyChannel, err)
+	}
+
+	wake := make(chan struct{}, 1)
+	go func() {
+		for range listener.Notify {
+			// Coalesce bursts of notifications into a single pending wake-up
+			select {
+			case wake <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	return wake, listener.Close, nil
+}
@@ -0,0 +1,98 @@
+package events
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// TypePackReady is sent to the buyer once every Nadmon of their pack is indexed, so the
+// pack can be opened without polling for its tokens
+const TypePackReady = "pack_ready"
+
+// PackReadyVersion is the schema version of PackReadyEvent
+const PackReadyVersion = 1
+
+// pendingPackTTL is how long a pack waits for its Nadmons to be indexed before it is dropped
+const pendingPackTTL = time.Hour
+
+// PackReadyEvent is the payload of a pack_ready message
+type PackReadyEvent struct {
+	PackID   int64   `json:"packId"`
+	Player   string  `json:"player"`
+	TokenIDs []int64 `json:"tokenIds"`
+}
+
+func (PackReadyEvent) SchemaVersion() int { return PackReadyVersion }
+
+// pendingPack is a purchased pack whose Nadmons aren't all indexed yet
+type pendingPack struct {
+	pack  PackMintedEvent
+	since time.Time
+}
+
+// trackPack waits for the Nadmons of a newly indexed pack
+func (p *Pipeline) trackPack(pack PackMintedEvent) {
+	p.pending[pack.PackID] = pendingPack{pack: pack, since: time.Now()}
+}
+
+// checkPacks sends pack_ready for the pending packs whose Nadmons are all indexed. Envio may
+// write a pack's mints before or after the pack itself, so they are counted in the database.
+func (p *Pipeline) checkPacks() error {
+	if len(p.pending) == 0 {
+		return nil
+	}
+
+	packIDs := make([]int64, 0, len(p.pending))
+	for packID := range p.pending {
+		packIDs = append(packIDs, packID)
+	}
+	rows, err := p.db.Query(`
+		SELECT "packId"::bigint, array_agg(DISTINCT "tokenId"::bigint)
+		FROM "NadmonNFT_NadmonMinted"
+		WHERE "packId" = ANY($1)
+		GROUP BY "packId"
+	`, pq.Array(packIDs))
+	if err != nil {
+		return fmt.Errorf("failed to query pack mints: %w", err)
+	}
+	defer rows.Close()
+
+	indexed := make(map[int64]map[int64]bool, len(packIDs))
+	for rows.Next() {
+		var packID int64
+		var tokenIDs []int64
+		if err := rows.Scan(&packID, pq.Array(&tokenIDs)); err != nil {
+			return fmt.Errorf("failed to scan pack mints: %w", err)
+		}
+		indexed[packID] = make(map[int64]bool, len(tokenIDs))
+		for _, tokenID := range tokenIDs {
+			indexed[packID][tokenID] = true
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to read pack mints: %w", err)
+	}
+
+	for packID, pending := range p.pending {
+		ready := true
+		for _, tokenID := range pending.pack.TokenIDs {
+			ready = ready && indexed[packID][tokenID]
+		}
+		switch {
+		case ready:
+			delete(p.pending, packID)
+			// Subscribers saw the rows already; only the buyer is told
+			event := PackReadyEvent{PackID: packID, Player: pending.pack.Player, TokenIDs: pending.pack.TokenIDs}
+			for _, address := range recipients(event.Player) {
+				p.notifier.NotifyUser(address, TypePackReady, event)
+			}
+		case time.Since(pending.since) > pendingPackTTL:
+			delete(p.pending, packID)
+			log.Printf("Warning: pack %d still has Nadmons missing after %s, not sending pack_ready", packID, pendingPackTTL)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,216 @@
+package events
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// pollBatchSize caps how many rows are read per table per query
+const pollBatchSize = 500
+
+// loadTimeout bounds reading a transferred Nadmon for its messages
+const loadTimeout = 5 * time.Second
+
+// cursor is the (db_write_timestamp, id) position of the last row processed in a table
+type cursor struct {
+	ts time.Time
+	id string
+}
+
+// Pipeline detects new Envio rows and pushes them to the affected players' WebSocket
+// connections. Rows are read in (db_write_timestamp, id) order, so every row is delivered once.
+type Pipeline struct {
+	db        *sql.DB
+	notifier  Notifier
+	loader    NadmonLoader
+	listeners []func(Event)
+	cursors   map[string]cursor
+	pending   map[int64]pendingPack // packs waiting for their Nadmons, by pack ID
+
+	mu     sync.Mutex
+	status Status
+}
+
+// Status describes the pipeline's progress for readiness checks
+type Status struct {
+	LastPoll  time.Time // end of the last successful poll
+	NewestRow time.Time // db_write_timestamp of the newest row seen in any table
+	Err       error     // error of the last poll, nil once a poll succeeds again
+}
+
+// NewPipeline creates an event pipeline reading from the Envio database
+func NewPipeline(db *sql.DB, notifier Notifier) *Pipeline {
+	return &Pipeline{db: db, notifier: notifier, cursors: make(map[string]cursor), pending: make(map[int64]pendingPack)}
+}
+
+// Subscribe registers fn to be called for every event, e.g. to invalidate caches.
+// Subscribers must be registered before Run.
+func (p *Pipeline) Subscribe(fn func(Event)) {
+	p.listeners = append(p.listeners, fn)
+}
+
+// SetNadmonLoader makes transfer messages carry the Nadmon's full state, read from loader
+func (p *Pipeline) SetNadmonLoader(loader NadmonLoader) {
+	p.loader = loader
+}
+
+// Init positions every cursor at the newest existing row so only new rows produce events
+func (p *Pipeline) Init() error {
+	for _, src := range sources {
+		var c cursor
+		query := fmt.Sprintf(`
+			SELECT db_write_timestamp, id FROM %q
+			WHERE db_write_timestamp IS NOT NULL
+			ORDER BY db_write_timestamp DESC, id DESC
+			LIMIT 1
+		`, src.table)
+		err := p.db.QueryRow(query).Scan(&c.ts, &c.id)
+		if err != nil && err != sql.ErrNoRows {
+			return fmt.Errorf("failed to initialize %s cursor: %w", src.table, err)
+		}
+		p.cursors[src.table] = c
+	}
+	p.recordPoll(nil)
+	return nil
+}
+
+// Poll reads all rows added since the previous poll and dispatches their events, then
+// pack_ready for the packs whose Nadmons are now all indexed
+func (p *Pipeline) Poll() error {
+	for _, src := range sources {
+		for {
+			n, err := p.pollSource(src)
+			if err != nil {
+				p.recordPoll(err)
+				return err
+			}
+			if n < pollBatchSize {
+				break
+			}
+		}
+	}
+	if err := p.checkPacks(); err != nil {
+		p.recordPoll(err)
+		return err
+	}
+	p.recordPoll(nil)
+	return nil
+}
+
+// Status returns the pipeline's progress; it is safe to call while Run is polling
+func (p *Pipeline) Status() Status {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.status
+}
+
+// recordPoll updates the status after a poll; only the polling goroutine calls it
+func (p *Pipeline) recordPoll(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.status.Err = err
+	if err != nil {
+		return
+	}
+	p.status.LastPoll = time.Now()
+	for _, c := range p.cursors {
+		if c.ts.After(p.status.NewestRow) {
+			p.status.NewestRow = c.ts
+		}
+	}
+}
+
+// pollSource processes one batch of new rows from a table and returns how many were read
+func (p *Pipeline) pollSource(src source) (int, error) {
+	c := p.cursors[src.table]
+	query := fmt.Sprintf(`
+		SELECT %s FROM %q
+		WHERE (db_write_timestamp, id) > ($1, $2)
+		ORDER BY db_write_timestamp, id
+		LIMIT %d
+	`, src.columns, src.table, pollBatchSize)
+
+	rows, err := p.db.Query(query, c.ts, c.id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to poll %s: %w", src.table, err)
+	}
+	defer rows.Close()
+
+	n := 0
+	for rows.Next() {
+		id, ts, event, err := src.scan(rows)
+		if err != nil {
+			return n, fmt.Errorf("failed to scan %s row: %w", src.table, err)
+		}
+		n++
+		p.cursors[src.table] = cursor{ts: ts, id: id}
+		if event.Type != "" {
+			p.dispatch(event)
+		}
+	}
+	return n, rows.Err()
+}
+
+// dispatch sends an event to its subscribers and recipients. Subscribers go first, so the
+// current-state table and caches are up to date when players read back.
+func (p *Pipeline) dispatch(event Event) {
+	for _, fn := range p.listeners {
+		fn(event)
+	}
+	if pack, ok := event.Data.(PackMintedEvent); ok {
+		p.trackPack(pack)
+	}
+
+	if transfer, ok := event.Data.(NFTTransferred); ok {
+		p.notifyTransfer(transfer)
+		return
+	}
+	for _, address := range event.Addresses {
+		p.notifier.NotifyUser(address, event.Type, event.Data)
+	}
+}
+
+// notifyTransfer sends nft_sent to the sender and nft_received to the receiver, with the
+// Nadmon so both inventories can update without refetching
+func (p *Pipeline) notifyTransfer(transfer NFTTransferred) {
+	moved := TransferEvent{NFTTransferred: transfer}
+	if p.loader != nil && !transfer.Burned {
+		ctx, cancel := context.WithTimeout(context.Background(), loadTimeout)
+		nadmon, err := p.loader.GetSingleNadmon(ctx, transfer.TokenID)
+		cancel()
+		if err != nil {
+			log.Printf("Warning: failed to load transferred NFT %d: %v", transfer.TokenID, err)
+		}
+		moved.Nadmon = nadmon
+	}
+
+	for _, address := range recipients(transfer.From) {
+		p.notifier.NotifyUser(address, TypeNFTSent, moved)
+	}
+	for _, address := range recipients(transfer.To) {
+		p.notifier.NotifyUser(address, TypeNFTReceived, moved)
+	}
+}
+
+// Run polls every interval, and immediately whenever wake fires, until stop is closed
+func (p *Pipeline) Run(interval time.Duration, wake <-chan struct{}, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-wake:
+		case <-stop:
+			return
+		}
+		if err := p.Poll(); err != nil {
+			log.Printf("Warning: event pipeline: %v", err)
+		}
+	}
+}
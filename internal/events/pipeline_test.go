@@ -0,0 +1,153 @@
+//go:build integration
+
+package events
+
+import (
+	"testing"
+
+	"nadmon-backend/internal/fixtures"
+	"nadmon-backend/internal/repository"
+	"nadmon-backend/internal/testharness"
+)
+
+type notification struct {
+	address     string
+	messageType string
+}
+
+type recordingNotifier struct {
+	sent []notification
+	data []interface{}
+}
+
+func (n *recordingNotifier) NotifyUser(address string, messageType string, data interface{}) {
+	n.sent = append(n.sent, notification{address: address, messageType: messageType})
+	n.data = append(n.data, data)
+}
+
+func TestPipeline(t *testing.T) {
+	envioDB := testharness.StartEnvioDB(t)
+	db := envioDB.DB
+	notifier := &recordingNotifier{}
+
+	pipeline := NewPipeline(db, notifier)
+	pipeline.SetNadmonLoader(repository.NewNadmonRepository(envioDB))
+	if err := pipeline.Init(); err != nil {
+		t.Fatal(err)
+	}
+
+	var subscribed int
+	pipeline.Subscribe(func(Event) { subscribed++ })
+
+	// Existing fixture rows must not produce events
+	if err := pipeline.Poll(); err != nil {
+		t.Fatal(err)
+	}
+	if len(notifier.sent) != 0 {
+		t.Fatalf("expected no events for existing rows, got %v", notifier.sent)
+	}
+
+	inserts := []string{
+		`INSERT INTO "NadmonNFT_PackMinted" (id, player, "packId", sequence, "tokenIds", "paymentType", db_write_timestamp)
+		VALUES ('pack-4', '` + fixtures.Carol + `', 4, 4, '{16}', 'MON', NOW())`,
+		`INSERT INTO "NadmonNFT_Transfer" (id, "from", "to", "tokenId", db_write_timestamp)
+		VALUES ('transfer-5-gift', '` + fixtures.Alice + `', '` + fixtures.Carol + `', 5, NOW())`,
+		`INSERT INTO "NadmonNFT_StatsChanged" (id, "tokenId", sequence, "changeType",
+			"newHp", "newAttack", "newDefense", "newCrit", "newFusion", "newEvo",
+			"oldHp", "oldAttack", "oldDefense", "oldCrit", "oldFusion", "oldEvo", db_write_timestamp)
+		VALUES ('stats-5-evo', 5, 9, 'evolution', 160, 45, 25, 14, 0, 2, 130, 35, 20, 12, 0, 1, NOW())`,
+	}
+	for _, insert := range inserts {
+		if _, err := db.Exec(insert); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := pipeline.Poll(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[notification]bool{
+		{fixtures.Carol, TypePackMinted}:   true,
+		{fixtures.Alice, TypeNFTSent}:      true,
+		{fixtures.Carol, TypeNFTReceived}:  true,
+		{fixtures.Carol, TypeStatsChanged}: true,
+	}
+	if len(notifier.sent) != len(want) {
+		t.Fatalf("expected %d notifications, got %v", len(want), notifier.sent)
+	}
+	for i, n := range notifier.sent {
+		if !want[n] {
+			t.Errorf("unexpected notification %v", n)
+		}
+		// Both sides of the transfer get the Nadmon as it is after the transfer
+		if moved, ok := notifier.data[i].(TransferEvent); ok && (moved.Nadmon == nil || moved.Nadmon.TokenID != 5 || moved.Nadmon.Owner != fixtures.Carol) {
+			t.Errorf("expected %s to get token 5 now owned by carol, got %+v", n.messageType, moved.Nadmon)
+		}
+	}
+	if subscribed != 3 {
+		t.Errorf("expected 3 subscribed events, got %d", subscribed)
+	}
+
+	// A second poll must not redeliver
+	if err := pipeline.Poll(); err != nil {
+		t.Fatal(err)
+	}
+	if len(notifier.sent) != len(want) {
+		t.Errorf("events were delivered twice: %v", notifier.sent)
+	}
+
+	// Pack 4 is ready once its Nadmon is indexed
+	if _, err := db.Exec(`INSERT INTO "NadmonNFT_NadmonMinted" (id, owner, "tokenId", "packId", sequence, "nadmonType", element, rarity, hp, attack, defense, crit, fusion, evo, db_write_timestamp)
+		VALUES ('mint-16', '` + fixtures.Carol + `', 16, 4, 16, 'Pyro', 'Fire', 'Epic', 150, 40, 20, 10, 0, 1, NOW())`); err != nil {
+		t.Fatal(err)
+	}
+	if err := pipeline.Poll(); err != nil {
+		t.Fatal(err)
+	}
+	sent := notifier.sent[len(want):]
+	if len(sent) != 2 || sent[0] != (notification{fixtures.Carol, TypeNFTMinted}) || sent[1] != (notification{fixtures.Carol, TypePackReady}) {
+		t.Fatalf("expected nft_minted then pack_ready for carol, got %v", sent)
+	}
+	if ready, ok := notifier.data[len(notifier.data)-1].(PackReadyEvent); !ok || ready.PackID != 4 || len(ready.TokenIDs) != 1 {
+		t.Errorf("expected pack 4 ready with token 16, got %+v", notifier.data[len(notifier.data)-1])
+	}
+	if err := pipeline.Poll(); err != nil {
+		t.Fatal(err)
+	}
+	if len(notifier.sent) != len(want)+2 {
+		t.Errorf("pack_ready was sent twice: %v", notifier.sent)
+	}
+}
+
+func TestPipelineStatus(t *testing.T) {
+	db := testharness.StartEnvioDB(t).DB
+
+	pipeline := NewPipeline(db, &recordingNotifier{})
+	if err := pipeline.Init(); err != nil {
+		t.Fatal(err)
+	}
+	initial := pipeline.Status()
+	if initial.LastPoll.IsZero() || initial.NewestRow.IsZero() || initial.Err != nil {
+		t.Fatalf("expected a successful status after Init, got %+v", initial)
+	}
+
+	if _, err := db.Exec(`INSERT INTO "NadmonNFT_Transfer" (id, "from", "to", "tokenId", db_write_timestamp)
+		VALUES ('transfer-5-status', '` + fixtures.Alice + `', '` + fixtures.Carol + `', 5, NOW() + INTERVAL '1 hour')`); err != nil {
+		t.Fatal(err)
+	}
+	if err := pipeline.Poll(); err != nil {
+		t.Fatal(err)
+	}
+	if status := pipeline.Status(); !status.NewestRow.After(initial.NewestRow) || !status.LastPoll.After(initial.LastPoll) {
+		t.Errorf("expected the status to advance past %+v, got %+v", initial, status)
+	}
+
+	db.Close()
+	if err := pipeline.Poll(); err == nil {
+		t.Fatal("expected polling a closed database to fail")
+	}
+	if status := pipeline.Status(); status.Err == nil || !status.LastPoll.After(initial.LastPoll) {
+		t.Errorf("expected the failure to be recorded without moving LastPoll, got %+v", status)
+	}
+}
@@ -0,0 +1,77 @@
+package events
+
+import (
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// source describes how new rows of one Envio table become events. Every query selects
+// id and db_write_timestamp first and is paginated by the (db_write_timestamp, id) cursor.
+type source struct {
+	table   string
+	columns string
+	scan    func(rows *sql.Rows) (id string, ts time.Time, event Event, err error)
+}
+
+var sources = []source{
+	{
+		table:   "NadmonNFT_PackMinted",
+		columns: `id, db_write_timestamp, player, "packId"::bigint, "tokenIds"::bigint[], "paymentType"`,
+		scan: func(rows *sql.Rows) (string, time.Time, Event, error) {
+			var id string
+			var ts time.Time
+			var pack PackMintedEvent
+			err := rows.Scan(&id, &ts, &pack.Player, &pack.PackID, pq.Array(&pack.TokenIDs), &pack.PaymentType)
+			return id, ts, Event{Type: TypePackMinted, Addresses: recipients(pack.Player), TokenIDs: pack.TokenIDs, Data: pack, OccurredAt: ts}, err
+		},
+	},
+	{
+		table:   "NadmonNFT_NadmonMinted",
+		columns: `id, db_write_timestamp, owner, "tokenId"::bigint, "packId"::bigint, "nadmonType", element, rarity`,
+		scan: func(rows *sql.Rows) (string, time.Time, Event, error) {
+			var id string
+			var ts time.Time
+			var mint NFTMintedEvent
+			err := rows.Scan(&id, &ts, &mint.Owner, &mint.TokenID, &mint.PackID, &mint.NadmonType, &mint.Element, &mint.Rarity)
+			return id, ts, Event{Type: TypeNFTMinted, Addresses: recipients(mint.Owner), TokenIDs: []int64{mint.TokenID}, Data: mint, OccurredAt: ts}, err
+		},
+	},
+	{
+		// Mint transfers from the zero address are already covered by nft_minted
+		table:   "NadmonNFT_Transfer",
+		columns: `id, db_write_timestamp, "from", "to", "tokenId"::bigint`,
+		scan: func(rows *sql.Rows) (string, time.Time, Event, error) {
+			var id string
+			var ts time.Time
+			var transfer NFTTransferred
+			err := rows.Scan(&id, &ts, &transfer.From, &transfer.To, &transfer.TokenID)
+			if strings.EqualFold(transfer.From, zeroAddress) {
+				return id, ts, Event{}, err
+			}
+			transfer.Burned = strings.EqualFold(transfer.To, zeroAddress)
+			return id, ts, Event{Type: TypeNFTTransferred, Addresses: recipients(transfer.From, transfer.To), TokenIDs: []int64{transfer.TokenID}, Data: transfer, OccurredAt: ts}, err
+		},
+	},
+	{
+		table: "NadmonNFT_StatsChanged",
+		columns: `id, db_write_timestamp, "tokenId"::bigint, "changeType",
+			"newHp"::bigint, "newAttack"::bigint, "newDefense"::bigint, "newCrit"::bigint, "newFusion"::bigint, "newEvo"::bigint,
+			COALESCE(
+				(SELECT t."to" FROM "NadmonNFT_Transfer" t WHERE t."tokenId" = "NadmonNFT_StatsChanged"."tokenId" ORDER BY t.db_write_timestamp DESC LIMIT 1),
+				(SELECT m.owner FROM "NadmonNFT_NadmonMinted" m WHERE m."tokenId" = "NadmonNFT_StatsChanged"."tokenId" LIMIT 1),
+				''
+			)`,
+		scan: func(rows *sql.Rows) (string, time.Time, Event, error) {
+			var id string
+			var ts time.Time
+			var change StatsChangedEvent
+			err := rows.Scan(&id, &ts, &change.TokenID, &change.ChangeType,
+				&change.NewHP, &change.NewAttack, &change.NewDefense, &change.NewCrit, &change.NewFusion, &change.NewEvo,
+				&change.Owner)
+			return id, ts, Event{Type: TypeStatsChanged, Addresses: recipients(change.Owner), TokenIDs: []int64{change.TokenID}, Data: change, OccurredAt: ts}, err
+		},
+	},
+}
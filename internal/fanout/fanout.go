@@ -0,0 +1,137 @@
+package fanout
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// publishTimeout bounds every publish so a slow Redis never stalls the caller
+const publishTimeout = 2 * time.Second
+
+// Local delivers messages to the clients connected to this instance
+type Local interface {
+	NotifyUserOnChain(chain, address string, messageType string, data interface{})
+	BroadcastOnChain(chain, messageType string, data interface{})
+}
+
+// envelope is a message on the fan-out channel; an empty Address means broadcast
+type envelope struct {
+	Chain   string          `json:"chain,omitempty"`
+	Address string          `json:"address,omitempty"`
+	Type    string          `json:"type"`
+	Data    json.RawMessage `json:"data"`
+}
+
+// Redis publishes notifications to a Redis channel that every instance subscribes to and
+// delivers what it receives to its own clients, so a player gets messages whichever
+// replica holds their connection
+type Redis struct {
+	client  *redis.Client
+	channel string
+	local   Local
+}
+
+// NewRedis connects to the Redis server at url and fans messages out over channel to local
+func NewRedis(url, channel string, local Local) (*Redis, error) {
+	options, err := redis.ParseURL(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse redis url: %w", err)
+	}
+
+	client := redis.NewClient(options)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to connect to redis: %w", err)
+	}
+
+	return &Redis{client: client, channel: channel, local: local}, nil
+}
+
+// NotifyUser publishes a message for one player to every instance
+func (f *Redis) NotifyUser(address string, messageType string, data interface{}) {
+	f.publish("", address, messageType, data)
+}
+
+// NotifyUserOnChain publishes a message about an event on chain for one player
+func (f *Redis) NotifyUserOnChain(chain, address string, messageType string, data interface{}) {
+	f.publish(chain, address, messageType, data)
+}
+
+// BroadcastToAll publishes a message for every connected client to every instance
+func (f *Redis) BroadcastToAll(messageType string, data interface{}) {
+	f.publish("", "", messageType, data)
+}
+
+// BroadcastOnChain publishes a message about an event on chain for every connected client
+func (f *Redis) BroadcastOnChain(chain, messageType string, data interface{}) {
+	f.publish(chain, "", messageType, data)
+}
+
+// publish sends a message to the channel, delivering it locally instead if Redis fails so
+// at least this instance's clients get it
+func (f *Redis) publish(chain, address, messageType string, data interface{}) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		log.Printf("Warning: failed to encode %s for fan-out: %v", messageType, err)
+		return
+	}
+	message := envelope{Chain: chain, Address: address, Type: messageType, Data: encoded}
+	payload, err := json.Marshal(message)
+	if err != nil {
+		log.Printf("Warning: failed to encode %s for fan-out: %v", messageType, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+	if err := f.client.Publish(ctx, f.channel, payload).Err(); err != nil {
+		log.Printf("Warning: failed to publish %s, delivering locally: %v", messageType, err)
+		f.deliver(message)
+	}
+}
+
+// deliver hands a received message to the local clients
+func (f *Redis) deliver(message envelope) {
+	if message.Address == "" {
+		f.local.BroadcastOnChain(message.Chain, message.Type, message.Data)
+		return
+	}
+	f.local.NotifyUserOnChain(message.Chain, message.Address, message.Type, message.Data)
+}
+
+// Run delivers channel messages to the local clients until stop is closed. The
+// subscription reconnects by itself after Redis outages.
+func (f *Redis) Run(stop <-chan struct{}) {
+	sub := f.client.Subscribe(context.Background(), f.channel)
+	defer sub.Close()
+
+	messages := sub.Channel()
+	for {
+		select {
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+			var message envelope
+			if err := json.Unmarshal([]byte(msg.Payload), &message); err != nil {
+				log.Printf("Warning: ignoring malformed fan-out message: %v", err)
+				continue
+			}
+			f.deliver(message)
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Close closes the Redis connection pool
+func (f *Redis) Close() error {
+	return f.client.Close()
+}
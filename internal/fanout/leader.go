@@ -0,0 +1,116 @@
+package fanout
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// renewLease extends the lease only while this instance still holds it
+var renewLease = redis.NewScript(`
+	if redis.call("GET", KEYS[1]) == ARGV[1] then
+		return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+	end
+	return 0
+`)
+
+// Leader holds a Redis lease so a task every instance runs (such as pushing indexer events)
+// is only acted on by one of them at a time. A crashed leader's lease expires after ttl and
+// another instance takes over.
+type Leader struct {
+	client  *redis.Client
+	key     string
+	id      string
+	ttl     time.Duration
+	leading atomic.Bool
+}
+
+// Leader returns a lease on key shared with the other instances on the same Redis
+func (f *Redis) Leader(key string, ttl time.Duration) *Leader {
+	id := make([]byte, 8)
+	rand.Read(id)
+	return &Leader{client: f.client, key: key, id: hex.EncodeToString(id), ttl: ttl}
+}
+
+// IsLeader reports whether this instance currently holds the lease
+func (l *Leader) IsLeader() bool {
+	return l.leading.Load()
+}
+
+// Run acquires and renews the lease every third of its ttl until stop is closed, then
+// releases it
+func (l *Leader) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(l.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		l.refresh()
+		select {
+		case <-ticker.C:
+		case <-stop:
+			// Expire a held lease right away so another instance takes over without waiting
+			ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+			if l.leading.Load() {
+				renewLease.Run(ctx, l.client, []string{l.key}, l.id, 1)
+			}
+			cancel()
+			return
+		}
+	}
+}
+
+// refresh renews a held lease or tries to acquire a free one
+func (l *Leader) refresh() {
+	ctx, cancel := context.WithTimeout(context.Background(), publishTimeout)
+	defer cancel()
+
+	var leading bool
+	var err error
+	if l.leading.Load() {
+		var renewed int64
+		renewed, err = renewLease.Run(ctx, l.client, []string{l.key}, l.id, l.ttl.Milliseconds()).Int64()
+		leading = renewed == 1
+	} else {
+		leading, err = l.client.SetNX(ctx, l.key, l.id, l.ttl).Result()
+	}
+	if err != nil {
+		// Without Redis nobody can confirm the lease; keep the last known state until it expires
+		log.Printf("Warning: failed to refresh %s lease: %v", l.key, err)
+		return
+	}
+
+	if leading != l.leading.Swap(leading) {
+		if leading {
+			log.Printf("👑 Acquired %s lease", l.key)
+		} else {
+			log.Printf("Lost %s lease", l.key)
+		}
+	}
+}
+
+// Notifier is what the event pipeline notifies players through
+type Notifier interface {
+	NotifyUser(address string, messageType string, data interface{})
+}
+
+// LeaderOnly drops notifications unless leader holds its lease, so the event pipeline
+// running on every instance pushes each event once
+func LeaderOnly(notifier Notifier, leader *Leader) Notifier {
+	return &leaderNotifier{notifier: notifier, leader: leader}
+}
+
+type leaderNotifier struct {
+	notifier Notifier
+	leader   *Leader
+}
+
+func (n *leaderNotifier) NotifyUser(address string, messageType string, data interface{}) {
+	if n.leader.IsLeader() {
+		n.notifier.NotifyUser(address, messageType, data)
+	}
+}
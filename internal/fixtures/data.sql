@@ -0,0 +1,49 @@
+-- Fixture data shaped like production: two packs for alice, one for bob,
+-- a secondary-market transfer, an evolution, a fusion and a fusion burn.
+--
+-- alice = 0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa
+-- bob   = 0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb
+-- carol = 0xcccccccccccccccccccccccccccccccccccccccc (never bought a pack)
+
+INSERT INTO "NadmonNFT_PackMinted" (id, player, "packId", sequence, "tokenIds", "paymentType", db_write_timestamp) VALUES
+	('pack-1', '0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa', 1, 1, '{1,2,3,4,5}', 'MON', '2025-07-01 10:00:00'),
+	('pack-2', '0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb', 2, 2, '{6,7,8,9,10}', 'COOKIES', '2025-07-01 11:00:00'),
+	('pack-3', '0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa', 3, 3, '{11,12,13,14,15}', 'MON', '2025-07-02 09:00:00');
+
+INSERT INTO "NadmonNFT_NadmonMinted" (id, owner, "tokenId", "packId", sequence, "nadmonType", element, rarity, hp, attack, defense, crit, fusion, evo, db_write_timestamp) VALUES
+	('mint-1',  '0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa', 1,  1, 1,  'Urchin', 'Water',  'Common',    110, 20, 22, 5,  0, 1, '2025-07-01 10:00:00'),
+	('mint-2',  '0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa', 2,  1, 2,  'Pyro',   'Fire',   'Rare',      120, 30, 18, 8,  0, 1, '2025-07-01 10:00:00'),
+	('mint-3',  '0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa', 3,  1, 3,  'Sprout', 'Nature', 'Common',    100, 18, 25, 4,  0, 1, '2025-07-01 10:00:00'),
+	('mint-4',  '0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa', 4,  1, 4,  'Pyro',   'Fire',   'Common',    115, 26, 17, 6,  0, 1, '2025-07-01 10:00:00'),
+	('mint-5',  '0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa', 5,  1, 5,  'Volt',   'Electric','Epic',     130, 35, 20, 12, 0, 1, '2025-07-01 10:00:00'),
+	('mint-6',  '0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb', 6,  2, 6,  'Urchin', 'Water',  'Common',    108, 21, 23, 5,  0, 1, '2025-07-01 11:00:00'),
+	('mint-7',  '0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb', 7,  2, 7,  'Rocky',  'Earth',  'Uncommon',  125, 22, 30, 4,  0, 1, '2025-07-01 11:00:00'),
+	('mint-8',  '0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb', 8,  2, 8,  'Frosty', 'Ice',    'Common',    105, 19, 21, 6,  0, 1, '2025-07-01 11:00:00'),
+	('mint-9',  '0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb', 9,  2, 9,  'Shade',  'Dark',   'Legendary', 150, 40, 28, 15, 0, 1, '2025-07-01 11:00:00'),
+	('mint-10', '0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb', 10, 2, 10, 'Sprout', 'Nature', 'Common',    101, 17, 26, 4,  0, 1, '2025-07-01 11:00:00'),
+	('mint-11', '0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa', 11, 3, 11, 'Glim',   'Light',  'Uncommon',  112, 24, 22, 7,  0, 1, '2025-07-02 09:00:00'),
+	('mint-12', '0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa', 12, 3, 12, 'Urchin', 'Water',  'Rare',      118, 25, 24, 6,  0, 1, '2025-07-02 09:00:00'),
+	('mint-13', '0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa', 13, 3, 13, 'Pyro',   'Fire',   'Common',    113, 27, 16, 6,  0, 1, '2025-07-02 09:00:00'),
+	('mint-14', '0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa', 14, 3, 14, 'Rocky',  'Earth',  'Common',    122, 20, 29, 3,  0, 1, '2025-07-02 09:00:00'),
+	('mint-15', '0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa', 15, 3, 15, 'Volt',   'Electric','Common',   111, 28, 18, 9,  0, 1, '2025-07-02 09:00:00');
+
+-- Mint transfers from the zero address, followed by secondary activity:
+--   token 3  alice -> carol (market sale)
+--   token 13 alice -> burn  (consumed by the fusion of token 4)
+INSERT INTO "NadmonNFT_Transfer" (id, "from", "to", "tokenId", db_write_timestamp)
+SELECT 'transfer-mint-' || "tokenId", '0x0000000000000000000000000000000000000000', owner, "tokenId", db_write_timestamp
+FROM "NadmonNFT_NadmonMinted";
+
+INSERT INTO "NadmonNFT_Transfer" (id, "from", "to", "tokenId", db_write_timestamp) VALUES
+	('transfer-3-sale',  '0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa', '0xcccccccccccccccccccccccccccccccccccccccc', 3,  '2025-07-03 12:00:00'),
+	('transfer-13-burn', '0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa', '0x0000000000000000000000000000000000000000', 13, '2025-07-04 08:00:00');
+
+INSERT INTO "NadmonNFT_StatsChanged" (id, "tokenId", sequence, "changeType",
+	"newHp", "newAttack", "newDefense", "newCrit", "newFusion", "newEvo",
+	"oldHp", "oldAttack", "oldDefense", "oldCrit", "oldFusion", "oldEvo", db_write_timestamp) VALUES
+	('stats-2-evo',    2, 1, 'evolution', 150, 40, 25, 10, 0, 2, 120, 30, 18, 8, 0, 1, '2025-07-03 15:00:00'),
+	('stats-4-fusion', 4, 2, 'fusion',    125, 30, 20, 7,  1, 1, 115, 26, 17, 6, 0, 1, '2025-07-04 08:00:00');
+
+-- The sale of token 3 on the marketplace, 1.5 MON
+INSERT INTO "NadmonMarketplace_Sale" (id, "tokenId", seller, buyer, price, db_write_timestamp) VALUES
+	('sale-3', 3, '0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa', '0xcccccccccccccccccccccccccccccccccccccccc', 1500000000000000000, '2025-07-03 12:00:00');
@@ -0,0 +1,36 @@
+package fixtures
+
+import (
+	"database/sql"
+	_ "embed"
+	"fmt"
+)
+
+// Schema creates the Envio event tables the backend reads from
+//
+//go:embed schema.sql
+var Schema string
+
+// Data populates the Envio tables with a small, production-shaped dataset
+//
+//go:embed data.sql
+var Data string
+
+// Well-known fixture addresses
+const (
+	Alice       = "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	Bob         = "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	Carol       = "0xcccccccccccccccccccccccccccccccccccccccc"
+	ZeroAddress = "0x0000000000000000000000000000000000000000"
+)
+
+// Load creates the Envio schema and inserts the fixture dataset
+func Load(db *sql.DB) error {
+	if _, err := db.Exec(Schema); err != nil {
+		return fmt.Errorf("failed to create envio schema: %w", err)
+	}
+	if _, err := db.Exec(Data); err != nil {
+		return fmt.Errorf("failed to load fixtures: %w", err)
+	}
+	return nil
+}
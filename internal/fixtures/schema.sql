@@ -0,0 +1,67 @@
+-- Minimal replica of the Envio-generated tables the backend reads from.
+-- Column names and types mirror what the indexer creates for NadmonNFT events.
+
+CREATE TABLE IF NOT EXISTS "NadmonNFT_NadmonMinted" (
+	id TEXT PRIMARY KEY,
+	owner TEXT NOT NULL,
+	"tokenId" NUMERIC NOT NULL,
+	"packId" NUMERIC NOT NULL,
+	sequence NUMERIC NOT NULL,
+	"nadmonType" TEXT NOT NULL,
+	element TEXT NOT NULL,
+	rarity TEXT NOT NULL,
+	hp NUMERIC NOT NULL,
+	attack NUMERIC NOT NULL,
+	defense NUMERIC NOT NULL,
+	crit NUMERIC NOT NULL,
+	fusion NUMERIC NOT NULL,
+	evo NUMERIC NOT NULL,
+	db_write_timestamp TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS "NadmonNFT_PackMinted" (
+	id TEXT PRIMARY KEY,
+	player TEXT NOT NULL,
+	"packId" NUMERIC NOT NULL,
+	sequence NUMERIC NOT NULL,
+	"tokenIds" NUMERIC[] NOT NULL,
+	"paymentType" TEXT NOT NULL,
+	db_write_timestamp TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS "NadmonNFT_StatsChanged" (
+	id TEXT PRIMARY KEY,
+	"tokenId" NUMERIC NOT NULL,
+	sequence NUMERIC NOT NULL,
+	"changeType" TEXT NOT NULL,
+	"newHp" NUMERIC NOT NULL,
+	"newAttack" NUMERIC NOT NULL,
+	"newDefense" NUMERIC NOT NULL,
+	"newCrit" NUMERIC NOT NULL,
+	"newFusion" NUMERIC NOT NULL,
+	"newEvo" NUMERIC NOT NULL,
+	"oldHp" NUMERIC NOT NULL,
+	"oldAttack" NUMERIC NOT NULL,
+	"oldDefense" NUMERIC NOT NULL,
+	"oldCrit" NUMERIC NOT NULL,
+	"oldFusion" NUMERIC NOT NULL,
+	"oldEvo" NUMERIC NOT NULL,
+	db_write_timestamp TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS "NadmonNFT_Transfer" (
+	id TEXT PRIMARY KEY,
+	"from" TEXT NOT NULL,
+	"to" TEXT NOT NULL,
+	"tokenId" NUMERIC NOT NULL,
+	db_write_timestamp TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS "NadmonMarketplace_Sale" (
+	id TEXT PRIMARY KEY,
+	"tokenId" NUMERIC NOT NULL,
+	seller TEXT NOT NULL,
+	buyer TEXT NOT NULL,
+	price NUMERIC NOT NULL,
+	db_write_timestamp TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
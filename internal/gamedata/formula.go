@@ -0,0 +1,195 @@
+package gamedata
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Variables a derived-stat formula can read, the stats of a Nadmon
+var Variables = []string{"hp", "attack", "defense", "crit", "fusion", "evo"}
+
+// Formula is a compiled derived-stat formula: integer arithmetic (+, -, *, / truncating,
+// parentheses) over the Variables. Dividing by zero yields 0.
+type Formula struct {
+	source string
+	eval   func(vars map[string]int64) int64
+}
+
+// String returns the formula's source
+func (f *Formula) String() string {
+	return f.source
+}
+
+// Eval computes the formula; missing variables count as 0
+func (f *Formula) Eval(vars map[string]int64) int64 {
+	return f.eval(vars)
+}
+
+// MarshalJSON encodes the formula as its source
+func (f *Formula) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.source)
+}
+
+// UnmarshalJSON compiles a formula from its source
+func (f *Formula) UnmarshalJSON(data []byte) error {
+	var source string
+	if err := json.Unmarshal(data, &source); err != nil {
+		return err
+	}
+	compiled, err := Compile(source)
+	if err != nil {
+		return err
+	}
+	*f = *compiled
+	return nil
+}
+
+// Compile parses a formula
+func Compile(source string) (*Formula, error) {
+	p := &parser{source: source}
+	p.next()
+	eval, err := p.expression()
+	if err != nil {
+		return nil, err
+	}
+	if p.token != "" {
+		return nil, fmt.Errorf("unexpected %q at %d", p.token, p.start)
+	}
+	return &Formula{source: source, eval: eval}, nil
+}
+
+type evalFunc = func(vars map[string]int64) int64
+
+// parser is a recursive-descent parser over the formula grammar:
+//
+//	expression = term { ("+" | "-") term }
+//	term       = factor { ("*" | "/") factor }
+//	factor     = number | variable | "(" expression ")" | "-" factor
+type parser struct {
+	source string
+	pos    int
+	start  int
+	token  string // "" at the end of the source
+}
+
+// next reads the next token
+func (p *parser) next() {
+	for p.pos < len(p.source) && p.source[p.pos] == ' ' {
+		p.pos++
+	}
+	p.start = p.pos
+	if p.pos == len(p.source) {
+		p.token = ""
+		return
+	}
+
+	isWord := func(r byte) bool { return r == '_' || unicode.IsLetter(rune(r)) || unicode.IsDigit(rune(r)) }
+	if isWord(p.source[p.pos]) {
+		for p.pos < len(p.source) && isWord(p.source[p.pos]) {
+			p.pos++
+		}
+	} else {
+		p.pos++
+	}
+	p.token = p.source[p.start:p.pos]
+}
+
+func (p *parser) expression() (evalFunc, error) {
+	left, err := p.term()
+	if err != nil {
+		return nil, err
+	}
+	for p.token == "+" || p.token == "-" {
+		op := p.token
+		p.next()
+		right, err := p.term()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		if op == "+" {
+			left = func(vars map[string]int64) int64 { return l(vars) + right(vars) }
+		} else {
+			left = func(vars map[string]int64) int64 { return l(vars) - right(vars) }
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) term() (evalFunc, error) {
+	left, err := p.factor()
+	if err != nil {
+		return nil, err
+	}
+	for p.token == "*" || p.token == "/" {
+		op := p.token
+		p.next()
+		right, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		if op == "*" {
+			left = func(vars map[string]int64) int64 { return l(vars) * right(vars) }
+		} else {
+			left = func(vars map[string]int64) int64 {
+				divisor := right(vars)
+				if divisor == 0 {
+					return 0
+				}
+				return l(vars) / divisor
+			}
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) factor() (evalFunc, error) {
+	token, start := p.token, p.start
+	switch {
+	case token == "":
+		return nil, fmt.Errorf("unexpected end of formula")
+	case token == "(":
+		p.next()
+		inner, err := p.expression()
+		if err != nil {
+			return nil, err
+		}
+		if p.token != ")" {
+			return nil, fmt.Errorf("missing ) at %d", p.start)
+		}
+		p.next()
+		return inner, nil
+	case token == "-":
+		p.next()
+		operand, err := p.factor()
+		if err != nil {
+			return nil, err
+		}
+		return func(vars map[string]int64) int64 { return -operand(vars) }, nil
+	case unicode.IsDigit(rune(token[0])):
+		value, err := strconv.ParseInt(token, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q at %d", token, start)
+		}
+		p.next()
+		return func(map[string]int64) int64 { return value }, nil
+	case isVariable(token):
+		p.next()
+		return func(vars map[string]int64) int64 { return vars[token] }, nil
+	}
+	return nil, fmt.Errorf("unexpected %q at %d, expected a number, (, or one of: %s", token, start, strings.Join(Variables, ", "))
+}
+
+// isVariable reports whether name is one of the Variables
+func isVariable(name string) bool {
+	for _, variable := range Variables {
+		if name == variable {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,143 @@
+// Package gamedata holds the game balance data the API derives values from: element colors,
+// element matchups and derived-stat formulas. It is read from a versioned JSON file, so
+// balance changes ship without a backend release, and can be reloaded while serving.
+package gamedata
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync/atomic"
+)
+
+// Derived stats the API requires a formula for
+const (
+	StatSpeed = "speed"
+	StatPower = "power"
+)
+
+// RequiredStats lists the derived stats every data file must define
+var RequiredStats = []string{StatSpeed, StatPower}
+
+// defaultData is used when no GAME_DATA_FILE is configured
+//
+//go:embed default.json
+var defaultData []byte
+
+// Element is the display data of an element
+type Element struct {
+	Color string `json:"color"`
+}
+
+// Data is one version of the game data
+type Data struct {
+	Version      string             `json:"version"`
+	DefaultColor string             `json:"defaultColor"`
+	Elements     map[string]Element `json:"elements"`
+	// Matchups holds the damage multiplier of an attacking element against a defending one;
+	// pairs that aren't listed deal normal (1x) damage
+	Matchups map[string]map[string]float64 `json:"matchups"`
+	Derived  map[string]*Formula           `json:"derived"`
+	// Source is the file the data was read from, or "embedded"
+	Source string `json:"-"`
+}
+
+var current atomic.Pointer[Data]
+
+func init() {
+	data, err := Parse(defaultData, "embedded")
+	if err != nil {
+		panic(err)
+	}
+	current.Store(data)
+}
+
+// Current returns the game data in use
+func Current() *Data {
+	return current.Load()
+}
+
+// Set makes data the game data in use
+func Set(data *Data) {
+	current.Store(data)
+}
+
+// Load reads the game data file at path, or the embedded defaults when path is empty
+func Load(path string) (*Data, error) {
+	if path == "" {
+		return Parse(defaultData, "embedded")
+	}
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read game data: %w", err)
+	}
+	return Parse(raw, path)
+}
+
+// Parse decodes and validates game data read from source
+func Parse(raw []byte, source string) (*Data, error) {
+	var data Data
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, fmt.Errorf("failed to parse game data %s: %w", source, err)
+	}
+	data.Source = source
+	if err := data.validate(); err != nil {
+		return nil, fmt.Errorf("invalid game data %s: %w", source, err)
+	}
+	return &data, nil
+}
+
+// validate checks that the data is complete and consistent
+func (d *Data) validate() error {
+	if d.Version == "" {
+		return fmt.Errorf("version is required")
+	}
+	if len(d.Elements) == 0 {
+		return fmt.Errorf("at least one element is required")
+	}
+	for attacker, defenders := range d.Matchups {
+		if _, ok := d.Elements[attacker]; !ok {
+			return fmt.Errorf("matchups reference unknown element %q", attacker)
+		}
+		for defender, multiplier := range defenders {
+			if _, ok := d.Elements[defender]; !ok {
+				return fmt.Errorf("matchups of %s reference unknown element %q", attacker, defender)
+			}
+			if multiplier < 0 {
+				return fmt.Errorf("matchup %s against %s has a negative multiplier", attacker, defender)
+			}
+		}
+	}
+	for _, stat := range RequiredStats {
+		if d.Derived[stat] == nil {
+			return fmt.Errorf("derived stat %q is required", stat)
+		}
+	}
+	return nil
+}
+
+// ElementColor returns the display color of element, or the default color
+func (d *Data) ElementColor(element string) string {
+	if e, ok := d.Elements[element]; ok && e.Color != "" {
+		return e.Color
+	}
+	return d.DefaultColor
+}
+
+// Multiplier returns the damage multiplier of attacker against defender
+func (d *Data) Multiplier(attacker, defender string) float64 {
+	if multiplier, ok := d.Matchups[attacker][defender]; ok {
+		return multiplier
+	}
+	return 1
+}
+
+// Derive computes the derived stat from a Nadmon's stats, or 0 for an unknown stat
+func (d *Data) Derive(stat string, vars map[string]int64) int64 {
+	formula := d.Derived[stat]
+	if formula == nil {
+		return 0
+	}
+	return formula.Eval(vars)
+}
@@ -0,0 +1,510 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"nadmon-backend/internal/ethaddr"
+	"nadmon-backend/internal/models"
+)
+
+// rarityWeights are the rarity-score points of each held NFT; other rarities are worth 1
+var rarityWeights = map[string]int64{"Legendary": 25, "Epic": 10, "Rare": 5, "Uncommon": 2}
+
+// GetTopCollectors retrieves players with the most NFTs
+func (s *Store) GetTopCollectors(ctx context.Context, limit int) ([]models.PlayerProfile, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+
+	var profiles []models.PlayerProfile
+	for address, count := range s.holdings() {
+		profiles = append(profiles, models.PlayerProfile{Address: address, TotalNFTs: count})
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		if profiles[i].TotalNFTs != profiles[j].TotalNFTs {
+			return profiles[i].TotalNFTs > profiles[j].TotalNFTs
+		}
+		return profiles[i].Address < profiles[j].Address
+	})
+	if len(profiles) > limit {
+		profiles = profiles[:limit]
+	}
+	return profiles, nil
+}
+
+// GetLeaderboard returns one page of a leaderboard (see models.LeaderboardTypes). When player
+// is set and ranked, their own entry is included.
+func (s *Store) GetLeaderboard(ctx context.Context, kind, player string, limit, offset int) (*models.Leaderboard, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+	player = ethaddr.Normalize(player)
+
+	scores := map[string]int64{}
+	switch kind {
+	case models.LeaderboardEvolutions, models.LeaderboardFusion:
+		// Stat changes are credited to the token's owner at the time of the change
+		changeType := map[string]string{models.LeaderboardEvolutions: "evolution", models.LeaderboardFusion: "fusion"}[kind]
+		for _, change := range s.changes {
+			if change.ChangeType != changeType {
+				continue
+			}
+			if owner := s.holderAt(int64(change.TokenID), change.WrittenAt.Time); owner != "" && !s.excluded[owner] {
+				scores[owner]++
+			}
+		}
+	case models.LeaderboardPacks:
+		for _, pack := range s.packs {
+			if !s.excluded[pack.Player] {
+				scores[pack.Player]++
+			}
+		}
+	case models.LeaderboardRarityScore:
+		for _, nadmon := range s.nadmons {
+			if !circulating(nadmon) || s.excluded[nadmon.Owner] {
+				continue
+			}
+			weight, ok := rarityWeights[nadmon.Rarity]
+			if !ok {
+				weight = 1
+			}
+			scores[nadmon.Owner] += weight
+		}
+	default:
+		return nil, fmt.Errorf("unknown leaderboard type %q", kind)
+	}
+
+	ranked := rank(scores)
+	start, end := page(len(ranked), limit, offset)
+	leaderboard := &models.Leaderboard{Type: kind, Entries: append(make([]models.LeaderboardEntry, 0, end-start), ranked[start:end]...)}
+	if len(leaderboard.Entries) > 0 {
+		leaderboard.TotalPlayers = len(ranked)
+	}
+	if player != "" {
+		for _, entry := range ranked {
+			if entry.Address == player {
+				entry := entry
+				leaderboard.Player = &entry
+				break
+			}
+		}
+	}
+	return leaderboard, nil
+}
+
+// rank orders players by score like SQL RANK(): ties share a rank and are listed by address
+func rank(scores map[string]int64) []models.LeaderboardEntry {
+	entries := make([]models.LeaderboardEntry, 0, len(scores))
+	for address, score := range scores {
+		entries = append(entries, models.LeaderboardEntry{Address: address, Score: score})
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Score != entries[j].Score {
+			return entries[i].Score > entries[j].Score
+		}
+		return entries[i].Address < entries[j].Address
+	})
+	for i := range entries {
+		entries[i].Rank = i + 1
+		if i > 0 && entries[i].Score == entries[i-1].Score {
+			entries[i].Rank = entries[i-1].Rank
+		}
+	}
+	return entries
+}
+
+// GetGameStats retrieves overall game statistics
+func (s *Store) GetGameStats(ctx context.Context) (*models.GameStats, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+
+	stats := &models.GameStats{TotalPacks: len(s.packs), UniqueCollectors: len(s.holdings())}
+	for _, nadmon := range s.nadmons {
+		if circulating(nadmon) {
+			stats.TotalNFTs++
+		}
+	}
+	for _, change := range s.changes {
+		if change.ChangeType == "evolution" {
+			stats.TotalEvolutions++
+		}
+	}
+	stats.TotalPlayers = len(s.packsPerPlayer())
+	return stats, nil
+}
+
+// packsPerPlayer counts the packs every player has bought
+func (s *Store) packsPerPlayer() map[string]int {
+	packs := map[string]int{}
+	for _, pack := range s.packs {
+		packs[pack.Player]++
+	}
+	return packs
+}
+
+// GetPackDistribution retrieves a histogram of how many packs each player has bought
+func (s *Store) GetPackDistribution(ctx context.Context) (*models.PackDistribution, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+
+	var buckets [4]int
+	counts := make([]int, 0, len(s.packs))
+	for _, packs := range s.packsPerPlayer() {
+		switch {
+		case packs == 1:
+			buckets[0]++
+		case packs <= 5:
+			buckets[1]++
+		case packs <= 20:
+			buckets[2]++
+		default:
+			buckets[3]++
+		}
+		counts = append(counts, packs)
+	}
+	sort.Ints(counts)
+
+	// Mean and interpolated median, like AVG and PERCENTILE_CONT(0.5)
+	var mean, median float64
+	if n := len(counts); n > 0 {
+		mean = float64(len(s.packs)) / float64(n)
+		middle := float64(n-1) / 2
+		lower, upper := counts[(n-1)/2], counts[n/2]
+		median = float64(lower) + float64(upper-lower)*(middle-float64((n-1)/2))
+	}
+
+	return models.NewPackDistribution(buckets, len(counts), len(s.packs), mean, median), nil
+}
+
+// GetOwnershipConcentration computes the supply share of the top holders and the Gini coefficient
+func (s *Store) GetOwnershipConcentration(ctx context.Context) (*models.OwnershipConcentration, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+
+	holdings := s.holdings()
+	balances := make([]int, 0, len(holdings))
+	for _, count := range holdings {
+		balances = append(balances, count)
+	}
+	// Balances are sorted descending
+	sort.Sort(sort.Reverse(sort.IntSlice(balances)))
+	return models.NewOwnershipConcentration(balances), nil
+}
+
+// GetSupplyStats counts minted, burned and circulating tokens overall and per rarity and element
+func (s *Store) GetSupplyStats(ctx context.Context) (*models.SupplyStats, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+
+	stats := &models.SupplyStats{
+		ByRarity:  map[string]models.SupplyCount{},
+		ByElement: map[string]models.SupplyCount{},
+	}
+	for _, nadmon := range s.nadmons {
+		burned := 0
+		if !circulating(nadmon) {
+			burned = 1
+		}
+		stats.Add(1, burned)
+
+		rarity := stats.ByRarity[nadmon.Rarity]
+		rarity.Add(1, burned)
+		stats.ByRarity[nadmon.Rarity] = rarity
+
+		element := stats.ByElement[nadmon.Element]
+		element.Add(1, burned)
+		stats.ByElement[nadmon.Element] = element
+	}
+	return stats, nil
+}
+
+// GetTraitStats breaks the circulating supply down by element or rarity with the average
+// current stats and evolution rate of each
+func (s *Store) GetTraitStats(ctx context.Context, dimension string) (*models.TraitBreakdown, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+
+	byValue := map[string]*models.TraitTotals{}
+	for _, nadmon := range s.nadmons {
+		if !circulating(nadmon) {
+			continue
+		}
+		value := nadmon.Element
+		if dimension == models.DimensionRarity {
+			value = nadmon.Rarity
+		}
+		total, ok := byValue[value]
+		if !ok {
+			total = &models.TraitTotals{Value: value}
+			byValue[value] = total
+		}
+		total.Count++
+		total.HP += nadmon.HP
+		total.Attack += nadmon.Attack
+		total.Defense += nadmon.Defense
+		total.Crit += nadmon.Crit
+		if nadmon.Evo > 1 {
+			total.Evolved++
+		}
+	}
+
+	totals := make([]models.TraitTotals, 0, len(byValue))
+	for _, total := range byValue {
+		totals = append(totals, *total)
+	}
+	return models.NewTraitBreakdown(dimension, totals), nil
+}
+
+// GetPackOdds computes empirical rarity and element drop rates of the packs bought in [from, to),
+// overall and per payment type. A zero from or to leaves that side of the window open.
+func (s *Store) GetPackOdds(ctx context.Context, from, to time.Time) (*models.PackOddsReport, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+
+	// Nadmons dropped by each pack
+	drops := map[int64][]mintRow{}
+	for _, mint := range s.mintsByToken {
+		drops[int64(mint.PackID)] = append(drops[int64(mint.PackID)], mint)
+	}
+
+	all := newPackDrops()
+	byPayment := map[string]*packDrops{}
+	for _, pack := range s.packs {
+		if !from.IsZero() && pack.WrittenAt.Before(from) || !to.IsZero() && !pack.WrittenAt.Before(to) {
+			continue
+		}
+		if byPayment[pack.PaymentType] == nil {
+			byPayment[pack.PaymentType] = newPackDrops()
+		}
+		all.add(drops[int64(pack.PackID)])
+		byPayment[pack.PaymentType].add(drops[int64(pack.PackID)])
+	}
+
+	report := &models.PackOddsReport{
+		PackOdds:      all.odds(),
+		ByPaymentType: make(map[string]models.PackOdds, len(byPayment)),
+	}
+	for paymentType, drops := range byPayment {
+		report.ByPaymentType[paymentType] = drops.odds()
+	}
+	return report, nil
+}
+
+// packDrops accumulates Nadmon drop counts of a set of packs
+type packDrops struct {
+	packs     int
+	nadmons   int
+	byRarity  map[string]int
+	byElement map[string]int
+}
+
+func newPackDrops() *packDrops {
+	return &packDrops{byRarity: map[string]int{}, byElement: map[string]int{}}
+}
+
+// add records a pack that dropped mints
+func (d *packDrops) add(mints []mintRow) {
+	d.packs++
+	for _, mint := range mints {
+		d.nadmons++
+		d.byRarity[mint.Rarity]++
+		d.byElement[mint.Element]++
+	}
+}
+
+func (d *packDrops) odds() models.PackOdds {
+	odds := models.PackOdds{
+		Packs:     d.packs,
+		Nadmons:   d.nadmons,
+		ByRarity:  make(map[string]models.DropRate, len(d.byRarity)),
+		ByElement: make(map[string]models.DropRate, len(d.byElement)),
+	}
+	for rarity, drops := range d.byRarity {
+		odds.ByRarity[rarity] = models.NewDropRate(drops, d.nadmons)
+	}
+	for element, drops := range d.byElement {
+		odds.ByElement[element] = models.NewDropRate(drops, d.nadmons)
+	}
+	return odds
+}
+
+// GetSearchSuggestions returns nadmon types, elements and rarities matching query, with circulating counts
+func (s *Store) GetSearchSuggestions(ctx context.Context, query string, limit int) ([]models.SearchSuggestion, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+
+	counts := map[models.SearchSuggestion]int{}
+	for _, nadmon := range s.nadmons {
+		if !circulating(nadmon) {
+			continue
+		}
+		counts[models.SearchSuggestion{Type: models.SuggestionTypeNadmonType, Value: nadmon.NadmonType}]++
+		counts[models.SearchSuggestion{Type: models.SuggestionTypeElement, Value: nadmon.Element}]++
+		counts[models.SearchSuggestion{Type: models.SuggestionTypeRarity, Value: nadmon.Rarity}]++
+	}
+
+	query = strings.ToLower(strings.TrimSpace(query))
+	suggestions := []models.SearchSuggestion{}
+	for suggestion, count := range counts {
+		if strings.Contains(strings.ToLower(suggestion.Value), query) {
+			suggestion.Circulating = count
+			suggestions = append(suggestions, suggestion)
+		}
+	}
+
+	// Prefix matches first, then the most common values
+	sort.Slice(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		aPrefix, bPrefix := strings.HasPrefix(strings.ToLower(a.Value), query), strings.HasPrefix(strings.ToLower(b.Value), query)
+		if aPrefix != bPrefix {
+			return aPrefix
+		}
+		if a.Circulating != b.Circulating {
+			return a.Circulating > b.Circulating
+		}
+		if a.Value != b.Value {
+			return a.Value < b.Value
+		}
+		return a.Type < b.Type
+	})
+	if len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions, nil
+}
+
+// GetEventTimeSeries counts events of a metric (mints, packs, transfers) or active players per
+// hour or day
+func (s *Store) GetEventTimeSeries(ctx context.Context, metric, interval string, from, to time.Time) ([]models.TimeSeriesPoint, error) {
+	var truncate func(time.Time) time.Time
+	switch interval {
+	case "hour":
+		truncate = func(t time.Time) time.Time { return t.Truncate(time.Hour) }
+	case "day":
+		truncate = func(t time.Time) time.Time { return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC) }
+	}
+
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+
+	var events []time.Time
+	players := map[time.Time]map[string]bool{}
+	switch metric {
+	case "mints":
+		for _, mint := range s.mints {
+			events = append(events, mint.WrittenAt.Time)
+		}
+	case "packs":
+		for _, pack := range s.packs {
+			events = append(events, pack.WrittenAt.Time)
+		}
+	case "transfers":
+		for _, transfer := range s.transfers {
+			events = append(events, transfer.WrittenAt.Time)
+		}
+	case models.MetricPlayers:
+		for _, pack := range s.packs {
+			events = append(events, pack.WrittenAt.Time)
+		}
+	default:
+		return nil, fmt.Errorf("unknown metric %q", metric)
+	}
+	if truncate == nil {
+		return nil, fmt.Errorf("unknown interval %q", interval)
+	}
+
+	counts := map[time.Time]int64{}
+	for i, at := range events {
+		if at.Before(from) || !at.Before(to) {
+			continue
+		}
+		bucket := truncate(at)
+		if metric != models.MetricPlayers {
+			counts[bucket]++
+			continue
+		}
+		// Distinct buyers per bucket; the events of this metric are the packs, in order
+		player := s.packs[i].Player
+		if players[bucket] == nil {
+			players[bucket] = map[string]bool{}
+		}
+		if !players[bucket][player] {
+			players[bucket][player] = true
+			counts[bucket]++
+		}
+	}
+
+	points := make([]models.TimeSeriesPoint, 0, len(counts))
+	for bucket, count := range counts {
+		points = append(points, models.TimeSeriesPoint{Bucket: bucket, Count: count})
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Bucket.Before(points[j].Bucket) })
+	return points, nil
+}
+
+// marketTally accumulates the market stats of a set of sales
+type marketTally struct {
+	sales      int
+	volume     *big.Int
+	floor      *big.Int
+	lastPrice  string
+	lastSoldAt time.Time
+}
+
+func newMarketTally() *marketTally {
+	return &marketTally{volume: new(big.Int)}
+}
+
+// add records a sale; sales sold since floorSince count towards the floor
+func (t *marketTally) add(sale saleRow, floorSince time.Time) error {
+	price, ok := new(big.Int).SetString(string(sale.Price), 10)
+	if !ok {
+		return fmt.Errorf("invalid sale price %q", sale.Price)
+	}
+
+	t.sales++
+	t.volume.Add(t.volume, price)
+	if !sale.WrittenAt.Before(floorSince) && (t.floor == nil || price.Cmp(t.floor) < 0) {
+		t.floor = price
+	}
+	if t.lastPrice == "" || !sale.WrittenAt.Before(t.lastSoldAt) {
+		t.lastPrice, t.lastSoldAt = price.String(), sale.WrittenAt.Time
+	}
+	return nil
+}
+
+func (t *marketTally) stats() models.MarketStats {
+	stats := models.MarketStats{Sales: t.sales, Volume: t.volume.String()}
+	if t.floor != nil {
+		floor := t.floor.String()
+		stats.FloorPrice = &floor
+	}
+	if t.sales > 0 {
+		lastPrice, lastSoldAt := t.lastPrice, t.lastSoldAt
+		stats.LastPrice, stats.LastSoldAt = &lastPrice, &lastSoldAt
+	}
+	return stats
+}
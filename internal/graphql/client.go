@@ -0,0 +1,106 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Client talks to the GraphQL (Hasura) endpoint of a hosted Envio indexer
+type Client struct {
+	url         string
+	adminSecret string
+	http        *http.Client
+}
+
+// NewClient creates a GraphQL client. adminSecret is sent as x-hasura-admin-secret when set;
+// hosted Envio endpoints are public and need none.
+func NewClient(url, adminSecret string) *Client {
+	return &Client{
+		url:         url,
+		adminSecret: adminSecret,
+		http:        &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// Error is an error reported in the errors array of a GraphQL response
+type Error struct {
+	Message    string `json:"message"`
+	Extensions struct {
+		Code string `json:"code"`
+	} `json:"extensions"`
+}
+
+// Errors are the errors of a GraphQL response
+type Errors []Error
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Message
+	}
+	return "graphql: " + strings.Join(messages, "; ")
+}
+
+// HasCode reports whether any of the errors has the given extensions code
+func (e Errors) HasCode(code string) bool {
+	for _, err := range e {
+		if err.Extensions.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// Query runs query with variables and decodes its data into v. Errors reported by the
+// server are returned as Errors.
+func (c *Client) Query(ctx context.Context, query string, variables map[string]interface{}, v interface{}) error {
+	body, err := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.adminSecret != "" {
+		req.Header.Set("x-hasura-admin-secret", c.adminSecret)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("graphql: %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+
+	var result struct {
+		Data   json.RawMessage `json:"data"`
+		Errors Errors          `json:"errors"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("graphql: failed to decode response: %w", err)
+	}
+	if len(result.Errors) > 0 {
+		return result.Errors
+	}
+	return json.Unmarshal(result.Data, v)
+}
+
+// Ping checks that the endpoint answers queries
+func (c *Client) Ping(ctx context.Context) error {
+	var data struct {
+		Typename string `json:"__typename"`
+	}
+	return c.Query(ctx, `{ __typename }`, nil, &data)
+}
@@ -0,0 +1,208 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// pageSize is how many rows are fetched per query; hosted Envio endpoints return at most 1000
+const pageSize = 1000
+
+// timestampLayout formats cursors the way Hasura compares timestamp columns
+const timestampLayout = "2006-01-02T15:04:05.999999"
+
+// numeric decodes a numeric column, which Hasura sends as a JSON number or, with stringified
+// numerics, as a string
+type numeric int64
+
+func (n *numeric) UnmarshalJSON(data []byte) error {
+	value, err := strconv.ParseInt(strings.Trim(string(data), `"`), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid numeric %s: %w", data, err)
+	}
+	*n = numeric(value)
+	return nil
+}
+
+// bigNumeric decodes a numeric column that may not fit 64 bits (wei prices) as its decimal text
+type bigNumeric string
+
+func (n *bigNumeric) UnmarshalJSON(data []byte) error {
+	*n = bigNumeric(strings.Trim(string(data), `"`))
+	return nil
+}
+
+// numericArray decodes a numeric[] column, sent as a JSON array or as a Postgres array literal
+type numericArray []int64
+
+func (a *numericArray) UnmarshalJSON(data []byte) error {
+	var values []numeric
+	if err := json.Unmarshal(data, &values); err == nil {
+		*a = make(numericArray, len(values))
+		for i, value := range values {
+			(*a)[i] = int64(value)
+		}
+		return nil
+	}
+
+	var literal string
+	if err := json.Unmarshal(data, &literal); err != nil {
+		return fmt.Errorf("invalid numeric array %s", data)
+	}
+	*a = numericArray{}
+	for _, element := range strings.Split(strings.Trim(literal, "{}"), ",") {
+		if element = strings.TrimSpace(element); element == "" {
+			continue
+		}
+		value, err := strconv.ParseInt(element, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid numeric array %s: %w", data, err)
+		}
+		*a = append(*a, value)
+	}
+	return nil
+}
+
+// timestamp decodes db_write_timestamp, a timestamp without time zone holding UTC
+type timestamp struct{ time.Time }
+
+func (t *timestamp) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.999999999"} {
+		if parsed, err := time.Parse(layout, value); err == nil {
+			t.Time = parsed.UTC()
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid timestamp %q", value)
+}
+
+// Rows of the mirrored Envio tables. Addresses are lowercased once they are mirrored.
+
+type mintRow struct {
+	ID         string    `json:"id"`
+	Owner      string    `json:"owner"`
+	TokenID    numeric   `json:"tokenId"`
+	PackID     numeric   `json:"packId"`
+	NadmonType string    `json:"nadmonType"`
+	Element    string    `json:"element"`
+	Rarity     string    `json:"rarity"`
+	HP         numeric   `json:"hp"`
+	Attack     numeric   `json:"attack"`
+	Defense    numeric   `json:"defense"`
+	Crit       numeric   `json:"crit"`
+	Fusion     numeric   `json:"fusion"`
+	Evo        numeric   `json:"evo"`
+	WrittenAt  timestamp `json:"db_write_timestamp"`
+}
+
+type packRow struct {
+	ID          string       `json:"id"`
+	Player      string       `json:"player"`
+	PackID      numeric      `json:"packId"`
+	Sequence    numeric      `json:"sequence"`
+	TokenIDs    numericArray `json:"tokenIds"`
+	PaymentType string       `json:"paymentType"`
+	WrittenAt   timestamp    `json:"db_write_timestamp"`
+}
+
+type transferRow struct {
+	ID        string    `json:"id"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	TokenID   numeric   `json:"tokenId"`
+	WrittenAt timestamp `json:"db_write_timestamp"`
+}
+
+type statsChangeRow struct {
+	ID         string    `json:"id"`
+	TokenID    numeric   `json:"tokenId"`
+	Sequence   numeric   `json:"sequence"`
+	ChangeType string    `json:"changeType"`
+	NewHP      numeric   `json:"newHp"`
+	NewAttack  numeric   `json:"newAttack"`
+	NewDefense numeric   `json:"newDefense"`
+	NewCrit    numeric   `json:"newCrit"`
+	NewFusion  numeric   `json:"newFusion"`
+	NewEvo     numeric   `json:"newEvo"`
+	OldHP      numeric   `json:"oldHp"`
+	OldAttack  numeric   `json:"oldAttack"`
+	OldDefense numeric   `json:"oldDefense"`
+	OldCrit    numeric   `json:"oldCrit"`
+	OldFusion  numeric   `json:"oldFusion"`
+	OldEvo     numeric   `json:"oldEvo"`
+	WrittenAt  timestamp `json:"db_write_timestamp"`
+}
+
+type saleRow struct {
+	ID        string     `json:"id"`
+	TokenID   numeric    `json:"tokenId"`
+	Seller    string     `json:"seller"`
+	Buyer     string     `json:"buyer"`
+	Price     bigNumeric `json:"price"`
+	WrittenAt timestamp  `json:"db_write_timestamp"`
+}
+
+// Selection sets of the mirrored tables
+const (
+	mintFields        = `id owner tokenId packId nadmonType element rarity hp attack defense crit fusion evo db_write_timestamp`
+	packFields        = `id player packId sequence tokenIds paymentType db_write_timestamp`
+	transferFields    = `id from to tokenId db_write_timestamp`
+	statsChangeFields = `id tokenId sequence changeType newHp newAttack newDefense newCrit newFusion newEvo oldHp oldAttack oldDefense oldCrit oldFusion oldEvo db_write_timestamp`
+	saleFields        = `id tokenId seller buyer price db_write_timestamp`
+)
+
+// cursor tracks how far a table has been mirrored: up to since, of which atSince rows were
+// written at exactly since. Envio writes each batch in one transaction with one timestamp,
+// so no row can appear at or before since after it was read.
+type cursor struct {
+	since   time.Time
+	atSince int
+}
+
+// advance moves the cursor past a row written at writtenAt; rows arrive in write order
+func (c *cursor) advance(writtenAt time.Time) {
+	if writtenAt.Equal(c.since) {
+		c.atSince++
+		return
+	}
+	c.since, c.atSince = writtenAt, 1
+}
+
+// fetch reads the rows of table written since the cursor, oldest first. The cursor only
+// advances when every page was read.
+func fetch[T any](ctx context.Context, client *Client, table, fields string, c *cursor, writtenAt func(T) time.Time) ([]T, error) {
+	next := *c
+	var rows []T
+	for {
+		query := fmt.Sprintf(`{
+			rows: %s(
+				where: {db_write_timestamp: {_gte: %q}},
+				order_by: [{db_write_timestamp: asc}, {id: asc}],
+				offset: %d, limit: %d
+			) { %s }
+		}`, table, next.since.Format(timestampLayout), next.atSince, pageSize, fields)
+
+		var page struct {
+			Rows []T `json:"rows"`
+		}
+		if err := client.Query(ctx, query, nil, &page); err != nil {
+			return nil, err
+		}
+		for _, row := range page.Rows {
+			next.advance(writtenAt(row))
+		}
+		rows = append(rows, page.Rows...)
+		if len(page.Rows) < pageSize {
+			*c = next
+			return rows, nil
+		}
+	}
+}
@@ -0,0 +1,1230 @@
+// Package graphql serves Nadmon data from the GraphQL (Hasura) endpoint of a hosted Envio
+// indexer, for deployments without credentials for the indexer's database.
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"nadmon-backend/internal/ethaddr"
+	"nadmon-backend/internal/models"
+	"nadmon-backend/internal/repository"
+)
+
+// Envio tables mirrored from the GraphQL endpoint
+const (
+	mintTable        = "NadmonNFT_NadmonMinted"
+	packTable        = "NadmonNFT_PackMinted"
+	transferTable    = "NadmonNFT_Transfer"
+	statsChangeTable = "NadmonNFT_StatsChanged"
+	saleTable        = "NadmonMarketplace_Sale"
+)
+
+// ErrNotSynced is returned by reads before the first sync completed
+var ErrNotSynced = errors.New("graphql store has not synced yet")
+
+// Store mirrors the Envio event tables from the GraphQL endpoint into memory and answers
+// every query from the mirror, with the same semantics as the Postgres repository. Hosted
+// endpoints don't offer the grouping and window functions the aggregates need, and events
+// are append-only, so only new rows are fetched on each sync.
+type Store struct {
+	client *Client
+
+	// excluded holds lowercased addresses (treasury, deployer, escrow) left out of public aggregates
+	excluded map[string]bool
+
+	// syncMu serializes syncs, which own the cursors
+	syncMu  sync.Mutex
+	cursors map[string]*cursor
+
+	mu           sync.RWMutex
+	synced       bool
+	salesIndexed bool
+	mints        []mintRow // event rows in write order
+	packs        []packRow
+	transfers    []transferRow
+	changes      []statsChangeRow
+	sales        []saleRow
+
+	// Indexes rebuilt after every sync that brought new rows
+	nadmons          map[int64]models.Nadmon // every minted token; burned ones are owned by the zero address
+	tokenIDs         []int64                 // minted token IDs, ascending
+	mintsByToken     map[int64]mintRow
+	transfersByToken map[int64][]transferRow    // oldest first
+	changesByToken   map[int64][]statsChangeRow // by sequence
+	burnedAt         map[int64]time.Time
+}
+
+// Ensure Store implements repository.Store
+var _ repository.Store = (*Store)(nil)
+
+// NewStore creates a store reading through client; call Sync before serving reads
+func NewStore(client *Client) *Store {
+	return &Store{
+		client:   client,
+		excluded: map[string]bool{},
+		cursors: map[string]*cursor{
+			mintTable: {}, packTable: {}, transferTable: {}, statsChangeTable: {}, saleTable: {},
+		},
+	}
+}
+
+// SetExcludedAddresses leaves the given addresses out of leaderboards, collector counts and
+// concentration metrics
+func (s *Store) SetExcludedAddresses(addresses []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.excluded = make(map[string]bool, len(addresses))
+	for _, address := range addresses {
+		s.excluded[ethaddr.Normalize(address)] = true
+	}
+}
+
+// Sync fetches the rows written since the last sync. It returns the number of new rows.
+func (s *Store) Sync(ctx context.Context) (int, error) {
+	s.syncMu.Lock()
+	defer s.syncMu.Unlock()
+
+	mints, err := fetch(ctx, s.client, mintTable, mintFields, s.cursors[mintTable], func(r mintRow) time.Time { return r.WrittenAt.Time })
+	if err != nil {
+		return 0, fmt.Errorf("failed to sync mints: %w", err)
+	}
+	packs, err := fetch(ctx, s.client, packTable, packFields, s.cursors[packTable], func(r packRow) time.Time { return r.WrittenAt.Time })
+	if err != nil {
+		return 0, fmt.Errorf("failed to sync packs: %w", err)
+	}
+	transfers, err := fetch(ctx, s.client, transferTable, transferFields, s.cursors[transferTable], func(r transferRow) time.Time { return r.WrittenAt.Time })
+	if err != nil {
+		return 0, fmt.Errorf("failed to sync transfers: %w", err)
+	}
+	changes, err := fetch(ctx, s.client, statsChangeTable, statsChangeFields, s.cursors[statsChangeTable], func(r statsChangeRow) time.Time { return r.WrittenAt.Time })
+	if err != nil {
+		return 0, fmt.Errorf("failed to sync stat changes: %w", err)
+	}
+
+	// The marketplace table only exists once the marketplace contract is indexed
+	salesIndexed := true
+	sales, err := fetch(ctx, s.client, saleTable, saleFields, s.cursors[saleTable], func(r saleRow) time.Time { return r.WrittenAt.Time })
+	var gqlErrors Errors
+	if errors.As(err, &gqlErrors) && gqlErrors.HasCode("validation-failed") {
+		salesIndexed, err = false, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to sync sales: %w", err)
+	}
+
+	for i := range mints {
+		mints[i].Owner = ethaddr.Normalize(mints[i].Owner)
+	}
+	for i := range packs {
+		packs[i].Player = ethaddr.Normalize(packs[i].Player)
+	}
+	for i := range transfers {
+		transfers[i].From, transfers[i].To = ethaddr.Normalize(transfers[i].From), ethaddr.Normalize(transfers[i].To)
+	}
+	for i := range sales {
+		sales[i].Seller, sales[i].Buyer = ethaddr.Normalize(sales[i].Seller), ethaddr.Normalize(sales[i].Buyer)
+	}
+
+	added := len(mints) + len(packs) + len(transfers) + len(changes) + len(sales)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mints = append(s.mints, mints...)
+	s.packs = append(s.packs, packs...)
+	s.transfers = append(s.transfers, transfers...)
+	s.changes = append(s.changes, changes...)
+	s.sales = append(s.sales, sales...)
+	s.salesIndexed = salesIndexed
+	if added > 0 || !s.synced {
+		s.reindex()
+	}
+	s.synced = true
+	return added, nil
+}
+
+// Run syncs every interval until stop is closed
+func (s *Store) Run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), interval+30*time.Second)
+			if _, err := s.Sync(ctx); err != nil {
+				log.Printf("Warning: %v", err)
+			}
+			cancel()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// reindex rebuilds the current state of every token; s.mu must be held for writing
+func (s *Store) reindex() {
+	s.mintsByToken = make(map[int64]mintRow, len(s.mints))
+	s.tokenIDs = s.tokenIDs[:0]
+	for _, mint := range s.mints {
+		id := int64(mint.TokenID)
+		if _, ok := s.mintsByToken[id]; !ok {
+			s.tokenIDs = append(s.tokenIDs, id)
+		}
+		s.mintsByToken[id] = mint
+	}
+	sort.Slice(s.tokenIDs, func(i, j int) bool { return s.tokenIDs[i] < s.tokenIDs[j] })
+
+	// Transfers are mirrored in write order, so the last one of a token is its current owner
+	s.transfersByToken = make(map[int64][]transferRow)
+	for _, transfer := range s.transfers {
+		s.transfersByToken[int64(transfer.TokenID)] = append(s.transfersByToken[int64(transfer.TokenID)], transfer)
+	}
+
+	s.changesByToken = make(map[int64][]statsChangeRow)
+	for _, change := range s.changes {
+		s.changesByToken[int64(change.TokenID)] = append(s.changesByToken[int64(change.TokenID)], change)
+	}
+	for _, changes := range s.changesByToken {
+		sort.SliceStable(changes, func(i, j int) bool { return changes[i].Sequence < changes[j].Sequence })
+	}
+
+	s.nadmons = make(map[int64]models.Nadmon, len(s.mintsByToken))
+	s.burnedAt = make(map[int64]time.Time)
+	for id, mint := range s.mintsByToken {
+		nadmon := models.Nadmon{
+			TokenID:     id,
+			Owner:       mint.Owner,
+			PackID:      int64(mint.PackID),
+			NadmonType:  mint.NadmonType,
+			Element:     mint.Element,
+			Rarity:      mint.Rarity,
+			HP:          int64(mint.HP),
+			Attack:      int64(mint.Attack),
+			Defense:     int64(mint.Defense),
+			Crit:        int64(mint.Crit),
+			Fusion:      int64(mint.Fusion),
+			Evo:         int64(mint.Evo),
+			CreatedAt:   mint.WrittenAt.Time,
+			LastUpdated: mint.WrittenAt.Time,
+		}
+		if transfers := s.transfersByToken[id]; len(transfers) > 0 {
+			last := transfers[len(transfers)-1]
+			nadmon.Owner = last.To
+			if last.To == models.ZeroAddress {
+				s.burnedAt[id] = last.WrittenAt.Time
+			}
+		}
+		if changes := s.changesByToken[id]; len(changes) > 0 {
+			last := changes[len(changes)-1]
+			nadmon.HP, nadmon.Attack, nadmon.Defense = int64(last.NewHP), int64(last.NewAttack), int64(last.NewDefense)
+			nadmon.Crit, nadmon.Fusion, nadmon.Evo = int64(last.NewCrit), int64(last.NewFusion), int64(last.NewEvo)
+			nadmon.LastUpdated = last.WrittenAt.Time
+		}
+		s.nadmons[id] = nadmon
+	}
+}
+
+// read locks the mirror for reading, failing before the first sync
+func (s *Store) read() error {
+	s.mu.RLock()
+	if !s.synced {
+		s.mu.RUnlock()
+		return ErrNotSynced
+	}
+	return nil
+}
+
+// circulating reports whether nadmon has not been burned
+func circulating(nadmon models.Nadmon) bool {
+	return nadmon.Owner != models.ZeroAddress
+}
+
+// holderAt returns who held token right after at, or "" when it had not been transferred yet
+func (s *Store) holderAt(tokenID int64, at time.Time) string {
+	holder := ""
+	for _, transfer := range s.transfersByToken[tokenID] {
+		if transfer.WrittenAt.After(at) {
+			break
+		}
+		holder = transfer.To
+	}
+	return holder
+}
+
+// holdings counts the circulating NFTs of every holder outside the excluded addresses
+func (s *Store) holdings() map[string]int {
+	holdings := map[string]int{}
+	for _, nadmon := range s.nadmons {
+		if circulating(nadmon) && !s.excluded[nadmon.Owner] {
+			holdings[nadmon.Owner]++
+		}
+	}
+	return holdings
+}
+
+// page returns the [offset, offset+limit) window of n items
+func page(n, limit, offset int) (int, int) {
+	if offset > n {
+		offset = n
+	}
+	end := offset + limit
+	if end > n {
+		end = n
+	}
+	return offset, end
+}
+
+// GetPlayerNadmons retrieves all NFTs owned by a player with their current stats
+func (s *Store) GetPlayerNadmons(ctx context.Context, address string) ([]models.Nadmon, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+	return s.playerNadmons(ethaddr.Normalize(address)), nil
+}
+
+func (s *Store) playerNadmons(address string) []models.Nadmon {
+	var nadmons []models.Nadmon
+	for _, id := range s.tokenIDs {
+		if nadmon := s.nadmons[id]; nadmon.Owner == address && circulating(nadmon) {
+			nadmons = append(nadmons, nadmon)
+		}
+	}
+	return nadmons
+}
+
+// GetPlayerProfile retrieves complete player profile with aggregated stats
+func (s *Store) GetPlayerProfile(ctx context.Context, address string) (*models.PlayerProfile, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+	return s.playerProfile(ethaddr.Normalize(address)), nil
+}
+
+// GetPlayerSummary retrieves a player profile with their Nadmons counted by rarity and element
+func (s *Store) GetPlayerSummary(ctx context.Context, address string) (*models.PlayerSummary, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+
+	profile := s.playerProfile(ethaddr.Normalize(address))
+	summary := &models.PlayerSummary{
+		Address:       profile.Address,
+		TotalNFTs:     profile.TotalNFTs,
+		PacksBought:   profile.PacksBought,
+		RarityCounts:  map[string]int{},
+		ElementCounts: map[string]int{},
+		LastActive:    profile.LastActive,
+	}
+	for _, nadmon := range profile.Nadmons {
+		summary.RarityCounts[nadmon.Rarity]++
+		summary.ElementCounts[nadmon.Element]++
+		if nadmon.Evo > 1 {
+			summary.EvolvedNFTs++
+		}
+	}
+	return summary, nil
+}
+
+// playerProfile builds a player's profile; the caller holds the read lock
+func (s *Store) playerProfile(address string) *models.PlayerProfile {
+	nadmons := s.playerNadmons(address)
+	profile := &models.PlayerProfile{Address: address, TotalNFTs: len(nadmons), Nadmons: nadmons}
+
+	// Last active: the latest pack purchase or stat change of a Nadmon the player holds
+	for _, pack := range s.packs {
+		if pack.Player == address {
+			profile.PacksBought++
+			if pack.WrittenAt.After(profile.LastActive) {
+				profile.LastActive = pack.WrittenAt.Time
+			}
+		}
+	}
+	for _, nadmon := range nadmons {
+		for _, change := range s.changesByToken[nadmon.TokenID] {
+			if change.WrittenAt.After(profile.LastActive) {
+				profile.LastActive = change.WrittenAt.Time
+			}
+		}
+	}
+
+	return profile
+}
+
+// GetPlayerPacks retrieves all pack purchases by a player
+func (s *Store) GetPlayerPacks(ctx context.Context, address string) ([]models.Pack, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+	address = ethaddr.Normalize(address)
+
+	var packs []models.Pack
+	for _, pack := range s.packsBySequence() {
+		if pack.Player == address {
+			packs = append(packs, toPack(pack))
+		}
+	}
+	return packs, nil
+}
+
+// GetPlayerPackSummary aggregates a player's pack purchases with their most recent packs
+func (s *Store) GetPlayerPackSummary(ctx context.Context, address string, recent int) (*models.PackSummary, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+	address = ethaddr.Normalize(address)
+
+	summary := models.NewPackSummary(address)
+	for _, pack := range s.packsBySequence() {
+		if pack.Player != address {
+			continue
+		}
+		summary.TotalPacks++
+		summary.Count(pack.PaymentType, 1)
+		if len(summary.RecentPacks) < recent {
+			summary.RecentPacks = append(summary.RecentPacks, toPack(pack))
+		}
+		at := pack.WrittenAt.Time
+		if summary.FirstPurchaseAt == nil || at.Before(*summary.FirstPurchaseAt) {
+			summary.FirstPurchaseAt = &at
+		}
+		if summary.LastPurchaseAt == nil || at.After(*summary.LastPurchaseAt) {
+			summary.LastPurchaseAt = &at
+		}
+	}
+	return summary, nil
+}
+
+// packsBySequence returns the packs newest first
+func (s *Store) packsBySequence() []packRow {
+	packs := append([]packRow(nil), s.packs...)
+	sort.SliceStable(packs, func(i, j int) bool { return packs[i].Sequence > packs[j].Sequence })
+	return packs
+}
+
+func toPack(row packRow) models.Pack {
+	return models.Pack{
+		PackID:      int64(row.PackID),
+		Player:      row.Player,
+		TokenIDs:    []int64(row.TokenIDs),
+		PaymentType: row.PaymentType,
+		PurchasedAt: row.WrittenAt.Time,
+	}
+}
+
+// GetPlayerDex compares the species (type/element/rarity combinations) a player owns with
+// every species minted so far
+func (s *Store) GetPlayerDex(ctx context.Context, address string) (*models.Dex, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+	address = ethaddr.Normalize(address)
+
+	owned := make(map[models.DexSpecies]int)
+	for _, nadmon := range s.playerNadmons(address) {
+		owned[models.DexSpecies{Type: nadmon.NadmonType, Element: nadmon.Element, Rarity: nadmon.Rarity}]++
+	}
+
+	minted := make(map[models.DexSpecies]bool)
+	var species []models.DexSpecies
+	for _, mint := range s.mintsByToken {
+		key := models.DexSpecies{Type: mint.NadmonType, Element: mint.Element, Rarity: mint.Rarity}
+		if !minted[key] {
+			minted[key] = true
+			species = append(species, key)
+		}
+	}
+	sort.Slice(species, func(i, j int) bool {
+		a, b := species[i], species[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Element != b.Element {
+			return a.Element < b.Element
+		}
+		return a.Rarity < b.Rarity
+	})
+
+	dex := &models.Dex{
+		Address:      address,
+		TotalSpecies: len(species),
+		Owned:        []models.DexSpecies{},
+		Missing:      []models.DexSpecies{},
+	}
+	for _, entry := range species {
+		if count := owned[entry]; count > 0 {
+			entry.Owned = count
+			dex.Owned = append(dex.Owned, entry)
+		} else {
+			dex.Missing = append(dex.Missing, entry)
+		}
+	}
+
+	dex.OwnedSpecies = len(dex.Owned)
+	if dex.TotalSpecies > 0 {
+		dex.Completion = math.Round(float64(dex.OwnedSpecies)/float64(dex.TotalSpecies)*10000) / 100
+	}
+	return dex, nil
+}
+
+// SearchNadmons searches a player's NFTs by the filters NadmonRepository.SearchNadmons takes
+func (s *Store) SearchNadmons(ctx context.Context, address string, filters map[string]interface{}) ([]models.Nadmon, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+
+	sortBy, _ := filters["sort_by"].(string)
+	if sortBy == "" {
+		sortBy = repository.SortByTokenID
+	}
+	key, ok := searchSortKeys[sortBy]
+	if !ok {
+		return nil, fmt.Errorf("unsupported sort field %q", sortBy)
+	}
+	descending := false
+	if order, ok := filters["order"].(string); ok && strings.EqualFold(order, "desc") {
+		descending = true
+	}
+
+	var nadmons []models.Nadmon
+	for _, nadmon := range s.playerNadmons(ethaddr.Normalize(address)) {
+		if matchesSearch(nadmon, filters) {
+			nadmons = append(nadmons, nadmon)
+		}
+	}
+
+	// Sort by the field, breaking ties by token ID, both in the requested direction
+	sort.SliceStable(nadmons, func(i, j int) bool {
+		a, b := nadmons[i], nadmons[j]
+		if descending {
+			a, b = b, a
+		}
+		if ka, kb := key(a), key(b); ka != kb {
+			return ka < kb
+		}
+		return a.TokenID < b.TokenID
+	})
+	return nadmons, nil
+}
+
+// SearchAllNadmons searches the circulating NFTs of every player, one keyset page at a time
+func (s *Store) SearchAllNadmons(ctx context.Context, filters map[string]interface{}, after *repository.SearchCursor, limit int) (*models.NadmonSearchPage, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+
+	sortBy, _ := filters["sort_by"].(string)
+	if _, ok := searchSortKeys[sortBy]; !ok && sortBy != "" {
+		return nil, fmt.Errorf("unsupported sort field %q", sortBy)
+	}
+	descending := false
+	if order, ok := filters["order"].(string); ok && strings.EqualFold(order, "desc") {
+		descending = true
+	}
+
+	// before reports whether a sorts before b: by sort key, then token ID, in the direction
+	before := func(a, b repository.SearchCursor) bool {
+		if descending {
+			a, b = b, a
+		}
+		if a.Key != b.Key {
+			return a.Key < b.Key
+		}
+		return a.TokenID < b.TokenID
+	}
+	position := func(n models.Nadmon) repository.SearchCursor {
+		return repository.SearchCursor{Key: repository.SearchSortKey(n, sortBy), TokenID: n.TokenID}
+	}
+
+	var matches []models.Nadmon
+	for _, id := range s.tokenIDs {
+		if nadmon := s.nadmons[id]; circulating(nadmon) && matchesSearch(nadmon, filters) {
+			matches = append(matches, nadmon)
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return before(position(matches[i]), position(matches[j])) })
+
+	page := &models.NadmonSearchPage{Nadmons: []models.Nadmon{}, Total: len(matches)}
+	for _, nadmon := range matches {
+		if after != nil && !before(*after, position(nadmon)) {
+			continue
+		}
+		page.Nadmons = append(page.Nadmons, nadmon)
+		if len(page.Nadmons) > limit {
+			break
+		}
+	}
+	return repository.NewSearchPage(page, sortBy, limit), nil
+}
+
+// searchStatValues reads the stats SearchNadmons filters by range
+var searchStatValues = map[string]func(models.Nadmon) int64{
+	"hp":      func(n models.Nadmon) int64 { return n.HP },
+	"attack":  func(n models.Nadmon) int64 { return n.Attack },
+	"defense": func(n models.Nadmon) int64 { return n.Defense },
+	"crit":    func(n models.Nadmon) int64 { return n.Crit },
+	"fusion":  func(n models.Nadmon) int64 { return n.Fusion },
+	"evo":     func(n models.Nadmon) int64 { return n.Evo },
+}
+
+// searchSortKeys maps repository.SearchSortFields to sort keys
+var searchSortKeys = map[string]func(models.Nadmon) int64{
+	repository.SortByTokenID:   func(n models.Nadmon) int64 { return n.TokenID },
+	repository.SortByRarity:    func(n models.Nadmon) int64 { return int64(rarityRank(n.Rarity)) },
+	repository.SortByCreatedAt: func(n models.Nadmon) int64 { return n.CreatedAt.UnixNano() },
+}
+
+func init() {
+	for stat, value := range searchStatValues {
+		searchSortKeys[stat] = value
+	}
+}
+
+// matchesSearch applies the SearchNadmons filters to nadmon
+func matchesSearch(nadmon models.Nadmon, filters map[string]interface{}) bool {
+	if element, ok := filters["element"].(string); ok && element != "" && nadmon.Element != element {
+		return false
+	}
+	if rarity, ok := filters["rarity"].(string); ok && rarity != "" && nadmon.Rarity != rarity {
+		return false
+	}
+	if nadmonType, ok := filters["type"].(string); ok && nadmonType != "" && nadmon.NadmonType != nadmonType {
+		return false
+	}
+	if evo, ok := filters["evo"].(int); ok && evo > 0 && nadmon.Evo != int64(evo) {
+		return false
+	}
+	if owner, ok := filters["owner"].(string); ok && owner != "" && !strings.Contains(nadmon.Owner, strings.ToLower(owner)) {
+		return false
+	}
+	for stat, value := range searchStatValues {
+		if lower, ok := filters["min_"+stat].(int); ok && value(nadmon) < int64(lower) {
+			return false
+		}
+		if upper, ok := filters["max_"+stat].(int); ok && value(nadmon) > int64(upper) {
+			return false
+		}
+	}
+	return true
+}
+
+// rarityRank orders rarities from Common (1) to Legendary (5)
+func rarityRank(rarity string) int {
+	switch rarity {
+	case "Legendary":
+		return 5
+	case "Epic":
+		return 4
+	case "Rare":
+		return 3
+	case "Uncommon":
+		return 2
+	default:
+		return 1
+	}
+}
+
+// GetSingleNadmon retrieves a single NFT by token ID with current stats
+func (s *Store) GetSingleNadmon(ctx context.Context, tokenID int64) (*models.Nadmon, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+
+	nadmon, ok := s.nadmons[tokenID]
+	if !ok || !circulating(nadmon) {
+		return nil, nil
+	}
+	return &nadmon, nil
+}
+
+// GetNadmonsByIDs retrieves multiple NFTs by their token IDs
+func (s *Store) GetNadmonsByIDs(ctx context.Context, tokenIDs []int64) ([]models.Nadmon, error) {
+	if len(tokenIDs) == 0 {
+		return []models.Nadmon{}, nil
+	}
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+
+	requested := make(map[int64]bool, len(tokenIDs))
+	for _, id := range tokenIDs {
+		requested[id] = true
+	}
+	var nadmons []models.Nadmon
+	for _, id := range s.tokenIDs {
+		if nadmon := s.nadmons[id]; requested[id] && circulating(nadmon) {
+			nadmons = append(nadmons, nadmon)
+		}
+	}
+	return nadmons, nil
+}
+
+// GetInventoryChanges returns what changed in a player's inventory after sinceSequence, with
+// the same semantics as the Postgres repository
+func (s *Store) GetInventoryChanges(ctx context.Context, address string, sinceSequence int64) (*models.InventoryChanges, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+
+	owner := ethaddr.Normalize(address)
+	since := models.SyncSequenceTime(sinceSequence)
+	changes := &models.InventoryChanges{
+		Address:       owner,
+		SinceSequence: sinceSequence,
+		Sequence:      sinceSequence,
+		Changed:       []models.Nadmon{},
+		Removed:       []int64{},
+	}
+
+	// Rows are mirrored in write order, so the last row of each table is its newest
+	var newest time.Time
+	if n := len(s.mints); n > 0 && s.mints[n-1].WrittenAt.After(newest) {
+		newest = s.mints[n-1].WrittenAt.Time
+	}
+	if n := len(s.transfers); n > 0 && s.transfers[n-1].WrittenAt.After(newest) {
+		newest = s.transfers[n-1].WrittenAt.Time
+	}
+	if n := len(s.changes); n > 0 && s.changes[n-1].WrittenAt.After(newest) {
+		newest = s.changes[n-1].WrittenAt.Time
+	}
+	if !newest.IsZero() && models.SyncSequence(newest) > sinceSequence {
+		changes.Sequence = models.SyncSequence(newest)
+	}
+
+	for _, id := range s.tokenIDs {
+		touched, left := false, false
+		for _, transfer := range s.transfersByToken[id] {
+			if transfer.WrittenAt.After(since) {
+				touched = touched || transfer.To == owner
+				left = left || transfer.From == owner
+			}
+		}
+		for _, change := range s.changesByToken[id] {
+			touched = touched || change.WrittenAt.After(since)
+		}
+
+		nadmon := s.nadmons[id]
+		switch {
+		case nadmon.Owner == owner && circulating(nadmon) && (touched || left):
+			changes.Changed = append(changes.Changed, nadmon)
+		case nadmon.Owner != owner && left:
+			changes.Removed = append(changes.Removed, id)
+		}
+	}
+	return changes, nil
+}
+
+// GetNadmonHistory retrieves evolution/fusion history for a specific NFT
+func (s *Store) GetNadmonHistory(ctx context.Context, tokenID int64) ([]models.StatsChange, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+
+	var changes []models.StatsChange
+	for _, row := range s.changesByToken[tokenID] {
+		changes = append(changes, models.StatsChange{
+			TokenID:    tokenID,
+			ChangeType: row.ChangeType,
+			Sequence:   int64(row.Sequence),
+			OldStats: models.StatSet{
+				HP: int64(row.OldHP), Attack: int64(row.OldAttack), Defense: int64(row.OldDefense),
+				Crit: int64(row.OldCrit), Fusion: int64(row.OldFusion), Evo: int64(row.OldEvo),
+			},
+			NewStats: models.StatSet{
+				HP: int64(row.NewHP), Attack: int64(row.NewAttack), Defense: int64(row.NewDefense),
+				Crit: int64(row.NewCrit), Fusion: int64(row.NewFusion), Evo: int64(row.NewEvo),
+			},
+			ChangedAt: row.WrittenAt.Time,
+		})
+	}
+	return changes, nil
+}
+
+// GetFusions retrieves the fusions of the given NFTs with the Nadmons burned to feed them,
+// ordered by fusion time
+func (s *Store) GetFusions(ctx context.Context, tokenIDs []int64) ([]models.Fusion, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+
+	fusions := []models.Fusion{}
+	for _, id := range tokenIDs {
+		fused, ok := s.nadmons[id]
+		if !ok {
+			continue
+		}
+		for _, row := range s.changesByToken[id] {
+			if row.ChangeType != "fusion" {
+				continue
+			}
+			fusion := models.Fusion{
+				TokenID:      id,
+				Sequence:     int64(row.Sequence),
+				FusionBefore: int64(row.OldFusion),
+				FusionAfter:  int64(row.NewFusion),
+				FusedAt:      row.WrittenAt.Time,
+				Consumed:     []models.ConsumedNadmon{},
+			}
+			owner := s.ownerAt(id, fusion.FusedAt)
+			for burnedID, burnedAt := range s.burnedAt {
+				burned := s.nadmons[burnedID]
+				transfers := s.transfersByToken[burnedID]
+				if burnedID == id || burned.NadmonType != fused.NadmonType || burned.Element != fused.Element ||
+					len(transfers) == 0 || transfers[len(transfers)-1].From != owner {
+					continue
+				}
+				if gap := burnedAt.Sub(fusion.FusedAt); gap < -models.FusionBurnWindow || gap > models.FusionBurnWindow {
+					continue
+				}
+				fusion.Consumed = append(fusion.Consumed, models.ConsumedNadmon{
+					TokenID:    burnedID,
+					PackID:     burned.PackID,
+					NadmonType: burned.NadmonType,
+					Element:    burned.Element,
+					Rarity:     burned.Rarity,
+					Stats:      burned.Stats(),
+					BurnedAt:   burnedAt,
+				})
+			}
+			fusions = append(fusions, fusion)
+		}
+	}
+	return models.MatchFusionBurns(fusions), nil
+}
+
+// ownerAt returns the owner of a token once the transfers written up to at are applied
+func (s *Store) ownerAt(tokenID int64, at time.Time) string {
+	owner := s.mintsByToken[tokenID].Owner
+	for _, transfer := range s.transfersByToken[tokenID] {
+		if transfer.WrittenAt.After(at) {
+			break
+		}
+		owner = transfer.To
+	}
+	return owner
+}
+
+// GetNadmonSnapshot retrieves up to limit circulating NFTs with token IDs above afterTokenID,
+// ordered by token ID, for paging through the whole collection
+func (s *Store) GetNadmonSnapshot(ctx context.Context, afterTokenID int64, limit int) ([]models.Nadmon, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+
+	nadmons := make([]models.Nadmon, 0, limit)
+	start := sort.Search(len(s.tokenIDs), func(i int) bool { return s.tokenIDs[i] > afterTokenID })
+	for _, id := range s.tokenIDs[start:] {
+		if len(nadmons) == limit {
+			break
+		}
+		if nadmon := s.nadmons[id]; circulating(nadmon) {
+			nadmons = append(nadmons, nadmon)
+		}
+	}
+	return nadmons, nil
+}
+
+// GetHolderSnapshot replays the transfers written up to asOf into every holder's balance at
+// that time
+func (s *Store) GetHolderSnapshot(ctx context.Context, asOf time.Time) (*models.HolderSnapshot, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+
+	balances := make(map[string]*models.HolderBalance)
+	snapshot := &models.HolderSnapshot{AsOf: asOf.UTC(), Holders: []models.HolderBalance{}}
+	for _, id := range s.tokenIDs {
+		mint := s.mintsByToken[id]
+		if mint.WrittenAt.After(asOf) {
+			continue
+		}
+		owner := mint.Owner
+		for _, transfer := range s.transfersByToken[id] {
+			if transfer.WrittenAt.After(asOf) {
+				break
+			}
+			owner = transfer.To
+		}
+		if owner == models.ZeroAddress {
+			continue
+		}
+		holder, ok := balances[owner]
+		if !ok {
+			holder = &models.HolderBalance{Address: owner}
+			balances[owner] = holder
+		}
+		holder.Balance++
+		holder.TokenIDs = append(holder.TokenIDs, id)
+		snapshot.TotalSupply++
+	}
+
+	for _, holder := range balances {
+		snapshot.Holders = append(snapshot.Holders, *holder)
+	}
+	sort.Slice(snapshot.Holders, func(i, j int) bool {
+		a, b := snapshot.Holders[i], snapshot.Holders[j]
+		if a.Balance != b.Balance {
+			return a.Balance > b.Balance
+		}
+		return a.Address < b.Address
+	})
+	snapshot.TotalHolders = len(snapshot.Holders)
+	return snapshot, nil
+}
+
+// GetNadmonStatuses reports whether each token is active, burned or unknown. Every requested
+// ID is present in the result.
+func (s *Store) GetNadmonStatuses(ctx context.Context, tokenIDs []int64) (map[int64]models.NadmonStatus, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+
+	statuses := make(map[int64]models.NadmonStatus, len(tokenIDs))
+	for _, id := range tokenIDs {
+		status := models.NadmonStatus{TokenID: id, Status: models.StatusUnknown}
+		if _, ok := s.nadmons[id]; ok {
+			status.Status = models.StatusActive
+		}
+		if burnedAt, ok := s.burnedAt[id]; ok {
+			status.Status = models.StatusBurned
+			status.BurnedAt = &burnedAt
+		}
+		statuses[id] = status
+	}
+	return statuses, nil
+}
+
+// toTransfer converts a transfer row into the API model
+func toTransfer(row transferRow) models.Transfer {
+	kind := models.TransferKindTransfer
+	switch {
+	case row.From == models.ZeroAddress:
+		kind = models.TransferKindMint
+	case row.To == models.ZeroAddress:
+		kind = models.TransferKindBurn
+	}
+
+	return models.Transfer{
+		ID:            row.ID,
+		TokenID:       int64(row.TokenID),
+		From:          row.From,
+		To:            row.To,
+		Kind:          kind,
+		TransferredAt: row.WrittenAt.Time,
+	}
+}
+
+// transferPage returns one page of the transfers matching match, newest first
+func (s *Store) transferPage(match func(transferRow) bool, limit, offset int) *models.TransferPage {
+	var matching []transferRow
+	for i := len(s.transfers) - 1; i >= 0; i-- {
+		if match(s.transfers[i]) {
+			matching = append(matching, s.transfers[i])
+		}
+	}
+
+	start, end := page(len(matching), limit, offset)
+	transfers := make([]models.Transfer, 0, end-start)
+	for _, row := range matching[start:end] {
+		transfers = append(transfers, toTransfer(row))
+	}
+	return &models.TransferPage{Transfers: transfers, Total: len(matching)}
+}
+
+// GetNadmonTransfers retrieves a page of an NFT's transfer history, newest first
+func (s *Store) GetNadmonTransfers(ctx context.Context, tokenID int64, limit, offset int) (*models.TransferPage, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+
+	return s.transferPage(func(row transferRow) bool { return int64(row.TokenID) == tokenID }, limit, offset), nil
+}
+
+// GetPlayerTransfers retrieves a page of transfers sent or received by a player, newest first
+func (s *Store) GetPlayerTransfers(ctx context.Context, address string, limit, offset int) (*models.TransferPage, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+	address = ethaddr.Normalize(address)
+
+	return s.transferPage(func(row transferRow) bool { return row.From == address || row.To == address }, limit, offset), nil
+}
+
+// GetNadmonSales retrieves a page of an NFT's marketplace sales, newest first
+func (s *Store) GetNadmonSales(ctx context.Context, tokenID int64, limit, offset int) (*models.SalePage, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+	if !s.salesIndexed {
+		return nil, fmt.Errorf("failed to query nadmon sales: %w", repository.ErrMarketplaceUnavailable)
+	}
+
+	var matching []saleRow
+	for i := len(s.sales) - 1; i >= 0; i-- {
+		if int64(s.sales[i].TokenID) == tokenID {
+			matching = append(matching, s.sales[i])
+		}
+	}
+
+	start, end := page(len(matching), limit, offset)
+	sales := make([]models.Sale, 0, end-start)
+	for _, row := range matching[start:end] {
+		sales = append(sales, models.Sale{
+			ID:      row.ID,
+			TokenID: int64(row.TokenID),
+			Seller:  row.Seller,
+			Buyer:   row.Buyer,
+			Price:   string(row.Price),
+			SoldAt:  row.WrittenAt.Time,
+		})
+	}
+	return &models.SalePage{Sales: sales, Total: len(matching)}, nil
+}
+
+// GetMarketStats computes sale count, volume, floor and last sale overall and per rarity and
+// element, optionally limited to one rarity and/or element (empty means any)
+func (s *Store) GetMarketStats(ctx context.Context, rarity, element string) (*models.MarketReport, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+	if !s.salesIndexed {
+		return nil, fmt.Errorf("failed to query market stats: %w", repository.ErrMarketplaceUnavailable)
+	}
+
+	floorSince := time.Now().Add(-repository.FloorWindow)
+	all := newMarketTally()
+	byRarity := map[string]*marketTally{}
+	byElement := map[string]*marketTally{}
+	for _, sale := range s.sales {
+		mint, ok := s.mintsByToken[int64(sale.TokenID)]
+		if !ok || rarity != "" && mint.Rarity != rarity || element != "" && mint.Element != element {
+			continue
+		}
+		if byRarity[mint.Rarity] == nil {
+			byRarity[mint.Rarity] = newMarketTally()
+		}
+		if byElement[mint.Element] == nil {
+			byElement[mint.Element] = newMarketTally()
+		}
+		for _, tally := range []*marketTally{all, byRarity[mint.Rarity], byElement[mint.Element]} {
+			if err := tally.add(sale, floorSince); err != nil {
+				return nil, fmt.Errorf("failed to query market stats: %w", err)
+			}
+		}
+	}
+
+	report := &models.MarketReport{
+		MarketStats: all.stats(),
+		FloorWindow: repository.FloorWindow.String(),
+		ByRarity:    make(map[string]models.MarketStats, len(byRarity)),
+		ByElement:   make(map[string]models.MarketStats, len(byElement)),
+	}
+	for value, tally := range byRarity {
+		report.ByRarity[value] = tally.stats()
+	}
+	for value, tally := range byElement {
+		report.ByElement[value] = tally.stats()
+	}
+	return report, nil
+}
+
+// GetActivity retrieves a page of the global activity feed, newest first, limited to the
+// given entry types
+func (s *Store) GetActivity(ctx context.Context, types []string, limit, offset int) (*models.ActivityPage, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+
+	wanted := typeSet(types)
+	var activities []models.Activity
+	if wanted[models.ActivityMint] {
+		for _, mint := range s.mints {
+			activities = append(activities, models.Activity{
+				ID: mint.ID, Type: models.ActivityMint, Player: mint.Owner,
+				TokenID: int64(mint.TokenID), PackID: int64(mint.PackID), Detail: mint.Rarity, OccurredAt: mint.WrittenAt.Time,
+			})
+		}
+	}
+	if wanted[models.ActivityTransfer] {
+		for _, transfer := range s.transfers {
+			if transfer.From == models.ZeroAddress {
+				continue
+			}
+			activities = append(activities, models.Activity{
+				ID: transfer.ID, Type: models.ActivityTransfer, Player: transfer.To, Counterparty: transfer.From,
+				TokenID: int64(transfer.TokenID), Detail: transferDetail(transfer), OccurredAt: transfer.WrittenAt.Time,
+			})
+		}
+	}
+	if wanted[models.ActivityEvolution] {
+		for _, change := range s.changes {
+			activities = append(activities, models.Activity{
+				ID: change.ID, Type: models.ActivityEvolution,
+				TokenID: int64(change.TokenID), Detail: change.ChangeType, OccurredAt: change.WrittenAt.Time,
+			})
+		}
+	}
+	if wanted[models.ActivityPack] {
+		for _, pack := range s.packs {
+			activities = append(activities, models.Activity{
+				ID: pack.ID, Type: models.ActivityPack, Player: pack.Player,
+				PackID: int64(pack.PackID), Detail: pack.PaymentType, OccurredAt: pack.WrittenAt.Time,
+			})
+		}
+	}
+
+	return activityPage(activities, limit, offset), nil
+}
+
+// GetPlayerActivity retrieves a page of a player's activity feed, newest first, limited to
+// the given entry types
+func (s *Store) GetPlayerActivity(ctx context.Context, address string, types []string, limit, offset int) (*models.ActivityPage, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+	address = ethaddr.Normalize(address)
+
+	wanted := typeSet(types)
+	var activities []models.Activity
+	if wanted[models.ActivityMint] {
+		for _, mint := range s.mints {
+			if mint.Owner == address {
+				activities = append(activities, models.Activity{
+					ID: mint.ID, Type: models.ActivityMint, Player: address,
+					TokenID: int64(mint.TokenID), PackID: int64(mint.PackID), Detail: mint.Rarity, OccurredAt: mint.WrittenAt.Time,
+				})
+			}
+		}
+	}
+	for _, transfer := range s.transfers {
+		if wanted[models.ActivityTransferIn] && transfer.To == address && transfer.From != models.ZeroAddress {
+			activities = append(activities, models.Activity{
+				ID: transfer.ID, Type: models.ActivityTransferIn, Player: address, Counterparty: transfer.From,
+				TokenID: int64(transfer.TokenID), Detail: models.ActivityTransfer, OccurredAt: transfer.WrittenAt.Time,
+			})
+		}
+		if wanted[models.ActivityTransferOut] && transfer.From == address {
+			activities = append(activities, models.Activity{
+				ID: transfer.ID, Type: models.ActivityTransferOut, Player: address, Counterparty: transfer.To,
+				TokenID: int64(transfer.TokenID), Detail: transferDetail(transfer), OccurredAt: transfer.WrittenAt.Time,
+			})
+		}
+	}
+	if wanted[models.ActivityEvolution] {
+		// Stat changes belong to whoever held the Nadmon at the time
+		for _, change := range s.changes {
+			if s.holderAt(int64(change.TokenID), change.WrittenAt.Time) == address {
+				activities = append(activities, models.Activity{
+					ID: change.ID, Type: models.ActivityEvolution, Player: address,
+					TokenID: int64(change.TokenID), Detail: change.ChangeType, OccurredAt: change.WrittenAt.Time,
+				})
+			}
+		}
+	}
+	if wanted[models.ActivityPack] {
+		for _, pack := range s.packs {
+			if pack.Player == address {
+				activities = append(activities, models.Activity{
+					ID: pack.ID, Type: models.ActivityPack, Player: address,
+					PackID: int64(pack.PackID), Detail: pack.PaymentType, OccurredAt: pack.WrittenAt.Time,
+				})
+			}
+		}
+	}
+
+	return activityPage(activities, limit, offset), nil
+}
+
+// transferDetail tells burns from ordinary transfers
+func transferDetail(row transferRow) string {
+	if row.To == models.ZeroAddress {
+		return "burn"
+	}
+	return models.ActivityTransfer
+}
+
+func typeSet(types []string) map[string]bool {
+	set := make(map[string]bool, len(types))
+	for _, kind := range types {
+		set[kind] = true
+	}
+	return set
+}
+
+// activityPage sorts activities newest first and returns one page of them
+func activityPage(activities []models.Activity, limit, offset int) *models.ActivityPage {
+	sort.SliceStable(activities, func(i, j int) bool {
+		a, b := activities[i], activities[j]
+		if !a.OccurredAt.Equal(b.OccurredAt) {
+			return a.OccurredAt.After(b.OccurredAt)
+		}
+		return a.ID > b.ID
+	})
+
+	start, end := page(len(activities), limit, offset)
+	return &models.ActivityPage{
+		Activities: append(make([]models.Activity, 0, end-start), activities[start:end]...),
+		Total:      len(activities),
+	}
+}
+
+// GetPackByID retrieves a specific pack by its ID
+func (s *Store) GetPackByID(ctx context.Context, packID int64) (*models.Pack, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+
+	for _, row := range s.packs {
+		if int64(row.PackID) == packID {
+			pack := toPack(row)
+			return &pack, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetRecentPacks retrieves the most recent pack purchases
+func (s *Store) GetRecentPacks(ctx context.Context, limit int) ([]models.Pack, error) {
+	if err := s.read(); err != nil {
+		return nil, err
+	}
+	defer s.mu.RUnlock()
+
+	var packs []models.Pack
+	for _, row := range s.packsBySequence() {
+		if len(packs) == limit {
+			break
+		}
+		packs = append(packs, toPack(row))
+	}
+	return packs, nil
+}
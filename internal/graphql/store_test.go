@@ -0,0 +1,183 @@
+//go:build integration
+
+package graphql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"nadmon-backend/internal/fixtures"
+	"nadmon-backend/internal/models"
+	"nadmon-backend/internal/repository"
+	"nadmon-backend/internal/testharness"
+
+	"github.com/lib/pq"
+)
+
+// tableQuery matches the table queries the store sends
+var tableQuery = regexp.MustCompile(`(?s)rows: (\w+)\(\s*where: \{db_write_timestamp: \{_gte: "([^"]*)"\}\},.*offset: (\d+), limit: (\d+)\s*\) \{ ([^}]*) \}`)
+
+// newFakeHasura serves the store's table queries from db the way Hasura does, reporting
+// unknown tables as validation errors
+func newFakeHasura(t *testing.T, db *sql.DB) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Query string `json:"query"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		match := tableQuery.FindStringSubmatch(request.Query)
+		if match == nil {
+			http.Error(w, "unexpected query: "+request.Query, http.StatusBadRequest)
+			return
+		}
+
+		var columns []string
+		for _, field := range strings.Fields(match[5]) {
+			columns = append(columns, pq.QuoteIdentifier(field))
+		}
+		offset, _ := strconv.Atoi(match[3])
+		limit, _ := strconv.Atoi(match[4])
+
+		var rows []byte
+		err := db.QueryRowContext(r.Context(), `
+			SELECT COALESCE(json_agg(r), '[]') FROM (
+				SELECT `+strings.Join(columns, ", ")+` FROM `+pq.QuoteIdentifier(match[1])+`
+				WHERE db_write_timestamp >= $1::timestamp
+				ORDER BY db_write_timestamp, id
+				OFFSET $2 LIMIT $3
+			) r
+		`, match[2], offset, limit).Scan(&rows)
+
+		w.Header().Set("Content-Type", "application/json")
+		var pqErr *pq.Error
+		switch {
+		case errors.As(err, &pqErr) && pqErr.Code == "42P01":
+			w.Write([]byte(`{"errors":[{"message":"field '` + match[1] + `' not found in type: 'query_root'","extensions":{"code":"validation-failed"}}]}`))
+		case err != nil:
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		default:
+			w.Write([]byte(`{"data":{"rows":` + string(rows) + `}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// TestStoreMatchesRepository checks that the GraphQL mirror answers like the Postgres
+// repository reading the same fixtures
+func TestStoreMatchesRepository(t *testing.T) {
+	ctx := context.Background()
+	envioDB := testharness.StartEnvioDB(t)
+	repo := repository.NewNadmonRepository(envioDB)
+	repo.SetExcludedAddresses([]string{fixtures.Carol})
+
+	store := NewStore(NewClient(newFakeHasura(t, envioDB.DB).URL, ""))
+	store.SetExcludedAddresses([]string{fixtures.Carol})
+	if _, err := store.GetSingleNadmon(ctx, 1); !errors.Is(err, ErrNotSynced) {
+		t.Fatalf("reads before the first sync should fail with ErrNotSynced, got %v", err)
+	}
+	mirrored, err := store.Sync(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mirrored == 0 {
+		t.Fatal("the first sync mirrored no rows")
+	}
+	if again, err := store.Sync(ctx); err != nil || again != 0 {
+		t.Fatalf("a sync without new rows mirrored %d rows (err %v)", again, err)
+	}
+
+	day := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+	calls := map[string]func(repository.Store) (interface{}, error){
+		"GetPlayerNadmons": func(s repository.Store) (interface{}, error) { return s.GetPlayerNadmons(ctx, fixtures.Alice) },
+		"GetPlayerProfile": func(s repository.Store) (interface{}, error) { return s.GetPlayerProfile(ctx, fixtures.Alice) },
+		"GetPlayerSummary": func(s repository.Store) (interface{}, error) { return s.GetPlayerSummary(ctx, fixtures.Alice) },
+		"GetPlayerPacks":   func(s repository.Store) (interface{}, error) { return s.GetPlayerPacks(ctx, fixtures.Alice) },
+		"GetPlayerDex":     func(s repository.Store) (interface{}, error) { return s.GetPlayerDex(ctx, fixtures.Bob) },
+		"SearchNadmons": func(s repository.Store) (interface{}, error) {
+			return s.SearchNadmons(ctx, fixtures.Alice, map[string]interface{}{"min_attack": 20, "sort_by": "rarity", "order": "desc"})
+		},
+		"GetInventoryChanges": func(s repository.Store) (interface{}, error) { return s.GetInventoryChanges(ctx, fixtures.Carol, 0) },
+		"GetInventoryChanges since": func(s repository.Store) (interface{}, error) {
+			return s.GetInventoryChanges(ctx, fixtures.Alice, models.SyncSequence(day.Add(48*time.Hour)))
+		},
+		"GetSingleNadmon":        func(s repository.Store) (interface{}, error) { return s.GetSingleNadmon(ctx, 5) },
+		"GetSingleNadmon burned": func(s repository.Store) (interface{}, error) { return s.GetSingleNadmon(ctx, 13) },
+		"GetNadmonsByIDs":        func(s repository.Store) (interface{}, error) { return s.GetNadmonsByIDs(ctx, []int64{3, 5, 13, 999}) },
+		"GetNadmonHistory":       func(s repository.Store) (interface{}, error) { return s.GetNadmonHistory(ctx, 5) },
+		"GetFusions":             func(s repository.Store) (interface{}, error) { return s.GetFusions(ctx, []int64{2, 4, 13, 999}) },
+		"GetNadmonSnapshot":      func(s repository.Store) (interface{}, error) { return s.GetNadmonSnapshot(ctx, 4, 5) },
+		"GetNadmonStatuses":      func(s repository.Store) (interface{}, error) { return s.GetNadmonStatuses(ctx, []int64{1, 13, 999}) },
+		"GetNadmonTransfers":     func(s repository.Store) (interface{}, error) { return s.GetNadmonTransfers(ctx, 3, 10, 0) },
+		"GetPlayerTransfers":     func(s repository.Store) (interface{}, error) { return s.GetPlayerTransfers(ctx, fixtures.Alice, 3, 1) },
+		"GetHolderSnapshot":      func(s repository.Store) (interface{}, error) { return s.GetHolderSnapshot(ctx, day.Add(60*time.Hour)) },
+		"GetNadmonSales":         func(s repository.Store) (interface{}, error) { return s.GetNadmonSales(ctx, 3, 10, 0) },
+		"GetActivity": func(s repository.Store) (interface{}, error) {
+			return s.GetActivity(ctx, models.ActivityTypes, 10, 2)
+		},
+		"GetPlayerActivity": func(s repository.Store) (interface{}, error) {
+			return s.GetPlayerActivity(ctx, fixtures.Alice, models.PlayerActivityTypes, 50, 0)
+		},
+		"GetPackByID":               func(s repository.Store) (interface{}, error) { return s.GetPackByID(ctx, 2) },
+		"GetRecentPacks":            func(s repository.Store) (interface{}, error) { return s.GetRecentPacks(ctx, 2) },
+		"GetGameStats":              func(s repository.Store) (interface{}, error) { return s.GetGameStats(ctx) },
+		"GetPackDistribution":       func(s repository.Store) (interface{}, error) { return s.GetPackDistribution(ctx) },
+		"GetOwnershipConcentration": func(s repository.Store) (interface{}, error) { return s.GetOwnershipConcentration(ctx) },
+		"GetSupplyStats":            func(s repository.Store) (interface{}, error) { return s.GetSupplyStats(ctx) },
+		"GetTraitStats element":     func(s repository.Store) (interface{}, error) { return s.GetTraitStats(ctx, models.DimensionElement) },
+		"GetTraitStats rarity":      func(s repository.Store) (interface{}, error) { return s.GetTraitStats(ctx, models.DimensionRarity) },
+		"GetPackOdds":               func(s repository.Store) (interface{}, error) { return s.GetPackOdds(ctx, day, time.Time{}) },
+		"GetSearchSuggestions":      func(s repository.Store) (interface{}, error) { return s.GetSearchSuggestions(ctx, "r", 10) },
+		"GetEventTimeSeries": func(s repository.Store) (interface{}, error) {
+			return s.GetEventTimeSeries(ctx, models.MetricPlayers, "hour", day, day.Add(72*time.Hour))
+		},
+	}
+	for _, kind := range models.LeaderboardTypes {
+		kind := kind
+		calls["GetLeaderboard "+kind] = func(s repository.Store) (interface{}, error) {
+			return s.GetLeaderboard(ctx, kind, fixtures.Bob, 10, 0)
+		}
+	}
+
+	for name, call := range calls {
+		t.Run(name, func(t *testing.T) {
+			want, wantErr := call(repo)
+			got, gotErr := call(store)
+			if (wantErr == nil) != (gotErr == nil) {
+				t.Fatalf("error = %v, repository error = %v", gotErr, wantErr)
+			}
+			wantJSON, _ := json.Marshal(want)
+			gotJSON, _ := json.Marshal(got)
+			var wantValue, gotValue interface{}
+			json.Unmarshal(wantJSON, &wantValue)
+			json.Unmarshal(gotJSON, &gotValue)
+			if !reflect.DeepEqual(gotValue, wantValue) {
+				t.Errorf("got %s\nrepository returned %s", gotJSON, wantJSON)
+			}
+		})
+	}
+
+	t.Run("GetTopCollectors", func(t *testing.T) {
+		collectors, err := store.GetTopCollectors(ctx, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(collectors) != 2 || collectors[0].Address != fixtures.Alice || collectors[0].TotalNFTs != 8 {
+			t.Errorf("expected alice with 8 NFTs first and carol excluded, got %+v", collectors)
+		}
+	})
+}
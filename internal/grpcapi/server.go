@@ -0,0 +1,213 @@
+// Package grpcapi serves the repository over gRPC for internal game servers that want
+// inventories without the JSON/HTTP overhead. The protobuf definitions live in
+// proto/nadmon/v1; the generated code is in nadmonv1.
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"runtime/debug"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"nadmon-backend/internal/database"
+	"nadmon-backend/internal/ethaddr"
+	"nadmon-backend/internal/grpcapi/nadmonv1"
+	"nadmon-backend/internal/models"
+	"nadmon-backend/internal/repository"
+)
+
+// NewServer returns a gRPC server exposing repo as nadmon.v1.NadmonService, with server
+// reflection enabled for tools like grpcurl. Each call gets a deadline of timeout unless the
+// client set an earlier one; a zero timeout leaves calls unbounded. GetNadmons accepts at
+// most maxBatchIDs token IDs, like GET /api/nfts?ids=.
+func NewServer(repo repository.Store, timeout time.Duration, maxBatchIDs int) *grpc.Server {
+	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(recoverPanics, withTimeout(timeout)))
+	nadmonv1.RegisterNadmonServiceServer(srv, &service{repo: repo, maxBatchIDs: maxBatchIDs})
+	reflection.Register(srv)
+	return srv
+}
+
+// service implements nadmonv1.NadmonServiceServer on top of a repository.Store
+type service struct {
+	nadmonv1.UnimplementedNadmonServiceServer
+	repo        repository.Store
+	maxBatchIDs int
+}
+
+func (s *service) GetInventory(ctx context.Context, req *nadmonv1.GetInventoryRequest) (*nadmonv1.GetInventoryResponse, error) {
+	if !ethaddr.Valid(req.Address) {
+		return nil, status.Error(codes.InvalidArgument, "invalid Ethereum address")
+	}
+
+	nadmons, err := s.repo.GetPlayerNadmons(ctx, req.Address)
+	if err != nil {
+		return nil, toStatus(ctx, "failed to fetch inventory", err)
+	}
+	return &nadmonv1.GetInventoryResponse{Nadmons: toNadmons(nadmons)}, nil
+}
+
+func (s *service) GetNadmon(ctx context.Context, req *nadmonv1.GetNadmonRequest) (*nadmonv1.Nadmon, error) {
+	if req.TokenId < 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid token ID")
+	}
+
+	nadmon, err := s.repo.GetSingleNadmon(ctx, req.TokenId)
+	if err != nil {
+		return nil, toStatus(ctx, "failed to fetch NFT", err)
+	}
+	if nadmon == nil {
+		return nil, status.Error(codes.NotFound, "NFT not found")
+	}
+	return toNadmon(*nadmon), nil
+}
+
+func (s *service) GetNadmons(ctx context.Context, req *nadmonv1.GetNadmonsRequest) (*nadmonv1.GetNadmonsResponse, error) {
+	if len(req.TokenIds) > s.maxBatchIDs {
+		return nil, status.Errorf(codes.InvalidArgument, "at most %d token IDs per call", s.maxBatchIDs)
+	}
+	if len(req.TokenIds) == 0 {
+		return &nadmonv1.GetNadmonsResponse{}, nil
+	}
+
+	nadmons, err := s.repo.GetNadmonsByIDs(ctx, req.TokenIds)
+	if err != nil {
+		return nil, toStatus(ctx, "failed to fetch NFTs", err)
+	}
+	return &nadmonv1.GetNadmonsResponse{Nadmons: toNadmons(nadmons)}, nil
+}
+
+func (s *service) GetProfile(ctx context.Context, req *nadmonv1.GetProfileRequest) (*nadmonv1.Profile, error) {
+	if !ethaddr.Valid(req.Address) {
+		return nil, status.Error(codes.InvalidArgument, "invalid Ethereum address")
+	}
+
+	profile, err := s.repo.GetPlayerProfile(ctx, req.Address)
+	if err != nil {
+		return nil, toStatus(ctx, "failed to fetch player profile", err)
+	}
+	return &nadmonv1.Profile{
+		Address:     profile.Address,
+		TotalNfts:   int32(profile.TotalNFTs),
+		PacksBought: int32(profile.PacksBought),
+		Nadmons:     toNadmons(profile.Nadmons),
+		LastActive:  toTimestamp(profile.LastActive),
+	}, nil
+}
+
+func (s *service) GetPlayerPacks(ctx context.Context, req *nadmonv1.GetPlayerPacksRequest) (*nadmonv1.GetPlayerPacksResponse, error) {
+	if !ethaddr.Valid(req.Address) {
+		return nil, status.Error(codes.InvalidArgument, "invalid Ethereum address")
+	}
+
+	packs, err := s.repo.GetPlayerPacks(ctx, req.Address)
+	if err != nil {
+		return nil, toStatus(ctx, "failed to fetch player packs", err)
+	}
+	resp := &nadmonv1.GetPlayerPacksResponse{Packs: make([]*nadmonv1.Pack, 0, len(packs))}
+	for _, pack := range packs {
+		resp.Packs = append(resp.Packs, toPack(pack))
+	}
+	return resp, nil
+}
+
+func (s *service) GetPack(ctx context.Context, req *nadmonv1.GetPackRequest) (*nadmonv1.Pack, error) {
+	if req.PackId < 0 {
+		return nil, status.Error(codes.InvalidArgument, "invalid pack ID")
+	}
+
+	pack, err := s.repo.GetPackByID(ctx, req.PackId)
+	if err != nil {
+		return nil, toStatus(ctx, "failed to fetch pack", err)
+	}
+	if pack == nil {
+		return nil, status.Error(codes.NotFound, "pack not found")
+	}
+	return toPack(*pack), nil
+}
+
+// toStatus maps a repository error to a gRPC status: cancelled and timed out calls keep
+// their context code, an open database circuit is UNAVAILABLE and anything else INTERNAL
+func toStatus(ctx context.Context, msg string, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return status.FromContextError(ctxErr).Err()
+	}
+	if errors.Is(err, database.ErrCircuitOpen) {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+	return status.Errorf(codes.Internal, "%s: %v", msg, err)
+}
+
+// withTimeout bounds each call by timeout, keeping an earlier client deadline
+func withTimeout(timeout time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if timeout <= 0 {
+			return handler(ctx, req)
+		}
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return handler(ctx, req)
+	}
+}
+
+// recoverPanics turns a panicking handler into an INTERNAL error instead of crashing the process
+func recoverPanics(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("❌ gRPC panic", "method", info.FullMethod, "panic", r, "stack", string(debug.Stack()))
+			err = status.Error(codes.Internal, "internal error")
+		}
+	}()
+	return handler(ctx, req)
+}
+
+func toNadmons(nadmons []models.Nadmon) []*nadmonv1.Nadmon {
+	out := make([]*nadmonv1.Nadmon, 0, len(nadmons))
+	for _, nadmon := range nadmons {
+		out = append(out, toNadmon(nadmon))
+	}
+	return out
+}
+
+func toNadmon(n models.Nadmon) *nadmonv1.Nadmon {
+	return &nadmonv1.Nadmon{
+		TokenId:     n.TokenID,
+		Owner:       n.Owner,
+		PackId:      n.PackID,
+		NadmonType:  n.NadmonType,
+		Element:     n.Element,
+		Rarity:      n.Rarity,
+		Hp:          n.HP,
+		Attack:      n.Attack,
+		Defense:     n.Defense,
+		Crit:        n.Crit,
+		Fusion:      n.Fusion,
+		Evo:         n.Evo,
+		CreatedAt:   toTimestamp(n.CreatedAt),
+		LastUpdated: toTimestamp(n.LastUpdated),
+	}
+}
+
+func toPack(p models.Pack) *nadmonv1.Pack {
+	return &nadmonv1.Pack{
+		PackId:      p.PackID,
+		Player:      p.Player,
+		TokenIds:    p.TokenIDs,
+		PaymentType: p.PaymentType,
+		PurchasedAt: toTimestamp(p.PurchasedAt),
+	}
+}
+
+// toTimestamp leaves unknown (zero) times unset
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"nadmon-backend/internal/database"
+	"nadmon-backend/internal/repository"
+	"nadmon-backend/internal/slowlog"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	db      *database.EnvioDB
+	cache   *repository.CachedStore
+	slowLog *slowlog.Log
+}
+
+// NewAdminHandler creates a handler for operational tasks; db and cache may be nil when the
+// database or the Redis cache is not in use
+func NewAdminHandler(db *database.EnvioDB, cache *repository.CachedStore, slowLog *slowlog.Log) *AdminHandler {
+	return &AdminHandler{db: db, cache: cache, slowLog: slowLog}
+}
+
+// FlushCache drops every cached repository result
+func (h *AdminHandler) FlushCache(c *gin.Context) {
+	if h.cache == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Cache is not enabled"})
+		return
+	}
+
+	if err := h.cache.Flush(); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to flush cache: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"flushed": true})
+}
+
+// RebuildIndexes recreates and reindexes the backend's indexes on the Envio tables
+func (h *AdminHandler) RebuildIndexes(c *gin.Context) {
+	if h.db == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Database is not connected"})
+		return
+	}
+
+	results, err := h.db.RebuildIndexes(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rebuild indexes: " + err.Error()})
+		return
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Error != "" {
+			failed++
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"indexes": results,
+		"failed":  failed,
+	})
+}
+
+// maxSlowQueries caps the statements of one slow query report
+const maxSlowQueries = 100
+
+// GetSlowQueries returns the statements with the highest mean execution time
+func (h *AdminHandler) GetSlowQueries(c *gin.Context) {
+	if h.db == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Database is not connected"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > maxSlowQueries {
+		limit = 20
+	}
+
+	queries, err := h.db.SlowQueries(c.Request.Context(), limit)
+	if errors.Is(err, database.ErrStatStatementsUnavailable) {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "Slow query reports need the pg_stat_statements extension"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch slow queries: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": queries})
+}
+
+// GetSlowOperations returns the slowest queries and requests over their latency budget seen
+// by this instance recently, slowest first
+func (h *AdminHandler) GetSlowOperations(c *gin.Context) {
+	kind := c.Query("kind")
+	if kind != "" && kind != slowlog.KindQuery && kind != slowlog.KindRequest {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid kind, expected query or request"})
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > maxSlowQueries {
+		limit = 20
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":           h.slowLog.Top(kind, limit),
+		"threshold_ms":   h.slowLog.Threshold().Milliseconds(),
+		"window_seconds": int(h.slowLog.Window().Seconds()),
+	})
+}
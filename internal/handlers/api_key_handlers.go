@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"nadmon-backend/internal/apikeys"
+	"nadmon-backend/internal/models"
+	"nadmon-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// API key request limits
+const (
+	maxAPIKeyNameLength = 100
+	maxAPIKeyUsageDays  = 365
+)
+
+// APIKeyRequest is the body of POST /admin/api-keys. A zero rate limit or burst takes the
+// configured default; scopes default to read.
+type APIKeyRequest struct {
+	Name       string   `json:"name"`
+	Scopes     []string `json:"scopes"`
+	RateLimit  float64  `json:"rate_limit"`
+	Burst      int      `json:"burst"`
+	DailyQuota int64    `json:"daily_quota"`
+}
+
+// APIKeyHandler issues and revokes partner API keys and reports their usage; it is mounted
+// behind the admin API key
+type APIKeyHandler struct {
+	keys         *apikeys.Service
+	store        repository.APIKeyStore
+	defaultRate  float64
+	defaultBurst int
+}
+
+// NewAPIKeyHandler creates an API key handler issuing keys with the given default bucket
+func NewAPIKeyHandler(keys *apikeys.Service, store repository.APIKeyStore, defaultRate float64, defaultBurst int) *APIKeyHandler {
+	return &APIKeyHandler{keys: keys, store: store, defaultRate: defaultRate, defaultBurst: defaultBurst}
+}
+
+// CreateAPIKey issues a key. The response is the only one carrying the key itself.
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	var req APIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > maxAPIKeyNameLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid name, expected 1 to %d characters", maxAPIKeyNameLength)})
+		return
+	}
+
+	scopes, err := parseAPIKeyScopes(req.Scopes)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scopes, " + err.Error()})
+		return
+	}
+
+	if req.RateLimit < 0 || req.Burst < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rate limit, expected rate_limit and burst of 0 (default) or more"})
+		return
+	}
+	if req.RateLimit == 0 {
+		req.RateLimit = h.defaultRate
+	}
+	if req.Burst == 0 {
+		req.Burst = h.defaultBurst
+	}
+	if req.DailyQuota < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid daily_quota, expected 0 (unlimited) or more"})
+		return
+	}
+
+	key, err := h.keys.Issue(c.Request.Context(), models.APIKey{
+		Name:       name,
+		Scopes:     scopes,
+		RateLimit:  req.RateLimit,
+		Burst:      req.Burst,
+		DailyQuota: req.DailyQuota,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to issue API key: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, key)
+}
+
+// GetAPIKeys lists every key with its usage today and over the last 30 days
+func (h *APIKeyHandler) GetAPIKeys(c *gin.Context) {
+	keys, err := h.store.GetAPIKeys(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch API keys: " + err.Error()})
+		return
+	}
+
+	var today, last30Days int64
+	active := 0
+	for _, key := range keys {
+		if key.Active() {
+			active++
+		}
+		today += key.Usage.Today
+		last30Days += key.Usage.Last30Days
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   keys,
+		"total":  len(keys),
+		"active": active,
+		"usage": gin.H{
+			"today":        today,
+			"last_30_days": last30Days,
+		},
+	})
+}
+
+// GetAPIKeyUsage returns a key's requests per UTC day over the last ?days= days (default 30),
+// newest first. Usage is written to the database periodically, so the latest requests may be
+// missing.
+func (h *APIKeyHandler) GetAPIKeyUsage(c *gin.Context) {
+	id, ok := apiKeyID(c)
+	if !ok {
+		return
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 || days > maxAPIKeyUsageDays {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid days, expected 1 to %d", maxAPIKeyUsageDays)})
+		return
+	}
+
+	usage, err := h.store.GetAPIKeyUsage(c.Request.Context(), id, days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch API key usage: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"key_id": id,
+		"days":   days,
+		"data":   usage,
+	})
+}
+
+// RevokeAPIKey revokes a key; its usage history is kept
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	id, ok := apiKeyID(c)
+	if !ok {
+		return
+	}
+
+	found, err := h.keys.Revoke(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke API key: " + err.Error()})
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "API key not found"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// apiKeyID parses the :id parameter, writing the error response when it is invalid
+func apiKeyID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid API key ID"})
+		return 0, false
+	}
+	return id, true
+}
+
+// parseAPIKeyScopes checks the requested scopes and drops duplicates; none means read
+func parseAPIKeyScopes(requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return []string{models.APIKeyScopeRead}, nil
+	}
+
+	var scopes []string
+	seen := make(map[string]bool, len(requested))
+	for _, scope := range requested {
+		known := false
+		for _, s := range models.APIKeyScopes {
+			known = known || s == scope
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown scope %q, expected one of %v", scope, models.APIKeyScopes)
+		}
+		if !seen[scope] {
+			seen[scope] = true
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, nil
+}
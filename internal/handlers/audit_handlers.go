@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"nadmon-backend/internal/ethaddr"
+	"nadmon-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Audit log page sizes
+const (
+	defaultAuditPageSize = 100
+	maxAuditPageSize     = 1000
+)
+
+// AuditHandler serves the audit log of player data reads; it is mounted behind the admin API
+// key
+type AuditHandler struct {
+	store repository.AuditStore
+}
+
+// NewAuditHandler creates an audit handler reading from store
+func NewAuditHandler(store repository.AuditStore) *AuditHandler {
+	return &AuditHandler{store: store}
+}
+
+// GetAuditLog returns audit entries newest first, filtered by ?address=, ?api_key_id=, ?ip=
+// and a ?from= / ?to= time range. Pages continue with ?before= set to the previous page's
+// next_before. Entries are written every few seconds, so the latest reads may be missing.
+func (h *AuditHandler) GetAuditLog(c *gin.Context) {
+	var filter repository.AuditFilter
+
+	if address := c.Query("address"); address != "" {
+		if !ethaddr.Valid(address) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid address, expected an Ethereum address"})
+			return
+		}
+		filter.Address = address
+	}
+	if value := c.Query("api_key_id"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || id < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid api_key_id, expected a positive integer"})
+			return
+		}
+		filter.APIKeyID = id
+	}
+	filter.ClientIP = c.Query("ip")
+	if value := c.Query("from"); value != "" {
+		from, err := parseTimeParam(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from, expected RFC 3339 or YYYY-MM-DD"})
+			return
+		}
+		filter.From = from
+	}
+	if value := c.Query("to"); value != "" {
+		to, err := parseTimeParam(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to, expected RFC 3339 or YYYY-MM-DD"})
+			return
+		}
+		filter.To = to
+	}
+	if value := c.Query("before"); value != "" {
+		id, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || id < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid before, expected the next_before of a previous page"})
+			return
+		}
+		filter.BeforeID = id
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultAuditPageSize)))
+	if err != nil || limit < 1 || limit > maxAuditPageSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid limit, expected 1 to %d", maxAuditPageSize)})
+		return
+	}
+
+	entries, err := h.store.GetAuditEntries(c.Request.Context(), filter, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch audit log: " + err.Error()})
+		return
+	}
+
+	var nextBefore interface{}
+	if len(entries) == limit {
+		nextBefore = entries[len(entries)-1].ID
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":        entries,
+		"total":       len(entries),
+		"next_before": nextBefore,
+	})
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+
+	"nadmon-backend/internal/auth"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuthHandler struct {
+	auth *auth.Service
+}
+
+// NewAuthHandler creates a new handler for Sign-In With Ethereum
+func NewAuthHandler(service *auth.Service) *AuthHandler {
+	return &AuthHandler{auth: service}
+}
+
+// GetNonce issues a single-use nonce to embed in the SIWE message
+func (h *AuthHandler) GetNonce(c *gin.Context) {
+	nonce, expiresAt, err := h.auth.Nonce()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to issue nonce: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"nonce":     nonce,
+		"expiresAt": expiresAt,
+	})
+}
+
+type verifyRequest struct {
+	Message   string `json:"message" binding:"required"`
+	Signature string `json:"signature" binding:"required"`
+}
+
+// Verify checks a signed SIWE message and returns a JWT for the signing wallet
+func (h *AuthHandler) Verify(c *gin.Context) {
+	var req verifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	token, address, expiresAt, err := h.auth.Verify(req.Message, req.Signature)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Failed to verify signature: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token":     token,
+		"address":   address,
+		"expiresAt": expiresAt,
+	})
+}
+
+// GetSession returns the wallet address bound to the caller's token
+func (h *AuthHandler) GetSession(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"address": c.GetString(auth.AddressKey)})
+}
+
+// GetStreamToken issues a short-lived token for opening the caller's private WebSocket or
+// event stream
+func (h *AuthHandler) GetStreamToken(c *gin.Context) {
+	token, expiresAt, err := h.auth.IssueStreamToken(c.GetString(auth.AddressKey))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to issue stream token: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"token":     token,
+		"expiresAt": expiresAt,
+	})
+}
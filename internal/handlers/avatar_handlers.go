@@ -0,0 +1,45 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"nadmon-backend/internal/avatar"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AvatarHandler struct {
+	cache *avatar.Cache
+}
+
+// NewAvatarHandler creates a new handler serving generated avatars
+func NewAvatarHandler() *AvatarHandler {
+	return &AvatarHandler{cache: avatar.NewCache()}
+}
+
+// GetAvatar returns a deterministic identicon PNG for an address
+func (h *AvatarHandler) GetAvatar(c *gin.Context) {
+	address := c.Param("address")
+	if !isValidEthereumAddress(address) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Ethereum address format"})
+		return
+	}
+
+	// The image only depends on the address, so the lowercased address is a stable ETag
+	etag := `"` + strings.ToLower(address) + `"`
+	c.Header("Cache-Control", "public, max-age=604800, immutable")
+	c.Header("ETag", etag)
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	img, err := h.cache.Get(address)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate avatar: " + err.Error()})
+		return
+	}
+
+	c.Data(http.StatusOK, "image/png", img)
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"nadmon-backend/internal/models"
+	"nadmon-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxBattleClockSkew is how far in the future a battle's fought_at may be, allowing for clock
+// drift between the game server and the API
+const maxBattleClockSkew = time.Minute
+
+// BattlePlayerRequest is one player of a reported battle
+type BattlePlayerRequest struct {
+	Address      string  `json:"address"`
+	TeamTokenIDs []int64 `json:"team_token_ids"`
+}
+
+// BattleRequest is the body of POST /battles, sent by the game server
+type BattleRequest struct {
+	BattleID string              `json:"battle_id"`
+	Player1  BattlePlayerRequest `json:"player1"`
+	Player2  BattlePlayerRequest `json:"player2"`
+	// Winner is one of the players' addresses; empty for a draw
+	Winner string `json:"winner"`
+	// FoughtAt defaults to the time the result is recorded
+	FoughtAt *time.Time `json:"fought_at"`
+}
+
+// PlayerBattlesResponse is a page of a player's battles with their PvP record
+type PlayerBattlesResponse struct {
+	PaginatedResponse
+	Record *models.PvPRecord `json:"record"`
+}
+
+// PvPLeaderboardResponse is a page of the PvP leaderboard; Player is the ?address= player's
+// own record
+type PvPLeaderboardResponse struct {
+	PaginatedResponse
+	Player *models.PvPRecord `json:"player"`
+}
+
+// SetBattleStore enables the battle endpoints, recording battles and ratings in store
+func (h *NadmonHandler) SetBattleStore(store repository.BattleStore) {
+	h.battles = store
+}
+
+// RecordBattle stores a battle result reported by the game server and updates both players'
+// ELO ratings. Reporting a battle ID again returns the stored result with 200 instead of 201.
+func (h *NadmonHandler) RecordBattle(c *gin.Context) {
+	if h.battles == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Battles are not available"})
+		return
+	}
+
+	var req BattleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	if req.BattleID == "" || len(req.BattleID) > models.MaxBattleIDLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid battle_id, expected 1-%d characters", models.MaxBattleIDLength)})
+		return
+	}
+	for _, player := range []BattlePlayerRequest{req.Player1, req.Player2} {
+		if !isValidEthereumAddress(player.Address) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player address, expected a 0x-prefixed Ethereum address"})
+			return
+		}
+		if len(player.TeamTokenIDs) > h.limits.MaxTeamSize {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid team_token_ids, expected at most %d Nadmons", h.limits.MaxTeamSize)})
+			return
+		}
+	}
+	if strings.EqualFold(req.Player1.Address, req.Player2.Address) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid players, expected two different addresses"})
+		return
+	}
+	var winner *string
+	if req.Winner != "" {
+		if !strings.EqualFold(req.Winner, req.Player1.Address) && !strings.EqualFold(req.Winner, req.Player2.Address) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid winner, expected one of the players or empty for a draw"})
+			return
+		}
+		winner = &req.Winner
+	}
+	foughtAt := time.Now().UTC()
+	if req.FoughtAt != nil {
+		if req.FoughtAt.After(foughtAt.Add(maxBattleClockSkew)) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid fought_at, expected a time in the past"})
+			return
+		}
+		foughtAt = req.FoughtAt.UTC()
+	}
+
+	battle := models.Battle{
+		BattleID: req.BattleID,
+		Player1:  models.BattleSide{Address: req.Player1.Address, TeamTokenIDs: nonNilIDs(req.Player1.TeamTokenIDs)},
+		Player2:  models.BattleSide{Address: req.Player2.Address, TeamTokenIDs: nonNilIDs(req.Player2.TeamTokenIDs)},
+		Winner:   winner,
+		FoughtAt: foughtAt,
+	}
+	recorded, created, err := h.battles.RecordBattle(c.Request.Context(), battle)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record battle: " + err.Error()})
+		return
+	}
+
+	status := http.StatusCreated
+	if !created {
+		status = http.StatusOK
+	}
+	c.JSON(status, recorded)
+}
+
+// GetPlayerBattles returns a page of a player's battles, newest first, with their PvP record
+func (h *NadmonHandler) GetPlayerBattles(c *gin.Context) {
+	if h.battles == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Battles are not available"})
+		return
+	}
+
+	address := c.Param("address")
+	if !isValidEthereumAddress(address) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Ethereum address"})
+		return
+	}
+
+	pagination := h.bindPagination(c)
+	page, err := h.battles.GetPlayerBattles(c.Request.Context(), address, pagination.Limit, (pagination.Page-1)*pagination.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch battles: " + err.Error()})
+		return
+	}
+	record, err := h.battles.GetPvPRecord(c.Request.Context(), address)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch PvP record: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, PlayerBattlesResponse{
+		PaginatedResponse: newPaginatedResponse(page.Battles, page.Total, pagination),
+		Record:            record,
+	})
+}
+
+// GetPvPLeaderboard returns players ranked by ELO rating. ?address= adds that player's own
+// record.
+func (h *NadmonHandler) GetPvPLeaderboard(c *gin.Context) {
+	if h.battles == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Battles are not available"})
+		return
+	}
+
+	address := c.Query("address")
+	if address != "" && !isValidEthereumAddress(address) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Ethereum address"})
+		return
+	}
+
+	pagination := h.bindPagination(c)
+	records, total, err := h.battles.GetPvPLeaderboard(c.Request.Context(), pagination.Limit, (pagination.Page-1)*pagination.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch PvP leaderboard: " + err.Error()})
+		return
+	}
+	var player *models.PvPRecord
+	if address != "" {
+		if player, err = h.battles.GetPvPRecord(c.Request.Context(), address); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch PvP record: " + err.Error()})
+			return
+		}
+	}
+
+	addresses := make([]string, 0, len(records)+1)
+	for _, record := range records {
+		addresses = append(addresses, record.Address)
+	}
+	if player != nil {
+		addresses = append(addresses, player.Address)
+	}
+	profiles := h.displayProfiles(c, addresses)
+	names := h.displayNames(addresses)
+	for i := range records {
+		records[i].Display = withDisplay(profiles, records[i].Address)
+		records[i].DisplayName = names[strings.ToLower(records[i].Address)]
+	}
+	if player != nil {
+		player.Display = withDisplay(profiles, player.Address)
+		player.DisplayName = names[strings.ToLower(player.Address)]
+	}
+
+	c.JSON(http.StatusOK, PvPLeaderboardResponse{
+		PaginatedResponse: newPaginatedResponse(records, total, pagination),
+		Player:            player,
+	})
+}
+
+// nonNilIDs returns ids, or an empty slice when it is nil, so it is stored and served as []
+func nonNilIDs(ids []int64) []int64 {
+	if ids == nil {
+		return []int64{}
+	}
+	return ids
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"nadmon-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ChainHandler struct {
+	chains       map[string]repository.Store
+	defaultChain string
+}
+
+// NewChainHandler creates a handler serving the chain registry. chains maps chain IDs to the
+// stores of their Envio databases, including the default chain.
+func NewChainHandler(chains map[string]repository.Store, defaultChain string) *ChainHandler {
+	return &ChainHandler{chains: chains, defaultChain: defaultChain}
+}
+
+// Resolve is middleware that looks up the :chain route parameter and makes its store the one
+// handlers read from, answering 404 for unknown chains
+func (h *ChainHandler) Resolve() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !h.use(c, c.Param("chain")) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Unknown chain"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// Query is middleware that reads from the chain named in the optional ?chain= parameter,
+// answering 400 for unknown chains
+func (h *ChainHandler) Query() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		chain := c.Query("chain")
+		if chain != "" && !h.use(c, chain) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid chain, expected one of " + strings.Join(h.ids(), ", ")})
+			return
+		}
+		c.Next()
+	}
+}
+
+// Valid reports whether chain is a configured chain ID
+func (h *ChainHandler) Valid(chain string) bool {
+	_, ok := h.chains[chain]
+	return ok
+}
+
+// use makes chain the one c reads from, or returns false when it isn't configured
+func (h *ChainHandler) use(c *gin.Context, chain string) bool {
+	store, ok := h.chains[chain]
+	if !ok {
+		return false
+	}
+	// The default chain is read from the handlers' own store, with its caches and collections
+	if chain != h.defaultChain {
+		c.Set(CollectionKey, store)
+	}
+	return true
+}
+
+// ids returns the configured chain IDs in numeric order
+func (h *ChainHandler) ids() []string {
+	ids := make([]string, 0, len(h.chains))
+	for id := range h.chains {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		if len(ids[i]) != len(ids[j]) {
+			return len(ids[i]) < len(ids[j])
+		}
+		return ids[i] < ids[j]
+	})
+	return ids
+}
+
+// GetChains lists the configured chains
+func (h *ChainHandler) GetChains(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"chains":  h.ids(),
+		"default": h.defaultChain,
+	})
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"nadmon-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CollectionKey is the context key holding the Store of the collection named in the route
+const CollectionKey = "collection.store"
+
+type CollectionHandler struct {
+	collections       map[string]repository.Store
+	defaultCollection string
+}
+
+// NewCollectionHandler creates a handler serving the collection registry. collections maps
+// collection names to their stores, including the default collection.
+func NewCollectionHandler(collections map[string]repository.Store, defaultCollection string) *CollectionHandler {
+	return &CollectionHandler{collections: collections, defaultCollection: defaultCollection}
+}
+
+// Resolve is middleware that looks up the :collection route parameter and makes its store
+// the one handlers read from, answering 404 for unknown collections. Collections are only
+// served on the default chain.
+func (h *CollectionHandler) Resolve() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, otherChain := c.Get(CollectionKey); otherChain {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Collections are only served on the default chain"})
+			return
+		}
+		store, ok := h.collections[strings.ToLower(c.Param("collection"))]
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "Unknown collection"})
+			return
+		}
+		c.Set(CollectionKey, store)
+		c.Next()
+	}
+}
+
+// GetCollections lists the configured collections
+func (h *CollectionHandler) GetCollections(c *gin.Context) {
+	names := make([]string, 0, len(h.collections))
+	for name := range h.collections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	c.JSON(http.StatusOK, gin.H{
+		"collections": names,
+		"default":     h.defaultCollection,
+	})
+}
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"nadmon-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CompareNFTs puts 2 to 5 Nadmons (?ids=12,87) side by side: stats, deltas against the first
+// one, rarity ranks and what their evolution and fusion histories have in common
+func (h *NadmonHandler) CompareNFTs(c *gin.Context) {
+	tokenIDs, ok := parseCompareIDs(c.Query("ids"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid ids, expected %d to %d distinct token IDs", models.MinCompared, models.MaxCompared)})
+		return
+	}
+
+	ctx := c.Request.Context()
+	nadmons, err := h.store(c).GetNadmonsByIDs(ctx, tokenIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch NFTs: " + err.Error()})
+		return
+	}
+
+	// Keep the requested order: the first token is the base of the deltas
+	byID := make(map[int64]models.Nadmon, len(nadmons))
+	for _, nadmon := range nadmons {
+		byID[nadmon.TokenID] = nadmon
+	}
+	ordered := make([]models.Nadmon, 0, len(tokenIDs))
+	var missing []string
+	for _, id := range tokenIDs {
+		nadmon, found := byID[id]
+		if !found {
+			missing = append(missing, strconv.FormatInt(id, 10))
+			continue
+		}
+		ordered = append(ordered, nadmon)
+	}
+	if len(missing) > 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "NFTs not found or burned: " + strings.Join(missing, ", ")})
+		return
+	}
+
+	histories := make(map[int64][]models.StatsChange, len(ordered))
+	ranks := make(map[int64]*models.NFTRarity, len(ordered))
+	for _, nadmon := range ordered {
+		history, err := h.store(c).GetNadmonHistory(ctx, nadmon.TokenID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch NFT history: " + err.Error()})
+			return
+		}
+		histories[nadmon.TokenID] = history
+
+		// Ranks are optional: without the rarity store they are left out
+		if h.rarity != nil {
+			rank, err := h.rarity.GetNFTRarity(ctx, nadmon.TokenID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch rarity rank: " + err.Error()})
+				return
+			}
+			ranks[nadmon.TokenID] = rank
+		}
+	}
+
+	c.JSON(http.StatusOK, models.NewNadmonComparison(ordered, histories, ranks))
+}
+
+// parseCompareIDs parses a comma-separated list of MinCompared to MaxCompared distinct token IDs
+func parseCompareIDs(raw string) ([]int64, bool) {
+	if raw == "" {
+		return nil, false
+	}
+
+	parts := strings.Split(raw, ",")
+	if len(parts) > models.MaxCompared {
+		return nil, false
+	}
+	seen := make(map[int64]bool, len(parts))
+	tokenIDs := make([]int64, 0, len(parts))
+	for _, part := range parts {
+		id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil || id < 0 || seen[id] {
+			return nil, false
+		}
+		seen[id] = true
+		tokenIDs = append(tokenIDs, id)
+	}
+	return tokenIDs, len(tokenIDs) >= models.MinCompared
+}
@@ -0,0 +1,28 @@
+package handlers
+
+import (
+	"net/http"
+
+	"nadmon-backend/internal/openapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DocsHandler struct{}
+
+// NewDocsHandler creates a new handler serving the OpenAPI spec and Swagger UI
+func NewDocsHandler() *DocsHandler {
+	return &DocsHandler{}
+}
+
+// GetSpec returns the OpenAPI 3 document describing the API
+func (h *DocsHandler) GetSpec(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.Data(http.StatusOK, "application/json; charset=utf-8", openapi.Spec())
+}
+
+// GetUI returns the Swagger UI page rendering the OpenAPI spec
+func (h *DocsHandler) GetUI(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.Data(http.StatusOK, "text/html; charset=utf-8", openapi.UI())
+}
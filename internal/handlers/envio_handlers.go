@@ -1,28 +1,132 @@
 package handlers
 
 import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"nadmon-backend/internal/auth"
+	"nadmon-backend/internal/etag"
+	"nadmon-backend/internal/ethaddr"
+	"nadmon-backend/internal/models"
 	"nadmon-backend/internal/repository"
 
 	"github.com/gin-gonic/gin"
 )
 
 type NadmonHandler struct {
-	repo *repository.NadmonRepository
+	repo repository.Store
+
+	// Inventories with more Nadmons than this are streamed instead of buffered (0 never streams)
+	streamThreshold int
+
+	limits Limits
+
+	// profiles holds players' display profiles; nil leaves them out of responses
+	profiles repository.ProfileStore
+
+	// teams holds players' saved squads; nil disables the team endpoints
+	teams repository.TeamStore
+
+	// favorites holds the Nadmons players starred; nil disables the favorite endpoints
+	favorites repository.FavoriteStore
+
+	// trades holds the trade offers players negotiate; nil disables the trade endpoints
+	trades repository.TradeStore
+
+	// notifier tells players about trade offers; nil sends nothing
+	notifier Notifier
+
+	// rarity holds precomputed NFT rarity ranks; nil disables the rank endpoints
+	rarity repository.RarityStore
+
+	// leaderboard holds the collector leaderboard snapshot; nil serves the collector
+	// leaderboard live and disables player ranks
+	leaderboard repository.LeaderboardStore
+
+	// playerStats holds per-player aggregates kept up to date by the event pipeline; nil
+	// computes player stats per request
+	playerStats repository.PlayerStatsStore
+
+	// battles holds off-chain battle results and PvP ratings; nil disables the battle endpoints
+	battles repository.BattleStore
+
+	// names resolves name service names of addresses; nil leaves them out of responses and
+	// disables the resolve endpoint
+	names NameService
+}
+
+// Limits caps the size of requests and pages served by the handlers
+type Limits struct {
+	MaxBatchIDs      int // token IDs accepted by one batch lookup
+	DefaultPageSize  int // page size when ?limit= is missing or out of range
+	MaxPageSize      int // largest ?limit= of paginated and top-N endpoints
+	MaxTeamSize      int // Nadmons in one saved team
+	MaxTeams         int // saved teams per player
+	MaxFavorites     int // starred Nadmons per player
+	MaxTradeSize     int // Nadmons on each side of a trade offer
+	MaxPendingTrades int // pending trade offers per proposer
+}
+
+// DefaultLimits returns the limits used unless SetLimits overrides them
+func DefaultLimits() Limits {
+	return Limits{MaxBatchIDs: 500, DefaultPageSize: 20, MaxPageSize: 100, MaxTeamSize: 6, MaxTeams: 20, MaxFavorites: 200, MaxTradeSize: 10, MaxPendingTrades: 20}
+}
+
+// NewNadmonHandler creates a new handler with a storage backend
+func NewNadmonHandler(repo repository.Store) *NadmonHandler {
+	return &NadmonHandler{repo: repo, limits: DefaultLimits()}
+}
+
+// SetLimits replaces the default request and page size limits; values below 1 keep their
+// default, and the default page size never exceeds the largest one
+func (h *NadmonHandler) SetLimits(limits Limits) {
+	defaults := DefaultLimits()
+	if limits.MaxBatchIDs < 1 {
+		limits.MaxBatchIDs = defaults.MaxBatchIDs
+	}
+	if limits.MaxPageSize < 1 {
+		limits.MaxPageSize = defaults.MaxPageSize
+	}
+	if limits.DefaultPageSize < 1 {
+		limits.DefaultPageSize = defaults.DefaultPageSize
+	}
+	if limits.MaxTeamSize < 1 {
+		limits.MaxTeamSize = defaults.MaxTeamSize
+	}
+	if limits.MaxTeams < 1 {
+		limits.MaxTeams = defaults.MaxTeams
+	}
+	if limits.MaxFavorites < 1 {
+		limits.MaxFavorites = defaults.MaxFavorites
+	}
+	if limits.MaxTradeSize < 1 {
+		limits.MaxTradeSize = defaults.MaxTradeSize
+	}
+	if limits.MaxPendingTrades < 1 {
+		limits.MaxPendingTrades = defaults.MaxPendingTrades
+	}
+	if limits.DefaultPageSize > limits.MaxPageSize {
+		limits.DefaultPageSize = limits.MaxPageSize
+	}
+	h.limits = limits
 }
 
-// NewNadmonHandler creates a new handler with repository
-func NewNadmonHandler(repo *repository.NadmonRepository) *NadmonHandler {
-	return &NadmonHandler{repo: repo}
+// SetInventoryStreamThreshold streams inventories larger than n Nadmons as chunked JSON
+func (h *NadmonHandler) SetInventoryStreamThreshold(n int) {
+	h.streamThreshold = n
 }
 
 // PaginationQuery represents pagination parameters
 type PaginationQuery struct {
 	Page  int `form:"page,default=1"`
-	Limit int `form:"limit,default=20"`
+	Limit int `form:"limit"`
 }
 
 // SearchQuery represents search parameters
@@ -31,9 +135,29 @@ type SearchQuery struct {
 	Rarity     string `form:"rarity"`
 	Type       string `form:"type"`
 	Evo        int    `form:"evo"`
-	MinHP      int    `form:"min_hp"`
-	MinAttack  int    `form:"min_attack"`
-	MinDefense int    `form:"min_defense"`
+	MinHP      *int   `form:"min_hp"`
+	MaxHP      *int   `form:"max_hp"`
+	MinAttack  *int   `form:"min_attack"`
+	MaxAttack  *int   `form:"max_attack"`
+	MinDefense *int   `form:"min_defense"`
+	MaxDefense *int   `form:"max_defense"`
+	MinCrit    *int   `form:"min_crit"`
+	MaxCrit    *int   `form:"max_crit"`
+	MinFusion  *int   `form:"min_fusion"`
+	MaxFusion  *int   `form:"max_fusion"`
+	SortBy     string `form:"sort_by"`
+	Order      string `form:"order"`
+}
+
+// statRanges maps the optional stat bounds to their repository filter keys
+func (q SearchQuery) statRanges() map[string]*int {
+	return map[string]*int{
+		"min_hp": q.MinHP, "max_hp": q.MaxHP,
+		"min_attack": q.MinAttack, "max_attack": q.MaxAttack,
+		"min_defense": q.MinDefense, "max_defense": q.MaxDefense,
+		"min_crit": q.MinCrit, "max_crit": q.MaxCrit,
+		"min_fusion": q.MinFusion, "max_fusion": q.MaxFusion,
+	}
 }
 
 // PaginatedResponse represents a paginated API response
@@ -47,7 +171,8 @@ type PaginatedResponse struct {
 	HasPrev    bool        `json:"hasPrev"`
 }
 
-// GetInventory returns NFT inventory for an address
+// GetInventory returns NFT inventory for an address. sort (with order) sorts it in the
+// database; fields=id,hp,attack trims each NFT to the listed keys
 func (h *NadmonHandler) GetInventory(c *gin.Context) {
 	address := c.Param("address")
 	if address == "" {
@@ -61,17 +186,56 @@ func (h *NadmonHandler) GetInventory(c *gin.Context) {
 		return
 	}
 
+	fields, err := parseFields(c.Query("fields"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid fields, " + err.Error()})
+		return
+	}
+
+	// Sorting, e.g. sort=attack&order=desc, is done by the search query
+	sorting := make(map[string]interface{})
+	if sortBy := c.Query("sort"); sortBy != "" {
+		if !isSearchSortField(sortBy) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort, expected one of: " + strings.Join(repository.SearchSortFields, ", ")})
+			return
+		}
+		sorting["sort_by"] = sortBy
+	}
+	if order := strings.ToLower(c.Query("order")); order != "" {
+		if order != "asc" && order != "desc" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order, expected asc or desc"})
+			return
+		}
+		sorting["order"] = order
+	}
+
 	// Get player's NFTs
-	nadmons, err := h.repo.GetPlayerNadmons(address)
+	var nadmons []models.Nadmon
+	if len(sorting) > 0 {
+		nadmons, err = h.store(c).SearchNadmons(c.Request.Context(), address, sorting)
+	} else {
+		nadmons, err = h.store(c).GetPlayerNadmons(c.Request.Context(), address)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch NFTs: " + err.Error()})
 		return
 	}
 
+	// Nadmons the player starred are flagged favorited
+	var favorited map[int64]bool
+	if hasField(fields, "favorited") {
+		favorited = h.favoritedTokens(c, address)
+	}
+
+	if h.streamThreshold > 0 && len(nadmons) > h.streamThreshold {
+		streamInventory(c, nadmons, fields, favorited)
+		return
+	}
+
 	// Convert to frontend format
 	nfts := make([]map[string]interface{}, len(nadmons))
-	for i, nadmon := range nadmons {
-		nfts[i] = nadmon.ToFrontendFormat()
+	for i := range nadmons {
+		nfts[i] = inventoryItem(&nadmons[i], fields, favorited)
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -80,6 +244,96 @@ func (h *NadmonHandler) GetInventory(c *gin.Context) {
 	})
 }
 
+// streamInventory writes the same body as GetInventory one Nadmon at a time in 32 KiB chunks
+// (chunked transfer encoding), so whale inventories are never held in memory as a whole
+// response. The response has no ETag, as it would need the full body.
+func streamInventory(c *gin.Context, nadmons []models.Nadmon, fields []string, favorited map[int64]bool) {
+	etag.Skip(c)
+	c.Header("Content-Type", "application/json; charset=utf-8")
+	c.Status(http.StatusOK)
+
+	w := bufio.NewWriterSize(c.Writer, 32<<10)
+	w.WriteString(`{"data":[`)
+	for i := range nadmons {
+		if i > 0 {
+			w.WriteByte(',')
+		}
+		item, err := json.Marshal(inventoryItem(&nadmons[i], fields, favorited))
+		if err != nil {
+			// Headers are already sent; cut the body short so clients see invalid JSON
+			c.Error(err)
+			w.Flush()
+			return
+		}
+		w.Write(item)
+	}
+	w.WriteString(`],"total":` + strconv.Itoa(len(nadmons)) + `}`)
+	w.Flush()
+}
+
+// parseFields reads a comma-separated selection of frontend-format keys and favorited; nil
+// selects them all
+func parseFields(raw string) ([]string, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	known := (&models.Nadmon{}).ToFrontendFormat()
+	known["favorited"] = false
+	var fields []string
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if _, ok := known[field]; !ok {
+			names := make([]string, 0, len(known))
+			for name := range known {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			return nil, fmt.Errorf("unknown field %q, expected any of: %s", field, strings.Join(names, ", "))
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// frontendFields converts a Nadmon to frontend format, keeping only fields when set
+func frontendFields(n *models.Nadmon, fields []string) map[string]interface{} {
+	nft := n.ToFrontendFormat()
+	if fields == nil {
+		return nft
+	}
+	selected := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		selected[field] = nft[field]
+	}
+	return selected
+}
+
+// hasField reports whether fields, as parsed by parseFields, selects field
+func hasField(fields []string, field string) bool {
+	if fields == nil {
+		return true
+	}
+	for _, selected := range fields {
+		if selected == field {
+			return true
+		}
+	}
+	return false
+}
+
+// inventoryItem converts a held Nadmon to frontend format with its favorited flag, which is
+// left out when favorited is nil
+func inventoryItem(n *models.Nadmon, fields []string, favorited map[int64]bool) map[string]interface{} {
+	item := frontendFields(n, fields)
+	if favorited != nil {
+		item["favorited"] = favorited[n.TokenID]
+	} else {
+		delete(item, "favorited")
+	}
+	return item
+}
+
 // SearchNFTs searches NFTs with filters
 func (h *NadmonHandler) SearchNFTs(c *gin.Context) {
 	address := c.Param("address")
@@ -101,23 +355,13 @@ func (h *NadmonHandler) SearchNFTs(c *gin.Context) {
 		return
 	}
 
-	// Build filters map
-	filters := make(map[string]interface{})
-	if search.Element != "" {
-		filters["element"] = search.Element
-	}
-	if search.Rarity != "" {
-		filters["rarity"] = search.Rarity
-	}
-	if search.Type != "" {
-		filters["type"] = search.Type
-	}
-	if search.Evo > 0 {
-		filters["evo"] = search.Evo
+	filters, ok := searchFilters(c, search)
+	if !ok {
+		return
 	}
 
 	// Search NFTs
-	nadmons, err := h.repo.SearchNadmons(address, filters)
+	nadmons, err := h.store(c).SearchNadmons(c.Request.Context(), address, filters)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search NFTs: " + err.Error()})
 		return
@@ -135,6 +379,59 @@ func (h *NadmonHandler) SearchNFTs(c *gin.Context) {
 	})
 }
 
+// searchFilters builds the repository filters of a search, answering 400 and returning
+// false when the sort is invalid
+func searchFilters(c *gin.Context, search SearchQuery) (map[string]interface{}, bool) {
+	// Build filters map
+	filters := make(map[string]interface{})
+	if search.Element != "" {
+		filters["element"] = search.Element
+	}
+	if search.Rarity != "" {
+		filters["rarity"] = search.Rarity
+	}
+	if search.Type != "" {
+		filters["type"] = search.Type
+	}
+	if search.Evo > 0 {
+		filters["evo"] = search.Evo
+	}
+	for key, value := range search.statRanges() {
+		if value != nil {
+			filters[key] = *value
+		}
+	}
+
+	// Sorting, e.g. sort_by=attack&order=desc
+	if search.SortBy != "" {
+		if !isSearchSortField(search.SortBy) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid sort_by, expected one of: " + strings.Join(repository.SearchSortFields, ", ")})
+			return nil, false
+		}
+		filters["sort_by"] = search.SortBy
+	}
+	if search.Order != "" {
+		order := strings.ToLower(search.Order)
+		if order != "asc" && order != "desc" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order, expected asc or desc"})
+			return nil, false
+		}
+		filters["order"] = order
+	}
+
+	return filters, true
+}
+
+// isSearchSortField reports whether field is accepted as sort_by
+func isSearchSortField(field string) bool {
+	for _, allowed := range repository.SearchSortFields {
+		if field == allowed {
+			return true
+		}
+	}
+	return false
+}
+
 // GetNFT returns a single NFT by token ID with current stats and evolution history
 func (h *NadmonHandler) GetNFT(c *gin.Context) {
 	tokenIDStr := c.Param("tokenId")
@@ -145,26 +442,49 @@ func (h *NadmonHandler) GetNFT(c *gin.Context) {
 	}
 
 	// Get NFT details
-	nadmon, err := h.repo.GetSingleNadmon(tokenID)
+	nadmon, err := h.store(c).GetSingleNadmon(c.Request.Context(), tokenID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch NFT: " + err.Error()})
 		return
 	}
 
-	if nadmon == nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "NFT not found"})
-		return
-	}
-
 	// Get evolution history for this NFT
-	history, err := h.repo.GetNadmonHistory(tokenID)
+	history, err := h.store(c).GetNadmonHistory(c.Request.Context(), tokenID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch NFT history: " + err.Error()})
 		return
 	}
 
+	if nadmon == nil {
+		// Distinguish tokens consumed in a fusion (or otherwise burned) from tokens that never existed
+		statuses, err := h.store(c).GetNadmonStatuses(c.Request.Context(), []int64{tokenID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch NFT status: " + err.Error()})
+			return
+		}
+
+		status := statuses[tokenID]
+		if status.Status == models.StatusBurned {
+			c.JSON(http.StatusGone, gin.H{
+				"error":    "NFT was burned",
+				"burned":   true,
+				"status":   status.Status,
+				"burnedAt": status.BurnedAt,
+				"history":  history,
+			})
+			return
+		}
+
+		c.JSON(http.StatusNotFound, gin.H{"error": "NFT not found", "status": status.Status})
+		return
+	}
+
+	nft := nadmon.ToFrontendFormat()
+	nft["status"] = models.StatusActive
+	nft["burned"] = false
+
 	response := gin.H{
-		"nft":     nadmon.ToFrontendFormat(),
+		"nft":     nft,
 		"history": history,
 	}
 
@@ -181,7 +501,7 @@ func (h *NadmonHandler) GetPackDetails(c *gin.Context) {
 	}
 
 	// Get pack information
-	pack, err := h.repo.GetPackByID(packID)
+	pack, err := h.store(c).GetPackByID(c.Request.Context(), packID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pack: " + err.Error()})
 		return
@@ -193,7 +513,7 @@ func (h *NadmonHandler) GetPackDetails(c *gin.Context) {
 	}
 
 	// Get all NFTs in this pack
-	nadmons, err := h.repo.GetNadmonsByIDs(pack.TokenIDs)
+	nadmons, err := h.store(c).GetNadmonsByIDs(c.Request.Context(), pack.TokenIDs)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pack NFTs: " + err.Error()})
 		return
@@ -227,8 +547,12 @@ func (h *NadmonHandler) GetNFTsByIDs(c *gin.Context) {
 		return
 	}
 
-	// Split and parse token IDs
+	// Split and parse token IDs, limited to prevent abuse
 	idStrings := strings.Split(tokenIDsStr, ",")
+	if len(idStrings) > h.limits.MaxBatchIDs {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Too many token IDs (max %d)", h.limits.MaxBatchIDs)})
+		return
+	}
 	tokenIDs := make([]int64, 0, len(idStrings))
 	
 	for _, idStr := range idStrings {
@@ -240,14 +564,8 @@ func (h *NadmonHandler) GetNFTsByIDs(c *gin.Context) {
 		tokenIDs = append(tokenIDs, id)
 	}
 
-	// Limit to prevent abuse
-	if len(tokenIDs) > 50 {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Too many token IDs (max 50)"})
-		return
-	}
-
 	// Get NFTs
-	nadmons, err := h.repo.GetNadmonsByIDs(tokenIDs)
+	nadmons, err := h.store(c).GetNadmonsByIDs(c.Request.Context(), tokenIDs)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch NFTs: " + err.Error()})
 		return
@@ -255,17 +573,44 @@ func (h *NadmonHandler) GetNFTsByIDs(c *gin.Context) {
 
 	// Convert to frontend format
 	nfts := make([]map[string]interface{}, len(nadmons))
+	found := make(map[int64]bool, len(nadmons))
 	for i, nadmon := range nadmons {
 		nfts[i] = nadmon.ToFrontendFormat()
+		nfts[i]["status"] = models.StatusActive
+		found[nadmon.TokenID] = true
+	}
+
+	// Report why requested tokens are missing instead of silently dropping them
+	missingIDs := []int64{}
+	for _, id := range tokenIDs {
+		if !found[id] {
+			missingIDs = append(missingIDs, id)
+			found[id] = true
+		}
+	}
+
+	missing := []models.NadmonStatus{}
+	if len(missingIDs) > 0 {
+		statuses, err := h.store(c).GetNadmonStatuses(c.Request.Context(), missingIDs)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch NFT statuses: " + err.Error()})
+			return
+		}
+		for _, id := range missingIDs {
+			missing = append(missing, statuses[id])
+		}
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"data":  nfts,
-		"total": len(nfts),
+		"data":        nfts,
+		"total":       len(nfts),
+		"missing_ids": missingIDs,
+		"missing":     missing,
 	})
 }
 
-// GetPlayerProfile returns complete player profile
+// GetPlayerProfile returns complete player profile; ?include=summary counts the player's
+// Nadmons by rarity and element instead of listing them
 func (h *NadmonHandler) GetPlayerProfile(c *gin.Context) {
 	address := c.Param("address")
 	if !isValidEthereumAddress(address) {
@@ -273,13 +618,31 @@ func (h *NadmonHandler) GetPlayerProfile(c *gin.Context) {
 		return
 	}
 
-	profile, err := h.repo.GetPlayerProfile(address)
+	switch include := c.DefaultQuery("include", "nadmons"); include {
+	case "nadmons":
+	case "summary":
+		summary, err := h.store(c).GetPlayerSummary(c.Request.Context(), address)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch player profile: " + err.Error()})
+			return
+		}
+		// Store results may be shared with other requests, so the display is set on a copy
+		withProfile := *summary
+		withProfile.Display = withDisplay(h.displayProfiles(c, []string{summary.Address}), summary.Address)
+		c.JSON(http.StatusOK, withProfile)
+		return
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid include, expected nadmons or summary"})
+		return
+	}
+
+	profile, err := h.store(c).GetPlayerProfile(c.Request.Context(), address)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch player profile: " + err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, profile)
+	c.JSON(http.StatusOK, h.withDisplayProfiles(c, []models.PlayerProfile{*profile})[0])
 }
 
 // GetPlayerPacks returns player's pack purchase history
@@ -290,7 +653,7 @@ func (h *NadmonHandler) GetPlayerPacks(c *gin.Context) {
 		return
 	}
 
-	packs, err := h.repo.GetPlayerPacks(address)
+	packs, err := h.store(c).GetPlayerPacks(c.Request.Context(), address)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch player packs: " + err.Error()})
 		return
@@ -302,7 +665,247 @@ func (h *NadmonHandler) GetPlayerPacks(c *gin.Context) {
 	})
 }
 
-// GetStats returns player statistics
+// summaryRecentPacks is how many of the newest packs a pack summary carries
+const summaryRecentPacks = 5
+
+// GetPlayerPackSummary returns pack purchase totals per payment type, the first and last
+// purchase and the newest packs, without the full history
+func (h *NadmonHandler) GetPlayerPackSummary(c *gin.Context) {
+	address := c.Param("address")
+	if !isValidEthereumAddress(address) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Ethereum address"})
+		return
+	}
+
+	summary, err := h.store(c).GetPlayerPackSummary(c.Request.Context(), address, summaryRecentPacks)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pack summary: " + err.Error()})
+		return
+	}
+
+	summary.RecentPacks = h.withPackNames(summary.RecentPacks)
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetPlayerDex returns the player's Nadmondex: completion over every species minted so far
+// and the species still missing
+func (h *NadmonHandler) GetPlayerDex(c *gin.Context) {
+	address := c.Param("address")
+	if !isValidEthereumAddress(address) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Ethereum address"})
+		return
+	}
+
+	dex, err := h.store(c).GetPlayerDex(c.Request.Context(), address)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch player dex: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, dex)
+}
+
+// GetFusionCandidates groups a player's Nadmons by species and reports which can fuse now
+// and which can evolve, following the contract's progression rules
+func (h *NadmonHandler) GetFusionCandidates(c *gin.Context) {
+	address := c.Param("address")
+	if !isValidEthereumAddress(address) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Ethereum address"})
+		return
+	}
+
+	nadmons, err := h.store(c).GetPlayerNadmons(c.Request.Context(), address)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch NFTs: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewFusionCandidates(ethaddr.Normalize(address), nadmons))
+}
+
+// bindPagination parses page/limit query parameters, clamping them to the handler's limits
+func (h *NadmonHandler) bindPagination(c *gin.Context) PaginationQuery {
+	var pagination PaginationQuery
+	if err := c.ShouldBindQuery(&pagination); err != nil || pagination.Page < 1 {
+		pagination.Page = 1
+	}
+	if pagination.Limit < 1 || pagination.Limit > h.limits.MaxPageSize {
+		pagination.Limit = h.limits.DefaultPageSize
+	}
+	return pagination
+}
+
+// bindTopLimit parses the limit query parameter of a top-N list, falling back to
+// defaultLimit (within the handler's largest page size) when it is missing or out of range
+func (h *NadmonHandler) bindTopLimit(c *gin.Context, defaultLimit int) int {
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit < 1 || limit > h.limits.MaxPageSize {
+		limit = defaultLimit
+	}
+	if limit > h.limits.MaxPageSize {
+		limit = h.limits.MaxPageSize
+	}
+	return limit
+}
+
+// newPaginatedResponse wraps one page of data with its paging metadata
+func newPaginatedResponse(data interface{}, total int, pagination PaginationQuery) PaginatedResponse {
+	totalPages := (total + pagination.Limit - 1) / pagination.Limit
+	return PaginatedResponse{
+		Data:       data,
+		Total:      total,
+		Page:       pagination.Page,
+		Limit:      pagination.Limit,
+		TotalPages: totalPages,
+		HasNext:    pagination.Page < totalPages,
+		HasPrev:    pagination.Page > 1,
+	}
+}
+
+// GetPlayerTransfers returns NFTs sent and received by a player, newest first
+func (h *NadmonHandler) GetPlayerTransfers(c *gin.Context) {
+	address := c.Param("address")
+	if !isValidEthereumAddress(address) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Ethereum address"})
+		return
+	}
+
+	pagination := h.bindPagination(c)
+	page, err := h.store(c).GetPlayerTransfers(c.Request.Context(), address, pagination.Limit, (pagination.Page-1)*pagination.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch player transfers: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newPaginatedResponse(page.Transfers, page.Total, pagination))
+}
+
+// GetNFTTransfers returns the ownership history (provenance) of an NFT, newest first
+func (h *NadmonHandler) GetNFTTransfers(c *gin.Context) {
+	tokenID, err := strconv.ParseInt(c.Param("tokenId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid token ID"})
+		return
+	}
+
+	pagination := h.bindPagination(c)
+	page, err := h.store(c).GetNadmonTransfers(c.Request.Context(), tokenID, pagination.Limit, (pagination.Page-1)*pagination.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch NFT transfers: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newPaginatedResponse(page.Transfers, page.Total, pagination))
+}
+
+// GetNFTSales returns a page of an NFT's marketplace sales, newest first
+func (h *NadmonHandler) GetNFTSales(c *gin.Context) {
+	tokenID, err := strconv.ParseInt(c.Param("tokenId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid token ID"})
+		return
+	}
+
+	pagination := h.bindPagination(c)
+	page, err := h.store(c).GetNadmonSales(c.Request.Context(), tokenID, pagination.Limit, (pagination.Page-1)*pagination.Limit)
+	if errors.Is(err, repository.ErrMarketplaceUnavailable) {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "Marketplace sales are not indexed"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch NFT sales: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newPaginatedResponse(page.Sales, page.Total, pagination))
+}
+
+// GetFloorStats returns floor price, last sale and volume overall and per rarity and element,
+// optionally limited to the rarity and element query parameters
+func (h *NadmonHandler) GetFloorStats(c *gin.Context) {
+	market, err := h.store(c).GetMarketStats(c.Request.Context(), c.Query("rarity"), c.Query("element"))
+	if errors.Is(err, repository.ErrMarketplaceUnavailable) {
+		c.JSON(http.StatusNotImplemented, gin.H{"error": "Marketplace sales are not indexed"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch floor stats: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, market)
+}
+
+// GetActivity returns the global activity feed (mints, transfers, evolutions and pack
+// purchases), newest first. ?type=mint,pack limits it to the given entry types.
+func (h *NadmonHandler) GetActivity(c *gin.Context) {
+	types, ok := bindActivityTypes(c, "type", models.ActivityTypes)
+	if !ok {
+		return
+	}
+
+	pagination := h.bindPagination(c)
+	page, err := h.store(c).GetActivity(c.Request.Context(), types, pagination.Limit, (pagination.Page-1)*pagination.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch activity: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newPaginatedResponse(h.withActivityNames(page.Activities), page.Total, pagination))
+}
+
+// GetPlayerActivity returns a player's activity feed (mints, transfers in and out, evolutions
+// and pack purchases), newest first. ?types=mint,pack limits it to the given entry types.
+func (h *NadmonHandler) GetPlayerActivity(c *gin.Context) {
+	address := c.Param("address")
+	if !isValidEthereumAddress(address) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Ethereum address"})
+		return
+	}
+
+	types, ok := bindActivityTypes(c, "types", models.PlayerActivityTypes)
+	if !ok {
+		return
+	}
+
+	pagination := h.bindPagination(c)
+	page, err := h.store(c).GetPlayerActivity(c.Request.Context(), address, types, pagination.Limit, (pagination.Page-1)*pagination.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch player activity: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newPaginatedResponse(h.withActivityNames(page.Activities), page.Total, pagination))
+}
+
+// bindActivityTypes reads comma separated entry types from the given query parameter. All
+// known types are returned when it is absent; an unknown type aborts with 400.
+func bindActivityTypes(c *gin.Context, param string, known []string) ([]string, bool) {
+	requested := make(map[string]bool)
+	for _, value := range c.QueryArray(param) {
+		for _, activityType := range strings.Split(value, ",") {
+			if activityType = strings.TrimSpace(strings.ToLower(activityType)); activityType != "" {
+				requested[activityType] = true
+			}
+		}
+	}
+
+	// Keep a canonical order so equivalent filters share cache entries
+	types := make([]string, 0, len(known))
+	for _, activityType := range known {
+		if len(requested) == 0 || requested[activityType] {
+			types = append(types, activityType)
+		}
+		delete(requested, activityType)
+	}
+	if len(requested) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param + ", expected one of: " + strings.Join(known, ", ")})
+		return nil, false
+	}
+	return types, true
+}
+
+// GetStats returns player statistics, from the aggregate table when it has the player
 func (h *NadmonHandler) GetStats(c *gin.Context) {
 	address := c.Param("address")
 	if !isValidEthereumAddress(address) {
@@ -310,8 +913,18 @@ func (h *NadmonHandler) GetStats(c *gin.Context) {
 		return
 	}
 
-	// Get player profile which includes stats
-	profile, err := h.repo.GetPlayerProfile(address)
+	aggregated, err := h.aggregatedStats(c, address)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch player stats: " + err.Error()})
+		return
+	}
+	if aggregated != nil {
+		c.JSON(http.StatusOK, aggregated)
+		return
+	}
+
+	// The summary counts the player's Nadmons without fetching them
+	summary, err := h.store(c).GetPlayerSummary(c.Request.Context(), address)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch player stats: " + err.Error()})
 		return
@@ -319,29 +932,17 @@ func (h *NadmonHandler) GetStats(c *gin.Context) {
 
 	// Calculate additional statistics
 	stats := gin.H{
-		"address":      profile.Address,
-		"totalNFTs":    profile.TotalNFTs,
-		"packsBought":  profile.PacksBought,
-		"lastActivity": profile.LastActive,
+		"address":      summary.Address,
+		"totalNFTs":    summary.TotalNFTs,
+		"packsBought":  summary.PacksBought,
+		"lastActivity": summary.LastActive,
 	}
 
 	// Add rarity and element breakdown
-	if len(profile.Nadmons) > 0 {
-		rarityStats := make(map[string]int)
-		elementStats := make(map[string]int)
-		evolvedCount := 0
-
-		for _, nadmon := range profile.Nadmons {
-			rarityStats[nadmon.Rarity]++
-			elementStats[nadmon.Element]++
-			if nadmon.Evo > 1 {
-				evolvedCount++
-			}
-		}
-
-		stats["rarityStats"] = rarityStats
-		stats["elementStats"] = elementStats
-		stats["evolvedNFTs"] = evolvedCount
+	if summary.TotalNFTs > 0 {
+		stats["rarityStats"] = summary.RarityCounts
+		stats["elementStats"] = summary.ElementCounts
+		stats["evolvedNFTs"] = summary.EvolvedNFTs
 	}
 
 	c.JSON(http.StatusOK, stats)
@@ -349,47 +950,86 @@ func (h *NadmonHandler) GetStats(c *gin.Context) {
 
 // GetRecentPacks returns recent pack purchases across all players
 func (h *NadmonHandler) GetRecentPacks(c *gin.Context) {
-	limitStr := c.DefaultQuery("limit", "10")
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit < 1 || limit > 100 {
-		limit = 10
-	}
+	limit := h.bindTopLimit(c, 10)
 
-	packs, err := h.repo.GetRecentPacks(limit)
+	packs, err := h.store(c).GetRecentPacks(c.Request.Context(), limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch recent packs: " + err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"data":  packs,
+		"data":  h.withPackNames(packs),
 		"total": len(packs),
 	})
 }
 
-// GetLeaderboard returns top collectors
+// GetLeaderboard returns top collectors, from the leaderboard snapshot for the default collection
 func (h *NadmonHandler) GetLeaderboard(c *gin.Context) {
-	limitStr := c.DefaultQuery("limit", "10")
-	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit < 1 || limit > 100 {
-		limit = 10
-	}
+	limit := h.bindTopLimit(c, 10)
 
-	collectors, err := h.repo.GetTopCollectors(limit)
+	var collectors []models.PlayerProfile
+	var err error
+	if _, scoped := c.Get(CollectionKey); h.leaderboard != nil && !scoped {
+		collectors, err = h.leaderboard.GetTopCollectors(c.Request.Context(), limit)
+	} else {
+		collectors, err = h.store(c).GetTopCollectors(c.Request.Context(), limit)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch leaderboard: " + err.Error()})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"data":  collectors,
+		"data":  h.withDisplayProfiles(c, collectors),
 		"total": len(collectors),
 	})
 }
 
+// LeaderboardResponse is a paginated leaderboard with the requesting player's own entry
+type LeaderboardResponse struct {
+	PaginatedResponse
+	Type   string                   `json:"type"`
+	Player *models.LeaderboardEntry `json:"player"`
+}
+
+// GetLeaderboardByType returns a paginated leaderboard (evolutions, fusion, packs or
+// rarity_score). ?address= adds that player's own rank.
+func (h *NadmonHandler) GetLeaderboardByType(c *gin.Context) {
+	kind := c.Param("type")
+	valid := false
+	for _, leaderboardType := range models.LeaderboardTypes {
+		valid = valid || kind == leaderboardType
+	}
+	if !valid {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Unknown leaderboard, expected one of: collectors, " + strings.Join(models.LeaderboardTypes, ", ")})
+		return
+	}
+
+	address := c.Query("address")
+	if address != "" && !isValidEthereumAddress(address) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Ethereum address"})
+		return
+	}
+
+	pagination := h.bindPagination(c)
+	leaderboard, err := h.store(c).GetLeaderboard(c.Request.Context(), kind, address, pagination.Limit, (pagination.Page-1)*pagination.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch leaderboard: " + err.Error()})
+		return
+	}
+
+	leaderboard = h.withLeaderboardDisplay(c, leaderboard)
+	c.JSON(http.StatusOK, LeaderboardResponse{
+		PaginatedResponse: newPaginatedResponse(leaderboard.Entries, leaderboard.TotalPlayers, pagination),
+		Type:              leaderboard.Type,
+		Player:            leaderboard.Player,
+	})
+}
+
 // GetGameStats returns overall game statistics
 func (h *NadmonHandler) GetGameStats(c *gin.Context) {
-	stats, err := h.repo.GetGameStats()
+	stats, err := h.store(c).GetGameStats(c.Request.Context())
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch game stats: " + err.Error()})
 		return
@@ -398,9 +1038,232 @@ func (h *NadmonHandler) GetGameStats(c *gin.Context) {
 	c.JSON(http.StatusOK, stats)
 }
 
+// GetPackDistribution returns a histogram of packs bought per player
+func (h *NadmonHandler) GetPackDistribution(c *gin.Context) {
+	distribution, err := h.store(c).GetPackDistribution(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pack distribution: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, distribution)
+}
+
+// GetOwnershipConcentration returns top-holder supply shares and the Gini coefficient
+func (h *NadmonHandler) GetOwnershipConcentration(c *gin.Context) {
+	concentration, err := h.store(c).GetOwnershipConcentration(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch ownership concentration: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, concentration)
+}
+
+// GetSupplyStats returns minted, burned and circulating token counts per rarity and element
+func (h *NadmonHandler) GetSupplyStats(c *gin.Context) {
+	supply, err := h.store(c).GetSupplyStats(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch supply stats: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, supply)
+}
+
+// GetElementStats returns the circulating count, share, average stats and evolution rate of
+// each element
+func (h *NadmonHandler) GetElementStats(c *gin.Context) {
+	h.getTraitStats(c, models.DimensionElement)
+}
+
+// GetRarityStats returns the circulating count, share, average stats and evolution rate of
+// each rarity
+func (h *NadmonHandler) GetRarityStats(c *gin.Context) {
+	h.getTraitStats(c, models.DimensionRarity)
+}
+
+func (h *NadmonHandler) getTraitStats(c *gin.Context, dimension string) {
+	breakdown, err := h.store(c).GetTraitStats(c.Request.Context(), dimension)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch " + dimension + " stats: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, breakdown)
+}
+
+// maxTimeSeriesBuckets caps how many buckets a time-series request may span
+const maxTimeSeriesBuckets = 1000
+
+// defaultTimeSeriesBuckets is the span returned when from is omitted
+const defaultTimeSeriesBuckets = 30
+
+// GetTimeSeries returns mints, packs, transfers or active players per hour or day for charts
+func (h *NadmonHandler) GetTimeSeries(c *gin.Context) {
+	metric := c.Query("metric")
+	valid := false
+	for _, m := range models.TimeSeriesMetrics {
+		valid = valid || metric == m
+	}
+	if !valid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid metric, expected one of: " + strings.Join(models.TimeSeriesMetrics, ", ")})
+		return
+	}
+
+	interval := c.DefaultQuery("interval", "day")
+	step, ok := models.TimeSeriesIntervals[interval]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid interval, expected hour or day"})
+		return
+	}
+
+	to := time.Now().UTC()
+	if value := c.Query("to"); value != "" {
+		parsed, err := parseTimeParam(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to, expected RFC 3339 or YYYY-MM-DD"})
+			return
+		}
+		to = parsed
+	}
+	from := to.Add(-defaultTimeSeriesBuckets * step).Truncate(step)
+	if value := c.Query("from"); value != "" {
+		parsed, err := parseTimeParam(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from, expected RFC 3339 or YYYY-MM-DD"})
+			return
+		}
+		from = parsed
+	}
+	if !from.Before(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be before to"})
+		return
+	}
+	if to.Sub(from.Truncate(step)) > maxTimeSeriesBuckets*step {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Time range too large, at most " + strconv.Itoa(maxTimeSeriesBuckets) + " " + interval + "s"})
+		return
+	}
+
+	points, err := h.store(c).GetEventTimeSeries(c.Request.Context(), metric, interval, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch time series: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"metric":   metric,
+		"interval": interval,
+		"from":     from,
+		"to":       to,
+		"data":     fillTimeSeries(points, step, from, to),
+	})
+}
+
+// GetPackOdds returns empirical rarity and element drop rates per payment type, optionally
+// limited to packs bought between from and to
+func (h *NadmonHandler) GetPackOdds(c *gin.Context) {
+	var window [2]time.Time
+	for i, name := range []string{"from", "to"} {
+		value := c.Query(name)
+		if value == "" {
+			continue
+		}
+		parsed, err := parseTimeParam(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + name + ", expected RFC 3339 or YYYY-MM-DD"})
+			return
+		}
+		window[i] = parsed
+	}
+	from, to := window[0], window[1]
+	if !from.IsZero() && !to.IsZero() && !from.Before(to) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must be before to"})
+		return
+	}
+
+	odds, err := h.store(c).GetPackOdds(c.Request.Context(), from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pack odds: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, odds)
+}
+
+// parseTimeParam parses an RFC 3339 timestamp or a YYYY-MM-DD date (midnight UTC)
+func parseTimeParam(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	return time.Parse("2006-01-02", value)
+}
+
+// fillTimeSeries returns one point per bucket between from and to, so charts get zeros for
+// buckets without events
+func fillTimeSeries(points []models.TimeSeriesPoint, step time.Duration, from, to time.Time) []models.TimeSeriesPoint {
+	counts := make(map[int64]int64, len(points))
+	for _, point := range points {
+		counts[point.Bucket.Unix()] = point.Count
+	}
+
+	filled := []models.TimeSeriesPoint{}
+	for bucket := from.Truncate(step); bucket.Before(to); bucket = bucket.Add(step) {
+		filled = append(filled, models.TimeSeriesPoint{Bucket: bucket, Count: counts[bucket.Unix()]})
+	}
+	return filled
+}
+
+// MaxSearchSuggestions caps the suggestions returned by one search suggestion request
+const MaxSearchSuggestions = 50
+
+// GetSearchSuggestions returns filter values (types, elements, rarities) matching the q parameter
+func (h *NadmonHandler) GetSearchSuggestions(c *gin.Context) {
+	limitStr := c.DefaultQuery("limit", "10")
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit < 1 || limit > MaxSearchSuggestions {
+		limit = 10
+	}
+
+	suggestions, err := h.store(c).GetSearchSuggestions(c.Request.Context(), c.Query("q"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch search suggestions: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  suggestions,
+		"total": len(suggestions),
+	})
+}
+
 // Helper functions
 
-// isValidEthereumAddress validates Ethereum address format
+// storeFor returns repo, the store of the collection or chain the request names, or the store
+// either wraps when the request asks to skip caches (see noCache)
+func storeFor(c *gin.Context, repo repository.Store) repository.Store {
+	if collection, ok := c.Get(CollectionKey); ok {
+		repo = collection.(repository.Store)
+	}
+	if bypasser, ok := repo.(repository.Bypasser); ok && noCache(c) {
+		return bypasser.Bypass()
+	}
+	return repo
+}
+
+// noCache reports whether the request skips caches with ?nocache=1 (for debugging stale data).
+// Only the admin API key may ask, so clients can't send their load past the caches.
+func noCache(c *gin.Context) bool {
+	return c.Query("nocache") != "" && c.GetBool(auth.AdminKey)
+}
+
+// store returns the storage backend to use for this request
+func (h *NadmonHandler) store(c *gin.Context) repository.Store {
+	return storeFor(c, h.repo)
+}
+
+// isValidEthereumAddress validates Ethereum address format, including the EIP-55 checksum of
+// mixed-case addresses
 func isValidEthereumAddress(address string) bool {
-	return len(address) == 42 && strings.HasPrefix(address, "0x")
+	return ethaddr.Valid(address)
 }
\ No newline at end of file
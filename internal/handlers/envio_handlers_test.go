@@ -0,0 +1,1764 @@
+//go:build integration
+
+package handlers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"nadmon-backend/internal/apiversion"
+	"nadmon-backend/internal/audit"
+	"nadmon-backend/internal/auth"
+	"nadmon-backend/internal/compress"
+	"nadmon-backend/internal/etag"
+	"nadmon-backend/internal/fixtures"
+	"nadmon-backend/internal/gamedata"
+	"nadmon-backend/internal/images"
+	"nadmon-backend/internal/logging"
+	"nadmon-backend/internal/models"
+	"nadmon-backend/internal/names"
+	"nadmon-backend/internal/repository"
+	"nadmon-backend/internal/slowlog"
+	"nadmon-backend/internal/testharness"
+	"nadmon-backend/internal/timeout"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	repo := repository.NewNadmonRepository(testharness.StartEnvioDB(t))
+	nadmonHandler := NewNadmonHandler(repo)
+	metadataHandler := NewMetadataHandler(repo, "https://nadmon.example")
+	// The same Envio database stands in for a deployment on a second chain
+	chainHandler := NewChainHandler(map[string]repository.Store{"10143": repo, "1": repo}, "10143")
+
+	r := gin.New()
+	r.Use(logging.Middleware())
+	api := r.Group("/api")
+	api.GET("/players/:address/nadmons", etag.Middleware(), nadmonHandler.GetInventory)
+	api.GET("/players/:address/profile", nadmonHandler.GetPlayerProfile)
+	api.GET("/players/:address/packs", nadmonHandler.GetPlayerPacks)
+	api.GET("/players/:address/packs/summary", nadmonHandler.GetPlayerPackSummary)
+	api.GET("/players/:address/stats", nadmonHandler.GetStats)
+	api.GET("/players/:address/search", nadmonHandler.SearchNFTs)
+	api.GET("/players/:address/transfers", nadmonHandler.GetPlayerTransfers)
+	api.GET("/players/:address/dex", nadmonHandler.GetPlayerDex)
+	api.GET("/players/:address/activity", nadmonHandler.GetPlayerActivity)
+	api.GET("/players/:address/nadmons/changes", nadmonHandler.GetInventoryChanges)
+	api.GET("/players/:address/fusion-candidates", nadmonHandler.GetFusionCandidates)
+	api.GET("/players/:address/export", nadmonHandler.ExportPlayer)
+	api.GET("/nfts/search", nadmonHandler.SearchAllNFTs)
+	api.GET("/nfts/:tokenId", chainHandler.Query(), etag.Middleware(), nadmonHandler.GetNFT)
+	api.GET("/nfts/:tokenId/stats/timeline", nadmonHandler.GetStatTimeline)
+	api.GET("/nfts/:tokenId/lineage", nadmonHandler.GetNFTLineage)
+	api.GET("/nfts/:tokenId/transfers", nadmonHandler.GetNFTTransfers)
+	api.GET("/nfts/:tokenId/sales", nadmonHandler.GetNFTSales)
+	api.GET("/nfts", nadmonHandler.GetNFTsByIDs)
+	api.GET("/verify/ownership", nadmonHandler.VerifyOwnership)
+	api.GET("/packs/:packId", nadmonHandler.GetPackDetails)
+	api.GET("/packs/:packId/reveal", nadmonHandler.GetPackReveal)
+	api.GET("/packs/recent", nadmonHandler.GetRecentPacks)
+	api.GET("/activity", nadmonHandler.GetActivity)
+	api.GET("/leaderboard/collectors", nadmonHandler.GetLeaderboard)
+	api.GET("/leaderboard/:type", nadmonHandler.GetLeaderboardByType)
+	api.GET("/stats/game", nadmonHandler.GetGameStats)
+	api.GET("/stats/pack-distribution", nadmonHandler.GetPackDistribution)
+	api.GET("/stats/concentration", nadmonHandler.GetOwnershipConcentration)
+	api.GET("/stats/supply", nadmonHandler.GetSupplyStats)
+	api.GET("/stats/elements", nadmonHandler.GetElementStats)
+	api.GET("/stats/rarities", nadmonHandler.GetRarityStats)
+	api.GET("/stats/floor", nadmonHandler.GetFloorStats)
+	api.GET("/analytics/timeseries", nadmonHandler.GetTimeSeries)
+	api.GET("/analytics/packs", nadmonHandler.GetPackOdds)
+	api.GET("/search/suggestions", nadmonHandler.GetSearchSuggestions)
+	api.GET("/metadata/:tokenId", metadataHandler.GetMetadata)
+	api.GET("/openapi.json", NewDocsHandler().GetSpec)
+	api.GET("/game-data", NewGameDataHandler("").GetGameData)
+
+	collectionHandler := NewCollectionHandler(map[string]repository.Store{"nadmon": repo}, "nadmon")
+	collections := api.Group("/collections/:collection", chainHandler.Query(), collectionHandler.Resolve())
+	collections.GET("/nfts/:tokenId", nadmonHandler.GetNFT)
+
+	api.GET("/chains", chainHandler.GetChains)
+	chains := api.Group("/chains/:chain", chainHandler.Resolve())
+	chains.GET("/nfts/:tokenId", nadmonHandler.GetNFT)
+
+	r.GET("/admin/snapshot", nadmonHandler.GetHolderSnapshot)
+	return r
+}
+
+func doGet(t *testing.T, r *gin.Engine, path string) (int, map[string]interface{}) {
+	t.Helper()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("GET %s: invalid JSON response %q: %v", path, w.Body.String(), err)
+	}
+	return w.Code, body
+}
+
+func TestHandlers(t *testing.T) {
+	r := newTestRouter(t)
+
+	tests := []struct {
+		name   string
+		path   string
+		status int
+		check  func(t *testing.T, body map[string]interface{})
+	}{
+		{"inventory", "/api/players/" + fixtures.Alice + "/nadmons", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			if body["total"].(float64) != 8 {
+				t.Errorf("expected 8 nadmons, got %v", body["total"])
+			}
+		}},
+		{"inventory invalid address", "/api/players/0x123/nadmons", http.StatusBadRequest, nil},
+		{"inventory checksummed address", "/api/players/0xaAaAaAaaAaAaAaaAaAAAAAAAAaaaAaAaAaaAaaAa/nadmons", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			if body["total"].(float64) != 8 {
+				t.Errorf("expected 8 nadmons, got %v", body["total"])
+			}
+		}},
+		{"inventory bad checksum", "/api/players/0xAAaAaAaaAaAaAaaAaAAAAAAAAaaaAaAaAaaAaaAa/nadmons", http.StatusBadRequest, nil},
+		{"inventory sorted slim", "/api/players/" + fixtures.Alice + "/nadmons?sort=attack&order=desc&fields=id,attack", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			data := body["data"].([]interface{})
+			first := data[0].(map[string]interface{})
+			if len(data) != 8 || first["id"] != float64(2) || first["attack"] != float64(40) || len(first) != 2 {
+				t.Errorf("expected the evolved token 2 first with only id and attack, got %v", data)
+			}
+		}},
+		{"inventory by rarity", "/api/players/" + fixtures.Alice + "/nadmons?sort=rarity&order=desc&fields=id", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			if first := body["data"].([]interface{})[0].(map[string]interface{}); first["id"] != float64(5) {
+				t.Errorf("expected the epic token 5 first, got %v", body["data"])
+			}
+		}},
+		{"inventory invalid sort", "/api/players/" + fixtures.Alice + "/nadmons?sort=owner", http.StatusBadRequest, nil},
+		{"inventory invalid fields", "/api/players/" + fixtures.Alice + "/nadmons?fields=id,secret", http.StatusBadRequest, nil},
+		{"profile", "/api/players/" + fixtures.Alice + "/profile", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			if body["packs_bought"].(float64) != 2 {
+				t.Errorf("expected 2 packs, got %v", body["packs_bought"])
+			}
+		}},
+		{"profile summary", "/api/players/" + fixtures.Alice + "/profile?include=summary", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			if _, ok := body["nadmons"]; ok {
+				t.Error("expected no nadmons in the summary")
+			}
+			if body["total_nfts"].(float64) != 8 || body["packs_bought"].(float64) != 2 {
+				t.Errorf("expected 8 nfts and 2 packs, got %v and %v", body["total_nfts"], body["packs_bought"])
+			}
+		}},
+		{"profile invalid include", "/api/players/" + fixtures.Alice + "/profile?include=everything", http.StatusBadRequest, nil},
+		{"holder snapshot before the sale", "/admin/snapshot?block_time=2025-07-02T12:00:00Z", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			if body["total_holders"].(float64) != 2 || body["total_supply"].(float64) != 15 {
+				t.Errorf("expected 2 holders of 15 nfts, got %v of %v", body["total_holders"], body["total_supply"])
+			}
+		}},
+		{"holder snapshot now", "/admin/snapshot", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			if body["total_holders"].(float64) != 3 || body["total_supply"].(float64) != 14 {
+				t.Errorf("expected 3 holders of 14 nfts, got %v of %v", body["total_holders"], body["total_supply"])
+			}
+		}},
+		{"holder snapshot invalid block_time", "/admin/snapshot?block_time=yesterday", http.StatusBadRequest, nil},
+		{"holder snapshot invalid format", "/admin/snapshot?format=xml", http.StatusBadRequest, nil},
+		{"packs", "/api/players/" + fixtures.Bob + "/packs", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			if body["total"].(float64) != 1 {
+				t.Errorf("expected 1 pack, got %v", body["total"])
+			}
+		}},
+		{"pack summary", "/api/players/" + fixtures.Alice + "/packs/summary", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			byPaymentType := body["by_payment_type"].(map[string]interface{})
+			if body["total_packs"].(float64) != 2 || body["mon_packs"].(float64) != 2 || byPaymentType["MON"].(float64) != 2 {
+				t.Errorf("expected 2 MON packs, got %v (%v)", body["total_packs"], byPaymentType)
+			}
+			if recent := body["recent_packs"].([]interface{}); len(recent) != 2 || recent[0].(map[string]interface{})["pack_id"].(float64) != 3 {
+				t.Errorf("expected packs 3 and 1 newest first, got %v", recent)
+			}
+			if last := body["last_purchase_at"].(string); !strings.HasPrefix(last, "2025-07-02") {
+				t.Errorf("expected last purchase on 2025-07-02, got %v", last)
+			}
+		}},
+		{"pack summary without packs", "/api/players/" + fixtures.Carol + "/packs/summary", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			if body["total_packs"].(float64) != 0 || len(body["recent_packs"].([]interface{})) != 0 || body["first_purchase_at"] != nil {
+				t.Errorf("expected an empty summary, got %v", body)
+			}
+		}},
+		{"pack summary invalid address", "/api/players/0x123/packs/summary", http.StatusBadRequest, nil},
+		{"stats", "/api/players/" + fixtures.Alice + "/stats", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			if body["evolvedNFTs"].(float64) != 1 {
+				t.Errorf("expected 1 evolved nft, got %v", body["evolvedNFTs"])
+			}
+		}},
+		{"search", "/api/players/" + fixtures.Alice + "/search?element=Water", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			if body["total"].(float64) != 2 {
+				t.Errorf("expected 2 water nadmons, got %v", body["total"])
+			}
+		}},
+		{"search sorted by attack", "/api/players/" + fixtures.Alice + "/search?min_attack=30&sort_by=attack&order=desc", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			data := body["data"].([]interface{})
+			if len(data) != 3 || data[0].(map[string]interface{})["id"].(float64) != 2 {
+				t.Errorf("expected token 2 first of 3, got %v", data)
+			}
+		}},
+		{"activity", "/api/activity?type=transfer", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			data := body["data"].([]interface{})
+			if body["total"].(float64) != 2 || data[0].(map[string]interface{})["detail"] != "burn" {
+				t.Errorf("expected the burn first of 2 transfers, got %v", body)
+			}
+		}},
+		{"packs leaderboard", "/api/leaderboard/packs?address=" + fixtures.Bob, http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			player := body["player"].(map[string]interface{})
+			if body["total"].(float64) != 2 || player["rank"].(float64) != 2 {
+				t.Errorf("expected bob ranked 2nd of 2 pack buyers, got %v", body)
+			}
+		}},
+		{"unknown leaderboard", "/api/leaderboard/trades", http.StatusNotFound, nil},
+		{"activity invalid type", "/api/activity?type=mint,trade", http.StatusBadRequest, nil},
+		{"player activity", "/api/players/" + fixtures.Alice + "/activity?types=transfer_out,pack", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			data := body["data"].([]interface{})
+			if body["total"].(float64) != 4 || data[0].(map[string]interface{})["type"] != "transfer_out" {
+				t.Errorf("expected the burn first of 2 transfers out and 2 packs, got %v", body)
+			}
+		}},
+		{"fusion candidates", "/api/players/" + fixtures.Alice + "/fusion-candidates", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			groups := body["groups"].([]interface{})
+			if len(groups) != 5 || body["fusable"].(float64) != 6 || body["evolvable"].(float64) != 0 {
+				t.Fatalf("expected 5 species and 6 fusable Nadmons, got %v", body)
+			}
+			// Pyro/Fire holds token 2 (evolved, final stage) and token 4 (fused once)
+			pyro := groups[1].(map[string]interface{})
+			candidates := pyro["candidates"].([]interface{})
+			if pyro["nadmon_type"] != "Pyro" || len(candidates) != 2 {
+				t.Fatalf("expected the Pyro group second, got %v", pyro)
+			}
+			evolved, fused := candidates[0].(map[string]interface{}), candidates[1].(map[string]interface{})
+			if evolved["fusions_to_evolve"] != nil || fused["fusions_to_evolve"].(float64) != 9 || fused["can_fuse"] != true {
+				t.Errorf("unexpected Pyro candidates: %v", candidates)
+			}
+		}},
+		{"player activity invalid type", "/api/players/" + fixtures.Alice + "/activity?types=transfer", http.StatusBadRequest, nil},
+		{"search invalid sort", "/api/players/" + fixtures.Alice + "/search?sort_by=owner", http.StatusBadRequest, nil},
+		{"search invalid order", "/api/players/" + fixtures.Alice + "/search?order=sideways", http.StatusBadRequest, nil},
+		{"global search by owner", "/api/nfts/search?owner=0xBBB", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			if body["total"].(float64) != 5 || body["hasNext"] != false || body["nextCursor"] != nil {
+				t.Errorf("expected bob's 5 nadmons on one page, got %v", body)
+			}
+		}},
+		{"global search invalid owner", "/api/nfts/search?owner=bob", http.StatusBadRequest, nil},
+		{"global search invalid cursor", "/api/nfts/search?cursor=not-a-cursor", http.StatusBadRequest, nil},
+		{"global search invalid sort", "/api/nfts/search?sort_by=owner", http.StatusBadRequest, nil},
+		{"nft", "/api/nfts/2", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			if len(body["history"].([]interface{})) != 1 {
+				t.Errorf("expected 1 history entry, got %v", body["history"])
+			}
+		}},
+		{"collection nft", "/api/collections/nadmon/nfts/2", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			if len(body["history"].([]interface{})) != 1 {
+				t.Errorf("expected the default collection's token 2, got %v", body)
+			}
+		}},
+		{"unknown collection", "/api/collections/items/nfts/2", http.StatusNotFound, nil},
+		{"chains", "/api/chains", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			if fmt.Sprint(body["chains"]) != "[1 10143]" || body["default"] != "10143" {
+				t.Errorf("expected chains 1 and 10143, got %v", body)
+			}
+		}},
+		{"chain nft", "/api/chains/1/nfts/2", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			if len(body["history"].([]interface{})) != 1 {
+				t.Errorf("expected the chain's token 2, got %v", body)
+			}
+		}},
+		{"nft on chain", "/api/nfts/2?chain=1", http.StatusOK, nil},
+		{"unknown chain", "/api/chains/137/nfts/2", http.StatusNotFound, nil},
+		{"invalid chain", "/api/nfts/2?chain=137", http.StatusBadRequest, nil},
+		{"collection on other chain", "/api/collections/nadmon/nfts/2?chain=1", http.StatusBadRequest, nil},
+		{"collection on default chain", "/api/collections/nadmon/nfts/2?chain=10143", http.StatusOK, nil},
+		{"burned nft", "/api/nfts/13", http.StatusGone, func(t *testing.T, body map[string]interface{}) {
+			if body["burned"] != true || body["status"] != "burned" || body["burnedAt"] == nil {
+				t.Errorf("expected burned status with timestamp, got %v", body)
+			}
+		}},
+		{"unknown nft", "/api/nfts/999", http.StatusNotFound, func(t *testing.T, body map[string]interface{}) {
+			if body["status"] != "unknown" {
+				t.Errorf("expected unknown status, got %v", body["status"])
+			}
+		}},
+		{"invalid nft id", "/api/nfts/abc", http.StatusBadRequest, nil},
+		{"stat timeline", "/api/nfts/2/stats/timeline?metric=hp,attack", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			events := body["events"].([]interface{})
+			series := body["series"].([]interface{})
+			if len(events) != 2 || events[0] != "mint" || events[1] != "evolution" || len(body["timestamps"].([]interface{})) != 2 {
+				t.Fatalf("expected the mint and the evolution, got %v", body)
+			}
+			hp := series[0].(map[string]interface{})
+			if len(series) != 2 || hp["metric"] != "hp" || fmt.Sprint(hp["values"]) != "[120 150]" {
+				t.Errorf("expected hp from 120 to 150, got %v", series)
+			}
+		}},
+		{"stat timeline unchanged", "/api/nfts/1/stats/timeline", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			if len(body["events"].([]interface{})) != 1 || len(body["series"].([]interface{})) != 6 {
+				t.Errorf("expected the mint point of every stat, got %v", body)
+			}
+		}},
+		{"stat timeline invalid metric", "/api/nfts/2/stats/timeline?metric=luck", http.StatusBadRequest, nil},
+		{"stat timeline burned", "/api/nfts/13/stats/timeline", http.StatusGone, nil},
+		{"nft lineage", "/api/nfts/4/lineage", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			root := body["lineage"].(map[string]interface{})
+			fusions := root["fusions"].([]interface{})
+			if len(fusions) != 1 || body["total_consumed"].(float64) != 1 || body["depth"].(float64) != 1 {
+				t.Fatalf("expected one fusion consuming one Nadmon, got %v", body)
+			}
+			consumed := fusions[0].(map[string]interface{})["consumed"].([]interface{})
+			if consumed[0].(map[string]interface{})["token_id"].(float64) != 13 {
+				t.Errorf("expected token 13 consumed by the fusion of token 4, got %v", consumed)
+			}
+		}},
+		{"nft lineage unfused", "/api/nfts/2/lineage", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			if body["depth"].(float64) != 0 || len(body["lineage"].(map[string]interface{})["fusions"].([]interface{})) != 0 {
+				t.Errorf("expected no fusions, got %v", body)
+			}
+		}},
+		{"nft lineage invalid depth", "/api/nfts/4/lineage?depth=11", http.StatusBadRequest, nil},
+		{"nft lineage burned", "/api/nfts/13/lineage", http.StatusGone, nil},
+		{"batch nfts", "/api/nfts?ids=1,2,13,999", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			if body["total"].(float64) != 2 {
+				t.Errorf("expected 2 nfts, got %v", body["total"])
+			}
+			missing := body["missing"].([]interface{})
+			if len(missing) != 2 || missing[0].(map[string]interface{})["status"] != "burned" || missing[1].(map[string]interface{})["status"] != "unknown" {
+				t.Errorf("expected burned and unknown missing entries, got %v", missing)
+			}
+			if ids := body["missing_ids"].([]interface{}); len(ids) != 2 || ids[0] != 13.0 || ids[1] != 999.0 {
+				t.Errorf("expected missing IDs 13 and 999, got %v", ids)
+			}
+		}},
+		{"verify ownership", "/api/verify/ownership?address=" + fixtures.Alice + "&tokenIds=1,2,6,13", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			owned := body["owned"].(map[string]interface{})
+			if owned["1"] != true || owned["2"] != true || owned["6"] != false || owned["13"] != false || body["all_owned"] != false {
+				t.Errorf("expected tokens 1 and 2 owned, not bob's 6 or burned 13, got %v", body)
+			}
+		}},
+		{"verify ownership min rarity", "/api/verify/ownership?address=" + fixtures.Alice + "&tokenIds=2,5&min_rarity=Epic", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			owned := body["owned"].(map[string]interface{})
+			if owned["2"] != false || owned["5"] != true || body["min_rarity"] != "Epic" {
+				t.Errorf("expected only the epic token 5 to count, got %v", body)
+			}
+		}},
+		{"verify ownership missing tokens", "/api/verify/ownership?address=" + fixtures.Alice, http.StatusBadRequest, nil},
+		{"nft transfers", "/api/nfts/3/transfers?limit=1", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			if body["total"].(float64) != 2 || body["totalPages"].(float64) != 2 || body["hasNext"] != true {
+				t.Errorf("expected 2 transfers over 2 pages, got %v", body)
+			}
+		}},
+		{"nft sales", "/api/nfts/3/sales", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			if body["total"].(float64) != 1 {
+				t.Errorf("expected 1 sale, got %v", body)
+			}
+		}},
+		{"floor", "/api/stats/floor?element=Nature", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			if body["sales"].(float64) != 1 || body["floor_price"] != nil || body["last_price"] != "1500000000000000000" {
+				t.Errorf("unexpected floor stats: %v", body)
+			}
+		}},
+		{"player transfers", "/api/players/" + fixtures.Carol + "/transfers", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			if body["total"].(float64) != 1 {
+				t.Errorf("expected 1 transfer, got %v", body["total"])
+			}
+		}},
+		{"dex", "/api/players/" + fixtures.Carol + "/dex", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			if body["owned_species"].(float64) != 1 || len(body["missing"].([]interface{})) != 11 {
+				t.Errorf("expected 1 of 12 species, got %v", body)
+			}
+		}},
+		{"dex invalid address", "/api/players/0x123/dex", http.StatusBadRequest, nil},
+		{"inventory changes", "/api/players/" + fixtures.Alice + "/nadmons/changes?since_sequence=0&fields=id", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			removed := body["removed"].([]interface{})
+			if body["total"].(float64) != 8 || len(removed) != 2 || body["sequence"].(float64) <= 0 {
+				t.Errorf("expected 8 held and tokens 3 and 13 removed, got %v", body)
+			}
+		}},
+		{"pack reveal", "/api/packs/1/reveal?revealed=2", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			// Commons 1, 3 and 4, then rare 2 and epic 5
+			cards := body["cards"].([]interface{})
+			first, second, last := cards[0].(map[string]interface{}), cards[1].(map[string]interface{}), cards[4].(map[string]interface{})
+			if body["ready"] != true || len(cards) != 5 || first["token_id"] != float64(1) || second["token_id"] != float64(3) {
+				t.Fatalf("expected the two first commons revealed, got %v", body)
+			}
+			if _, spoiled := last["token_id"]; last["sealed"] != true || spoiled || body["remaining"] != float64(3) {
+				t.Errorf("expected the epic sealed without its token ID, got %v", last)
+			}
+		}},
+		{"pack reveal with a burned card", "/api/packs/3/reveal?revealed=5", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			burned := body["burned_token_ids"].([]interface{})
+			if body["ready"] != true || len(body["cards"].([]interface{})) != 4 || len(burned) != 1 || burned[0] != float64(13) || body["revealed"] != float64(4) {
+				t.Errorf("expected 4 cards and token 13 burned, got %v", body)
+			}
+		}},
+		{"pack reveal invalid revealed", "/api/packs/1/reveal?revealed=6", http.StatusBadRequest, nil},
+		{"pack reveal not found", "/api/packs/999/reveal", http.StatusNotFound, nil},
+		{"inventory changes invalid sequence", "/api/players/" + fixtures.Alice + "/nadmons/changes?since_sequence=-1", http.StatusBadRequest, nil},
+		{"batch nfts missing ids", "/api/nfts", http.StatusBadRequest, nil},
+		{"pack details", "/api/packs/3", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			if body["total_nfts"].(float64) != 4 {
+				t.Errorf("expected 4 live nfts in pack 3, got %v", body["total_nfts"])
+			}
+		}},
+		{"missing pack", "/api/packs/999", http.StatusNotFound, nil},
+		{"recent packs", "/api/packs/recent?limit=2", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			if body["total"].(float64) != 2 {
+				t.Errorf("expected 2 packs, got %v", body["total"])
+			}
+		}},
+		{"leaderboard", "/api/leaderboard/collectors", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			if body["total"].(float64) != 3 {
+				t.Errorf("expected 3 collectors, got %v", body["total"])
+			}
+		}},
+		{"game stats", "/api/stats/game", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			if body["total_nfts"].(float64) != 14 {
+				t.Errorf("expected 14 nfts, got %v", body["total_nfts"])
+			}
+		}},
+		{"pack distribution", "/api/stats/pack-distribution", http.StatusOK, nil},
+		{"concentration", "/api/stats/concentration", http.StatusOK, nil},
+		{"timeseries", "/api/analytics/timeseries?metric=packs&interval=day&from=2025-07-01&to=2025-07-04", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			// Empty buckets are filled with zeros
+			data := body["data"].([]interface{})
+			counts := []float64{2, 1, 0}
+			if len(data) != len(counts) {
+				t.Fatalf("expected %d buckets, got %v", len(counts), data)
+			}
+			for i, count := range counts {
+				if data[i].(map[string]interface{})["count"].(float64) != count {
+					t.Errorf("bucket %d: expected %v, got %v", i, count, data[i])
+				}
+			}
+		}},
+		{"timeseries unknown metric", "/api/analytics/timeseries?metric=burns", http.StatusBadRequest, nil},
+		{"timeseries range too large", "/api/analytics/timeseries?metric=mints&interval=hour&from=2024-01-01&to=2025-01-01", http.StatusBadRequest, nil},
+		{"pack odds", "/api/analytics/packs?to=2025-07-02", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			byPayment := body["by_payment_type"].(map[string]interface{})
+			if body["packs"].(float64) != 2 || len(byPayment) != 2 {
+				t.Errorf("expected the two packs of July 1, got %v", body)
+			}
+		}},
+		{"pack odds invalid window", "/api/analytics/packs?from=2025-07-02&to=2025-07-01", http.StatusBadRequest, nil},
+		{"supply", "/api/stats/supply", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			if body["minted"].(float64) != 15 || body["burned"].(float64) != 1 || body["circulating"].(float64) != 14 {
+				t.Errorf("unexpected supply: %v", body)
+			}
+		}},
+		{"element stats", "/api/stats/elements", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			traits := body["traits"].([]interface{})
+			if body["circulating"].(float64) != 14 || len(traits) != 8 || traits[0].(map[string]interface{})["value"] != "Water" {
+				t.Errorf("unexpected element stats: %v", body)
+			}
+		}},
+		{"rarity stats", "/api/stats/rarities", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			traits := body["traits"].([]interface{})
+			if len(traits) != 5 || traits[0].(map[string]interface{})["value"] != "Common" || traits[0].(map[string]interface{})["count"].(float64) != 8 {
+				t.Errorf("unexpected rarity stats: %v", body)
+			}
+		}},
+		{"metadata", "/api/metadata/2", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			if body["name"] != "Pyro #2" || body["image"] != "https://nadmon.example/api/images/2?v=ii-f0" {
+				t.Errorf("unexpected metadata: %v", body)
+			}
+			if len(body["attributes"].([]interface{})) == 0 {
+				t.Error("expected attributes")
+			}
+		}},
+		{"burned metadata", "/api/metadata/13", http.StatusNotFound, nil},
+		{"search suggestions", "/api/search/suggestions?q=py", http.StatusOK, func(t *testing.T, body map[string]interface{}) {
+			if body["total"].(float64) != 1 {
+				t.Errorf("expected 1 suggestion, got %v", body["data"])
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := doGet(t, r, tt.path)
+			if status != tt.status {
+				t.Fatalf("GET %s: got status %d, want %d (%v)", tt.path, status, tt.status, body)
+			}
+			if tt.check != nil {
+				tt.check(t, body)
+			}
+		})
+	}
+}
+
+func TestHolderSnapshotCSV(t *testing.T) {
+	r := newTestRouter(t)
+
+	// 2025-07-01 10:30 UTC: only alice's first pack is minted
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/snapshot?block_time=1751365800&format=csv", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("got status %d: %s", w.Code, w.Body.String())
+	}
+	want := "address,balance,token_ids\n" + fixtures.Alice + ",5,1;2;3;4;5\n"
+	if w.Body.String() != want {
+		t.Errorf("got %q, want %q", w.Body.String(), want)
+	}
+}
+
+func TestGlobalSearchPaging(t *testing.T) {
+	r := newTestRouter(t)
+
+	for _, order := range []string{"asc", "desc"} {
+		var ids []int64
+		path := "/api/nfts/search?sort_by=attack&limit=4&order=" + order
+		for cursor := ""; ; {
+			code, body := doGet(t, r, path+cursor)
+			if code != http.StatusOK {
+				t.Fatalf("%s: expected 200, got %d: %v", order, code, body)
+			}
+			if body["total"].(float64) != 14 {
+				t.Fatalf("%s: expected 14 circulating nadmons, got %v", order, body["total"])
+			}
+			for _, nft := range body["data"].([]interface{}) {
+				ids = append(ids, int64(nft.(map[string]interface{})["id"].(float64)))
+			}
+			next, ok := body["nextCursor"].(string)
+			if !ok {
+				break
+			}
+			cursor = "&cursor=" + next
+		}
+
+		seen := make(map[int64]bool)
+		for _, id := range ids {
+			if seen[id] || id == 13 {
+				t.Errorf("%s: unexpected token %d in %v", order, id, ids)
+			}
+			seen[id] = true
+		}
+		if len(ids) != 14 {
+			t.Errorf("%s: expected 14 nadmons across pages, got %v", order, ids)
+		}
+	}
+}
+
+func TestOpenAPISpec(t *testing.T) {
+	r := newTestRouter(t)
+
+	status, body := doGet(t, r, "/api/openapi.json")
+	if status != http.StatusOK {
+		t.Fatalf("got status %d", status)
+	}
+	paths, ok := body["paths"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("spec has no paths: %v", body)
+	}
+
+	// Every route registered by the handlers under test must be documented
+	for _, route := range r.Routes() {
+		segments := strings.Split(route.Path, "/")
+		for i, segment := range segments {
+			if strings.HasPrefix(segment, ":") {
+				segments[i] = "{" + segment[1:] + "}"
+			}
+		}
+		path := strings.Join(segments, "/")
+
+		operations, ok := paths[path].(map[string]interface{})
+		if !ok {
+			t.Errorf("%s is missing from the spec", path)
+			continue
+		}
+		if _, ok := operations[strings.ToLower(route.Method)]; !ok {
+			t.Errorf("%s %s is missing from the spec", route.Method, path)
+		}
+	}
+}
+
+func TestAPIVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	nadmonHandler := NewNadmonHandler(repository.NewNadmonRepository(testharness.StartEnvioDB(t)))
+	r := gin.New()
+	r.Use(logging.Middleware())
+	for _, api := range []*gin.RouterGroup{r.Group("/api", apiversion.Middleware("")), r.Group("/api/v1", apiversion.Middleware(apiversion.V1))} {
+		api.GET("/players/:address/nadmons", etag.Middleware(), nadmonHandler.GetInventory)
+		api.GET("/players/:address/profile", nadmonHandler.GetPlayerProfile)
+		api.GET("/nfts/:tokenId", nadmonHandler.GetNFT)
+		api.GET("/players/:address/export", nadmonHandler.ExportPlayer)
+	}
+	get := func(path, version string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if version != "" {
+			req.Header.Set(apiversion.Header, version)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	decode := func(w *httptest.ResponseRecorder) map[string]interface{} {
+		var body map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("invalid JSON response %q: %v", w.Body.String(), err)
+		}
+		return body
+	}
+
+	// Legacy bodies are unchanged without the header
+	legacy := get("/api/players/"+fixtures.Alice+"/nadmons", "")
+	if body := decode(legacy); legacy.Code != http.StatusOK || body["meta"] != nil || body["total"] != float64(8) {
+		t.Errorf("expected the legacy inventory, got %d %v", legacy.Code, body)
+	}
+	if legacy.Header().Get(apiversion.Header) != "" || !strings.Contains(legacy.Header().Get("Vary"), apiversion.Header) {
+		t.Errorf("unexpected version headers: %v", legacy.Header())
+	}
+
+	// {data, ...} responses put data in the envelope and the rest in meta, whether the
+	// version comes from the path or the header
+	for _, w := range []*httptest.ResponseRecorder{get("/api/v1/players/"+fixtures.Alice+"/nadmons", ""), get("/api/players/"+fixtures.Alice+"/nadmons", "1")} {
+		body := decode(w)
+		meta, _ := body["meta"].(map[string]interface{})
+		if data, _ := body["data"].([]interface{}); w.Code != http.StatusOK || len(data) != 8 || meta["total"] != float64(8) || meta["api_version"] != "1" || body["error"] != nil {
+			t.Errorf("expected 8 enveloped NFTs, got %d %v", w.Code, body)
+		}
+		if w.Header().Get(apiversion.Header) != "1" || w.Header().Get("ETag") == "" {
+			t.Errorf("expected API-Version 1 and an ETag, got %v", w.Header())
+		}
+	}
+
+	// Structs become data as a whole
+	w := get("/api/v1/players/"+fixtures.Alice+"/profile", "")
+	if data, _ := decode(w)["data"].(map[string]interface{}); w.Code != http.StatusOK || data["address"] != fixtures.Alice {
+		t.Errorf("expected alice's profile as data, got %d %s", w.Code, w.Body.String())
+	}
+
+	// Errors carry a code from the status and the handler's message
+	w = get("/api/v1/nfts/abc", "")
+	body := decode(w)
+	if errBody, _ := body["error"].(map[string]interface{}); w.Code != http.StatusBadRequest || body["data"] != nil ||
+		errBody["code"] != apiversion.CodeBadRequest || errBody["message"] == "" || body["request_id"] == nil {
+		t.Errorf("expected an enveloped 400, got %d %v", w.Code, body)
+	}
+
+	// Unsupported versions are rejected, and non-JSON responses are left alone
+	for _, w := range []*httptest.ResponseRecorder{get("/api/players/"+fixtures.Alice+"/profile", "2"), get("/api/v1/players/"+fixtures.Alice+"/profile", "3")} {
+		if errBody := decode(w); w.Code != http.StatusBadRequest || errBody["code"] != apiversion.CodeUnsupportedVersion {
+			t.Errorf("unsupported version: expected 400, got %d %v", w.Code, errBody)
+		}
+	}
+	w = get("/api/v1/players/"+fixtures.Alice+"/export?format=csv", "")
+	if w.Code != http.StatusOK || strings.Contains(w.Body.String(), "api_version") {
+		t.Errorf("expected a plain CSV export, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	r := newTestRouter(t)
+
+	// A forwarded ID is reused and reported in error bodies
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/players/not-an-address/nadmons", nil)
+	req.Header.Set(logging.RequestIDHeader, "bug-report-42")
+	r.ServeHTTP(w, req)
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v (%s)", err, w.Body.String())
+	}
+	if w.Code != http.StatusBadRequest || body["request_id"] != "bug-report-42" || body["error"] == nil {
+		t.Errorf("unexpected error response %d: %v", w.Code, body)
+	}
+	if got := w.Header().Get(logging.RequestIDHeader); got != "bug-report-42" {
+		t.Errorf("X-Request-ID = %q", got)
+	}
+
+	// Otherwise one is generated; success bodies are left alone
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/stats/game", nil))
+	if w.Header().Get(logging.RequestIDHeader) == "" {
+		t.Error("expected a generated request ID")
+	}
+	if strings.Contains(w.Body.String(), "request_id") {
+		t.Errorf("request ID added to a success body: %s", w.Body.String())
+	}
+}
+
+func TestConditionalRequests(t *testing.T) {
+	r := newTestRouter(t)
+
+	for _, path := range []string{"/api/players/" + fixtures.Alice + "/nadmons", "/api/nfts/1"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		tag := w.Header().Get("ETag")
+		if w.Code != http.StatusOK || tag == "" {
+			t.Fatalf("GET %s: status %d, ETag %q", path, w.Code, tag)
+		}
+
+		// A matching If-None-Match gets an empty 304
+		w = httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("If-None-Match", `"stale", `+tag)
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusNotModified || w.Body.Len() != 0 || w.Header().Get("ETag") != tag {
+			t.Errorf("GET %s with matching ETag: status %d, body %q", path, w.Code, w.Body.String())
+		}
+
+		// A stale one gets the full response
+		w = httptest.NewRecorder()
+		req = httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("If-None-Match", `"stale"`)
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK || w.Body.Len() == 0 {
+			t.Errorf("GET %s with stale ETag: status %d", path, w.Code)
+		}
+	}
+
+	// Errors are not tagged
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/nfts/999", nil))
+	if w.Code != http.StatusNotFound || w.Header().Get("ETag") != "" {
+		t.Errorf("missing NFT: status %d, ETag %q", w.Code, w.Header().Get("ETag"))
+	}
+}
+
+func TestAdmin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := testharness.StartEnvioDB(t)
+	// Every query counts as slow
+	slowLog := slowlog.New(time.Nanosecond, 10, time.Hour)
+	adminHandler := NewAdminHandler(db, nil, slowLog)
+	r := gin.New()
+	admin := r.Group("/admin", auth.RequireAPIKey("secret"))
+	admin.POST("/cache/flush", adminHandler.FlushCache)
+	admin.POST("/indexes/rebuild", adminHandler.RebuildIndexes)
+	admin.GET("/slow-queries", adminHandler.GetSlowQueries)
+	admin.GET("/slow-operations", adminHandler.GetSlowOperations)
+
+	do := func(method, path, key string) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, nil)
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+		r.ServeHTTP(w, req)
+
+		var body map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s %s: invalid JSON response %q: %v", method, path, w.Body.String(), err)
+		}
+		return w.Code, body
+	}
+
+	for _, key := range []string{"", "wrong"} {
+		if code, _ := do(http.MethodGet, "/admin/slow-queries", key); code != http.StatusUnauthorized {
+			t.Errorf("key %q: expected 401, got %d", key, code)
+		}
+	}
+
+	code, body := do(http.MethodPost, "/admin/indexes/rebuild", "secret")
+	if code != http.StatusOK || body["failed"].(float64) != 0 || len(body["indexes"].([]interface{})) == 0 {
+		t.Errorf("unexpected index rebuild response %d: %v", code, body)
+	}
+
+	// pg_stat_statements is usually not loaded in the test database
+	if code, body := do(http.MethodGet, "/admin/slow-queries", "secret"); code != http.StatusOK && code != http.StatusNotImplemented {
+		t.Errorf("unexpected slow query response %d: %v", code, body)
+	}
+
+	if code, _ := do(http.MethodPost, "/admin/cache/flush", "secret"); code != http.StatusConflict {
+		t.Errorf("flush without a cache: expected 409, got %d", code)
+	}
+
+	repo := repository.NewNadmonRepositoryWithConn(db, slowLog.Wrap(db.DB))
+	if _, err := repo.GetSingleNadmon(context.Background(), 2); err != nil {
+		t.Fatal(err)
+	}
+	code, body = do(http.MethodGet, "/admin/slow-operations?kind=query", "secret")
+	operations, _ := body["data"].([]interface{})
+	if code != http.StatusOK || len(operations) == 0 {
+		t.Fatalf("expected the NFT query, got %d: %v", code, body)
+	}
+	if op := operations[0].(map[string]interface{}); op["kind"] != "query" || op["name"] == "" || len(op["params"].([]interface{})) == 0 {
+		t.Errorf("expected a named query with its parameters, got %v", op)
+	}
+	if code, _ := do(http.MethodGet, "/admin/slow-operations?kind=disk", "secret"); code != http.StatusBadRequest {
+		t.Errorf("invalid kind: expected 400, got %d", code)
+	}
+}
+
+func TestGameData(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	defaults := gamedata.Current()
+	t.Cleanup(func() { gamedata.Set(defaults) })
+
+	path := filepath.Join(t.TempDir(), "game-data.json")
+	handler := NewGameDataHandler(path)
+	r := gin.New()
+	r.GET("/api/game-data", handler.GetGameData)
+	r.POST("/admin/game-data/reload", handler.ReloadGameData)
+
+	reload := func(data string) (int, map[string]interface{}) {
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/game-data/reload", nil))
+		var body map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("invalid JSON response %q: %v", w.Body.String(), err)
+		}
+		return w.Code, body
+	}
+
+	nadmon := models.Nadmon{Element: "Fire", HP: 120, Attack: 30, Defense: 18, Crit: 8}
+	status, body := doGet(t, r, "/api/game-data")
+	derived := body["derived"].(map[string]interface{})
+	if status != http.StatusOK || derived["speed"] != "(hp + attack + defense) / 10" || nadmon.CalculateSpeed() != 16 {
+		t.Fatalf("expected the default speed formula, got %d: %v", status, body)
+	}
+	if models.GetElementColor("Fire") != "#ff6b6b" || models.GetElementColor("Plasma") != "#6c757d" {
+		t.Errorf("unexpected default colors")
+	}
+
+	status, body = reload(`{
+		"version": "2025-08-01",
+		"defaultColor": "#000000",
+		"elements": {"Fire": {"color": "#ff0000"}, "Water": {"color": "#0000ff"}},
+		"matchups": {"Water": {"Fire": 1.5}},
+		"derived": {"speed": "(hp + attack * 2) / 20", "power": "hp + attack"}
+	}`)
+	if status != http.StatusOK || body["version"] != "2025-08-01" || body["previous_version"] != defaults.Version {
+		t.Fatalf("unexpected reload response %d: %v", status, body)
+	}
+	if nadmon.CalculateSpeed() != 9 || nadmon.Stats().Power() != 150 || models.GetElementColor("Fire") != "#ff0000" {
+		t.Errorf("expected stats derived with the reloaded formulas, got speed %d, power %d", nadmon.CalculateSpeed(), nadmon.Stats().Power())
+	}
+	if multiplier := gamedata.Current().Multiplier("Water", "Fire"); multiplier != 1.5 {
+		t.Errorf("expected Water to deal 1.5x against Fire, got %v", multiplier)
+	}
+
+	for name, data := range map[string]string{
+		"unknown variable": `{"version": "bad", "elements": {"Fire": {}}, "derived": {"speed": "luck", "power": "hp"}}`,
+		"unknown element":  `{"version": "bad", "elements": {"Fire": {}}, "matchups": {"Fire": {"Plasma": 2}}, "derived": {"speed": "hp", "power": "hp"}}`,
+		"missing formula":  `{"version": "bad", "elements": {"Fire": {}}, "derived": {"speed": "hp"}}`,
+		"malformed":        `{"version": "bad", "elements": {"Fire": {}}, "derived": {"speed": "(hp +", "power": "hp"}}`,
+	} {
+		if status, _ := reload(data); status != http.StatusInternalServerError {
+			t.Errorf("%s: expected the reload to fail, got %d", name, status)
+		}
+	}
+	if version := gamedata.Current().Version; version != "2025-08-01" {
+		t.Errorf("a failed reload replaced the game data with version %s", version)
+	}
+}
+
+func TestRequestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := testharness.StartEnvioDB(t)
+	nadmonHandler := NewNadmonHandler(repository.NewNadmonRepository(db))
+	r := gin.New()
+	api := r.Group("/api", timeout.Middleware(200*time.Millisecond))
+	api.GET("/stats/game", nadmonHandler.GetGameStats)
+	api.GET("/slow", func(c *gin.Context) {
+		if _, err := db.DB.ExecContext(c.Request.Context(), "SELECT pg_sleep(5)"); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to sleep: " + err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"slept": true})
+	})
+
+	if status, body := doGet(t, r, "/api/stats/game"); status != http.StatusOK {
+		t.Errorf("fast request: status %d (%v)", status, body)
+	}
+
+	// The query is cancelled at the deadline instead of running for 5 seconds
+	start := time.Now()
+	status, body := doGet(t, r, "/api/slow")
+	if status != http.StatusGatewayTimeout || body["error"] != "Request timed out" {
+		t.Errorf("slow request: status %d (%v)", status, body)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("slow request took %s", elapsed)
+	}
+}
+
+func TestCompressedStreamedInventory(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	nadmonHandler := NewNadmonHandler(repository.NewNadmonRepository(testharness.StartEnvioDB(t)))
+	r := gin.New()
+	r.Use(compress.Middleware(1))
+	r.GET("/api/players/:address/nadmons", etag.Middleware(), nadmonHandler.GetInventory)
+	path := "/api/players/" + fixtures.Alice + "/nadmons"
+
+	get := func(encoding string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("Accept-Encoding", encoding)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	buffered := get("identity")
+	if buffered.Header().Get("Content-Encoding") != "" || buffered.Header().Get("ETag") == "" {
+		t.Fatalf("expected an uncompressed response with an ETag, got %v", buffered.Header())
+	}
+
+	// Alice's 8 Nadmons are above the threshold: same body, streamed and gzipped, no ETag
+	nadmonHandler.SetInventoryStreamThreshold(2)
+	streamed := get("br;q=0.5, gzip")
+	if streamed.Header().Get("Content-Encoding") != "gzip" || streamed.Header().Get("ETag") != "" {
+		t.Fatalf("expected a gzipped response without ETag, got %v", streamed.Header())
+	}
+	reader, err := gzip.NewReader(streamed.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(body, buffered.Body.Bytes()) {
+		t.Errorf("streamed body differs:\n%s\n%s", body, buffered.Body.Bytes())
+	}
+}
+
+func TestExportPlayer(t *testing.T) {
+	r := newTestRouter(t)
+	path := "/api/players/" + fixtures.Alice + "/export"
+
+	_, activity := doGet(t, r, "/api/players/"+fixtures.Alice+"/activity?limit=100")
+	activityTotal := int(activity["total"].(float64))
+
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path+query, nil))
+		return w
+	}
+
+	csvResp := get("")
+	if csvResp.Code != http.StatusOK || !strings.HasPrefix(csvResp.Header().Get("Content-Type"), "text/csv") {
+		t.Fatalf("got status %d, content type %q", csvResp.Code, csvResp.Header().Get("Content-Type"))
+	}
+	records, err := csv.NewReader(csvResp.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if records[0][0] != "record" || records[0][1] != "token_id" {
+		t.Errorf("unexpected header %v", records[0])
+	}
+	counts := make(map[string]int)
+	for _, record := range records[1:] {
+		counts[record[0]]++
+	}
+	if counts["nadmon"] != 8 || counts["activity"] != activityTotal {
+		t.Errorf("got %v, want 8 nadmon and %d activity records", counts, activityTotal)
+	}
+	if records[1][1] != "1" || records[1][3] == "" || records[1][14] != "" {
+		t.Errorf("first record should be token 1 without activity columns, got %v", records[1])
+	}
+
+	ndjsonResp := get("?format=ndjson")
+	lines := strings.Split(strings.TrimSpace(ndjsonResp.Body.String()), "\n")
+	if len(lines) != 8+activityTotal {
+		t.Fatalf("got %d lines, want %d", len(lines), 8+activityTotal)
+	}
+	var last map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[len(lines)-1]), &last); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := last["nadmon_type"]; ok || last["record"] != "activity" || last["activity_type"] == nil {
+		t.Errorf("last line should be an activity record without Nadmon columns, got %v", last)
+	}
+
+	if w := get("?format=xlsx"); w.Code != http.StatusBadRequest {
+		t.Errorf("unknown format: got status %d, want 400", w.Code)
+	}
+}
+
+func TestLimits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	nadmonHandler := NewNadmonHandler(repository.NewNadmonRepository(testharness.StartEnvioDB(t)))
+	nadmonHandler.SetLimits(Limits{MaxBatchIDs: 2, DefaultPageSize: 2, MaxPageSize: 3})
+	r := gin.New()
+	r.GET("/api/nfts", nadmonHandler.GetNFTsByIDs)
+	r.GET("/api/activity", nadmonHandler.GetActivity)
+	r.GET("/api/packs/recent", nadmonHandler.GetRecentPacks)
+
+	if code, body := doGet(t, r, "/api/nfts?ids=1,2,3"); code != http.StatusBadRequest {
+		t.Errorf("3 IDs: expected 400, got %d: %v", code, body)
+	}
+	if code, body := doGet(t, r, "/api/nfts?ids=1,2"); code != http.StatusOK {
+		t.Errorf("2 IDs: expected 200, got %d: %v", code, body)
+	}
+
+	for query, want := range map[string]float64{"": 2, "?limit=3": 3, "?limit=4": 2} {
+		_, body := doGet(t, r, "/api/activity"+query)
+		if body["limit"] != want {
+			t.Errorf("activity%s: expected limit %v, got %v", query, want, body["limit"])
+		}
+	}
+
+	// The top-N default of 10 is capped by the largest page size
+	if _, body := doGet(t, r, "/api/packs/recent"); len(body["data"].([]interface{})) > 3 {
+		t.Errorf("expected at most 3 recent packs, got %v", body["data"])
+	}
+}
+
+func TestDisplayProfile(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := testharness.StartEnvioDB(t)
+	if err := db.SetupAppSchema(); err != nil {
+		t.Fatal(err)
+	}
+	nadmonHandler := NewNadmonHandler(repository.NewNadmonRepository(db))
+	nadmonHandler.SetProfileStore(repository.NewProfileRepository(db.DB))
+
+	r := gin.New()
+	signedIn := func(c *gin.Context) { c.Set(auth.AddressKey, c.Param("address")) }
+	r.PUT("/api/players/:address/profile", signedIn, nadmonHandler.UpdateDisplayProfile)
+	r.GET("/api/players/:address/profile", nadmonHandler.GetPlayerProfile)
+	r.GET("/api/leaderboard/collectors", nadmonHandler.GetLeaderboard)
+
+	put := func(address, body string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "/api/players/"+address+"/profile", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Token 6 is Bob's
+	if code := put(fixtures.Alice, `{"nickname": "Ash", "avatar_token_id": 6}`); code != http.StatusBadRequest {
+		t.Errorf("avatar held by someone else: expected 400, got %d", code)
+	}
+	if code := put(fixtures.Alice, `{"nickname": "x"}`); code != http.StatusBadRequest {
+		t.Errorf("short nickname: expected 400, got %d", code)
+	}
+	if code := put(fixtures.Alice, `{"nickname": "  Ash   Ketchum ", "avatar_token_id": 5, "bio": "Fire main"}`); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if code := put(fixtures.Bob, `{"nickname": "ash ketchum"}`); code != http.StatusConflict {
+		t.Errorf("taken nickname: expected 409, got %d", code)
+	}
+
+	_, profile := doGet(t, r, "/api/players/"+fixtures.Alice+"/profile")
+	display, _ := profile["display"].(map[string]interface{})
+	if display["nickname"] != "Ash Ketchum" || display["avatar_token_id"] != float64(5) {
+		t.Errorf("expected the display profile in the player profile, got %v", profile["display"])
+	}
+
+	_, leaderboard := doGet(t, r, "/api/leaderboard/collectors")
+	for _, entry := range leaderboard["data"].([]interface{}) {
+		entry := entry.(map[string]interface{})
+		if (entry["address"] == fixtures.Alice) != (entry["display"] != nil) {
+			t.Errorf("expected a display profile only for Alice, got %v", entry)
+		}
+	}
+}
+
+func TestAuditLog(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := testharness.StartEnvioDB(t)
+	if err := db.SetupAppSchema(); err != nil {
+		t.Fatal(err)
+	}
+	store := repository.NewAuditRepository(db.DB)
+	logger := audit.NewLogger(store, audit.Config{})
+	nadmonHandler := NewNadmonHandler(repository.NewNadmonRepository(db))
+
+	r := gin.New()
+	api := r.Group("/api", logger.Middleware())
+	api.GET("/players/:address/nadmons", nadmonHandler.GetInventory)
+	api.GET("/players/:address/stats", nadmonHandler.GetStats)
+	api.GET("/nfts/:tokenId", nadmonHandler.GetNFT)
+	r.GET("/admin/audit-log", NewAuditHandler(store).GetAuditLog)
+
+	for _, path := range []string{
+		"/api/players/" + fixtures.Alice + "/nadmons",
+		"/api/players/" + fixtures.Alice + "/stats",
+		"/api/players/" + fixtures.Bob + "/nadmons",
+		"/api/nfts/1",
+	} {
+		if code, body := doGet(t, r, path); code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d: %v", path, code, body)
+		}
+	}
+	if err := logger.Flush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	code, body := doGet(t, r, "/admin/audit-log?address="+fixtures.Alice)
+	if code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %v", code, body)
+	}
+	data := body["data"].([]interface{})
+	if len(data) != 2 || data[0].(map[string]interface{})["route"] != "/api/players/:address/stats" || body["next_before"] != nil {
+		t.Fatalf("expected alice's 2 reads, newest first, got %v", body)
+	}
+
+	// Pages of one entry over all 3 reads
+	var routes []interface{}
+	path := "/admin/audit-log?limit=1"
+	for i := 0; i < 4 && path != ""; i++ {
+		_, page := doGet(t, r, path)
+		for _, entry := range page["data"].([]interface{}) {
+			routes = append(routes, entry.(map[string]interface{})["route"])
+		}
+		path = ""
+		if next, ok := page["next_before"].(float64); ok {
+			path = fmt.Sprintf("/admin/audit-log?limit=1&before=%d", int64(next))
+		}
+	}
+	if len(routes) != 3 {
+		t.Errorf("expected the 3 player reads over pages, got %v", routes)
+	}
+
+	for _, query := range []string{"address=alice", "api_key_id=0", "from=yesterday", "before=x", "limit=1001"} {
+		if code, _ := doGet(t, r, "/admin/audit-log?"+query); code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, code)
+		}
+	}
+}
+
+func TestTeams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := testharness.StartEnvioDB(t)
+	if err := db.SetupAppSchema(); err != nil {
+		t.Fatal(err)
+	}
+	nadmonHandler := NewNadmonHandler(repository.NewNadmonRepository(db))
+	nadmonHandler.SetTeamStore(repository.NewTeamRepository(db.DB))
+	nadmonHandler.SetLimits(Limits{MaxTeamSize: 3, MaxTeams: 2})
+
+	r := gin.New()
+	signedIn := func(c *gin.Context) { c.Set(auth.AddressKey, c.Param("address")) }
+	r.GET("/api/players/:address/teams", signedIn, nadmonHandler.GetTeams)
+	r.POST("/api/players/:address/teams", signedIn, nadmonHandler.CreateTeam)
+	r.DELETE("/api/players/:address/teams/:teamId", signedIn, nadmonHandler.DeleteTeam)
+
+	post := func(body string) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/players/"+fixtures.Alice+"/teams", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		r.ServeHTTP(w, req)
+		var team map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &team)
+		return w.Code, team
+	}
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"empty name", `{"name": " ", "token_ids": [1]}`, http.StatusBadRequest},
+		{"no Nadmons", `{"name": "Squad", "token_ids": []}`, http.StatusBadRequest},
+		{"too many Nadmons", `{"name": "Squad", "token_ids": [1, 2, 4, 5]}`, http.StatusBadRequest},
+		{"duplicate Nadmon", `{"name": "Squad", "token_ids": [1, 1]}`, http.StatusBadRequest},
+		{"Bob's Nadmon", `{"name": "Squad", "token_ids": [1, 6]}`, http.StatusBadRequest},
+		{"burned Nadmon", `{"name": "Squad", "token_ids": [13]}`, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		if code, _ := post(tt.body); code != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.want, code)
+		}
+	}
+
+	code, team := post(`{"name": "Fire squad", "token_ids": [1, 2, 5]}`)
+	if code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", code)
+	}
+	if code, _ := post(`{"name": "fire squad", "token_ids": [4]}`); code != http.StatusConflict {
+		t.Errorf("taken name: expected 409, got %d", code)
+	}
+	if code, _ := post(`{"name": "Backup", "token_ids": [4]}`); code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", code)
+	}
+	if code, _ := post(`{"name": "Third", "token_ids": [11]}`); code != http.StatusConflict {
+		t.Errorf("team limit: expected 409, got %d", code)
+	}
+
+	_, body := doGet(t, r, "/api/players/"+fixtures.Alice+"/teams")
+	if body["total"] != float64(2) {
+		t.Fatalf("expected 2 teams, got %v", body)
+	}
+	first := body["data"].([]interface{})[0].(map[string]interface{})
+	if first["name"] != "Fire squad" || len(first["missing_token_ids"].([]interface{})) != 0 {
+		t.Errorf("expected the first team fully held, got %v", first)
+	}
+
+	teamPath := fmt.Sprintf("/api/players/%s/teams/%.0f", fixtures.Alice, team["id"].(float64))
+	for _, want := range []int{http.StatusNoContent, http.StatusNotFound} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, teamPath, nil))
+		if w.Code != want {
+			t.Errorf("DELETE %s: expected %d, got %d", teamPath, want, w.Code)
+		}
+	}
+}
+
+func TestFavorites(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := testharness.StartEnvioDB(t)
+	if err := db.SetupAppSchema(); err != nil {
+		t.Fatal(err)
+	}
+	nadmonHandler := NewNadmonHandler(repository.NewNadmonRepository(db))
+	nadmonHandler.SetFavoriteStore(repository.NewFavoriteRepository(db.DB))
+	nadmonHandler.SetLimits(Limits{MaxFavorites: 2})
+
+	r := gin.New()
+	signedIn := func(c *gin.Context) { c.Set(auth.AddressKey, c.Param("address")) }
+	r.GET("/api/players/:address/nadmons", nadmonHandler.GetInventory)
+	r.GET("/api/players/:address/favorites", nadmonHandler.GetFavorites)
+	r.POST("/api/players/:address/favorites/:tokenId", signedIn, nadmonHandler.AddFavorite)
+	r.DELETE("/api/players/:address/favorites/:tokenId", signedIn, nadmonHandler.RemoveFavorite)
+
+	do := func(method, tokenID string) int {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(method, "/api/players/"+fixtures.Alice+"/favorites/"+tokenID, nil))
+		return w.Code
+	}
+
+	tests := []struct {
+		name    string
+		method  string
+		tokenID string
+		want    int
+	}{
+		{"held Nadmon", http.MethodPost, "1", http.StatusCreated},
+		{"starred again", http.MethodPost, "1", http.StatusOK},
+		{"Bob's Nadmon", http.MethodPost, "6", http.StatusCreated},
+		{"favorite limit", http.MethodPost, "2", http.StatusConflict},
+		{"unknown Nadmon", http.MethodPost, "9999", http.StatusNotFound},
+		{"invalid token ID", http.MethodPost, "abc", http.StatusBadRequest},
+		{"not starred", http.MethodDelete, "2", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		if code := do(tt.method, tt.tokenID); code != tt.want {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.want, code)
+		}
+	}
+
+	_, body := doGet(t, r, "/api/players/"+fixtures.Alice+"/favorites")
+	if body["total"] != float64(2) {
+		t.Fatalf("expected 2 favorites, got %v", body)
+	}
+	latest := body["data"].([]interface{})[0].(map[string]interface{})
+	if latest["token_id"] != float64(6) || latest["owner"] != strings.ToLower(fixtures.Bob) || latest["nft"] == nil {
+		t.Errorf("expected Bob's Nadmon first, got %v", latest)
+	}
+
+	// The inventory flags the starred Nadmon, also when selecting fields
+	_, body = doGet(t, r, "/api/players/"+fixtures.Alice+"/nadmons?fields=id,favorited")
+	for _, item := range body["data"].([]interface{}) {
+		nft := item.(map[string]interface{})
+		if want := nft["id"] == float64(1); nft["favorited"] != want {
+			t.Errorf("NFT %v: expected favorited %v, got %v", nft["id"], want, nft["favorited"])
+		}
+	}
+
+	if code := do(http.MethodDelete, "1"); code != http.StatusNoContent {
+		t.Errorf("expected 204, got %d", code)
+	}
+	if code := do(http.MethodPost, "2"); code != http.StatusCreated {
+		t.Errorf("expected room for another favorite, got %d", code)
+	}
+}
+
+// recordingNotifier keeps the messages pushed to players, as "address type"
+type recordingNotifier struct {
+	messages []string
+}
+
+func (n *recordingNotifier) NotifyUser(address string, messageType string, data interface{}) {
+	n.messages = append(n.messages, address+" "+messageType)
+}
+
+// fakeNameResolver resolves the names in its map and their addresses
+type fakeNameResolver map[string]string // address -> name
+
+func (f fakeNameResolver) LookupName(ctx context.Context, address string) (string, error) {
+	return f[address], nil
+}
+
+func (f fakeNameResolver) LookupAddress(ctx context.Context, name string) (string, error) {
+	for address, n := range f {
+		if n == name {
+			return address, nil
+		}
+	}
+	return "", nil
+}
+
+func TestNames(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	nadmonHandler := NewNadmonHandler(repository.NewNadmonRepository(testharness.StartEnvioDB(t)))
+	r := gin.New()
+	r.GET("/api/resolve/:addressOrName", nadmonHandler.ResolveName)
+	r.GET("/api/activity", nadmonHandler.GetActivity)
+	r.GET("/api/packs/recent", nadmonHandler.GetRecentPacks)
+
+	if code, _ := doGet(t, r, "/api/resolve/alice.eth"); code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 without a name service, got %d", code)
+	}
+	nadmonHandler.SetNameService(names.NewService(fakeNameResolver{fixtures.Alice: "alice.eth"}, time.Hour, time.Minute, 10))
+
+	tests := []struct {
+		name    string
+		path    string
+		status  int
+		address interface{}
+		want    interface{}
+	}{
+		{"address with a name", "/api/resolve/" + fixtures.Alice, http.StatusOK, fixtures.Alice, "alice.eth"},
+		{"address without a name", "/api/resolve/" + fixtures.Bob, http.StatusOK, fixtures.Bob, nil},
+		{"name", "/api/resolve/Alice.ETH", http.StatusOK, fixtures.Alice, "alice.eth"},
+		{"unknown name", "/api/resolve/nobody.eth", http.StatusNotFound, nil, nil},
+		{"neither address nor name", "/api/resolve/alice", http.StatusBadRequest, nil, nil},
+	}
+	for _, tt := range tests {
+		code, body := doGet(t, r, tt.path)
+		if code != tt.status {
+			t.Errorf("%s: expected %d, got %d", tt.name, tt.status, code)
+			continue
+		}
+		if code == http.StatusOK && (body["address"] != tt.address || body["name"] != tt.want) {
+			t.Errorf("%s: expected %v named %v, got %v", tt.name, tt.address, tt.want, body)
+		}
+	}
+
+	// Alice's name is cached by now, so her packs carry it
+	_, body := doGet(t, r, "/api/packs/recent")
+	for _, pack := range body["data"].([]interface{}) {
+		pack := pack.(map[string]interface{})
+		if want := map[bool]interface{}{true: "alice.eth", false: nil}[pack["player"] == fixtures.Alice]; pack["display_name"] != want {
+			t.Errorf("expected display_name %v for %v, got %v", want, pack["player"], pack["display_name"])
+		}
+	}
+	_, body = doGet(t, r, "/api/activity?type=pack")
+	for _, activity := range body["data"].([]interface{}) {
+		activity := activity.(map[string]interface{})
+		if activity["player"] == fixtures.Alice && activity["display_name"] != "alice.eth" {
+			t.Errorf("expected alice's activity named, got %v", activity)
+		}
+	}
+}
+
+func TestTrades(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := testharness.StartEnvioDB(t)
+	if err := db.SetupAppSchema(); err != nil {
+		t.Fatal(err)
+	}
+	notifier := &recordingNotifier{}
+	nadmonHandler := NewNadmonHandler(repository.NewNadmonRepository(db))
+	nadmonHandler.SetTradeStore(repository.NewTradeRepository(db.DB, time.Hour), notifier)
+	nadmonHandler.SetLimits(Limits{MaxTradeSize: 2, MaxPendingTrades: 1})
+
+	r := gin.New()
+	signedIn := func(c *gin.Context) { c.Set(auth.AddressKey, c.GetHeader("X-Test-Address")) }
+	trades := r.Group("/api/trades", signedIn)
+	trades.POST("", nadmonHandler.CreateTrade)
+	trades.GET("", nadmonHandler.GetTrades)
+	trades.GET("/:tradeId", nadmonHandler.GetTrade)
+	trades.POST("/:tradeId/accept", nadmonHandler.AcceptTrade)
+	trades.POST("/:tradeId/reject", nadmonHandler.RejectTrade)
+	trades.POST("/:tradeId/cancel", nadmonHandler.CancelTrade)
+
+	do := func(method, path, address, body string) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Test-Address", address)
+		r.ServeHTTP(w, req)
+		var response map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response
+	}
+	propose := func(counterparty, offered, requested string) (int, map[string]interface{}) {
+		body := fmt.Sprintf(`{"counterparty": %q, "offered_token_ids": %s, "requested_token_ids": %s}`, counterparty, offered, requested)
+		return do(http.MethodPost, "/api/trades", fixtures.Alice, body)
+	}
+
+	tests := []struct {
+		name         string
+		counterparty string
+		offered      string
+		requested    string
+	}{
+		{"own address", fixtures.Alice, "[1]", "[2]"},
+		{"nothing requested", fixtures.Bob, "[1]", "[]"},
+		{"too many Nadmons", fixtures.Bob, "[1, 2, 4]", "[6]"},
+		{"listed twice", fixtures.Bob, "[1]", "[1]"},
+		{"Bob's Nadmon offered", fixtures.Bob, "[6]", "[7]"},
+		{"Carol's Nadmon requested", fixtures.Bob, "[1]", "[3]"},
+	}
+	for _, tt := range tests {
+		if code, _ := propose(tt.counterparty, tt.offered, tt.requested); code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", tt.name, code)
+		}
+	}
+
+	code, offer := propose(fixtures.Bob, "[1, 2]", "[6]")
+	if code != http.StatusCreated || offer["status"] != "pending" {
+		t.Fatalf("expected a pending offer, got %d %v", code, offer)
+	}
+	if code, _ := propose(fixtures.Carol, "[4]", "[3]"); code != http.StatusConflict {
+		t.Errorf("pending offer limit: expected 409, got %d", code)
+	}
+
+	tradePath := fmt.Sprintf("/api/trades/%.0f", offer["id"].(float64))
+	if code, _ := do(http.MethodGet, tradePath, fixtures.Carol, ""); code != http.StatusNotFound {
+		t.Errorf("other player: expected 404, got %d", code)
+	}
+	if code, _ := do(http.MethodPost, tradePath+"/accept", fixtures.Alice, ""); code != http.StatusForbidden {
+		t.Errorf("proposer accepting: expected 403, got %d", code)
+	}
+
+	_, received := do(http.MethodGet, "/api/trades?role=received&status=pending", fixtures.Bob, "")
+	if received["total"] != float64(1) {
+		t.Fatalf("expected 1 received offer, got %v", received)
+	}
+	listed := received["data"].([]interface{})[0].(map[string]interface{})
+	if len(listed["missing_token_ids"].([]interface{})) != 0 {
+		t.Errorf("expected every Nadmon still held, got %v", listed)
+	}
+	if _, sent := do(http.MethodGet, "/api/trades?role=sent", fixtures.Bob, ""); sent["total"] != float64(0) {
+		t.Errorf("expected no sent offers, got %v", sent)
+	}
+
+	if code, accepted := do(http.MethodPost, tradePath+"/accept", fixtures.Bob, ""); code != http.StatusOK || accepted["status"] != "accepted" {
+		t.Errorf("expected the offer accepted, got %d %v", code, accepted)
+	}
+	if code, _ := do(http.MethodPost, tradePath+"/reject", fixtures.Bob, ""); code != http.StatusConflict {
+		t.Errorf("answered offer: expected 409, got %d", code)
+	}
+
+	// An answered offer no longer counts against the limit
+	code, offer = propose(fixtures.Carol, "[4]", "[3]")
+	if code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d", code)
+	}
+	tradePath = fmt.Sprintf("/api/trades/%.0f", offer["id"].(float64))
+	if code, _ := do(http.MethodPost, tradePath+"/cancel", fixtures.Carol, ""); code != http.StatusForbidden {
+		t.Errorf("counterparty cancelling: expected 403, got %d", code)
+	}
+	if code, _ := do(http.MethodPost, tradePath+"/cancel", fixtures.Alice, ""); code != http.StatusOK {
+		t.Errorf("expected the offer cancelled, got %d", code)
+	}
+
+	want := []string{
+		fixtures.Bob + " " + models.TypeTradeOffered,
+		fixtures.Alice + " " + models.TypeTradeAccepted,
+		fixtures.Carol + " " + models.TypeTradeOffered,
+		fixtures.Carol + " " + models.TypeTradeCancelled,
+	}
+	if fmt.Sprint(notifier.messages) != fmt.Sprint(want) {
+		t.Errorf("expected messages %v, got %v", want, notifier.messages)
+	}
+}
+
+func TestRarityRank(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := testharness.StartEnvioDB(t)
+	if err := db.SetupAppSchema(); err != nil {
+		t.Fatal(err)
+	}
+	rarity := repository.NewRarityRepository(db.DB)
+	if _, err := rarity.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	nadmonHandler := NewNadmonHandler(repository.NewNadmonRepository(db))
+	nadmonHandler.SetRarityStore(rarity)
+
+	r := gin.New()
+	r.GET("/api/nfts/:tokenId/rank", nadmonHandler.GetNFTRank)
+	r.GET("/api/leaderboard/rarest-nfts", nadmonHandler.GetRarestNFTs)
+
+	code, page := doGet(t, r, "/api/leaderboard/rarest-nfts?limit=5")
+	if code != http.StatusOK || page["total"] != float64(14) || len(page["data"].([]interface{})) != 5 {
+		t.Fatalf("expected 5 of 14 ranked NFTs, got %d %v", code, page)
+	}
+	rarest := page["data"].([]interface{})[0].(map[string]interface{})
+
+	code, rank := doGet(t, r, fmt.Sprintf("/api/nfts/%.0f/rank", rarest["token_id"]))
+	if code != http.StatusOK || rank["rank"] != float64(1) {
+		t.Errorf("expected the rarest NFT at rank 1, got %d %v", code, rank)
+	}
+	if code, _ := doGet(t, r, "/api/nfts/13/rank"); code != http.StatusNotFound {
+		t.Errorf("burned NFT: expected 404, got %d", code)
+	}
+	if code, _ := doGet(t, r, "/api/nfts/abc/rank"); code != http.StatusBadRequest {
+		t.Errorf("invalid token ID: expected 400, got %d", code)
+	}
+}
+
+func TestCompareNFTs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := testharness.StartEnvioDB(t)
+	if err := db.SetupAppSchema(); err != nil {
+		t.Fatal(err)
+	}
+	rarity := repository.NewRarityRepository(db.DB)
+	if _, err := rarity.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	nadmonHandler := NewNadmonHandler(repository.NewNadmonRepository(db))
+	nadmonHandler.SetRarityStore(rarity)
+
+	r := gin.New()
+	r.GET("/api/nfts/compare", nadmonHandler.CompareNFTs)
+
+	// Token 4 fused once, token 2 (the same species) evolved
+	code, body := doGet(t, r, "/api/nfts/compare?ids=4,2")
+	if code != http.StatusOK || body["base"] != float64(4) {
+		t.Fatalf("expected token 4 as the base, got %d %v", code, body)
+	}
+	nadmons := body["nadmons"].([]interface{})
+	fused, evolved := nadmons[0].(map[string]interface{}), nadmons[1].(map[string]interface{})
+	if fused["fusions"] != float64(1) || evolved["evolutions"] != float64(1) || fused["rarity_rank"] == nil {
+		t.Errorf("expected ranked Nadmons with their progression, got %v", nadmons)
+	}
+	delta := body["deltas"].([]interface{})[0].(map[string]interface{})
+	if stats := delta["stats"].(map[string]interface{}); stats["hp"] != float64(25) || delta["power"] != float64(43) {
+		t.Errorf("expected token 2 ahead by 25 hp and 43 power, got %v", delta)
+	}
+	if best := body["best"].(map[string]interface{}); best["attack"] != float64(2) || best["fusion"] != float64(4) {
+		t.Errorf("unexpected best stats %v", best)
+	}
+	history := body["history"].(map[string]interface{})
+	if history["same_species"] != true || len(history["shared_change_types"].([]interface{})) != 0 {
+		t.Errorf("expected the same species with no change type in common, got %v", history)
+	}
+
+	if code, body := doGet(t, r, "/api/nfts/compare?ids=4,13"); code != http.StatusNotFound {
+		t.Errorf("burned NFT: expected 404, got %d %v", code, body)
+	}
+	for _, ids := range []string{"", "4", "4,4", "1,2,3,4,5,6", "4,x"} {
+		if code, _ := doGet(t, r, "/api/nfts/compare?ids="+ids); code != http.StatusBadRequest {
+			t.Errorf("ids %q: expected 400, got %d", ids, code)
+		}
+	}
+}
+
+func TestPlayerRank(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := testharness.StartEnvioDB(t)
+	if err := db.SetupAppSchema(); err != nil {
+		t.Fatal(err)
+	}
+	nadmonHandler := NewNadmonHandler(repository.NewNadmonRepository(db))
+
+	r := gin.New()
+	r.GET("/api/players/:address/rank", nadmonHandler.GetPlayerRank)
+	r.GET("/api/leaderboard/collectors", nadmonHandler.GetLeaderboard)
+
+	if code, _ := doGet(t, r, "/api/players/"+fixtures.Alice+"/rank"); code != http.StatusServiceUnavailable {
+		t.Errorf("without a snapshot: expected 503, got %d", code)
+	}
+
+	standings := repository.NewLeaderboardRepository(db.DB, nil)
+	if _, err := standings.Refresh(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	nadmonHandler.SetLeaderboardStore(standings)
+
+	code, rank := doGet(t, r, "/api/players/"+fixtures.Bob+"/rank")
+	if code != http.StatusOK || rank["rank"] != float64(2) || rank["nft_count"] != float64(5) || rank["rank_delta"] != nil {
+		t.Errorf("expected bob second with no delta yet, got %d %v", code, rank)
+	}
+	code, top := doGet(t, r, "/api/leaderboard/collectors?limit=1")
+	if data, _ := top["data"].([]interface{}); code != http.StatusOK || len(data) != 1 || data[0].(map[string]interface{})["address"] != fixtures.Alice {
+		t.Errorf("expected alice on top of the snapshot, got %d %v", code, top)
+	}
+
+	if code, _ := doGet(t, r, "/api/players/0x1111111111111111111111111111111111111111/rank"); code != http.StatusNotFound {
+		t.Errorf("player without NFTs: expected 404, got %d", code)
+	}
+	if code, _ := doGet(t, r, "/api/players/nope/rank"); code != http.StatusBadRequest {
+		t.Errorf("invalid address: expected 400, got %d", code)
+	}
+}
+
+func TestBattles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	db := testharness.StartEnvioDB(t)
+	if err := db.SetupAppSchema(); err != nil {
+		t.Fatal(err)
+	}
+	nadmonHandler := NewNadmonHandler(repository.NewNadmonRepository(db))
+	nadmonHandler.SetBattleStore(repository.NewBattleRepository(db.DB))
+
+	const secret = "game-server-secret"
+	r := gin.New()
+	r.POST("/api/battles", auth.RequireSignature(secret, time.Minute), nadmonHandler.RecordBattle)
+	r.GET("/api/players/:address/battles", nadmonHandler.GetPlayerBattles)
+	r.GET("/api/leaderboard/pvp", nadmonHandler.GetPvPLeaderboard)
+
+	post := func(body string, signedAt time.Time, key string) (int, map[string]interface{}) {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/battles", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(auth.TimestampHeader, fmt.Sprint(signedAt.Unix()))
+		req.Header.Set(auth.SignatureHeader, auth.Sign(key, signedAt.Unix(), []byte(body)))
+		r.ServeHTTP(w, req)
+		var battle map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &battle)
+		return w.Code, battle
+	}
+
+	battle := fmt.Sprintf(`{"battle_id": "b-1", "player1": {"address": "%s", "team_token_ids": [1, 2]},
+		"player2": {"address": "%s", "team_token_ids": [6]}, "winner": "%s"}`, fixtures.Alice, fixtures.Bob, fixtures.Alice)
+	if code, _ := post(battle, time.Now(), "wrong-secret"); code != http.StatusUnauthorized {
+		t.Errorf("wrong secret: expected 401, got %d", code)
+	}
+	if code, _ := post(battle, time.Now().Add(-time.Hour), secret); code != http.StatusUnauthorized {
+		t.Errorf("stale timestamp: expected 401, got %d", code)
+	}
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"no battle ID", fmt.Sprintf(`{"player1": {"address": "%s"}, "player2": {"address": "%s"}}`, fixtures.Alice, fixtures.Bob)},
+		{"invalid address", fmt.Sprintf(`{"battle_id": "b-x", "player1": {"address": "nope"}, "player2": {"address": "%s"}}`, fixtures.Bob)},
+		{"same player", fmt.Sprintf(`{"battle_id": "b-x", "player1": {"address": "%s"}, "player2": {"address": "%s"}}`, fixtures.Alice, fixtures.Alice)},
+		{"outside winner", fmt.Sprintf(`{"battle_id": "b-x", "player1": {"address": "%s"}, "player2": {"address": "%s"}, "winner": "%s"}`, fixtures.Alice, fixtures.Bob, fixtures.Carol)},
+		{"future battle", fmt.Sprintf(`{"battle_id": "b-x", "player1": {"address": "%s"}, "player2": {"address": "%s"}, "fought_at": "2999-01-01T00:00:00Z"}`, fixtures.Alice, fixtures.Bob)},
+	}
+	for _, tt := range tests {
+		if code, _ := post(tt.body, time.Now(), secret); code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", tt.name, code)
+		}
+	}
+
+	code, recorded := post(battle, time.Now(), secret)
+	if code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d %v", code, recorded)
+	}
+	if player1 := recorded["player1"].(map[string]interface{}); player1["rating_after"] != float64(1216) {
+		t.Errorf("expected alice rated 1216, got %v", recorded)
+	}
+	if code, _ := post(battle, time.Now(), secret); code != http.StatusOK {
+		t.Errorf("retried battle: expected 200, got %d", code)
+	}
+
+	code, body := doGet(t, r, "/api/players/"+fixtures.Bob+"/battles")
+	record, _ := body["record"].(map[string]interface{})
+	if code != http.StatusOK || body["total"] != float64(1) || record["losses"] != float64(1) || record["rating"] != float64(1184) {
+		t.Errorf("expected bob's loss and record, got %d %v", code, body)
+	}
+	if _, body := doGet(t, r, "/api/players/"+fixtures.Carol+"/battles"); body["record"] != nil || body["total"] != float64(0) {
+		t.Errorf("expected no battles for carol, got %v", body)
+	}
+
+	code, body = doGet(t, r, "/api/leaderboard/pvp?address="+fixtures.Bob)
+	data, _ := body["data"].([]interface{})
+	player, _ := body["player"].(map[string]interface{})
+	if code != http.StatusOK || len(data) != 2 || data[0].(map[string]interface{})["address"] != fixtures.Alice || player["rank"] != float64(2) {
+		t.Errorf("expected alice ahead of bob, got %d %v", code, body)
+	}
+	if code, _ := doGet(t, r, "/api/leaderboard/pvp?address=nope"); code != http.StatusBadRequest {
+		t.Errorf("invalid address: expected 400, got %d", code)
+	}
+}
+
+func TestImages(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	png := []byte("\x89PNG\r\n\x1a\nartwork")
+
+	// Stage art on disk; the gateway only has the base Urchin art
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "pyro-ii.png"), png, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var gatewayRequests atomic.Int32
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gatewayRequests.Add(1)
+		if r.URL.Path != "/ipfs/bafytest/urchin-i.png" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "image/png")
+		w.Write(png)
+	}))
+	defer gateway.Close()
+
+	resolver := images.NewResolver(images.Config{
+		Dir:        dir,
+		CID:        "bafytest",
+		Gateways:   []string{"http://127.0.0.1:1/ipfs/", gateway.URL + "/ipfs/"},
+		Timeout:    time.Second,
+		CacheBytes: 1 << 20,
+	})
+	imageHandler := NewImageHandler(repository.NewNadmonRepository(testharness.StartEnvioDB(t)), resolver)
+
+	r := gin.New()
+	r.GET("/api/images/:tokenId", imageHandler.GetImage)
+	get := func(path, ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	// Token 2 is an evolved Pyro: no fusion-specific art, so the stage art is served
+	w := get("/api/images/2?v=ii-f0", "")
+	if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "image/png" || !bytes.Equal(w.Body.Bytes(), png) {
+		t.Fatalf("expected the stage art, got %d %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Header().Get("Cache-Control"), "immutable") {
+		t.Errorf("expected a versioned URL to be immutable, got %q", w.Header().Get("Cache-Control"))
+	}
+	if w := get("/api/images/2", w.Header().Get("ETag")); w.Code != http.StatusNotModified {
+		t.Errorf("expected 304 for a matching ETag, got %d", w.Code)
+	}
+
+	// Token 1 falls back past the unreachable gateway and to the base art; misses are remembered
+	if w := get("/api/images/1", ""); w.Code != http.StatusOK {
+		t.Fatalf("expected the gateway art, got %d %s", w.Code, w.Body.String())
+	}
+	requests := gatewayRequests.Load()
+	if w := get("/api/images/1", ""); w.Code != http.StatusOK || gatewayRequests.Load() != requests {
+		t.Errorf("expected a cached image, got %d after %d more gateway requests", w.Code, gatewayRequests.Load()-requests)
+	}
+
+	if w := get("/api/images/13", ""); w.Code != http.StatusNotFound {
+		t.Errorf("burned NFT: expected 404, got %d", w.Code)
+	}
+	if w := get("/api/images/abc", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid token ID: expected 400, got %d", w.Code)
+	}
+}
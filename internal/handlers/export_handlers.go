@@ -0,0 +1,232 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"nadmon-backend/internal/ethaddr"
+	"nadmon-backend/internal/export"
+	"nadmon-backend/internal/logging"
+	"nadmon-backend/internal/models"
+	"nadmon-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// exportBatchSize is how many records each repository call reads while streaming an export
+const exportBatchSize = 1000
+
+// Player export record types, in the record column
+const (
+	exportRecordNadmon   = "nadmon"
+	exportRecordActivity = "activity"
+)
+
+// playerExportColumns are the columns of a player export: a nadmon record per held Nadmon
+// followed by an activity record per entry of the player's history, newest first. Each
+// record type leaves the other's columns empty.
+var playerExportColumns = []string{
+	"record", "token_id", "pack_id",
+	"nadmon_type", "element", "rarity", "hp", "attack", "defense", "crit", "fusion", "evo", "created_at", "last_updated",
+	"activity_id", "activity_type", "counterparty", "detail", "occurred_at",
+}
+
+// snapshotColumns are the columns of a full-collection snapshot, one record per circulating Nadmon
+var snapshotColumns = []string{
+	"token_id", "owner", "pack_id",
+	"nadmon_type", "element", "rarity", "hp", "attack", "defense", "crit", "fusion", "evo", "last_updated",
+}
+
+// holderSnapshotColumns are the columns of a holder snapshot export, one record per holder
+var holderSnapshotColumns = []string{"address", "balance", "token_ids"}
+
+// ExportPlayer streams a player's inventory and activity history as CSV or NDJSON
+func (h *NadmonHandler) ExportPlayer(c *gin.Context) {
+	address := c.Param("address")
+	if !isValidEthereumAddress(address) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Ethereum address"})
+		return
+	}
+	address = ethaddr.Normalize(address)
+
+	format, ok := bindExportFormat(c)
+	if !ok {
+		return
+	}
+
+	// The inventory is read before the response starts, so a failing database still gets a 500.
+	// Exports read in bulk, so they go to the read replica when there is one.
+	store := h.store(c)
+	ctx := repository.PreferReplica(c.Request.Context())
+	nadmons, err := store.GetPlayerNadmons(ctx, address)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch NFTs: " + err.Error()})
+		return
+	}
+
+	w := startExport(c, format, "nadmon-"+address, playerExportColumns)
+	for _, n := range nadmons {
+		w.Write(exportRecordNadmon, n.TokenID, n.PackID,
+			n.NadmonType, n.Element, n.Rarity, n.HP, n.Attack, n.Defense, n.Crit, n.Fusion, n.Evo, n.CreatedAt, n.LastUpdated,
+			nil, nil, nil, nil, nil)
+	}
+
+	for offset := 0; ; offset += exportBatchSize {
+		if !flushExport(c, w) {
+			return
+		}
+		page, err := store.GetPlayerActivity(ctx, address, models.PlayerActivityTypes, exportBatchSize, offset)
+		if err != nil {
+			abortExport(c, w, err)
+			return
+		}
+		for _, a := range page.Activities {
+			w.Write(exportRecordActivity, optionalID(a.TokenID), optionalID(a.PackID),
+				nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+				a.ID, a.Type, optionalString(a.Counterparty), a.Detail, a.OccurredAt)
+		}
+		if len(page.Activities) < exportBatchSize {
+			break
+		}
+	}
+	flushExport(c, w)
+}
+
+// ExportSnapshot streams every circulating Nadmon with its owner as CSV or NDJSON, e.g. to
+// compute airdrops. The collection is read in batches by token ID, so each row reflects the
+// owner at the time its batch was read.
+func (h *NadmonHandler) ExportSnapshot(c *gin.Context) {
+	format, ok := bindExportFormat(c)
+	if !ok {
+		return
+	}
+
+	store := h.store(c)
+	ctx := c.Request.Context()
+	batch, err := store.GetNadmonSnapshot(ctx, -1, exportBatchSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch NFTs: " + err.Error()})
+		return
+	}
+
+	w := startExport(c, format, "nadmon-snapshot-"+time.Now().UTC().Format("20060102T150405Z"), snapshotColumns)
+	for {
+		for _, n := range batch {
+			w.Write(n.TokenID, n.Owner, n.PackID,
+				n.NadmonType, n.Element, n.Rarity, n.HP, n.Attack, n.Defense, n.Crit, n.Fusion, n.Evo, n.LastUpdated)
+		}
+		if len(batch) < exportBatchSize || !flushExport(c, w) {
+			break
+		}
+
+		batch, err = store.GetNadmonSnapshot(ctx, batch[len(batch)-1].TokenID, exportBatchSize)
+		if err != nil {
+			abortExport(c, w, err)
+			return
+		}
+	}
+	flushExport(c, w)
+}
+
+// GetHolderSnapshot returns every holder with their balance and token IDs as of ?block_time=
+// (RFC 3339, YYYY-MM-DD or Unix seconds; now by default), replayed from the transfers indexed
+// by then, e.g. for airdrops and governance votes. ?format= is json (default), csv or ndjson.
+func (h *NadmonHandler) GetHolderSnapshot(c *gin.Context) {
+	asOf := time.Now().UTC()
+	if value := c.Query("block_time"); value != "" {
+		parsed, err := parseBlockTime(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid block_time, expected RFC 3339, YYYY-MM-DD or Unix seconds"})
+			return
+		}
+		asOf = parsed
+	}
+	format := strings.ToLower(c.DefaultQuery("format", "json"))
+	if format != "json" && !export.ValidFormat(format) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format, expected json, csv or ndjson"})
+		return
+	}
+
+	snapshot, err := h.store(c).GetHolderSnapshot(c.Request.Context(), asOf)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch holder snapshot: " + err.Error()})
+		return
+	}
+
+	if format == "json" {
+		c.JSON(http.StatusOK, snapshot)
+		return
+	}
+	w := startExport(c, format, "nadmon-holders-"+asOf.Format("20060102T150405Z"), holderSnapshotColumns)
+	for _, holder := range snapshot.Holders {
+		w.Write(holder.Address, holder.Balance, holder.TokenIDs)
+	}
+	flushExport(c, w)
+}
+
+// parseBlockTime parses a block timestamp in Unix seconds, or a time accepted by parseTimeParam
+func parseBlockTime(value string) (time.Time, error) {
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0).UTC(), nil
+	}
+	return parseTimeParam(value)
+}
+
+// bindExportFormat reads the format query parameter, csv by default; an unknown format
+// aborts with 400
+func bindExportFormat(c *gin.Context) (string, bool) {
+	format := strings.ToLower(c.DefaultQuery("format", export.FormatCSV))
+	if !export.ValidFormat(format) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format, expected csv or ndjson"})
+		return "", false
+	}
+	return format, true
+}
+
+// startExport sends the headers of a download named filename and returns a writer for its body
+func startExport(c *gin.Context, format, filename string, columns []string) *export.Writer {
+	c.Header("Content-Type", export.ContentType(format))
+	c.Header("Content-Disposition", `attachment; filename="`+filename+"."+format+`"`)
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+
+	// The format was validated, so this cannot fail
+	w, _ := export.NewWriter(c.Writer, format, columns)
+	return w
+}
+
+// flushExport sends the records written so far to the client and reports whether it is
+// still there
+func flushExport(c *gin.Context, w *export.Writer) bool {
+	if err := w.Flush(); err != nil {
+		return false
+	}
+	c.Writer.Flush()
+	return c.Request.Context().Err() == nil
+}
+
+// abortExport ends an export that failed after the response started. Headers are already
+// sent, so the body is cut short after the last complete record.
+func abortExport(c *gin.Context, w *export.Writer, err error) {
+	logging.FromContext(c.Request.Context()).Error("❌ Export failed", "path", c.Request.URL.Path, "error", err)
+	c.Error(err)
+	flushExport(c, w)
+}
+
+// optionalID leaves unset (zero) IDs out of an export
+func optionalID(id int64) interface{} {
+	if id == 0 {
+		return nil
+	}
+	return id
+}
+
+// optionalString leaves empty strings out of an export
+func optionalString(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"nadmon-backend/internal/auth"
+	"nadmon-backend/internal/models"
+	"nadmon-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetFavoriteStore enables the favorite endpoints and the favorited flag of inventories,
+// saving favorites to store
+func (h *NadmonHandler) SetFavoriteStore(store repository.FavoriteStore) {
+	h.favorites = store
+}
+
+// GetFavorites returns the Nadmons a player starred, most recently starred first, each with
+// its current owner and stats
+func (h *NadmonHandler) GetFavorites(c *gin.Context) {
+	if h.favorites == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Favorites are not available"})
+		return
+	}
+
+	address := c.Param("address")
+	if !isValidEthereumAddress(address) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Ethereum address format"})
+		return
+	}
+
+	favorites, err := h.favorites.GetFavorites(c.Request.Context(), address)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch favorites: " + err.Error()})
+		return
+	}
+
+	tokenIDs := make([]int64, len(favorites))
+	for i, favorite := range favorites {
+		tokenIDs[i] = favorite.TokenID
+	}
+	nadmons, err := h.store(c).GetNadmonsByIDs(c.Request.Context(), tokenIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch NFTs: " + err.Error()})
+		return
+	}
+	byID := make(map[int64]*models.Nadmon, len(nadmons))
+	for i := range nadmons {
+		if !strings.EqualFold(nadmons[i].Owner, models.ZeroAddress) {
+			byID[nadmons[i].TokenID] = &nadmons[i]
+		}
+	}
+	for i := range favorites {
+		if nadmon, ok := byID[favorites[i].TokenID]; ok {
+			favorites[i].Owner = nadmon.Owner
+			favorites[i].NFT = nadmon.ToFrontendFormat()
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  favorites,
+		"total": len(favorites),
+	})
+}
+
+// AddFavorite stars a Nadmon for the authenticated player; it doesn't need to be theirs, so
+// favorites double as a wishlist
+func (h *NadmonHandler) AddFavorite(c *gin.Context) {
+	if h.favorites == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Favorites are not available"})
+		return
+	}
+
+	tokenID, err := strconv.ParseInt(c.Param("tokenId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid token ID"})
+		return
+	}
+
+	nadmons, err := h.store(c).GetNadmonsByIDs(c.Request.Context(), []int64{tokenID})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch NFT: " + err.Error()})
+		return
+	}
+	if len(nadmons) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "NFT not found"})
+		return
+	}
+
+	favorite, created, err := h.favorites.AddFavorite(c.Request.Context(), c.GetString(auth.AddressKey), tokenID, h.limits.MaxFavorites)
+	switch {
+	case errors.Is(err, repository.ErrFavoriteLimit):
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Favorite limit reached (max %d)", h.limits.MaxFavorites)})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save favorite: " + err.Error()})
+		return
+	}
+	if !strings.EqualFold(nadmons[0].Owner, models.ZeroAddress) {
+		favorite.Owner = nadmons[0].Owner
+		favorite.NFT = nadmons[0].ToFrontendFormat()
+	}
+
+	status := http.StatusCreated
+	if !created {
+		status = http.StatusOK
+	}
+	c.JSON(status, favorite)
+}
+
+// RemoveFavorite unstars a Nadmon for the authenticated player
+func (h *NadmonHandler) RemoveFavorite(c *gin.Context) {
+	if h.favorites == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Favorites are not available"})
+		return
+	}
+
+	tokenID, err := strconv.ParseInt(c.Param("tokenId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid token ID"})
+		return
+	}
+
+	found, err := h.favorites.RemoveFavorite(c.Request.Context(), c.GetString(auth.AddressKey), tokenID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete favorite: " + err.Error()})
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Favorite not found"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// favoritedTokens returns the token IDs address starred, for flagging them in its inventory.
+// Favorites are of the default collection, so other collections and chains get nil, as do
+// requests failing to read them: the flag is left out rather than failing the inventory.
+func (h *NadmonHandler) favoritedTokens(c *gin.Context, address string) map[int64]bool {
+	if _, scoped := c.Get(CollectionKey); h.favorites == nil || scoped {
+		return nil
+	}
+
+	favorites, err := h.favorites.GetFavorites(c.Request.Context(), address)
+	if err != nil {
+		log.Printf("Warning: %v", err)
+		return nil
+	}
+	favorited := make(map[int64]bool, len(favorites))
+	for _, favorite := range favorites {
+		favorited[favorite.TokenID] = true
+	}
+	return favorited
+}
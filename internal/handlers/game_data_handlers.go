@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"nadmon-backend/internal/gamedata"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GameDataHandler struct {
+	path string
+}
+
+// NewGameDataHandler creates a handler serving the game data, reloaded from path (the
+// embedded defaults when empty)
+func NewGameDataHandler(path string) *GameDataHandler {
+	return &GameDataHandler{path: path}
+}
+
+// GetGameData returns the game data in use: element colors, element matchups and the
+// formulas of derived stats, so clients compute them the way the API does
+func (h *GameDataHandler) GetGameData(c *gin.Context) {
+	data := gamedata.Current()
+	c.JSON(http.StatusOK, gin.H{
+		"version":      data.Version,
+		"defaultColor": data.DefaultColor,
+		"elements":     data.Elements,
+		"matchups":     data.Matchups,
+		"derived":      data.Derived,
+		"variables":    gamedata.Variables,
+	})
+}
+
+// ReloadGameData re-reads the game data file. Invalid data is rejected and the data in use
+// is kept.
+func (h *GameDataHandler) ReloadGameData(c *gin.Context) {
+	previous := gamedata.Current()
+	data, err := gamedata.Load(h.path)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to reload game data: " + err.Error()})
+		return
+	}
+	gamedata.Set(data)
+	log.Printf("🎮 Game data reloaded: version %s (was %s) from %s", data.Version, previous.Version, data.Source)
+
+	c.JSON(http.StatusOK, gin.H{
+		"version":          data.Version,
+		"previous_version": previous.Version,
+		"source":           data.Source,
+		"elements":         len(data.Elements),
+	})
+}
@@ -0,0 +1,37 @@
+package handlers
+
+import (
+	"net/http"
+
+	"nadmon-backend/internal/i18n"
+
+	"github.com/gin-gonic/gin"
+)
+
+type I18nHandler struct {
+	catalogs i18n.Catalogs
+}
+
+// NewI18nHandler creates a new handler serving the embedded label catalogs
+func NewI18nHandler(catalogs i18n.Catalogs) *I18nHandler {
+	return &I18nHandler{catalogs: catalogs}
+}
+
+// GetCatalog returns translated labels for a locale. Unknown locales are negotiated
+// from Accept-Language before falling back to English.
+func (h *I18nHandler) GetCatalog(c *gin.Context) {
+	catalog := h.catalogs.Negotiate(c.Param("locale"), c.GetHeader("Accept-Language"))
+
+	c.Header("Content-Language", catalog.Locale)
+	c.Header("Vary", "Accept-Language")
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.JSON(http.StatusOK, gin.H{
+		"locale":       catalog.Locale,
+		"name":         catalog.Name,
+		"available":    h.catalogs.Locales(),
+		"elements":     catalog.Elements,
+		"rarities":     catalog.Rarities,
+		"changeTypes":  catalog.ChangeTypes,
+		"achievements": catalog.Achievements,
+	})
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"nadmon-backend/internal/images"
+	"nadmon-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ImageHandler struct {
+	repo     repository.Store
+	resolver *images.Resolver
+}
+
+// NewImageHandler creates a new handler serving NFT artwork from resolver
+func NewImageHandler(repo repository.Store, resolver *images.Resolver) *ImageHandler {
+	return &ImageHandler{repo: repo, resolver: resolver}
+}
+
+// GetImage serves a token's artwork for its current evolution stage and fusion level,
+// falling back to its stage art and then its base art
+func (h *ImageHandler) GetImage(c *gin.Context) {
+	if !h.resolver.Enabled() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "NFT images are not available"})
+		return
+	}
+
+	tokenID, err := strconv.ParseInt(c.Param("tokenId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid token ID"})
+		return
+	}
+
+	nadmon, err := storeFor(c, h.repo).GetSingleNadmon(c.Request.Context(), tokenID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch NFT: " + err.Error()})
+		return
+	}
+	if nadmon == nil {
+		c.Header("Cache-Control", "public, max-age=60")
+		c.JSON(http.StatusNotFound, gin.H{"error": "NFT not found"})
+		return
+	}
+
+	img, err := h.resolver.Resolve(c.Request.Context(), nadmon.ArtworkNames())
+	if errors.Is(err, images.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No artwork for " + nadmon.NadmonType})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to fetch artwork: " + err.Error()})
+		return
+	}
+
+	// A versioned URL (from GetImageURL) always shows the same artwork; unversioned ones
+	// change on evolution and fusion
+	if c.Query("v") == nadmon.ArtworkVersion() {
+		c.Header("Cache-Control", "public, max-age=31536000, immutable")
+	} else {
+		c.Header("Cache-Control", "public, max-age=300")
+	}
+	c.Header("ETag", img.ETag)
+	if matches := c.GetHeader("If-None-Match"); matches == img.ETag || matches == "W/"+img.ETag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	// Artwork may be SVG from a third-party gateway: never let it run scripts on this origin
+	c.Header("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'; sandbox")
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Data(http.StatusOK, img.ContentType, img.Data)
+}
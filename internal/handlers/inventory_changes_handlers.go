@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetInventoryChanges returns what changed in a player's inventory after ?since_sequence= (0,
+// the default, returns the full inventory): the Nadmons they hold that were minted, received or
+// changed stats, and the token IDs they no longer hold. Clients pass the returned sequence as
+// the next since_sequence; a change may be reported twice but is never missed.
+func (h *NadmonHandler) GetInventoryChanges(c *gin.Context) {
+	address := c.Param("address")
+	if !isValidEthereumAddress(address) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Ethereum address format"})
+		return
+	}
+
+	since, err := strconv.ParseInt(c.DefaultQuery("since_sequence", "0"), 10, 64)
+	if err != nil || since < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid since_sequence, expected a sequence returned by a previous call"})
+		return
+	}
+
+	fields, err := parseFields(c.Query("fields"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid fields, " + err.Error()})
+		return
+	}
+
+	changes, err := h.store(c).GetInventoryChanges(c.Request.Context(), address, since)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch inventory changes: " + err.Error()})
+		return
+	}
+
+	var favorited map[int64]bool
+	if hasField(fields, "favorited") {
+		favorited = h.favoritedTokens(c, address)
+	}
+
+	nfts := make([]map[string]interface{}, len(changes.Changed))
+	for i := range changes.Changed {
+		nfts[i] = inventoryItem(&changes.Changed[i], fields, favorited)
+	}
+	removed := changes.Removed
+	if removed == nil {
+		removed = []int64{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":           nfts,
+		"removed":        removed,
+		"sequence":       changes.Sequence,
+		"since_sequence": changes.SinceSequence,
+		"total":          len(nfts),
+	})
+}
@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+
+	"nadmon-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetLeaderboardStore serves the collector leaderboard and player ranks from store's snapshot
+func (h *NadmonHandler) SetLeaderboardStore(store repository.LeaderboardStore) {
+	h.leaderboard = store
+}
+
+// GetPlayerRank returns a player's collector rank as of the last leaderboard snapshot and how
+// it moved since the snapshot before
+func (h *NadmonHandler) GetPlayerRank(c *gin.Context) {
+	if h.leaderboard == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Player ranks are not available"})
+		return
+	}
+
+	address := c.Param("address")
+	if !isValidEthereumAddress(address) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Ethereum address"})
+		return
+	}
+
+	rank, err := h.leaderboard.GetCollectorRank(c.Request.Context(), address)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch player rank: " + err.Error()})
+		return
+	}
+	if rank == nil {
+		// Players holding no NFTs (or excluded ones) aren't ranked
+		c.JSON(http.StatusNotFound, gin.H{"error": "Player is not ranked"})
+		return
+	}
+
+	rank.Display = withDisplay(h.displayProfiles(c, []string{rank.Address}), rank.Address)
+	c.JSON(http.StatusOK, rank)
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"nadmon-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Fusion lineage limits: ?depth= levels of consumed Nadmons, and how many Nadmons a lineage
+// reads fusions for before it is cut short
+const (
+	defaultLineageDepth = 5
+	maxLineageDepth     = 10
+	maxLineageNodes     = 500
+)
+
+// GetNFTLineage returns the tree of Nadmons consumed to build a Nadmon: its fusions with the
+// Nadmons each one burned, then theirs, down to ?depth= levels. Fusions and burns aren't
+// linked on chain, so they are correlated by owner, species and indexing time.
+func (h *NadmonHandler) GetNFTLineage(c *gin.Context) {
+	tokenID, err := strconv.ParseInt(c.Param("tokenId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid token ID"})
+		return
+	}
+
+	depth, err := strconv.Atoi(c.DefaultQuery("depth", strconv.Itoa(defaultLineageDepth)))
+	if err != nil || depth < 1 || depth > maxLineageDepth {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid depth, expected 1 to %d", maxLineageDepth)})
+		return
+	}
+
+	nadmon, err := h.store(c).GetSingleNadmon(c.Request.Context(), tokenID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch NFT: " + err.Error()})
+		return
+	}
+	if nadmon == nil {
+		statuses, err := h.store(c).GetNadmonStatuses(c.Request.Context(), []int64{tokenID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch NFT status: " + err.Error()})
+			return
+		}
+		if statuses[tokenID].Status == models.StatusBurned {
+			c.JSON(http.StatusGone, gin.H{"error": "NFT was burned", "burned": true})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": "NFT not found"})
+		return
+	}
+
+	// Fusions are read a level at a time, one level past depth to tell whether the tree goes on
+	fusions := make(map[int64][]models.Fusion)
+	seen := map[int64]bool{tokenID: true}
+	level := []int64{tokenID}
+	for d := 0; d <= depth && len(level) > 0; d++ {
+		found, err := h.store(c).GetFusions(c.Request.Context(), level)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch NFT fusions: " + err.Error()})
+			return
+		}
+
+		level = nil
+		for _, fusion := range found {
+			fusions[fusion.TokenID] = append(fusions[fusion.TokenID], fusion)
+			for _, consumed := range fusion.Consumed {
+				if !seen[consumed.TokenID] {
+					seen[consumed.TokenID] = true
+					level = append(level, consumed.TokenID)
+				}
+			}
+		}
+		if len(seen) > maxLineageNodes && d < depth {
+			depth = d + 1
+		}
+	}
+
+	c.JSON(http.StatusOK, models.NewLineage(nadmon, fusions, depth))
+}
@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"nadmon-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MetadataHandler struct {
+	repo    repository.Store
+	baseURL string
+}
+
+// NewMetadataHandler creates a new handler serving ERC-721 metadata; image URLs are built on baseURL
+func NewMetadataHandler(repo repository.Store, baseURL string) *MetadataHandler {
+	return &MetadataHandler{repo: repo, baseURL: baseURL}
+}
+
+// GetMetadata returns standard ERC-721 JSON metadata for a token
+func (h *MetadataHandler) GetMetadata(c *gin.Context) {
+	tokenID, err := strconv.ParseInt(c.Param("tokenId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid token ID"})
+		return
+	}
+
+	nadmon, err := h.store(c).GetSingleNadmon(c.Request.Context(), tokenID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch NFT: " + err.Error()})
+		return
+	}
+
+	// Burned and unknown tokens have no metadata
+	if nadmon == nil {
+		c.Header("Cache-Control", "public, max-age=60")
+		c.JSON(http.StatusNotFound, gin.H{"error": "NFT not found"})
+		return
+	}
+
+	// Stats change on evolution and fusion, so metadata is only cached briefly
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, nadmon.ToERC721Metadata(h.baseURL))
+}
+
+// store returns the storage backend to use for this request
+func (h *MetadataHandler) store(c *gin.Context) repository.Store {
+	return storeFor(c, h.repo)
+}
@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"nadmon-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NameService resolves name service (ENS-style) names of addresses
+type NameService interface {
+	// Names returns the already cached names of addresses, keyed by lowercased address,
+	// without waiting on the name service
+	Names(addresses []string) map[string]string
+	LookupName(ctx context.Context, address string) (string, time.Time, error)
+	LookupAddress(ctx context.Context, name string) (string, time.Time, error)
+}
+
+// SetNameService adds name service names to leaderboards, activity feeds and recent packs,
+// and enables the resolve endpoint
+func (h *NadmonHandler) SetNameService(names NameService) {
+	h.names = names
+}
+
+// namePattern matches dotted names like alice.eth or bob.nad
+var namePattern = regexp.MustCompile(`^[^\s.]+(\.[^\s.]+)+$`)
+
+// maxNameLength caps the length of names accepted by the resolve endpoint
+const maxNameLength = 255
+
+// ResolveName resolves an address to its primary name, or a name to its address
+func (h *NadmonHandler) ResolveName(c *gin.Context) {
+	if h.names == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Name resolution is not available"})
+		return
+	}
+
+	query := c.Param("addressOrName")
+	if isValidEthereumAddress(query) {
+		address := strings.ToLower(query)
+		name, resolvedAt, err := h.names.LookupName(c.Request.Context(), address)
+		if err != nil {
+			c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to resolve name: " + err.Error()})
+			return
+		}
+
+		resolution := models.NameResolution{Address: &address, ResolvedAt: resolvedAt}
+		if name != "" {
+			resolution.Name = &name
+		}
+		c.JSON(http.StatusOK, resolution)
+		return
+	}
+
+	if len(query) > maxNameLength || !namePattern.MatchString(query) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid address or name, expected a 0x address or a name like alice.eth"})
+		return
+	}
+
+	name := strings.ToLower(query)
+	address, resolvedAt, err := h.names.LookupAddress(c.Request.Context(), name)
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error": "Failed to resolve address: " + err.Error()})
+		return
+	}
+	if address == "" {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Name does not resolve to an address"})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NameResolution{Address: &address, Name: &name, ResolvedAt: resolvedAt})
+}
+
+// displayNames returns the cached names of addresses, or nil without a name service
+func (h *NadmonHandler) displayNames(addresses []string) map[string]string {
+	if h.names == nil || len(addresses) == 0 {
+		return nil
+	}
+	return h.names.Names(addresses)
+}
+
+// withActivityNames returns copies of activities with the names of their players and
+// counterparties; store results may be shared with other requests
+func (h *NadmonHandler) withActivityNames(activities []models.Activity) []models.Activity {
+	if h.names == nil {
+		return activities
+	}
+
+	addresses := make([]string, 0, 2*len(activities))
+	for _, activity := range activities {
+		addresses = append(addresses, activity.Player, activity.Counterparty)
+	}
+	names := h.displayNames(addresses)
+
+	decorated := make([]models.Activity, len(activities))
+	for i, activity := range activities {
+		activity.DisplayName = names[strings.ToLower(activity.Player)]
+		activity.CounterpartyDisplayName = names[strings.ToLower(activity.Counterparty)]
+		decorated[i] = activity
+	}
+	return decorated
+}
+
+// withPackNames returns copies of packs with the names of their buyers
+func (h *NadmonHandler) withPackNames(packs []models.Pack) []models.Pack {
+	if h.names == nil {
+		return packs
+	}
+
+	addresses := make([]string, len(packs))
+	for i, pack := range packs {
+		addresses[i] = pack.Player
+	}
+	names := h.displayNames(addresses)
+
+	decorated := make([]models.Pack, len(packs))
+	for i, pack := range packs {
+		pack.DisplayName = names[strings.ToLower(pack.Player)]
+		decorated[i] = pack
+	}
+	return decorated
+}
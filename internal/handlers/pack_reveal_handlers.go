@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"nadmon-backend/internal/models"
+	"nadmon-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetPackReveal returns a pack's Nadmons as cards for its opening animation, ordered by
+// rarity from Common up so the best pull comes last. ?revealed=N opens the first N cards; the
+// others are sealed and carry nothing but their position, so they can't spoil the reveal.
+// Until every Nadmon of the pack is indexed ready is false and no card is returned; the
+// buyer gets a pack_ready WebSocket message the moment they are.
+func (h *NadmonHandler) GetPackReveal(c *gin.Context) {
+	packID, err := strconv.ParseInt(c.Param("packId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pack ID"})
+		return
+	}
+
+	pack, err := h.store(c).GetPackByID(c.Request.Context(), packID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pack: " + err.Error()})
+		return
+	}
+	if pack == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pack not found"})
+		return
+	}
+
+	revealed, err := strconv.Atoi(c.DefaultQuery("revealed", "0"))
+	if err != nil || revealed < 0 || revealed > len(pack.TokenIDs) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid revealed, expected 0 to %d", len(pack.TokenIDs))})
+		return
+	}
+
+	nadmons, err := h.store(c).GetNadmonsByIDs(c.Request.Context(), pack.TokenIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pack NFTs: " + err.Error()})
+		return
+	}
+
+	// Missing Nadmons were either burned since or aren't indexed yet
+	burned := []int64{}
+	indexed := len(nadmons)
+	if len(nadmons) < len(pack.TokenIDs) {
+		statuses, err := h.store(c).GetNadmonStatuses(c.Request.Context(), pack.TokenIDs)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pack NFTs: " + err.Error()})
+			return
+		}
+		for _, tokenID := range pack.TokenIDs {
+			if statuses[tokenID].Status == models.StatusBurned {
+				burned = append(burned, tokenID)
+				indexed++
+			}
+		}
+	}
+	ready := indexed == len(pack.TokenIDs)
+
+	cards := []gin.H{}
+	if ready {
+		sort.SliceStable(nadmons, func(i, j int) bool {
+			ri := repository.SearchSortKey(nadmons[i], repository.SortByRarity)
+			rj := repository.SearchSortKey(nadmons[j], repository.SortByRarity)
+			if ri != rj {
+				return ri < rj
+			}
+			return nadmons[i].TokenID < nadmons[j].TokenID
+		})
+		for i := range nadmons {
+			card := gin.H{"position": i + 1, "sealed": i >= revealed}
+			if i < revealed {
+				card["token_id"] = nadmons[i].TokenID
+				card["nft"] = nadmons[i].ToFrontendFormat()
+			}
+			cards = append(cards, card)
+		}
+	} else {
+		revealed = 0
+	}
+
+	opened := revealed
+	if opened > len(cards) {
+		opened = len(cards)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"pack_id":          pack.PackID,
+		"player":           pack.Player,
+		"payment_type":     pack.PaymentType,
+		"purchased_at":     pack.PurchasedAt,
+		"ready":            ready,
+		"indexed":          indexed,
+		"total_cards":      len(pack.TokenIDs),
+		"revealed":         opened,
+		"remaining":        len(cards) - opened,
+		"cards":            cards,
+		"burned_token_ids": burned,
+	})
+}
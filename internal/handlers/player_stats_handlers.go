@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"nadmon-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetPlayerStatsStore serves player stats of the default collection from store's aggregates
+func (h *NadmonHandler) SetPlayerStatsStore(store repository.PlayerStatsStore) {
+	h.playerStats = store
+}
+
+// aggregatedStats returns a player's stats in the GetStats shape from the aggregate table, or
+// nil when they must be computed: for other collections, with noCache and for players the
+// table doesn't have yet
+func (h *NadmonHandler) aggregatedStats(c *gin.Context, address string) (gin.H, error) {
+	if _, scoped := c.Get(CollectionKey); h.playerStats == nil || scoped || noCache(c) {
+		return nil, nil
+	}
+
+	stats, err := h.playerStats.GetPlayerStats(c.Request.Context(), address)
+	if err != nil || stats == nil {
+		return nil, err
+	}
+
+	aggregated := gin.H{
+		"address":             stats.Address,
+		"totalNFTs":           stats.TotalNFTs,
+		"packsBought":         stats.PacksBought,
+		"lastActivity":        stats.LastActive,
+		"evolutionsPerformed": stats.EvolutionsPerformed,
+		"rarestOwned":         stats.RarestOwned,
+		"updatedAt":           stats.UpdatedAt,
+	}
+	if stats.TotalNFTs > 0 {
+		aggregated["rarityStats"] = stats.RarityCounts
+		aggregated["elementStats"] = stats.ElementCounts
+		aggregated["evolvedNFTs"] = stats.EvolvedNFTs
+	}
+	return aggregated, nil
+}
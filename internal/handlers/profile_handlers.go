@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"nadmon-backend/internal/auth"
+	"nadmon-backend/internal/models"
+	"nadmon-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// nicknamePattern allows letters, digits, spaces, dots, dashes and underscores
+var nicknamePattern = regexp.MustCompile(`^[\p{L}\p{N} ._-]+$`)
+
+// DisplayProfileRequest is the body of PUT /players/:address/profile; it replaces the whole
+// display profile, so omitted fields are cleared
+type DisplayProfileRequest struct {
+	Nickname      string `json:"nickname"`
+	AvatarTokenID *int64 `json:"avatar_token_id"`
+	Bio           string `json:"bio"`
+}
+
+// SetProfileStore merges display profiles from store into profile and leaderboard responses
+func (h *NadmonHandler) SetProfileStore(store repository.ProfileStore) {
+	h.profiles = store
+}
+
+// UpdateDisplayProfile sets the authenticated player's nickname, avatar and bio
+func (h *NadmonHandler) UpdateDisplayProfile(c *gin.Context) {
+	if h.profiles == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Display profiles are not available"})
+		return
+	}
+
+	var req DisplayProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	address := c.GetString(auth.AddressKey)
+	profile := models.DisplayProfile{Address: address, AvatarTokenID: req.AvatarTokenID, Bio: strings.TrimSpace(req.Bio)}
+
+	if nickname := strings.Join(strings.Fields(req.Nickname), " "); nickname != "" {
+		length := utf8.RuneCountInString(nickname)
+		if length < models.MinNicknameLength || length > models.MaxNicknameLength || !nicknamePattern.MatchString(nickname) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid nickname, expected 3-24 letters, digits, spaces, dots, dashes or underscores"})
+			return
+		}
+		profile.Nickname = &nickname
+	}
+	if utf8.RuneCountInString(profile.Bio) > models.MaxBioLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bio, expected at most 280 characters"})
+		return
+	}
+
+	// The avatar must be a Nadmon the player holds
+	if profile.AvatarTokenID != nil {
+		nadmon, err := h.store(c).GetSingleNadmon(c.Request.Context(), *profile.AvatarTokenID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch NFT: " + err.Error()})
+			return
+		}
+		if nadmon == nil || !strings.EqualFold(nadmon.Owner, address) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid avatar_token_id, expected a Nadmon held by this address"})
+			return
+		}
+	}
+
+	saved, err := h.profiles.SaveDisplayProfile(c.Request.Context(), profile)
+	if errors.Is(err, repository.ErrNicknameTaken) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Nickname is already taken"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save profile: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, saved)
+}
+
+// displayProfiles looks up the display profiles of addresses. Display profiles only
+// decorate responses, so a failed lookup is logged and the response is served without them.
+func (h *NadmonHandler) displayProfiles(c *gin.Context, addresses []string) map[string]models.DisplayProfile {
+	if h.profiles == nil || len(addresses) == 0 {
+		return nil
+	}
+
+	profiles, err := h.profiles.GetDisplayProfiles(c.Request.Context(), addresses)
+	if err != nil {
+		log.Printf("Warning: %v", err)
+		return nil
+	}
+	return profiles
+}
+
+// withDisplay returns the display profile of address from profiles, or nil
+func withDisplay(profiles map[string]models.DisplayProfile, address string) *models.DisplayProfile {
+	profile, ok := profiles[strings.ToLower(address)]
+	if !ok {
+		return nil
+	}
+	return &profile
+}
+
+// withDisplayProfiles returns copies of players with their display profiles; store results
+// may be shared with other requests, so they are never modified in place
+func (h *NadmonHandler) withDisplayProfiles(c *gin.Context, players []models.PlayerProfile) []models.PlayerProfile {
+	addresses := make([]string, len(players))
+	for i, player := range players {
+		addresses[i] = player.Address
+	}
+	profiles := h.displayProfiles(c, addresses)
+	names := h.displayNames(addresses)
+
+	decorated := make([]models.PlayerProfile, len(players))
+	for i, player := range players {
+		player.Display = withDisplay(profiles, player.Address)
+		player.DisplayName = names[strings.ToLower(player.Address)]
+		decorated[i] = player
+	}
+	return decorated
+}
+
+// withLeaderboardDisplay returns a copy of leaderboard with the display profile of every
+// ranked player
+func (h *NadmonHandler) withLeaderboardDisplay(c *gin.Context, leaderboard *models.Leaderboard) *models.Leaderboard {
+	addresses := make([]string, 0, len(leaderboard.Entries)+1)
+	for _, entry := range leaderboard.Entries {
+		addresses = append(addresses, entry.Address)
+	}
+	if leaderboard.Player != nil {
+		addresses = append(addresses, leaderboard.Player.Address)
+	}
+	profiles := h.displayProfiles(c, addresses)
+	names := h.displayNames(addresses)
+
+	decorated := *leaderboard
+	decorated.Entries = make([]models.LeaderboardEntry, len(leaderboard.Entries))
+	for i, entry := range leaderboard.Entries {
+		entry.Display = withDisplay(profiles, entry.Address)
+		entry.DisplayName = names[strings.ToLower(entry.Address)]
+		decorated.Entries[i] = entry
+	}
+	if leaderboard.Player != nil {
+		player := *leaderboard.Player
+		player.Display = withDisplay(profiles, player.Address)
+		player.DisplayName = names[strings.ToLower(player.Address)]
+		decorated.Player = &player
+	}
+	return &decorated
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"nadmon-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetRarityStore enables the NFT rarity rank endpoints, reading ranks from store
+func (h *NadmonHandler) SetRarityStore(store repository.RarityStore) {
+	h.rarity = store
+}
+
+// GetNFTRank returns how rare a Nadmon is among the circulating ones: its score, rank and
+// how common each of its traits is
+func (h *NadmonHandler) GetNFTRank(c *gin.Context) {
+	if h.rarity == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Rarity ranks are not available"})
+		return
+	}
+
+	tokenID, err := strconv.ParseInt(c.Param("tokenId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid token ID"})
+		return
+	}
+
+	rarity, err := h.rarity.GetNFTRarity(c.Request.Context(), tokenID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch rarity rank: " + err.Error()})
+		return
+	}
+	if rarity == nil {
+		// Burned and just-minted Nadmons aren't ranked
+		c.JSON(http.StatusNotFound, gin.H{"error": "NFT is not ranked"})
+		return
+	}
+
+	c.JSON(http.StatusOK, rarity)
+}
+
+// GetRarestNFTs returns the circulating Nadmons from the rarest down (paginated)
+func (h *NadmonHandler) GetRarestNFTs(c *gin.Context) {
+	if h.rarity == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Rarity ranks are not available"})
+		return
+	}
+
+	pagination := h.bindPagination(c)
+	rarities, total, err := h.rarity.GetRarestNFTs(c.Request.Context(), pagination.Limit, (pagination.Page-1)*pagination.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch rarest NFTs: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newPaginatedResponse(rarities, total, pagination))
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"net/http"
+	"regexp"
+
+	"nadmon-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GlobalSearchQuery represents the parameters of a collection-wide search
+type GlobalSearchQuery struct {
+	SearchQuery
+	Owner  string `form:"owner"`
+	Cursor string `form:"cursor"`
+}
+
+// ownerFragment matches the part of an address an owner search may contain
+var ownerFragment = regexp.MustCompile(`^(0x)?[0-9a-fA-F]{1,40}$`)
+
+// SearchAllNFTs searches the circulating NFTs of every player by the SearchNFTs filters and
+// an owner substring. Pages are keyset-paginated: pass nextCursor back as ?cursor= with the
+// same filters and sort to read the next page.
+func (h *NadmonHandler) SearchAllNFTs(c *gin.Context) {
+	var search GlobalSearchQuery
+	if err := c.ShouldBindQuery(&search); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid search parameters"})
+		return
+	}
+
+	filters, ok := searchFilters(c, search.SearchQuery)
+	if !ok {
+		return
+	}
+	if search.Owner != "" {
+		if !ownerFragment.MatchString(search.Owner) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid owner, expected part of a hex address"})
+			return
+		}
+		filters["owner"] = search.Owner
+	}
+
+	var after *repository.SearchCursor
+	if search.Cursor != "" {
+		cursor, err := repository.DecodeSearchCursor(search.Cursor)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor, expected the nextCursor of a previous page"})
+			return
+		}
+		after = cursor
+	}
+
+	limit := h.bindPagination(c).Limit
+	page, err := h.store(c).SearchAllNadmons(c.Request.Context(), filters, after, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to search NFTs: " + err.Error()})
+		return
+	}
+
+	nfts := make([]map[string]interface{}, len(page.Nadmons))
+	for i, nadmon := range page.Nadmons {
+		nfts[i] = nadmon.ToFrontendFormat()
+	}
+
+	var nextCursor interface{}
+	if page.NextCursor != "" {
+		nextCursor = page.NextCursor
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":       nfts,
+		"total":      page.Total,
+		"limit":      limit,
+		"hasNext":    page.NextCursor != "",
+		"nextCursor": nextCursor,
+	})
+}
@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"nadmon-backend/internal/status"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StatusHandler struct {
+	monitor *status.Monitor
+}
+
+// NewStatusHandler creates a new handler reporting recorded health history
+func NewStatusHandler(monitor *status.Monitor) *StatusHandler {
+	return &StatusHandler{monitor: monitor}
+}
+
+// bucketSizes is the bar width of the status page for each window
+var bucketSizes = map[string]time.Duration{
+	"24h": time.Hour,
+	"7d":  time.Hour,
+	"30d": 24 * time.Hour,
+}
+
+// GetHistory returns uptime percentages over 24h/7d/30d and aggregated samples for one window
+func (h *StatusHandler) GetHistory(c *gin.Context) {
+	window := c.DefaultQuery("window", "24h")
+	if _, ok := status.Windows[window]; !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid window, expected one of 24h, 7d, 30d"})
+		return
+	}
+
+	now := time.Now()
+	uptime := make(map[string]float64, len(status.Windows))
+	for name, period := range status.Windows {
+		uptime[name] = status.Uptime(h.monitor.Since(now.Add(-period)))
+	}
+
+	samples := h.monitor.Since(now.Add(-status.Windows[window]))
+	response := gin.H{
+		"uptime":  uptime,
+		"window":  window,
+		"buckets": status.Aggregate(samples, bucketSizes[window]),
+	}
+	if len(samples) > 0 {
+		response["current"] = samples[len(samples)-1]
+	}
+
+	c.JSON(http.StatusOK, response)
+}
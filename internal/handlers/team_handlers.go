@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"nadmon-backend/internal/auth"
+	"nadmon-backend/internal/models"
+	"nadmon-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TeamRequest is the body of POST /players/:address/teams
+type TeamRequest struct {
+	Name     string  `json:"name"`
+	TokenIDs []int64 `json:"token_ids"`
+}
+
+// SetTeamStore enables the team endpoints, saving teams to store
+func (h *NadmonHandler) SetTeamStore(store repository.TeamStore) {
+	h.teams = store
+}
+
+// GetTeams returns the authenticated player's saved teams, each listing the Nadmons the
+// player no longer holds
+func (h *NadmonHandler) GetTeams(c *gin.Context) {
+	if h.teams == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Teams are not available"})
+		return
+	}
+
+	address := c.GetString(auth.AddressKey)
+	teams, err := h.teams.GetTeams(c.Request.Context(), address)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch teams: " + err.Error()})
+		return
+	}
+
+	// One ownership lookup covers every team
+	var tokenIDs []int64
+	for _, team := range teams {
+		tokenIDs = append(tokenIDs, team.TokenIDs...)
+	}
+	held, err := h.heldTokens(c, address, tokenIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch NFTs: " + err.Error()})
+		return
+	}
+	for i := range teams {
+		teams[i].MissingTokenIDs = []int64{}
+		for _, id := range teams[i].TokenIDs {
+			if !held[id] {
+				teams[i].MissingTokenIDs = append(teams[i].MissingTokenIDs, id)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  teams,
+		"total": len(teams),
+	})
+}
+
+// CreateTeam saves a named team of Nadmons the authenticated player currently holds
+func (h *NadmonHandler) CreateTeam(c *gin.Context) {
+	if h.teams == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Teams are not available"})
+		return
+	}
+
+	var req TeamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" || utf8.RuneCountInString(name) > models.MaxTeamNameLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid name, expected 1-32 characters"})
+		return
+	}
+	if len(req.TokenIDs) == 0 || len(req.TokenIDs) > h.limits.MaxTeamSize {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid token_ids, expected 1-%d Nadmons", h.limits.MaxTeamSize)})
+		return
+	}
+	seen := make(map[int64]bool, len(req.TokenIDs))
+	for _, id := range req.TokenIDs {
+		if seen[id] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid token_ids, token " + strconv.FormatInt(id, 10) + " is listed twice"})
+			return
+		}
+		seen[id] = true
+	}
+
+	address := c.GetString(auth.AddressKey)
+	held, err := h.heldTokens(c, address, req.TokenIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch NFTs: " + err.Error()})
+		return
+	}
+	for _, id := range req.TokenIDs {
+		if !held[id] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid token_ids, token " + strconv.FormatInt(id, 10) + " is not held by this address"})
+			return
+		}
+	}
+
+	team, err := h.teams.CreateTeam(c.Request.Context(), models.Team{Address: address, Name: name, TokenIDs: req.TokenIDs}, h.limits.MaxTeams)
+	switch {
+	case errors.Is(err, repository.ErrTeamNameTaken):
+		c.JSON(http.StatusConflict, gin.H{"error": "A team with this name already exists"})
+		return
+	case errors.Is(err, repository.ErrTeamLimit):
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Team limit reached (max %d)", h.limits.MaxTeams)})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save team: " + err.Error()})
+		return
+	}
+
+	team.MissingTokenIDs = []int64{}
+	c.JSON(http.StatusCreated, team)
+}
+
+// DeleteTeam removes one of the authenticated player's teams
+func (h *NadmonHandler) DeleteTeam(c *gin.Context) {
+	if h.teams == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Teams are not available"})
+		return
+	}
+
+	id, err := strconv.ParseInt(c.Param("teamId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid team ID"})
+		return
+	}
+
+	found, err := h.teams.DeleteTeam(c.Request.Context(), c.GetString(auth.AddressKey), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete team: " + err.Error()})
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Team not found"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// heldTokens reports which of tokenIDs address currently holds
+func (h *NadmonHandler) heldTokens(c *gin.Context, address string, tokenIDs []int64) (map[int64]bool, error) {
+	held := make(map[int64]bool, len(tokenIDs))
+	if len(tokenIDs) == 0 {
+		return held, nil
+	}
+
+	nadmons, err := h.store(c).GetNadmonsByIDs(c.Request.Context(), tokenIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, nadmon := range nadmons {
+		if strings.EqualFold(nadmon.Owner, address) {
+			held[nadmon.TokenID] = true
+		}
+	}
+	return held, nil
+}
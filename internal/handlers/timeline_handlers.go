@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"nadmon-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetStatTimeline returns a Nadmon's stats from its mint through every stat change, as
+// timestamps and one array of values per ?metric= (all stats by default)
+func (h *NadmonHandler) GetStatTimeline(c *gin.Context) {
+	tokenID, err := strconv.ParseInt(c.Param("tokenId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid token ID"})
+		return
+	}
+
+	metrics := models.StatMetrics
+	if raw := c.Query("metric"); raw != "" {
+		metrics = nil
+		seen := make(map[string]bool)
+		for _, metric := range strings.Split(raw, ",") {
+			metric = strings.ToLower(strings.TrimSpace(metric))
+			if !isStatMetric(metric) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid metric, expected any of: " + strings.Join(models.StatMetrics, ", ")})
+				return
+			}
+			if !seen[metric] {
+				seen[metric] = true
+				metrics = append(metrics, metric)
+			}
+		}
+	}
+
+	nadmon, err := h.store(c).GetSingleNadmon(c.Request.Context(), tokenID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch NFT: " + err.Error()})
+		return
+	}
+	if nadmon == nil {
+		statuses, err := h.store(c).GetNadmonStatuses(c.Request.Context(), []int64{tokenID})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch NFT status: " + err.Error()})
+			return
+		}
+		if statuses[tokenID].Status == models.StatusBurned {
+			c.JSON(http.StatusGone, gin.H{"error": "NFT was burned", "burned": true})
+			return
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": "NFT not found"})
+		return
+	}
+
+	history, err := h.store(c).GetNadmonHistory(c.Request.Context(), tokenID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch NFT history: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, statTimeline(nadmon, history, metrics))
+}
+
+// statTimeline builds the timeline of nadmon from its stat changes, oldest first. The mint
+// stats are the first change's old stats, or the current ones when nothing changed.
+func statTimeline(nadmon *models.Nadmon, history []models.StatsChange, metrics []string) models.StatTimeline {
+	mint := nadmon.Stats()
+	if len(history) > 0 {
+		mint = history[0].OldStats
+	}
+
+	points := len(history) + 1
+	timeline := models.StatTimeline{
+		TokenID:    nadmon.TokenID,
+		Timestamps: make([]int64, 0, points),
+		Events:     make([]string, 0, points),
+		Series:     make([]models.StatSeries, len(metrics)),
+	}
+	for i, metric := range metrics {
+		timeline.Series[i] = models.StatSeries{Metric: metric, Values: make([]int64, 0, points)}
+	}
+
+	add := func(at int64, event string, stats models.StatSet) {
+		timeline.Timestamps = append(timeline.Timestamps, at)
+		timeline.Events = append(timeline.Events, event)
+		for i := range timeline.Series {
+			timeline.Series[i].Values = append(timeline.Series[i].Values, stats.Value(timeline.Series[i].Metric))
+		}
+	}
+	add(nadmon.CreatedAt.UnixMilli(), models.StatTimelineMint, mint)
+	for _, change := range history {
+		add(change.ChangedAt.UnixMilli(), change.ChangeType, change.NewStats)
+	}
+	return timeline
+}
+
+// isStatMetric reports whether metric names a stat
+func isStatMetric(metric string) bool {
+	for _, known := range models.StatMetrics {
+		if metric == known {
+			return true
+		}
+	}
+	return false
+}
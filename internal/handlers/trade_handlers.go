@@ -0,0 +1,308 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"nadmon-backend/internal/auth"
+	"nadmon-backend/internal/models"
+	"nadmon-backend/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Notifier pushes real-time messages to a player's WebSocket and SSE clients
+type Notifier interface {
+	NotifyUser(address string, messageType string, data interface{})
+}
+
+// TradeRequest is the body of POST /trades
+type TradeRequest struct {
+	Counterparty      string  `json:"counterparty"`
+	OfferedTokenIDs   []int64 `json:"offered_token_ids"`
+	RequestedTokenIDs []int64 `json:"requested_token_ids"`
+}
+
+// SetTradeStore enables the trade endpoints, saving offers to store and telling the other
+// side of an offer about it through notifier, unless it is nil
+func (h *NadmonHandler) SetTradeStore(store repository.TradeStore, notifier Notifier) {
+	h.trades = store
+	h.notifier = notifier
+}
+
+// CreateTrade proposes a swap of Nadmons the authenticated player holds for Nadmons the
+// counterparty holds, and notifies the counterparty
+func (h *NadmonHandler) CreateTrade(c *gin.Context) {
+	if h.trades == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Trades are not available"})
+		return
+	}
+
+	var req TradeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	address := c.GetString(auth.AddressKey)
+	if !isValidEthereumAddress(req.Counterparty) || strings.EqualFold(req.Counterparty, address) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid counterparty, expected another player's address"})
+		return
+	}
+	for field, ids := range map[string][]int64{"offered_token_ids": req.OfferedTokenIDs, "requested_token_ids": req.RequestedTokenIDs} {
+		if len(ids) == 0 || len(ids) > h.limits.MaxTradeSize {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid %s, expected 1-%d Nadmons", field, h.limits.MaxTradeSize)})
+			return
+		}
+	}
+	seen := make(map[int64]bool, len(req.OfferedTokenIDs)+len(req.RequestedTokenIDs))
+	for _, id := range append(append([]int64{}, req.OfferedTokenIDs...), req.RequestedTokenIDs...) {
+		if seen[id] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid token IDs, token " + strconv.FormatInt(id, 10) + " is listed twice"})
+			return
+		}
+		seen[id] = true
+	}
+
+	offer := models.TradeOffer{
+		Proposer:          address,
+		Counterparty:      req.Counterparty,
+		OfferedTokenIDs:   req.OfferedTokenIDs,
+		RequestedTokenIDs: req.RequestedTokenIDs,
+		Status:            models.TradePending,
+	}
+	if err := h.withMissingTokens(c, &offer); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch NFTs: " + err.Error()})
+		return
+	}
+	if len(offer.MissingTokenIDs) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid token IDs, token " + strconv.FormatInt(offer.MissingTokenIDs[0], 10) + " is not held by its side of the trade"})
+		return
+	}
+
+	created, err := h.trades.CreateTrade(c.Request.Context(), offer, h.limits.MaxPendingTrades)
+	switch {
+	case errors.Is(err, repository.ErrTradeLimit):
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Pending trade offer limit reached (max %d)", h.limits.MaxPendingTrades)})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save trade offer: " + err.Error()})
+		return
+	}
+
+	created.MissingTokenIDs = []int64{}
+	h.notify(created.Counterparty, models.TypeTradeOffered, created)
+	c.JSON(http.StatusCreated, created)
+}
+
+// GetTrades returns the trade offers the authenticated player sent and received, newest
+// first (paginated), optionally only of ?role=sent|received and ?status=
+func (h *NadmonHandler) GetTrades(c *gin.Context) {
+	if h.trades == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Trades are not available"})
+		return
+	}
+
+	role := c.Query("role")
+	if role != "" && role != repository.TradeRoleSent && role != repository.TradeRoleReceived {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role, expected sent or received"})
+		return
+	}
+	status := c.Query("status")
+	if status != "" && !isTradeStatus(status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status, expected one of: " + strings.Join(models.TradeStatuses, ", ")})
+		return
+	}
+
+	pagination := h.bindPagination(c)
+	page, err := h.trades.GetTrades(c.Request.Context(), c.GetString(auth.AddressKey), role, status, pagination.Limit, (pagination.Page-1)*pagination.Limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch trade offers: " + err.Error()})
+		return
+	}
+	offers := make([]*models.TradeOffer, len(page.Offers))
+	for i := range page.Offers {
+		offers[i] = &page.Offers[i]
+	}
+	if err := h.withMissingTokens(c, offers...); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch NFTs: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, newPaginatedResponse(page.Offers, page.Total, pagination))
+}
+
+// GetTrade returns one of the authenticated player's trade offers
+func (h *NadmonHandler) GetTrade(c *gin.Context) {
+	offer, ok := h.bindTrade(c)
+	if !ok {
+		return
+	}
+	if err := h.withMissingTokens(c, offer); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch NFTs: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, offer)
+}
+
+// AcceptTrade accepts an offer the authenticated player received, as long as both sides still
+// hold their Nadmons, and notifies the proposer; the swap is then settled on-chain
+func (h *NadmonHandler) AcceptTrade(c *gin.Context) {
+	offer, ok := h.bindTrade(c)
+	if !ok {
+		return
+	}
+	if !strings.EqualFold(offer.Counterparty, c.GetString(auth.AddressKey)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the counterparty can accept a trade offer"})
+		return
+	}
+	if offer.Status != models.TradePending {
+		c.JSON(http.StatusConflict, gin.H{"error": "Trade offer is " + offer.Status})
+		return
+	}
+
+	if err := h.withMissingTokens(c, offer); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch NFTs: " + err.Error()})
+		return
+	}
+	if len(offer.MissingTokenIDs) > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Trade offer can't be accepted, token " + strconv.FormatInt(offer.MissingTokenIDs[0], 10) + " changed hands"})
+		return
+	}
+
+	h.answerTrade(c, offer, models.TradeAccepted, offer.Proposer, models.TypeTradeAccepted)
+}
+
+// RejectTrade rejects an offer the authenticated player received and notifies the proposer
+func (h *NadmonHandler) RejectTrade(c *gin.Context) {
+	offer, ok := h.bindTrade(c)
+	if !ok {
+		return
+	}
+	if !strings.EqualFold(offer.Counterparty, c.GetString(auth.AddressKey)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the counterparty can reject a trade offer"})
+		return
+	}
+
+	h.answerTrade(c, offer, models.TradeRejected, offer.Proposer, models.TypeTradeRejected)
+}
+
+// CancelTrade withdraws an offer the authenticated player sent and notifies the counterparty
+func (h *NadmonHandler) CancelTrade(c *gin.Context) {
+	offer, ok := h.bindTrade(c)
+	if !ok {
+		return
+	}
+	if !strings.EqualFold(offer.Proposer, c.GetString(auth.AddressKey)) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only the proposer can cancel a trade offer"})
+		return
+	}
+
+	h.answerTrade(c, offer, models.TradeCancelled, offer.Counterparty, models.TypeTradeCancelled)
+}
+
+// bindTrade reads the offer named in the route. Players only see their own offers: others
+// get the same 404 as unknown ones.
+func (h *NadmonHandler) bindTrade(c *gin.Context) (*models.TradeOffer, bool) {
+	if h.trades == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Trades are not available"})
+		return nil, false
+	}
+
+	id, err := strconv.ParseInt(c.Param("tradeId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid trade ID"})
+		return nil, false
+	}
+
+	offer, err := h.trades.GetTrade(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch trade offer: " + err.Error()})
+		return nil, false
+	}
+	address := c.GetString(auth.AddressKey)
+	if offer == nil || (!strings.EqualFold(offer.Proposer, address) && !strings.EqualFold(offer.Counterparty, address)) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trade offer not found"})
+		return nil, false
+	}
+	return offer, true
+}
+
+// answerTrade moves a pending offer to status and notifies the other side with messageType
+func (h *NadmonHandler) answerTrade(c *gin.Context, offer *models.TradeOffer, status, notify, messageType string) {
+	updated, err := h.trades.SetTradeStatus(c.Request.Context(), offer.ID, status)
+	switch {
+	case errors.Is(err, repository.ErrTradeNotPending):
+		c.JSON(http.StatusConflict, gin.H{"error": "Trade offer is no longer pending"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update trade offer: " + err.Error()})
+		return
+	}
+
+	updated.MissingTokenIDs = []int64{}
+	h.notify(notify, messageType, updated)
+	c.JSON(http.StatusOK, updated)
+}
+
+// withMissingTokens checks the current owners of every pending offer's Nadmons with one
+// lookup and sets the offers' MissingTokenIDs to the ones no longer held by the side giving
+// them; other offers get an empty list
+func (h *NadmonHandler) withMissingTokens(c *gin.Context, offers ...*models.TradeOffer) error {
+	var tokenIDs []int64
+	for _, offer := range offers {
+		if offer.Status == models.TradePending {
+			tokenIDs = append(tokenIDs, offer.OfferedTokenIDs...)
+			tokenIDs = append(tokenIDs, offer.RequestedTokenIDs...)
+		}
+	}
+	owners := make(map[int64]string, len(tokenIDs))
+	if len(tokenIDs) > 0 {
+		nadmons, err := h.store(c).GetNadmonsByIDs(c.Request.Context(), tokenIDs)
+		if err != nil {
+			return err
+		}
+		for _, nadmon := range nadmons {
+			owners[nadmon.TokenID] = nadmon.Owner
+		}
+	}
+
+	for _, offer := range offers {
+		missing := []int64{}
+		if offer.Status == models.TradePending {
+			for _, id := range offer.OfferedTokenIDs {
+				if !strings.EqualFold(owners[id], offer.Proposer) {
+					missing = append(missing, id)
+				}
+			}
+			for _, id := range offer.RequestedTokenIDs {
+				if !strings.EqualFold(owners[id], offer.Counterparty) {
+					missing = append(missing, id)
+				}
+			}
+		}
+		offer.MissingTokenIDs = missing
+	}
+	return nil
+}
+
+// notify pushes a message to address when a notifier is set
+func (h *NadmonHandler) notify(address, messageType string, data interface{}) {
+	if h.notifier != nil {
+		h.notifier.NotifyUser(strings.ToLower(address), messageType, data)
+	}
+}
+
+// isTradeStatus reports whether status is a trade offer status
+func isTradeStatus(status string) bool {
+	for _, known := range models.TradeStatuses {
+		if status == known {
+			return true
+		}
+	}
+	return false
+}
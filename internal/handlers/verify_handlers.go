@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"nadmon-backend/internal/ethaddr"
+	"nadmon-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VerifyOwnership answers whether ?address= currently holds each of ?tokenIds=, optionally
+// of at least ?min_rarity=, in one query, so game servers can gate battles without pulling
+// whole inventories. The validation middleware checks the parameters' format and the ID count.
+func (h *NadmonHandler) VerifyOwnership(c *gin.Context) {
+	address := c.Query("address")
+	if address == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid address, expected an Ethereum address"})
+		return
+	}
+	if c.Query("tokenIds") == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid tokenIds, expected comma-separated token IDs"})
+		return
+	}
+
+	parts := strings.Split(c.Query("tokenIds"), ",")
+	if len(parts) > h.limits.MaxBatchIDs {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Too many token IDs (max %d)", h.limits.MaxBatchIDs)})
+		return
+	}
+	tokenIDs := make([]int64, 0, len(parts))
+	for _, part := range parts {
+		id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid token ID: " + part})
+			return
+		}
+		tokenIDs = append(tokenIDs, id)
+	}
+
+	nadmons, err := h.store(c).GetNadmonsByIDs(c.Request.Context(), tokenIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to verify ownership: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewOwnershipCheck(ethaddr.Normalize(address), tokenIDs, nadmons, c.Query("min_rarity")))
+}
@@ -0,0 +1,241 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"nadmon-backend/internal/auth"
+	"nadmon-backend/internal/ethaddr"
+	"nadmon-backend/internal/models"
+	"nadmon-backend/internal/repository"
+	"nadmon-backend/internal/webhooks"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Webhook request limits
+const (
+	maxWebhookURLLength = 2048
+	maxWebhookAddresses = 100
+)
+
+// MaxWebhookLog caps the deliveries returned by one delivery log request
+const MaxWebhookLog = 200
+
+// WebhookRequest is the body of POST /webhooks
+type WebhookRequest struct {
+	URL       string   `json:"url"`
+	Events    []string `json:"events"`
+	Addresses []string `json:"addresses"`
+}
+
+// WebhookHandler manages webhook subscriptions. Requests authenticate either with the admin
+// API key, which sees every webhook, or with a wallet's session token, which only sees the
+// webhooks that wallet registered.
+type WebhookHandler struct {
+	store       repository.WebhookStore
+	maxPerOwner int
+	onChange    func()
+}
+
+// NewWebhookHandler creates a webhook handler allowing each wallet maxPerOwner webhooks;
+// onChange, if not nil, is called after a webhook is registered or deleted
+func NewWebhookHandler(store repository.WebhookStore, maxPerOwner int, onChange func()) *WebhookHandler {
+	return &WebhookHandler{store: store, maxPerOwner: maxPerOwner, onChange: onChange}
+}
+
+// CreateWebhook registers a callback URL for the given event types. The response is the only
+// one carrying the signing secret.
+func (h *WebhookHandler) CreateWebhook(c *gin.Context) {
+	var req WebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	admin := c.GetBool(auth.AdminKey)
+	if err := validateWebhookURL(req.URL, admin); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid url, " + err.Error()})
+		return
+	}
+
+	eventTypes, err := parseWebhookEvents(req.Events)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid events, " + err.Error()})
+		return
+	}
+
+	if len(req.Addresses) > maxWebhookAddresses {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid addresses, expected at most %d", maxWebhookAddresses)})
+		return
+	}
+	for _, address := range req.Addresses {
+		if !ethaddr.Valid(address) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid addresses, " + address + " is not an Ethereum address"})
+			return
+		}
+	}
+
+	webhook, err := h.store.CreateWebhook(c.Request.Context(), models.Webhook{
+		URL:       req.URL,
+		Owner:     c.GetString(auth.AddressKey),
+		Events:    eventTypes,
+		Addresses: req.Addresses,
+		Secret:    webhooks.NewSecret(),
+	}, h.maxPerOwner)
+	if errors.Is(err, repository.ErrWebhookLimit) {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("Webhook limit reached (max %d)", h.maxPerOwner)})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save webhook: " + err.Error()})
+		return
+	}
+	if webhook.Addresses == nil {
+		webhook.Addresses = []string{}
+	}
+
+	h.changed()
+	c.JSON(http.StatusCreated, webhook)
+}
+
+// GetWebhooks lists the caller's webhooks, or every webhook for the admin
+func (h *WebhookHandler) GetWebhooks(c *gin.Context) {
+	var list []models.Webhook
+	var err error
+	if c.GetBool(auth.AdminKey) {
+		list, err = h.store.GetWebhooks(c.Request.Context())
+	} else {
+		list, err = h.store.GetOwnerWebhooks(c.Request.Context(), c.GetString(auth.AddressKey))
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch webhooks: " + err.Error()})
+		return
+	}
+
+	for i := range list {
+		list[i].Secret = ""
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  list,
+		"total": len(list),
+	})
+}
+
+// DeleteWebhook unregisters a webhook; pending retries of its deliveries are dropped
+func (h *WebhookHandler) DeleteWebhook(c *gin.Context) {
+	webhook, ok := h.accessibleWebhook(c)
+	if !ok {
+		return
+	}
+
+	found, err := h.store.DeleteWebhook(c.Request.Context(), webhook.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete webhook: " + err.Error()})
+		return
+	}
+	if !found {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+		return
+	}
+
+	h.changed()
+	c.Status(http.StatusNoContent)
+}
+
+// GetDeliveries returns a webhook's latest delivery attempts, newest first
+func (h *WebhookHandler) GetDeliveries(c *gin.Context) {
+	webhook, ok := h.accessibleWebhook(c)
+	if !ok {
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > MaxWebhookLog {
+		limit = 50
+	}
+
+	deliveries, err := h.store.GetDeliveries(c.Request.Context(), webhook.ID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch webhook deliveries: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  deliveries,
+		"total": len(deliveries),
+	})
+}
+
+// accessibleWebhook loads the :id webhook if the caller may manage it, otherwise it writes the
+// error response. Other wallets' webhooks are reported as not found.
+func (h *WebhookHandler) accessibleWebhook(c *gin.Context) (*models.Webhook, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook ID"})
+		return nil, false
+	}
+
+	webhook, err := h.store.GetWebhook(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch webhook: " + err.Error()})
+		return nil, false
+	}
+	if webhook == nil || (!c.GetBool(auth.AdminKey) && webhook.Owner != ethaddr.Normalize(c.GetString(auth.AddressKey))) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+		return nil, false
+	}
+	return webhook, true
+}
+
+// changed tells the dispatcher to pick up a registered or deleted webhook
+func (h *WebhookHandler) changed() {
+	if h.onChange != nil {
+		h.onChange()
+	}
+}
+
+// validateWebhookURL checks a callback URL; wallet-registered webhooks must use HTTPS
+func validateWebhookURL(raw string, admin bool) error {
+	if raw == "" || len(raw) > maxWebhookURLLength {
+		return fmt.Errorf("expected an absolute URL of at most %d characters", maxWebhookURLLength)
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" || u.User != nil {
+		return errors.New("expected an absolute URL without credentials")
+	}
+	switch {
+	case u.Scheme == "https":
+	case u.Scheme == "http" && admin:
+	default:
+		return errors.New("expected an https URL")
+	}
+	return nil
+}
+
+// parseWebhookEvents checks the requested event types and drops duplicates
+func parseWebhookEvents(requested []string) ([]string, error) {
+	if len(requested) == 0 {
+		return nil, fmt.Errorf("expected one or more of %v", models.WebhookEvents)
+	}
+
+	var eventTypes []string
+	seen := make(map[string]bool, len(requested))
+	for _, eventType := range requested {
+		known := false
+		for _, t := range models.WebhookEvents {
+			known = known || t == eventType
+		}
+		if !known {
+			return nil, fmt.Errorf("unknown event type %q, expected one of %v", eventType, models.WebhookEvents)
+		}
+		if !seen[eventType] {
+			seen[eventType] = true
+			eventTypes = append(eventTypes, eventType)
+		}
+	}
+	return eventTypes, nil
+}
@@ -1,44 +1,249 @@
 package handlers
 
 import (
+	"io"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
+	"nadmon-backend/internal/auth"
+	"nadmon-backend/internal/ethaddr"
 	"nadmon-backend/internal/websocket"
 
 	"github.com/gin-gonic/gin"
 )
 
+// Broadcaster sends a message to every connected client
+type Broadcaster interface {
+	BroadcastToAll(messageType string, data interface{})
+}
+
 type WebSocketHandler struct {
-	wsManager *websocket.Manager
+	wsManager   *websocket.Manager
+	broadcaster Broadcaster
+	auth        *auth.Service
+	chains      *ChainHandler
+	allowPublic bool
 }
 
-// NewWebSocketHandler creates a new WebSocket handler
-func NewWebSocketHandler(wsManager *websocket.Manager) *WebSocketHandler {
+// NewWebSocketHandler creates a new WebSocket handler. Private channels need a stream token
+// from authService; allowPublic lets clients without one connect for broadcasts only. Clients
+// may subscribe to one of chains with ?chain=.
+func NewWebSocketHandler(wsManager *websocket.Manager, authService *auth.Service, chains *ChainHandler, allowPublic bool) *WebSocketHandler {
 	return &WebSocketHandler{
-		wsManager: wsManager,
+		wsManager:   wsManager,
+		broadcaster: wsManager,
+		auth:        authService,
+		chains:      chains,
+		allowPublic: allowPublic,
 	}
 }
 
-// HandleConnection handles WebSocket connection requests
+// SetBroadcaster sends admin notices through broadcaster instead of the local manager, e.g. a
+// fan-out reaching the clients of every replica
+func (h *WebSocketHandler) SetBroadcaster(broadcaster Broadcaster) {
+	h.broadcaster = broadcaster
+}
+
+// HandleConnection handles WebSocket connection requests. ?encoding=msgpack sends messages as
+// MessagePack binary frames instead of JSON text frames.
 func (h *WebSocketHandler) HandleConnection(c *gin.Context) {
+	address, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+	subscription, ok := h.bindSubscription(c)
+	if !ok {
+		return
+	}
+	subscription.Encoding = c.DefaultQuery("encoding", websocket.EncodingJSON)
+	if subscription.Encoding != websocket.EncodingJSON && subscription.Encoding != websocket.EncodingMsgpack {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid encoding, expected one of " + strings.Join(websocket.Encodings, ", ")})
+		return
+	}
+
+	// Upgrade HTTP connection to WebSocket
+	h.wsManager.UpgradeConnection(c.Writer, c.Request, address, subscription)
+}
+
+// bindSubscription reads the optional query parameters selecting what a connection receives:
+// chain, to only get the events of one chain, and resume_from, the ID of the last message a
+// reconnecting client received, after which missed messages are replayed
+func (h *WebSocketHandler) bindSubscription(c *gin.Context) (websocket.Subscription, bool) {
+	subscription := websocket.Subscription{Chain: c.Query("chain"), ResumeFrom: websocket.NoResume}
+	if subscription.Chain != "" && !h.chains.Valid(subscription.Chain) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid chain, expected one of " + strings.Join(h.chains.ids(), ", ")})
+		return subscription, false
+	}
+
+	if raw := c.Query("resume_from"); raw != "" {
+		resumeFrom, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || resumeFrom < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resume_from, expected a message ID"})
+			return subscription, false
+		}
+		subscription.ResumeFrom = resumeFrom
+	}
+	return subscription, true
+}
+
+// authorize resolves which channel a connection may open. A valid stream token in the token
+// query parameter opens the private channel of its address, which must match the :address
+// parameter when one is given. Without a token the connection is public, receiving broadcasts
+// only, or is rejected when public connections are disabled. It returns "" for public
+// connections and false after writing an error response.
+func (h *WebSocketHandler) authorize(c *gin.Context) (string, bool) {
 	address := c.Param("address")
-	
-	// Validate Ethereum address
-	if !isValidEthereumAddress(address) {
+	if address != "" && !isValidEthereumAddress(address) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Ethereum address"})
-		return
+		return "", false
+	}
+
+	token := c.Query("token")
+	if token == "" {
+		if !h.allowPublic {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Stream token required"})
+			return "", false
+		}
+		return "", true
+	}
+
+	owner, err := h.auth.ParseStreamToken(token)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired stream token"})
+		return "", false
+	}
+	if address != "" && !strings.EqualFold(owner, address) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Token does not belong to this address"})
+		return "", false
 	}
 
 	// Normalize address to lowercase
-	address = strings.ToLower(address)
+	return ethaddr.Normalize(owner), true
+}
 
-	// Upgrade HTTP connection to WebSocket
-	h.wsManager.UpgradeConnection(c.Writer, c.Request, address)
+// sseKeepAlive is how often an idle event stream sends a comment to keep proxies from closing it
+const sseKeepAlive = 30 * time.Second
+
+// HandleStream streams the same messages as the WebSocket endpoint as Server-Sent Events,
+// for clients on networks that block WebSockets
+func (h *WebSocketHandler) HandleStream(c *gin.Context) {
+	address, ok := h.authorize(c)
+	if !ok {
+		return
+	}
+	subscription, ok := h.bindSubscription(c)
+	if !ok {
+		return
+	}
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // disable nginx response buffering
+
+	client := h.wsManager.Subscribe(address, subscription)
+	defer h.wsManager.Unsubscribe(client)
+
+	keepAlive := time.NewTicker(sseKeepAlive)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case message, ok := <-client.Send:
+			if !ok {
+				return false // replaced by a newer connection for this address
+			}
+			c.SSEvent(message.Type, message)
+			return true
+		case <-keepAlive.C:
+			_, err := io.WriteString(w, ": keep-alive\n\n")
+			return err == nil
+		}
+	})
+}
+
+// GetSchemas returns the JSON Schema and version of every typed message payload
+func (h *WebSocketHandler) GetSchemas(c *gin.Context) {
+	schemas := h.wsManager.Schemas()
+	c.JSON(http.StatusOK, gin.H{
+		"data":      schemas,
+		"total":     len(schemas),
+		"encodings": websocket.Encodings,
+	})
 }
 
 // GetConnectedUsers returns currently connected users (for debugging/admin)
 func (h *WebSocketHandler) GetConnectedUsers(c *gin.Context) {
 	stats := h.wsManager.GetStats()
 	c.JSON(http.StatusOK, stats)
-}
\ No newline at end of file
+}
+
+// ListConnections returns this instance's connections with their transport, topics and
+// connect time, oldest first
+func (h *WebSocketHandler) ListConnections(c *gin.Context) {
+	connections := h.wsManager.Connections()
+	c.JSON(http.StatusOK, gin.H{
+		"data":  connections,
+		"total": len(connections),
+	})
+}
+
+// DisconnectAddress force-closes the connection of an address on this instance; ?reason= is
+// passed on to the client
+func (h *WebSocketHandler) DisconnectAddress(c *gin.Context) {
+	address := c.Param("address")
+	if !isValidEthereumAddress(address) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Ethereum address"})
+		return
+	}
+
+	reason := c.DefaultQuery("reason", "disconnected by an administrator")
+	if !h.wsManager.Disconnect(address, reason) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Address is not connected to this instance"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"address": ethaddr.Normalize(address), "disconnected": true})
+}
+
+// maxNoticeLength caps the text of a broadcast notice
+const maxNoticeLength = 500
+
+// NoticeRequest is the body of POST /admin/websocket/broadcast
+type NoticeRequest struct {
+	Message  string     `json:"message"`
+	Level    string     `json:"level"`
+	StartsAt *time.Time `json:"starts_at"`
+}
+
+// BroadcastNotice sends an operator notice, such as upcoming maintenance, to every client
+func (h *WebSocketHandler) BroadcastNotice(c *gin.Context) {
+	var req NoticeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body: " + err.Error()})
+		return
+	}
+
+	req.Message = strings.TrimSpace(req.Message)
+	if req.Message == "" || len(req.Message) > maxNoticeLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid message, expected 1 to " + strconv.Itoa(maxNoticeLength) + " characters"})
+		return
+	}
+	switch req.Level {
+	case "":
+		req.Level = "info"
+	case "info", "warning", "critical":
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid level, expected info, warning or critical"})
+		return
+	}
+
+	notice := websocket.Notice{Message: req.Message, Level: req.Level, StartsAt: req.StartsAt}
+	h.broadcaster.BroadcastToAll(websocket.MessageNotice, notice)
+
+	c.JSON(http.StatusOK, gin.H{"sent": true, "notice": notice})
+}
@@ -0,0 +1,160 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// DefaultLocale is served when no requested locale is available; its labels also fill
+// any keys missing from other locales
+const DefaultLocale = "en"
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// Catalog holds the translated labels of one locale
+type Catalog struct {
+	Locale       string            `json:"locale"`
+	Name         string            `json:"name"`
+	Elements     map[string]string `json:"elements"`
+	Rarities     map[string]string `json:"rarities"`
+	ChangeTypes  map[string]string `json:"changeTypes"`
+	Achievements map[string]string `json:"achievements"`
+}
+
+// Catalogs holds every embedded locale, keyed by lowercased locale code
+type Catalogs map[string]*Catalog
+
+// Load parses the embedded locale files
+func Load() (Catalogs, error) {
+	entries, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read locale files: %w", err)
+	}
+
+	catalogs := make(Catalogs, len(entries))
+	for _, entry := range entries {
+		data, err := localeFiles.ReadFile(path.Join("locales", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read locale %s: %w", entry.Name(), err)
+		}
+
+		var catalog Catalog
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			return nil, fmt.Errorf("failed to parse locale %s: %w", entry.Name(), err)
+		}
+		catalog.Locale = strings.ToLower(strings.TrimSuffix(entry.Name(), ".json"))
+		catalogs[catalog.Locale] = &catalog
+	}
+
+	base, ok := catalogs[DefaultLocale]
+	if !ok {
+		return nil, fmt.Errorf("default locale %q is missing", DefaultLocale)
+	}
+	for _, catalog := range catalogs {
+		catalog.Elements = withFallback(catalog.Elements, base.Elements)
+		catalog.Rarities = withFallback(catalog.Rarities, base.Rarities)
+		catalog.ChangeTypes = withFallback(catalog.ChangeTypes, base.ChangeTypes)
+		catalog.Achievements = withFallback(catalog.Achievements, base.Achievements)
+	}
+
+	return catalogs, nil
+}
+
+// MustLoad is like Load but panics on error; the locale files are embedded at build time
+func MustLoad() Catalogs {
+	catalogs, err := Load()
+	if err != nil {
+		panic(err)
+	}
+	return catalogs
+}
+
+// withFallback fills labels missing from m with the default locale's labels
+func withFallback(m, fallback map[string]string) map[string]string {
+	if m == nil {
+		m = make(map[string]string, len(fallback))
+	}
+	for key, label := range fallback {
+		if _, ok := m[key]; !ok {
+			m[key] = label
+		}
+	}
+	return m
+}
+
+// Locales returns the available locale codes in sorted order
+func (c Catalogs) Locales() []string {
+	locales := make([]string, 0, len(c))
+	for locale := range c {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// Negotiate picks the best catalog for the requested locale, falling back to the
+// Accept-Language header and finally to the default locale
+func (c Catalogs) Negotiate(requested, acceptLanguage string) *Catalog {
+	if catalog := c.match(requested); catalog != nil {
+		return catalog
+	}
+	for _, tag := range parseAcceptLanguage(acceptLanguage) {
+		if catalog := c.match(tag); catalog != nil {
+			return catalog
+		}
+	}
+	return c[DefaultLocale]
+}
+
+// match finds a catalog for a language tag, trying the base language of regional tags (pt-BR -> pt)
+func (c Catalogs) match(tag string) *Catalog {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return nil
+	}
+	if catalog, ok := c[tag]; ok {
+		return catalog
+	}
+	if base, _, ok := strings.Cut(tag, "-"); ok {
+		return c[base]
+	}
+	return nil
+}
+
+// parseAcceptLanguage returns the language tags of an Accept-Language header ordered by quality
+func parseAcceptLanguage(header string) []string {
+	type weighted struct {
+		tag string
+		q   float64
+	}
+
+	var tags []weighted
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if _, err := fmt.Sscanf(value, "%g", &q); err != nil {
+				continue
+			}
+		}
+		if q > 0 {
+			tags = append(tags, weighted{tag: tag, q: q})
+		}
+	}
+
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+
+	result := make([]string, len(tags))
+	for i, t := range tags {
+		result[i] = t.tag
+	}
+	return result
+}
@@ -0,0 +1,212 @@
+// Package images resolves NFT artwork from a local directory or, failing that, from IPFS
+// through a list of gateways tried in turn, keeping fetched images in memory.
+package images
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned when no source has any of the requested artwork
+var ErrNotFound = errors.New("artwork not found")
+
+// maxImageBytes caps the size of a single artwork file
+const maxImageBytes = 5 << 20
+
+// missingTTL is how long artwork every source reported missing is not asked for again
+const missingTTL = 10 * time.Minute
+
+// Config lists the artwork sources
+type Config struct {
+	Dir        string        // local artwork directory, checked first; empty disables it
+	CID        string        // IPFS directory holding the artwork; empty disables IPFS
+	Gateways   []string      // IPFS gateway base URLs such as https://ipfs.io/ipfs/, tried in order
+	Timeout    time.Duration // per gateway request
+	CacheBytes int64         // memory kept for resolved images
+}
+
+// Image is resolved artwork
+type Image struct {
+	Data        []byte
+	ContentType string
+	ETag        string // strong ETag of Data
+	Source      string // the file or gateway URL the image came from
+}
+
+// Resolver finds artwork by file name
+type Resolver struct {
+	config Config
+	client *http.Client
+
+	mu      sync.Mutex
+	cache   map[string]*Image
+	cached  int64
+	missing map[string]time.Time // names no source has, with when that was last checked
+}
+
+// NewResolver creates a resolver reading from the sources in config
+func NewResolver(config Config) *Resolver {
+	return &Resolver{
+		config:  config,
+		client:  &http.Client{Timeout: config.Timeout},
+		cache:   make(map[string]*Image),
+		missing: make(map[string]time.Time),
+	}
+}
+
+// Enabled reports whether any artwork source is configured
+func (r *Resolver) Enabled() bool {
+	return r.config.Dir != "" || (r.config.CID != "" && len(r.config.Gateways) > 0)
+}
+
+// Resolve returns the first of names any source has, so callers list the most specific
+// artwork first and fall back to more generic art. It returns ErrNotFound when every source
+// reported every name missing, or the last source error when a source couldn't be reached.
+func (r *Resolver) Resolve(ctx context.Context, names []string) (*Image, error) {
+	var lastErr error
+	for _, name := range names {
+		img, err := r.get(ctx, name)
+		if err == nil {
+			return img, nil
+		}
+		if !errors.Is(err, ErrNotFound) {
+			lastErr = err
+		}
+	}
+	if lastErr != nil {
+		return nil, lastErr
+	}
+	return nil, ErrNotFound
+}
+
+// get returns one artwork file, from the cache or the sources
+func (r *Resolver) get(ctx context.Context, name string) (*Image, error) {
+	if name == "" || name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return nil, ErrNotFound
+	}
+
+	r.mu.Lock()
+	img, ok := r.cache[name]
+	checked, missing := r.missing[name]
+	r.mu.Unlock()
+	if ok {
+		return img, nil
+	}
+	if missing && time.Since(checked) < missingTTL {
+		return nil, ErrNotFound
+	}
+
+	img, err := r.fetch(ctx, name)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	switch {
+	case errors.Is(err, ErrNotFound):
+		r.missing[name] = time.Now()
+	case err == nil:
+		delete(r.missing, name)
+		// Artwork can always be fetched again, so simply start over when the cache is full
+		if r.cached+int64(len(img.Data)) > r.config.CacheBytes {
+			r.cache = make(map[string]*Image)
+			r.cached = 0
+		}
+		r.cache[name] = img
+		r.cached += int64(len(img.Data))
+	}
+	return img, err
+}
+
+// fetch reads name from the local directory, then from each gateway until one has it
+func (r *Resolver) fetch(ctx context.Context, name string) (*Image, error) {
+	if r.config.Dir != "" {
+		path := filepath.Join(r.config.Dir, name)
+		data, err := os.ReadFile(path)
+		if err == nil {
+			return newImage(data, "", path)
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to read artwork: %w", err)
+		}
+	}
+	if r.config.CID == "" || len(r.config.Gateways) == 0 {
+		return nil, ErrNotFound
+	}
+
+	// IPFS content is immutable, so a gateway reporting the file missing is final; only
+	// failing gateways are skipped for the next one
+	var lastErr error
+	for _, gateway := range r.config.Gateways {
+		img, err := r.fetchGateway(ctx, gateway, name)
+		if err == nil || errors.Is(err, ErrNotFound) {
+			return img, err
+		}
+		log.Printf("Warning: IPFS gateway %s: %v", gateway, err)
+		lastErr = err
+	}
+	return nil, fmt.Errorf("failed to fetch artwork from IPFS: %w", lastErr)
+}
+
+// fetchGateway reads name from the artwork directory through one gateway
+func (r *Resolver) fetchGateway(ctx context.Context, gateway, name string) (*Image, error) {
+	source := strings.TrimRight(gateway, "/") + "/" + r.config.CID + "/" + url.PathEscape(name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxImageBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxImageBytes {
+		return nil, fmt.Errorf("artwork larger than %d bytes", maxImageBytes)
+	}
+	return newImage(data, resp.Header.Get("Content-Type"), source)
+}
+
+// newImage checks that data is an image; the content type is sniffed unless the source
+// declared an image type
+func newImage(data []byte, contentType, source string) (*Image, error) {
+	if !strings.HasPrefix(contentType, "image/") {
+		contentType = http.DetectContentType(data)
+		// SVG is sniffed as XML or text
+		if strings.HasSuffix(source, ".svg") {
+			contentType = "image/svg+xml"
+		}
+	}
+	if !strings.HasPrefix(contentType, "image/") {
+		return nil, fmt.Errorf("%s is not an image (%s)", source, contentType)
+	}
+
+	sum := sha256.Sum256(data)
+	return &Image{
+		Data:        data,
+		ContentType: contentType,
+		ETag:        `"` + hex.EncodeToString(sum[:16]) + `"`,
+		Source:      source,
+	}, nil
+}
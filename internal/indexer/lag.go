@@ -0,0 +1,193 @@
+// Package indexer tracks how far the Envio indexer's tables lag behind real time, so
+// responses built from them can be flagged as possibly outdated.
+package indexer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LagHeader carries the indexer lag in whole seconds on API responses while it is stale
+const LagHeader = "X-Data-Lag-Seconds"
+
+// checkTimeout bounds each progress query
+const checkTimeout = 5 * time.Second
+
+// progressQuery reads the age of the newest row and the highest sequence of every event
+// table. Ages are computed by Postgres against LOCALTIMESTAMP, the clock the indexer's
+// db_write_timestamp defaults use, so clock skew with this server doesn't count as lag.
+const progressQuery = `
+	SELECT 'NadmonNFT_NadmonMinted', EXTRACT(EPOCH FROM LOCALTIMESTAMP - MAX(db_write_timestamp))::float8, MAX(sequence)::bigint
+	FROM "NadmonNFT_NadmonMinted"
+	UNION ALL
+	SELECT 'NadmonNFT_PackMinted', EXTRACT(EPOCH FROM LOCALTIMESTAMP - MAX(db_write_timestamp))::float8, MAX(sequence)::bigint
+	FROM "NadmonNFT_PackMinted"
+	UNION ALL
+	SELECT 'NadmonNFT_StatsChanged', EXTRACT(EPOCH FROM LOCALTIMESTAMP - MAX(db_write_timestamp))::float8, MAX(sequence)::bigint
+	FROM "NadmonNFT_StatsChanged"
+	UNION ALL
+	SELECT 'NadmonNFT_Transfer', EXTRACT(EPOCH FROM LOCALTIMESTAMP - MAX(db_write_timestamp))::float8, NULL::bigint
+	FROM "NadmonNFT_Transfer"
+`
+
+// TableProgress is the newest row the indexer has written to one table
+type TableProgress struct {
+	NewestWrite *time.Time `json:"newest_write"`       // nil while the table is empty
+	Sequence    *int64     `json:"sequence,omitempty"` // highest event sequence, for tables that have one
+}
+
+// Progress is the result of the latest check
+type Progress struct {
+	CheckedAt   time.Time                `json:"checked_at"`
+	NewestWrite time.Time                `json:"newest_write"` // newest row across all tables
+	LagSeconds  float64                  `json:"lag_seconds"`
+	Stale       bool                     `json:"stale"`
+	Tables      map[string]TableProgress `json:"tables"`
+	Error       string                   `json:"error,omitempty"` // error of the latest check
+}
+
+// Monitor periodically reads the indexer's progress. The lag is the age of the newest row in
+// any event table, so it also grows while nothing happens on chain; staleAfter should be set
+// above the usual quiet period.
+type Monitor struct {
+	db         *sql.DB
+	interval   time.Duration
+	staleAfter time.Duration
+
+	mu       sync.RWMutex
+	progress Progress
+}
+
+// NewMonitor creates a monitor checking db every interval and reporting the data as stale
+// once the lag exceeds staleAfter (0 never reports it stale)
+func NewMonitor(db *sql.DB, interval, staleAfter time.Duration) *Monitor {
+	return &Monitor{db: db, interval: interval, staleAfter: staleAfter}
+}
+
+// Run checks the indexer's progress right away and then every interval until stop is closed
+func (m *Monitor) Run(stop <-chan struct{}) {
+	m.checkAndLog()
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.checkAndLog()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// checkAndLog runs a check, logging instead of returning its error
+func (m *Monitor) checkAndLog() {
+	if err := m.Check(context.Background()); err != nil {
+		log.Printf("Warning: indexer lag check: %v", err)
+	}
+}
+
+// Check reads the indexer's progress now
+func (m *Monitor) Check(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	checkedAt := time.Now()
+	tables, newest, err := m.query(ctx, checkedAt)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err != nil {
+		// Keep the last known position; the lag keeps growing from it
+		m.progress.Error = err.Error()
+		return err
+	}
+	m.progress = Progress{CheckedAt: checkedAt, NewestWrite: newest, Tables: tables}
+	return nil
+}
+
+// query reads every table's newest row, translated to this server's clock
+func (m *Monitor) query(ctx context.Context, checkedAt time.Time) (map[string]TableProgress, time.Time, error) {
+	rows, err := m.db.QueryContext(ctx, progressQuery)
+	if err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to read indexer progress: %w", err)
+	}
+	defer rows.Close()
+
+	tables := make(map[string]TableProgress)
+	var newest time.Time
+	for rows.Next() {
+		var table string
+		var age sql.NullFloat64
+		var sequence sql.NullInt64
+		if err := rows.Scan(&table, &age, &sequence); err != nil {
+			return nil, time.Time{}, fmt.Errorf("failed to scan indexer progress: %w", err)
+		}
+
+		var progress TableProgress
+		if age.Valid {
+			written := checkedAt.Add(-time.Duration(age.Float64 * float64(time.Second)))
+			progress.NewestWrite = &written
+			if written.After(newest) {
+				newest = written
+			}
+		}
+		if sequence.Valid {
+			progress.Sequence = &sequence.Int64
+		}
+		tables[table] = progress
+	}
+	if err := rows.Err(); err != nil {
+		return nil, time.Time{}, fmt.Errorf("failed to read indexer progress: %w", err)
+	}
+	return tables, newest, nil
+}
+
+// Lag returns the age of the newest indexed row; ok is false until a check has seen one
+func (m *Monitor) Lag() (lag time.Duration, ok bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.progress.NewestWrite.IsZero() {
+		return 0, false
+	}
+	return time.Since(m.progress.NewestWrite), true
+}
+
+// Stale reports whether the lag exceeds the stale threshold
+func (m *Monitor) Stale() bool {
+	lag, ok := m.Lag()
+	return ok && m.staleAfter > 0 && lag > m.staleAfter
+}
+
+// Progress returns the latest check with the lag as of now
+func (m *Monitor) Progress() Progress {
+	m.mu.RLock()
+	progress := m.progress
+	m.mu.RUnlock()
+
+	if lag, ok := m.Lag(); ok {
+		progress.LagSeconds = lag.Seconds()
+		progress.Stale = m.Stale()
+	}
+	return progress
+}
+
+// Middleware adds the LagHeader to responses while the data is stale, so clients can warn
+// that what they show may be outdated
+func (m *Monitor) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if m.Stale() {
+			lag, _ := m.Lag()
+			c.Header(LagHeader, strconv.Itoa(int(lag.Seconds())))
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,64 @@
+//go:build integration
+
+package indexer
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"nadmon-backend/internal/testharness"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMonitor(t *testing.T) {
+	db := testharness.StartEnvioDB(t).DB
+	monitor := NewMonitor(db, time.Minute, time.Hour)
+
+	if _, ok := monitor.Lag(); ok {
+		t.Fatal("expected no lag before the first check")
+	}
+	if err := monitor.Check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fixture rows were just written
+	lag, ok := monitor.Lag()
+	if !ok || lag > time.Minute {
+		t.Fatalf("expected a fresh index, got lag %s (ok=%v)", lag, ok)
+	}
+	progress := monitor.Progress()
+	if len(progress.Tables) != 4 || progress.Stale {
+		t.Fatalf("expected 4 fresh tables, got %+v", progress)
+	}
+	if progress.Tables["NadmonNFT_NadmonMinted"].Sequence == nil || progress.Tables["NadmonNFT_Transfer"].Sequence != nil {
+		t.Errorf("expected sequences only for sequenced tables, got %+v", progress.Tables)
+	}
+
+	for _, table := range []string{"NadmonNFT_NadmonMinted", "NadmonNFT_PackMinted", "NadmonNFT_StatsChanged", "NadmonNFT_Transfer"} {
+		if _, err := db.Exec(`UPDATE "` + table + `" SET db_write_timestamp = LOCALTIMESTAMP - INTERVAL '2 hours'`); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := monitor.Check(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if !monitor.Stale() {
+		t.Fatalf("expected stale data, got %+v", monitor.Progress())
+	}
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(monitor.Middleware())
+	r.GET("/api/ping", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/ping", nil))
+	seconds, err := strconv.Atoi(w.Header().Get(LagHeader))
+	if err != nil || seconds < 7200 || seconds > 7260 {
+		t.Errorf("expected a lag header of about 7200s, got %q", w.Header().Get(LagHeader))
+	}
+}
@@ -0,0 +1,335 @@
+package loadtest
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// scenario is a single weighted request type in a traffic mix
+type scenario struct {
+	name   string
+	weight int
+	run    func(r *runner, rng *rand.Rand) error
+}
+
+// mixes are the predefined weighted traffic profiles
+var mixes = map[string][]scenario{
+	"inventory": {
+		{"inventory", 60, getInventory},
+		{"profile", 20, getProfile},
+		{"nft", 15, getNFT},
+		{"batch", 5, getBatch},
+	},
+	"leaderboard": {
+		{"leaderboard", 50, getLeaderboard},
+		{"game_stats", 30, getGameStats},
+		{"recent_packs", 20, getRecentPacks},
+	},
+	"ws-storm": {
+		{"ws_connect", 100, connectWebSocket},
+	},
+	"mixed": {
+		{"inventory", 35, getInventory},
+		{"profile", 10, getProfile},
+		{"nft", 15, getNFT},
+		{"batch", 5, getBatch},
+		{"leaderboard", 15, getLeaderboard},
+		{"game_stats", 10, getGameStats},
+		{"recent_packs", 5, getRecentPacks},
+		{"ws_connect", 5, connectWebSocket},
+	},
+}
+
+// runner holds the shared state of a load test run
+type runner struct {
+	target     string
+	addresses  []string
+	maxTokenID int
+	client     *http.Client
+
+	mu        sync.Mutex
+	latencies map[string][]time.Duration
+	errors    map[string]int
+}
+
+// Run executes the loadtest subcommand with the given arguments
+func Run(args []string) error {
+	fs := flag.NewFlagSet("loadtest", flag.ContinueOnError)
+	target := fs.String("target", "http://localhost:8081", "base URL of the instance under test")
+	mixName := fs.String("mix", "mixed", "traffic mix: inventory, leaderboard, ws-storm or mixed")
+	duration := fs.Duration("duration", 30*time.Second, "how long to generate load")
+	concurrency := fs.Int("concurrency", 20, "number of concurrent workers")
+	addresses := fs.String("addresses", "", "comma-separated player addresses to query (defaults to top collectors)")
+	maxTokenID := fs.Int("max-token-id", 1000, "highest token ID used for NFT lookups")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	mix, ok := mixes[*mixName]
+	if !ok {
+		return fmt.Errorf("unknown mix %q", *mixName)
+	}
+
+	r := &runner{
+		target:     strings.TrimRight(*target, "/"),
+		maxTokenID: *maxTokenID,
+		client:     &http.Client{Timeout: 30 * time.Second},
+		latencies:  make(map[string][]time.Duration),
+		errors:     make(map[string]int),
+	}
+
+	if *addresses != "" {
+		for _, address := range strings.Split(*addresses, ",") {
+			r.addresses = append(r.addresses, strings.TrimSpace(address))
+		}
+	} else if err := r.discoverAddresses(); err != nil {
+		return fmt.Errorf("failed to discover addresses, pass -addresses: %w", err)
+	}
+
+	fmt.Printf("🔥 Load testing %s with mix %q, %d workers for %s\n", r.target, *mixName, *concurrency, *duration)
+
+	totalWeight := 0
+	for _, s := range mix {
+		totalWeight += s.weight
+	}
+
+	deadline := time.Now().Add(*duration)
+	var wg sync.WaitGroup
+	for i := 0; i < *concurrency; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(seed))
+			for time.Now().Before(deadline) {
+				s := pick(mix, totalWeight, rng)
+				start := time.Now()
+				err := s.run(r, rng)
+				r.record(s.name, time.Since(start), err)
+			}
+		}(time.Now().UnixNano() + int64(i))
+	}
+	wg.Wait()
+
+	r.report(os.Stdout, *duration)
+	return nil
+}
+
+// pick selects a scenario according to its weight
+func pick(mix []scenario, totalWeight int, rng *rand.Rand) scenario {
+	n := rng.Intn(totalWeight)
+	for _, s := range mix {
+		if n < s.weight {
+			return s
+		}
+		n -= s.weight
+	}
+	return mix[len(mix)-1]
+}
+
+// record stores the outcome of a single request
+func (r *runner) record(name string, latency time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err != nil {
+		r.errors[name]++
+		return
+	}
+	r.latencies[name] = append(r.latencies[name], latency)
+}
+
+// discoverAddresses seeds the address pool from the collectors leaderboard
+func (r *runner) discoverAddresses() error {
+	var body struct {
+		Data []struct {
+			Address string `json:"address"`
+		} `json:"data"`
+	}
+	if err := r.getJSON("/api/leaderboard/collectors?limit=100", &body); err != nil {
+		return err
+	}
+	for _, collector := range body.Data {
+		r.addresses = append(r.addresses, collector.Address)
+	}
+	if len(r.addresses) == 0 {
+		return fmt.Errorf("leaderboard returned no collectors")
+	}
+	return nil
+}
+
+// get performs a GET request and treats any non-2xx status as an error
+func (r *runner) get(path string) error {
+	resp, err := r.client.Get(r.target + path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("GET %s: status %d", path, resp.StatusCode)
+	}
+	return nil
+}
+
+func (r *runner) randomAddress(rng *rand.Rand) string {
+	return r.addresses[rng.Intn(len(r.addresses))]
+}
+
+func (r *runner) randomTokenID(rng *rand.Rand) int {
+	return rng.Intn(r.maxTokenID) + 1
+}
+
+func getInventory(r *runner, rng *rand.Rand) error {
+	return r.get("/api/players/" + r.randomAddress(rng) + "/nadmons")
+}
+
+func getProfile(r *runner, rng *rand.Rand) error {
+	return r.get("/api/players/" + r.randomAddress(rng) + "/profile")
+}
+
+func getNFT(r *runner, rng *rand.Rand) error {
+	err := r.get(fmt.Sprintf("/api/nfts/%d", r.randomTokenID(rng)))
+	// Burned or unminted tokens legitimately return 404
+	if err != nil && strings.HasSuffix(err.Error(), "status 404") {
+		return nil
+	}
+	return err
+}
+
+func getBatch(r *runner, rng *rand.Rand) error {
+	ids := make([]string, 5)
+	for i := range ids {
+		ids[i] = fmt.Sprint(r.randomTokenID(rng))
+	}
+	return r.get("/api/nfts?ids=" + strings.Join(ids, ","))
+}
+
+func getLeaderboard(r *runner, rng *rand.Rand) error {
+	return r.get("/api/leaderboard/collectors?limit=100")
+}
+
+func getGameStats(r *runner, rng *rand.Rand) error {
+	return r.get("/api/stats/game")
+}
+
+func getRecentPacks(r *runner, rng *rand.Rand) error {
+	return r.get("/api/packs/recent?limit=20")
+}
+
+// connectWebSocket opens a WebSocket, waits for the welcome message and disconnects
+func connectWebSocket(r *runner, rng *rand.Rand) error {
+	u, err := url.Parse(r.target)
+	if err != nil {
+		return err
+	}
+	if u.Scheme == "https" {
+		u.Scheme = "wss"
+	} else {
+		u.Scheme = "ws"
+	}
+	u.Path = "/api/ws/" + r.randomAddress(rng)
+
+	// The server checks the Origin header against its allowed origins
+	header := http.Header{}
+	header.Set("Origin", os.Getenv("LOADTEST_ORIGIN"))
+	if header.Get("Origin") == "" {
+		header.Set("Origin", "http://localhost:3000")
+	}
+
+	conn, _, err := websocket.DefaultDialer.Dial(u.String(), header)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	_, _, err = conn.ReadMessage()
+	return err
+}
+
+// percentile returns the p-th percentile of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	if len(sorted) == 0 {
+		return 0
+	}
+	index := int(float64(len(sorted)-1) * p)
+	return sorted[index]
+}
+
+// report prints latency percentiles per scenario
+func (r *runner) report(out io.Writer, duration time.Duration) {
+	names := make(map[string]bool)
+	for name := range r.latencies {
+		names[name] = true
+	}
+	for name := range r.errors {
+		names[name] = true
+	}
+
+	sortedNames := make([]string, 0, len(names))
+	for name := range names {
+		sortedNames = append(sortedNames, name)
+	}
+	sort.Strings(sortedNames)
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "scenario\trequests\terrors\trps\tp50\tp90\tp99\tmax\t")
+
+	var all []time.Duration
+	totalErrors := 0
+	for _, name := range sortedNames {
+		latencies := r.latencies[name]
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		all = append(all, latencies...)
+		totalErrors += r.errors[name]
+		writeRow(w, name, latencies, r.errors[name], duration)
+	}
+
+	sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+	writeRow(w, "TOTAL", all, totalErrors, duration)
+	w.Flush()
+}
+
+func writeRow(w io.Writer, name string, sorted []time.Duration, errors int, duration time.Duration) {
+	requests := len(sorted) + errors
+	var max time.Duration
+	if len(sorted) > 0 {
+		max = sorted[len(sorted)-1]
+	}
+	fmt.Fprintf(w, "%s\t%d\t%d\t%.1f\t%s\t%s\t%s\t%s\t\n",
+		name, requests, errors, float64(requests)/duration.Seconds(),
+		round(percentile(sorted, 0.50)), round(percentile(sorted, 0.90)),
+		round(percentile(sorted, 0.99)), round(max))
+}
+
+func round(d time.Duration) time.Duration {
+	return d.Round(100 * time.Microsecond)
+}
+
+// getJSON performs a GET request and decodes the JSON response into v
+func (r *runner) getJSON(path string, v interface{}) error {
+	resp, err := r.client.Get(r.target + path)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("GET %s: status %d", path, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
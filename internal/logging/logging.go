@@ -0,0 +1,96 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+// Formats accepted by LOG_FORMAT
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Setup installs a slog logger writing records in format at level and above as the process
+// default. The standard log package is routed through it, so existing log.Printf calls
+// become structured records too, at the level their message starts with (see bridgedLevel).
+func Setup(format, level string) error {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("invalid log level %q: %w", level, err)
+	}
+
+	options := &slog.HandlerOptions{Level: lvl}
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case FormatJSON:
+		handler = slog.NewJSONHandler(os.Stderr, options)
+	case FormatText:
+		handler = slog.NewTextHandler(os.Stderr, options)
+	default:
+		return fmt.Errorf("unknown log format %q, expected %s or %s", format, FormatJSON, FormatText)
+	}
+
+	slog.SetDefault(slog.New(handler))
+	// slog.SetDefault routes the log package at INFO; keep warnings and errors at their level
+	log.SetFlags(0)
+	log.SetOutput(&bridge{handler: handler})
+	return nil
+}
+
+// bridgedLevel returns the level of a log package line: "Warning:" and ⚠️ lines are
+// warnings, "Error" and "Failed" lines errors, the rest info
+func bridgedLevel(message string) slog.Level {
+	switch {
+	case strings.HasPrefix(message, "Warning"), strings.HasPrefix(message, "⚠️"):
+		return slog.LevelWarn
+	case strings.HasPrefix(message, "Error"), strings.HasPrefix(message, "Failed"):
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// bridge writes log package lines to handler as records at their bridged level
+type bridge struct {
+	handler slog.Handler
+}
+
+func (b *bridge) Write(p []byte) (int, error) {
+	message := strings.TrimSuffix(string(p), "\n")
+	level := bridgedLevel(message)
+	ctx := context.Background()
+	if !b.handler.Enabled(ctx, level) {
+		return len(p), nil
+	}
+	if err := b.handler.Handle(ctx, slog.NewRecord(time.Now(), level, message, 0)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the request ID
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID carried by ctx, or "" outside a request
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// FromContext returns the default logger, tagged with the request ID carried by ctx
+func FromContext(ctx context.Context) *slog.Logger {
+	if id := RequestID(ctx); id != "" {
+		return slog.Default().With("request_id", id)
+	}
+	return slog.Default()
+}
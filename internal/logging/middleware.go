@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"regexp"
+	"runtime/debug"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the request ID on requests (set by a proxy) and responses
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID accepts IDs forwarded by proxies and load balancers; anything else is replaced
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,64}$`)
+
+// Middleware assigns every request an ID (reusing a valid incoming X-Request-ID), carries it
+// in the request context, echoes it in the X-Request-ID response header and adds it as
+// "request_id" to JSON error responses
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			id = newRequestID()
+		}
+
+		c.Request = c.Request.WithContext(WithRequestID(c.Request.Context(), id))
+		c.Header(RequestIDHeader, id)
+		c.Writer = &errorBodyWriter{ResponseWriter: c.Writer, requestID: id}
+		c.Next()
+	}
+}
+
+// Recovery turns panics into logged 500 responses tagged with the request ID
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(nil, func(c *gin.Context, recovered any) {
+		FromContext(c.Request.Context()).Error("panic recovered",
+			"error", fmt.Sprint(recovered), "path", c.Request.URL.Path, "stack", string(debug.Stack()))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	})
+}
+
+// newRequestID returns a random 128-bit hex ID
+func newRequestID() string {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(buf)
+}
+
+// errorBodyWriter adds the request ID to JSON object bodies of 4xx/5xx responses, so
+// handlers and middleware don't each have to
+type errorBodyWriter struct {
+	gin.ResponseWriter
+	requestID string
+}
+
+func (w *errorBodyWriter) Write(data []byte) (int, error) {
+	if w.Status() < http.StatusBadRequest ||
+		!strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") ||
+		!bytes.HasPrefix(data, []byte("{")) {
+		return w.ResponseWriter.Write(data)
+	}
+
+	field := fmt.Sprintf(`{"request_id":%q`, w.requestID)
+	if !bytes.Equal(bytes.TrimSpace(data[1:]), []byte("}")) {
+		field += ","
+	}
+	if _, err := w.ResponseWriter.Write(append([]byte(field), data[1:]...)); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
@@ -0,0 +1,152 @@
+// Package metrics exposes Prometheus metrics for HTTP routes, repository queries,
+// WebSocket connections and the cache.
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Cache lookup results
+const (
+	CacheHit   = "hit"
+	CacheMiss  = "miss"
+	CacheError = "error"
+
+	// CacheShared counts micro-cache misses whose load was shared with concurrent requests
+	CacheShared = "shared"
+)
+
+// registry holds only our collectors plus the Go runtime and process ones
+var registry = prometheus.NewRegistry()
+
+var (
+	httpRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "nadmon_http_request_duration_seconds",
+		Help:    "HTTP request latency by route, method and status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	dbQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "nadmon_db_query_duration_seconds",
+		Help:    "Repository query latency by method.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method"})
+
+	dbQueryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "nadmon_db_query_errors_total",
+		Help: "Failed repository queries by method.",
+	}, []string{"method"})
+
+	cacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "nadmon_cache_requests_total",
+		Help: "Cache lookups by result (hit, miss, error).",
+	}, []string{"result"})
+
+	microCacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "nadmon_micro_cache_requests_total",
+		Help: "In-process micro-cache lookups by result (hit, miss, shared).",
+	}, []string{"result"})
+
+	dedupedRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "nadmon_deduped_requests_total",
+		Help: "Deduplicated repository reads by whether they shared a concurrent identical read (shared) or ran their own (single).",
+	}, []string{"result"})
+
+	slowOperations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "nadmon_slow_operations_total",
+		Help: "Queries over SLOW_QUERY_THRESHOLD and requests over their latency budget, by kind and query name or route.",
+	}, []string{"kind", "name"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequestDuration,
+		dbQueryDuration,
+		dbQueryErrors,
+		cacheRequests,
+		microCacheRequests,
+		dedupedRequests,
+		slowOperations,
+	)
+}
+
+// Handler serves the metrics in the Prometheus text format
+func Handler() gin.HandlerFunc {
+	h := promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+	return gin.WrapH(h)
+}
+
+// Middleware records request latency labelled by the matched route pattern
+func Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		// Use the route pattern rather than the raw path to keep label cardinality bounded
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		httpRequestDuration.
+			WithLabelValues(route, c.Request.Method, strconv.Itoa(c.Writer.Status())).
+			Observe(time.Since(start).Seconds())
+	}
+}
+
+// ObserveQuery records the duration and outcome of a repository method call
+func ObserveQuery(method string, start time.Time, err error) {
+	dbQueryDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		dbQueryErrors.WithLabelValues(method).Inc()
+	}
+}
+
+// ObserveCache counts a cache lookup with its result
+func ObserveCache(result string) {
+	cacheRequests.WithLabelValues(result).Inc()
+}
+
+// ObserveMicroCache counts an in-process micro-cache lookup with its result
+func ObserveMicroCache(result string) {
+	microCacheRequests.WithLabelValues(result).Inc()
+}
+
+// ObserveDedup counts a deduplicated read by whether its result was shared
+func ObserveDedup(shared bool) {
+	if shared {
+		dedupedRequests.WithLabelValues("shared").Inc()
+	} else {
+		dedupedRequests.WithLabelValues("single").Inc()
+	}
+}
+
+// ObserveSlowOperation counts a slow query or a request over its latency budget
+func ObserveSlowOperation(kind, name string) {
+	slowOperations.WithLabelValues(kind, name).Inc()
+}
+
+// RegisterWebSocketGauge exposes the number of connected WebSocket clients
+func RegisterWebSocketGauge(connected func() int) {
+	registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "nadmon_websocket_connections",
+		Help: "Currently connected WebSocket clients.",
+	}, func() float64 {
+		return float64(connected())
+	}))
+}
+
+// RegisterIndexerLagGauge exposes the age in seconds of the newest indexed row
+func RegisterIndexerLagGauge(lag func() float64) {
+	registry.MustRegister(prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Name: "nadmon_indexer_lag_seconds",
+		Help: "Age of the newest row in the indexer's event tables (NaN before the first check).",
+	}, lag))
+}
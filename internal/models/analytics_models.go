@@ -0,0 +1,98 @@
+package models
+
+import "sort"
+
+// NewPackDistribution builds the packs-per-player histogram from bucket counts
+// ordered as 1, 2-5, 6-20 and 20+ packs
+func NewPackDistribution(buckets [4]int, totalPlayers, totalPacks int, mean, median float64) *PackDistribution {
+	return &PackDistribution{
+		Buckets: []PackDistributionBucket{
+			{Label: "1", Min: 1, Max: 1, Players: buckets[0]},
+			{Label: "2-5", Min: 2, Max: 5, Players: buckets[1]},
+			{Label: "6-20", Min: 6, Max: 20, Players: buckets[2]},
+			{Label: "20+", Min: 21, Players: buckets[3]},
+		},
+		TotalPlayers: totalPlayers,
+		TotalPacks:   totalPacks,
+		Mean:         mean,
+		Median:       median,
+	}
+}
+
+// NewOwnershipConcentration computes top-holder shares and the Gini coefficient
+// from per-holder balances sorted in descending order
+func NewOwnershipConcentration(balances []int) *OwnershipConcentration {
+	supply := 0
+	for _, balance := range balances {
+		supply += balance
+	}
+
+	concentration := &OwnershipConcentration{
+		CirculatingSupply: supply,
+		TotalHolders:      len(balances),
+		TopHolderShares:   make([]HolderShare, 0, 3),
+	}
+
+	for _, n := range []int{1, 10, 100} {
+		tokens := 0
+		for i := 0; i < n && i < len(balances); i++ {
+			tokens += balances[i]
+		}
+		share := 0.0
+		if supply > 0 {
+			share = float64(tokens) / float64(supply)
+		}
+		concentration.TopHolderShares = append(concentration.TopHolderShares, HolderShare{
+			TopHolders: n,
+			Tokens:     tokens,
+			Share:      share,
+		})
+	}
+
+	// Gini coefficient over ascending balances: G = (2 * sum(i * x_i)) / (n * sum(x)) - (n + 1) / n
+	if n := len(balances); n > 0 && supply > 0 {
+		weighted := 0.0
+		for i := 0; i < n; i++ {
+			weighted += float64(i+1) * float64(balances[n-1-i])
+		}
+		concentration.Gini = 2*weighted/(float64(n)*float64(supply)) - float64(n+1)/float64(n)
+	}
+
+	return concentration
+}
+
+// NewTraitBreakdown computes the averages, shares and evolution rates of the elements or
+// rarities (dimension) from their totals
+func NewTraitBreakdown(dimension string, totals []TraitTotals) *TraitBreakdown {
+	breakdown := &TraitBreakdown{Dimension: dimension, Traits: make([]TraitStats, 0, len(totals))}
+	for _, total := range totals {
+		breakdown.Circulating += total.Count
+	}
+
+	for _, total := range totals {
+		if total.Count == 0 {
+			continue
+		}
+		n := float64(total.Count)
+		breakdown.Traits = append(breakdown.Traits, TraitStats{
+			Value:         total.Value,
+			Count:         total.Count,
+			Share:         n / float64(breakdown.Circulating),
+			AvgHP:         float64(total.HP) / n,
+			AvgAttack:     float64(total.Attack) / n,
+			AvgDefense:    float64(total.Defense) / n,
+			AvgCrit:       float64(total.Crit) / n,
+			Evolved:       total.Evolved,
+			EvolutionRate: float64(total.Evolved) / n,
+		})
+	}
+	sort.Slice(breakdown.Traits, func(i, j int) bool {
+		a, b := breakdown.Traits[i], breakdown.Traits[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		return a.Value < b.Value
+	})
+
+	return breakdown
+}
@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+// API key scopes: read covers the public read endpoints, analytics the aggregate stats and
+// analytics endpoints
+const (
+	APIKeyScopeRead      = "read"
+	APIKeyScopeAnalytics = "analytics"
+)
+
+// APIKeyScopes lists every API key scope
+var APIKeyScopes = []string{APIKeyScopeRead, APIKeyScopeAnalytics}
+
+// APIKey is a partner integration's key to the public API
+type APIKey struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// Prefix is the start of the key, enough to tell keys apart without revealing them
+	Prefix string   `json:"prefix"`
+	Scopes []string `json:"scopes"`
+	// RateLimit and Burst are the key's token bucket, replacing the per-IP limits
+	RateLimit float64 `json:"rate_limit"`
+	Burst     int     `json:"burst"`
+	// DailyQuota caps the requests per UTC day; 0 means unlimited
+	DailyQuota int64      `json:"daily_quota"`
+	CreatedAt  time.Time  `json:"created_at"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	// Usage is only filled in by listings
+	Usage *APIKeyUsageSummary `json:"usage,omitempty"`
+	// Key is the secret itself; it is only returned when the key is issued
+	Key string `json:"key,omitempty"`
+}
+
+// HasScope reports whether the key grants scope
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// Active reports whether the key hasn't been revoked
+func (k *APIKey) Active() bool {
+	return k.RevokedAt == nil
+}
+
+// APIKeyUsage counts one key's requests on one UTC day
+type APIKeyUsage struct {
+	Day      string `json:"day"` // YYYY-MM-DD
+	Requests int64  `json:"requests"`
+	Rejected int64  `json:"rejected"` // over the rate limit or the daily quota
+}
+
+// APIKeyUsageSummary totals a key's recent usage
+type APIKeyUsageSummary struct {
+	Today      int64 `json:"today"`
+	Last30Days int64 `json:"last_30_days"`
+	Rejected30 int64 `json:"rejected_last_30_days"`
+}
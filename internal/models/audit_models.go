@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// AuditEntry records one API read of a player's data: who asked (API key and client IP),
+// for which address, through which route and when
+type AuditEntry struct {
+	ID      int64  `json:"id"`
+	Address string `json:"address"`
+	// APIKeyID is the partner API key the request was authenticated with; 0 without one
+	APIKeyID   int64  `json:"api_key_id,omitempty"`
+	APIKeyName string `json:"api_key_name,omitempty"`
+	// ClientIP is the client as resolved through TRUSTED_PROXIES; RemoteAddr is the address
+	// of the connection itself, which the client can't forge
+	ClientIP    string    `json:"client_ip"`
+	RemoteAddr  string    `json:"remote_addr"`
+	Method      string    `json:"method"`
+	Route       string    `json:"route"`
+	Status      int       `json:"status"`
+	RequestID   string    `json:"request_id,omitempty"`
+	RequestedAt time.Time `json:"requested_at"`
+}
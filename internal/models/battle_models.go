@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// PvP rating settings: every player starts at InitialPvPRating and a battle moves a rating by
+// at most PvPKFactor points
+const (
+	InitialPvPRating = 1200
+	PvPKFactor       = 32
+)
+
+// MaxBattleIDLength caps the length of the game server's battle IDs
+const MaxBattleIDLength = 128
+
+// Battle results from a player's point of view
+const (
+	BattleResultWin  = "win"
+	BattleResultLoss = "loss"
+	BattleResultDraw = "draw"
+)
+
+// BattleSide is one player of a battle, with the team they fielded and their PvP rating
+// before and after it
+type BattleSide struct {
+	Address      string  `json:"address"`
+	TeamTokenIDs []int64 `json:"team_token_ids"`
+	RatingBefore int64   `json:"rating_before"`
+	RatingAfter  int64   `json:"rating_after"`
+}
+
+// Battle is the result of an off-chain battle reported by the game server
+type Battle struct {
+	BattleID string     `json:"battle_id"`
+	Player1  BattleSide `json:"player1"`
+	Player2  BattleSide `json:"player2"`
+	// Winner is the winning address, nil for a draw
+	Winner     *string   `json:"winner"`
+	FoughtAt   time.Time `json:"fought_at"`
+	RecordedAt time.Time `json:"recorded_at"`
+	// Result is win, loss or draw for the player whose battles are listed
+	Result string `json:"result,omitempty"`
+}
+
+// BattlePage is one page of a player's battles, newest first
+type BattlePage struct {
+	Battles []Battle `json:"battles"`
+	Total   int      `json:"total"`
+}
+
+// PvPRecord is a player's PvP rating and win/loss record
+type PvPRecord struct {
+	Rank         int64           `json:"rank"`
+	Address      string          `json:"address"`
+	Rating       int64           `json:"rating"`
+	Wins         int64           `json:"wins"`
+	Losses       int64           `json:"losses"`
+	Draws        int64           `json:"draws"`
+	Battles      int64           `json:"battles"`
+	LastBattleAt time.Time       `json:"last_battle_at"`
+	Display      *DisplayProfile `json:"display,omitempty"`
+	DisplayName  string          `json:"display_name,omitempty"` // name service name
+}
@@ -0,0 +1,180 @@
+package models
+
+import (
+	"time"
+
+	"nadmon-backend/internal/gamedata"
+)
+
+// Bounds on the number of Nadmons one comparison takes
+const (
+	MinCompared = 2
+	MaxCompared = 5
+)
+
+// ComparedNadmon is one Nadmon of a comparison with its rank and progression so far
+type ComparedNadmon struct {
+	TokenID    int64   `json:"token_id"`
+	Owner      string  `json:"owner"`
+	NadmonType string  `json:"nadmon_type"`
+	Element    string  `json:"element"`
+	Rarity     string  `json:"rarity"`
+	Stats      StatSet `json:"stats"`
+	// Power is the game data's power stat, by default the sum of hp, attack, defense and crit
+	Power int64 `json:"power"`
+	// RarityRank and RarityScore are nil when the Nadmon isn't ranked or ranks are unavailable
+	RarityRank  *int64   `json:"rarity_rank"`
+	RarityScore *float64 `json:"rarity_score"`
+	Evolutions  int      `json:"evolutions"`
+	Fusions     int      `json:"fusions"`
+	// LastChangedAt is the time of the latest evolution or fusion; nil when it never changed
+	LastChangedAt *time.Time `json:"last_changed_at"`
+}
+
+// StatDelta is how much a Nadmon's stats differ from the comparison's base, positive when
+// it has more
+type StatDelta struct {
+	TokenID int64   `json:"token_id"`
+	Stats   StatSet `json:"stats"`
+	Power   int64   `json:"power"`
+}
+
+// HistoryOverlap is what the compared Nadmons' progression has in common
+type HistoryOverlap struct {
+	// SameSpecies is set when every Nadmon shares type and element, so one can consume
+	// the others in fusions
+	SameSpecies bool `json:"same_species"`
+	// SharedChangeTypes are the change types (evolution, fusion) every Nadmon went through
+	SharedChangeTypes []string `json:"shared_change_types"`
+	// Unchanged lists the Nadmons that never evolved or fused
+	Unchanged []int64 `json:"unchanged"`
+}
+
+// NadmonComparison puts 2 to 5 Nadmons side by side. Deltas compare every other Nadmon to
+// the base, the first one requested; Best names the Nadmon with the highest value of each
+// stat and of power, the first requested on ties.
+type NadmonComparison struct {
+	Base    int64            `json:"base"`
+	Nadmons []ComparedNadmon `json:"nadmons"`
+	Deltas  []StatDelta      `json:"deltas"`
+	Best    map[string]int64 `json:"best"`
+	History HistoryOverlap   `json:"history"`
+}
+
+// NewNadmonComparison compares nadmons, in the order given, with their stat changes oldest
+// first and their rarity ranks; either map may miss tokens
+func NewNadmonComparison(nadmons []Nadmon, histories map[int64][]StatsChange, ranks map[int64]*NFTRarity) *NadmonComparison {
+	comparison := &NadmonComparison{
+		Nadmons: make([]ComparedNadmon, len(nadmons)),
+		Deltas:  []StatDelta{},
+		Best:    make(map[string]int64),
+		History: HistoryOverlap{SameSpecies: true, SharedChangeTypes: []string{}, Unchanged: []int64{}},
+	}
+	if len(nadmons) == 0 {
+		return comparison
+	}
+	comparison.Base = nadmons[0].TokenID
+
+	changeTypes := make(map[string]int)
+	for i, nadmon := range nadmons {
+		compared := ComparedNadmon{
+			TokenID:    nadmon.TokenID,
+			Owner:      nadmon.Owner,
+			NadmonType: nadmon.NadmonType,
+			Element:    nadmon.Element,
+			Rarity:     nadmon.Rarity,
+			Stats:      nadmon.Stats(),
+		}
+		compared.Power = compared.Stats.Power()
+		if rank := ranks[nadmon.TokenID]; rank != nil {
+			compared.RarityRank = &rank.Rank
+			compared.RarityScore = &rank.Score
+		}
+
+		seen := make(map[string]bool)
+		for _, change := range histories[nadmon.TokenID] {
+			switch change.ChangeType {
+			case "evolution":
+				compared.Evolutions++
+			case "fusion":
+				compared.Fusions++
+			}
+			changedAt := change.ChangedAt
+			compared.LastChangedAt = &changedAt
+			if !seen[change.ChangeType] {
+				seen[change.ChangeType] = true
+				changeTypes[change.ChangeType]++
+			}
+		}
+		if len(histories[nadmon.TokenID]) == 0 {
+			comparison.History.Unchanged = append(comparison.History.Unchanged, nadmon.TokenID)
+		}
+		if nadmon.NadmonType != nadmons[0].NadmonType || nadmon.Element != nadmons[0].Element {
+			comparison.History.SameSpecies = false
+		}
+
+		comparison.Nadmons[i] = compared
+	}
+
+	base := comparison.Nadmons[0]
+	for _, compared := range comparison.Nadmons[1:] {
+		comparison.Deltas = append(comparison.Deltas, StatDelta{
+			TokenID: compared.TokenID,
+			Stats:   compared.Stats.Minus(base.Stats),
+			Power:   compared.Power - base.Power,
+		})
+	}
+
+	metrics := append(append([]string(nil), StatMetrics...), "power")
+	for _, metric := range metrics {
+		best := base
+		for _, compared := range comparison.Nadmons[1:] {
+			if compared.value(metric) > best.value(metric) {
+				best = compared
+			}
+		}
+		comparison.Best[metric] = best.TokenID
+	}
+
+	for _, changeType := range []string{"evolution", "fusion"} {
+		if changeTypes[changeType] == len(nadmons) {
+			comparison.History.SharedChangeTypes = append(comparison.History.SharedChangeTypes, changeType)
+		}
+	}
+
+	return comparison
+}
+
+// value returns the stat named metric, or the power
+func (c ComparedNadmon) value(metric string) int64 {
+	if metric == "power" {
+		return c.Power
+	}
+	return c.Stats.Value(metric)
+}
+
+// Stats returns the Nadmon's current stats
+func (n *Nadmon) Stats() StatSet {
+	return StatSet{
+		HP: n.HP, Attack: n.Attack, Defense: n.Defense,
+		Crit: n.Crit, Fusion: n.Fusion, Evo: n.Evo,
+	}
+}
+
+// Power derives the power stat with the game data's formula, by default the sum of the
+// battle stats: hp, attack, defense and crit
+func (s StatSet) Power() int64 {
+	return gamedata.Current().Derive(gamedata.StatPower, s.Vars())
+}
+
+// Minus returns s less other, stat by stat
+func (s StatSet) Minus(other StatSet) StatSet {
+	return StatSet{
+		HP:      s.HP - other.HP,
+		Attack:  s.Attack - other.Attack,
+		Defense: s.Defense - other.Defense,
+		Crit:    s.Crit - other.Crit,
+		Fusion:  s.Fusion - other.Fusion,
+		Evo:     s.Evo - other.Evo,
+	}
+}
@@ -1,8 +1,12 @@
 package models
 
 import (
+	"fmt"
+	"math"
 	"strings"
 	"time"
+
+	"nadmon-backend/internal/gamedata"
 )
 
 // EnvioNadmonMinted represents the NadmonNFT_NadmonMinted table from Envio
@@ -83,6 +87,20 @@ type Nadmon struct {
 	LastUpdated time.Time `json:"last_updated"`
 }
 
+// Token lifecycle statuses reported by NFT detail and batch responses
+const (
+	StatusActive  = "active"
+	StatusBurned  = "burned"
+	StatusUnknown = "unknown"
+)
+
+// NadmonStatus reports whether a token exists and whether it was burned (e.g. consumed in a fusion)
+type NadmonStatus struct {
+	TokenID  int64      `json:"id"`
+	Status   string     `json:"status"`
+	BurnedAt *time.Time `json:"burnedAt,omitempty"`
+}
+
 // Pack represents a pack purchase (API response model)
 type Pack struct {
 	PackID      int64     `json:"pack_id"`
@@ -90,15 +108,31 @@ type Pack struct {
 	TokenIDs    []int64   `json:"token_ids"`
 	PaymentType string    `json:"payment_type"`
 	PurchasedAt time.Time `json:"purchased_at"`
+	DisplayName string    `json:"display_name,omitempty"` // name service name of the player
 }
 
 // PlayerProfile represents aggregated player data
 type PlayerProfile struct {
-	Address     string    `json:"address"`
-	TotalNFTs   int       `json:"total_nfts"`
-	PacksBought int       `json:"packs_bought"`
-	Nadmons     []Nadmon  `json:"nadmons"`
-	LastActive  time.Time `json:"last_active"`
+	Address     string          `json:"address"`
+	TotalNFTs   int             `json:"total_nfts"`
+	PacksBought int             `json:"packs_bought"`
+	Nadmons     []Nadmon        `json:"nadmons"`
+	LastActive  time.Time       `json:"last_active"`
+	Display     *DisplayProfile `json:"display,omitempty"`
+	DisplayName string          `json:"display_name,omitempty"` // name service name
+}
+
+// PlayerSummary is a player profile without the Nadmon list: their holdings are counted by
+// rarity and element instead
+type PlayerSummary struct {
+	Address       string          `json:"address"`
+	TotalNFTs     int             `json:"total_nfts"`
+	PacksBought   int             `json:"packs_bought"`
+	EvolvedNFTs   int             `json:"evolved_nfts"`
+	RarityCounts  map[string]int  `json:"rarity_counts"`
+	ElementCounts map[string]int  `json:"element_counts"`
+	LastActive    time.Time       `json:"last_active"`
+	Display       *DisplayProfile `json:"display,omitempty"`
 }
 
 // StatsChange represents an evolution/fusion event
@@ -111,6 +145,160 @@ type StatsChange struct {
 	ChangedAt   time.Time `json:"changed_at"`
 }
 
+// ZeroAddress is the sender of mints and the recipient of burns
+const ZeroAddress = "0x0000000000000000000000000000000000000000"
+
+// Transfer kinds, derived from the zero address on either side
+const (
+	TransferKindMint     = "mint"
+	TransferKindTransfer = "transfer"
+	TransferKindBurn     = "burn"
+)
+
+// Transfer represents a single ownership change of an NFT
+type Transfer struct {
+	ID            string    `json:"id"`
+	TokenID       int64     `json:"token_id"`
+	From          string    `json:"from"`
+	To            string    `json:"to"`
+	Kind          string    `json:"kind"`
+	TransferredAt time.Time `json:"transferred_at"`
+}
+
+// TransferPage is one page of transfer history with the total number of transfers
+type TransferPage struct {
+	Transfers []Transfer `json:"transfers"`
+	Total     int        `json:"total"`
+}
+
+// NadmonSearchPage is one keyset page of a collection-wide search; NextCursor is empty on
+// the last page
+type NadmonSearchPage struct {
+	Nadmons    []Nadmon `json:"nadmons"`
+	Total      int      `json:"total"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
+
+// Sale represents a marketplace sale of an NFT; prices are wei amounts as decimal strings
+type Sale struct {
+	ID      string    `json:"id"`
+	TokenID int64     `json:"token_id"`
+	Seller  string    `json:"seller"`
+	Buyer   string    `json:"buyer"`
+	Price   string    `json:"price"`
+	SoldAt  time.Time `json:"sold_at"`
+}
+
+// SalePage is one page of sale history with the total number of sales
+type SalePage struct {
+	Sales []Sale `json:"sales"`
+	Total int    `json:"total"`
+}
+
+// MarketStats represents sale volume and prices of a set of NFTs, in wei
+type MarketStats struct {
+	Sales      int        `json:"sales"`
+	Volume     string     `json:"volume"`
+	FloorPrice *string    `json:"floor_price"` // lowest sale in the floor window, nil without recent sales
+	LastPrice  *string    `json:"last_price"`
+	LastSoldAt *time.Time `json:"last_sold_at"`
+}
+
+// MarketReport represents market stats overall and per rarity and element
+type MarketReport struct {
+	MarketStats
+	FloorWindow string                 `json:"floor_window"`
+	ByRarity    map[string]MarketStats `json:"by_rarity"`
+	ByElement   map[string]MarketStats `json:"by_element"`
+}
+
+// Activity feed entry types
+const (
+	ActivityMint        = "mint"
+	ActivityTransfer    = "transfer"
+	ActivityTransferIn  = "transfer_in"
+	ActivityTransferOut = "transfer_out"
+	ActivityEvolution   = "evolution"
+	ActivityPack        = "pack"
+)
+
+// ActivityTypes lists every activity feed entry type
+var ActivityTypes = []string{ActivityMint, ActivityTransfer, ActivityEvolution, ActivityPack}
+
+// PlayerActivityTypes lists every player activity feed entry type; transfers are split by
+// direction relative to the player
+var PlayerActivityTypes = []string{ActivityMint, ActivityTransferIn, ActivityTransferOut, ActivityEvolution, ActivityPack}
+
+// Activity is one entry of the global or a player's activity feed. Detail holds the rarity of
+// a mint, the transfer kind, the stat change type or the pack payment type.
+type Activity struct {
+	ID           string    `json:"id"`
+	Type         string    `json:"type"`
+	Player       string    `json:"player,omitempty"`
+	Counterparty string    `json:"counterparty,omitempty"`
+	TokenID      int64     `json:"token_id,omitempty"`
+	PackID       int64     `json:"pack_id,omitempty"`
+	Detail       string    `json:"detail"`
+	OccurredAt   time.Time `json:"occurred_at"`
+
+	// Name service names of the player and counterparty
+	DisplayName             string `json:"display_name,omitempty"`
+	CounterpartyDisplayName string `json:"counterparty_display_name,omitempty"`
+}
+
+// ActivityPage is one page of the activity feed with the total number of entries
+type ActivityPage struct {
+	Activities []Activity `json:"activities"`
+	Total      int        `json:"total"`
+}
+
+// Leaderboard types served by /api/leaderboard/{type}
+const (
+	LeaderboardEvolutions  = "evolutions"
+	LeaderboardFusion      = "fusion"
+	LeaderboardPacks       = "packs"
+	LeaderboardRarityScore = "rarity_score"
+)
+
+// LeaderboardTypes lists every leaderboard type
+var LeaderboardTypes = []string{LeaderboardEvolutions, LeaderboardFusion, LeaderboardPacks, LeaderboardRarityScore}
+
+// LeaderboardEntry is one ranked player; players with equal scores share a rank
+type LeaderboardEntry struct {
+	Rank        int             `json:"rank"`
+	Address     string          `json:"address"`
+	Score       int64           `json:"score"`
+	Display     *DisplayProfile `json:"display,omitempty"`
+	DisplayName string          `json:"display_name,omitempty"` // name service name
+}
+
+// Leaderboard is one page of a leaderboard, with the requesting player's own entry when
+// a player was given and is ranked
+type Leaderboard struct {
+	Type         string             `json:"type"`
+	Entries      []LeaderboardEntry `json:"entries"`
+	TotalPlayers int                `json:"total_players"`
+	Player       *LeaderboardEntry  `json:"player,omitempty"`
+}
+
+// DexSpecies is one type/element/rarity combination; Owned counts the player's tokens of it
+type DexSpecies struct {
+	Type    string `json:"type"`
+	Element string `json:"element"`
+	Rarity  string `json:"rarity"`
+	Owned   int    `json:"owned,omitempty"`
+}
+
+// Dex is a player's Nadmondex: the species they own out of every species minted so far
+type Dex struct {
+	Address      string       `json:"address"`
+	TotalSpecies int          `json:"total_species"`
+	OwnedSpecies int          `json:"owned_species"`
+	Completion   float64      `json:"completion"` // percentage of species owned
+	Owned        []DexSpecies `json:"owned"`
+	Missing      []DexSpecies `json:"missing"`
+}
+
 // StatSet represents a set of stats
 type StatSet struct {
 	HP      int64 `json:"hp"`
@@ -121,22 +309,97 @@ type StatSet struct {
 	Evo     int64 `json:"evo"`
 }
 
-// GetImageURL generates the local image path for a Nadmon based on type and evolution
-func (n *Nadmon) GetImageURL() string {
-	stage := "i"
-	if n.Evo == 2 {
-		stage = "ii"
-	} else if n.Fusion == 10 {
-		stage = "max"
+// StatMetrics lists the stats a StatSet holds, by their JSON names
+var StatMetrics = []string{"hp", "attack", "defense", "crit", "fusion", "evo"}
+
+// Value returns the stat named metric, one of StatMetrics
+func (s StatSet) Value(metric string) int64 {
+	switch metric {
+	case "hp":
+		return s.HP
+	case "attack":
+		return s.Attack
+	case "defense":
+		return s.Defense
+	case "crit":
+		return s.Crit
+	case "fusion":
+		return s.Fusion
+	case "evo":
+		return s.Evo
 	}
-	
-	// Use local images from /public/monster/ directory (much faster than IPFS!)
-	return "/monster/" + strings.ToLower(n.NadmonType) + "-" + stage + ".png"
+	return 0
+}
+
+// Vars returns the stats by name, as game data formulas read them
+func (s StatSet) Vars() map[string]int64 {
+	vars := make(map[string]int64, len(StatMetrics))
+	for _, metric := range StatMetrics {
+		vars[metric] = s.Value(metric)
+	}
+	return vars
+}
+
+// StatTimelineMint is the event of a stat timeline's first point
+const StatTimelineMint = "mint"
+
+// StatTimeline is a Nadmon's stats over time, shaped for charting libraries: point i of every
+// series was reached at Timestamps[i] by Events[i]. The first point is the mint.
+type StatTimeline struct {
+	TokenID    int64        `json:"token_id"`
+	Timestamps []int64      `json:"timestamps"` // Unix milliseconds
+	Events     []string     `json:"events"`     // mint, then each change's type
+	Series     []StatSeries `json:"series"`
+}
+
+// StatSeries is one stat's values along a StatTimeline
+type StatSeries struct {
+	Metric string  `json:"metric"`
+	Values []int64 `json:"values"`
+}
+
+// ArtworkStage returns the artwork stage of a Nadmon: "ii" once fully evolved, "max" at
+// max fusion, "i" otherwise
+func (n *Nadmon) ArtworkStage() string {
+	if n.Evo == MaxEvo {
+		return "ii"
+	} else if n.Fusion == MaxFusion {
+		return "max"
+	}
+	return "i"
+}
+
+// ArtworkNames returns the artwork file names of a Nadmon, most specific first: the art of
+// its stage and fusion level, of its stage, then the base art
+func (n *Nadmon) ArtworkNames() []string {
+	name := strings.ToLower(n.NadmonType)
+	stage := n.ArtworkStage()
+	names := []string{
+		fmt.Sprintf("%s-%s-f%d.png", name, stage, n.Fusion),
+		name + "-" + stage + ".png",
+	}
+	if stage != "i" {
+		names = append(names, name+"-i.png")
+	}
+	return names
+}
+
+// ArtworkVersion identifies the artwork a Nadmon currently shows; it changes on evolution
+// and fusion
+func (n *Nadmon) ArtworkVersion() string {
+	return fmt.Sprintf("%s-f%d", n.ArtworkStage(), n.Fusion)
+}
+
+// GetImageURL returns the path of the image served for a Nadmon, versioned so caches pick
+// up evolutions and fusions
+func (n *Nadmon) GetImageURL() string {
+	return fmt.Sprintf("/api/images/%d?v=%s", n.TokenID, n.ArtworkVersion())
 }
 
-// CalculateSpeed generates speed stat based on other stats (for frontend compatibility)
+// CalculateSpeed derives the speed stat from the other stats with the game data's formula
+// (for frontend compatibility)
 func (n *Nadmon) CalculateSpeed() int64 {
-	return (n.HP + n.Attack + n.Defense) / 10
+	return gamedata.Current().Derive(gamedata.StatSpeed, n.Stats().Vars())
 }
 
 // ToFrontendFormat converts Nadmon to frontend-compatible format
@@ -158,31 +421,83 @@ func (n *Nadmon) ToFrontendFormat() map[string]interface{} {
 	}
 }
 
-// GetElementColor returns the color for a given element
-func GetElementColor(element string) string {
-	colorMap := map[string]string{
-		"Fire":     "#ff6b6b",
-		"Water":    "#4ecdc4",
-		"Nature":   "#95e1d3",
-		"Earth":    "#8b5a3c",
-		"Electric": "#ffd93d",
-		"Ice":      "#74c0fc",
-		"Dark":     "#495057",
-		"Light":    "#ffd43b",
-	}
-	
-	if color, exists := colorMap[element]; exists {
-		return color
+// ERC721Attribute is a single trait in ERC-721 metadata
+type ERC721Attribute struct {
+	TraitType   string      `json:"trait_type"`
+	Value       interface{} `json:"value"`
+	DisplayType string      `json:"display_type,omitempty"`
+	MaxValue    int         `json:"max_value,omitempty"`
+}
+
+// ERC721Metadata is the standard token metadata JSON read by wallets and marketplaces
+type ERC721Metadata struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	Image       string            `json:"image"`
+	ExternalURL string            `json:"external_url,omitempty"`
+	Attributes  []ERC721Attribute `json:"attributes"`
+}
+
+// ToERC721Metadata converts Nadmon to ERC-721 metadata; image and external URLs are made
+// absolute with baseURL
+func (n *Nadmon) ToERC721Metadata(baseURL string) ERC721Metadata {
+	baseURL = strings.TrimRight(baseURL, "/")
+	return ERC721Metadata{
+		Name:        fmt.Sprintf("%s #%d", n.NadmonType, n.TokenID),
+		Description: fmt.Sprintf("A %s %s-type %s Nadmon.", strings.ToLower(n.Rarity), n.Element, n.NadmonType),
+		Image:       baseURL + n.GetImageURL(),
+		ExternalURL: fmt.Sprintf("%s/nft/%d", baseURL, n.TokenID),
+		Attributes: []ERC721Attribute{
+			{TraitType: "Type", Value: n.NadmonType},
+			{TraitType: "Element", Value: n.Element},
+			{TraitType: "Rarity", Value: n.Rarity},
+			{TraitType: "HP", Value: n.HP},
+			{TraitType: "Attack", Value: n.Attack},
+			{TraitType: "Defense", Value: n.Defense},
+			{TraitType: "Speed", Value: n.CalculateSpeed()},
+			{TraitType: "Critical", Value: n.Crit},
+			{TraitType: "Fusion", Value: n.Fusion, DisplayType: "number", MaxValue: MaxFusion},
+			{TraitType: "Evolution", Value: n.Evo, DisplayType: "number", MaxValue: MaxEvo},
+			{TraitType: "Pack", Value: n.PackID, DisplayType: "number"},
+		},
 	}
-	return "#6c757d" // Default gray
+}
+
+// GetElementColor returns the color for a given element from the game data
+func GetElementColor(element string) string {
+	return gamedata.Current().ElementColor(element)
 }
 
 // PackSummary represents summary statistics for pack purchases
 type PackSummary struct {
-	TotalPacks    int     `json:"total_packs"`
-	MonPacks      int     `json:"mon_packs"`
-	CookiesPacks  int     `json:"cookies_packs"`
-	RecentPacks   []Pack  `json:"recent_packs"`
+	Address         string         `json:"address"`
+	TotalPacks      int            `json:"total_packs"`
+	MonPacks        int            `json:"mon_packs"`
+	CookiesPacks    int            `json:"cookies_packs"`
+	ByPaymentType   map[string]int `json:"by_payment_type"`
+	FirstPurchaseAt *time.Time     `json:"first_purchase_at"`
+	LastPurchaseAt  *time.Time     `json:"last_purchase_at"`
+	RecentPacks     []Pack         `json:"recent_packs"`
+}
+
+// NewPackSummary returns an empty summary for address
+func NewPackSummary(address string) *PackSummary {
+	return &PackSummary{
+		Address:       address,
+		ByPaymentType: make(map[string]int),
+		RecentPacks:   []Pack{},
+	}
+}
+
+// Count records n packs bought with paymentType, keeping the MON and COOKIES shortcuts in step
+func (s *PackSummary) Count(paymentType string, n int) {
+	s.ByPaymentType[paymentType] += n
+	switch strings.ToUpper(paymentType) {
+	case "MON":
+		s.MonPacks += n
+	case "COOKIES":
+		s.CookiesPacks += n
+	}
 }
 
 // GameStats represents overall game statistics
@@ -192,4 +507,166 @@ type GameStats struct {
 	TotalPacks        int `json:"total_packs"`
 	TotalEvolutions   int `json:"total_evolutions"`
 	UniqueCollectors  int `json:"unique_collectors"`
-}
\ No newline at end of file
+	// ComputedAt is when background-refreshed stats were computed; nil when computed for the request
+	ComputedAt *time.Time `json:"computed_at,omitempty"`
+}
+// PackDistributionBucket represents a range of pack purchase counts and how many players fall in it
+type PackDistributionBucket struct {
+	Label   string `json:"label"`
+	Min     int    `json:"min"`
+	Max     int    `json:"max,omitempty"` // 0 means unbounded
+	Players int    `json:"players"`
+}
+
+// PackDistribution represents the packs-per-player histogram
+type PackDistribution struct {
+	Buckets      []PackDistributionBucket `json:"buckets"`
+	TotalPlayers int                      `json:"total_players"`
+	TotalPacks   int                      `json:"total_packs"`
+	Mean         float64                  `json:"mean"`
+	Median       float64                  `json:"median"`
+}
+
+// HolderShare represents the share of circulating supply held by the top N holders
+type HolderShare struct {
+	TopHolders int     `json:"top_holders"`
+	Tokens     int     `json:"tokens"`
+	Share      float64 `json:"share"`
+}
+
+// OwnershipConcentration represents how concentrated NFT ownership is across holders
+type OwnershipConcentration struct {
+	CirculatingSupply int           `json:"circulating_supply"`
+	TotalHolders      int           `json:"total_holders"`
+	TopHolderShares   []HolderShare `json:"top_holder_shares"`
+	Gini              float64       `json:"gini"`
+}
+
+// SupplyCount counts minted tokens and how many of them were burned or still circulate
+type SupplyCount struct {
+	Minted      int `json:"minted"`
+	Burned      int `json:"burned"`
+	Circulating int `json:"circulating"`
+}
+
+// Add records minted tokens, of which burned were burned
+func (s *SupplyCount) Add(minted, burned int) {
+	s.Minted += minted
+	s.Burned += burned
+	s.Circulating += minted - burned
+}
+
+// SupplyStats represents the token supply overall and per rarity and element
+type SupplyStats struct {
+	SupplyCount
+	ByRarity  map[string]SupplyCount `json:"by_rarity"`
+	ByElement map[string]SupplyCount `json:"by_element"`
+}
+
+// Dimensions of a TraitBreakdown
+const (
+	DimensionElement = "element"
+	DimensionRarity  = "rarity"
+)
+
+// TraitStats summarizes the circulating Nadmons of one element or rarity. Share is their
+// fraction of the circulating supply and EvolutionRate the fraction evolved past evo 1.
+type TraitStats struct {
+	Value         string  `json:"value"`
+	Count         int     `json:"count"`
+	Share         float64 `json:"share"`
+	AvgHP         float64 `json:"avg_hp"`
+	AvgAttack     float64 `json:"avg_attack"`
+	AvgDefense    float64 `json:"avg_defense"`
+	AvgCrit       float64 `json:"avg_crit"`
+	Evolved       int     `json:"evolved"`
+	EvolutionRate float64 `json:"evolution_rate"`
+}
+
+// TraitBreakdown is the circulating supply broken down by element or rarity, most common first
+type TraitBreakdown struct {
+	Dimension   string       `json:"dimension"`
+	Circulating int          `json:"circulating"`
+	Traits      []TraitStats `json:"traits"`
+}
+
+// TraitTotals holds the count and stat sums of the circulating Nadmons of one element or rarity
+type TraitTotals struct {
+	Value                     string
+	Count, Evolved            int
+	HP, Attack, Defense, Crit int64
+}
+
+// DropRate represents how often a rarity or element dropped among the Nadmons of a set of
+// packs, with the 95% Wilson confidence interval of the true rate
+type DropRate struct {
+	Drops    int     `json:"drops"`
+	Rate     float64 `json:"rate"`
+	RateLow  float64 `json:"rate_low"`
+	RateHigh float64 `json:"rate_high"`
+}
+
+// NewDropRate computes the rate of drops out of total Nadmons
+func NewDropRate(drops, total int) DropRate {
+	rate := DropRate{Drops: drops}
+	if total == 0 {
+		return rate
+	}
+
+	const z = 1.96
+	n := float64(total)
+	p := float64(drops) / n
+	center := (p + z*z/(2*n)) / (1 + z*z/n)
+	margin := z * math.Sqrt(p*(1-p)/n+z*z/(4*n*n)) / (1 + z*z/n)
+
+	rate.Rate = p
+	rate.RateLow = math.Max(0, center-margin)
+	rate.RateHigh = math.Min(1, center+margin)
+	return rate
+}
+
+// PackOdds represents the empirical drop rates of a set of packs
+type PackOdds struct {
+	Packs     int                 `json:"packs"`
+	Nadmons   int                 `json:"nadmons"`
+	ByRarity  map[string]DropRate `json:"by_rarity"`
+	ByElement map[string]DropRate `json:"by_element"`
+}
+
+// PackOddsReport represents pack drop rates overall and per payment type (MON, COOKIES)
+type PackOddsReport struct {
+	PackOdds
+	ByPaymentType map[string]PackOdds `json:"by_payment_type"`
+}
+
+// MetricPlayers is the time-series metric counting distinct pack buyers per bucket
+const MetricPlayers = "players"
+
+// TimeSeriesMetrics lists the metrics time series can be requested for
+var TimeSeriesMetrics = []string{"mints", "packs", "transfers", MetricPlayers}
+
+// TimeSeriesIntervals maps time-series intervals to their bucket size
+var TimeSeriesIntervals = map[string]time.Duration{
+	"hour": time.Hour,
+	"day":  24 * time.Hour,
+}
+
+// TimeSeriesPoint represents the number of events in a single time bucket
+type TimeSeriesPoint struct {
+	Bucket time.Time `json:"bucket"`
+	Count  int64     `json:"count"`
+}
+
+// Suggestion types returned by the search suggestion endpoint
+const (
+	SuggestionTypeNadmonType = "type"
+	SuggestionTypeElement    = "element"
+	SuggestionTypeRarity     = "rarity"
+)
+
+// SearchSuggestion represents a filter value matching a search query
+type SearchSuggestion struct {
+	Type        string `json:"type"`
+	Value       string `json:"value"`
+	Circulating int    `json:"circulating"`
+}
@@ -0,0 +1,266 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// On-chain progression rules. A Nadmon fuses by consuming (burning) another Nadmon of the
+// same species (type and element) until its fusion stat reaches MaxFusion; a fully fused
+// Nadmon below MaxEvo can evolve, which is when its art changes to the next stage.
+const (
+	MaxFusion = 10
+	MaxEvo    = 2
+)
+
+// FusionCandidate is one held Nadmon with what it can do next
+type FusionCandidate struct {
+	TokenID int64  `json:"token_id"`
+	Rarity  string `json:"rarity"`
+	Fusion  int64  `json:"fusion"`
+	Evo     int64  `json:"evo"`
+	// CanFuse is set when the Nadmon is below max fusion and the player holds another
+	// Nadmon of the same species to consume
+	CanFuse bool `json:"can_fuse"`
+	// CanEvolve is set when the Nadmon is fully fused and not yet at its final stage
+	CanEvolve bool `json:"can_evolve"`
+	// FusionsToEvolve counts the fusions left before it can evolve; nil at the final stage
+	FusionsToEvolve *int64 `json:"fusions_to_evolve"`
+}
+
+// FusionGroup is every Nadmon of one species a player holds
+type FusionGroup struct {
+	NadmonType string            `json:"nadmon_type"`
+	Element    string            `json:"element"`
+	Count      int               `json:"count"`
+	Candidates []FusionCandidate `json:"candidates"`
+}
+
+// FusionCandidates reports a player's Nadmons grouped by species with their fusion and
+// evolution eligibility
+type FusionCandidates struct {
+	Address   string        `json:"address"`
+	MaxFusion int64         `json:"max_fusion"`
+	MaxEvo    int64         `json:"max_evo"`
+	Fusable   int           `json:"fusable"`
+	Evolvable int           `json:"evolvable"`
+	Groups    []FusionGroup `json:"groups"`
+}
+
+// NewFusionCandidates groups nadmons by species and applies the progression rules; groups
+// are ordered by type and element, candidates by token ID
+func NewFusionCandidates(address string, nadmons []Nadmon) *FusionCandidates {
+	type species struct{ nadmonType, element string }
+	bySpecies := make(map[species][]Nadmon)
+	for _, nadmon := range nadmons {
+		key := species{nadmon.NadmonType, nadmon.Element}
+		bySpecies[key] = append(bySpecies[key], nadmon)
+	}
+
+	report := &FusionCandidates{
+		Address:   address,
+		MaxFusion: MaxFusion,
+		MaxEvo:    MaxEvo,
+		Groups:    make([]FusionGroup, 0, len(bySpecies)),
+	}
+	for key, members := range bySpecies {
+		sort.Slice(members, func(i, j int) bool { return members[i].TokenID < members[j].TokenID })
+
+		group := FusionGroup{NadmonType: key.nadmonType, Element: key.element, Count: len(members)}
+		for _, nadmon := range members {
+			candidate := FusionCandidate{
+				TokenID:   nadmon.TokenID,
+				Rarity:    nadmon.Rarity,
+				Fusion:    nadmon.Fusion,
+				Evo:       nadmon.Evo,
+				CanFuse:   nadmon.Fusion < MaxFusion && len(members) > 1,
+				CanEvolve: nadmon.Fusion >= MaxFusion && nadmon.Evo < MaxEvo,
+			}
+			if nadmon.Evo < MaxEvo {
+				remaining := MaxFusion - nadmon.Fusion
+				if remaining < 0 {
+					remaining = 0
+				}
+				candidate.FusionsToEvolve = &remaining
+			}
+
+			if candidate.CanFuse {
+				report.Fusable++
+			}
+			if candidate.CanEvolve {
+				report.Evolvable++
+			}
+			group.Candidates = append(group.Candidates, candidate)
+		}
+		report.Groups = append(report.Groups, group)
+	}
+
+	sort.Slice(report.Groups, func(i, j int) bool {
+		if report.Groups[i].NadmonType != report.Groups[j].NadmonType {
+			return report.Groups[i].NadmonType < report.Groups[j].NadmonType
+		}
+		return report.Groups[i].Element < report.Groups[j].Element
+	})
+	return report
+}
+
+// FusionBurnWindow is how far apart a fusion and the burns feeding it can be indexed. Envio
+// writes the events of one transaction together, so burns are correlated by write time.
+const FusionBurnWindow = time.Minute
+
+// ConsumedNadmon is a Nadmon burned to feed a fusion, with its stats when it was burned
+type ConsumedNadmon struct {
+	TokenID    int64     `json:"token_id"`
+	PackID     int64     `json:"pack_id"`
+	NadmonType string    `json:"nadmon_type"`
+	Element    string    `json:"element"`
+	Rarity     string    `json:"rarity"`
+	Stats      StatSet   `json:"stats"`
+	BurnedAt   time.Time `json:"burned_at"`
+}
+
+// Fusion is a fusion stat change of a Nadmon with the Nadmons burned to feed it. The chain
+// doesn't link them, so Consumed holds the burns of same-species Nadmons by the fused Nadmon's
+// owner within FusionBurnWindow of the fusion.
+type Fusion struct {
+	TokenID      int64            `json:"token_id"`
+	Sequence     int64            `json:"sequence"`
+	FusionBefore int64            `json:"fusion_before"`
+	FusionAfter  int64            `json:"fusion_after"`
+	FusedAt      time.Time        `json:"fused_at"`
+	Consumed     []ConsumedNadmon `json:"consumed"`
+}
+
+// MatchFusionBurns narrows each fusion's candidate burns, closest in time first, to the
+// fusion stat it gained (at least one); a burn feeds the earliest fusion that claims it.
+// fusions come back ordered by fusion time.
+func MatchFusionBurns(fusions []Fusion) []Fusion {
+	sort.SliceStable(fusions, func(i, j int) bool {
+		if !fusions[i].FusedAt.Equal(fusions[j].FusedAt) {
+			return fusions[i].FusedAt.Before(fusions[j].FusedAt)
+		}
+		return fusions[i].Sequence < fusions[j].Sequence
+	})
+
+	claimed := make(map[int64]bool)
+	for i := range fusions {
+		fusion := &fusions[i]
+		gained := int(fusion.FusionAfter - fusion.FusionBefore)
+		if gained < 1 {
+			gained = 1
+		}
+
+		candidates := fusion.Consumed
+		sort.SliceStable(candidates, func(a, b int) bool {
+			da, db := absDuration(candidates[a].BurnedAt.Sub(fusion.FusedAt)), absDuration(candidates[b].BurnedAt.Sub(fusion.FusedAt))
+			if da != db {
+				return da < db
+			}
+			return candidates[a].TokenID < candidates[b].TokenID
+		})
+		fusion.Consumed = []ConsumedNadmon{}
+		for _, candidate := range candidates {
+			if len(fusion.Consumed) == gained {
+				break
+			}
+			if candidate.TokenID == fusion.TokenID || claimed[candidate.TokenID] {
+				continue
+			}
+			claimed[candidate.TokenID] = true
+			fusion.Consumed = append(fusion.Consumed, candidate)
+		}
+		sort.Slice(fusion.Consumed, func(a, b int) bool { return fusion.Consumed[a].TokenID < fusion.Consumed[b].TokenID })
+	}
+	return fusions
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// LineageNode is a Nadmon in a fusion lineage tree with the fusions that built it
+type LineageNode struct {
+	TokenID    int64      `json:"token_id"`
+	NadmonType string     `json:"nadmon_type"`
+	Element    string     `json:"element"`
+	Rarity     string     `json:"rarity"`
+	Stats      StatSet    `json:"stats"`
+	BurnedAt   *time.Time `json:"burned_at,omitempty"`
+	// Truncated is set when the node has fusions past the requested depth
+	Truncated bool            `json:"truncated,omitempty"`
+	Fusions   []LineageFusion `json:"fusions"`
+}
+
+// LineageFusion is one fusion of a lineage node and the lineage of each Nadmon it consumed
+type LineageFusion struct {
+	Sequence     int64         `json:"sequence"`
+	FusionBefore int64         `json:"fusion_before"`
+	FusionAfter  int64         `json:"fusion_after"`
+	FusedAt      time.Time     `json:"fused_at"`
+	Consumed     []LineageNode `json:"consumed"`
+}
+
+// Lineage is the tree of Nadmons consumed, recursively, to build a Nadmon
+type Lineage struct {
+	TokenID int64 `json:"token_id"`
+	// Depth is the deepest level of consumed Nadmons in the tree, 0 for a Nadmon never fused
+	Depth         int         `json:"depth"`
+	TotalConsumed int         `json:"total_consumed"`
+	Truncated     bool        `json:"truncated"`
+	Root          LineageNode `json:"lineage"`
+}
+
+// NewLineage builds the lineage tree of root from the fusions of every Nadmon in it, by
+// fused token ID, down to maxDepth levels of consumed Nadmons
+func NewLineage(root *Nadmon, fusions map[int64][]Fusion, maxDepth int) *Lineage {
+	lineage := &Lineage{TokenID: root.TokenID}
+
+	var build func(node *LineageNode, depth int)
+	build = func(node *LineageNode, depth int) {
+		node.Fusions = []LineageFusion{}
+		if depth > lineage.Depth {
+			lineage.Depth = depth
+		}
+		if len(fusions[node.TokenID]) > 0 && depth == maxDepth {
+			node.Truncated = true
+			lineage.Truncated = true
+			return
+		}
+		for _, fusion := range fusions[node.TokenID] {
+			step := LineageFusion{
+				Sequence:     fusion.Sequence,
+				FusionBefore: fusion.FusionBefore,
+				FusionAfter:  fusion.FusionAfter,
+				FusedAt:      fusion.FusedAt,
+				Consumed:     make([]LineageNode, len(fusion.Consumed)),
+			}
+			for i, consumed := range fusion.Consumed {
+				burnedAt := consumed.BurnedAt
+				step.Consumed[i] = LineageNode{
+					TokenID:    consumed.TokenID,
+					NadmonType: consumed.NadmonType,
+					Element:    consumed.Element,
+					Rarity:     consumed.Rarity,
+					Stats:      consumed.Stats,
+					BurnedAt:   &burnedAt,
+				}
+				lineage.TotalConsumed++
+				build(&step.Consumed[i], depth+1)
+			}
+			node.Fusions = append(node.Fusions, step)
+		}
+	}
+
+	lineage.Root = LineageNode{
+		TokenID:    root.TokenID,
+		NadmonType: root.NadmonType,
+		Element:    root.Element,
+		Rarity:     root.Rarity,
+		Stats:      root.Stats(),
+	}
+	build(&lineage.Root, 0)
+	return lineage
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// InventoryChanges is a player's inventory delta since a sync sequence: the Nadmons they hold
+// that were minted, received or changed stats, and the token IDs they no longer hold
+type InventoryChanges struct {
+	Address       string `json:"address"`
+	SinceSequence int64  `json:"since_sequence"`
+	// Sequence is the position to pass as the next since; rows written after it are reported
+	// by the next call, possibly again
+	Sequence int64    `json:"sequence"`
+	Changed  []Nadmon `json:"changed"`
+	Removed  []int64  `json:"removed"`
+}
+
+// SyncSequence converts an indexer write time to a sync sequence, its Unix time in
+// microseconds, the precision of Postgres timestamps
+func SyncSequence(writtenAt time.Time) int64 {
+	return writtenAt.UnixMicro()
+}
+
+// SyncSequenceTime converts a sync sequence back to the write time it stands for, in UTC
+func SyncSequenceTime(sequence int64) time.Time {
+	return time.UnixMicro(sequence).UTC()
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// CollectorRank is a player's place on the collector leaderboard as of the last snapshot,
+// compared with the snapshot before it
+type CollectorRank struct {
+	Address      string `json:"address"`
+	Rank         int64  `json:"rank"`
+	NFTCount     int64  `json:"nft_count"`
+	TotalPlayers int64  `json:"total_players"`
+	// PreviousRank and PreviousNFTCount are nil when the player wasn't ranked in the
+	// previous snapshot
+	PreviousRank     *int64 `json:"previous_rank"`
+	PreviousNFTCount *int64 `json:"previous_nft_count"`
+	// RankDelta is how many places the player climbed (negative when they dropped)
+	RankDelta  *int64          `json:"rank_delta"`
+	SnapshotAt time.Time       `json:"snapshot_at"`
+	Display    *DisplayProfile `json:"display,omitempty"`
+}
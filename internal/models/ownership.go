@@ -0,0 +1,50 @@
+package models
+
+// OwnershipCheck tells a game server which of the given tokens an address holds right now
+type OwnershipCheck struct {
+	Address string `json:"address"`
+	// Owned maps every requested token ID to whether the address holds it; burned and unknown
+	// tokens, and tokens below MinRarity, are false
+	Owned     map[int64]bool `json:"owned"`
+	AllOwned  bool           `json:"all_owned"`
+	MinRarity string         `json:"min_rarity,omitempty"`
+}
+
+// NewOwnershipCheck checks address's ownership of tokenIDs among nadmons, the circulating
+// Nadmons of those IDs; minRarity, when set, must be one of Rarities
+func NewOwnershipCheck(address string, tokenIDs []int64, nadmons []Nadmon, minRarity string) *OwnershipCheck {
+	check := &OwnershipCheck{
+		Address:   address,
+		Owned:     make(map[int64]bool, len(tokenIDs)),
+		AllOwned:  true,
+		MinRarity: minRarity,
+	}
+	for _, id := range tokenIDs {
+		check.Owned[id] = false
+	}
+	for _, nadmon := range nadmons {
+		if _, requested := check.Owned[nadmon.TokenID]; requested && nadmon.Owner == address &&
+			(minRarity == "" || RarityAtLeast(nadmon.Rarity, minRarity)) {
+			check.Owned[nadmon.TokenID] = true
+		}
+	}
+	for _, owned := range check.Owned {
+		check.AllOwned = check.AllOwned && owned
+	}
+	return check
+}
+
+// RarityAtLeast reports whether rarity ranks at or above min in Rarities; unknown rarities
+// never do
+func RarityAtLeast(rarity, min string) bool {
+	level, minLevel := -1, len(Rarities)
+	for i, r := range Rarities {
+		if r == rarity {
+			level = i
+		}
+		if r == min {
+			minLevel = i
+		}
+	}
+	return level >= minLevel
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// PlayerStats are a player's aggregates kept up to date by the event pipeline
+type PlayerStats struct {
+	Address     string `json:"address"`
+	TotalNFTs   int    `json:"total_nfts"`
+	PacksBought int    `json:"packs_bought"`
+	// EvolutionsPerformed counts the evolutions of Nadmons the player held at the time
+	EvolutionsPerformed int            `json:"evolutions_performed"`
+	EvolvedNFTs         int            `json:"evolved_nfts"`
+	RarityCounts        map[string]int `json:"rarity_counts"`
+	ElementCounts       map[string]int `json:"element_counts"`
+	// RarestOwned is the held Nadmon of the highest rarity, the lowest token ID on ties; nil
+	// when the player holds none
+	RarestOwned *RarestOwned `json:"rarest_owned"`
+	LastActive  time.Time    `json:"last_active"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// RarestOwned names a player's rarest Nadmon
+type RarestOwned struct {
+	TokenID int64  `json:"token_id"`
+	Rarity  string `json:"rarity"`
+}
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// Display profile limits
+const (
+	MinNicknameLength = 3
+	MaxNicknameLength = 24
+	MaxBioLength      = 280
+)
+
+// DisplayProfile is how a player presents themselves: a nickname, one of their Nadmons as
+// avatar and a short bio. Unlike everything indexed from the chain, players edit it.
+type DisplayProfile struct {
+	Address       string    `json:"address"`
+	Nickname      *string   `json:"nickname"`
+	AvatarTokenID *int64    `json:"avatar_token_id"`
+	Bio           string    `json:"bio"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// MaxTeamNameLength caps the length of team names
+const MaxTeamNameLength = 32
+
+// Team is a named squad of Nadmons a player saved as a battle loadout
+type Team struct {
+	ID        int64     `json:"id"`
+	Address   string    `json:"address"`
+	Name      string    `json:"name"`
+	TokenIDs  []int64   `json:"token_ids"`
+	CreatedAt time.Time `json:"created_at"`
+	// MissingTokenIDs lists saved Nadmons the player no longer holds, e.g. sold or consumed
+	// by a fusion; a team with missing Nadmons can't be fielded as saved
+	MissingTokenIDs []int64 `json:"missing_token_ids"`
+}
+
+// Favorite is a Nadmon a player starred: one they hold, or one on their wishlist
+type Favorite struct {
+	TokenID     int64     `json:"token_id"`
+	FavoritedAt time.Time `json:"favorited_at"`
+	// Owner is the Nadmon's current holder, empty once it is burned
+	Owner string `json:"owner"`
+	// NFT is the Nadmon in inventory format, nil once it is burned
+	NFT map[string]interface{} `json:"nft"`
+}
+
+// NameResolution is the result of resolving an address to its name service name or back;
+// Name or Address is nil when there is no record
+type NameResolution struct {
+	Address    *string   `json:"address"`
+	Name       *string   `json:"name"`
+	ResolvedAt time.Time `json:"resolved_at"`
+}
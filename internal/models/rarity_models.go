@@ -0,0 +1,33 @@
+package models
+
+import "time"
+
+// Elements lists every Nadmon element
+var Elements = []string{"Fire", "Water", "Nature", "Electric", "Earth", "Ice", "Dark", "Light"}
+
+// Rarities lists every rarity from Common up to Legendary
+var Rarities = []string{"Common", "Uncommon", "Rare", "Epic", "Legendary"}
+
+// TraitRarity is how common one trait value is among circulating Nadmons
+type TraitRarity struct {
+	Trait     string  `json:"trait"` // type, element, rarity or evo
+	Value     string  `json:"value"`
+	Count     int64   `json:"count"`     // circulating Nadmons sharing the value
+	Frequency float64 `json:"frequency"` // Count as a share of all circulating Nadmons
+}
+
+// NFTRarity is a Nadmon's statistical rarity. The score sums, over its type, element, rarity
+// and evolution stage, the inverse of the share of circulating Nadmons with the same value,
+// so rare traits weigh more; rank 1 is the rarest Nadmon.
+type NFTRarity struct {
+	TokenID int64   `json:"token_id"`
+	Score   float64 `json:"score"`
+	Rank    int64   `json:"rank"`
+	Total   int64   `json:"total"` // ranked (circulating) Nadmons
+	// TopPercent is the share of ranked Nadmons at least as rare, e.g. 1.5 for the top 1.5%
+	TopPercent float64 `json:"top_percent"`
+	// CombinationCount is how many circulating Nadmons share all four traits
+	CombinationCount int64         `json:"combination_count"`
+	Traits           []TraitRarity `json:"traits"`
+	ComputedAt       time.Time     `json:"computed_at"`
+}
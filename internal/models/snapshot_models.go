@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// HolderBalance is one holder in a HolderSnapshot
+type HolderBalance struct {
+	Address  string  `json:"address"`
+	Balance  int     `json:"balance"`
+	TokenIDs []int64 `json:"token_ids"`
+}
+
+// HolderSnapshot lists the collection's holders as of a point in time, replayed from the
+// transfers indexed by then, largest balance first
+type HolderSnapshot struct {
+	AsOf         time.Time       `json:"as_of"`
+	TotalHolders int             `json:"total_holders"`
+	TotalSupply  int             `json:"total_supply"`
+	Holders      []HolderBalance `json:"holders"`
+}
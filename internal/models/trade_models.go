@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Trade offer statuses. Pending offers turn expired once their TTL passes.
+const (
+	TradePending   = "pending"
+	TradeAccepted  = "accepted"
+	TradeRejected  = "rejected"
+	TradeCancelled = "cancelled"
+	TradeExpired   = "expired"
+)
+
+// TradeStatuses lists the statuses trade offers can be filtered by
+var TradeStatuses = []string{TradePending, TradeAccepted, TradeRejected, TradeCancelled, TradeExpired}
+
+// WebSocket message types pushed to the other side of a trade offer
+const (
+	TypeTradeOffered   = "trade_offered"
+	TypeTradeAccepted  = "trade_accepted"
+	TypeTradeRejected  = "trade_rejected"
+	TypeTradeCancelled = "trade_cancelled"
+)
+
+// TradeOffer is a proposed swap of Nadmons between two players. The backend only brokers it:
+// once accepted, the players settle the swap on-chain.
+type TradeOffer struct {
+	ID                int64     `json:"id"`
+	Proposer          string    `json:"proposer"`
+	Counterparty      string    `json:"counterparty"`
+	OfferedTokenIDs   []int64   `json:"offered_token_ids"`   // held by the proposer
+	RequestedTokenIDs []int64   `json:"requested_token_ids"` // held by the counterparty
+	Status            string    `json:"status"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+	ExpiresAt         time.Time `json:"expires_at"`
+	// MissingTokenIDs lists the Nadmons of a pending offer no longer held by the side giving
+	// them, e.g. sold since; an offer with missing Nadmons can't be accepted
+	MissingTokenIDs []int64 `json:"missing_token_ids"`
+}
+
+// TradePage is one page of a player's trade offers
+type TradePage struct {
+	Offers []TradeOffer `json:"offers"`
+	Total  int          `json:"total"`
+}
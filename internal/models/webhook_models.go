@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// Webhook event types a subscription can ask for
+const (
+	WebhookEventMint      = "mint"
+	WebhookEventTransfer  = "transfer"
+	WebhookEventEvolution = "evolution"
+	WebhookEventPack      = "pack"
+)
+
+// WebhookEvents lists every webhook event type
+var WebhookEvents = []string{WebhookEventMint, WebhookEventTransfer, WebhookEventEvolution, WebhookEventPack}
+
+// Webhook is a callback URL registered by a third-party integration to receive indexer events
+type Webhook struct {
+	ID  int64  `json:"id"`
+	URL string `json:"url"`
+	// Owner is the wallet that registered the webhook; empty for webhooks registered with the
+	// admin API key
+	Owner  string   `json:"owner,omitempty"`
+	Events []string `json:"events"`
+	// Addresses limits deliveries to events involving one of these players; empty matches all
+	Addresses []string  `json:"addresses"`
+	CreatedAt time.Time `json:"created_at"`
+	// Secret signs every delivery; it is only returned when the webhook is registered
+	Secret string `json:"secret,omitempty"`
+}
+
+// WebhookDelivery is one attempt to deliver an event to a webhook
+type WebhookDelivery struct {
+	ID         int64     `json:"id"`
+	WebhookID  int64     `json:"webhook_id"`
+	DeliveryID string    `json:"delivery_id"` // shared by the retries of one event
+	Event      string    `json:"event"`
+	Attempt    int       `json:"attempt"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+	Succeeded  bool      `json:"succeeded"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,192 @@
+package names
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// DefaultRegistry is the ENS registry address, the same on every chain ENS is deployed to
+const DefaultRegistry = "0x00000000000C2E074eC69A0dFb2997BA6C7d2e1e"
+
+// Function selectors of the registry and resolver calls
+const (
+	selectorResolver = "0178b8bf" // resolver(bytes32)
+	selectorAddr     = "3b3b57de" // addr(bytes32)
+	selectorName     = "691f3431" // name(bytes32)
+)
+
+// zeroWord is an ABI-encoded zero address
+var zeroWord = strings.Repeat("0", 64)
+
+// ENSResolver resolves names through an ENS-compatible registry over Ethereum JSON-RPC.
+// Chain-native name services forked from ENS work by pointing it at their registry.
+type ENSResolver struct {
+	rpcURL   string
+	registry string
+	http     *http.Client
+}
+
+// NewENSResolver creates a resolver calling registry through the JSON-RPC endpoint rpcURL
+func NewENSResolver(rpcURL, registry string) *ENSResolver {
+	if registry == "" {
+		registry = DefaultRegistry
+	}
+	return &ENSResolver{
+		rpcURL:   rpcURL,
+		registry: strings.ToLower(registry),
+		http:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// LookupName returns the primary name of address, or "" when it has none. A reverse record
+// only counts when the name resolves back to address, as anyone can claim any name there.
+func (r *ENSResolver) LookupName(ctx context.Context, address string) (string, error) {
+	address = strings.ToLower(address)
+	node := namehash(strings.TrimPrefix(address, "0x") + ".addr.reverse")
+	result, err := r.resolverCall(ctx, node, selectorName)
+	if err != nil || result == "" {
+		return "", err
+	}
+	name, err := decodeString(result)
+	if err != nil || name == "" {
+		return "", err
+	}
+
+	forward, err := r.LookupAddress(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if forward != address {
+		return "", nil
+	}
+	return name, nil
+}
+
+// LookupAddress returns the lowercased address name resolves to, or "" when it resolves
+// to none
+func (r *ENSResolver) LookupAddress(ctx context.Context, name string) (string, error) {
+	result, err := r.resolverCall(ctx, namehash(strings.ToLower(name)), selectorAddr)
+	if err != nil || len(result) < 64 || result[:64] == zeroWord {
+		return "", err
+	}
+	return "0x" + result[24:64], nil
+}
+
+// resolverCall looks up the resolver of node in the registry and calls selector(node) on
+// it, returning the hex result without 0x, or "" when node has no resolver
+func (r *ENSResolver) resolverCall(ctx context.Context, node [32]byte, selector string) (string, error) {
+	nodeHex := hex.EncodeToString(node[:])
+	resolver, err := r.call(ctx, r.registry, selectorResolver+nodeHex)
+	if err != nil {
+		return "", fmt.Errorf("failed to look up resolver: %w", err)
+	}
+	if len(resolver) < 64 || resolver[:64] == zeroWord {
+		return "", nil
+	}
+
+	result, err := r.call(ctx, "0x"+resolver[24:64], selector+nodeHex)
+	if err != nil {
+		return "", fmt.Errorf("failed to call resolver: %w", err)
+	}
+	return result, nil
+}
+
+// call runs eth_call against the latest block and returns the result without 0x
+func (r *ENSResolver) call(ctx context.Context, to, data string) (string, error) {
+	body, err := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      1,
+		"method":  "eth_call",
+		"params":  []interface{}{map[string]string{"to": to, "data": "0x" + data}, "latest"},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.rpcURL, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		return "", fmt.Errorf("rpc: %s: %s", resp.Status, strings.TrimSpace(string(message)))
+	}
+
+	var result struct {
+		Result string `json:"result"`
+		Error  *struct {
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return "", fmt.Errorf("rpc: failed to decode response: %w", err)
+	}
+	if result.Error != nil {
+		// Calls to contracts that don't implement the function revert; they have no record
+		if strings.Contains(strings.ToLower(result.Error.Message), "revert") {
+			return "", nil
+		}
+		return "", fmt.Errorf("rpc: %s", result.Error.Message)
+	}
+	return strings.TrimPrefix(result.Result, "0x"), nil
+}
+
+// namehash computes the ENS node of a dot-separated name
+func namehash(name string) [32]byte {
+	var node [32]byte
+	if name == "" {
+		return node
+	}
+	labels := strings.Split(name, ".")
+	for i := len(labels) - 1; i >= 0; i-- {
+		label := keccak([]byte(labels[i]))
+		copy(node[:], keccak(node[:], label[:]))
+	}
+	return node
+}
+
+// keccak returns the Keccak-256 of the concatenated data
+func keccak(data ...[]byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+// decodeString decodes an ABI-encoded string return value given as hex
+func decodeString(result string) (string, error) {
+	raw, err := hex.DecodeString(result)
+	if err != nil {
+		return "", fmt.Errorf("invalid string result: %w", err)
+	}
+	if len(raw) < 64 {
+		return "", nil
+	}
+	offset := binary.BigEndian.Uint64(raw[24:32])
+	if offset+32 > uint64(len(raw)) {
+		return "", fmt.Errorf("invalid string result: offset %d out of range", offset)
+	}
+	length := binary.BigEndian.Uint64(raw[offset+24 : offset+32])
+	if offset+32+length > uint64(len(raw)) {
+		return "", fmt.Errorf("invalid string result: length %d out of range", length)
+	}
+	return string(raw[offset+32 : offset+32+length]), nil
+}
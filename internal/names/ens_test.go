@@ -0,0 +1,80 @@
+package names
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestNamehash(t *testing.T) {
+	tests := map[string]string{
+		"":        "0000000000000000000000000000000000000000000000000000000000000000",
+		"eth":     "93cdeb708b7545dc668eb9280176169d1c33cfd8ed6f04690a0bcc88a93fc4ae",
+		"foo.eth": "de9b09fd7c5f901e23a3f19fecc54828e9c848539801e86591bd9801b019f84f",
+	}
+	for name, want := range tests {
+		node := namehash(name)
+		if got := hex.EncodeToString(node[:]); got != want {
+			t.Errorf("namehash(%q) = %s, expected %s", name, got, want)
+		}
+	}
+}
+
+// word left-pads hex to one 32-byte ABI word
+func word(hexValue string) string {
+	return strings.Repeat("0", 64-len(hexValue)) + hexValue
+}
+
+func TestENSResolver(t *testing.T) {
+	const (
+		address  = "0x00000000000000000000000000000000000000aa"
+		resolver = "00000000000000000000000000000000000000ff"
+	)
+	name := "alice.eth"
+	nameResult := word("20") + word("9") + hex.EncodeToString([]byte(name)) + strings.Repeat("0", 64-2*len(name))
+
+	// A registry and resolver answering for alice.eth and its reverse record
+	rpc := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Params []json.RawMessage `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		var call struct{ To, Data string }
+		json.Unmarshal(req.Params[0], &call)
+
+		result := word("")
+		switch call.Data[2:10] {
+		case selectorResolver:
+			result = word(resolver)
+		case selectorName:
+			result = nameResult
+		case selectorAddr:
+			node := namehash(name)
+			if call.Data[10:] == hex.EncodeToString(node[:]) {
+				result = word(strings.TrimPrefix(address, "0x"))
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": "0x" + result})
+	}))
+	defer rpc.Close()
+
+	r := NewENSResolver(rpc.URL, "")
+	if got, err := r.LookupName(context.Background(), address); err != nil || got != name {
+		t.Errorf("expected %s, got %q (%v)", name, got, err)
+	}
+	if got, err := r.LookupAddress(context.Background(), name); err != nil || got != address {
+		t.Errorf("expected %s, got %q (%v)", address, got, err)
+	}
+	if got, err := r.LookupAddress(context.Background(), "bob.eth"); err != nil || got != "" {
+		t.Errorf("expected no address, got %q (%v)", got, err)
+	}
+
+	// The reverse record of another address claims alice.eth, which doesn't resolve back to it
+	if got, err := r.LookupName(context.Background(), "0x00000000000000000000000000000000000000bb"); err != nil || got != "" {
+		t.Errorf("expected the unverified name to be ignored, got %q (%v)", got, err)
+	}
+}
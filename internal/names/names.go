@@ -0,0 +1,217 @@
+// Package names resolves human-readable names (ENS or an ENS-compatible chain-native name
+// service) for player addresses. Responses are only decorated with names already cached;
+// unknown addresses are queued and resolved in the background, so a slow RPC endpoint never
+// delays the API.
+package names
+
+import (
+	"context"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"nadmon-backend/internal/models"
+)
+
+// Resolver looks names and addresses up in a name service
+type Resolver interface {
+	// LookupName returns the verified primary name of address, or "" when it has none
+	LookupName(ctx context.Context, address string) (string, error)
+	// LookupAddress returns the lowercased address of name, or "" when it resolves to none
+	LookupAddress(ctx context.Context, name string) (string, error)
+}
+
+// maxPending bounds the addresses waiting to be resolved, so a burst of new addresses can't
+// grow the queue without limit; addresses dropped are queued again on their next request
+const maxPending = 10000
+
+// lookupTimeout bounds each background lookup
+const lookupTimeout = 10 * time.Second
+
+// entry is a cached lookup result; value is "" when there is no record
+type entry struct {
+	value      string
+	resolvedAt time.Time
+}
+
+// Service caches resolved names and refreshes them in the background
+type Service struct {
+	resolver Resolver
+	ttl      time.Duration
+	interval time.Duration
+	batch    int
+
+	mu        sync.RWMutex
+	names     map[string]entry // address -> name
+	addresses map[string]entry // name -> address
+	pending   map[string]bool  // addresses to resolve on the next refresh
+}
+
+// NewService creates a name service caching results of resolver for ttl. Run resolves up to
+// batch queued or expired addresses every interval.
+func NewService(resolver Resolver, ttl, interval time.Duration, batch int) *Service {
+	return &Service{
+		resolver:  resolver,
+		ttl:       ttl,
+		interval:  interval,
+		batch:     batch,
+		names:     make(map[string]entry),
+		addresses: make(map[string]entry),
+		pending:   make(map[string]bool),
+	}
+}
+
+// Names returns the cached names of addresses, keyed by lowercased address. Addresses not
+// cached yet are queued for the refresher and left out.
+func (s *Service) Names(addresses []string) map[string]string {
+	names := make(map[string]string)
+	var missing []string
+
+	s.mu.RLock()
+	for _, address := range addresses {
+		address = strings.ToLower(address)
+		if address == "" || address == models.ZeroAddress {
+			continue
+		}
+		cached, ok := s.names[address]
+		switch {
+		case !ok:
+			missing = append(missing, address)
+		case cached.value != "":
+			names[address] = cached.value
+		}
+	}
+	s.mu.RUnlock()
+
+	if len(missing) > 0 {
+		s.mu.Lock()
+		for _, address := range missing {
+			if len(s.pending) < maxPending {
+				s.pending[address] = true
+			}
+		}
+		s.mu.Unlock()
+	}
+	return names
+}
+
+// LookupName returns the primary name of address, from the cache while it is fresh
+func (s *Service) LookupName(ctx context.Context, address string) (string, time.Time, error) {
+	address = strings.ToLower(address)
+	if cached, ok := s.cached(s.names, address); ok {
+		return cached.value, cached.resolvedAt, nil
+	}
+
+	name, err := s.resolver.LookupName(ctx, address)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return name, s.storeName(address, name), nil
+}
+
+// LookupAddress returns the address name resolves to, from the cache while it is fresh
+func (s *Service) LookupAddress(ctx context.Context, name string) (string, time.Time, error) {
+	name = strings.ToLower(name)
+	if cached, ok := s.cached(s.addresses, name); ok {
+		return cached.value, cached.resolvedAt, nil
+	}
+
+	address, err := s.resolver.LookupAddress(ctx, name)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	now := time.Now().UTC()
+	s.mu.Lock()
+	s.addresses[name] = entry{value: address, resolvedAt: now}
+	s.mu.Unlock()
+	return address, now, nil
+}
+
+// cached returns the entry of key in cache unless it has expired
+func (s *Service) cached(cache map[string]entry, key string) (entry, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	cached, ok := cache[key]
+	if !ok || time.Since(cached.resolvedAt) > s.ttl {
+		return entry{}, false
+	}
+	return cached, true
+}
+
+// storeName caches the name of address and returns when it was resolved
+func (s *Service) storeName(address, name string) time.Time {
+	now := time.Now().UTC()
+	s.mu.Lock()
+	s.names[address] = entry{value: name, resolvedAt: now}
+	delete(s.pending, address)
+	s.mu.Unlock()
+	return now
+}
+
+// Run refreshes names every interval until stop is closed
+func (s *Service) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			s.refresh()
+		}
+	}
+}
+
+// refresh resolves queued addresses first, then the ones whose names expired, up to the
+// batch size. Failed lookups keep their previous result.
+func (s *Service) refresh() {
+	due := s.due()
+	resolved, failed := 0, 0
+	for _, address := range due {
+		ctx, cancel := context.WithTimeout(context.Background(), lookupTimeout)
+		name, err := s.resolver.LookupName(ctx, address)
+		cancel()
+		if err != nil {
+			// Queued again when it's next requested; expired names stay cached until then
+			s.mu.Lock()
+			delete(s.pending, address)
+			s.mu.Unlock()
+			failed++
+			continue
+		}
+		s.storeName(address, name)
+		resolved++
+	}
+
+	if failed > 0 {
+		log.Printf("Warning: Failed to resolve %d of %d names", failed, len(due))
+	}
+	if resolved > 0 {
+		log.Printf("📛 Resolved %d names", resolved)
+	}
+}
+
+// due returns up to batch addresses to resolve: queued ones, then expired ones
+func (s *Service) due() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	due := make([]string, 0, s.batch)
+	for address := range s.pending {
+		if len(due) == s.batch {
+			return due
+		}
+		due = append(due, address)
+	}
+	for address, cached := range s.names {
+		if len(due) == s.batch {
+			break
+		}
+		if time.Since(cached.resolvedAt) > s.ttl {
+			due = append(due, address)
+		}
+	}
+	return due
+}
@@ -0,0 +1,21 @@
+// Package openapi embeds the hand-maintained OpenAPI 3 description of the HTTP API and
+// the Swagger UI page that renders it. Update openapi.json alongside route changes.
+package openapi
+
+import _ "embed"
+
+//go:embed openapi.json
+var spec []byte
+
+//go:embed swagger.html
+var ui []byte
+
+// Spec returns the OpenAPI document as JSON
+func Spec() []byte {
+	return spec
+}
+
+// UI returns the Swagger UI page, which loads the document from /api/openapi.json
+func UI() []byte {
+	return ui
+}
@@ -0,0 +1,75 @@
+// Package origins matches browser origins against the configured allow-list shared by the
+// CORS middleware and the WebSocket upgrader.
+package origins
+
+import "strings"
+
+// List is a set of allowed origins. An entry is either an exact origin such as
+// "https://nadmon.kadzu.dev", a wildcard such as "https://*.kadzu.dev" matching every
+// subdomain (but not the bare domain), or "*" allowing any origin.
+type List struct {
+	entries   []string
+	exact     map[string]bool
+	wildcards []wildcard
+	any       bool
+}
+
+// wildcard is a "scheme://*.suffix" entry split around the asterisk
+type wildcard struct {
+	prefix string // scheme, e.g. "https://"
+	suffix string // e.g. ".kadzu.dev" or ".kadzu.dev:8443"
+}
+
+// New builds a list from configured origins; entries are compared case-insensitively and a
+// trailing slash is ignored
+func New(origins []string) *List {
+	l := &List{exact: make(map[string]bool, len(origins))}
+	for _, origin := range origins {
+		origin = normalize(origin)
+		if origin == "" {
+			continue
+		}
+		l.entries = append(l.entries, origin)
+		switch {
+		case origin == "*":
+			l.any = true
+		case strings.Contains(origin, "://*."):
+			i := strings.Index(origin, "*")
+			l.wildcards = append(l.wildcards, wildcard{prefix: origin[:i], suffix: origin[i+1:]})
+		default:
+			l.exact[origin] = true
+		}
+	}
+	return l
+}
+
+// Allowed reports whether requests from origin may be served; an empty origin never is
+func (l *List) Allowed(origin string) bool {
+	origin = normalize(origin)
+	if origin == "" {
+		return false
+	}
+	if l.any || l.exact[origin] {
+		return true
+	}
+	for _, w := range l.wildcards {
+		if !strings.HasPrefix(origin, w.prefix) || !strings.HasSuffix(origin, w.suffix) {
+			continue
+		}
+		// The wildcard stands for one or more DNS labels, never a path or a port
+		sub := origin[len(w.prefix) : len(origin)-len(w.suffix)]
+		if sub != "" && !strings.ContainsAny(sub, "/:@") && !strings.HasPrefix(sub, ".") {
+			return true
+		}
+	}
+	return false
+}
+
+// Strings returns the configured entries for logging
+func (l *List) Strings() []string {
+	return l.entries
+}
+
+func normalize(origin string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(origin)), "/")
+}
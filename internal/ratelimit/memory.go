@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// idleTimeout is how long an untouched bucket is kept; by then it has refilled completely
+const idleTimeout = 10 * time.Minute
+
+// memoryBucket is the state of one in-process token bucket
+type memoryBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// Memory is a Limiter that keeps buckets in process memory. Limits apply per replica.
+type Memory struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+	stop    chan struct{}
+}
+
+// NewMemory creates an in-memory limiter that evicts idle buckets in the background
+func NewMemory() *Memory {
+	m := &Memory{
+		buckets: make(map[string]*memoryBucket),
+		stop:    make(chan struct{}),
+	}
+	go m.evictIdle()
+	return m
+}
+
+// Allow takes a token from the bucket under key, refilling it for the time since last use
+func (m *Memory) Allow(key string, limit Limit) (bool, time.Duration, error) {
+	now := time.Now()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	b, ok := m.buckets[key]
+	if !ok {
+		b = &memoryBucket{tokens: float64(limit.Burst), last: now}
+		m.buckets[key] = b
+	}
+
+	b.tokens += now.Sub(b.last).Seconds() * limit.Rate
+	if b.tokens > float64(limit.Burst) {
+		b.tokens = float64(limit.Burst)
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		return false, limit.retryAfter(b.tokens), nil
+	}
+	b.tokens--
+	return true, 0, nil
+}
+
+// evictIdle periodically drops buckets that haven't been used for idleTimeout
+func (m *Memory) evictIdle() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			cutoff := time.Now().Add(-idleTimeout)
+			m.mu.Lock()
+			for key, b := range m.buckets {
+				if b.last.Before(cutoff) {
+					delete(m.buckets, key)
+				}
+			}
+			m.mu.Unlock()
+		case <-m.stop:
+			return
+		}
+	}
+}
+
+// Close stops the eviction goroutine
+func (m *Memory) Close() error {
+	close(m.stop)
+	return nil
+}
@@ -0,0 +1,124 @@
+package ratelimit
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"nadmon-backend/internal/ethaddr"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Limit is a token bucket: Rate tokens are added per second, up to Burst
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Enabled reports whether the limit restricts anything
+func (l Limit) Enabled() bool {
+	return l.Rate > 0 && l.Burst > 0
+}
+
+// retryAfter returns how long until a bucket holding tokens has one token again
+func (l Limit) retryAfter(tokens float64) time.Duration {
+	return time.Duration((1 - tokens) / l.Rate * float64(time.Second))
+}
+
+// Limiter takes one token from the bucket stored under key
+type Limiter interface {
+	// Allow reports whether the request may proceed and, if not, when to retry
+	Allow(key string, limit Limit) (allowed bool, retryAfter time.Duration, err error)
+}
+
+// Rules configures the buckets the middleware applies. Each client IP gets one bucket per
+// class (default or expensive); requests for a player address also draw from a bucket of that
+// client and address, so one client can't hammer a single player's data. The address bucket
+// is per client: a shared one would let anyone lock a player out of their own inventory.
+type Rules struct {
+	Default        Limit
+	Expensive      Limit
+	Address        Limit
+	ExpensivePaths []string // route fragments, e.g. "/search", served from the Expensive limit
+}
+
+// expensive reports whether the matched route falls under the Expensive limit
+func (r Rules) expensive(route string) bool {
+	for _, fragment := range r.ExpensivePaths {
+		if fragment != "" && strings.Contains(route, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExemptKey is set on the Gin context by middleware that already limited the request with
+// buckets of its own, e.g. partner API keys; Middleware then lets it through
+const ExemptKey = "ratelimit.exempt"
+
+// bucket names the token bucket a request draws from
+type bucket struct {
+	key   string
+	limit Limit
+}
+
+// Middleware rejects requests over their limits with 429 Too Many Requests and a
+// Retry-After header. Limiter errors are logged and let the request through.
+func Middleware(limiter Limiter, rules Rules) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetBool(ExemptKey) {
+			c.Next()
+			return
+		}
+
+		class, limit := "default", rules.Default
+		if rules.expensive(c.FullPath()) {
+			class, limit = "expensive", rules.Expensive
+		}
+
+		ip := c.ClientIP()
+		buckets := []bucket{{IPBucket(ip, class), limit}}
+		if address := c.Param("address"); ethaddr.Valid(address) {
+			buckets = append(buckets, bucket{"address:" + ethaddr.Normalize(address) + ":" + ip, rules.Address})
+		}
+
+		for _, bucket := range buckets {
+			if !bucket.limit.Enabled() {
+				continue
+			}
+
+			allowed, retryAfter, err := limiter.Allow(bucket.key, bucket.limit)
+			if err != nil {
+				log.Printf("Warning: rate limiter unavailable: %v", err)
+				break
+			}
+			if !allowed {
+				Reject(c, retryAfter)
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// IPBucket names the bucket of a client IP for a class, for middleware drawing from the same
+// buckets as Middleware
+func IPBucket(ip, class string) string {
+	return "ip:" + ip + ":" + class
+}
+
+// Reject aborts the request with 429 Too Many Requests and a Retry-After header of at least
+// one second
+func Reject(c *gin.Context, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Rate limit exceeded, retry in " + strconv.Itoa(seconds) + "s"})
+}
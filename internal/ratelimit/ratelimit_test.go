@@ -0,0 +1,67 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+const player = "0xaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+
+func newRouter(t *testing.T, rules Rules) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	memory := NewMemory()
+	t.Cleanup(func() { memory.Close() })
+
+	r := gin.New()
+	if err := r.SetTrustedProxies(nil); err != nil {
+		t.Fatal(err)
+	}
+	r.Use(Middleware(memory, rules))
+	r.GET("/api/players/:address/nadmons", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return r
+}
+
+func get(r *gin.Engine, remoteAddr, forwardedFor string) int {
+	req := httptest.NewRequest(http.MethodGet, "/api/players/"+player+"/nadmons", nil)
+	req.RemoteAddr = remoteAddr
+	if forwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestForgedForwardedForSharesBucket(t *testing.T) {
+	r := newRouter(t, Rules{Default: Limit{Rate: 0.001, Burst: 2}})
+
+	// Without trusted proxies, rotating X-Forwarded-For still draws from the connection's bucket
+	for i, forwarded := range []string{"1.1.1.1", "2.2.2.2", "3.3.3.3"} {
+		want := http.StatusOK
+		if i == 2 {
+			want = http.StatusTooManyRequests
+		}
+		if code := get(r, "10.0.0.1:1234", forwarded); code != want {
+			t.Errorf("request %d: expected %d, got %d", i, want, code)
+		}
+	}
+}
+
+func TestAddressBucketPerClient(t *testing.T) {
+	r := newRouter(t, Rules{Address: Limit{Rate: 0.001, Burst: 1}})
+
+	if code := get(r, "10.0.0.1:1234", ""); code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", code)
+	}
+	if code := get(r, "10.0.0.1:1234", ""); code != http.StatusTooManyRequests {
+		t.Errorf("expected the same client limited on the player, got %d", code)
+	}
+	// Another client, e.g. the player, isn't locked out by the first one draining its bucket
+	if code := get(r, "10.0.0.2:1234", ""); code != http.StatusOK {
+		t.Errorf("expected another client to reach the player, got %d", code)
+	}
+}
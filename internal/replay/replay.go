@@ -0,0 +1,188 @@
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Data modes supported by the backend
+const (
+	ModeLive   = "live"
+	ModeRecord = "record"
+	ModeReplay = "replay"
+)
+
+// Entry represents a single recorded API response
+type Entry struct {
+	Status      int       `json:"status"`
+	ContentType string    `json:"content_type"`
+	Body        string    `json:"body"`
+	RecordedAt  time.Time `json:"recorded_at"`
+}
+
+// Bundle is a collection of recorded responses keyed by method and normalized URL
+type Bundle struct {
+	CreatedAt time.Time         `json:"created_at"`
+	Entries   map[string]*Entry `json:"entries"`
+}
+
+// LoadBundle reads a recorded bundle from disk
+func LoadBundle(path string) (*Bundle, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read replay bundle: %w", err)
+	}
+
+	var bundle Bundle
+	if err := json.Unmarshal(data, &bundle); err != nil {
+		return nil, fmt.Errorf("failed to parse replay bundle: %w", err)
+	}
+	if bundle.Entries == nil {
+		bundle.Entries = make(map[string]*Entry)
+	}
+	return &bundle, nil
+}
+
+// requestKey builds a stable key for a request, independent of query parameter order
+func requestKey(r *http.Request) string {
+	key := r.Method + " " + r.URL.Path
+	if query := r.URL.Query(); len(query) > 0 {
+		// url.Values.Encode sorts by key
+		key += "?" + query.Encode()
+	}
+	return key
+}
+
+// isStream reports whether the request is a WebSocket handshake or a Server-Sent Events
+// subscription, neither of which can be recorded or replayed
+func isStream(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket") ||
+		strings.Contains(r.Header.Get("Accept"), "text/event-stream")
+}
+
+// Recorder captures successful GET responses into a bundle
+type Recorder struct {
+	path   string
+	bundle *Bundle
+	mu     sync.Mutex
+}
+
+// NewRecorder creates a recorder that writes its bundle to path on Save
+func NewRecorder(path string) *Recorder {
+	return &Recorder{
+		path: path,
+		bundle: &Bundle{
+			CreatedAt: time.Now(),
+			Entries:   make(map[string]*Entry),
+		},
+	}
+}
+
+// bodyRecorder tees the response body into a buffer
+type bodyRecorder struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *bodyRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Middleware records every GET response below 500 while passing it through to the client
+func (rec *Recorder) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet || isStream(c.Request) {
+			c.Next()
+			return
+		}
+
+		writer := &bodyRecorder{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = writer
+		c.Next()
+
+		if c.Writer.Status() >= http.StatusInternalServerError {
+			return
+		}
+
+		rec.mu.Lock()
+		rec.bundle.Entries[requestKey(c.Request)] = &Entry{
+			Status:      c.Writer.Status(),
+			ContentType: c.Writer.Header().Get("Content-Type"),
+			Body:        writer.body.String(),
+			RecordedAt:  time.Now(),
+		}
+		rec.mu.Unlock()
+	}
+}
+
+// Save writes the recorded bundle to disk
+func (rec *Recorder) Save() error {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	data, err := json.MarshalIndent(rec.bundle, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode replay bundle: %w", err)
+	}
+	if err := os.WriteFile(rec.path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write replay bundle: %w", err)
+	}
+
+	log.Printf("💾 Saved %d recorded responses to %s", len(rec.bundle.Entries), rec.path)
+	return nil
+}
+
+// Player serves API responses from a recorded bundle without touching the database
+type Player struct {
+	bundle      *Bundle
+	passthrough map[string]bool
+}
+
+// NewPlayer creates a player for the given bundle. Requests to passthrough paths
+// that are missing from the bundle are handed to the regular handlers.
+func NewPlayer(bundle *Bundle, passthrough ...string) *Player {
+	p := &Player{bundle: bundle, passthrough: make(map[string]bool)}
+	for _, path := range passthrough {
+		p.passthrough[path] = true
+	}
+	return p
+}
+
+// Middleware answers requests from the bundle and aborts the handler chain
+func (p *Player) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if isStream(c.Request) || c.Request.Method == http.MethodOptions {
+			c.Next()
+			return
+		}
+
+		entry, ok := p.bundle.Entries[requestKey(c.Request)]
+		if !ok {
+			if p.passthrough[c.Request.URL.Path] {
+				c.Next()
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": "No recorded response for this request"})
+			return
+		}
+
+		c.Header("X-Replay-Recorded-At", entry.RecordedAt.Format(time.RFC3339))
+		c.Data(entry.Status, entry.ContentType, []byte(entry.Body))
+		c.Abort()
+	}
+}
@@ -0,0 +1,206 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"nadmon-backend/internal/database"
+	"nadmon-backend/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// APIKeyStore keeps partner API keys and their daily usage. Keys are stored as hashes, so a
+// leaked database doesn't leak working keys.
+type APIKeyStore interface {
+	// CreateAPIKey stores a new key under the hash of its secret
+	CreateAPIKey(ctx context.Context, key models.APIKey, hash string) (*models.APIKey, error)
+	// GetAPIKeys returns every key, revoked ones included, with its usage summary, oldest first
+	GetAPIKeys(ctx context.Context) ([]models.APIKey, error)
+	// GetAPIKeyByHash returns the key with the given hash and today's usage, or nil when there
+	// is none
+	GetAPIKeyByHash(ctx context.Context, hash string) (*models.APIKey, error)
+	// RevokeAPIKey marks a key revoked; found is false when it doesn't exist
+	RevokeAPIKey(ctx context.Context, id int64) (found bool, err error)
+	// RecordAPIKeyUsage adds requests to a key's usage on day (YYYY-MM-DD) and bumps its
+	// last use
+	RecordAPIKeyUsage(ctx context.Context, id int64, day string, requests, rejected int64, lastUsed time.Time) error
+	// GetAPIKeyUsage returns a key's usage over the last days days, newest first
+	GetAPIKeyUsage(ctx context.Context, id int64, days int) ([]models.APIKeyUsage, error)
+}
+
+// APIKeyRepository stores partner API keys in the backend-owned schema
+type APIKeyRepository struct {
+	db *sql.DB
+}
+
+// NewAPIKeyRepository creates an API key repository; the schema must have been set up with
+// EnvioDB.SetupAppSchema
+func NewAPIKeyRepository(db *sql.DB) *APIKeyRepository {
+	return &APIKeyRepository{db: db}
+}
+
+// utcToday is the current UTC day; usage is counted per UTC day whatever the database's time
+// zone
+const utcToday = `(NOW() AT TIME ZONE 'UTC')::date`
+
+const apiKeyColumns = `k.id, k.name, k.prefix, k.scopes, k.rate_limit, k.burst, k.daily_quota, k.created_at,
+	k.revoked_at, k.last_used_at`
+
+// scanAPIKey scans apiKeyColumns followed by dest
+func scanAPIKey(row interface{ Scan(...interface{}) error }, dest ...interface{}) (*models.APIKey, error) {
+	var key models.APIKey
+	var revokedAt, lastUsedAt sql.NullTime
+	columns := []interface{}{&key.ID, &key.Name, &key.Prefix, pq.Array(&key.Scopes), &key.RateLimit,
+		&key.Burst, &key.DailyQuota, &key.CreatedAt, &revokedAt, &lastUsedAt}
+	if err := row.Scan(append(columns, dest...)...); err != nil {
+		return nil, err
+	}
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+	if lastUsedAt.Valid {
+		key.LastUsedAt = &lastUsedAt.Time
+	}
+	if key.Scopes == nil {
+		key.Scopes = []string{}
+	}
+	return &key, nil
+}
+
+func (r *APIKeyRepository) CreateAPIKey(ctx context.Context, key models.APIKey, hash string) (*models.APIKey, error) {
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO `+database.AppSchema+`.api_keys (name, prefix, key_hash, scopes, rate_limit, burst, daily_quota)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at
+	`, key.Name, key.Prefix, hash, pq.Array(key.Scopes), key.RateLimit, key.Burst, key.DailyQuota).Scan(&key.ID, &key.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to insert API key: %w", err)
+	}
+	return &key, nil
+}
+
+func (r *APIKeyRepository) GetAPIKeys(ctx context.Context) ([]models.APIKey, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+apiKeyColumns+`,
+			COALESCE(SUM(u.requests) FILTER (WHERE u.day = `+utcToday+`), 0),
+			COALESCE(SUM(u.requests), 0),
+			COALESCE(SUM(u.rejected), 0)
+		FROM `+database.AppSchema+`.api_keys k
+		LEFT JOIN `+database.AppSchema+`.api_key_usage u
+			ON u.key_id = k.id AND u.day > `+utcToday+` - 30
+		GROUP BY k.id
+		ORDER BY k.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query API keys: %w", err)
+	}
+	defer rows.Close()
+
+	keys := []models.APIKey{}
+	for rows.Next() {
+		var usage models.APIKeyUsageSummary
+		key, err := scanAPIKey(rows, &usage.Today, &usage.Last30Days, &usage.Rejected30)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan API key: %w", err)
+		}
+		key.Usage = &usage
+		keys = append(keys, *key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read API keys: %w", err)
+	}
+	return keys, nil
+}
+
+func (r *APIKeyRepository) GetAPIKeyByHash(ctx context.Context, hash string) (*models.APIKey, error) {
+	var usage models.APIKeyUsageSummary
+	key, err := scanAPIKey(r.db.QueryRowContext(ctx, `
+		SELECT `+apiKeyColumns+`, COALESCE(u.requests, 0)
+		FROM `+database.AppSchema+`.api_keys k
+		LEFT JOIN `+database.AppSchema+`.api_key_usage u ON u.key_id = k.id AND u.day = `+utcToday+`
+		WHERE k.key_hash = $1
+	`, hash), &usage.Today)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query API key: %w", err)
+	}
+	key.Usage = &usage
+	return key, nil
+}
+
+func (r *APIKeyRepository) RevokeAPIKey(ctx context.Context, id int64) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE `+database.AppSchema+`.api_keys
+		SET revoked_at = COALESCE(revoked_at, NOW())
+		WHERE id = $1
+	`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke API key: %w", err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to revoke API key: %w", err)
+	}
+	return affected > 0, nil
+}
+
+func (r *APIKeyRepository) RecordAPIKeyUsage(ctx context.Context, id int64, day string, requests, rejected int64, lastUsed time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO `+database.AppSchema+`.api_key_usage (key_id, day, requests, rejected)
+		VALUES ($1, $2::date, $3, $4)
+		ON CONFLICT (key_id, day) DO UPDATE SET
+			requests = api_key_usage.requests + EXCLUDED.requests,
+			rejected = api_key_usage.rejected + EXCLUDED.rejected
+	`, id, day, requests, rejected); err != nil {
+		return fmt.Errorf("failed to record API key usage: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE `+database.AppSchema+`.api_keys
+		SET last_used_at = GREATEST(last_used_at, $2)
+		WHERE id = $1
+	`, id, lastUsed); err != nil {
+		return fmt.Errorf("failed to record API key use: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit API key usage: %w", err)
+	}
+	return nil
+}
+
+func (r *APIKeyRepository) GetAPIKeyUsage(ctx context.Context, id int64, days int) ([]models.APIKeyUsage, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT to_char(day, 'YYYY-MM-DD'), requests, rejected
+		FROM `+database.AppSchema+`.api_key_usage
+		WHERE key_id = $1 AND day > `+utcToday+` - $2::int
+		ORDER BY day DESC
+	`, id, days)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query API key usage: %w", err)
+	}
+	defer rows.Close()
+
+	usage := []models.APIKeyUsage{}
+	for rows.Next() {
+		var day models.APIKeyUsage
+		if err := rows.Scan(&day.Day, &day.Requests, &day.Rejected); err != nil {
+			return nil, fmt.Errorf("failed to scan API key usage: %w", err)
+		}
+		usage = append(usage, day)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read API key usage: %w", err)
+	}
+	return usage, nil
+}
@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"nadmon-backend/internal/database"
+	"nadmon-backend/internal/ethaddr"
+	"nadmon-backend/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// AuditFilter narrows an audit log query; zero fields match every entry
+type AuditFilter struct {
+	Address  string
+	APIKeyID int64
+	ClientIP string
+	From     time.Time // requested at or after
+	To       time.Time // requested before
+	BeforeID int64     // entries older than this one, to page through the log
+}
+
+// AuditStore keeps the audit log of player data reads
+type AuditStore interface {
+	// RecordAuditEntries appends entries to the log in one statement
+	RecordAuditEntries(ctx context.Context, entries []models.AuditEntry) error
+	// GetAuditEntries returns up to limit entries matching filter, newest first
+	GetAuditEntries(ctx context.Context, filter AuditFilter, limit int) ([]models.AuditEntry, error)
+	// PruneAuditEntries drops entries of requests made before the given time
+	PruneAuditEntries(ctx context.Context, before time.Time) (int64, error)
+}
+
+// AuditRepository stores the audit log in the backend-owned schema
+type AuditRepository struct {
+	db *sql.DB
+}
+
+// NewAuditRepository creates an audit repository; the schema must have been set up with
+// EnvioDB.SetupAppSchema
+func NewAuditRepository(db *sql.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+func (r *AuditRepository) RecordAuditEntries(ctx context.Context, entries []models.AuditEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	// One array per column, unnested back into rows
+	n := len(entries)
+	addresses, ips, remoteAddrs, methods, routes, requestIDs, requestedAt := make([]string, n), make([]string, n), make([]string, n), make([]string, n), make([]string, n), make([]string, n), make([]string, n)
+	keyIDs, statuses := make([]int64, n), make([]int64, n)
+	for i, entry := range entries {
+		addresses[i] = entry.Address
+		keyIDs[i] = entry.APIKeyID
+		ips[i] = entry.ClientIP
+		remoteAddrs[i] = entry.RemoteAddr
+		methods[i] = entry.Method
+		routes[i] = entry.Route
+		statuses[i] = int64(entry.Status)
+		requestIDs[i] = entry.RequestID
+		requestedAt[i] = entry.RequestedAt.UTC().Format(time.RFC3339Nano)
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO `+database.AppSchema+`.audit_log
+			(address, api_key_id, client_ip, remote_addr, method, route, status, request_id, requested_at)
+		SELECT address, NULLIF(api_key_id, 0), client_ip, remote_addr, method, route, status, request_id, requested_at
+		FROM UNNEST($1::text[], $2::bigint[], $3::text[], $4::text[], $5::text[], $6::text[], $7::int[], $8::text[], $9::timestamptz[])
+			AS e(address, api_key_id, client_ip, remote_addr, method, route, status, request_id, requested_at)
+	`, pq.Array(addresses), pq.Array(keyIDs), pq.Array(ips), pq.Array(remoteAddrs), pq.Array(methods), pq.Array(routes),
+		pq.Array(statuses), pq.Array(requestIDs), pq.Array(requestedAt))
+	if err != nil {
+		return fmt.Errorf("failed to record audit entries: %w", err)
+	}
+	return nil
+}
+
+func (r *AuditRepository) GetAuditEntries(ctx context.Context, filter AuditFilter, limit int) ([]models.AuditEntry, error) {
+	var conditions []string
+	var args []interface{}
+	where := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+	if filter.Address != "" {
+		where("a.address = $%d", ethaddr.Normalize(filter.Address))
+	}
+	if filter.APIKeyID != 0 {
+		where("a.api_key_id = $%d", filter.APIKeyID)
+	}
+	if filter.ClientIP != "" {
+		where("a.client_ip = $%d", filter.ClientIP)
+	}
+	if !filter.From.IsZero() {
+		where("a.requested_at >= $%d", filter.From)
+	}
+	if !filter.To.IsZero() {
+		where("a.requested_at < $%d", filter.To)
+	}
+	if filter.BeforeID != 0 {
+		where("a.id < $%d", filter.BeforeID)
+	}
+
+	query := `
+		SELECT a.id, a.address, COALESCE(a.api_key_id, 0), COALESCE(k.name, ''), a.client_ip, a.remote_addr, a.method,
+			a.route, a.status, a.request_id, a.requested_at
+		FROM ` + database.AppSchema + `.audit_log a
+		LEFT JOIN ` + database.AppSchema + `.api_keys k ON k.id = a.api_key_id`
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, limit)
+	query += fmt.Sprintf(" ORDER BY a.id DESC LIMIT $%d", len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.AuditEntry{}
+	for rows.Next() {
+		var e models.AuditEntry
+		if err := rows.Scan(&e.ID, &e.Address, &e.APIKeyID, &e.APIKeyName, &e.ClientIP, &e.RemoteAddr, &e.Method,
+			&e.Route, &e.Status, &e.RequestID, &e.RequestedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return entries, nil
+}
+
+func (r *AuditRepository) PruneAuditEntries(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM `+database.AppSchema+`.audit_log WHERE requested_at < $1
+	`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune audit log: %w", err)
+	}
+	return result.RowsAffected()
+}
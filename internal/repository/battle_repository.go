@@ -0,0 +1,284 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+
+	"nadmon-backend/internal/database"
+	"nadmon-backend/internal/ethaddr"
+	"nadmon-backend/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// BattleStore keeps the results of off-chain battles and the PvP ratings they produce
+type BattleStore interface {
+	// RecordBattle stores a battle result and updates both players' ratings. Battle IDs are
+	// recorded once: reporting one again returns the stored battle with created false.
+	RecordBattle(ctx context.Context, battle models.Battle) (recorded *models.Battle, created bool, err error)
+	// GetPlayerBattles returns a page of a player's battles, newest first
+	GetPlayerBattles(ctx context.Context, address string, limit, offset int) (*models.BattlePage, error)
+	// GetPvPRecord returns a player's rating and record, or nil when they never battled
+	GetPvPRecord(ctx context.Context, address string) (*models.PvPRecord, error)
+	// GetPvPLeaderboard returns a page of players ranked by rating, and how many are ranked
+	GetPvPLeaderboard(ctx context.Context, limit, offset int) ([]models.PvPRecord, int, error)
+}
+
+// BattleRepository stores battles and PvP ratings in the backend-owned schema
+type BattleRepository struct {
+	db *sql.DB
+}
+
+// NewBattleRepository creates a battle repository; the schema must have been set up with
+// EnvioDB.SetupAppSchema
+func NewBattleRepository(db *sql.DB) *BattleRepository {
+	return &BattleRepository{db: db}
+}
+
+// eloRatings returns both ratings after a battle in which player 1 scored score1 (1 for a
+// win, 0.5 for a draw, 0 for a loss). The exchange is zero-sum.
+func eloRatings(rating1, rating2 int64, score1 float64) (int64, int64) {
+	expected1 := 1 / (1 + math.Pow(10, float64(rating2-rating1)/400))
+	change := int64(math.Round(models.PvPKFactor * (score1 - expected1)))
+	return rating1 + change, rating2 - change
+}
+
+const battleColumns = `battle_id, player1, player2, winner, player1_team, player2_team,
+	player1_rating_before, player1_rating_after, player2_rating_before, player2_rating_after,
+	fought_at, recorded_at`
+
+// scanBattle scans a row selected with battleColumns
+func scanBattle(row interface{ Scan(...interface{}) error }) (models.Battle, error) {
+	var battle models.Battle
+	var winner sql.NullString
+	err := row.Scan(&battle.BattleID, &battle.Player1.Address, &battle.Player2.Address, &winner,
+		pq.Array(&battle.Player1.TeamTokenIDs), pq.Array(&battle.Player2.TeamTokenIDs),
+		&battle.Player1.RatingBefore, &battle.Player1.RatingAfter,
+		&battle.Player2.RatingBefore, &battle.Player2.RatingAfter,
+		&battle.FoughtAt, &battle.RecordedAt)
+	if winner.Valid {
+		battle.Winner = &winner.String
+	}
+	return battle, err
+}
+
+func (r *BattleRepository) RecordBattle(ctx context.Context, battle models.Battle) (*models.Battle, bool, error) {
+	battle.Player1.Address = ethaddr.Normalize(battle.Player1.Address)
+	battle.Player2.Address = ethaddr.Normalize(battle.Player2.Address)
+	score1 := 0.5
+	if battle.Winner != nil {
+		winner := ethaddr.Normalize(*battle.Winner)
+		battle.Winner = &winner
+		score1 = 0
+		if winner == battle.Player1.Address {
+			score1 = 1
+		}
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to begin battle: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Create and lock both ratings, in address order so concurrent battles between the same
+	// players can't deadlock, before reading them
+	first, second := battle.Player1.Address, battle.Player2.Address
+	if second < first {
+		first, second = second, first
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO `+database.AppSchema+`.pvp_ratings (address, rating)
+		VALUES ($1, $3), ($2, $3)
+		ON CONFLICT (address) DO NOTHING
+	`, first, second, models.InitialPvPRating); err != nil {
+		return nil, false, fmt.Errorf("failed to create ratings: %w", err)
+	}
+	rows, err := tx.QueryContext(ctx, `
+		SELECT address, rating FROM `+database.AppSchema+`.pvp_ratings
+		WHERE address IN ($1, $2)
+		ORDER BY address
+		FOR UPDATE
+	`, first, second)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to lock ratings: %w", err)
+	}
+	ratings := make(map[string]int64, 2)
+	for rows.Next() {
+		var address string
+		var rating int64
+		if err := rows.Scan(&address, &rating); err != nil {
+			rows.Close()
+			return nil, false, fmt.Errorf("failed to scan rating: %w", err)
+		}
+		ratings[address] = rating
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, false, fmt.Errorf("failed to read ratings: %w", err)
+	}
+
+	battle.Player1.RatingBefore = ratings[battle.Player1.Address]
+	battle.Player2.RatingBefore = ratings[battle.Player2.Address]
+	battle.Player1.RatingAfter, battle.Player2.RatingAfter = eloRatings(battle.Player1.RatingBefore, battle.Player2.RatingBefore, score1)
+
+	var winner sql.NullString
+	if battle.Winner != nil {
+		winner = sql.NullString{String: *battle.Winner, Valid: true}
+	}
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO `+database.AppSchema+`.battles (battle_id, player1, player2, winner, player1_team, player2_team,
+			player1_rating_before, player1_rating_after, player2_rating_before, player2_rating_after, fought_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (battle_id) DO NOTHING
+		RETURNING recorded_at
+	`, battle.BattleID, battle.Player1.Address, battle.Player2.Address, winner,
+		pq.Array(battle.Player1.TeamTokenIDs), pq.Array(battle.Player2.TeamTokenIDs),
+		battle.Player1.RatingBefore, battle.Player1.RatingAfter,
+		battle.Player2.RatingBefore, battle.Player2.RatingAfter,
+		battle.FoughtAt).Scan(&battle.RecordedAt)
+	if err == sql.ErrNoRows {
+		// Already recorded, e.g. a retry by the game server; the ratings are left as they were
+		existing, err := scanBattle(tx.QueryRowContext(ctx, `
+			SELECT `+battleColumns+` FROM `+database.AppSchema+`.battles WHERE battle_id = $1
+		`, battle.BattleID))
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to query battle: %w", err)
+		}
+		return &existing, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to save battle: %w", err)
+	}
+
+	for _, side := range []struct {
+		models.BattleSide
+		score float64
+	}{{battle.Player1, score1}, {battle.Player2, 1 - score1}} {
+		var win, loss, draw int
+		switch side.score {
+		case 1:
+			win = 1
+		case 0:
+			loss = 1
+		default:
+			draw = 1
+		}
+		if _, err := tx.ExecContext(ctx, `
+			UPDATE `+database.AppSchema+`.pvp_ratings SET
+				rating = $2,
+				wins = wins + $3,
+				losses = losses + $4,
+				draws = draws + $5,
+				last_battle_at = GREATEST(last_battle_at, $6)
+			WHERE address = $1
+		`, side.Address, side.RatingAfter, win, loss, draw, battle.FoughtAt); err != nil {
+			return nil, false, fmt.Errorf("failed to update rating: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, false, fmt.Errorf("failed to commit battle: %w", err)
+	}
+	return &battle, true, nil
+}
+
+func (r *BattleRepository) GetPlayerBattles(ctx context.Context, address string, limit, offset int) (*models.BattlePage, error) {
+	address = ethaddr.Normalize(address)
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM `+database.AppSchema+`.battles WHERE player1 = $1 OR player2 = $1
+	`, address).Scan(&total); err != nil {
+		return nil, fmt.Errorf("failed to count battles: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+battleColumns+` FROM `+database.AppSchema+`.battles
+		WHERE player1 = $1 OR player2 = $1
+		ORDER BY fought_at DESC, battle_id
+		LIMIT $2 OFFSET $3
+	`, address, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query battles: %w", err)
+	}
+	defer rows.Close()
+
+	battles := []models.Battle{}
+	for rows.Next() {
+		battle, err := scanBattle(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan battle: %w", err)
+		}
+		switch {
+		case battle.Winner == nil:
+			battle.Result = models.BattleResultDraw
+		case *battle.Winner == address:
+			battle.Result = models.BattleResultWin
+		default:
+			battle.Result = models.BattleResultLoss
+		}
+		battles = append(battles, battle)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read battles: %w", err)
+	}
+	return &models.BattlePage{Battles: battles, Total: total}, nil
+}
+
+// pvpRecordColumns selects a models.PvPRecord from pvp_ratings, ranked by rating
+const pvpRecordColumns = `RANK() OVER (ORDER BY rating DESC), address, rating, wins, losses, draws,
+	wins + losses + draws, last_battle_at`
+
+func (r *BattleRepository) GetPvPRecord(ctx context.Context, address string) (*models.PvPRecord, error) {
+	var record models.PvPRecord
+	err := r.db.QueryRowContext(ctx, `
+		SELECT * FROM (
+			SELECT `+pvpRecordColumns+` FROM `+database.AppSchema+`.pvp_ratings
+		) ranked
+		WHERE address = $1
+	`, ethaddr.Normalize(address)).Scan(&record.Rank, &record.Address, &record.Rating,
+		&record.Wins, &record.Losses, &record.Draws, &record.Battles, &record.LastBattleAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query PvP record: %w", err)
+	}
+	return &record, nil
+}
+
+func (r *BattleRepository) GetPvPLeaderboard(ctx context.Context, limit, offset int) ([]models.PvPRecord, int, error) {
+	var total int
+	if err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM `+database.AppSchema+`.pvp_ratings
+	`).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count PvP ratings: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+pvpRecordColumns+` FROM `+database.AppSchema+`.pvp_ratings
+		ORDER BY rating DESC, address
+		LIMIT $1 OFFSET $2
+	`, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query PvP leaderboard: %w", err)
+	}
+	defer rows.Close()
+
+	records := []models.PvPRecord{}
+	for rows.Next() {
+		var record models.PvPRecord
+		if err := rows.Scan(&record.Rank, &record.Address, &record.Rating,
+			&record.Wins, &record.Losses, &record.Draws, &record.Battles, &record.LastBattleAt); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan PvP record: %w", err)
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to read PvP leaderboard: %w", err)
+	}
+	return records, total, nil
+}
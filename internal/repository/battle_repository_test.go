@@ -0,0 +1,42 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"nadmon-backend/internal/models"
+)
+
+func TestRecordBattleConcurrent(t *testing.T) {
+	ctx := context.Background()
+	repo := NewBattleRepository(newAppDB(t).DB)
+
+	// Battles between new players reported in both orders at once must not deadlock
+	for round := 0; round < 20; round++ {
+		a := fmt.Sprintf("0x%040x", 2*round+1)
+		b := fmt.Sprintf("0x%040x", 2*round+2)
+
+		var wg sync.WaitGroup
+		for i, players := range [][2]string{{a, b}, {b, a}} {
+			wg.Add(1)
+			go func(i int, players [2]string) {
+				defer wg.Done()
+				_, _, err := repo.RecordBattle(ctx, models.Battle{
+					BattleID: fmt.Sprintf("round-%d-%d", round, i),
+					Player1:  models.BattleSide{Address: players[0], TeamTokenIDs: []int64{1}},
+					Player2:  models.BattleSide{Address: players[1], TeamTokenIDs: []int64{2}},
+					FoughtAt: time.Now(),
+				})
+				if err != nil {
+					t.Errorf("round %d: %v", round, err)
+				}
+			}(i, players)
+		}
+		wg.Wait()
+	}
+}
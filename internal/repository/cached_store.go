@@ -0,0 +1,305 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"nadmon-backend/internal/logging"
+	"nadmon-backend/internal/metrics"
+	"nadmon-backend/internal/models"
+)
+
+// Cache is a byte-oriented key/value cache with expiry
+type Cache interface {
+	Get(key string) (value []byte, found bool, err error)
+	Set(key string, value []byte, ttl time.Duration) error
+	DeletePrefix(prefix string) error
+}
+
+// CacheTTLs configures how long each group of cached results lives
+type CacheTTLs struct {
+	Player    time.Duration // inventories, profiles and pack history
+	NFT       time.Duration // single NFTs, batches, history and packs by ID
+	Aggregate time.Duration // leaderboards, game stats and analytics
+}
+
+// Key prefixes; invalidation deletes whole prefixes
+const (
+	cachePrefix          = "nadmon:"
+	cachePlayerPrefix    = cachePrefix + "player:"
+	cacheNFTPrefix       = cachePrefix + "nft:"
+	cacheNFTBatchPrefix  = cachePrefix + "nfts:"
+	cacheAggregatePrefix = cachePrefix + "agg:"
+)
+
+// Bypasser is implemented by wrapping stores that can hand out the store they wrap
+type Bypasser interface {
+	Bypass() Store
+}
+
+// CachedStore caches hot Store reads. Results are invalidated by TTL and explicitly
+// through Invalidate when the event pipeline sees new rows.
+type CachedStore struct {
+	Store
+	cache Cache
+	ttls  CacheTTLs
+}
+
+// NewCachedStore wraps store with cache
+func NewCachedStore(store Store, cache Cache, ttls CacheTTLs) *CachedStore {
+	return &CachedStore{Store: store, cache: cache, ttls: ttls}
+}
+
+// Bypass returns the wrapped store, skipping the cache
+func (s *CachedStore) Bypass() Store {
+	return s.Store
+}
+
+// Invalidate drops cached results affected by changes to the given players and tokens
+func (s *CachedStore) Invalidate(addresses []string, tokenIDs []int64) {
+	prefixes := []string{cacheAggregatePrefix}
+	for _, address := range addresses {
+		prefixes = append(prefixes, cachePlayerPrefix+strings.ToLower(address)+":")
+	}
+	if len(tokenIDs) > 0 {
+		prefixes = append(prefixes, cacheNFTBatchPrefix)
+	}
+	for _, id := range tokenIDs {
+		prefixes = append(prefixes, fmt.Sprintf("%s%d:", cacheNFTPrefix, id))
+	}
+
+	for _, prefix := range prefixes {
+		if err := s.cache.DeletePrefix(prefix); err != nil {
+			log.Printf("Warning: failed to invalidate cache %s*: %v", prefix, err)
+		}
+	}
+}
+
+// Flush drops every cached result
+func (s *CachedStore) Flush() error {
+	if err := s.cache.DeletePrefix(cachePrefix); err != nil {
+		return fmt.Errorf("failed to flush cache: %w", err)
+	}
+	return nil
+}
+
+// cached returns the cached result for key, or calls load and caches its result.
+// Cache failures are logged and fall through to load.
+func cached[T any](ctx context.Context, s *CachedStore, key string, ttl time.Duration, load func() (T, error)) (T, error) {
+	if data, found, err := s.cache.Get(key); err != nil {
+		metrics.ObserveCache(metrics.CacheError)
+		logging.FromContext(ctx).Warn("Cache get failed", "key", key, "error", err)
+	} else if found {
+		var result T
+		if err := json.Unmarshal(data, &result); err == nil {
+			metrics.ObserveCache(metrics.CacheHit)
+			return result, nil
+		}
+		metrics.ObserveCache(metrics.CacheError)
+	} else {
+		metrics.ObserveCache(metrics.CacheMiss)
+	}
+
+	result, err := load()
+	if err != nil {
+		return result, err
+	}
+
+	if data, err := json.Marshal(result); err == nil {
+		if err := s.cache.Set(key, data, ttl); err != nil {
+			logging.FromContext(ctx).Warn("Cache set failed", "key", key, "error", err)
+		}
+	}
+	return result, nil
+}
+
+func playerKey(address, method string) string {
+	return cachePlayerPrefix + strings.ToLower(address) + ":" + method
+}
+
+func nftKey(tokenID int64, method string) string {
+	return fmt.Sprintf("%s%d:%s", cacheNFTPrefix, tokenID, method)
+}
+
+func (s *CachedStore) GetPlayerNadmons(ctx context.Context, address string) ([]models.Nadmon, error) {
+	return cached(ctx, s, playerKey(address, "nadmons"), s.ttls.Player, func() ([]models.Nadmon, error) {
+		return s.Store.GetPlayerNadmons(ctx, address)
+	})
+}
+
+func (s *CachedStore) GetPlayerProfile(ctx context.Context, address string) (*models.PlayerProfile, error) {
+	return cached(ctx, s, playerKey(address, "profile"), s.ttls.Player, func() (*models.PlayerProfile, error) {
+		return s.Store.GetPlayerProfile(ctx, address)
+	})
+}
+
+func (s *CachedStore) GetPlayerSummary(ctx context.Context, address string) (*models.PlayerSummary, error) {
+	return cached(ctx, s, playerKey(address, "summary"), s.ttls.Player, func() (*models.PlayerSummary, error) {
+		return s.Store.GetPlayerSummary(ctx, address)
+	})
+}
+
+func (s *CachedStore) GetPlayerPacks(ctx context.Context, address string) ([]models.Pack, error) {
+	return cached(ctx, s, playerKey(address, "packs"), s.ttls.Player, func() ([]models.Pack, error) {
+		return s.Store.GetPlayerPacks(ctx, address)
+	})
+}
+
+func (s *CachedStore) GetPlayerPackSummary(ctx context.Context, address string, recent int) (*models.PackSummary, error) {
+	return cached(ctx, s, playerKey(address, fmt.Sprintf("packs:summary:%d", recent)), s.ttls.Player, func() (*models.PackSummary, error) {
+		return s.Store.GetPlayerPackSummary(ctx, address, recent)
+	})
+}
+
+// The dex also changes when anyone mints a new species, so it lives under the aggregate prefix
+func (s *CachedStore) GetPlayerDex(ctx context.Context, address string) (*models.Dex, error) {
+	return cached(ctx, s, cacheAggregatePrefix+"dex:"+strings.ToLower(address), s.ttls.Player, func() (*models.Dex, error) {
+		return s.Store.GetPlayerDex(ctx, address)
+	})
+}
+
+func (s *CachedStore) GetSingleNadmon(ctx context.Context, tokenID int64) (*models.Nadmon, error) {
+	return cached(ctx, s, nftKey(tokenID, "nadmon"), s.ttls.NFT, func() (*models.Nadmon, error) {
+		return s.Store.GetSingleNadmon(ctx, tokenID)
+	})
+}
+
+func (s *CachedStore) GetNadmonHistory(ctx context.Context, tokenID int64) ([]models.StatsChange, error) {
+	return cached(ctx, s, nftKey(tokenID, "history"), s.ttls.NFT, func() ([]models.StatsChange, error) {
+		return s.Store.GetNadmonHistory(ctx, tokenID)
+	})
+}
+
+func (s *CachedStore) GetFusions(ctx context.Context, tokenIDs []int64) ([]models.Fusion, error) {
+	ids := make([]string, len(tokenIDs))
+	for i, id := range tokenIDs {
+		ids[i] = fmt.Sprint(id)
+	}
+	return cached(ctx, s, cacheNFTBatchPrefix+"fusions:"+strings.Join(ids, ","), s.ttls.NFT, func() ([]models.Fusion, error) {
+		return s.Store.GetFusions(ctx, tokenIDs)
+	})
+}
+
+func (s *CachedStore) GetNadmonsByIDs(ctx context.Context, tokenIDs []int64) ([]models.Nadmon, error) {
+	ids := make([]string, len(tokenIDs))
+	for i, id := range tokenIDs {
+		ids[i] = fmt.Sprint(id)
+	}
+	return cached(ctx, s, cacheNFTBatchPrefix+strings.Join(ids, ","), s.ttls.NFT, func() ([]models.Nadmon, error) {
+		return s.Store.GetNadmonsByIDs(ctx, tokenIDs)
+	})
+}
+
+// Packs never change once minted, so packs by ID use the NFT TTL without invalidation
+func (s *CachedStore) GetNadmonTransfers(ctx context.Context, tokenID int64, limit, offset int) (*models.TransferPage, error) {
+	return cached(ctx, s, nftKey(tokenID, fmt.Sprintf("transfers:%d:%d", limit, offset)), s.ttls.NFT, func() (*models.TransferPage, error) {
+		return s.Store.GetNadmonTransfers(ctx, tokenID, limit, offset)
+	})
+}
+
+func (s *CachedStore) GetNadmonSales(ctx context.Context, tokenID int64, limit, offset int) (*models.SalePage, error) {
+	return cached(ctx, s, nftKey(tokenID, fmt.Sprintf("sales:%d:%d", limit, offset)), s.ttls.NFT, func() (*models.SalePage, error) {
+		return s.Store.GetNadmonSales(ctx, tokenID, limit, offset)
+	})
+}
+
+func (s *CachedStore) GetMarketStats(ctx context.Context, rarity, element string) (*models.MarketReport, error) {
+	return cached(ctx, s, fmt.Sprintf("%smarket:%s:%s", cacheAggregatePrefix, rarity, element), s.ttls.Aggregate, func() (*models.MarketReport, error) {
+		return s.Store.GetMarketStats(ctx, rarity, element)
+	})
+}
+
+func (s *CachedStore) GetPlayerTransfers(ctx context.Context, address string, limit, offset int) (*models.TransferPage, error) {
+	return cached(ctx, s, playerKey(address, fmt.Sprintf("transfers:%d:%d", limit, offset)), s.ttls.Player, func() (*models.TransferPage, error) {
+		return s.Store.GetPlayerTransfers(ctx, address, limit, offset)
+	})
+}
+
+func (s *CachedStore) GetActivity(ctx context.Context, types []string, limit, offset int) (*models.ActivityPage, error) {
+	key := fmt.Sprintf("%sactivity:%s:%d:%d", cacheAggregatePrefix, strings.Join(types, ","), limit, offset)
+	return cached(ctx, s, key, s.ttls.Aggregate, func() (*models.ActivityPage, error) {
+		return s.Store.GetActivity(ctx, types, limit, offset)
+	})
+}
+
+func (s *CachedStore) GetPlayerActivity(ctx context.Context, address string, types []string, limit, offset int) (*models.ActivityPage, error) {
+	key := playerKey(address, fmt.Sprintf("activity:%s:%d:%d", strings.Join(types, ","), limit, offset))
+	return cached(ctx, s, key, s.ttls.Player, func() (*models.ActivityPage, error) {
+		return s.Store.GetPlayerActivity(ctx, address, types, limit, offset)
+	})
+}
+
+func (s *CachedStore) GetPackByID(ctx context.Context, packID int64) (*models.Pack, error) {
+	return cached(ctx, s, fmt.Sprintf("%spack:%d", cachePrefix, packID), s.ttls.NFT, func() (*models.Pack, error) {
+		return s.Store.GetPackByID(ctx, packID)
+	})
+}
+
+func (s *CachedStore) GetRecentPacks(ctx context.Context, limit int) ([]models.Pack, error) {
+	return cached(ctx, s, fmt.Sprintf("%srecent-packs:%d", cacheAggregatePrefix, limit), s.ttls.Aggregate, func() ([]models.Pack, error) {
+		return s.Store.GetRecentPacks(ctx, limit)
+	})
+}
+
+func (s *CachedStore) GetTopCollectors(ctx context.Context, limit int) ([]models.PlayerProfile, error) {
+	return cached(ctx, s, fmt.Sprintf("%stop-collectors:%d", cacheAggregatePrefix, limit), s.ttls.Aggregate, func() ([]models.PlayerProfile, error) {
+		return s.Store.GetTopCollectors(ctx, limit)
+	})
+}
+
+func (s *CachedStore) GetLeaderboard(ctx context.Context, kind, player string, limit, offset int) (*models.Leaderboard, error) {
+	key := fmt.Sprintf("%sleaderboard:%s:%s:%d:%d", cacheAggregatePrefix, kind, strings.ToLower(player), limit, offset)
+	return cached(ctx, s, key, s.ttls.Aggregate, func() (*models.Leaderboard, error) {
+		return s.Store.GetLeaderboard(ctx, kind, player, limit, offset)
+	})
+}
+
+func (s *CachedStore) GetGameStats(ctx context.Context) (*models.GameStats, error) {
+	return cached(ctx, s, cacheAggregatePrefix+"game-stats", s.ttls.Aggregate, func() (*models.GameStats, error) {
+		return s.Store.GetGameStats(ctx)
+	})
+}
+
+func (s *CachedStore) GetPackDistribution(ctx context.Context) (*models.PackDistribution, error) {
+	return cached(ctx, s, cacheAggregatePrefix+"pack-distribution", s.ttls.Aggregate, func() (*models.PackDistribution, error) {
+		return s.Store.GetPackDistribution(ctx)
+	})
+}
+
+func (s *CachedStore) GetOwnershipConcentration(ctx context.Context) (*models.OwnershipConcentration, error) {
+	return cached(ctx, s, cacheAggregatePrefix+"concentration", s.ttls.Aggregate, func() (*models.OwnershipConcentration, error) {
+		return s.Store.GetOwnershipConcentration(ctx)
+	})
+}
+
+func (s *CachedStore) GetSupplyStats(ctx context.Context) (*models.SupplyStats, error) {
+	return cached(ctx, s, cacheAggregatePrefix+"supply", s.ttls.Aggregate, func() (*models.SupplyStats, error) {
+		return s.Store.GetSupplyStats(ctx)
+	})
+}
+
+func (s *CachedStore) GetTraitStats(ctx context.Context, dimension string) (*models.TraitBreakdown, error) {
+	return cached(ctx, s, cacheAggregatePrefix+"traits:"+dimension, s.ttls.Aggregate, func() (*models.TraitBreakdown, error) {
+		return s.Store.GetTraitStats(ctx, dimension)
+	})
+}
+
+func (s *CachedStore) GetPackOdds(ctx context.Context, from, to time.Time) (*models.PackOddsReport, error) {
+	// Windows with an open end are cached under the zero time, so the default all-time report is shared
+	key := fmt.Sprintf("%spack-odds:%d:%d", cacheAggregatePrefix, unixOrZero(from), unixOrZero(to))
+	return cached(ctx, s, key, s.ttls.Aggregate, func() (*models.PackOddsReport, error) {
+		return s.Store.GetPackOdds(ctx, from, to)
+	})
+}
+
+// unixOrZero returns the Unix time of t, or 0 for the zero time
+func unixOrZero(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Unix()
+}
@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ValidChainID reports whether id is a decimal EVM chain ID
+func ValidChainID(id string) bool {
+	n, err := strconv.ParseUint(id, 10, 64)
+	return err == nil && n > 0 && strconv.FormatUint(n, 10) == id
+}
+
+// ParseChains parses "chainID=databaseURL" entries (e.g. "1=postgres://...") into the
+// Envio database URL of each additional chain
+func ParseChains(entries []string) (map[string]string, error) {
+	chains := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		id, url, ok := strings.Cut(entry, "=")
+		id, url = strings.TrimSpace(id), strings.TrimSpace(url)
+		if !ok || url == "" {
+			return nil, fmt.Errorf("invalid chain database %q, expected chainID=url", id)
+		}
+		if !ValidChainID(id) {
+			return nil, fmt.Errorf("invalid chain ID %q", id)
+		}
+		if _, exists := chains[id]; exists {
+			return nil, fmt.Errorf("chain %s is configured twice", id)
+		}
+		chains[id] = url
+	}
+	return chains, nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"nadmon-backend/internal/database"
+	"nadmon-backend/internal/database/envio"
+)
+
+// DefaultTablePrefix is the Envio table prefix of the original Nadmon contract; queries are
+// written against "NadmonNFT_<Event>" tables
+const DefaultTablePrefix = "NadmonNFT"
+
+// validTablePrefix matches prefixes that are safe to splice into quoted table names
+var validTablePrefix = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// ParseCollections parses "name=TablePrefix" entries (e.g. "items=NadmonItems") into a
+// collection registry
+func ParseCollections(entries []string) (map[string]string, error) {
+	collections := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		name, prefix, ok := strings.Cut(entry, "=")
+		name, prefix = strings.ToLower(strings.TrimSpace(name)), strings.TrimSpace(prefix)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid collection %q, expected name=TablePrefix", entry)
+		}
+		if !validTablePrefix.MatchString(prefix) {
+			return nil, fmt.Errorf("invalid table prefix %q for collection %s", prefix, name)
+		}
+		if _, exists := collections[name]; exists {
+			return nil, fmt.Errorf("collection %s is configured twice", name)
+		}
+		collections[name] = prefix
+	}
+	return collections, nil
+}
+
+// NewCollectionRepository creates a repository for a sibling collection whose Envio tables are
+// named "<prefix>_<Event>" with the same columns as the Nadmon tables. Only the default
+// collection is served from the current-state table and continuous aggregates.
+func NewCollectionRepository(db *database.EnvioDB, conn envio.DBTX, prefix string) *NadmonRepository {
+	if prefix == DefaultTablePrefix {
+		return NewNadmonRepositoryWithConn(db, conn)
+	}
+
+	repo := NewNadmonRepositoryWithConn(db, &tablePrefixConn{
+		DBTX:     conn,
+		replacer: strings.NewReplacer(`"`+DefaultTablePrefix+`_`, `"`+prefix+`_`),
+	})
+	repo.prefix = prefix
+	return repo
+}
+
+// tablePrefixConn rewrites the default table prefix in every query, so the generated and
+// dynamic queries read another collection's tables
+type tablePrefixConn struct {
+	envio.DBTX
+	replacer *strings.Replacer
+}
+
+func (c *tablePrefixConn) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return c.DBTX.ExecContext(ctx, c.replacer.Replace(query), args...)
+}
+
+func (c *tablePrefixConn) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return c.DBTX.PrepareContext(ctx, c.replacer.Replace(query))
+}
+
+func (c *tablePrefixConn) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return c.DBTX.QueryContext(ctx, c.replacer.Replace(query), args...)
+}
+
+func (c *tablePrefixConn) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return c.DBTX.QueryRowContext(ctx, c.replacer.Replace(query), args...)
+}
@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sync/singleflight"
+
+	"nadmon-backend/internal/metrics"
+	"nadmon-backend/internal/models"
+)
+
+// DedupedStore collapses concurrent identical reads of the queries a pack opening hammers,
+// packs by ID, NFT batches and NFT statuses, into one round trip. Unlike the caches it keeps
+// nothing: a request arriving after the shared load finished runs its own. Results are shared
+// between concurrent callers and must not be modified.
+type DedupedStore struct {
+	Store
+	group singleflight.Group
+}
+
+// NewDedupedStore wraps store with in-flight request deduplication
+func NewDedupedStore(store Store) *DedupedStore {
+	return &DedupedStore{Store: store}
+}
+
+// Bypass returns the store below every cache layer
+func (s *DedupedStore) Bypass() Store {
+	if bypasser, ok := s.Store.(Bypasser); ok {
+		return bypasser.Bypass()
+	}
+	return s.Store
+}
+
+func (s *DedupedStore) GetPackByID(ctx context.Context, packID int64) (*models.Pack, error) {
+	result, err := s.load(ctx, "pack:"+strconv.FormatInt(packID, 10), func(ctx context.Context) (interface{}, error) {
+		return s.Store.GetPackByID(ctx, packID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*models.Pack), nil
+}
+
+// Batches are keyed on their sorted, distinct IDs: every backend returns the NFTs in token ID
+// order whatever order they were asked for in
+func (s *DedupedStore) GetNadmonsByIDs(ctx context.Context, tokenIDs []int64) ([]models.Nadmon, error) {
+	ids := distinctSorted(tokenIDs)
+	result, err := s.load(ctx, "nfts:"+joinIDs(ids), func(ctx context.Context) (interface{}, error) {
+		return s.Store.GetNadmonsByIDs(ctx, ids)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.([]models.Nadmon), nil
+}
+
+func (s *DedupedStore) GetNadmonStatuses(ctx context.Context, tokenIDs []int64) (map[int64]models.NadmonStatus, error) {
+	ids := distinctSorted(tokenIDs)
+	result, err := s.load(ctx, "statuses:"+joinIDs(ids), func(ctx context.Context) (interface{}, error) {
+		return s.Store.GetNadmonStatuses(ctx, ids)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(map[int64]models.NadmonStatus), nil
+}
+
+func (s *DedupedStore) load(ctx context.Context, key string, fn func(context.Context) (interface{}, error)) (interface{}, error) {
+	result, shared, err := loadShared(ctx, &s.group, key, fn)
+	metrics.ObserveDedup(shared)
+	return result, err
+}
+
+// loadShared runs fn once for all concurrent callers of group with the same key. It runs
+// detached from the caller's cancellation, so one client going away doesn't fail the others
+// waiting on it; each caller still returns as soon as its own context is done. shared reports
+// whether the result was handed to more than one caller.
+func loadShared(ctx context.Context, group *singleflight.Group, key string, fn func(context.Context) (interface{}, error)) (result interface{}, shared bool, err error) {
+	ch := group.DoChan(key, func() (interface{}, error) {
+		return fn(context.WithoutCancel(ctx))
+	})
+
+	select {
+	case res := <-ch:
+		return res.Val, res.Shared, res.Err
+	case <-ctx.Done():
+		return nil, false, ctx.Err()
+	}
+}
+
+// distinctSorted returns the distinct IDs in ascending order
+func distinctSorted(ids []int64) []int64 {
+	sorted := append([]int64(nil), ids...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	distinct := sorted[:0]
+	for i, id := range sorted {
+		if i == 0 || id != sorted[i-1] {
+			distinct = append(distinct, id)
+		}
+	}
+	return distinct
+}
+
+func joinIDs(ids []int64) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatInt(id, 10)
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"nadmon-backend/internal/database"
+	"nadmon-backend/internal/ethaddr"
+	"nadmon-backend/internal/models"
+)
+
+// ErrFavoriteLimit is returned when a player already has the most favorites allowed
+var ErrFavoriteLimit = errors.New("favorite limit reached")
+
+// FavoriteStore keeps the Nadmons players starred, held or wished for
+type FavoriteStore interface {
+	// GetFavorites returns a player's favorites, most recently starred first
+	GetFavorites(ctx context.Context, address string) ([]models.Favorite, error)
+	// AddFavorite stars a Nadmon unless the player already has maxFavorites; starring one
+	// again returns the stored favorite with created false
+	AddFavorite(ctx context.Context, address string, tokenID int64, maxFavorites int) (favorite *models.Favorite, created bool, err error)
+	// RemoveFavorite unstars a Nadmon; found is false when it wasn't starred
+	RemoveFavorite(ctx context.Context, address string, tokenID int64) (found bool, err error)
+}
+
+// FavoriteRepository stores favorites in the backend-owned schema
+type FavoriteRepository struct {
+	db *sql.DB
+}
+
+// NewFavoriteRepository creates a favorite repository; the schema must have been set up with
+// EnvioDB.SetupAppSchema
+func NewFavoriteRepository(db *sql.DB) *FavoriteRepository {
+	return &FavoriteRepository{db: db}
+}
+
+func (r *FavoriteRepository) GetFavorites(ctx context.Context, address string) ([]models.Favorite, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT token_id, created_at
+		FROM `+database.AppSchema+`.favorites
+		WHERE address = $1
+		ORDER BY created_at DESC, token_id
+	`, ethaddr.Normalize(address))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query favorites: %w", err)
+	}
+	defer rows.Close()
+
+	favorites := []models.Favorite{}
+	for rows.Next() {
+		var favorite models.Favorite
+		if err := rows.Scan(&favorite.TokenID, &favorite.FavoritedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan favorite: %w", err)
+		}
+		favorites = append(favorites, favorite)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read favorites: %w", err)
+	}
+	return favorites, nil
+}
+
+func (r *FavoriteRepository) AddFavorite(ctx context.Context, address string, tokenID int64, maxFavorites int) (*models.Favorite, bool, error) {
+	address = ethaddr.Normalize(address)
+	favorite := models.Favorite{TokenID: tokenID}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to begin favorite: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockOwner(ctx, tx, "favorites", address); err != nil {
+		return nil, false, err
+	}
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO `+database.AppSchema+`.favorites (address, token_id)
+		SELECT $1, $2
+		WHERE (SELECT COUNT(*) FROM `+database.AppSchema+`.favorites WHERE address = $1) < $3
+		ON CONFLICT (address, token_id) DO NOTHING
+		RETURNING created_at
+	`, address, tokenID, maxFavorites).Scan(&favorite.FavoritedAt)
+	if err == nil {
+		if err := tx.Commit(); err != nil {
+			return nil, false, fmt.Errorf("failed to commit favorite: %w", err)
+		}
+		return &favorite, true, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, false, fmt.Errorf("failed to save favorite: %w", err)
+	}
+
+	// Nothing was inserted: the Nadmon is starred already, or the player is at the limit
+	err = tx.QueryRowContext(ctx, `
+		SELECT created_at FROM `+database.AppSchema+`.favorites WHERE address = $1 AND token_id = $2
+	`, address, tokenID).Scan(&favorite.FavoritedAt)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, false, ErrFavoriteLimit
+	case err != nil:
+		return nil, false, fmt.Errorf("failed to fetch favorite: %w", err)
+	}
+	return &favorite, false, nil
+}
+
+func (r *FavoriteRepository) RemoveFavorite(ctx context.Context, address string, tokenID int64) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM `+database.AppSchema+`.favorites WHERE address = $1 AND token_id = $2
+	`, ethaddr.Normalize(address), tokenID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete favorite: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete favorite: %w", err)
+	}
+	return deleted > 0, nil
+}
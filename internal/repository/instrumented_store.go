@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"nadmon-backend/internal/logging"
+	"nadmon-backend/internal/metrics"
+	"nadmon-backend/internal/models"
+)
+
+// InstrumentedStore records the latency and errors of every Store call in Prometheus and
+// logs failed calls
+type InstrumentedStore struct {
+	Store
+}
+
+// NewInstrumentedStore wraps store with per-method query metrics
+func NewInstrumentedStore(store Store) *InstrumentedStore {
+	return &InstrumentedStore{Store: store}
+}
+
+// instrumented times call and records it under method; failures are logged with the request ID
+func instrumented[T any](ctx context.Context, method string, call func() (T, error)) (T, error) {
+	start := time.Now()
+	result, err := call()
+	metrics.ObserveQuery(method, start, err)
+	if err != nil {
+		logging.FromContext(ctx).Error("Store call failed", "method", method, "error", err)
+	}
+	return result, err
+}
+
+func (s *InstrumentedStore) GetPlayerNadmons(ctx context.Context, address string) ([]models.Nadmon, error) {
+	return instrumented(ctx, "GetPlayerNadmons", func() ([]models.Nadmon, error) {
+		return s.Store.GetPlayerNadmons(ctx, address)
+	})
+}
+
+func (s *InstrumentedStore) GetPlayerProfile(ctx context.Context, address string) (*models.PlayerProfile, error) {
+	return instrumented(ctx, "GetPlayerProfile", func() (*models.PlayerProfile, error) {
+		return s.Store.GetPlayerProfile(ctx, address)
+	})
+}
+
+func (s *InstrumentedStore) GetPlayerSummary(ctx context.Context, address string) (*models.PlayerSummary, error) {
+	return instrumented(ctx, "GetPlayerSummary", func() (*models.PlayerSummary, error) {
+		return s.Store.GetPlayerSummary(ctx, address)
+	})
+}
+
+func (s *InstrumentedStore) GetPlayerPacks(ctx context.Context, address string) ([]models.Pack, error) {
+	return instrumented(ctx, "GetPlayerPacks", func() ([]models.Pack, error) {
+		return s.Store.GetPlayerPacks(ctx, address)
+	})
+}
+
+func (s *InstrumentedStore) GetPlayerPackSummary(ctx context.Context, address string, recent int) (*models.PackSummary, error) {
+	return instrumented(ctx, "GetPlayerPackSummary", func() (*models.PackSummary, error) {
+		return s.Store.GetPlayerPackSummary(ctx, address, recent)
+	})
+}
+
+func (s *InstrumentedStore) GetPlayerDex(ctx context.Context, address string) (*models.Dex, error) {
+	return instrumented(ctx, "GetPlayerDex", func() (*models.Dex, error) {
+		return s.Store.GetPlayerDex(ctx, address)
+	})
+}
+
+func (s *InstrumentedStore) SearchNadmons(ctx context.Context, address string, filters map[string]interface{}) ([]models.Nadmon, error) {
+	return instrumented(ctx, "SearchNadmons", func() ([]models.Nadmon, error) {
+		return s.Store.SearchNadmons(ctx, address, filters)
+	})
+}
+
+func (s *InstrumentedStore) GetSingleNadmon(ctx context.Context, tokenID int64) (*models.Nadmon, error) {
+	return instrumented(ctx, "GetSingleNadmon", func() (*models.Nadmon, error) {
+		return s.Store.GetSingleNadmon(ctx, tokenID)
+	})
+}
+
+func (s *InstrumentedStore) GetNadmonsByIDs(ctx context.Context, tokenIDs []int64) ([]models.Nadmon, error) {
+	return instrumented(ctx, "GetNadmonsByIDs", func() ([]models.Nadmon, error) {
+		return s.Store.GetNadmonsByIDs(ctx, tokenIDs)
+	})
+}
+
+func (s *InstrumentedStore) GetNadmonHistory(ctx context.Context, tokenID int64) ([]models.StatsChange, error) {
+	return instrumented(ctx, "GetNadmonHistory", func() ([]models.StatsChange, error) {
+		return s.Store.GetNadmonHistory(ctx, tokenID)
+	})
+}
+
+func (s *InstrumentedStore) GetFusions(ctx context.Context, tokenIDs []int64) ([]models.Fusion, error) {
+	return instrumented(ctx, "GetFusions", func() ([]models.Fusion, error) {
+		return s.Store.GetFusions(ctx, tokenIDs)
+	})
+}
+
+func (s *InstrumentedStore) GetInventoryChanges(ctx context.Context, address string, sinceSequence int64) (*models.InventoryChanges, error) {
+	return instrumented(ctx, "GetInventoryChanges", func() (*models.InventoryChanges, error) {
+		return s.Store.GetInventoryChanges(ctx, address, sinceSequence)
+	})
+}
+
+func (s *InstrumentedStore) GetHolderSnapshot(ctx context.Context, asOf time.Time) (*models.HolderSnapshot, error) {
+	return instrumented(ctx, "GetHolderSnapshot", func() (*models.HolderSnapshot, error) {
+		return s.Store.GetHolderSnapshot(ctx, asOf)
+	})
+}
+
+func (s *InstrumentedStore) GetNadmonSnapshot(ctx context.Context, afterTokenID int64, limit int) ([]models.Nadmon, error) {
+	return instrumented(ctx, "GetNadmonSnapshot", func() ([]models.Nadmon, error) {
+		return s.Store.GetNadmonSnapshot(ctx, afterTokenID, limit)
+	})
+}
+
+func (s *InstrumentedStore) GetNadmonStatuses(ctx context.Context, tokenIDs []int64) (map[int64]models.NadmonStatus, error) {
+	return instrumented(ctx, "GetNadmonStatuses", func() (map[int64]models.NadmonStatus, error) {
+		return s.Store.GetNadmonStatuses(ctx, tokenIDs)
+	})
+}
+
+func (s *InstrumentedStore) GetNadmonTransfers(ctx context.Context, tokenID int64, limit, offset int) (*models.TransferPage, error) {
+	return instrumented(ctx, "GetNadmonTransfers", func() (*models.TransferPage, error) {
+		return s.Store.GetNadmonTransfers(ctx, tokenID, limit, offset)
+	})
+}
+
+func (s *InstrumentedStore) GetNadmonSales(ctx context.Context, tokenID int64, limit, offset int) (*models.SalePage, error) {
+	return instrumented(ctx, "GetNadmonSales", func() (*models.SalePage, error) {
+		return s.Store.GetNadmonSales(ctx, tokenID, limit, offset)
+	})
+}
+
+func (s *InstrumentedStore) GetMarketStats(ctx context.Context, rarity, element string) (*models.MarketReport, error) {
+	return instrumented(ctx, "GetMarketStats", func() (*models.MarketReport, error) {
+		return s.Store.GetMarketStats(ctx, rarity, element)
+	})
+}
+
+func (s *InstrumentedStore) GetPlayerTransfers(ctx context.Context, address string, limit, offset int) (*models.TransferPage, error) {
+	return instrumented(ctx, "GetPlayerTransfers", func() (*models.TransferPage, error) {
+		return s.Store.GetPlayerTransfers(ctx, address, limit, offset)
+	})
+}
+
+func (s *InstrumentedStore) GetActivity(ctx context.Context, types []string, limit, offset int) (*models.ActivityPage, error) {
+	return instrumented(ctx, "GetActivity", func() (*models.ActivityPage, error) {
+		return s.Store.GetActivity(ctx, types, limit, offset)
+	})
+}
+
+func (s *InstrumentedStore) GetPlayerActivity(ctx context.Context, address string, types []string, limit, offset int) (*models.ActivityPage, error) {
+	return instrumented(ctx, "GetPlayerActivity", func() (*models.ActivityPage, error) {
+		return s.Store.GetPlayerActivity(ctx, address, types, limit, offset)
+	})
+}
+
+func (s *InstrumentedStore) GetPackByID(ctx context.Context, packID int64) (*models.Pack, error) {
+	return instrumented(ctx, "GetPackByID", func() (*models.Pack, error) {
+		return s.Store.GetPackByID(ctx, packID)
+	})
+}
+
+func (s *InstrumentedStore) GetRecentPacks(ctx context.Context, limit int) ([]models.Pack, error) {
+	return instrumented(ctx, "GetRecentPacks", func() ([]models.Pack, error) {
+		return s.Store.GetRecentPacks(ctx, limit)
+	})
+}
+
+func (s *InstrumentedStore) GetTopCollectors(ctx context.Context, limit int) ([]models.PlayerProfile, error) {
+	return instrumented(ctx, "GetTopCollectors", func() ([]models.PlayerProfile, error) {
+		return s.Store.GetTopCollectors(ctx, limit)
+	})
+}
+
+func (s *InstrumentedStore) GetLeaderboard(ctx context.Context, kind, player string, limit, offset int) (*models.Leaderboard, error) {
+	return instrumented(ctx, "GetLeaderboard", func() (*models.Leaderboard, error) {
+		return s.Store.GetLeaderboard(ctx, kind, player, limit, offset)
+	})
+}
+
+func (s *InstrumentedStore) GetGameStats(ctx context.Context) (*models.GameStats, error) {
+	return instrumented(ctx, "GetGameStats", func() (*models.GameStats, error) {
+		return s.Store.GetGameStats(ctx)
+	})
+}
+
+func (s *InstrumentedStore) GetPackDistribution(ctx context.Context) (*models.PackDistribution, error) {
+	return instrumented(ctx, "GetPackDistribution", func() (*models.PackDistribution, error) {
+		return s.Store.GetPackDistribution(ctx)
+	})
+}
+
+func (s *InstrumentedStore) GetOwnershipConcentration(ctx context.Context) (*models.OwnershipConcentration, error) {
+	return instrumented(ctx, "GetOwnershipConcentration", func() (*models.OwnershipConcentration, error) {
+		return s.Store.GetOwnershipConcentration(ctx)
+	})
+}
+
+func (s *InstrumentedStore) GetSupplyStats(ctx context.Context) (*models.SupplyStats, error) {
+	return instrumented(ctx, "GetSupplyStats", func() (*models.SupplyStats, error) {
+		return s.Store.GetSupplyStats(ctx)
+	})
+}
+
+func (s *InstrumentedStore) GetTraitStats(ctx context.Context, dimension string) (*models.TraitBreakdown, error) {
+	return instrumented(ctx, "GetTraitStats", func() (*models.TraitBreakdown, error) {
+		return s.Store.GetTraitStats(ctx, dimension)
+	})
+}
+
+func (s *InstrumentedStore) SearchAllNadmons(ctx context.Context, filters map[string]interface{}, after *SearchCursor, limit int) (*models.NadmonSearchPage, error) {
+	return instrumented(ctx, "SearchAllNadmons", func() (*models.NadmonSearchPage, error) {
+		return s.Store.SearchAllNadmons(ctx, filters, after, limit)
+	})
+}
+
+func (s *InstrumentedStore) GetSearchSuggestions(ctx context.Context, query string, limit int) ([]models.SearchSuggestion, error) {
+	return instrumented(ctx, "GetSearchSuggestions", func() ([]models.SearchSuggestion, error) {
+		return s.Store.GetSearchSuggestions(ctx, query, limit)
+	})
+}
+
+func (s *InstrumentedStore) GetPackOdds(ctx context.Context, from, to time.Time) (*models.PackOddsReport, error) {
+	return instrumented(ctx, "GetPackOdds", func() (*models.PackOddsReport, error) {
+		return s.Store.GetPackOdds(ctx, from, to)
+	})
+}
+
+func (s *InstrumentedStore) GetEventTimeSeries(ctx context.Context, metric, interval string, from, to time.Time) ([]models.TimeSeriesPoint, error) {
+	return instrumented(ctx, "GetEventTimeSeries", func() ([]models.TimeSeriesPoint, error) {
+		return s.Store.GetEventTimeSeries(ctx, metric, interval, from, to)
+	})
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"nadmon-backend/internal/database"
+	"nadmon-backend/internal/ethaddr"
+	"nadmon-backend/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// LeaderboardStore reads the collector leaderboard snapshot
+type LeaderboardStore interface {
+	// GetTopCollectors returns the top collectors of the last snapshot
+	GetTopCollectors(ctx context.Context, limit int) ([]models.PlayerProfile, error)
+	// GetCollectorRank returns a player's rank, or nil when they held no NFTs at the last
+	// snapshot
+	GetCollectorRank(ctx context.Context, address string) (*models.CollectorRank, error)
+}
+
+// LeaderboardRepository snapshots collector standings into the backend-owned schema
+type LeaderboardRepository struct {
+	db *sql.DB
+	// excluded holds lowercased addresses left out of the standings
+	excluded []string
+}
+
+// NewLeaderboardRepository creates a leaderboard repository leaving excluded out of the
+// standings; the schema must have been set up with EnvioDB.SetupAppSchema
+func NewLeaderboardRepository(db *sql.DB, excluded []string) *LeaderboardRepository {
+	normalized := make([]string, 0, len(excluded))
+	for _, address := range excluded {
+		normalized = append(normalized, ethaddr.Normalize(address))
+	}
+	return &LeaderboardRepository{db: db, excluded: normalized}
+}
+
+// leaderboardSnapshotQuery ranks every holder of the default collection like GetTopCollectors,
+// keeping each player's rank and count from the previous snapshot
+const leaderboardSnapshotQuery = `
+	INSERT INTO ` + database.AppSchema + `.collector_ranks (address, nft_count, rank, snapshot_at)
+	WITH current_owners AS (
+		SELECT DISTINCT ON (t."tokenId") t."tokenId", t."to" AS current_owner
+		FROM "NadmonNFT_Transfer" t
+		ORDER BY t."tokenId", t.db_write_timestamp DESC
+	),
+	holdings AS (
+		SELECT LOWER(COALESCE(co.current_owner, m.owner)) AS address, COUNT(*) AS nft_count
+		FROM "NadmonNFT_NadmonMinted" m
+		LEFT JOIN current_owners co ON m."tokenId" = co."tokenId"
+		WHERE COALESCE(co.current_owner, m.owner) != '0x0000000000000000000000000000000000000000'
+			AND LOWER(COALESCE(co.current_owner, m.owner)) != ALL($1::text[])
+		GROUP BY LOWER(COALESCE(co.current_owner, m.owner))
+	)
+	SELECT address, nft_count, RANK() OVER (ORDER BY nft_count DESC), NOW()
+	FROM holdings
+	ON CONFLICT (address) DO UPDATE SET
+		previous_nft_count = collector_ranks.nft_count,
+		previous_rank = collector_ranks.rank,
+		nft_count = EXCLUDED.nft_count,
+		rank = EXCLUDED.rank,
+		snapshot_at = EXCLUDED.snapshot_at
+`
+
+// Refresh takes a new snapshot in one transaction, so readers see either the old or the new
+// standings; players who no longer hold any NFT drop out. It returns the number of ranked
+// players.
+func (r *LeaderboardRepository) Refresh(ctx context.Context) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin leaderboard snapshot: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, leaderboardSnapshotQuery, pq.Array(r.excluded))
+	if err != nil {
+		return 0, fmt.Errorf("failed to snapshot leaderboard: %w", err)
+	}
+	// NOW() is the transaction's start time, so every row ranked above carries it
+	if _, err := tx.ExecContext(ctx, `DELETE FROM `+database.AppSchema+`.collector_ranks WHERE snapshot_at <> NOW()`); err != nil {
+		return 0, fmt.Errorf("failed to clear former collectors: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit leaderboard snapshot: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+func (r *LeaderboardRepository) GetTopCollectors(ctx context.Context, limit int) ([]models.PlayerProfile, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT address, nft_count FROM `+database.AppSchema+`.collector_ranks
+		ORDER BY rank, address
+		LIMIT $1
+	`, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query leaderboard snapshot: %w", err)
+	}
+	defer rows.Close()
+
+	var profiles []models.PlayerProfile
+	for rows.Next() {
+		var profile models.PlayerProfile
+		if err := rows.Scan(&profile.Address, &profile.TotalNFTs); err != nil {
+			return nil, fmt.Errorf("failed to scan leaderboard snapshot: %w", err)
+		}
+		profiles = append(profiles, profile)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read leaderboard snapshot: %w", err)
+	}
+	return profiles, nil
+}
+
+func (r *LeaderboardRepository) GetCollectorRank(ctx context.Context, address string) (*models.CollectorRank, error) {
+	var rank models.CollectorRank
+	var previousRank, previousNFTCount sql.NullInt64
+	err := r.db.QueryRowContext(ctx, `
+		SELECT address, rank, nft_count, previous_rank, previous_nft_count, snapshot_at,
+			(SELECT COUNT(*) FROM `+database.AppSchema+`.collector_ranks)
+		FROM `+database.AppSchema+`.collector_ranks
+		WHERE address = $1
+	`, ethaddr.Normalize(address)).Scan(&rank.Address, &rank.Rank, &rank.NFTCount,
+		&previousRank, &previousNFTCount, &rank.SnapshotAt, &rank.TotalPlayers)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query collector rank: %w", err)
+	}
+
+	if previousRank.Valid {
+		delta := previousRank.Int64 - rank.Rank
+		rank.PreviousRank = &previousRank.Int64
+		rank.PreviousNFTCount = &previousNFTCount.Int64
+		rank.RankDelta = &delta
+	}
+	return &rank, nil
+}
@@ -0,0 +1,122 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"nadmon-backend/internal/database"
+	"nadmon-backend/internal/fixtures"
+	"nadmon-backend/internal/models"
+	"nadmon-backend/internal/testharness"
+)
+
+// concurrentWrites is how many writes race against a per-owner limit
+const concurrentWrites = 20
+
+func newAppDB(t *testing.T) *database.EnvioDB {
+	t.Helper()
+	db := testharness.StartEnvioDB(t)
+	if err := db.SetupAppSchema(); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// race runs write concurrentWrites times at once and counts the writes that succeeded and
+// those refused with limitErr
+func race(t *testing.T, limitErr error, write func(i int) error) (saved, refused int) {
+	t.Helper()
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i := 0; i < concurrentWrites; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := write(i)
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				saved++
+			case errors.Is(err, limitErr):
+				refused++
+			default:
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+	return saved, refused
+}
+
+func TestTradeLimitConcurrent(t *testing.T) {
+	ctx := context.Background()
+	repo := NewTradeRepository(newAppDB(t).DB, time.Hour)
+
+	const maxPending = 3
+	saved, refused := race(t, ErrTradeLimit, func(i int) error {
+		_, err := repo.CreateTrade(ctx, models.TradeOffer{
+			Proposer:          fixtures.Alice,
+			Counterparty:      fixtures.Bob,
+			OfferedTokenIDs:   []int64{1},
+			RequestedTokenIDs: []int64{2},
+		}, maxPending)
+		return err
+	})
+	if saved != maxPending || refused != concurrentWrites-maxPending {
+		t.Errorf("expected %d offers saved and the rest refused, got %d saved and %d refused", maxPending, saved, refused)
+	}
+}
+
+func TestTeamLimitConcurrent(t *testing.T) {
+	ctx := context.Background()
+	repo := NewTeamRepository(newAppDB(t).DB)
+
+	const maxTeams = 3
+	saved, refused := race(t, ErrTeamLimit, func(i int) error {
+		_, err := repo.CreateTeam(ctx, models.Team{Address: fixtures.Alice, Name: "team " + strconv.Itoa(i), TokenIDs: []int64{1}}, maxTeams)
+		return err
+	})
+	if saved != maxTeams || refused != concurrentWrites-maxTeams {
+		t.Errorf("expected %d teams saved and the rest refused, got %d saved and %d refused", maxTeams, saved, refused)
+	}
+}
+
+func TestFavoriteLimitConcurrent(t *testing.T) {
+	ctx := context.Background()
+	repo := NewFavoriteRepository(newAppDB(t).DB)
+
+	const maxFavorites = 3
+	saved, refused := race(t, ErrFavoriteLimit, func(i int) error {
+		_, _, err := repo.AddFavorite(ctx, fixtures.Alice, int64(i+1), maxFavorites)
+		return err
+	})
+	if saved != maxFavorites || refused != concurrentWrites-maxFavorites {
+		t.Errorf("expected %d favorites saved and the rest refused, got %d saved and %d refused", maxFavorites, saved, refused)
+	}
+}
+
+func TestWebhookLimitConcurrent(t *testing.T) {
+	ctx := context.Background()
+	repo := NewWebhookRepository(newAppDB(t).DB)
+
+	const maxPerOwner = 3
+	saved, refused := race(t, ErrWebhookLimit, func(i int) error {
+		_, err := repo.CreateWebhook(ctx, models.Webhook{
+			URL:    "https://example.com/hooks/" + strconv.Itoa(i),
+			Owner:  fixtures.Alice,
+			Events: []string{models.WebhookEventTransfer},
+			Secret: "secret",
+		}, maxPerOwner)
+		return err
+	})
+	if saved != maxPerOwner || refused != concurrentWrites-maxPerOwner {
+		t.Errorf("expected %d webhooks saved and the rest refused, got %d saved and %d refused", maxPerOwner, saved, refused)
+	}
+}
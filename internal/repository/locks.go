@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// lockOwner takes a transaction-scoped advisory lock on owner's rows of table, held until tx
+// ends. Writes checking a per-owner limit take it first, so they run one at a time and each
+// counts the rows the previous one committed.
+func lockOwner(ctx context.Context, tx *sql.Tx, table, owner string) error {
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, table+":"+owner); err != nil {
+		return fmt.Errorf("failed to lock %s of %s: %w", table, owner, err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"nadmon-backend/internal/cache"
+	"nadmon-backend/internal/metrics"
+	"nadmon-backend/internal/models"
+)
+
+// gameStatsKey is the singleflight key of GetGameStats; token keys are their decimal IDs
+const gameStatsKey = "game-stats"
+
+// MicroCachedStore keeps the hottest single-row reads, GetSingleNadmon and GetGameStats, in
+// process memory for a few seconds in front of the database and Redis. Concurrent identical
+// reads share one load, so a burst of pack-open screens fetching the same tokens turns into
+// one query per token. Results are shared between callers and must not be modified.
+type MicroCachedStore struct {
+	Store
+	nadmons   *cache.LRU[int64, *models.Nadmon]
+	gameStats *cache.LRU[struct{}, *models.GameStats]
+	group     singleflight.Group
+}
+
+// NewMicroCachedStore wraps store with an in-process cache of up to size Nadmons, serving
+// each result for ttl
+func NewMicroCachedStore(store Store, size int, ttl time.Duration) *MicroCachedStore {
+	return &MicroCachedStore{
+		Store:     store,
+		nadmons:   cache.NewLRU[int64, *models.Nadmon](size, ttl),
+		gameStats: cache.NewLRU[struct{}, *models.GameStats](1, ttl),
+	}
+}
+
+// Bypass returns the store below every cache layer
+func (s *MicroCachedStore) Bypass() Store {
+	if bypasser, ok := s.Store.(Bypasser); ok {
+		return bypasser.Bypass()
+	}
+	return s.Store
+}
+
+// Invalidate drops cached results affected by changes to the given tokens; any change can
+// move the game stats
+func (s *MicroCachedStore) Invalidate(tokenIDs []int64) {
+	for _, id := range tokenIDs {
+		s.nadmons.Remove(id)
+	}
+	s.gameStats.Purge()
+}
+
+func (s *MicroCachedStore) GetSingleNadmon(ctx context.Context, tokenID int64) (*models.Nadmon, error) {
+	if nadmon, found := s.nadmons.Get(tokenID); found {
+		metrics.ObserveMicroCache(metrics.CacheHit)
+		return nadmon, nil
+	}
+
+	result, err := s.load(ctx, strconv.FormatInt(tokenID, 10), func(ctx context.Context) (interface{}, error) {
+		nadmon, err := s.Store.GetSingleNadmon(ctx, tokenID)
+		// Unknown tokens aren't cached: they may be minted and indexed any moment
+		if err == nil && nadmon != nil {
+			s.nadmons.Add(tokenID, nadmon)
+		}
+		return nadmon, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*models.Nadmon), nil
+}
+
+func (s *MicroCachedStore) GetGameStats(ctx context.Context) (*models.GameStats, error) {
+	if stats, found := s.gameStats.Get(struct{}{}); found {
+		metrics.ObserveMicroCache(metrics.CacheHit)
+		return stats, nil
+	}
+
+	result, err := s.load(ctx, gameStatsKey, func(ctx context.Context) (interface{}, error) {
+		stats, err := s.Store.GetGameStats(ctx)
+		if err == nil {
+			s.gameStats.Add(struct{}{}, stats)
+		}
+		return stats, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.(*models.GameStats), nil
+}
+
+// load runs fn once for all concurrent callers with the same key, counting whether the
+// result was shared
+func (s *MicroCachedStore) load(ctx context.Context, key string, fn func(context.Context) (interface{}, error)) (interface{}, error) {
+	result, shared, err := loadShared(ctx, &s.group, key, fn)
+	if err != nil && ctx.Err() != nil {
+		return nil, err
+	}
+	if shared {
+		metrics.ObserveMicroCache(metrics.CacheShared)
+	} else {
+		metrics.ObserveMicroCache(metrics.CacheMiss)
+	}
+	return result, err
+}
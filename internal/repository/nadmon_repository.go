@@ -1,464 +1,1055 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
-	"encoding/json"
 	"fmt"
-	"strconv"
+	"math"
 	"strings"
+	"time"
 
 	"nadmon-backend/internal/database"
+	"nadmon-backend/internal/database/envio"
+	"nadmon-backend/internal/ethaddr"
 	"nadmon-backend/internal/models"
-
-	"github.com/lib/pq"
 )
 
 // NadmonRepository handles database operations for Nadmon data
 type NadmonRepository struct {
-	db *database.EnvioDB
+	db      *database.EnvioDB
+	conn    envio.DBTX // all queries, generated and dynamic, go through conn
+	queries *envio.Queries
+
+	// excluded holds lowercased addresses (treasury, deployer, escrow) left out of public aggregates
+	excluded []string
+
+	// prefix is the Envio table prefix of the collection this repository reads
+	prefix string
 }
 
 // NewNadmonRepository creates a new repository instance
 func NewNadmonRepository(db *database.EnvioDB) *NadmonRepository {
-	return &NadmonRepository{db: db}
+	return NewNadmonRepositoryWithConn(db, db.DB)
+}
+
+// NewNadmonRepositoryWithConn creates a repository that sends its queries through conn,
+// allowing the connection to be wrapped (e.g. for fault injection)
+func NewNadmonRepositoryWithConn(db *database.EnvioDB, conn envio.DBTX) *NadmonRepository {
+	return &NadmonRepository{db: db, conn: conn, queries: envio.New(conn), excluded: []string{}, prefix: DefaultTablePrefix}
+}
+
+// currentState reports whether reads can use the current-state table, which only covers the
+// default collection
+func (r *NadmonRepository) currentState() bool {
+	return r.prefix == DefaultTablePrefix && r.db.CurrentState
+}
+
+// timescale reports whether time series can use the continuous aggregates, which only cover
+// the default collection
+func (r *NadmonRepository) timescale() bool {
+	return r.prefix == DefaultTablePrefix && r.db.Timescale
+}
+
+// SetExcludedAddresses leaves the given addresses out of leaderboards, collector counts and
+// concentration metrics
+func (r *NadmonRepository) SetExcludedAddresses(addresses []string) {
+	r.excluded = make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		r.excluded = append(r.excluded, ethaddr.Normalize(address))
+	}
+}
+
+// toNadmon converts a generated current-state row into the API model.
+// All current-state queries share the same columns, so their row types convert to this one.
+func toNadmon(row envio.GetPlayerNadmonsRow) models.Nadmon {
+	return models.Nadmon{
+		TokenID:     row.TokenID,
+		Owner:       row.Owner,
+		PackID:      row.PackID,
+		NadmonType:  row.NadmonType,
+		Element:     row.Element,
+		Rarity:      row.Rarity,
+		HP:          row.Hp,
+		Attack:      row.Attack,
+		Defense:     row.Defense,
+		Crit:        row.Crit,
+		Fusion:      row.Fusion,
+		Evo:         row.Evo,
+		CreatedAt:   row.CreatedAt.Time,
+		LastUpdated: row.LastUpdated.Time,
+	}
+}
+
+// toPack converts a generated pack row into the API model
+func toPack(row envio.GetPlayerPacksRow) models.Pack {
+	return models.Pack{
+		PackID:      row.PackID,
+		Player:      row.Player,
+		TokenIDs:    row.TokenIds,
+		PaymentType: row.PaymentType,
+		PurchasedAt: row.PurchasedAt.Time,
+	}
 }
 
 // GetPlayerNadmons retrieves all NFTs owned by a player with their current stats
-func (r *NadmonRepository) GetPlayerNadmons(address string) ([]models.Nadmon, error) {
-	query := `
-		WITH current_owners AS (
-			-- Get the most recent Transfer event for each token to determine current owner
-			SELECT DISTINCT ON (t."tokenId") 
-				t."tokenId", 
-				t."to" as current_owner
-			FROM "NadmonNFT_Transfer" t
-			ORDER BY t."tokenId", t.db_write_timestamp DESC
-		),
-		latest_stats AS (
-			-- Get the most recent stats for each token
-			SELECT DISTINCT ON (s."tokenId")
-				s."tokenId", s."newHp", s."newAttack", s."newDefense", 
-				s."newCrit", s."newFusion", s."newEvo", s.db_write_timestamp
-			FROM "NadmonNFT_StatsChanged" s
-			ORDER BY s."tokenId", s.sequence DESC
-		)
-		SELECT 
-			m."tokenId", 
-			COALESCE(co.current_owner, m.owner) as owner, 
-			m."packId", m."nadmonType", 
-			m.element, m.rarity,
-			COALESCE(ls."newHp", m.hp) as hp,
-			COALESCE(ls."newAttack", m.attack) as attack,
-			COALESCE(ls."newDefense", m.defense) as defense,
-			COALESCE(ls."newCrit", m.crit) as crit,
-			COALESCE(ls."newFusion", m.fusion) as fusion,
-			COALESCE(ls."newEvo", m.evo) as evo,
-			m.db_write_timestamp as created_at,
-			COALESCE(ls.db_write_timestamp, m.db_write_timestamp) as last_updated
-		FROM "NadmonNFT_NadmonMinted" m
-		LEFT JOIN current_owners co ON m."tokenId" = co."tokenId"
-		LEFT JOIN latest_stats ls ON m."tokenId" = ls."tokenId"
-		WHERE COALESCE(co.current_owner, m.owner) = $1 
-			AND COALESCE(co.current_owner, m.owner) != '0x0000000000000000000000000000000000000000'
-		ORDER BY m."tokenId"
-	`
+func (r *NadmonRepository) GetPlayerNadmons(ctx context.Context, address string) ([]models.Nadmon, error) {
+	address = ethaddr.Normalize(address)
+
+	var nadmons []models.Nadmon
+	if r.currentState() {
+		rows, err := r.queries.GetPlayerNadmonsFromState(ctx, address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query player nadmons: %w", err)
+		}
+		for _, row := range rows {
+			nadmons = append(nadmons, toNadmon(envio.GetPlayerNadmonsRow(row)))
+		}
+		return nadmons, nil
+	}
 
-	rows, err := r.db.DB.Query(query, address)
+	rows, err := r.queries.GetPlayerNadmons(ctx, address)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query player nadmons: %w", err)
 	}
-	defer rows.Close()
+	for _, row := range rows {
+		nadmons = append(nadmons, toNadmon(row))
+	}
 
-	var nadmons []models.Nadmon
-	for rows.Next() {
-		var n models.Nadmon
-		err := rows.Scan(
-			&n.TokenID, &n.Owner, &n.PackID, &n.NadmonType,
-			&n.Element, &n.Rarity, &n.HP, &n.Attack,
-			&n.Defense, &n.Crit, &n.Fusion, &n.Evo,
-			&n.CreatedAt, &n.LastUpdated,
-		)
+	return nadmons, nil
+}
+
+// GetPlayerProfile retrieves complete player profile with aggregated stats, in one statement
+func (r *NadmonRepository) GetPlayerProfile(ctx context.Context, address string) (*models.PlayerProfile, error) {
+	address = ethaddr.Normalize(address)
+
+	var rows []envio.GetPlayerProfileRow
+	if r.currentState() {
+		stateRows, err := r.queries.GetPlayerProfileFromState(ctx, address)
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan nadmon: %w", err)
+			return nil, fmt.Errorf("failed to query player profile: %w", err)
+		}
+		for _, row := range stateRows {
+			rows = append(rows, envio.GetPlayerProfileRow(row))
+		}
+	} else {
+		var err error
+		if rows, err = r.queries.GetPlayerProfile(ctx, address); err != nil {
+			return nil, fmt.Errorf("failed to query player profile: %w", err)
 		}
-		nadmons = append(nadmons, n)
 	}
 
-	return nadmons, nil
+	profile := &models.PlayerProfile{Address: address}
+	for _, row := range rows {
+		// Every row carries the summary; a player without Nadmons gets a single row without one
+		profile.PacksBought = int(row.PackCount)
+		if row.LastActive.Valid {
+			profile.LastActive = row.LastActive.Time
+		}
+		if !row.TokenID.Valid {
+			continue
+		}
+		profile.Nadmons = append(profile.Nadmons, toNadmon(envio.GetPlayerNadmonsRow{
+			TokenID:     row.TokenID.Int64,
+			Owner:       address,
+			PackID:      row.PackID.Int64,
+			NadmonType:  row.NadmonType.String,
+			Element:     row.Element.String,
+			Rarity:      row.Rarity.String,
+			Hp:          row.Hp.Int64,
+			Attack:      row.Attack.Int64,
+			Defense:     row.Defense.Int64,
+			Crit:        row.Crit.Int64,
+			Fusion:      row.Fusion.Int64,
+			Evo:         row.Evo.Int64,
+			CreatedAt:   row.CreatedAt,
+			LastUpdated: row.LastUpdated,
+		}))
+	}
+	profile.TotalNFTs = len(profile.Nadmons)
+
+	return profile, nil
+}
+
+// GetPlayerSummary retrieves a player profile with their Nadmons counted by rarity and element
+// instead of listed, in one statement
+func (r *NadmonRepository) GetPlayerSummary(ctx context.Context, address string) (*models.PlayerSummary, error) {
+	address = ethaddr.Normalize(address)
+
+	var rows []envio.GetPlayerSummaryRow
+	if r.currentState() {
+		stateRows, err := r.queries.GetPlayerSummaryFromState(ctx, address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query player summary: %w", err)
+		}
+		for _, row := range stateRows {
+			rows = append(rows, envio.GetPlayerSummaryRow(row))
+		}
+	} else {
+		var err error
+		if rows, err = r.queries.GetPlayerSummary(ctx, address); err != nil {
+			return nil, fmt.Errorf("failed to query player summary: %w", err)
+		}
+	}
+
+	summary := &models.PlayerSummary{
+		Address:       address,
+		RarityCounts:  map[string]int{},
+		ElementCounts: map[string]int{},
+	}
+	for _, row := range rows {
+		// Rows are per rarity and element; a player without Nadmons gets a single row without one
+		summary.PacksBought = int(row.PackCount)
+		if row.LastActive.Valid {
+			summary.LastActive = row.LastActive.Time
+		}
+		if row.NftCount == 0 {
+			continue
+		}
+		summary.TotalNFTs += int(row.NftCount)
+		summary.EvolvedNFTs += int(row.EvolvedCount)
+		summary.RarityCounts[row.Rarity.String] += int(row.NftCount)
+		summary.ElementCounts[row.Element.String] += int(row.NftCount)
+	}
+
+	return summary, nil
 }
 
-// GetPlayerProfile retrieves complete player profile with aggregated stats
-func (r *NadmonRepository) GetPlayerProfile(address string) (*models.PlayerProfile, error) {
-	// Get player's NFTs
-	nadmons, err := r.GetPlayerNadmons(address)
+// GetPlayerDex compares the species (type/element/rarity combinations) a player owns with
+// every species minted so far
+func (r *NadmonRepository) GetPlayerDex(ctx context.Context, address string) (*models.Dex, error) {
+	address = ethaddr.Normalize(address)
+
+	nadmons, err := r.GetPlayerNadmons(ctx, address)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get pack count
-	var packCount int
-	err = r.db.DB.QueryRow(`SELECT COUNT(*) FROM "NadmonNFT_PackMinted" WHERE player = $1`, address).Scan(&packCount)
+	rows, err := r.queries.GetDexSpecies(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to count packs: %w", err)
+		return nil, fmt.Errorf("failed to query dex species: %w", err)
 	}
 
-	// Get last activity
-	var lastActive sql.NullTime
-	err = r.db.DB.QueryRow(`
-		SELECT MAX(db_write_timestamp) FROM (
-			SELECT db_write_timestamp FROM "NadmonNFT_PackMinted" WHERE player = $1
-			UNION ALL
-			SELECT s.db_write_timestamp FROM "NadmonNFT_StatsChanged" s
-			JOIN "NadmonNFT_NadmonMinted" m ON s."tokenId" = m."tokenId"
-			LEFT JOIN (
-				SELECT DISTINCT ON (t."tokenId") 
-					t."tokenId", t."to" as current_owner
-				FROM "NadmonNFT_Transfer" t
-				ORDER BY t."tokenId", t.db_write_timestamp DESC
-			) co ON m."tokenId" = co."tokenId"
-			WHERE COALESCE(co.current_owner, m.owner) = $1
-				AND COALESCE(co.current_owner, m.owner) != '0x0000000000000000000000000000000000000000'
-		) combined
-	`, address).Scan(&lastActive)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get last activity: %w", err)
+	owned := make(map[models.DexSpecies]int)
+	for _, nadmon := range nadmons {
+		owned[models.DexSpecies{Type: nadmon.NadmonType, Element: nadmon.Element, Rarity: nadmon.Rarity}]++
 	}
 
-	profile := &models.PlayerProfile{
-		Address:     address,
-		TotalNFTs:   len(nadmons),
-		PacksBought: packCount,
-		Nadmons:     nadmons,
+	dex := &models.Dex{
+		Address:      address,
+		TotalSpecies: len(rows),
+		Owned:        []models.DexSpecies{},
+		Missing:      []models.DexSpecies{},
+	}
+	for _, row := range rows {
+		species := models.DexSpecies{Type: row.NadmonType, Element: row.Element, Rarity: row.Rarity}
+		if count := owned[species]; count > 0 {
+			species.Owned = count
+			dex.Owned = append(dex.Owned, species)
+		} else {
+			dex.Missing = append(dex.Missing, species)
+		}
 	}
 
-	if lastActive.Valid {
-		profile.LastActive = lastActive.Time
+	dex.OwnedSpecies = len(dex.Owned)
+	if dex.TotalSpecies > 0 {
+		dex.Completion = math.Round(float64(dex.OwnedSpecies)/float64(dex.TotalSpecies)*10000) / 100
 	}
 
-	return profile, nil
+	return dex, nil
 }
 
 // GetPlayerPacks retrieves all pack purchases by a player
-func (r *NadmonRepository) GetPlayerPacks(address string) ([]models.Pack, error) {
-	query := `
-		SELECT "packId", player, "tokenIds", "paymentType", db_write_timestamp
-		FROM "NadmonNFT_PackMinted"
-		WHERE player = $1
-		ORDER BY sequence DESC
-	`
-
-	rows, err := r.db.DB.Query(query, address)
+func (r *NadmonRepository) GetPlayerPacks(ctx context.Context, address string) ([]models.Pack, error) {
+	address = ethaddr.Normalize(address)
+	rows, err := r.queries.GetPlayerPacks(ctx, address)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query player packs: %w", err)
 	}
-	defer rows.Close()
 
 	var packs []models.Pack
-	for rows.Next() {
-		var p models.Pack
-		var tokenIDs pq.Int64Array
-		err := rows.Scan(&p.PackID, &p.Player, &tokenIDs, &p.PaymentType, &p.PurchasedAt)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan pack: %w", err)
-		}
-		p.TokenIDs = []int64(tokenIDs)
-		packs = append(packs, p)
+	for _, row := range rows {
+		packs = append(packs, toPack(row))
 	}
 
 	return packs, nil
 }
 
-// GetNadmonHistory retrieves evolution/fusion history for a specific NFT
-func (r *NadmonRepository) GetNadmonHistory(tokenID int64) ([]models.StatsChange, error) {
-	query := `
-		SELECT "tokenId", "changeType", sequence,
-			"newHp", "newAttack", "newDefense", "newCrit", "newFusion", "newEvo",
-			"oldHp", "oldAttack", "oldDefense", "oldCrit", "oldFusion", "oldEvo",
-			db_write_timestamp
-		FROM "NadmonNFT_StatsChanged"
-		WHERE "tokenId" = $1
-		ORDER BY sequence ASC
-	`
+// GetPlayerPackSummary aggregates a player's pack purchases with their most recent packs in one query
+func (r *NadmonRepository) GetPlayerPackSummary(ctx context.Context, address string, recent int) (*models.PackSummary, error) {
+	address = ethaddr.Normalize(address)
+	rows, err := r.queries.GetPlayerPackSummary(ctx, envio.GetPlayerPackSummaryParams{
+		Player:    address,
+		MaxRecent: int32(recent),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query player pack summary: %w", err)
+	}
+
+	summary := models.NewPackSummary(address)
+	if len(rows) == 0 {
+		return summary, nil
+	}
 
-	rows, err := r.db.DB.Query(query, tokenID)
+	first := rows[0]
+	summary.TotalPacks = int(first.TotalPacks)
+	for i, paymentType := range first.PaymentTypes {
+		if i < len(first.PaymentTypeCounts) {
+			summary.Count(paymentType, int(first.PaymentTypeCounts[i]))
+		}
+	}
+	if first.FirstPurchaseAt.Valid {
+		summary.FirstPurchaseAt = &first.FirstPurchaseAt.Time
+	}
+	if first.LastPurchaseAt.Valid {
+		summary.LastPurchaseAt = &first.LastPurchaseAt.Time
+	}
+
+	for _, row := range rows {
+		if !row.PackID.Valid {
+			continue
+		}
+		summary.RecentPacks = append(summary.RecentPacks, toPack(envio.GetPlayerPacksRow{
+			PackID:      row.PackID.Int64,
+			Player:      address,
+			TokenIds:    row.TokenIds,
+			PaymentType: row.PaymentType.String,
+			PurchasedAt: row.PurchasedAt,
+		}))
+	}
+
+	return summary, nil
+}
+
+// GetNadmonHistory retrieves evolution/fusion history for a specific NFT
+func (r *NadmonRepository) GetNadmonHistory(ctx context.Context, tokenID int64) ([]models.StatsChange, error) {
+	rows, err := r.queries.GetNadmonHistory(ctx, tokenID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query nadmon history: %w", err)
 	}
-	defer rows.Close()
 
 	var changes []models.StatsChange
-	for rows.Next() {
-		var change models.StatsChange
-		err := rows.Scan(
-			&change.TokenID, &change.ChangeType, &change.Sequence,
-			&change.NewStats.HP, &change.NewStats.Attack, &change.NewStats.Defense,
-			&change.NewStats.Crit, &change.NewStats.Fusion, &change.NewStats.Evo,
-			&change.OldStats.HP, &change.OldStats.Attack, &change.OldStats.Defense,
-			&change.OldStats.Crit, &change.OldStats.Fusion, &change.OldStats.Evo,
-			&change.ChangedAt,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan stats change: %w", err)
-		}
-		changes = append(changes, change)
+	for _, row := range rows {
+		changes = append(changes, models.StatsChange{
+			TokenID:    row.TokenID,
+			ChangeType: row.ChangeType,
+			Sequence:   row.Sequence,
+			OldStats: models.StatSet{
+				HP: row.OldHp, Attack: row.OldAttack, Defense: row.OldDefense,
+				Crit: row.OldCrit, Fusion: row.OldFusion, Evo: row.OldEvo,
+			},
+			NewStats: models.StatSet{
+				HP: row.NewHp, Attack: row.NewAttack, Defense: row.NewDefense,
+				Crit: row.NewCrit, Fusion: row.NewFusion, Evo: row.NewEvo,
+			},
+			ChangedAt: row.ChangedAt.Time,
+		})
 	}
 
 	return changes, nil
 }
 
-// GetNadmonsByIDs retrieves multiple NFTs by their token IDs
-func (r *NadmonRepository) GetNadmonsByIDs(tokenIDs []int64) ([]models.Nadmon, error) {
+// GetFusions retrieves the fusions of the given NFTs with the Nadmons burned to feed them,
+// ordered by fusion time
+func (r *NadmonRepository) GetFusions(ctx context.Context, tokenIDs []int64) ([]models.Fusion, error) {
+	if len(tokenIDs) == 0 {
+		return []models.Fusion{}, nil
+	}
+
+	rows, err := r.queries.GetFusionBurns(ctx, envio.GetFusionBurnsParams{
+		TokenIds:      tokenIDs,
+		WindowSeconds: models.FusionBurnWindow.Seconds(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query fusions: %w", err)
+	}
+
+	// One row per fusion and candidate burn, grouped by fusion
+	type fusionKey struct{ tokenID, sequence int64 }
+	var fusions []models.Fusion
+	index := make(map[fusionKey]int)
+	for _, row := range rows {
+		key := fusionKey{row.TokenID, row.Sequence}
+		i, ok := index[key]
+		if !ok {
+			i = len(fusions)
+			index[key] = i
+			fusions = append(fusions, models.Fusion{
+				TokenID:      row.TokenID,
+				Sequence:     row.Sequence,
+				FusionBefore: row.FusionBefore,
+				FusionAfter:  row.FusionAfter,
+				FusedAt:      row.FusedAt.Time,
+				Consumed:     []models.ConsumedNadmon{},
+			})
+		}
+		if row.ConsumedTokenID == 0 {
+			continue
+		}
+		fusions[i].Consumed = append(fusions[i].Consumed, models.ConsumedNadmon{
+			TokenID:    row.ConsumedTokenID,
+			PackID:     row.ConsumedPackID,
+			NadmonType: row.ConsumedNadmonType,
+			Element:    row.ConsumedElement,
+			Rarity:     row.ConsumedRarity,
+			Stats: models.StatSet{
+				HP: row.ConsumedHp, Attack: row.ConsumedAttack, Defense: row.ConsumedDefense,
+				Crit: row.ConsumedCrit, Fusion: row.ConsumedFusion, Evo: row.ConsumedEvo,
+			},
+			BurnedAt: row.BurnedAt.Time,
+		})
+	}
+
+	return models.MatchFusionBurns(fusions), nil
+}
+
+// nadmonIDBatchSize caps the token IDs sent in one batch query; larger lookups are split
+// into several queries, so a 500 ID lookup doesn't plan one huge ANY($1) scan
+const nadmonIDBatchSize = 100
+
+// GetNadmonsByIDs retrieves multiple NFTs by their token IDs, in token ID order. Duplicate
+// IDs are looked up once, and lookups above nadmonIDBatchSize IDs are batched.
+func (r *NadmonRepository) GetNadmonsByIDs(ctx context.Context, tokenIDs []int64) ([]models.Nadmon, error) {
 	if len(tokenIDs) == 0 {
 		return []models.Nadmon{}, nil
 	}
 
-	// Build the query with placeholders for token IDs
-	placeholders := make([]string, len(tokenIDs))
-	args := make([]interface{}, len(tokenIDs))
-	for i, id := range tokenIDs {
-		placeholders[i] = fmt.Sprintf("$%d", i+1)
-		args[i] = id
+	ids := distinctSorted(tokenIDs)
+	var nadmons []models.Nadmon
+	for start := 0; start < len(ids); start += nadmonIDBatchSize {
+		batch := ids[start:min(start+nadmonIDBatchSize, len(ids))]
+		found, err := r.getNadmonsByIDs(ctx, batch)
+		if err != nil {
+			return nil, err
+		}
+		nadmons = append(nadmons, found...)
 	}
 
-	query := fmt.Sprintf(`
-		WITH current_owners AS (
-			-- Get the most recent Transfer event for each token to determine current owner
-			SELECT DISTINCT ON (t."tokenId") 
-				t."tokenId", 
-				t."to" as current_owner
-			FROM "NadmonNFT_Transfer" t
-			ORDER BY t."tokenId", t.db_write_timestamp DESC
-		),
-		latest_stats AS (
-			-- Get the most recent stats for each token
-			SELECT DISTINCT ON (s."tokenId")
-				s."tokenId", s."newHp", s."newAttack", s."newDefense", 
-				s."newCrit", s."newFusion", s."newEvo", s.db_write_timestamp
-			FROM "NadmonNFT_StatsChanged" s
-			ORDER BY s."tokenId", s.sequence DESC
-		)
-		SELECT DISTINCT ON (m."tokenId")
-			m."tokenId", 
-			COALESCE(co.current_owner, m.owner) as owner, 
-			m."packId", m."nadmonType", 
-			m.element, m.rarity,
-			COALESCE(ls."newHp", m.hp) as hp,
-			COALESCE(ls."newAttack", m.attack) as attack,
-			COALESCE(ls."newDefense", m.defense) as defense,
-			COALESCE(ls."newCrit", m.crit) as crit,
-			COALESCE(ls."newFusion", m.fusion) as fusion,
-			COALESCE(ls."newEvo", m.evo) as evo,
-			m.db_write_timestamp as created_at,
-			COALESCE(ls.db_write_timestamp, m.db_write_timestamp) as last_updated
-		FROM "NadmonNFT_NadmonMinted" m
-		LEFT JOIN current_owners co ON m."tokenId" = co."tokenId"
-		LEFT JOIN latest_stats ls ON m."tokenId" = ls."tokenId"
-		WHERE m."tokenId" IN (%s)
-			AND COALESCE(co.current_owner, m.owner) != '0x0000000000000000000000000000000000000000'
-		ORDER BY m."tokenId"
-	`, strings.Join(placeholders, ","))
+	return nadmons, nil
+}
 
-	rows, err := r.db.DB.Query(query, args...)
+// getNadmonsByIDs runs one batch query
+func (r *NadmonRepository) getNadmonsByIDs(ctx context.Context, tokenIDs []int64) ([]models.Nadmon, error) {
+	var nadmons []models.Nadmon
+	if r.currentState() {
+		rows, err := r.queries.GetNadmonsByIDsFromState(ctx, tokenIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query nadmons by IDs: %w", err)
+		}
+		for _, row := range rows {
+			nadmons = append(nadmons, toNadmon(envio.GetPlayerNadmonsRow(row)))
+		}
+		return nadmons, nil
+	}
+
+	rows, err := r.queries.GetNadmonsByIDs(ctx, tokenIDs)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query nadmons by IDs: %w", err)
 	}
-	defer rows.Close()
+	for _, row := range rows {
+		nadmons = append(nadmons, toNadmon(envio.GetPlayerNadmonsRow(row)))
+	}
 
-	var nadmons []models.Nadmon
-	for rows.Next() {
-		var nadmon models.Nadmon
-		err := rows.Scan(
-			&nadmon.TokenID, &nadmon.Owner, &nadmon.PackID, &nadmon.NadmonType,
-			&nadmon.Element, &nadmon.Rarity,
-			&nadmon.HP, &nadmon.Attack, &nadmon.Defense, &nadmon.Crit, &nadmon.Fusion, &nadmon.Evo,
-			&nadmon.CreatedAt, &nadmon.LastUpdated,
-		)
+	return nadmons, nil
+}
+
+// GetNadmonSnapshot retrieves up to limit circulating NFTs with token IDs above afterTokenID,
+// ordered by token ID, for paging through the whole collection
+func (r *NadmonRepository) GetNadmonSnapshot(ctx context.Context, afterTokenID int64, limit int) ([]models.Nadmon, error) {
+	nadmons := make([]models.Nadmon, 0, limit)
+	if r.currentState() {
+		rows, err := r.queries.GetNadmonSnapshotFromState(ctx, envio.GetNadmonSnapshotFromStateParams{
+			AfterTokenID: afterTokenID,
+			MaxResults:   int32(limit),
+		})
 		if err != nil {
-			return nil, fmt.Errorf("failed to scan nadmon: %w", err)
+			return nil, fmt.Errorf("failed to query nadmon snapshot: %w", err)
+		}
+		for _, row := range rows {
+			nadmons = append(nadmons, toNadmon(envio.GetPlayerNadmonsRow(row)))
 		}
-		nadmons = append(nadmons, nadmon)
+		return nadmons, nil
+	}
+
+	rows, err := r.queries.GetNadmonSnapshot(ctx, envio.GetNadmonSnapshotParams{
+		AfterTokenID: afterTokenID,
+		MaxResults:   int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query nadmon snapshot: %w", err)
+	}
+	for _, row := range rows {
+		nadmons = append(nadmons, toNadmon(envio.GetPlayerNadmonsRow(row)))
 	}
 
 	return nadmons, nil
 }
 
+// GetHolderSnapshot replays the transfers indexed up to asOf into every holder's balance at
+// that time. It always reads the event tables: the current-state table has no history.
+func (r *NadmonRepository) GetHolderSnapshot(ctx context.Context, asOf time.Time) (*models.HolderSnapshot, error) {
+	rows, err := r.queries.GetHolderSnapshot(ctx, asOf.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to query holder snapshot: %w", err)
+	}
+
+	snapshot := &models.HolderSnapshot{AsOf: asOf.UTC(), Holders: make([]models.HolderBalance, 0, len(rows))}
+	for _, row := range rows {
+		snapshot.Holders = append(snapshot.Holders, models.HolderBalance{
+			Address:  row.Owner,
+			Balance:  int(row.Balance),
+			TokenIDs: row.TokenIds,
+		})
+		snapshot.TotalSupply += int(row.Balance)
+	}
+	snapshot.TotalHolders = len(snapshot.Holders)
+
+	return snapshot, nil
+}
+
+// GetInventoryChanges returns what changed in a player's inventory after sinceSequence. It
+// always reads the event tables: the current-state table may not have caught up with the
+// sequence it returns.
+func (r *NadmonRepository) GetInventoryChanges(ctx context.Context, address string, sinceSequence int64) (*models.InventoryChanges, error) {
+	// The sequence is read before the changes, so rows written in between are reported again
+	// by the next call rather than missed
+	newest, err := r.queries.GetNewestEventWrite(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query newest event: %w", err)
+	}
+
+	owner := ethaddr.Normalize(address)
+	rows, err := r.queries.GetInventoryChanges(ctx, envio.GetInventoryChangesParams{
+		Since: models.SyncSequenceTime(sinceSequence),
+		Owner: owner,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query inventory changes: %w", err)
+	}
+
+	changes := &models.InventoryChanges{
+		Address:       owner,
+		SinceSequence: sinceSequence,
+		Sequence:      sinceSequence,
+		Changed:       []models.Nadmon{},
+		Removed:       []int64{},
+	}
+	if newest.Valid && models.SyncSequence(newest.Time) > sinceSequence {
+		changes.Sequence = models.SyncSequence(newest.Time)
+	}
+	for _, row := range rows {
+		if row.Removed {
+			changes.Removed = append(changes.Removed, row.TokenID)
+			continue
+		}
+		changes.Changed = append(changes.Changed, toNadmon(envio.GetPlayerNadmonsRow{
+			TokenID:     row.TokenID,
+			Owner:       row.Owner,
+			PackID:      row.PackID,
+			NadmonType:  row.NadmonType,
+			Element:     row.Element,
+			Rarity:      row.Rarity,
+			Hp:          row.Hp,
+			Attack:      row.Attack,
+			Defense:     row.Defense,
+			Crit:        row.Crit,
+			Fusion:      row.Fusion,
+			Evo:         row.Evo,
+			CreatedAt:   row.CreatedAt,
+			LastUpdated: row.LastUpdated,
+		}))
+	}
+
+	return changes, nil
+}
+
 // GetSingleNadmon retrieves a single NFT by token ID with current stats
-func (r *NadmonRepository) GetSingleNadmon(tokenID int64) (*models.Nadmon, error) {
-	query := `
-		WITH current_owners AS (
-			-- Get the most recent Transfer event for each token to determine current owner
-			SELECT DISTINCT ON (t."tokenId") 
-				t."tokenId", 
-				t."to" as current_owner
-			FROM "NadmonNFT_Transfer" t
-			ORDER BY t."tokenId", t.db_write_timestamp DESC
-		),
-		latest_stats AS (
-			-- Get the most recent stats for each token
-			SELECT DISTINCT ON (s."tokenId")
-				s."tokenId", s."newHp", s."newAttack", s."newDefense", 
-				s."newCrit", s."newFusion", s."newEvo", s.db_write_timestamp
-			FROM "NadmonNFT_StatsChanged" s
-			ORDER BY s."tokenId", s.sequence DESC
-		)
-		SELECT DISTINCT ON (m."tokenId")
-			m."tokenId", 
-			COALESCE(co.current_owner, m.owner) as owner, 
-			m."packId", m."nadmonType", 
-			m.element, m.rarity,
-			COALESCE(ls."newHp", m.hp) as hp,
-			COALESCE(ls."newAttack", m.attack) as attack,
-			COALESCE(ls."newDefense", m.defense) as defense,
-			COALESCE(ls."newCrit", m.crit) as crit,
-			COALESCE(ls."newFusion", m.fusion) as fusion,
-			COALESCE(ls."newEvo", m.evo) as evo,
-			m.db_write_timestamp as created_at,
-			COALESCE(ls.db_write_timestamp, m.db_write_timestamp) as last_updated
-		FROM "NadmonNFT_NadmonMinted" m
-		LEFT JOIN current_owners co ON m."tokenId" = co."tokenId"
-		LEFT JOIN latest_stats ls ON m."tokenId" = ls."tokenId"
-		WHERE m."tokenId" = $1
-			AND COALESCE(co.current_owner, m.owner) != '0x0000000000000000000000000000000000000000'
-		ORDER BY m."tokenId"
-	`
+func (r *NadmonRepository) GetSingleNadmon(ctx context.Context, tokenID int64) (*models.Nadmon, error) {
+
+	var row envio.GetPlayerNadmonsRow
+	var err error
+	if r.currentState() {
+		var stateRow envio.GetSingleNadmonFromStateRow
+		stateRow, err = r.queries.GetSingleNadmonFromState(ctx, tokenID)
+		row = envio.GetPlayerNadmonsRow(stateRow)
+	} else {
+		var cteRow envio.GetSingleNadmonRow
+		cteRow, err = r.queries.GetSingleNadmon(ctx, tokenID)
+		row = envio.GetPlayerNadmonsRow(cteRow)
+	}
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query single nadmon: %w", err)
+	}
+
+	nadmon := toNadmon(row)
+	return &nadmon, nil
+}
+
+// GetNadmonStatuses reports whether each token is active, burned or unknown. Every requested
+// ID is present in the result.
+func (r *NadmonRepository) GetNadmonStatuses(ctx context.Context, tokenIDs []int64) (map[int64]models.NadmonStatus, error) {
+	statuses := make(map[int64]models.NadmonStatus, len(tokenIDs))
+	for _, id := range tokenIDs {
+		statuses[id] = models.NadmonStatus{TokenID: id, Status: models.StatusUnknown}
+	}
+	if len(tokenIDs) == 0 {
+		return statuses, nil
+	}
+
+	var rows []envio.GetNadmonStatusesRow
+	if r.currentState() {
+		stateRows, err := r.queries.GetNadmonStatusesFromState(ctx, tokenIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query nadmon statuses: %w", err)
+		}
+		for _, row := range stateRows {
+			rows = append(rows, envio.GetNadmonStatusesRow(row))
+		}
+	} else {
+		var err error
+		rows, err = r.queries.GetNadmonStatuses(ctx, tokenIDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query nadmon statuses: %w", err)
+		}
+	}
+
+	for _, row := range rows {
+		status := models.NadmonStatus{TokenID: row.TokenID, Status: models.StatusActive}
+		if row.Burned {
+			status.Status = models.StatusBurned
+			if row.BurnedAt.Valid {
+				burnedAt := row.BurnedAt.Time
+				status.BurnedAt = &burnedAt
+			}
+		}
+		statuses[row.TokenID] = status
+	}
+
+	return statuses, nil
+}
+
+// toTransfer converts a generated transfer row into the API model
+func toTransfer(row envio.GetNadmonTransfersRow) models.Transfer {
+	kind := models.TransferKindTransfer
+	switch {
+	case strings.EqualFold(row.FromAddress, models.ZeroAddress):
+		kind = models.TransferKindMint
+	case strings.EqualFold(row.ToAddress, models.ZeroAddress):
+		kind = models.TransferKindBurn
+	}
+
+	return models.Transfer{
+		ID:            row.ID,
+		TokenID:       row.TokenID,
+		From:          row.FromAddress,
+		To:            row.ToAddress,
+		Kind:          kind,
+		TransferredAt: row.TransferredAt.Time,
+	}
+}
+
+// GetNadmonTransfers retrieves a page of an NFT's transfer history, newest first
+func (r *NadmonRepository) GetNadmonTransfers(ctx context.Context, tokenID int64, limit, offset int) (*models.TransferPage, error) {
+
+	total, err := r.queries.CountNadmonTransfers(ctx, tokenID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count nadmon transfers: %w", err)
+	}
+
+	rows, err := r.queries.GetNadmonTransfers(ctx, envio.GetNadmonTransfersParams{
+		TokenID:    tokenID,
+		MaxResults: int32(limit),
+		Skip:       int32(offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query nadmon transfers: %w", err)
+	}
+
+	transfers := make([]models.Transfer, 0, len(rows))
+	for _, row := range rows {
+		transfers = append(transfers, toTransfer(row))
+	}
+
+	return &models.TransferPage{Transfers: transfers, Total: int(total)}, nil
+}
+
+// GetPlayerTransfers retrieves a page of transfers sent or received by a player, newest first
+func (r *NadmonRepository) GetPlayerTransfers(ctx context.Context, address string, limit, offset int) (*models.TransferPage, error) {
+	address = ethaddr.Normalize(address)
+
+	total, err := r.queries.CountPlayerTransfers(ctx, address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count player transfers: %w", err)
+	}
+
+	rows, err := r.queries.GetPlayerTransfers(ctx, envio.GetPlayerTransfersParams{
+		Player:     address,
+		MaxResults: int32(limit),
+		Skip:       int32(offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query player transfers: %w", err)
+	}
+
+	transfers := make([]models.Transfer, 0, len(rows))
+	for _, row := range rows {
+		transfers = append(transfers, toTransfer(envio.GetNadmonTransfersRow(row)))
+	}
+
+	return &models.TransferPage{Transfers: transfers, Total: int(total)}, nil
+}
+
+// GetActivity retrieves a page of the global activity feed, newest first, limited to the
+// given entry types
+func (r *NadmonRepository) GetActivity(ctx context.Context, types []string, limit, offset int) (*models.ActivityPage, error) {
+
+	total, err := r.queries.CountActivityFeed(ctx, types)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count activity: %w", err)
+	}
+
+	rows, err := r.queries.GetActivityFeed(ctx, envio.GetActivityFeedParams{
+		Types:      types,
+		MaxResults: int32(limit),
+		Skip:       int32(offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query activity: %w", err)
+	}
+
+	activities := make([]models.Activity, 0, len(rows))
+	for _, row := range rows {
+		activities = append(activities, models.Activity{
+			ID:           row.ID,
+			Type:         row.ActivityType,
+			Player:       row.Player,
+			Counterparty: row.Counterparty,
+			TokenID:      row.TokenID,
+			PackID:       row.PackID,
+			Detail:       row.Detail,
+			OccurredAt:   row.OccurredAt.Time,
+		})
+	}
+
+	return &models.ActivityPage{Activities: activities, Total: int(total)}, nil
+}
+
+// GetPlayerActivity retrieves a page of a player's activity feed, newest first, limited to
+// the given entry types
+func (r *NadmonRepository) GetPlayerActivity(ctx context.Context, address string, types []string, limit, offset int) (*models.ActivityPage, error) {
+	address = ethaddr.Normalize(address)
+
+	total, err := r.queries.CountPlayerActivity(ctx, envio.CountPlayerActivityParams{
+		Types:  types,
+		Player: address,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to count player activity: %w", err)
+	}
+
+	rows, err := r.queries.GetPlayerActivity(ctx, envio.GetPlayerActivityParams{
+		Player:     address,
+		Types:      types,
+		MaxResults: int32(limit),
+		Skip:       int32(offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query player activity: %w", err)
+	}
+
+	activities := make([]models.Activity, 0, len(rows))
+	for _, row := range rows {
+		activities = append(activities, models.Activity{
+			ID:           row.ID,
+			Type:         row.ActivityType,
+			Player:       address,
+			Counterparty: row.Counterparty,
+			TokenID:      row.TokenID,
+			PackID:       row.PackID,
+			Detail:       row.Detail,
+			OccurredAt:   row.OccurredAt.Time,
+		})
+	}
+
+	return &models.ActivityPage{Activities: activities, Total: int(total)}, nil
+}
+
+// GetPackByID retrieves a specific pack by its ID
+func (r *NadmonRepository) GetPackByID(ctx context.Context, packID int64) (*models.Pack, error) {
+	row, err := r.queries.GetPackByID(ctx, packID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to query pack: %w", err)
+	}
+
+	pack := toPack(envio.GetPlayerPacksRow(row))
+	return &pack, nil
+}
+
+// GetRecentPacks retrieves the most recent pack purchases
+func (r *NadmonRepository) GetRecentPacks(ctx context.Context, limit int) ([]models.Pack, error) {
+	rows, err := r.queries.GetRecentPacks(ctx, int32(limit))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query recent packs: %w", err)
+	}
+
+	var packs []models.Pack
+	for _, row := range rows {
+		packs = append(packs, toPack(envio.GetPlayerPacksRow(row)))
+	}
+
+	return packs, nil
+}
 
-	var nadmon models.Nadmon
-	err := r.db.DB.QueryRow(query, tokenID).Scan(
-		&nadmon.TokenID, &nadmon.Owner, &nadmon.PackID, &nadmon.NadmonType,
-		&nadmon.Element, &nadmon.Rarity,
-		&nadmon.HP, &nadmon.Attack, &nadmon.Defense, &nadmon.Crit, &nadmon.Fusion, &nadmon.Evo,
-		&nadmon.CreatedAt, &nadmon.LastUpdated,
-	)
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil
+// GetTopCollectors retrieves players with the most NFTs
+func (r *NadmonRepository) GetTopCollectors(ctx context.Context, limit int) ([]models.PlayerProfile, error) {
+	params := envio.GetTopCollectorsParams{
+		ExcludedAddresses: r.excluded,
+		MaxResults:        int32(limit),
+	}
+
+	var rows []envio.GetTopCollectorsRow
+	var err error
+	if r.currentState() {
+		var stateRows []envio.GetTopCollectorsFromStateRow
+		stateRows, err = r.queries.GetTopCollectorsFromState(ctx, envio.GetTopCollectorsFromStateParams(params))
+		for _, row := range stateRows {
+			rows = append(rows, envio.GetTopCollectorsRow(row))
 		}
-		return nil, fmt.Errorf("failed to query single nadmon: %w", err)
+	} else {
+		rows, err = r.queries.GetTopCollectors(ctx, params)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query top collectors: %w", err)
 	}
 
-	return &nadmon, nil
+	var profiles []models.PlayerProfile
+	for _, row := range rows {
+		profiles = append(profiles, models.PlayerProfile{
+			Address:   row.Owner,
+			TotalNFTs: int(row.NftCount),
+		})
+	}
+
+	return profiles, nil
 }
 
-// GetPackByID retrieves a specific pack by its ID
-func (r *NadmonRepository) GetPackByID(packID int64) (*models.Pack, error) {
-	query := `
-		SELECT "packId", player, "tokenIds", "paymentType", db_write_timestamp
-		FROM "NadmonNFT_PackMinted"
-		WHERE "packId" = $1
-	`
+// leaderboardChangeTypes maps stat-change leaderboards to the changeType they count
+var leaderboardChangeTypes = map[string]string{
+	models.LeaderboardEvolutions: "evolution",
+	models.LeaderboardFusion:     "fusion",
+}
+
+// GetLeaderboard returns one page of a leaderboard (see models.LeaderboardTypes). When player
+// is set and ranked, their own entry is included.
+func (r *NadmonRepository) GetLeaderboard(ctx context.Context, kind, player string, limit, offset int) (*models.Leaderboard, error) {
+	player = ethaddr.Normalize(player)
 
-	var pack models.Pack
-	var tokenIDsStr string
-	err := r.db.DB.QueryRow(query, packID).Scan(
-		&pack.PackID, &pack.Player, &tokenIDsStr, &pack.PaymentType, &pack.PurchasedAt,
-	)
+	var rows []envio.GetPackLeaderboardRow
+	var rank envio.GetPackRankRow
+	var err error
+	switch kind {
+	case models.LeaderboardEvolutions, models.LeaderboardFusion:
+		rows, rank, err = r.statChangeLeaderboard(ctx, leaderboardChangeTypes[kind], player, limit, offset)
+	case models.LeaderboardPacks:
+		rows, rank, err = r.packLeaderboard(ctx, player, limit, offset)
+	case models.LeaderboardRarityScore:
+		rows, rank, err = r.rarityScoreLeaderboard(ctx, player, limit, offset)
+	default:
+		return nil, fmt.Errorf("unknown leaderboard type %q", kind)
+	}
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, nil
-		}
-		return nil, fmt.Errorf("failed to query pack: %w", err)
+		return nil, fmt.Errorf("failed to query %s leaderboard: %w", kind, err)
 	}
 
-	// Parse token IDs - handle both PostgreSQL array format and JSON format
-	if err := json.Unmarshal([]byte(tokenIDsStr), &pack.TokenIDs); err != nil {
-		// Try parsing as PostgreSQL array format: {1,2,3,4,5}
-		if strings.HasPrefix(tokenIDsStr, "{") && strings.HasSuffix(tokenIDsStr, "}") {
-			// Remove braces and split by comma
-			inner := strings.Trim(tokenIDsStr, "{}")
-			if inner == "" {
-				pack.TokenIDs = []int64{}
-			} else {
-				parts := strings.Split(inner, ",")
-				pack.TokenIDs = make([]int64, len(parts))
-				for i, part := range parts {
-					id, parseErr := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
-					if parseErr != nil {
-						return nil, fmt.Errorf("failed to parse token ID %s: %w", part, parseErr)
-					}
-					pack.TokenIDs[i] = id
-				}
-			}
-		} else {
-			return nil, fmt.Errorf("failed to parse token IDs: %w", err)
-		}
+	leaderboard := &models.Leaderboard{Type: kind, Entries: make([]models.LeaderboardEntry, 0, len(rows))}
+	for _, row := range rows {
+		leaderboard.Entries = append(leaderboard.Entries, models.LeaderboardEntry{
+			Rank:    int(row.Rank),
+			Address: row.Owner,
+			Score:   row.Score,
+		})
+		leaderboard.TotalPlayers = int(row.TotalPlayers)
+	}
+	if rank.Rank > 0 {
+		leaderboard.Player = &models.LeaderboardEntry{Rank: int(rank.Rank), Address: player, Score: rank.Score}
 	}
 
-	return &pack, nil
+	return leaderboard, nil
 }
 
-// GetRecentPacks retrieves the most recent pack purchases
-func (r *NadmonRepository) GetRecentPacks(limit int) ([]models.Pack, error) {
-	query := `
-		SELECT "packId", player, "tokenIds", "paymentType", db_write_timestamp
-		FROM "NadmonNFT_PackMinted"
-		ORDER BY sequence DESC
-		LIMIT $1
-	`
+// statChangeLeaderboard ranks players by stat changes of changeType made while they held the token
+func (r *NadmonRepository) statChangeLeaderboard(ctx context.Context, changeType, player string, limit, offset int) ([]envio.GetPackLeaderboardRow, envio.GetPackRankRow, error) {
+	page, err := r.queries.GetStatChangeLeaderboard(ctx, envio.GetStatChangeLeaderboardParams{
+		ChangeType:        changeType,
+		ExcludedAddresses: r.excluded,
+		MaxResults:        int32(limit),
+		Skip:              int32(offset),
+	})
+	if err != nil {
+		return nil, envio.GetPackRankRow{}, err
+	}
+	rows := make([]envio.GetPackLeaderboardRow, 0, len(page))
+	for _, row := range page {
+		rows = append(rows, envio.GetPackLeaderboardRow(row))
+	}
+
+	var rank envio.GetStatChangeRankRow
+	if player != "" {
+		rank, err = r.queries.GetStatChangeRank(ctx, envio.GetStatChangeRankParams{
+			ChangeType:        changeType,
+			ExcludedAddresses: r.excluded,
+			Player:            player,
+		})
+	}
+	return rows, envio.GetPackRankRow(rank), ignoreNoRows(err)
+}
 
-	rows, err := r.db.DB.Query(query, limit)
+// packLeaderboard ranks players by packs bought
+func (r *NadmonRepository) packLeaderboard(ctx context.Context, player string, limit, offset int) ([]envio.GetPackLeaderboardRow, envio.GetPackRankRow, error) {
+	rows, err := r.queries.GetPackLeaderboard(ctx, envio.GetPackLeaderboardParams{
+		ExcludedAddresses: r.excluded,
+		MaxResults:        int32(limit),
+		Skip:              int32(offset),
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to query recent packs: %w", err)
+		return nil, envio.GetPackRankRow{}, err
 	}
-	defer rows.Close()
 
-	var packs []models.Pack
-	for rows.Next() {
-		var p models.Pack
-		var tokenIDs pq.Int64Array
-		err := rows.Scan(&p.PackID, &p.Player, &tokenIDs, &p.PaymentType, &p.PurchasedAt)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan pack: %w", err)
+	var rank envio.GetPackRankRow
+	if player != "" {
+		rank, err = r.queries.GetPackRank(ctx, envio.GetPackRankParams{ExcludedAddresses: r.excluded, Player: player})
+	}
+	return rows, rank, ignoreNoRows(err)
+}
+
+// rarityScoreLeaderboard ranks players by the rarity-weighted value of the NFTs they hold
+func (r *NadmonRepository) rarityScoreLeaderboard(ctx context.Context, player string, limit, offset int) ([]envio.GetPackLeaderboardRow, envio.GetPackRankRow, error) {
+	params := envio.GetRarityScoreLeaderboardParams{
+		ExcludedAddresses: r.excluded,
+		MaxResults:        int32(limit),
+		Skip:              int32(offset),
+	}
+	rankParams := envio.GetRarityScoreRankParams{ExcludedAddresses: r.excluded, Player: player}
+
+	var rows []envio.GetPackLeaderboardRow
+	var rank envio.GetRarityScoreRankRow
+	var err error
+	if r.currentState() {
+		var page []envio.GetRarityScoreLeaderboardFromStateRow
+		page, err = r.queries.GetRarityScoreLeaderboardFromState(ctx, envio.GetRarityScoreLeaderboardFromStateParams(params))
+		for _, row := range page {
+			rows = append(rows, envio.GetPackLeaderboardRow(row))
+		}
+		if err == nil && player != "" {
+			var stateRank envio.GetRarityScoreRankFromStateRow
+			stateRank, err = r.queries.GetRarityScoreRankFromState(ctx, envio.GetRarityScoreRankFromStateParams(rankParams))
+			rank = envio.GetRarityScoreRankRow(stateRank)
+		}
+	} else {
+		var page []envio.GetRarityScoreLeaderboardRow
+		page, err = r.queries.GetRarityScoreLeaderboard(ctx, params)
+		for _, row := range page {
+			rows = append(rows, envio.GetPackLeaderboardRow(row))
+		}
+		if err == nil && player != "" {
+			rank, err = r.queries.GetRarityScoreRank(ctx, rankParams)
 		}
-		p.TokenIDs = []int64(tokenIDs)
-		packs = append(packs, p)
 	}
+	return rows, envio.GetPackRankRow(rank), ignoreNoRows(err)
+}
 
-	return packs, nil
+// ignoreNoRows treats sql.ErrNoRows (e.g. an unranked player) as success
+func ignoreNoRows(err error) error {
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	return err
 }
 
-// GetTopCollectors retrieves players with the most NFTs
-func (r *NadmonRepository) GetTopCollectors(limit int) ([]models.PlayerProfile, error) {
-	query := `
-		WITH current_owners AS (
-			SELECT DISTINCT ON (t."tokenId") 
-				t."tokenId", 
-				t."to" as current_owner
-			FROM "NadmonNFT_Transfer" t
-			ORDER BY t."tokenId", t.db_write_timestamp DESC
-		)
-		SELECT 
-			COALESCE(co.current_owner, m.owner) as owner, 
-			COUNT(*) as nft_count
-		FROM "NadmonNFT_NadmonMinted" m
-		LEFT JOIN current_owners co ON m."tokenId" = co."tokenId"
-		WHERE COALESCE(co.current_owner, m.owner) != '0x0000000000000000000000000000000000000000'
-		GROUP BY COALESCE(co.current_owner, m.owner)
-		ORDER BY nft_count DESC
-		LIMIT $1
-	`
+// likeEscaper escapes LIKE wildcards so user input is matched literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// GetSearchSuggestions returns nadmon types, elements and rarities matching query, with circulating counts
+func (r *NadmonRepository) GetSearchSuggestions(ctx context.Context, query string, limit int) ([]models.SearchSuggestion, error) {
+	params := envio.GetSearchSuggestionsParams{
+		Query:      likeEscaper.Replace(strings.TrimSpace(query)),
+		MaxResults: int32(limit),
+	}
 
-	rows, err := r.db.DB.Query(query, limit)
+	var rows []envio.GetSearchSuggestionsRow
+	var err error
+	if r.currentState() {
+		var stateRows []envio.GetSearchSuggestionsFromStateRow
+		stateRows, err = r.queries.GetSearchSuggestionsFromState(ctx, envio.GetSearchSuggestionsFromStateParams(params))
+		for _, row := range stateRows {
+			rows = append(rows, envio.GetSearchSuggestionsRow(row))
+		}
+	} else {
+		rows, err = r.queries.GetSearchSuggestions(ctx, params)
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to query top collectors: %w", err)
+		return nil, fmt.Errorf("failed to query search suggestions: %w", err)
 	}
-	defer rows.Close()
 
-	var profiles []models.PlayerProfile
-	for rows.Next() {
-		var profile models.PlayerProfile
-		err := rows.Scan(&profile.Address, &profile.TotalNFTs)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan collector: %w", err)
+	suggestions := make([]models.SearchSuggestion, 0, len(rows))
+	for _, row := range rows {
+		suggestions = append(suggestions, models.SearchSuggestion{
+			Type:        row.Kind,
+			Value:       row.Value,
+			Circulating: int(row.Circulating),
+		})
+	}
+
+	return suggestions, nil
+}
+
+// searchStats are the stats SearchNadmons can filter by range and sort on
+var searchStats = []string{"hp", "attack", "defense", "crit", "fusion", "evo"}
+
+// SortByTokenID is the default SearchNadmons sort field
+const SortByTokenID = "token_id"
+
+// Sort fields beside the stats: rarity from Common up to Legendary, and mint time
+const (
+	SortByRarity    = "rarity"
+	SortByCreatedAt = "created_at"
+)
+
+// SearchSortFields lists the sort_by values accepted by SearchNadmons
+var SearchSortFields = append([]string{SortByTokenID, SortByRarity, SortByCreatedAt}, searchStats...)
+
+// rarityRank is a SQL expression ordering the rarity in column from Common (1) to Legendary (5)
+func rarityRank(column string) string {
+	return `CASE ` + column + ` WHEN 'Legendary' THEN 5 WHEN 'Epic' THEN 4 WHEN 'Rare' THEN 3 WHEN 'Uncommon' THEN 2 ELSE 1 END`
+}
+
+// isSearchStat reports whether name is a stat SearchNadmons can filter and sort on
+func isSearchStat(name string) bool {
+	for _, stat := range searchStats {
+		if stat == name {
+			return true
 		}
-		profiles = append(profiles, profile)
 	}
+	return false
+}
 
-	return profiles, nil
+// sortDirection returns the SQL direction for the "order" filter, ascending by default
+func sortDirection(filters map[string]interface{}) string {
+	if order, ok := filters["order"].(string); ok && strings.EqualFold(order, "desc") {
+		return "DESC"
+	}
+	return "ASC"
 }
 
-// SearchNadmons searches for NFTs by various criteria
-func (r *NadmonRepository) SearchNadmons(address string, filters map[string]interface{}) ([]models.Nadmon, error) {
-	baseQuery := `
+// searchSource returns the query over circulating NFTs the searches filter, without ORDER
+// BY, and the SQL expression of each filterable and sortable column
+func (r *NadmonRepository) searchSource() (query string, columns map[string]string) {
+	query = `
 		WITH current_owners AS (
 			SELECT DISTINCT ON (t."tokenId") 
 				t."tokenId", 
@@ -475,7 +1066,7 @@ func (r *NadmonRepository) SearchNadmons(address string, filters map[string]inte
 		)
 		SELECT 
 			m."tokenId", 
-			COALESCE(co.current_owner, m.owner) as owner, 
+			LOWER(COALESCE(co.current_owner, m.owner)) as owner, 
 			m."packId", m."nadmonType", 
 			m.element, m.rarity,
 			COALESCE(ls."newHp", m.hp) as hp,
@@ -489,48 +1080,136 @@ func (r *NadmonRepository) SearchNadmons(address string, filters map[string]inte
 		FROM "NadmonNFT_NadmonMinted" m
 		LEFT JOIN current_owners co ON m."tokenId" = co."tokenId"
 		LEFT JOIN latest_stats ls ON m."tokenId" = ls."tokenId"
-		WHERE COALESCE(co.current_owner, m.owner) = $1 
-			AND COALESCE(co.current_owner, m.owner) != '0x0000000000000000000000000000000000000000'
+		WHERE COALESCE(co.current_owner, m.owner) != '0x0000000000000000000000000000000000000000'
+	`
+	columns = map[string]string{
+		"tokenId": `m."tokenId"`,
+		"owner":   `LOWER(COALESCE(co.current_owner, m.owner))`,
+		"element": `m.element`,
+		"rarity":  `m.rarity`,
+		"type":    `m."nadmonType"`,
+		"evo":     `COALESCE(ls."newEvo", m.evo)`,
+		"hp":      `COALESCE(ls."newHp", m.hp)`,
+		"attack":  `COALESCE(ls."newAttack", m.attack)`,
+		"defense": `COALESCE(ls."newDefense", m.defense)`,
+		"crit":    `COALESCE(ls."newCrit", m.crit)`,
+		"fusion":  `COALESCE(ls."newFusion", m.fusion)`,
+
+		// Sort only
+		SortByCreatedAt: `m.db_write_timestamp`,
+	}
+
+	if r.currentState() {
+		query = `
+		SELECT
+			s.token_id, s.owner, s.pack_id, s.nadmon_type,
+			s.element, s.rarity, s.hp, s.attack, s.defense,
+			s.crit, s.fusion, s.evo, s.created_at, s.last_updated
+		FROM nadmon_current_state s
+		WHERE s.owner != '0x0000000000000000000000000000000000000000'
 	`
+		columns = map[string]string{
+			"tokenId": `s.token_id`,
+			"owner":   `s.owner`,
+			"element": `s.element`,
+			"rarity":  `s.rarity`,
+			"type":    `s.nadmon_type`,
+			"evo":     `s.evo`,
+			"hp":      `s.hp`,
+			"attack":  `s.attack`,
+			"defense": `s.defense`,
+			"crit":    `s.crit`,
+			"fusion":  `s.fusion`,
 
+			// Sort only
+			SortByCreatedAt: `s.created_at`,
+		}
+	}
+
+	return query, columns
+}
+
+// searchConditions returns the WHERE conditions for the search filters and appends their
+// arguments to args
+func searchConditions(columns map[string]string, filters map[string]interface{}, args []interface{}) ([]string, []interface{}) {
 	var conditions []string
-	var args []interface{}
-	args = append(args, address)
-	argIndex := 2
+	argIndex := len(args) + 1
 
 	// Add filters
 	if element, ok := filters["element"].(string); ok && element != "" {
-		conditions = append(conditions, fmt.Sprintf("m.element = $%d", argIndex))
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", columns["element"], argIndex))
 		args = append(args, element)
 		argIndex++
 	}
 
 	if rarity, ok := filters["rarity"].(string); ok && rarity != "" {
-		conditions = append(conditions, fmt.Sprintf("m.rarity = $%d", argIndex))
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", columns["rarity"], argIndex))
 		args = append(args, rarity)
 		argIndex++
 	}
 
 	if nadmonType, ok := filters["type"].(string); ok && nadmonType != "" {
-		conditions = append(conditions, fmt.Sprintf("m.\"nadmonType\" = $%d", argIndex))
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", columns["type"], argIndex))
 		args = append(args, nadmonType)
 		argIndex++
 	}
 
 	if evo, ok := filters["evo"].(int); ok && evo > 0 {
-		conditions = append(conditions, fmt.Sprintf("COALESCE(s.\"newEvo\", m.evo) = $%d", argIndex))
+		conditions = append(conditions, fmt.Sprintf("%s = $%d", columns["evo"], argIndex))
 		args = append(args, evo)
 		argIndex++
 	}
 
+	// Case-insensitive owner substring, e.g. owner=dead
+	if owner, ok := filters["owner"].(string); ok && owner != "" {
+		conditions = append(conditions, fmt.Sprintf("%s LIKE $%d", columns["owner"], argIndex))
+		args = append(args, "%"+likeEscaper.Replace(strings.ToLower(owner))+"%")
+		argIndex++
+	}
+
+	// Stat ranges, e.g. min_attack / max_attack
+	for _, stat := range searchStats {
+		if lower, ok := filters["min_"+stat].(int); ok {
+			conditions = append(conditions, fmt.Sprintf("%s >= $%d", columns[stat], argIndex))
+			args = append(args, lower)
+			argIndex++
+		}
+		if upper, ok := filters["max_"+stat].(int); ok {
+			conditions = append(conditions, fmt.Sprintf("%s <= $%d", columns[stat], argIndex))
+			args = append(args, upper)
+			argIndex++
+		}
+	}
+
+	return conditions, args
+}
+
+// SearchNadmons searches for NFTs by various criteria
+func (r *NadmonRepository) SearchNadmons(ctx context.Context, address string, filters map[string]interface{}) ([]models.Nadmon, error) {
+	baseQuery, columns := r.searchSource()
+	baseQuery += fmt.Sprintf(" AND %s = $1", columns["owner"])
+	conditions, args := searchConditions(columns, filters, []interface{}{ethaddr.Normalize(address)})
+
 	// Add conditions to query
 	if len(conditions) > 0 {
 		baseQuery += " AND " + strings.Join(conditions, " AND ")
 	}
 
-	baseQuery += " ORDER BY m.\"tokenId\", s.sequence DESC NULLS LAST"
+	// Sort by a whitelisted column, breaking ties by token ID
+	baseQuery += " ORDER BY "
+	if sortBy, ok := filters["sort_by"].(string); ok && sortBy != "" && sortBy != SortByTokenID {
+		column := columns[sortBy]
+		switch {
+		case sortBy == SortByRarity:
+			column = rarityRank(columns["rarity"])
+		case !isSearchStat(sortBy) && sortBy != SortByCreatedAt:
+			return nil, fmt.Errorf("unsupported sort field %q", sortBy)
+		}
+		baseQuery += column + " " + sortDirection(filters) + ", "
+	}
+	baseQuery += columns["tokenId"] + " " + sortDirection(filters)
 
-	rows, err := r.db.DB.Query(baseQuery, args...)
+	rows, err := r.conn.QueryContext(ctx, baseQuery, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search nadmons: %w", err)
 	}
@@ -550,67 +1229,378 @@ func (r *NadmonRepository) SearchNadmons(address string, filters map[string]inte
 		}
 		nadmons = append(nadmons, n)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read nadmons: %w", err)
+	}
 
 	return nadmons, nil
 }
 
+// searchKey returns a BIGINT SQL expression of the sort_by field that keyset pagination
+// compares against SearchCursor.Key
+func searchKey(columns map[string]string, sortBy string) (string, error) {
+	switch {
+	case sortBy == "" || sortBy == SortByTokenID:
+		return columns["tokenId"], nil
+	case sortBy == SortByRarity:
+		return rarityRank(columns["rarity"]), nil
+	case sortBy == SortByCreatedAt:
+		return `(EXTRACT(EPOCH FROM ` + columns[SortByCreatedAt] + `) * 1000000)::BIGINT`, nil
+	case isSearchStat(sortBy):
+		return columns[sortBy], nil
+	}
+	return "", fmt.Errorf("unsupported sort field %q", sortBy)
+}
+
+// SearchAllNadmons searches the circulating NFTs of every player by the SearchNadmons
+// filters plus an owner substring, returning up to limit results after the cursor (nil for
+// the first page) and the total number of matches
+func (r *NadmonRepository) SearchAllNadmons(ctx context.Context, filters map[string]interface{}, after *SearchCursor, limit int) (*models.NadmonSearchPage, error) {
+	baseQuery, columns := r.searchSource()
+	conditions, args := searchConditions(columns, filters, nil)
+	if len(conditions) > 0 {
+		baseQuery += " AND " + strings.Join(conditions, " AND ")
+	}
+
+	sortBy, _ := filters["sort_by"].(string)
+	key, err := searchKey(columns, sortBy)
+	if err != nil {
+		return nil, err
+	}
+	direction := sortDirection(filters)
+
+	page := &models.NadmonSearchPage{Nadmons: []models.Nadmon{}}
+	if err := r.conn.QueryRowContext(ctx, "SELECT COUNT(*) FROM ("+baseQuery+") matches", args...).Scan(&page.Total); err != nil {
+		return nil, fmt.Errorf("failed to count search results: %w", err)
+	}
+
+	// Keyset pagination: rows sorting after the cursor's (key, token ID) in the sort direction
+	if after != nil {
+		comparison := ">"
+		if direction == "DESC" {
+			comparison = "<"
+		}
+		baseQuery += fmt.Sprintf(" AND (%s, %s) %s ($%d, $%d)", key, columns["tokenId"], comparison, len(args)+1, len(args)+2)
+		args = append(args, after.Key, after.TokenID)
+	}
+	baseQuery += fmt.Sprintf(" ORDER BY %s %s, %s %s LIMIT $%d", key, direction, columns["tokenId"], direction, len(args)+1)
+	args = append(args, limit+1)
+
+	rows, err := r.conn.QueryContext(ctx, baseQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search nadmons: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var n models.Nadmon
+		err := rows.Scan(
+			&n.TokenID, &n.Owner, &n.PackID, &n.NadmonType,
+			&n.Element, &n.Rarity, &n.HP, &n.Attack,
+			&n.Defense, &n.Crit, &n.Fusion, &n.Evo,
+			&n.CreatedAt, &n.LastUpdated,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan nadmon: %w", err)
+		}
+		page.Nadmons = append(page.Nadmons, n)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to search nadmons: %w", err)
+	}
+
+	return NewSearchPage(page, sortBy, limit), nil
+}
+
 // GetGameStats retrieves overall game statistics
-func (r *NadmonRepository) GetGameStats() (*models.GameStats, error) {
-	stats := &models.GameStats{}
+func (r *NadmonRepository) GetGameStats(ctx context.Context) (*models.GameStats, error) {
+
+	countCirculating, countCollectors := r.queries.CountCirculatingNadmons, r.queries.CountUniqueCollectors
+	if r.currentState() {
+		countCirculating, countCollectors = r.queries.CountCirculatingNadmonsFromState, r.queries.CountUniqueCollectorsFromState
+	}
 
 	// Total NFTs (excluding burned ones)
-	err := r.db.DB.QueryRow(`
-		WITH current_owners AS (
-			SELECT DISTINCT ON (t."tokenId") 
-				t."tokenId", 
-				t."to" as current_owner
-			FROM "NadmonNFT_Transfer" t
-			ORDER BY t."tokenId", t.db_write_timestamp DESC
-		)
-		SELECT COUNT(*) 
-		FROM "NadmonNFT_NadmonMinted" m
-		LEFT JOIN current_owners co ON m."tokenId" = co."tokenId"
-		WHERE COALESCE(co.current_owner, m.owner) != '0x0000000000000000000000000000000000000000'
-	`).Scan(&stats.TotalNFTs)
+	totalNFTs, err := countCirculating(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to count NFTs: %w", err)
 	}
 
 	// Total packs
-	err = r.db.DB.QueryRow(`SELECT COUNT(*) FROM "NadmonNFT_PackMinted"`).Scan(&stats.TotalPacks)
+	totalPacks, err := r.queries.CountPacks(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to count packs: %w", err)
 	}
 
 	// Unique collectors (excluding those who only have burned NFTs)
-	err = r.db.DB.QueryRow(`
-		WITH current_owners AS (
-			SELECT DISTINCT ON (t."tokenId") 
-				t."tokenId", 
-				t."to" as current_owner
-			FROM "NadmonNFT_Transfer" t
-			ORDER BY t."tokenId", t.db_write_timestamp DESC
-		)
-		SELECT COUNT(DISTINCT COALESCE(co.current_owner, m.owner)) 
-		FROM "NadmonNFT_NadmonMinted" m
-		LEFT JOIN current_owners co ON m."tokenId" = co."tokenId"
-		WHERE COALESCE(co.current_owner, m.owner) != '0x0000000000000000000000000000000000000000'
-	`).Scan(&stats.UniqueCollectors)
+	uniqueCollectors, err := countCollectors(ctx, r.excluded)
 	if err != nil {
 		return nil, fmt.Errorf("failed to count collectors: %w", err)
 	}
 
 	// Total evolutions
-	err = r.db.DB.QueryRow(`SELECT COUNT(*) FROM "NadmonNFT_StatsChanged" WHERE "changeType" = 'evolution'`).Scan(&stats.TotalEvolutions)
+	totalEvolutions, err := r.queries.CountEvolutions(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to count evolutions: %w", err)
 	}
 
 	// Total players (unique pack buyers)
-	err = r.db.DB.QueryRow(`SELECT COUNT(DISTINCT player) FROM "NadmonNFT_PackMinted"`).Scan(&stats.TotalPlayers)
+	totalPlayers, err := r.queries.CountPackBuyers(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to count players: %w", err)
 	}
 
+	return &models.GameStats{
+		TotalPlayers:     int(totalPlayers),
+		TotalNFTs:        int(totalNFTs),
+		TotalPacks:       int(totalPacks),
+		TotalEvolutions:  int(totalEvolutions),
+		UniqueCollectors: int(uniqueCollectors),
+	}, nil
+}
+
+// GetPackDistribution retrieves a histogram of how many packs each player has bought
+func (r *NadmonRepository) GetPackDistribution(ctx context.Context) (*models.PackDistribution, error) {
+	row, err := r.queries.GetPackDistribution(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pack distribution: %w", err)
+	}
+
+	return models.NewPackDistribution(
+		[4]int{int(row.SinglePack), int(row.TwoToFive), int(row.SixToTwenty), int(row.OverTwenty)},
+		int(row.TotalPlayers), int(row.TotalPacks), row.Mean, row.Median,
+	), nil
+}
+
+// GetOwnershipConcentration computes the supply share of the top holders and the Gini coefficient
+func (r *NadmonRepository) GetOwnershipConcentration(ctx context.Context) (*models.OwnershipConcentration, error) {
+	getBalances := r.queries.GetHolderBalances
+	if r.currentState() {
+		getBalances = r.queries.GetHolderBalancesFromState
+	}
+
+	// Balances are sorted descending
+	rows, err := getBalances(ctx, r.excluded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query holder balances: %w", err)
+	}
+
+	balances := make([]int, len(rows))
+	for i, balance := range rows {
+		balances[i] = int(balance)
+	}
+
+	return models.NewOwnershipConcentration(balances), nil
+}
+
+// GetSupplyStats counts minted, burned and circulating tokens overall and per rarity and element
+func (r *NadmonRepository) GetSupplyStats(ctx context.Context) (*models.SupplyStats, error) {
+	var rows []envio.GetSupplyBreakdownRow
+	var err error
+	if r.currentState() {
+		var stateRows []envio.GetSupplyBreakdownFromStateRow
+		stateRows, err = r.queries.GetSupplyBreakdownFromState(ctx)
+		for _, row := range stateRows {
+			rows = append(rows, envio.GetSupplyBreakdownRow(row))
+		}
+	} else {
+		rows, err = r.queries.GetSupplyBreakdown(ctx)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query supply breakdown: %w", err)
+	}
+
+	stats := &models.SupplyStats{
+		ByRarity:  map[string]models.SupplyCount{},
+		ByElement: map[string]models.SupplyCount{},
+	}
+	for _, row := range rows {
+		byValue := stats.ByElement
+		if row.Dimension == "rarity" {
+			// Every token has exactly one rarity, so the rarity rows add up to the totals
+			byValue = stats.ByRarity
+			stats.Add(int(row.Minted), int(row.Burned))
+		}
+		count := byValue[row.Value]
+		count.Add(int(row.Minted), int(row.Burned))
+		byValue[row.Value] = count
+	}
+
 	return stats, nil
-}
\ No newline at end of file
+}
+
+// GetTraitStats breaks the circulating supply down by element or rarity (models.DimensionElement
+// or models.DimensionRarity) with the average current stats and evolution rate of each
+func (r *NadmonRepository) GetTraitStats(ctx context.Context, dimension string) (*models.TraitBreakdown, error) {
+	var rows []envio.GetTraitStatsRow
+	var err error
+	if r.currentState() {
+		var stateRows []envio.GetTraitStatsFromStateRow
+		stateRows, err = r.queries.GetTraitStatsFromState(ctx, dimension)
+		for _, row := range stateRows {
+			rows = append(rows, envio.GetTraitStatsRow(row))
+		}
+	} else {
+		rows, err = r.queries.GetTraitStats(ctx, dimension)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s stats: %w", dimension, err)
+	}
+
+	totals := make([]models.TraitTotals, 0, len(rows))
+	for _, row := range rows {
+		totals = append(totals, models.TraitTotals{
+			Value:   row.Value,
+			Count:   int(row.Nadmons),
+			Evolved: int(row.Evolved),
+			HP:      row.TotalHp,
+			Attack:  row.TotalAttack,
+			Defense: row.TotalDefense,
+			Crit:    row.TotalCrit,
+		})
+	}
+
+	return models.NewTraitBreakdown(dimension, totals), nil
+}
+
+// GetPackOdds computes empirical rarity and element drop rates of the packs bought in [from, to),
+// overall and per payment type. A zero from or to leaves that side of the window open.
+func (r *NadmonRepository) GetPackOdds(ctx context.Context, from, to time.Time) (*models.PackOddsReport, error) {
+	window := envio.GetPackOddsParams{
+		FromTime: sql.NullTime{Time: from, Valid: !from.IsZero()},
+		ToTime:   sql.NullTime{Time: to, Valid: !to.IsZero()},
+	}
+
+	packRows, err := r.queries.CountPacksByPaymentType(ctx, envio.CountPacksByPaymentTypeParams(window))
+	if err != nil {
+		return nil, fmt.Errorf("failed to count packs by payment type: %w", err)
+	}
+	dropRows, err := r.queries.GetPackOdds(ctx, window)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query pack odds: %w", err)
+	}
+
+	all := newPackDrops()
+	byPayment := map[string]*packDrops{}
+	for _, row := range packRows {
+		byPayment[row.PaymentType] = newPackDrops()
+		byPayment[row.PaymentType].packs = int(row.Packs)
+		all.packs += int(row.Packs)
+	}
+	for _, row := range dropRows {
+		drops, ok := byPayment[row.PaymentType]
+		if !ok {
+			continue
+		}
+		drops.add(row.Dimension, row.Value, int(row.Drops))
+		all.add(row.Dimension, row.Value, int(row.Drops))
+	}
+
+	report := &models.PackOddsReport{
+		PackOdds:      all.odds(),
+		ByPaymentType: make(map[string]models.PackOdds, len(byPayment)),
+	}
+	for paymentType, drops := range byPayment {
+		report.ByPaymentType[paymentType] = drops.odds()
+	}
+	return report, nil
+}
+
+// packDrops accumulates Nadmon drop counts of a set of packs
+type packDrops struct {
+	packs     int
+	nadmons   int
+	byRarity  map[string]int
+	byElement map[string]int
+}
+
+func newPackDrops() *packDrops {
+	return &packDrops{byRarity: map[string]int{}, byElement: map[string]int{}}
+}
+
+func (d *packDrops) add(dimension, value string, drops int) {
+	if dimension == "rarity" {
+		// Every Nadmon has exactly one rarity, so the rarity rows add up to the total
+		d.byRarity[value] += drops
+		d.nadmons += drops
+		return
+	}
+	d.byElement[value] += drops
+}
+
+func (d *packDrops) odds() models.PackOdds {
+	odds := models.PackOdds{
+		Packs:     d.packs,
+		Nadmons:   d.nadmons,
+		ByRarity:  make(map[string]models.DropRate, len(d.byRarity)),
+		ByElement: make(map[string]models.DropRate, len(d.byElement)),
+	}
+	for rarity, drops := range d.byRarity {
+		odds.ByRarity[rarity] = models.NewDropRate(drops, d.nadmons)
+	}
+	for element, drops := range d.byElement {
+		odds.ByElement[element] = models.NewDropRate(drops, d.nadmons)
+	}
+	return odds
+}
+
+// GetEventTimeSeries counts events of a metric (mints, packs, transfers) or active players per
+// hour or day. Event counts read the TimescaleDB continuous aggregate when available and fall
+// back to date_trunc over the Envio tables otherwise; distinct players can't be summed from
+// hourly buckets, so they are always counted from the pack table.
+func (r *NadmonRepository) GetEventTimeSeries(ctx context.Context, metric, interval string, from, to time.Time) ([]models.TimeSeriesPoint, error) {
+	table, ok := database.TimescaleEventSources[metric]
+	count := "COUNT(*)"
+	if metric == models.MetricPlayers {
+		table, ok, count = database.TimescaleEventSources["packs"], true, "COUNT(DISTINCT LOWER(player))"
+	}
+	if !ok {
+		return nil, fmt.Errorf("unknown metric %q", metric)
+	}
+	if interval != "hour" && interval != "day" {
+		return nil, fmt.Errorf("unknown interval %q", interval)
+	}
+
+	var query string
+	var args []interface{}
+	if r.timescale() && metric != models.MetricPlayers {
+		query = `
+			SELECT date_trunc($1, bucket) AS b, SUM(events)::bigint
+			FROM nadmon_analytics_hourly
+			WHERE kind = $2 AND bucket >= $3 AND bucket < $4
+			GROUP BY b
+			ORDER BY b
+		`
+		args = []interface{}{interval, metric, from, to}
+	} else {
+		query = fmt.Sprintf(`
+			SELECT date_trunc($1, db_write_timestamp AT TIME ZONE 'UTC') AS b, %s
+			FROM %s
+			WHERE db_write_timestamp AT TIME ZONE 'UTC' >= $2
+				AND db_write_timestamp AT TIME ZONE 'UTC' < $3
+			GROUP BY b
+			ORDER BY b
+		`, count, table)
+		args = []interface{}{interval, from, to}
+	}
+
+	rows, err := r.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query %s time series: %w", metric, err)
+	}
+	defer rows.Close()
+
+	points := []models.TimeSeriesPoint{}
+	for rows.Next() {
+		var point models.TimeSeriesPoint
+		if err := rows.Scan(&point.Bucket, &point.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan time series point: %w", err)
+		}
+		points = append(points, point)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s time series: %w", metric, err)
+	}
+
+	return points, nil
+}
@@ -0,0 +1,1663 @@
+//go:build integration
+
+package repository
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"nadmon-backend/internal/database"
+	"nadmon-backend/internal/fixtures"
+	"nadmon-backend/internal/models"
+	"nadmon-backend/internal/testharness"
+)
+
+func newTestRepository(t *testing.T) *NadmonRepository {
+	t.Helper()
+	return NewNadmonRepository(testharness.StartEnvioDB(t))
+}
+
+func TestRepository(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+
+	t.Run("GetPlayerNadmons follows transfers and hides burns", func(t *testing.T) {
+		nadmons, err := repo.GetPlayerNadmons(ctx, fixtures.Alice)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []int64{1, 2, 4, 5, 11, 12, 14, 15}
+		if len(nadmons) != len(want) {
+			t.Fatalf("got %d nadmons, want %d", len(nadmons), len(want))
+		}
+		for i, n := range nadmons {
+			if n.TokenID != want[i] {
+				t.Errorf("nadmons[%d].TokenID = %d, want %d", i, n.TokenID, want[i])
+			}
+		}
+
+		carol, err := repo.GetPlayerNadmons(ctx, fixtures.Carol)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(carol) != 1 || carol[0].TokenID != 3 {
+			t.Errorf("carol should own token 3 only, got %+v", carol)
+		}
+	})
+
+	t.Run("addresses are case-insensitive", func(t *testing.T) {
+		upper := "0x" + strings.ToUpper(fixtures.Alice[2:])
+
+		nadmons, err := repo.GetPlayerNadmons(ctx, upper)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(nadmons) != 8 || nadmons[0].Owner != fixtures.Alice {
+			t.Errorf("expected alice's 8 nadmons with a lowercase owner, got %d", len(nadmons))
+		}
+
+		profile, err := repo.GetPlayerProfile(ctx, upper)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if profile.Address != fixtures.Alice || profile.PacksBought != 2 {
+			t.Errorf("unexpected profile for checksummed address: %+v", profile)
+		}
+
+		transfers, err := repo.GetPlayerTransfers(ctx, "0x"+strings.ToUpper(fixtures.Carol[2:]), 10, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if transfers.Total != 1 {
+			t.Errorf("expected carol's transfer, got %+v", transfers)
+		}
+	})
+
+	t.Run("GetPlayerNadmons applies latest stats", func(t *testing.T) {
+		nadmons, err := repo.GetNadmonsByIDs(ctx, []int64{2, 4})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(nadmons) != 2 {
+			t.Fatalf("got %d nadmons, want 2", len(nadmons))
+		}
+		if nadmons[0].Evo != 2 || nadmons[0].Attack != 40 {
+			t.Errorf("token 2 should be evolved, got evo=%d attack=%d", nadmons[0].Evo, nadmons[0].Attack)
+		}
+		if nadmons[1].Fusion != 1 {
+			t.Errorf("token 4 should be fused once, got fusion=%d", nadmons[1].Fusion)
+		}
+	})
+
+	t.Run("GetPlayerProfile", func(t *testing.T) {
+		profile, err := repo.GetPlayerProfile(ctx, fixtures.Alice)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if profile.TotalNFTs != 8 || profile.PacksBought != 2 {
+			t.Errorf("got total=%d packs=%d, want 8 and 2", profile.TotalNFTs, profile.PacksBought)
+		}
+		if profile.LastActive.IsZero() {
+			t.Error("expected last activity to be set")
+		}
+	})
+
+	t.Run("GetPlayerProfile and GetPlayerSummary match the inventory", func(t *testing.T) {
+		for _, address := range []string{fixtures.Alice, fixtures.Bob, fixtures.Carol, "0x000000000000000000000000000000000000dead"} {
+			profile, err := repo.GetPlayerProfile(ctx, address)
+			if err != nil {
+				t.Fatal(err)
+			}
+			nadmons, err := repo.GetPlayerNadmons(ctx, address)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(profile.Nadmons) != len(nadmons) || profile.TotalNFTs != len(nadmons) {
+				t.Fatalf("%s: profile has %d nadmons, inventory %d", address, len(profile.Nadmons), len(nadmons))
+			}
+			for i := range nadmons {
+				if profile.Nadmons[i] != nadmons[i] {
+					t.Errorf("%s: nadmons[%d] = %+v, want %+v", address, i, profile.Nadmons[i], nadmons[i])
+				}
+			}
+
+			summary, err := repo.GetPlayerSummary(ctx, address)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if summary.TotalNFTs != profile.TotalNFTs || summary.PacksBought != profile.PacksBought || !summary.LastActive.Equal(profile.LastActive) {
+				t.Errorf("%s: summary %+v doesn't match profile %+v", address, summary, profile)
+			}
+			rarities, elements, evolved := map[string]int{}, map[string]int{}, 0
+			for _, nadmon := range nadmons {
+				rarities[nadmon.Rarity]++
+				elements[nadmon.Element]++
+				if nadmon.Evo > 1 {
+					evolved++
+				}
+			}
+			if !reflect.DeepEqual(summary.RarityCounts, rarities) || !reflect.DeepEqual(summary.ElementCounts, elements) || summary.EvolvedNFTs != evolved {
+				t.Errorf("%s: summary counts %v %v %d, want %v %v %d", address,
+					summary.RarityCounts, summary.ElementCounts, summary.EvolvedNFTs, rarities, elements, evolved)
+			}
+		}
+	})
+
+	t.Run("GetPlayerDex counts burned species as seen", func(t *testing.T) {
+		dex, err := repo.GetPlayerDex(ctx, fixtures.Alice)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dex.TotalSpecies != 12 || dex.OwnedSpecies != 8 || dex.Completion != 66.67 {
+			t.Fatalf("got %d/%d (%.2f%%), want 8/12 (66.67%%)", dex.OwnedSpecies, dex.TotalSpecies, dex.Completion)
+		}
+		if len(dex.Missing) != 4 || dex.Missing[0].Type != "Frosty" {
+			t.Errorf("unexpected missing species: %+v", dex.Missing)
+		}
+		for _, species := range dex.Owned {
+			// Tokens 4 (held) and 13 (burned) are both Common Fire Pyros
+			if species.Type == "Pyro" && species.Rarity == "Common" && species.Owned != 1 {
+				t.Errorf("expected 1 common Pyro, got %d", species.Owned)
+			}
+		}
+	})
+
+	t.Run("GetPlayerPacks", func(t *testing.T) {
+		packs, err := repo.GetPlayerPacks(ctx, fixtures.Alice)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(packs) != 2 || packs[0].PackID != 3 {
+			t.Fatalf("expected packs 3 and 1 newest first, got %+v", packs)
+		}
+		if len(packs[0].TokenIDs) != 5 {
+			t.Errorf("expected 5 token IDs, got %v", packs[0].TokenIDs)
+		}
+	})
+
+	t.Run("GetPlayerPackSummary", func(t *testing.T) {
+		summary, err := repo.GetPlayerPackSummary(ctx, fixtures.Alice, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if summary.TotalPacks != 2 || summary.MonPacks != 2 || summary.ByPaymentType["MON"] != 2 {
+			t.Errorf("expected 2 MON packs, got %+v", summary)
+		}
+		if len(summary.RecentPacks) != 1 || summary.RecentPacks[0].PackID != 3 {
+			t.Errorf("expected only the newest pack, got %+v", summary.RecentPacks)
+		}
+		if summary.FirstPurchaseAt == nil || summary.LastPurchaseAt == nil || !summary.FirstPurchaseAt.Before(*summary.LastPurchaseAt) {
+			t.Errorf("expected first purchase before last, got %v and %v", summary.FirstPurchaseAt, summary.LastPurchaseAt)
+		}
+
+		empty, err := repo.GetPlayerPackSummary(ctx, fixtures.Carol, 5)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if empty.TotalPacks != 0 || len(empty.RecentPacks) != 0 || empty.FirstPurchaseAt != nil {
+			t.Errorf("expected an empty summary, got %+v", empty)
+		}
+	})
+
+	t.Run("GetNadmonHistory", func(t *testing.T) {
+		history, err := repo.GetNadmonHistory(ctx, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(history) != 1 || history[0].ChangeType != "evolution" {
+			t.Fatalf("unexpected history: %+v", history)
+		}
+		if history[0].OldStats.Evo != 1 || history[0].NewStats.Evo != 2 {
+			t.Errorf("unexpected evo change: %+v", history[0])
+		}
+	})
+
+	t.Run("GetFusions credits fusions with same-species burns", func(t *testing.T) {
+		fusions, err := repo.GetFusions(ctx, []int64{2, 4})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(fusions) != 1 || fusions[0].TokenID != 4 || fusions[0].FusionBefore != 0 || fusions[0].FusionAfter != 1 {
+			t.Fatalf("expected the fusion of token 4, got %+v", fusions)
+		}
+		consumed := fusions[0].Consumed
+		if len(consumed) != 1 || consumed[0].TokenID != 13 || consumed[0].NadmonType != "Pyro" || consumed[0].Stats.HP != 113 {
+			t.Errorf("expected token 13 consumed, got %+v", consumed)
+		}
+	})
+
+	t.Run("GetNadmonsByIDs skips burned tokens", func(t *testing.T) {
+		nadmons, err := repo.GetNadmonsByIDs(ctx, []int64{12, 13, 999})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(nadmons) != 1 || nadmons[0].TokenID != 12 {
+			t.Errorf("expected only token 12, got %+v", nadmons)
+		}
+	})
+
+	t.Run("GetNadmonsByIDs batches large lookups", func(t *testing.T) {
+		// 500 IDs, newest first and token 1 twice, over several batch queries
+		ids := []int64{1}
+		for id := int64(500); id >= 1; id-- {
+			ids = append(ids, id)
+		}
+		nadmons, err := repo.GetNadmonsByIDs(ctx, ids)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Tokens 1 to 15 are minted and 13 was burned
+		if len(nadmons) != 14 {
+			t.Fatalf("expected 14 nadmons, got %d", len(nadmons))
+		}
+		for i := 1; i < len(nadmons); i++ {
+			if nadmons[i].TokenID <= nadmons[i-1].TokenID {
+				t.Fatalf("expected token ID order, got %d after %d", nadmons[i].TokenID, nadmons[i-1].TokenID)
+			}
+		}
+	})
+
+	t.Run("DedupedStore shares concurrent identical batches", func(t *testing.T) {
+		deduped := NewDedupedStore(repo)
+		want, err := repo.GetNadmonsByIDs(ctx, []int64{1, 3, 6})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The same batch in any order or repetition is one key
+		batches := [][]int64{{6, 3, 1}, {1, 3, 6}, {3, 3, 1, 6, 1}}
+		results := make([][]models.Nadmon, len(batches))
+		errs := make(chan error, len(batches))
+		for i, ids := range batches {
+			go func(i int, ids []int64) {
+				var err error
+				results[i], err = deduped.GetNadmonsByIDs(ctx, ids)
+				errs <- err
+			}(i, ids)
+		}
+		for range batches {
+			if err := <-errs; err != nil {
+				t.Fatal(err)
+			}
+		}
+		for i, got := range results {
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("batch %v: got %+v, want %+v", batches[i], got, want)
+			}
+		}
+	})
+
+	t.Run("StatsRefresher serves its snapshot until the next refresh", func(t *testing.T) {
+		refresher := NewStatsRefresher(repo)
+
+		// Before the first refresh the stats are computed per request
+		live, err := refresher.GetGameStats(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if live.ComputedAt != nil || live.TotalNFTs != 14 {
+			t.Errorf("expected live stats of 14 NFTs, got %+v", live)
+		}
+
+		snapshot, err := refresher.Refresh(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if snapshot.ComputedAt == nil || snapshot.TotalNFTs != live.TotalNFTs || snapshot.TotalPacks != live.TotalPacks {
+			t.Fatalf("unexpected snapshot %+v, live %+v", snapshot, live)
+		}
+		served, err := refresher.GetGameStats(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if served != snapshot {
+			t.Errorf("expected the snapshot, got %+v", served)
+		}
+	})
+
+	t.Run("GetNadmonSnapshot pages by token ID and skips burns", func(t *testing.T) {
+		first, err := repo.GetNadmonSnapshot(ctx, -1, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(first) != 10 || first[0].TokenID != 1 || first[9].TokenID != 10 {
+			t.Fatalf("expected tokens 1-10, got %+v", first)
+		}
+		if first[2].Owner != fixtures.Carol {
+			t.Errorf("token 3 should belong to carol, got %s", first[2].Owner)
+		}
+
+		rest, err := repo.GetNadmonSnapshot(ctx, first[9].TokenID, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []int64{11, 12, 14, 15}
+		if len(rest) != len(want) {
+			t.Fatalf("got %d nadmons after token 10, want %d", len(rest), len(want))
+		}
+		for i, n := range rest {
+			if n.TokenID != want[i] {
+				t.Errorf("rest[%d].TokenID = %d, want %d", i, n.TokenID, want[i])
+			}
+		}
+	})
+
+	t.Run("GetHolderSnapshot replays transfers up to the cut-off", func(t *testing.T) {
+		before, err := repo.GetHolderSnapshot(ctx, time.Date(2025, 7, 3, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if before.TotalHolders != 2 || before.TotalSupply != 15 || before.Holders[0].Address != fixtures.Alice || before.Holders[0].Balance != 10 {
+			t.Fatalf("before the sale got %+v, want alice with 10 of 15", before)
+		}
+
+		after, err := repo.GetHolderSnapshot(ctx, time.Date(2025, 7, 5, 0, 0, 0, 0, time.UTC))
+		if err != nil {
+			t.Fatal(err)
+		}
+		balances := map[string]int{}
+		for _, holder := range after.Holders {
+			balances[holder.Address] = holder.Balance
+		}
+		if after.TotalSupply != 14 || balances[fixtures.Alice] != 8 || balances[fixtures.Bob] != 5 || balances[fixtures.Carol] != 1 {
+			t.Errorf("after the sale and burn got %v (supply %d), want alice 8, bob 5, carol 1", balances, after.TotalSupply)
+		}
+	})
+
+	t.Run("GetSingleNadmon", func(t *testing.T) {
+		nadmon, err := repo.GetSingleNadmon(ctx, 3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if nadmon == nil || nadmon.Owner != fixtures.Carol {
+			t.Fatalf("expected token 3 owned by carol, got %+v", nadmon)
+		}
+
+		burned, err := repo.GetSingleNadmon(ctx, 13)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if burned != nil {
+			t.Errorf("expected burned token to be nil, got %+v", burned)
+		}
+	})
+
+	t.Run("GetPackByID", func(t *testing.T) {
+		pack, err := repo.GetPackByID(ctx, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pack == nil || pack.Player != fixtures.Bob || len(pack.TokenIDs) != 5 {
+			t.Fatalf("unexpected pack: %+v", pack)
+		}
+
+		missing, err := repo.GetPackByID(ctx, 999)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if missing != nil {
+			t.Errorf("expected nil for missing pack, got %+v", missing)
+		}
+	})
+
+	t.Run("GetRecentPacks", func(t *testing.T) {
+		packs, err := repo.GetRecentPacks(ctx, 2)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(packs) != 2 || packs[0].PackID != 3 || packs[1].PackID != 2 {
+			t.Errorf("unexpected recent packs: %+v", packs)
+		}
+	})
+
+	t.Run("GetTopCollectors", func(t *testing.T) {
+		collectors, err := repo.GetTopCollectors(ctx, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(collectors) != 3 || collectors[0].Address != fixtures.Alice || collectors[0].TotalNFTs != 8 {
+			t.Errorf("unexpected collectors: %+v", collectors)
+		}
+	})
+
+	t.Run("SearchNadmons", func(t *testing.T) {
+		nadmons, err := repo.SearchNadmons(ctx, fixtures.Alice, map[string]interface{}{"element": "Fire"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(nadmons) != 2 {
+			t.Errorf("expected 2 fire nadmons (13 is burned), got %+v", nadmons)
+		}
+
+		evolved, err := repo.SearchNadmons(ctx, fixtures.Alice, map[string]interface{}{"evo": 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(evolved) != 1 || evolved[0].TokenID != 2 {
+			t.Errorf("expected only token 2 at evo 2, got %+v", evolved)
+		}
+	})
+
+	t.Run("SearchNadmons stat ranges and sorting", func(t *testing.T) {
+		strong, err := repo.SearchNadmons(ctx, fixtures.Alice, map[string]interface{}{
+			"min_attack": 30, "sort_by": "attack", "order": "desc",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []int64{2, 5, 4} // attack 40 (evolved), 35, 30 (fused)
+		if len(strong) != len(want) {
+			t.Fatalf("got %d nadmons, want %d: %+v", len(strong), len(want), strong)
+		}
+		for i, n := range strong {
+			if n.TokenID != want[i] {
+				t.Errorf("strong[%d].TokenID = %d, want %d", i, n.TokenID, want[i])
+			}
+		}
+
+		capped, err := repo.SearchNadmons(ctx, fixtures.Alice, map[string]interface{}{"min_attack": 30, "max_hp": 140})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(capped) != 2 || capped[0].TokenID != 4 || capped[1].TokenID != 5 {
+			t.Errorf("expected tokens 4 and 5 by token ID, got %+v", capped)
+		}
+
+		// Rarity sorts by tier rather than alphabetically, ties by token ID
+		byRarity, err := repo.SearchNadmons(ctx, fixtures.Alice, map[string]interface{}{"sort_by": "rarity", "order": "desc"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(byRarity) != 8 || byRarity[0].TokenID != 5 || byRarity[1].TokenID != 12 || byRarity[2].TokenID != 2 {
+			t.Errorf("expected epic 5 then rare 12 and 2, got %+v", byRarity)
+		}
+
+		if _, err := repo.SearchNadmons(ctx, fixtures.Alice, map[string]interface{}{"sort_by": "owner"}); err == nil {
+			t.Error("expected an error for an unsupported sort field")
+		}
+	})
+
+	t.Run("GetGameStats", func(t *testing.T) {
+		stats, err := repo.GetGameStats(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.TotalNFTs != 14 || stats.TotalPacks != 3 || stats.UniqueCollectors != 3 ||
+			stats.TotalEvolutions != 1 || stats.TotalPlayers != 2 {
+			t.Errorf("unexpected game stats: %+v", stats)
+		}
+	})
+
+	t.Run("GetPackDistribution", func(t *testing.T) {
+		dist, err := repo.GetPackDistribution(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dist.TotalPlayers != 2 || dist.TotalPacks != 3 || dist.Median != 1.5 {
+			t.Errorf("unexpected distribution: %+v", dist)
+		}
+		if dist.Buckets[0].Players != 1 || dist.Buckets[1].Players != 1 {
+			t.Errorf("unexpected buckets: %+v", dist.Buckets)
+		}
+	})
+
+	t.Run("GetOwnershipConcentration", func(t *testing.T) {
+		concentration, err := repo.GetOwnershipConcentration(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if concentration.CirculatingSupply != 14 || concentration.TotalHolders != 3 {
+			t.Errorf("unexpected concentration: %+v", concentration)
+		}
+		if concentration.TopHolderShares[0].Tokens != 8 {
+			t.Errorf("top holder should hold 8 tokens, got %+v", concentration.TopHolderShares[0])
+		}
+	})
+
+	t.Run("GetSupplyStats", func(t *testing.T) {
+		supply, err := repo.GetSupplyStats(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if supply.Minted != 15 || supply.Burned != 1 || supply.Circulating != 14 {
+			t.Errorf("unexpected supply totals: %+v", supply.SupplyCount)
+		}
+		// Token 13, the burned one, is a Common Fire Pyro
+		if fire := supply.ByElement["Fire"]; fire.Minted != 3 || fire.Burned != 1 || fire.Circulating != 2 {
+			t.Errorf("unexpected Fire supply: %+v", fire)
+		}
+		if supply.ByRarity["Common"].Burned != 1 {
+			t.Errorf("unexpected Common supply: %+v", supply.ByRarity["Common"])
+		}
+	})
+
+	t.Run("GetTraitStats", func(t *testing.T) {
+		elements, err := repo.GetTraitStats(ctx, models.DimensionElement)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if elements.Circulating != 14 || len(elements.Traits) != 8 {
+			t.Fatalf("got %d circulating in %d elements, want 14 in 8", elements.Circulating, len(elements.Traits))
+		}
+		// Water is the most common: tokens 1, 6 and 12 with 110, 108 and 118 HP
+		if water := elements.Traits[0]; water.Value != "Water" || water.Count != 3 || water.AvgHP != 112 {
+			t.Errorf("unexpected Water stats: %+v", water)
+		}
+		for _, trait := range elements.Traits {
+			// Token 2 evolved; burned token 13 is left out
+			if trait.Value == "Fire" && (trait.Count != 2 || trait.Evolved != 1 || trait.EvolutionRate != 0.5) {
+				t.Errorf("unexpected Fire stats: %+v", trait)
+			}
+		}
+
+		rarities, err := repo.GetTraitStats(ctx, models.DimensionRarity)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if common := rarities.Traits[0]; common.Value != "Common" || common.Count != 8 || common.Share != 8.0/14 {
+			t.Errorf("unexpected Common stats: %+v", common)
+		}
+	})
+
+	t.Run("GetEventTimeSeries", func(t *testing.T) {
+		from := time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC)
+		to := from.Add(48 * time.Hour)
+
+		mints, err := repo.GetEventTimeSeries(ctx, "mints", "day", from, to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(mints) != 2 || mints[0].Count != 10 || mints[1].Count != 5 {
+			t.Errorf("unexpected mint series: %+v", mints)
+		}
+
+		// Alice bought a pack on both days, Bob only on the first
+		players, err := repo.GetEventTimeSeries(ctx, models.MetricPlayers, "day", from, to)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(players) != 2 || players[0].Count != 2 || players[1].Count != 1 {
+			t.Errorf("unexpected player series: %+v", players)
+		}
+	})
+
+	t.Run("GetPackOdds", func(t *testing.T) {
+		odds, err := repo.GetPackOdds(ctx, time.Time{}, time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if odds.Packs != 3 || odds.Nadmons != 15 {
+			t.Errorf("unexpected totals: %d packs, %d nadmons", odds.Packs, odds.Nadmons)
+		}
+		// Alice's two MON packs dropped 6 Commons out of 10, Bob's COOKIES pack 3 out of 5
+		mon, cookies := odds.ByPaymentType["MON"], odds.ByPaymentType["COOKIES"]
+		if mon.Packs != 2 || mon.ByRarity["Common"].Drops != 6 || mon.ByRarity["Common"].Rate != 0.6 {
+			t.Errorf("unexpected MON odds: %+v", mon)
+		}
+		if common := cookies.ByRarity["Common"]; common.Drops != 3 || common.RateLow >= common.Rate || common.RateHigh <= common.Rate {
+			t.Errorf("unexpected COOKIES Common rate: %+v", common)
+		}
+
+		// Only the MON pack of July 2 falls in the window
+		from := time.Date(2025, 7, 2, 0, 0, 0, 0, time.UTC)
+		windowed, err := repo.GetPackOdds(ctx, from, time.Time{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if windowed.Packs != 1 || windowed.Nadmons != 5 || len(windowed.ByPaymentType) != 1 {
+			t.Errorf("unexpected windowed odds: %+v", windowed)
+		}
+	})
+
+	t.Run("GetSearchSuggestions", func(t *testing.T) {
+		suggestions, err := repo.GetSearchSuggestions(ctx, "fi", 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Token 13 is burned, so only two Fire nadmons circulate
+		if len(suggestions) != 1 || suggestions[0].Type != models.SuggestionTypeElement || suggestions[0].Circulating != 2 {
+			t.Errorf("unexpected suggestions: %+v", suggestions)
+		}
+
+		all, err := repo.GetSearchSuggestions(ctx, "", 50)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(all) == 0 {
+			t.Error("empty query should return all suggestions")
+		}
+	})
+
+	t.Run("GetNadmonStatuses", func(t *testing.T) {
+		statuses, err := repo.GetNadmonStatuses(ctx, []int64{3, 13, 999})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if statuses[3].Status != models.StatusActive {
+			t.Errorf("token 3 should be active, got %+v", statuses[3])
+		}
+		if statuses[13].Status != models.StatusBurned || statuses[13].BurnedAt == nil {
+			t.Errorf("token 13 should be burned with a timestamp, got %+v", statuses[13])
+		}
+		if statuses[999].Status != models.StatusUnknown {
+			t.Errorf("token 999 should be unknown, got %+v", statuses[999])
+		}
+	})
+
+	t.Run("GetNadmonSales", func(t *testing.T) {
+		page, err := repo.GetNadmonSales(ctx, 3, 10, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if page.Total != 1 || page.Sales[0].Buyer != fixtures.Carol || page.Sales[0].Price != "1500000000000000000" {
+			t.Errorf("token 3 should have one sale to carol, got %+v", page)
+		}
+
+		unsold, err := repo.GetNadmonSales(ctx, 1, 10, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if unsold.Total != 0 || len(unsold.Sales) != 0 {
+			t.Errorf("token 1 was never sold, got %+v", unsold)
+		}
+	})
+
+	t.Run("GetMarketStats", func(t *testing.T) {
+		market, err := repo.GetMarketStats(ctx, "", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		// The only sale is months old, so there is no floor
+		if market.Sales != 1 || market.Volume != "1500000000000000000" || market.FloorPrice != nil {
+			t.Errorf("unexpected market stats: %+v", market.MarketStats)
+		}
+		if market.LastPrice == nil || *market.LastPrice != "1500000000000000000" {
+			t.Errorf("unexpected last price: %v", market.LastPrice)
+		}
+		if nature := market.ByElement["Nature"]; nature.Sales != 1 || market.ByRarity["Common"].Sales != 1 {
+			t.Errorf("the sale should count for Common and Nature, got %+v", market)
+		}
+
+		rare, err := repo.GetMarketStats(ctx, "Rare", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rare.Sales != 0 || rare.Volume != "0" || rare.LastPrice != nil || len(rare.ByRarity) != 0 {
+			t.Errorf("no Rare was sold, got %+v", rare)
+		}
+	})
+
+	t.Run("GetNadmonTransfers", func(t *testing.T) {
+		page, err := repo.GetNadmonTransfers(ctx, 3, 10, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if page.Total != 2 || len(page.Transfers) != 2 {
+			t.Fatalf("token 3 should have a mint and a sale, got %+v", page)
+		}
+		if page.Transfers[0].Kind != models.TransferKindTransfer || page.Transfers[0].To != fixtures.Carol {
+			t.Errorf("newest transfer should be the sale to carol, got %+v", page.Transfers[0])
+		}
+		if page.Transfers[1].Kind != models.TransferKindMint {
+			t.Errorf("oldest transfer should be the mint, got %+v", page.Transfers[1])
+		}
+
+		second, err := repo.GetNadmonTransfers(ctx, 3, 1, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if second.Total != 2 || len(second.Transfers) != 1 || second.Transfers[0].Kind != models.TransferKindMint {
+			t.Errorf("second page should hold the mint only, got %+v", second)
+		}
+
+		burned, err := repo.GetNadmonTransfers(ctx, 13, 10, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(burned.Transfers) == 0 || burned.Transfers[0].Kind != models.TransferKindBurn {
+			t.Errorf("token 13 should end with a burn, got %+v", burned)
+		}
+	})
+
+	t.Run("GetPlayerTransfers", func(t *testing.T) {
+		page, err := repo.GetPlayerTransfers(ctx, fixtures.Carol, 10, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if page.Total != 1 || len(page.Transfers) != 1 || page.Transfers[0].TokenID != 3 {
+			t.Errorf("carol should have received token 3 only, got %+v", page)
+		}
+	})
+
+	t.Run("GetLeaderboard", func(t *testing.T) {
+		rarity, err := repo.GetLeaderboard(ctx, models.LeaderboardRarityScore, fixtures.Carol, 2, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rarity.TotalPlayers != 3 || len(rarity.Entries) != 2 {
+			t.Fatalf("expected 2 of 3 ranked players, got %+v", rarity)
+		}
+		// bob holds the legendary (30 points), alice has more but lower-tier NFTs (26)
+		if rarity.Entries[0].Address != fixtures.Bob || rarity.Entries[0].Score != 30 || rarity.Entries[1].Score != 26 {
+			t.Errorf("unexpected rarity ranking: %+v", rarity.Entries)
+		}
+		if rarity.Player == nil || rarity.Player.Rank != 3 || rarity.Player.Score != 1 {
+			t.Errorf("expected carol at rank 3, got %+v", rarity.Player)
+		}
+
+		packs, err := repo.GetLeaderboard(ctx, models.LeaderboardPacks, fixtures.Carol, 10, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(packs.Entries) != 2 || packs.Entries[0].Address != fixtures.Alice || packs.Entries[0].Score != 2 {
+			t.Errorf("unexpected pack ranking: %+v", packs.Entries)
+		}
+		if packs.Player != nil {
+			t.Errorf("carol bought no packs and should be unranked, got %+v", packs.Player)
+		}
+
+		for _, kind := range []string{models.LeaderboardEvolutions, models.LeaderboardFusion} {
+			board, err := repo.GetLeaderboard(ctx, kind, "", 10, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(board.Entries) != 1 || board.Entries[0].Address != fixtures.Alice || board.Entries[0].Score != 1 {
+				t.Errorf("expected alice alone on the %s leaderboard, got %+v", kind, board.Entries)
+			}
+		}
+
+		if _, err := repo.GetLeaderboard(ctx, "trades", "", 10, 0); err == nil {
+			t.Error("expected an error for an unknown leaderboard")
+		}
+	})
+
+	t.Run("GetActivity", func(t *testing.T) {
+		page, err := repo.GetActivity(ctx, models.ActivityTypes, 2, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if page.Total != 22 {
+			t.Errorf("expected 15 mints, 2 transfers, 2 stat changes and 3 packs, got %d", page.Total)
+		}
+		if len(page.Activities) != 2 || page.Activities[0].ID != "transfer-13-burn" || page.Activities[1].ID != "stats-4-fusion" {
+			t.Fatalf("unexpected newest activity: %+v", page.Activities)
+		}
+		if page.Activities[0].Detail != models.TransferKindBurn || page.Activities[1].Detail != "fusion" {
+			t.Errorf("unexpected activity details: %+v", page.Activities)
+		}
+
+		packs, err := repo.GetActivity(ctx, []string{models.ActivityPack}, 10, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if packs.Total != 3 || len(packs.Activities) != 3 || packs.Activities[0].PackID != 3 {
+			t.Errorf("expected 3 packs, newest first, got %+v", packs)
+		}
+	})
+
+	t.Run("GetPlayerActivity", func(t *testing.T) {
+		page, err := repo.GetPlayerActivity(ctx, fixtures.Alice, models.PlayerActivityTypes, 2, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if page.Total != 16 {
+			t.Errorf("expected 10 mints, 2 transfers out, 2 stat changes and 2 packs, got %d", page.Total)
+		}
+		if len(page.Activities) != 2 || page.Activities[0].Type != models.ActivityTransferOut || page.Activities[1].ID != "stats-4-fusion" {
+			t.Fatalf("unexpected newest activity: %+v", page.Activities)
+		}
+		if page.Activities[0].Detail != models.TransferKindBurn {
+			t.Errorf("expected the burn first, got %+v", page.Activities[0])
+		}
+
+		received, err := repo.GetPlayerActivity(ctx, fixtures.Carol, []string{models.ActivityTransferIn, models.ActivityEvolution}, 10, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if received.Total != 1 || len(received.Activities) != 1 || received.Activities[0].Counterparty != fixtures.Alice {
+			t.Errorf("expected carol's purchase of token 3 from alice, got %+v", received)
+		}
+	})
+
+	t.Run("ExcludedAddresses", func(t *testing.T) {
+		excluding := NewNadmonRepository(repo.db)
+		excluding.SetExcludedAddresses([]string{"0x" + strings.ToUpper(fixtures.Alice[2:])})
+
+		collectors, err := excluding.GetTopCollectors(ctx, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, collector := range collectors {
+			if strings.EqualFold(collector.Address, fixtures.Alice) {
+				t.Errorf("excluded address should not appear on the leaderboard: %+v", collectors)
+			}
+		}
+
+		stats, err := excluding.GetGameStats(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.UniqueCollectors != 2 {
+			t.Errorf("expected 2 collectors without alice, got %d", stats.UniqueCollectors)
+		}
+
+		concentration, err := excluding.GetOwnershipConcentration(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if concentration.TotalHolders != 2 || concentration.CirculatingSupply != 6 {
+			t.Errorf("unexpected concentration without alice: %+v", concentration)
+		}
+	})
+}
+
+func TestCurrentState(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+
+	// Reads through the CTEs before the table exists, to compare against
+	before, err := repo.GetPlayerNadmons(ctx, fixtures.Alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	beforeStats, err := repo.GetGameStats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.db.SetupCurrentState(); err != nil {
+		t.Fatal(err)
+	}
+	if !repo.db.CurrentState {
+		t.Fatal("expected current-state reads to be enabled after setup")
+	}
+
+	t.Run("backfill matches the CTE reads", func(t *testing.T) {
+		after, err := repo.GetPlayerNadmons(ctx, fixtures.Alice)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(after) != len(before) {
+			t.Fatalf("got %d nadmons, want %d", len(after), len(before))
+		}
+		for i := range after {
+			a, b := after[i], before[i]
+			if a.TokenID != b.TokenID || a.Owner != b.Owner || a.HP != b.HP || a.Evo != b.Evo {
+				t.Errorf("nadmons[%d] = %+v, want %+v", i, a, b)
+			}
+		}
+
+		stats, err := repo.GetGameStats(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.TotalNFTs != beforeStats.TotalNFTs || stats.UniqueCollectors != beforeStats.UniqueCollectors {
+			t.Errorf("stats = %+v, want %+v", stats, beforeStats)
+		}
+
+		results, err := repo.SearchNadmons(ctx, fixtures.Alice, map[string]interface{}{"evo": 2})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(results) != 1 || results[0].TokenID != 2 {
+			t.Errorf("expected evolved token 2 from the state table, got %+v", results)
+		}
+	})
+
+	t.Run("sync applies new transfers", func(t *testing.T) {
+		_, err := repo.db.DB.Exec(`INSERT INTO "NadmonNFT_Transfer" (id, "from", "to", "tokenId", db_write_timestamp)
+			VALUES ('transfer-1-gift', $1, $2, 1, '2025-07-05 09:00:00')`, fixtures.Alice, fixtures.Bob)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := repo.db.SyncCurrentState(); err != nil {
+			t.Fatal(err)
+		}
+
+		nadmon, err := repo.GetSingleNadmon(ctx, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if nadmon == nil || nadmon.Owner != fixtures.Bob {
+			t.Errorf("token 1 should now belong to bob, got %+v", nadmon)
+		}
+	})
+}
+
+func TestCollections(t *testing.T) {
+	ctx := context.Background()
+	envioDB := testharness.StartEnvioDB(t)
+
+	// A sibling collection with one item minted to bob
+	if _, err := envioDB.DB.Exec(strings.ReplaceAll(fixtures.Schema, `"NadmonNFT_`, `"NadmonItems_`)); err != nil {
+		t.Fatal(err)
+	}
+	_, err := envioDB.DB.Exec(`INSERT INTO "NadmonItems_NadmonMinted" (id, owner, "tokenId", "packId", sequence,
+		"nadmonType", element, rarity, hp, attack, defense, crit, fusion, evo, db_write_timestamp)
+		VALUES ('item-1', $1, 1, 1, 1, 'Potion', 'Water', 'Common', 10, 0, 0, 0, 0, 1, '2025-07-05 10:00:00')`, fixtures.Bob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = envioDB.DB.Exec(`INSERT INTO "NadmonItems_Transfer" (id, "from", "to", "tokenId", db_write_timestamp)
+		VALUES ('item-transfer-1', $1, $2, 1, '2025-07-05 10:00:00')`, fixtures.ZeroAddress, fixtures.Bob)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The default collection's current-state table must not leak into other collections
+	if err := envioDB.SetupCurrentState(); err != nil {
+		t.Fatal(err)
+	}
+
+	items := NewCollectionRepository(envioDB, envioDB.DB, "NadmonItems")
+
+	bob, err := items.GetPlayerNadmons(ctx, fixtures.Bob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bob) != 1 || bob[0].NadmonType != "Potion" {
+		t.Errorf("expected bob's potion, got %+v", bob)
+	}
+
+	alice, err := items.GetPlayerNadmons(ctx, fixtures.Alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alice) != 0 {
+		t.Errorf("alice owns no items, got %+v", alice)
+	}
+
+	stats, err := items.GetGameStats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.TotalNFTs != 1 || stats.UniqueCollectors != 1 {
+		t.Errorf("unexpected item stats: %+v", stats)
+	}
+
+	for _, entries := range [][]string{{"items"}, {"items=Bad-Prefix"}, {"items=A", "items=B"}} {
+		if _, err := ParseCollections(entries); err == nil {
+			t.Errorf("expected ParseCollections(%q) to fail", entries)
+		}
+	}
+}
+
+func TestMicroCachedStore(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestRepository(t)
+	store := NewMicroCachedStore(repo, 10, time.Minute)
+
+	before, err := store.GetSingleNadmon(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statsBefore, err := store.GetGameStats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = repo.db.DB.Exec(`INSERT INTO "NadmonNFT_Transfer" (id, "from", "to", "tokenId", db_write_timestamp)
+		VALUES ('transfer-1-burn', $1, $2, 1, '2025-07-05 09:00:00')`, fixtures.Alice, models.ZeroAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("serves cached results until invalidated", func(t *testing.T) {
+		cached, err := store.GetSingleNadmon(ctx, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cached == nil || cached.Owner != before.Owner {
+			t.Errorf("expected the cached token 1, got %+v", cached)
+		}
+
+		store.Invalidate([]int64{1})
+		burned, err := store.GetSingleNadmon(ctx, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if burned != nil {
+			t.Errorf("token 1 was burned, got %+v", burned)
+		}
+
+		stats, err := store.GetGameStats(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.TotalNFTs != statsBefore.TotalNFTs-1 {
+			t.Errorf("TotalNFTs = %d, want %d after the burn", stats.TotalNFTs, statsBefore.TotalNFTs-1)
+		}
+	})
+
+	t.Run("Bypass skips the cache", func(t *testing.T) {
+		if store.Bypass() != Store(repo) {
+			t.Error("expected Bypass to return the repository")
+		}
+	})
+}
+
+func TestReplicaStore(t *testing.T) {
+	ctx := context.Background()
+	primary := newTestRepository(t)
+	replica := newTestRepository(t)
+	store := WithReplica(primary, replica)
+
+	// The replica lags behind a burn of token 1
+	_, err := primary.db.DB.Exec(`INSERT INTO "NadmonNFT_Transfer" (id, "from", "to", "tokenId", db_write_timestamp)
+		VALUES ('transfer-1-burn', $1, $2, 1, '2025-07-05 09:00:00')`, fixtures.Alice, models.ZeroAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+	primaryStats, err := primary.GetGameStats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replicaStats, err := replica.GetGameStats(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("analytics read from the replica", func(t *testing.T) {
+		stats, err := store.GetGameStats(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.TotalNFTs != replicaStats.TotalNFTs {
+			t.Errorf("TotalNFTs = %d, want the replica's %d", stats.TotalNFTs, replicaStats.TotalNFTs)
+		}
+	})
+
+	t.Run("lookups read from the primary", func(t *testing.T) {
+		nadmon, err := store.GetSingleNadmon(ctx, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if nadmon != nil {
+			t.Errorf("token 1 was burned on the primary, got %+v", nadmon)
+		}
+	})
+
+	t.Run("PreferReplica moves player reads to the replica", func(t *testing.T) {
+		held := func(ctx context.Context) bool {
+			nadmons, err := store.GetPlayerNadmons(ctx, fixtures.Alice)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, n := range nadmons {
+				if n.TokenID == 1 {
+					return true
+				}
+			}
+			return false
+		}
+		if held(ctx) {
+			t.Error("expected the primary's inventory without token 1")
+		}
+		if !held(PreferReplica(ctx)) {
+			t.Error("expected the replica's inventory with token 1")
+		}
+	})
+
+	t.Run("falls back to the primary when the replica fails", func(t *testing.T) {
+		if err := replica.db.Close(); err != nil {
+			t.Fatal(err)
+		}
+		stats, err := store.GetGameStats(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if stats.TotalNFTs != primaryStats.TotalNFTs {
+			t.Errorf("TotalNFTs = %d, want the primary's %d", stats.TotalNFTs, primaryStats.TotalNFTs)
+		}
+	})
+}
+
+func TestProfileRepository(t *testing.T) {
+	ctx := context.Background()
+	db := testharness.StartEnvioDB(t)
+	if err := db.SetupAppSchema(); err != nil {
+		t.Fatal(err)
+	}
+	profiles := NewProfileRepository(db.DB)
+
+	nickname, avatar := "Ash", int64(5)
+	saved, err := profiles.SaveDisplayProfile(ctx, models.DisplayProfile{Address: strings.ToUpper(fixtures.Alice[:2]) + fixtures.Alice[2:], Nickname: &nickname, AvatarTokenID: &avatar, Bio: "Fire main"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.Address != fixtures.Alice || saved.UpdatedAt.IsZero() {
+		t.Errorf("expected a saved profile for %s, got %+v", fixtures.Alice, saved)
+	}
+
+	// Nicknames are unique ignoring case
+	taken := "ASH"
+	if _, err := profiles.SaveDisplayProfile(ctx, models.DisplayProfile{Address: fixtures.Bob, Nickname: &taken}); err != ErrNicknameTaken {
+		t.Errorf("expected ErrNicknameTaken, got %v", err)
+	}
+
+	// Saving again replaces the profile, clearing omitted fields
+	if _, err := profiles.SaveDisplayProfile(ctx, models.DisplayProfile{Address: fixtures.Alice, Nickname: &nickname}); err != nil {
+		t.Fatal(err)
+	}
+	found, err := profiles.GetDisplayProfiles(ctx, []string{fixtures.Alice, fixtures.Bob})
+	if err != nil {
+		t.Fatal(err)
+	}
+	alice, ok := found[fixtures.Alice]
+	if len(found) != 1 || !ok {
+		t.Fatalf("expected only Alice's profile, got %v", found)
+	}
+	if alice.Nickname == nil || *alice.Nickname != "Ash" || alice.AvatarTokenID != nil || alice.Bio != "" {
+		t.Errorf("expected the replaced profile, got %+v", alice)
+	}
+}
+
+func TestTeamRepository(t *testing.T) {
+	ctx := context.Background()
+	db := testharness.StartEnvioDB(t)
+	if err := db.SetupAppSchema(); err != nil {
+		t.Fatal(err)
+	}
+	teams := NewTeamRepository(db.DB)
+
+	saved, err := teams.CreateTeam(ctx, models.Team{Address: strings.ToUpper(fixtures.Alice[:2]) + fixtures.Alice[2:], Name: "Fire squad", TokenIDs: []int64{1, 2, 5}}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.ID == 0 || saved.Address != fixtures.Alice || saved.CreatedAt.IsZero() {
+		t.Errorf("expected a saved team for %s, got %+v", fixtures.Alice, saved)
+	}
+
+	// Names are unique per player ignoring case, but other players may reuse them
+	if _, err := teams.CreateTeam(ctx, models.Team{Address: fixtures.Alice, Name: "FIRE SQUAD", TokenIDs: []int64{4}}, 2); err != ErrTeamNameTaken {
+		t.Errorf("expected ErrTeamNameTaken, got %v", err)
+	}
+	if _, err := teams.CreateTeam(ctx, models.Team{Address: fixtures.Bob, Name: "Fire squad", TokenIDs: []int64{6}}, 2); err != nil {
+		t.Errorf("expected Bob to reuse the name, got %v", err)
+	}
+
+	if _, err := teams.CreateTeam(ctx, models.Team{Address: fixtures.Alice, Name: "Backup", TokenIDs: []int64{4}}, 2); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := teams.CreateTeam(ctx, models.Team{Address: fixtures.Alice, Name: "One too many", TokenIDs: []int64{11}}, 2); err != ErrTeamLimit {
+		t.Errorf("expected ErrTeamLimit, got %v", err)
+	}
+
+	found, err := teams.GetTeams(ctx, fixtures.Alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) != 2 || found[0].Name != "Fire squad" || len(found[0].TokenIDs) != 3 || found[1].Name != "Backup" {
+		t.Fatalf("expected Alice's two teams oldest first, got %+v", found)
+	}
+
+	// Players can only delete their own teams
+	if deleted, err := teams.DeleteTeam(ctx, fixtures.Bob, saved.ID); err != nil || deleted {
+		t.Errorf("expected Bob not to delete Alice's team, got %v, %v", deleted, err)
+	}
+	if deleted, err := teams.DeleteTeam(ctx, fixtures.Alice, saved.ID); err != nil || !deleted {
+		t.Errorf("expected the team to be deleted, got %v, %v", deleted, err)
+	}
+	if found, _ := teams.GetTeams(ctx, fixtures.Alice); len(found) != 1 {
+		t.Errorf("expected one team left, got %+v", found)
+	}
+}
+
+func TestWebhookRepository(t *testing.T) {
+	ctx := context.Background()
+	db := testharness.StartEnvioDB(t)
+	if err := db.SetupAppSchema(); err != nil {
+		t.Fatal(err)
+	}
+	webhooks := NewWebhookRepository(db.DB)
+
+	owned := models.Webhook{URL: "https://bot.example.com/hook", Owner: strings.ToUpper(fixtures.Alice[:2]) + fixtures.Alice[2:], Events: []string{models.WebhookEventPack}, Addresses: []string{strings.ToUpper(fixtures.Bob[:2]) + fixtures.Bob[2:]}, Secret: "s1"}
+	saved, err := webhooks.CreateWebhook(ctx, owned, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.ID == 0 || saved.Owner != fixtures.Alice || saved.Addresses[0] != fixtures.Bob {
+		t.Errorf("expected a saved webhook with normalized addresses, got %+v", saved)
+	}
+
+	// Wallets are limited, the admin is not
+	if _, err := webhooks.CreateWebhook(ctx, owned, 1); err != ErrWebhookLimit {
+		t.Errorf("expected ErrWebhookLimit, got %v", err)
+	}
+	admin := models.Webhook{URL: "http://bot.internal/hook", Events: models.WebhookEvents, Secret: "s2"}
+	for i := 0; i < 2; i++ {
+		if _, err := webhooks.CreateWebhook(ctx, admin, 1); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	all, err := webhooks.GetWebhooks(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mine, err := webhooks.GetOwnerWebhooks(ctx, fixtures.Alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 || len(mine) != 1 || mine[0].Secret != "s1" || len(all[1].Addresses) != 0 {
+		t.Fatalf("expected 3 webhooks, 1 of them Alice's, got %+v and %+v", all, mine)
+	}
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		delivery := models.WebhookDelivery{WebhookID: saved.ID, DeliveryID: "d1", Event: models.WebhookEventPack, Attempt: attempt, StatusCode: 500, Error: "unexpected status 500"}
+		if err := webhooks.RecordDelivery(ctx, delivery); err != nil {
+			t.Fatal(err)
+		}
+	}
+	deliveries, err := webhooks.GetDeliveries(ctx, saved.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deliveries) != 1 || deliveries[0].Attempt != 2 {
+		t.Errorf("expected the latest attempt, got %+v", deliveries)
+	}
+	if pruned, err := webhooks.PruneDeliveries(ctx, time.Now().Add(time.Hour)); err != nil || pruned != 2 {
+		t.Errorf("expected 2 pruned attempts, got %d, %v", pruned, err)
+	}
+
+	// Deleting a webhook drops it and its log; deliveries still in flight aren't recorded
+	if found, err := webhooks.DeleteWebhook(ctx, saved.ID); err != nil || !found {
+		t.Fatalf("expected the webhook to be deleted, got %v, %v", found, err)
+	}
+	if err := webhooks.RecordDelivery(ctx, models.WebhookDelivery{WebhookID: saved.ID, DeliveryID: "d2", Event: models.WebhookEventPack, Attempt: 1}); err != nil {
+		t.Errorf("expected late deliveries to be ignored, got %v", err)
+	}
+	if webhook, err := webhooks.GetWebhook(ctx, saved.ID); err != nil || webhook != nil {
+		t.Errorf("expected no webhook, got %+v, %v", webhook, err)
+	}
+}
+
+func TestRarityRepository(t *testing.T) {
+	ctx := context.Background()
+	db := testharness.StartEnvioDB(t)
+	if err := db.SetupAppSchema(); err != nil {
+		t.Fatal(err)
+	}
+	rarity := NewRarityRepository(db.DB)
+
+	// Refreshing twice replaces the ranking
+	for i := 0; i < 2; i++ {
+		ranked, err := rarity.Refresh(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ranked != 14 {
+			t.Fatalf("expected the 14 circulating NFTs to be ranked, got %d", ranked)
+		}
+	}
+
+	rarest, total, err := rarity.GetRarestNFTs(ctx, 20, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 14 || len(rarest) != 14 || rarest[0].Rank != 1 {
+		t.Fatalf("expected 14 NFTs from rank 1, got %d of %d", len(rarest), total)
+	}
+	for i := 1; i < len(rarest); i++ {
+		if rarest[i].Score > rarest[i-1].Score || rarest[i].Rank < rarest[i-1].Rank {
+			t.Errorf("expected the rarest first, got %+v before %+v", rarest[i-1], rarest[i])
+		}
+	}
+
+	first := rarest[0]
+	if len(first.Traits) != 4 || first.TopPercent <= 0 || first.TopPercent > 100 || first.CombinationCount < 1 {
+		t.Errorf("expected traits and shares, got %+v", first)
+	}
+	// The score is the sum of the inverse trait frequencies
+	score := 0.0
+	for _, trait := range first.Traits {
+		score += 1 / trait.Frequency
+	}
+	if diff := score - first.Score; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("expected score %f from the trait frequencies, got %f", score, first.Score)
+	}
+
+	// The burned token 13 isn't ranked
+	if burned, err := rarity.GetNFTRarity(ctx, 13); err != nil || burned != nil {
+		t.Errorf("expected no rank for a burned NFT, got %+v, %v", burned, err)
+	}
+	if found, err := rarity.GetNFTRarity(ctx, first.TokenID); err != nil || found == nil || found.Rank != first.Rank {
+		t.Errorf("expected token %d at rank %d, got %+v, %v", first.TokenID, first.Rank, found, err)
+	}
+}
+
+func TestLeaderboardRepository(t *testing.T) {
+	ctx := context.Background()
+	db := testharness.StartEnvioDB(t)
+	if err := db.SetupAppSchema(); err != nil {
+		t.Fatal(err)
+	}
+	standings := NewLeaderboardRepository(db.DB, nil)
+
+	if ranked, err := standings.Refresh(ctx); err != nil || ranked != 3 {
+		t.Fatalf("expected 3 ranked collectors, got %d, %v", ranked, err)
+	}
+	first, err := standings.GetCollectorRank(ctx, fixtures.Alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first == nil || first.Rank != 1 || first.NFTCount != 8 || first.TotalPlayers != 3 || first.PreviousRank != nil || first.RankDelta != nil {
+		t.Fatalf("expected alice first with no previous snapshot, got %+v", first)
+	}
+
+	// Pretend alice was third with one NFT last time, and a former collector has since sold out
+	if _, err := db.DB.ExecContext(ctx, `
+		UPDATE `+database.AppSchema+`.collector_ranks SET rank = 3, nft_count = 1 WHERE address = $1
+	`, fixtures.Alice); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := db.DB.ExecContext(ctx, `
+		INSERT INTO `+database.AppSchema+`.collector_ranks (address, nft_count, rank, snapshot_at)
+		VALUES ('0x000000000000000000000000000000000000dead', 1, 4, NOW())
+	`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := standings.Refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	rank, err := standings.GetCollectorRank(ctx, "0x"+strings.ToUpper(fixtures.Alice[2:]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rank == nil || rank.Rank != 1 || rank.PreviousRank == nil || *rank.PreviousRank != 3 ||
+		*rank.PreviousNFTCount != 1 || *rank.RankDelta != 2 || rank.TotalPlayers != 3 {
+		t.Errorf("expected alice to have climbed from 3rd to 1st, got %+v", rank)
+	}
+	if gone, err := standings.GetCollectorRank(ctx, "0x000000000000000000000000000000000000dead"); err != nil || gone != nil {
+		t.Errorf("expected the former collector to drop out, got %+v, %v", gone, err)
+	}
+
+	top, err := standings.GetTopCollectors(ctx, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(top) != 2 || top[0].Address != fixtures.Alice || top[0].TotalNFTs != 8 || top[1].Address != fixtures.Bob {
+		t.Errorf("unexpected top collectors: %+v", top)
+	}
+
+	excluding := NewLeaderboardRepository(db.DB, []string{"0x" + strings.ToUpper(fixtures.Alice[2:])})
+	if ranked, err := excluding.Refresh(ctx); err != nil || ranked != 2 {
+		t.Fatalf("expected 2 ranked collectors without alice, got %d, %v", ranked, err)
+	}
+	if excluded, err := excluding.GetCollectorRank(ctx, fixtures.Alice); err != nil || excluded != nil {
+		t.Errorf("expected no rank for an excluded address, got %+v, %v", excluded, err)
+	}
+}
+
+func TestPlayerStatsRepository(t *testing.T) {
+	ctx := context.Background()
+	db := testharness.StartEnvioDB(t)
+	if err := db.SetupAppSchema(); err != nil {
+		t.Fatal(err)
+	}
+	playerStats := NewPlayerStatsRepository(db.DB)
+
+	if players, err := playerStats.Backfill(ctx); err != nil || players != 3 {
+		t.Fatalf("expected 3 players, got %d, %v", players, err)
+	}
+	alice, err := playerStats.GetPlayerStats(ctx, "0x"+strings.ToUpper(fixtures.Alice[2:]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alice == nil || alice.TotalNFTs != 8 || alice.PacksBought != 2 || alice.EvolutionsPerformed != 1 || alice.EvolvedNFTs != 1 {
+		t.Fatalf("unexpected stats for alice: %+v", alice)
+	}
+	if alice.RarityCounts["Common"] != 4 || alice.RarityCounts["Rare"] != 2 || alice.ElementCounts["Fire"] != 2 ||
+		alice.RarestOwned == nil || alice.RarestOwned.TokenID != 5 || alice.RarestOwned.Rarity != "Epic" {
+		t.Errorf("unexpected breakdown for alice: %+v", alice)
+	}
+
+	// Alice gives her epic away; only the two players involved are recomputed
+	if _, err := db.DB.ExecContext(ctx, `
+		INSERT INTO "NadmonNFT_Transfer" (id, "from", "to", "tokenId", db_write_timestamp)
+		VALUES ('transfer-5-gift', $1, $2, 5, '2025-07-05 10:00:00')
+	`, fixtures.Alice, fixtures.Carol); err != nil {
+		t.Fatal(err)
+	}
+	if err := playerStats.Refresh(ctx, []string{fixtures.Alice, fixtures.Carol}); err != nil {
+		t.Fatal(err)
+	}
+	alice, err = playerStats.GetPlayerStats(ctx, fixtures.Alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if alice.TotalNFTs != 7 || alice.RarestOwned.TokenID != 2 || alice.RarestOwned.Rarity != "Rare" {
+		t.Errorf("expected alice down to 7 NFTs with token 2 the rarest, got %+v", alice)
+	}
+	carol, err := playerStats.GetPlayerStats(ctx, fixtures.Carol)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if carol.TotalNFTs != 2 || carol.PacksBought != 0 || carol.RarestOwned.TokenID != 5 {
+		t.Errorf("expected carol to hold 2 NFTs with token 5 the rarest, got %+v", carol)
+	}
+
+	if unknown, err := playerStats.GetPlayerStats(ctx, "0x000000000000000000000000000000000000dead"); err != nil || unknown != nil {
+		t.Errorf("expected no stats for an unknown player, got %+v, %v", unknown, err)
+	}
+}
+
+func TestBattleRepository(t *testing.T) {
+	ctx := context.Background()
+	db := testharness.StartEnvioDB(t)
+	if err := db.SetupAppSchema(); err != nil {
+		t.Fatal(err)
+	}
+	battles := NewBattleRepository(db.DB)
+
+	day := time.Date(2025, 7, 1, 12, 0, 0, 0, time.UTC)
+	alice := "0x" + strings.ToUpper(fixtures.Alice[2:])
+	win := models.Battle{
+		BattleID: "b-1",
+		Player1:  models.BattleSide{Address: alice, TeamTokenIDs: []int64{1, 2}},
+		Player2:  models.BattleSide{Address: fixtures.Bob, TeamTokenIDs: []int64{6}},
+		Winner:   &alice,
+		FoughtAt: day,
+	}
+	recorded, created, err := battles.RecordBattle(ctx, win)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Even ratings move by half the K-factor
+	if !created || recorded.Player1.Address != fixtures.Alice || *recorded.Winner != fixtures.Alice ||
+		recorded.Player1.RatingBefore != 1200 || recorded.Player1.RatingAfter != 1216 || recorded.Player2.RatingAfter != 1184 {
+		t.Errorf("expected alice to win 16 points from bob, got %+v", recorded)
+	}
+
+	// Draws move the higher rating down
+	draw := models.Battle{
+		BattleID: "b-2",
+		Player1:  models.BattleSide{Address: fixtures.Alice, TeamTokenIDs: []int64{}},
+		Player2:  models.BattleSide{Address: fixtures.Carol, TeamTokenIDs: []int64{3}},
+		FoughtAt: day.Add(time.Hour),
+	}
+	recorded, _, err = battles.RecordBattle(ctx, draw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if recorded.Winner != nil || recorded.Player1.RatingAfter != 1215 || recorded.Player2.RatingAfter != 1201 {
+		t.Errorf("expected a draw moving one point from alice to carol, got %+v", recorded)
+	}
+
+	// Reporting a battle again returns it as recorded and leaves ratings alone
+	win.Winner = &win.Player2.Address
+	recorded, created, err = battles.RecordBattle(ctx, win)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created || *recorded.Winner != fixtures.Alice || recorded.Player1.RatingAfter != 1216 {
+		t.Errorf("expected the stored battle, got created %v, %+v", created, recorded)
+	}
+
+	page, err := battles.GetPlayerBattles(ctx, alice, 10, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if page.Total != 2 || len(page.Battles) != 2 || page.Battles[0].BattleID != "b-2" ||
+		page.Battles[0].Result != models.BattleResultDraw || page.Battles[1].Result != models.BattleResultWin {
+		t.Errorf("expected alice's draw then win, got %+v", page)
+	}
+	if page, _ := battles.GetPlayerBattles(ctx, fixtures.Bob, 10, 0); page.Total != 1 || page.Battles[0].Result != models.BattleResultLoss {
+		t.Errorf("expected bob's loss, got %+v", page)
+	}
+
+	record, err := battles.GetPvPRecord(ctx, fixtures.Alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record == nil || record.Rank != 1 || record.Rating != 1215 || record.Wins != 1 || record.Draws != 1 ||
+		record.Battles != 2 || !record.LastBattleAt.Equal(day.Add(time.Hour)) {
+		t.Errorf("expected alice first at 1215 with a win and a draw, got %+v", record)
+	}
+	if record, err := battles.GetPvPRecord(ctx, "0x1111111111111111111111111111111111111111"); err != nil || record != nil {
+		t.Errorf("expected no record for a player without battles, got %+v, %v", record, err)
+	}
+
+	leaderboard, total, err := battles.GetPvPLeaderboard(ctx, 2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 3 || len(leaderboard) != 2 || leaderboard[0].Address != fixtures.Carol || leaderboard[1].Address != fixtures.Bob ||
+		leaderboard[1].Rank != 3 || leaderboard[1].Losses != 1 {
+		t.Errorf("expected carol then bob after alice, got %d %+v", total, leaderboard)
+	}
+}
+
+func TestAPIKeyRepository(t *testing.T) {
+	ctx := context.Background()
+	db := testharness.StartEnvioDB(t)
+	if err := db.SetupAppSchema(); err != nil {
+		t.Fatal(err)
+	}
+	keys := NewAPIKeyRepository(db.DB)
+
+	saved, err := keys.CreateAPIKey(ctx, models.APIKey{Name: "marketplace", Prefix: "nk_1234abcd", Scopes: []string{models.APIKeyScopeRead}, RateLimit: 20, Burst: 60, DailyQuota: 1000}, "hash-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.ID == 0 || saved.CreatedAt.IsZero() {
+		t.Fatalf("expected a saved key, got %+v", saved)
+	}
+
+	today := time.Now().UTC()
+	if err := keys.RecordAPIKeyUsage(ctx, saved.ID, today.Format("2006-01-02"), 10, 2, today); err != nil {
+		t.Fatal(err)
+	}
+	if err := keys.RecordAPIKeyUsage(ctx, saved.ID, today.Format("2006-01-02"), 5, 0, today); err != nil {
+		t.Fatal(err)
+	}
+	if err := keys.RecordAPIKeyUsage(ctx, saved.ID, today.AddDate(0, 0, -3).Format("2006-01-02"), 7, 1, today.AddDate(0, 0, -3)); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := keys.GetAPIKeyByHash(ctx, "hash-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found == nil || found.ID != saved.ID || found.Usage.Today != 15 || found.LastUsedAt == nil || !found.Active() {
+		t.Fatalf("expected the key with 15 requests today, got %+v", found)
+	}
+	if missing, err := keys.GetAPIKeyByHash(ctx, "hash-2"); err != nil || missing != nil {
+		t.Errorf("expected no key for an unknown hash, got %+v, %v", missing, err)
+	}
+
+	usage, err := keys.GetAPIKeyUsage(ctx, saved.ID, 30)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(usage) != 2 || usage[0].Requests != 15 || usage[0].Rejected != 2 || usage[1].Requests != 7 {
+		t.Errorf("expected 2 days of usage, newest first, got %+v", usage)
+	}
+	if recent, err := keys.GetAPIKeyUsage(ctx, saved.ID, 1); err != nil || len(recent) != 1 {
+		t.Errorf("expected only today's usage, got %+v, %v", recent, err)
+	}
+
+	if found, err := keys.RevokeAPIKey(ctx, saved.ID); err != nil || !found {
+		t.Fatalf("expected the key to be revoked, got %v, %v", found, err)
+	}
+	if found, err := keys.RevokeAPIKey(ctx, saved.ID+1); err != nil || found {
+		t.Errorf("expected an unknown key not to be found, got %v, %v", found, err)
+	}
+
+	all, err := keys.GetAPIKeys(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 1 || all[0].Active() || all[0].Usage.Today != 15 || all[0].Usage.Last30Days != 22 || all[0].Usage.Rejected30 != 3 {
+		t.Errorf("expected the revoked key with its usage, got %+v", all)
+	}
+}
+
+func TestAuditRepository(t *testing.T) {
+	ctx := context.Background()
+	db := testharness.StartEnvioDB(t)
+	if err := db.SetupAppSchema(); err != nil {
+		t.Fatal(err)
+	}
+	keys := NewAPIKeyRepository(db.DB)
+	audit := NewAuditRepository(db.DB)
+
+	key, err := keys.CreateAPIKey(ctx, models.APIKey{Name: "marketplace", Prefix: "nk_1234abcd", Scopes: []string{models.APIKeyScopeRead}, RateLimit: 20, Burst: 60}, "hash-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().UTC()
+	entries := []models.AuditEntry{
+		{Address: fixtures.Alice, APIKeyID: key.ID, ClientIP: "10.0.0.1", RemoteAddr: "10.0.0.1:51234", Method: "GET", Route: "/api/players/:address/nadmons", Status: 200, RequestID: "req-1", RequestedAt: now.Add(-48 * time.Hour)},
+		{Address: fixtures.Alice, ClientIP: "10.0.0.2", Method: "GET", Route: "/api/players/:address/profile", Status: 200, RequestedAt: now.Add(-time.Hour)},
+		{Address: fixtures.Bob, APIKeyID: key.ID, ClientIP: "10.0.0.1", Method: "GET", Route: "/api/players/:address/packs", Status: 429, RequestedAt: now},
+	}
+	if err := audit.RecordAuditEntries(ctx, entries); err != nil {
+		t.Fatal(err)
+	}
+
+	all, err := audit.GetAuditEntries(ctx, AuditFilter{}, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 3 || all[0].Address != fixtures.Bob || all[0].APIKeyName != "marketplace" || all[0].Status != 429 {
+		t.Fatalf("expected 3 entries, newest first with the key name, got %+v", all)
+	}
+	if all[1].APIKeyID != 0 || all[1].APIKeyName != "" || all[2].RequestID != "req-1" || all[2].RemoteAddr != "10.0.0.1:51234" {
+		t.Errorf("expected the unkeyed entry without a key, got %+v", all)
+	}
+
+	for name, tc := range map[string]struct {
+		filter AuditFilter
+		want   int
+	}{
+		"address":  {AuditFilter{Address: fixtures.Alice}, 2},
+		"api key":  {AuditFilter{APIKeyID: key.ID}, 2},
+		"ip":       {AuditFilter{ClientIP: "10.0.0.2"}, 1},
+		"from":     {AuditFilter{From: now.Add(-2 * time.Hour)}, 2},
+		"to":       {AuditFilter{To: now.Add(-24 * time.Hour)}, 1},
+		"combined": {AuditFilter{Address: fixtures.Alice, APIKeyID: key.ID}, 1},
+		"before":   {AuditFilter{BeforeID: all[1].ID}, 1},
+	} {
+		found, err := audit.GetAuditEntries(ctx, tc.filter, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(found) != tc.want {
+			t.Errorf("%s: expected %d entries, got %+v", name, tc.want, found)
+		}
+	}
+
+	pruned, err := audit.PruneAuditEntries(ctx, now.Add(-24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pruned != 1 {
+		t.Errorf("expected the 2 day old entry pruned, got %d", pruned)
+	}
+}
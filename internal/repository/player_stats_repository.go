@@ -0,0 +1,203 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"nadmon-backend/internal/database"
+	"nadmon-backend/internal/ethaddr"
+	"nadmon-backend/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// PlayerStatsStore reads the per-player aggregates
+type PlayerStatsStore interface {
+	// GetPlayerStats returns a player's aggregates, or nil when they have none yet
+	GetPlayerStats(ctx context.Context, address string) (*models.PlayerStats, error)
+}
+
+// PlayerStatsRepository keeps per-player aggregates of the default collection in the
+// backend-owned schema: a backfill computes every player, after which the event pipeline
+// recomputes only the players each batch of new rows touched
+type PlayerStatsRepository struct {
+	db *sql.DB
+}
+
+// NewPlayerStatsRepository creates a player stats repository; the schema must have been set
+// up with EnvioDB.SetupAppSchema
+func NewPlayerStatsRepository(db *sql.DB) *PlayerStatsRepository {
+	return &PlayerStatsRepository{db: db}
+}
+
+// playerStatsQuery recomputes the aggregates of the players in $1, or of every player when $1
+// is NULL. Only tokens ever minted or transferred to them are read, so updating a handful of
+// players stays cheap. Evolutions are credited to the token's owner at the time, like the
+// evolutions leaderboard.
+const playerStatsQuery = `
+	INSERT INTO ` + database.AppSchema + `.player_stats (address, nft_count, packs_bought,
+		evolutions_performed, evolved_nfts, rarity_counts, element_counts, rarest_token_id,
+		rarest_rarity, last_active, updated_at)
+	WITH targets AS (
+		SELECT DISTINCT address FROM (
+			SELECT LOWER(a) AS address FROM unnest($1::text[]) AS a
+			UNION ALL
+			SELECT LOWER(m.owner) FROM "NadmonNFT_NadmonMinted" m WHERE $1::text[] IS NULL
+			UNION ALL
+			SELECT LOWER(t."to") FROM "NadmonNFT_Transfer" t WHERE $1::text[] IS NULL
+			UNION ALL
+			SELECT LOWER(p.player) FROM "NadmonNFT_PackMinted" p WHERE $1::text[] IS NULL
+		) addresses
+		WHERE address != '0x0000000000000000000000000000000000000000'
+	),
+	candidates AS (
+		SELECT m."tokenId" FROM "NadmonNFT_NadmonMinted" m WHERE LOWER(m.owner) IN (SELECT address FROM targets)
+		UNION
+		SELECT t."tokenId" FROM "NadmonNFT_Transfer" t WHERE LOWER(t."to") IN (SELECT address FROM targets)
+	),
+	current_owners AS (
+		SELECT DISTINCT ON (t."tokenId") t."tokenId", t."to" AS current_owner
+		FROM "NadmonNFT_Transfer" t
+		WHERE t."tokenId" IN (SELECT "tokenId" FROM candidates)
+		ORDER BY t."tokenId", t.db_write_timestamp DESC
+	),
+	latest_stats AS (
+		SELECT DISTINCT ON (s."tokenId") s."tokenId", s."newEvo", s.db_write_timestamp
+		FROM "NadmonNFT_StatsChanged" s
+		WHERE s."tokenId" IN (SELECT "tokenId" FROM candidates)
+		ORDER BY s."tokenId", s.sequence DESC
+	),
+	held AS (
+		SELECT DISTINCT ON (m."tokenId")
+			LOWER(COALESCE(co.current_owner, m.owner)) AS address, m."tokenId"::bigint AS token_id,
+			m.element, m.rarity, COALESCE(ls."newEvo", m.evo) AS evo, ls.db_write_timestamp AS changed_at
+		FROM "NadmonNFT_NadmonMinted" m
+		LEFT JOIN current_owners co ON m."tokenId" = co."tokenId"
+		LEFT JOIN latest_stats ls ON m."tokenId" = ls."tokenId"
+		WHERE m."tokenId" IN (SELECT "tokenId" FROM candidates)
+			AND LOWER(COALESCE(co.current_owner, m.owner)) IN (SELECT address FROM targets)
+		ORDER BY m."tokenId"
+	),
+	holdings AS (
+		SELECT address, COUNT(*) AS nft_count, COUNT(*) FILTER (WHERE evo > 1) AS evolved_nfts,
+			MAX(changed_at) AS changed_at
+		FROM held
+		GROUP BY address
+	),
+	rarity_counts AS (
+		SELECT address, jsonb_object_agg(rarity, n) AS counts
+		FROM (SELECT address, rarity, COUNT(*) AS n FROM held GROUP BY address, rarity) c
+		GROUP BY address
+	),
+	element_counts AS (
+		SELECT address, jsonb_object_agg(element, n) AS counts
+		FROM (SELECT address, element, COUNT(*) AS n FROM held GROUP BY address, element) c
+		GROUP BY address
+	),
+	rarest AS (
+		SELECT DISTINCT ON (address) address, token_id, rarity
+		FROM held
+		ORDER BY address, array_position(ARRAY['Common', 'Uncommon', 'Rare', 'Epic', 'Legendary'], rarity) DESC NULLS LAST, token_id
+	),
+	packs AS (
+		SELECT LOWER(p.player) AS address, COUNT(*) AS packs_bought, MAX(p.db_write_timestamp) AS bought_at
+		FROM "NadmonNFT_PackMinted" p
+		WHERE LOWER(p.player) IN (SELECT address FROM targets)
+		GROUP BY LOWER(p.player)
+	),
+	evolutions AS (
+		SELECT LOWER(o.owner) AS address, COUNT(*) AS evolutions
+		FROM "NadmonNFT_StatsChanged" s
+		CROSS JOIN LATERAL (
+			SELECT t."to" AS owner
+			FROM "NadmonNFT_Transfer" t
+			WHERE t."tokenId" = s."tokenId" AND t.db_write_timestamp <= s.db_write_timestamp
+			ORDER BY t.db_write_timestamp DESC
+			LIMIT 1
+		) o
+		WHERE s."changeType" = 'evolution'
+			AND s."tokenId" IN (SELECT "tokenId" FROM candidates)
+			AND LOWER(o.owner) IN (SELECT address FROM targets)
+		GROUP BY LOWER(o.owner)
+	)
+	SELECT t.address, COALESCE(h.nft_count, 0), COALESCE(p.packs_bought, 0), COALESCE(e.evolutions, 0),
+		COALESCE(h.evolved_nfts, 0), COALESCE(rc.counts, '{}'::jsonb), COALESCE(ec.counts, '{}'::jsonb),
+		r.token_id, r.rarity, GREATEST(p.bought_at, h.changed_at)::timestamp, NOW()
+	FROM targets t
+	LEFT JOIN holdings h ON h.address = t.address
+	LEFT JOIN rarity_counts rc ON rc.address = t.address
+	LEFT JOIN element_counts ec ON ec.address = t.address
+	LEFT JOIN rarest r ON r.address = t.address
+	LEFT JOIN packs p ON p.address = t.address
+	LEFT JOIN evolutions e ON e.address = t.address
+	ON CONFLICT (address) DO UPDATE SET
+		nft_count = EXCLUDED.nft_count,
+		packs_bought = EXCLUDED.packs_bought,
+		evolutions_performed = EXCLUDED.evolutions_performed,
+		evolved_nfts = EXCLUDED.evolved_nfts,
+		rarity_counts = EXCLUDED.rarity_counts,
+		element_counts = EXCLUDED.element_counts,
+		rarest_token_id = EXCLUDED.rarest_token_id,
+		rarest_rarity = EXCLUDED.rarest_rarity,
+		last_active = EXCLUDED.last_active,
+		updated_at = EXCLUDED.updated_at
+`
+
+// Backfill recomputes the aggregates of every player, catching up on rows indexed while the
+// backend was down; it returns the number of players
+func (r *PlayerStatsRepository) Backfill(ctx context.Context) (int64, error) {
+	result, err := r.db.ExecContext(ctx, playerStatsQuery, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to backfill player stats: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+// Refresh recomputes the aggregates of the given players
+func (r *PlayerStatsRepository) Refresh(ctx context.Context, addresses []string) error {
+	if len(addresses) == 0 {
+		return nil
+	}
+	if _, err := r.db.ExecContext(ctx, playerStatsQuery, pq.Array(addresses)); err != nil {
+		return fmt.Errorf("failed to refresh player stats: %w", err)
+	}
+	return nil
+}
+
+func (r *PlayerStatsRepository) GetPlayerStats(ctx context.Context, address string) (*models.PlayerStats, error) {
+	var stats models.PlayerStats
+	var rarityCounts, elementCounts []byte
+	var rarestTokenID sql.NullInt64
+	var rarestRarity sql.NullString
+	var lastActive sql.NullTime
+	err := r.db.QueryRowContext(ctx, `
+		SELECT address, nft_count, packs_bought, evolutions_performed, evolved_nfts, rarity_counts,
+			element_counts, rarest_token_id, rarest_rarity, last_active, updated_at
+		FROM `+database.AppSchema+`.player_stats
+		WHERE address = $1
+	`, ethaddr.Normalize(address)).Scan(&stats.Address, &stats.TotalNFTs, &stats.PacksBought,
+		&stats.EvolutionsPerformed, &stats.EvolvedNFTs, &rarityCounts, &elementCounts,
+		&rarestTokenID, &rarestRarity, &lastActive, &stats.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query player stats: %w", err)
+	}
+
+	if err := json.Unmarshal(rarityCounts, &stats.RarityCounts); err != nil {
+		return nil, fmt.Errorf("failed to decode rarity counts: %w", err)
+	}
+	if err := json.Unmarshal(elementCounts, &stats.ElementCounts); err != nil {
+		return nil, fmt.Errorf("failed to decode element counts: %w", err)
+	}
+	if rarestTokenID.Valid {
+		stats.RarestOwned = &models.RarestOwned{TokenID: rarestTokenID.Int64, Rarity: rarestRarity.String}
+	}
+	if lastActive.Valid {
+		stats.LastActive = lastActive.Time
+	}
+	return &stats, nil
+}
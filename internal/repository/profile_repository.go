@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"nadmon-backend/internal/database"
+	"nadmon-backend/internal/ethaddr"
+	"nadmon-backend/internal/models"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+)
+
+// ErrNicknameTaken is returned when another player already uses a nickname (case-insensitive)
+var ErrNicknameTaken = errors.New("nickname is already taken")
+
+// ProfileStore reads and writes players' display profiles
+type ProfileStore interface {
+	// GetDisplayProfiles returns the display profiles of the given players that have one,
+	// keyed by lowercased address
+	GetDisplayProfiles(ctx context.Context, addresses []string) (map[string]models.DisplayProfile, error)
+	// SaveDisplayProfile creates or replaces a player's display profile
+	SaveDisplayProfile(ctx context.Context, profile models.DisplayProfile) (*models.DisplayProfile, error)
+}
+
+// ProfileRepository stores display profiles in the backend-owned schema
+type ProfileRepository struct {
+	db *sql.DB
+}
+
+// NewProfileRepository creates a profile repository; the schema must have been set up with
+// EnvioDB.SetupAppSchema
+func NewProfileRepository(db *sql.DB) *ProfileRepository {
+	return &ProfileRepository{db: db}
+}
+
+func (r *ProfileRepository) GetDisplayProfiles(ctx context.Context, addresses []string) (map[string]models.DisplayProfile, error) {
+	profiles := make(map[string]models.DisplayProfile)
+	if len(addresses) == 0 {
+		return profiles, nil
+	}
+
+	normalized := make([]string, len(addresses))
+	for i, address := range addresses {
+		normalized[i] = ethaddr.Normalize(address)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT address, nickname, avatar_token_id, bio, updated_at
+		FROM `+database.AppSchema+`.player_profiles
+		WHERE address = ANY($1)
+	`, pq.Array(normalized))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query display profiles: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var profile models.DisplayProfile
+		var nickname sql.NullString
+		var avatar sql.NullInt64
+		if err := rows.Scan(&profile.Address, &nickname, &avatar, &profile.Bio, &profile.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan display profile: %w", err)
+		}
+		if nickname.Valid {
+			profile.Nickname = &nickname.String
+		}
+		if avatar.Valid {
+			profile.AvatarTokenID = &avatar.Int64
+		}
+		profiles[profile.Address] = profile
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read display profiles: %w", err)
+	}
+	return profiles, nil
+}
+
+func (r *ProfileRepository) SaveDisplayProfile(ctx context.Context, profile models.DisplayProfile) (*models.DisplayProfile, error) {
+	profile.Address = ethaddr.Normalize(profile.Address)
+
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO `+database.AppSchema+`.player_profiles (address, nickname, avatar_token_id, bio, updated_at)
+		VALUES ($1, $2, $3, $4, NOW())
+		ON CONFLICT (address) DO UPDATE SET
+			nickname = EXCLUDED.nickname,
+			avatar_token_id = EXCLUDED.avatar_token_id,
+			bio = EXCLUDED.bio,
+			updated_at = EXCLUDED.updated_at
+		RETURNING updated_at
+	`, profile.Address, profile.Nickname, profile.AvatarTokenID, profile.Bio).Scan(&profile.UpdatedAt)
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" { // unique_violation
+		return nil, ErrNicknameTaken
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save display profile: %w", err)
+	}
+	return &profile, nil
+}
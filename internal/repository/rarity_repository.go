@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"nadmon-backend/internal/database"
+	"nadmon-backend/internal/models"
+)
+
+// RarityStore reads the precomputed rarity scores of circulating Nadmons
+type RarityStore interface {
+	// GetNFTRarity returns a Nadmon's score and rank, or nil when it isn't ranked (unknown,
+	// burned or minted since the last refresh)
+	GetNFTRarity(ctx context.Context, tokenID int64) (*models.NFTRarity, error)
+	// GetRarestNFTs returns a page of Nadmons from the rarest down, with the number ranked
+	GetRarestNFTs(ctx context.Context, limit, offset int) ([]models.NFTRarity, int, error)
+}
+
+// RarityRepository computes rarity scores into the backend-owned schema
+type RarityRepository struct {
+	db *sql.DB
+}
+
+// NewRarityRepository creates a rarity repository; the schema must have been set up with
+// EnvioDB.SetupAppSchema
+func NewRarityRepository(db *sql.DB) *RarityRepository {
+	return &RarityRepository{db: db}
+}
+
+// rarityRefreshQuery scores every circulating Nadmon of the default collection from the current
+// trait counts: type, element and rarity from the mint, evolution stage from the latest stats
+const rarityRefreshQuery = `
+	INSERT INTO ` + database.AppSchema + `.nft_rarity (token_id, nadmon_type, element, rarity, evo,
+		type_count, element_count, rarity_count, evo_count, combination_count, total, score, rank, computed_at)
+	WITH current_owners AS (
+		SELECT DISTINCT ON (t."tokenId") t."tokenId", t."to" AS current_owner
+		FROM "NadmonNFT_Transfer" t
+		ORDER BY t."tokenId", t.db_write_timestamp DESC
+	),
+	latest_stats AS (
+		SELECT DISTINCT ON (s."tokenId") s."tokenId", s."newEvo"
+		FROM "NadmonNFT_StatsChanged" s
+		ORDER BY s."tokenId", s.sequence DESC
+	),
+	circulating AS (
+		SELECT DISTINCT ON (m."tokenId")
+			m."tokenId"::bigint AS token_id, m."nadmonType" AS nadmon_type, m.element, m.rarity,
+			COALESCE(ls."newEvo", m.evo)::bigint AS evo
+		FROM "NadmonNFT_NadmonMinted" m
+		LEFT JOIN current_owners co ON m."tokenId" = co."tokenId"
+		LEFT JOIN latest_stats ls ON m."tokenId" = ls."tokenId"
+		WHERE COALESCE(co.current_owner, m.owner) != '0x0000000000000000000000000000000000000000'
+		ORDER BY m."tokenId"
+	),
+	counted AS (
+		SELECT c.*,
+			COUNT(*) OVER (PARTITION BY nadmon_type) AS type_count,
+			COUNT(*) OVER (PARTITION BY element) AS element_count,
+			COUNT(*) OVER (PARTITION BY rarity) AS rarity_count,
+			COUNT(*) OVER (PARTITION BY evo) AS evo_count,
+			COUNT(*) OVER (PARTITION BY nadmon_type, element, rarity, evo) AS combination_count,
+			COUNT(*) OVER () AS total
+		FROM circulating c
+	),
+	scored AS (
+		SELECT counted.*,
+			total::float8 / type_count + total::float8 / element_count
+				+ total::float8 / rarity_count + total::float8 / evo_count AS score
+		FROM counted
+	)
+	SELECT token_id, nadmon_type, element, rarity, evo,
+		type_count, element_count, rarity_count, evo_count, combination_count, total, score,
+		RANK() OVER (ORDER BY score DESC), NOW()
+	FROM scored
+`
+
+// Refresh recomputes every score and rank in one transaction, so readers see either the old
+// or the new ranking; it returns the number of ranked Nadmons
+func (r *RarityRepository) Refresh(ctx context.Context) (int64, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin rarity refresh: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM `+database.AppSchema+`.nft_rarity`); err != nil {
+		return 0, fmt.Errorf("failed to clear rarity scores: %w", err)
+	}
+	result, err := tx.ExecContext(ctx, rarityRefreshQuery)
+	if err != nil {
+		return 0, fmt.Errorf("failed to compute rarity scores: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit rarity scores: %w", err)
+	}
+	return result.RowsAffected()
+}
+
+const rarityColumns = `token_id, nadmon_type, element, rarity, evo, type_count, element_count, rarity_count,
+	evo_count, combination_count, total, score, rank, computed_at`
+
+func (r *RarityRepository) GetNFTRarity(ctx context.Context, tokenID int64) (*models.NFTRarity, error) {
+	rarities, err := r.queryRarities(ctx, `
+		SELECT `+rarityColumns+` FROM `+database.AppSchema+`.nft_rarity
+		WHERE token_id = $1
+	`, tokenID)
+	if err != nil || len(rarities) == 0 {
+		return nil, err
+	}
+	return &rarities[0], nil
+}
+
+func (r *RarityRepository) GetRarestNFTs(ctx context.Context, limit, offset int) ([]models.NFTRarity, int, error) {
+	rarities, err := r.queryRarities(ctx, `
+		SELECT `+rarityColumns+` FROM `+database.AppSchema+`.nft_rarity
+		ORDER BY rank, token_id
+		LIMIT $1 OFFSET $2
+	`, limit, offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM `+database.AppSchema+`.nft_rarity`).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count rarity scores: %w", err)
+	}
+	return rarities, total, nil
+}
+
+// queryRarities runs a query selecting rarityColumns
+func (r *RarityRepository) queryRarities(ctx context.Context, query string, args ...interface{}) ([]models.NFTRarity, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rarity scores: %w", err)
+	}
+	defer rows.Close()
+
+	rarities := []models.NFTRarity{}
+	for rows.Next() {
+		var rarity models.NFTRarity
+		var nadmonType, element, rarityName string
+		var evo, typeCount, elementCount, rarityCount, evoCount int64
+		if err := rows.Scan(&rarity.TokenID, &nadmonType, &element, &rarityName, &evo,
+			&typeCount, &elementCount, &rarityCount, &evoCount, &rarity.CombinationCount,
+			&rarity.Total, &rarity.Score, &rarity.Rank, &rarity.ComputedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan rarity score: %w", err)
+		}
+
+		rarity.TopPercent = 100 * float64(rarity.Rank) / float64(rarity.Total)
+		trait := func(name, value string, count int64) models.TraitRarity {
+			return models.TraitRarity{Trait: name, Value: value, Count: count, Frequency: float64(count) / float64(rarity.Total)}
+		}
+		rarity.Traits = []models.TraitRarity{
+			trait("type", nadmonType, typeCount),
+			trait("element", element, elementCount),
+			trait("rarity", rarityName, rarityCount),
+			trait("evo", strconv.FormatInt(evo, 10), evoCount),
+		}
+		rarities = append(rarities, rarity)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read rarity scores: %w", err)
+	}
+	return rarities, nil
+}
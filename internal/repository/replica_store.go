@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"nadmon-backend/internal/models"
+)
+
+// ReplicaStore sends heavy reads (leaderboards, analytics, global search and exports) to a
+// read replica and everything else, inventory and NFT lookups in particular, to the primary
+// Store. Reads failing on the replica are retried on the primary, so an unreachable replica
+// slows them down but doesn't fail them.
+type ReplicaStore struct {
+	Store
+	replica Store
+}
+
+// WithReplica returns a Store routing heavy reads to replica
+func WithReplica(primary, replica Store) *ReplicaStore {
+	return &ReplicaStore{Store: primary, replica: replica}
+}
+
+type preferReplicaKey struct{}
+
+// PreferReplica marks ctx so every read made with it goes to the replica, for requests such
+// as exports that read player data in bulk
+func PreferReplica(ctx context.Context) context.Context {
+	return context.WithValue(ctx, preferReplicaKey{}, true)
+}
+
+// prefersReplica reports whether ctx was marked by PreferReplica
+func prefersReplica(ctx context.Context) bool {
+	prefer, _ := ctx.Value(preferReplicaKey{}).(bool)
+	return prefer
+}
+
+// onReplica runs read against the replica, then against the primary if it fails while ctx
+// is still live
+func onReplica[T any](ctx context.Context, s *ReplicaStore, method string, read func(Store) (T, error)) (T, error) {
+	result, err := read(s.replica)
+	if err == nil || ctx.Err() != nil {
+		return result, err
+	}
+	log.Printf("Warning: %s failed on the read replica, using the primary: %v", method, err)
+	return read(s.Store)
+}
+
+// Bulk player reads, on the replica for requests marked by PreferReplica
+
+func (s *ReplicaStore) GetPlayerNadmons(ctx context.Context, address string) ([]models.Nadmon, error) {
+	if !prefersReplica(ctx) {
+		return s.Store.GetPlayerNadmons(ctx, address)
+	}
+	return onReplica(ctx, s, "GetPlayerNadmons", func(store Store) ([]models.Nadmon, error) {
+		return store.GetPlayerNadmons(ctx, address)
+	})
+}
+
+func (s *ReplicaStore) GetPlayerActivity(ctx context.Context, address string, types []string, limit, offset int) (*models.ActivityPage, error) {
+	if !prefersReplica(ctx) {
+		return s.Store.GetPlayerActivity(ctx, address, types, limit, offset)
+	}
+	return onReplica(ctx, s, "GetPlayerActivity", func(store Store) (*models.ActivityPage, error) {
+		return store.GetPlayerActivity(ctx, address, types, limit, offset)
+	})
+}
+
+// Search
+
+func (s *ReplicaStore) SearchAllNadmons(ctx context.Context, filters map[string]interface{}, after *SearchCursor, limit int) (*models.NadmonSearchPage, error) {
+	return onReplica(ctx, s, "SearchAllNadmons", func(store Store) (*models.NadmonSearchPage, error) {
+		return store.SearchAllNadmons(ctx, filters, after, limit)
+	})
+}
+
+func (s *ReplicaStore) GetSearchSuggestions(ctx context.Context, query string, limit int) ([]models.SearchSuggestion, error) {
+	return onReplica(ctx, s, "GetSearchSuggestions", func(store Store) ([]models.SearchSuggestion, error) {
+		return store.GetSearchSuggestions(ctx, query, limit)
+	})
+}
+
+// Exports
+
+func (s *ReplicaStore) GetNadmonSnapshot(ctx context.Context, afterTokenID int64, limit int) ([]models.Nadmon, error) {
+	return onReplica(ctx, s, "GetNadmonSnapshot", func(store Store) ([]models.Nadmon, error) {
+		return store.GetNadmonSnapshot(ctx, afterTokenID, limit)
+	})
+}
+
+func (s *ReplicaStore) GetHolderSnapshot(ctx context.Context, asOf time.Time) (*models.HolderSnapshot, error) {
+	return onReplica(ctx, s, "GetHolderSnapshot", func(store Store) (*models.HolderSnapshot, error) {
+		return store.GetHolderSnapshot(ctx, asOf)
+	})
+}
+
+// Leaderboards
+
+func (s *ReplicaStore) GetTopCollectors(ctx context.Context, limit int) ([]models.PlayerProfile, error) {
+	return onReplica(ctx, s, "GetTopCollectors", func(store Store) ([]models.PlayerProfile, error) {
+		return store.GetTopCollectors(ctx, limit)
+	})
+}
+
+func (s *ReplicaStore) GetLeaderboard(ctx context.Context, kind, player string, limit, offset int) (*models.Leaderboard, error) {
+	return onReplica(ctx, s, "GetLeaderboard", func(store Store) (*models.Leaderboard, error) {
+		return store.GetLeaderboard(ctx, kind, player, limit, offset)
+	})
+}
+
+// Analytics
+
+func (s *ReplicaStore) GetMarketStats(ctx context.Context, rarity, element string) (*models.MarketReport, error) {
+	return onReplica(ctx, s, "GetMarketStats", func(store Store) (*models.MarketReport, error) {
+		return store.GetMarketStats(ctx, rarity, element)
+	})
+}
+
+func (s *ReplicaStore) GetGameStats(ctx context.Context) (*models.GameStats, error) {
+	return onReplica(ctx, s, "GetGameStats", func(store Store) (*models.GameStats, error) {
+		return store.GetGameStats(ctx)
+	})
+}
+
+func (s *ReplicaStore) GetPackDistribution(ctx context.Context) (*models.PackDistribution, error) {
+	return onReplica(ctx, s, "GetPackDistribution", func(store Store) (*models.PackDistribution, error) {
+		return store.GetPackDistribution(ctx)
+	})
+}
+
+func (s *ReplicaStore) GetOwnershipConcentration(ctx context.Context) (*models.OwnershipConcentration, error) {
+	return onReplica(ctx, s, "GetOwnershipConcentration", func(store Store) (*models.OwnershipConcentration, error) {
+		return store.GetOwnershipConcentration(ctx)
+	})
+}
+
+func (s *ReplicaStore) GetSupplyStats(ctx context.Context) (*models.SupplyStats, error) {
+	return onReplica(ctx, s, "GetSupplyStats", func(store Store) (*models.SupplyStats, error) {
+		return store.GetSupplyStats(ctx)
+	})
+}
+
+func (s *ReplicaStore) GetTraitStats(ctx context.Context, dimension string) (*models.TraitBreakdown, error) {
+	return onReplica(ctx, s, "GetTraitStats", func(store Store) (*models.TraitBreakdown, error) {
+		return store.GetTraitStats(ctx, dimension)
+	})
+}
+
+func (s *ReplicaStore) GetPackOdds(ctx context.Context, from, to time.Time) (*models.PackOddsReport, error) {
+	return onReplica(ctx, s, "GetPackOdds", func(store Store) (*models.PackOddsReport, error) {
+		return store.GetPackOdds(ctx, from, to)
+	})
+}
+
+func (s *ReplicaStore) GetEventTimeSeries(ctx context.Context, metric, interval string, from, to time.Time) ([]models.TimeSeriesPoint, error) {
+	return onReplica(ctx, s, "GetEventTimeSeries", func(store Store) ([]models.TimeSeriesPoint, error) {
+		return store.GetEventTimeSeries(ctx, metric, interval, from, to)
+	})
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"nadmon-backend/internal/database/envio"
+	"nadmon-backend/internal/models"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrMarketplaceUnavailable is returned by sale queries when the marketplace contract is not
+// indexed (yet) or the collection has no marketplace
+var ErrMarketplaceUnavailable = errors.New("marketplace sales are not indexed")
+
+// FloorWindow is how far back sales count towards the floor price
+const FloorWindow = 7 * 24 * time.Hour
+
+// marketplaceError maps a missing sale table to ErrMarketplaceUnavailable
+func marketplaceError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "42P01" { // undefined_table
+		return ErrMarketplaceUnavailable
+	}
+	return err
+}
+
+// GetNadmonSales retrieves a page of an NFT's marketplace sales, newest first
+func (r *NadmonRepository) GetNadmonSales(ctx context.Context, tokenID int64, limit, offset int) (*models.SalePage, error) {
+	// The marketplace only trades the default collection
+	if r.prefix != DefaultTablePrefix {
+		return nil, ErrMarketplaceUnavailable
+	}
+
+	total, err := r.queries.CountNadmonSales(ctx, tokenID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count nadmon sales: %w", marketplaceError(err))
+	}
+
+	rows, err := r.queries.GetNadmonSales(ctx, envio.GetNadmonSalesParams{
+		TokenID:    tokenID,
+		MaxResults: int32(limit),
+		Skip:       int32(offset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query nadmon sales: %w", marketplaceError(err))
+	}
+
+	sales := make([]models.Sale, 0, len(rows))
+	for _, row := range rows {
+		sales = append(sales, models.Sale{
+			ID:      row.ID,
+			TokenID: row.TokenID,
+			Seller:  row.Seller,
+			Buyer:   row.Buyer,
+			Price:   row.Price,
+			SoldAt:  row.SoldAt.Time,
+		})
+	}
+
+	return &models.SalePage{Sales: sales, Total: int(total)}, nil
+}
+
+// GetMarketStats computes sale count, volume, floor and last sale overall and per rarity and
+// element, optionally limited to one rarity and/or element (empty means any)
+func (r *NadmonRepository) GetMarketStats(ctx context.Context, rarity, element string) (*models.MarketReport, error) {
+	if r.prefix != DefaultTablePrefix {
+		return nil, ErrMarketplaceUnavailable
+	}
+
+	rows, err := r.queries.GetMarketStats(ctx, envio.GetMarketStatsParams{
+		Rarity:     rarity,
+		Element:    element,
+		FloorSince: time.Now().Add(-FloorWindow),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query market stats: %w", marketplaceError(err))
+	}
+
+	report := &models.MarketReport{
+		MarketStats: models.MarketStats{Volume: "0"},
+		FloorWindow: FloorWindow.String(),
+		ByRarity:    map[string]models.MarketStats{},
+		ByElement:   map[string]models.MarketStats{},
+	}
+	for _, row := range rows {
+		stats := models.MarketStats{Sales: int(row.Sales), Volume: row.Volume}
+		// Copy the values: row is reused by the next iteration
+		if floor := row.FloorPrice; floor.Valid {
+			stats.FloorPrice = &floor.String
+		}
+		if last := row.LastPrice; last.Valid {
+			stats.LastPrice = &last.String
+		}
+		if soldAt := row.LastSoldAt; soldAt.Valid {
+			stats.LastSoldAt = &soldAt.Time
+		}
+
+		switch row.Dimension {
+		case "rarity":
+			report.ByRarity[row.Value] = stats
+		case "element":
+			report.ByElement[row.Value] = stats
+		default:
+			report.MarketStats = stats
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"nadmon-backend/internal/models"
+)
+
+// ErrInvalidCursor is returned by DecodeSearchCursor for cursors it did not issue
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// SearchCursor is the keyset position of a collection-wide search: the sort key and token ID
+// of the last result of the previous page
+type SearchCursor struct {
+	Key     int64
+	TokenID int64
+}
+
+// Encode returns the opaque form of the cursor handed to clients
+func (c SearchCursor) Encode() string {
+	return base64.RawURLEncoding.EncodeToString([]byte(fmt.Sprintf("%d:%d", c.Key, c.TokenID)))
+}
+
+// DecodeSearchCursor parses a cursor returned by Encode
+func DecodeSearchCursor(encoded string) (*SearchCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	key, tokenID, ok := strings.Cut(string(raw), ":")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+
+	var cursor SearchCursor
+	if cursor.Key, err = strconv.ParseInt(key, 10, 64); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	if cursor.TokenID, err = strconv.ParseInt(tokenID, 10, 64); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &cursor, nil
+}
+
+// SearchSortKey returns the keyset value of nadmon for a SearchSortFields field: the stat,
+// the rarity rank from Common (1) to Legendary (5), the mint time in microseconds or the
+// token ID
+func SearchSortKey(nadmon models.Nadmon, sortBy string) int64 {
+	switch sortBy {
+	case SortByRarity:
+		switch nadmon.Rarity {
+		case "Legendary":
+			return 5
+		case "Epic":
+			return 4
+		case "Rare":
+			return 3
+		case "Uncommon":
+			return 2
+		default:
+			return 1
+		}
+	case SortByCreatedAt:
+		return nadmon.CreatedAt.UnixMicro()
+	case "hp":
+		return nadmon.HP
+	case "attack":
+		return nadmon.Attack
+	case "defense":
+		return nadmon.Defense
+	case "crit":
+		return nadmon.Crit
+	case "fusion":
+		return nadmon.Fusion
+	case "evo":
+		return nadmon.Evo
+	default:
+		return nadmon.TokenID
+	}
+}
+
+// NewSearchPage trims a page read with one extra row down to limit results, setting
+// NextCursor to the last kept result when the extra row shows more follow
+func NewSearchPage(page *models.NadmonSearchPage, sortBy string, limit int) *models.NadmonSearchPage {
+	if len(page.Nadmons) > limit {
+		page.Nadmons = page.Nadmons[:limit]
+		last := page.Nadmons[limit-1]
+		page.NextCursor = SearchCursor{Key: SearchSortKey(last, sortBy), TokenID: last.TokenID}.Encode()
+	}
+	return page
+}
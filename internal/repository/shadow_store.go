@@ -0,0 +1,363 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"nadmon-backend/internal/logging"
+	"nadmon-backend/internal/models"
+)
+
+// maxShadowInFlight bounds concurrent shadow reads so sampling never piles up goroutines
+const maxShadowInFlight = 16
+
+// ShadowStore serves every call from the primary Store and, for a sampled share of calls,
+// replays the same call against a candidate Store in the background and compares results.
+// It is used to validate query rewrites before cutting over.
+type ShadowStore struct {
+	Store
+	candidate  Store
+	sampleRate float64
+	inFlight   chan struct{}
+
+	compared atomic.Int64
+	diverged atomic.Int64
+	errors   atomic.Int64
+	mu       sync.Mutex
+	byMethod map[string]int64
+}
+
+// NewShadowStore wraps primary with shadow reads against candidate for sampleRate (0..1) of calls
+func NewShadowStore(primary, candidate Store, sampleRate float64) *ShadowStore {
+	return &ShadowStore{
+		Store:      primary,
+		candidate:  candidate,
+		sampleRate: sampleRate,
+		inFlight:   make(chan struct{}, maxShadowInFlight),
+		byMethod:   make(map[string]int64),
+	}
+}
+
+// ShadowStats reports how the candidate has compared against the primary so far
+type ShadowStats struct {
+	SampleRate       float64          `json:"sample_rate"`
+	Compared         int64            `json:"compared"`
+	Diverged         int64            `json:"diverged"`
+	CandidateErrors  int64            `json:"candidate_errors"`
+	DivergedByMethod map[string]int64 `json:"diverged_by_method"`
+}
+
+// Stats returns the current comparison counters
+func (s *ShadowStore) Stats() ShadowStats {
+	s.mu.Lock()
+	byMethod := make(map[string]int64, len(s.byMethod))
+	for method, count := range s.byMethod {
+		byMethod[method] = count
+	}
+	s.mu.Unlock()
+
+	return ShadowStats{
+		SampleRate:       s.sampleRate,
+		Compared:         s.compared.Load(),
+		Diverged:         s.diverged.Load(),
+		CandidateErrors:  s.errors.Load(),
+		DivergedByMethod: byMethod,
+	}
+}
+
+// shadow runs call against the candidate in the background for sampled requests
+// and compares its result with the primary one. The candidate call keeps the request's
+// values (e.g. its request ID) but outlives the request.
+func shadow[T any](ctx context.Context, s *ShadowStore, method string, primary T, primaryErr error, call func(context.Context, Store) (T, error)) (T, error) {
+	if primaryErr != nil || rand.Float64() >= s.sampleRate {
+		return primary, primaryErr
+	}
+
+	select {
+	case s.inFlight <- struct{}{}:
+	default:
+		return primary, primaryErr // Too many shadow reads in flight, skip this sample
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		defer func() { <-s.inFlight }()
+
+		start := time.Now()
+		candidate, err := call(ctx, s.candidate)
+		s.compared.Add(1)
+		if err != nil {
+			s.errors.Add(1)
+			logging.FromContext(ctx).Warn("👥 Shadow candidate error", "method", method, "error", err)
+			return
+		}
+
+		primaryJSON, _ := json.Marshal(primary)
+		candidateJSON, _ := json.Marshal(candidate)
+		if !bytes.Equal(primaryJSON, candidateJSON) {
+			s.diverged.Add(1)
+			s.mu.Lock()
+			s.byMethod[method]++
+			s.mu.Unlock()
+			logging.FromContext(ctx).Warn("👥 Shadow diverged",
+				"method", method,
+				"candidate_ms", time.Since(start).Milliseconds(),
+				"primary", truncate(primaryJSON, 500),
+				"candidate", truncate(candidateJSON, 500),
+			)
+		}
+	}()
+
+	return primary, primaryErr
+}
+
+// truncate returns at most n bytes of data as a string
+func truncate(data []byte, n int) string {
+	if len(data) > n {
+		data = data[:n]
+	}
+	return string(data)
+}
+
+func (s *ShadowStore) GetPlayerNadmons(ctx context.Context, address string) ([]models.Nadmon, error) {
+	result, err := s.Store.GetPlayerNadmons(ctx, address)
+	return shadow(ctx, s, "GetPlayerNadmons", result, err, func(ctx context.Context, st Store) ([]models.Nadmon, error) {
+		return st.GetPlayerNadmons(ctx, address)
+	})
+}
+
+func (s *ShadowStore) GetPlayerProfile(ctx context.Context, address string) (*models.PlayerProfile, error) {
+	result, err := s.Store.GetPlayerProfile(ctx, address)
+	return shadow(ctx, s, "GetPlayerProfile", result, err, func(ctx context.Context, st Store) (*models.PlayerProfile, error) {
+		return st.GetPlayerProfile(ctx, address)
+	})
+}
+
+func (s *ShadowStore) GetPlayerSummary(ctx context.Context, address string) (*models.PlayerSummary, error) {
+	result, err := s.Store.GetPlayerSummary(ctx, address)
+	return shadow(ctx, s, "GetPlayerSummary", result, err, func(ctx context.Context, st Store) (*models.PlayerSummary, error) {
+		return st.GetPlayerSummary(ctx, address)
+	})
+}
+
+func (s *ShadowStore) GetPlayerPacks(ctx context.Context, address string) ([]models.Pack, error) {
+	result, err := s.Store.GetPlayerPacks(ctx, address)
+	return shadow(ctx, s, "GetPlayerPacks", result, err, func(ctx context.Context, st Store) ([]models.Pack, error) {
+		return st.GetPlayerPacks(ctx, address)
+	})
+}
+
+func (s *ShadowStore) GetPlayerPackSummary(ctx context.Context, address string, recent int) (*models.PackSummary, error) {
+	result, err := s.Store.GetPlayerPackSummary(ctx, address, recent)
+	return shadow(ctx, s, "GetPlayerPackSummary", result, err, func(ctx context.Context, st Store) (*models.PackSummary, error) {
+		return st.GetPlayerPackSummary(ctx, address, recent)
+	})
+}
+
+func (s *ShadowStore) GetPlayerDex(ctx context.Context, address string) (*models.Dex, error) {
+	result, err := s.Store.GetPlayerDex(ctx, address)
+	return shadow(ctx, s, "GetPlayerDex", result, err, func(ctx context.Context, st Store) (*models.Dex, error) {
+		return st.GetPlayerDex(ctx, address)
+	})
+}
+
+func (s *ShadowStore) SearchNadmons(ctx context.Context, address string, filters map[string]interface{}) ([]models.Nadmon, error) {
+	result, err := s.Store.SearchNadmons(ctx, address, filters)
+	return shadow(ctx, s, "SearchNadmons", result, err, func(ctx context.Context, st Store) ([]models.Nadmon, error) {
+		return st.SearchNadmons(ctx, address, filters)
+	})
+}
+
+func (s *ShadowStore) GetSingleNadmon(ctx context.Context, tokenID int64) (*models.Nadmon, error) {
+	result, err := s.Store.GetSingleNadmon(ctx, tokenID)
+	return shadow(ctx, s, "GetSingleNadmon", result, err, func(ctx context.Context, st Store) (*models.Nadmon, error) {
+		return st.GetSingleNadmon(ctx, tokenID)
+	})
+}
+
+func (s *ShadowStore) GetNadmonsByIDs(ctx context.Context, tokenIDs []int64) ([]models.Nadmon, error) {
+	result, err := s.Store.GetNadmonsByIDs(ctx, tokenIDs)
+	return shadow(ctx, s, "GetNadmonsByIDs", result, err, func(ctx context.Context, st Store) ([]models.Nadmon, error) {
+		return st.GetNadmonsByIDs(ctx, tokenIDs)
+	})
+}
+
+func (s *ShadowStore) GetNadmonHistory(ctx context.Context, tokenID int64) ([]models.StatsChange, error) {
+	result, err := s.Store.GetNadmonHistory(ctx, tokenID)
+	return shadow(ctx, s, "GetNadmonHistory", result, err, func(ctx context.Context, st Store) ([]models.StatsChange, error) {
+		return st.GetNadmonHistory(ctx, tokenID)
+	})
+}
+
+func (s *ShadowStore) GetFusions(ctx context.Context, tokenIDs []int64) ([]models.Fusion, error) {
+	result, err := s.Store.GetFusions(ctx, tokenIDs)
+	return shadow(ctx, s, "GetFusions", result, err, func(ctx context.Context, st Store) ([]models.Fusion, error) {
+		return st.GetFusions(ctx, tokenIDs)
+	})
+}
+
+func (s *ShadowStore) GetInventoryChanges(ctx context.Context, address string, sinceSequence int64) (*models.InventoryChanges, error) {
+	result, err := s.Store.GetInventoryChanges(ctx, address, sinceSequence)
+	return shadow(ctx, s, "GetInventoryChanges", result, err, func(ctx context.Context, st Store) (*models.InventoryChanges, error) {
+		return st.GetInventoryChanges(ctx, address, sinceSequence)
+	})
+}
+
+func (s *ShadowStore) GetHolderSnapshot(ctx context.Context, asOf time.Time) (*models.HolderSnapshot, error) {
+	result, err := s.Store.GetHolderSnapshot(ctx, asOf)
+	return shadow(ctx, s, "GetHolderSnapshot", result, err, func(ctx context.Context, st Store) (*models.HolderSnapshot, error) {
+		return st.GetHolderSnapshot(ctx, asOf)
+	})
+}
+
+func (s *ShadowStore) GetNadmonSnapshot(ctx context.Context, afterTokenID int64, limit int) ([]models.Nadmon, error) {
+	result, err := s.Store.GetNadmonSnapshot(ctx, afterTokenID, limit)
+	return shadow(ctx, s, "GetNadmonSnapshot", result, err, func(ctx context.Context, st Store) ([]models.Nadmon, error) {
+		return st.GetNadmonSnapshot(ctx, afterTokenID, limit)
+	})
+}
+
+func (s *ShadowStore) GetNadmonStatuses(ctx context.Context, tokenIDs []int64) (map[int64]models.NadmonStatus, error) {
+	result, err := s.Store.GetNadmonStatuses(ctx, tokenIDs)
+	return shadow(ctx, s, "GetNadmonStatuses", result, err, func(ctx context.Context, st Store) (map[int64]models.NadmonStatus, error) {
+		return st.GetNadmonStatuses(ctx, tokenIDs)
+	})
+}
+
+func (s *ShadowStore) GetNadmonTransfers(ctx context.Context, tokenID int64, limit, offset int) (*models.TransferPage, error) {
+	result, err := s.Store.GetNadmonTransfers(ctx, tokenID, limit, offset)
+	return shadow(ctx, s, "GetNadmonTransfers", result, err, func(ctx context.Context, st Store) (*models.TransferPage, error) {
+		return st.GetNadmonTransfers(ctx, tokenID, limit, offset)
+	})
+}
+
+func (s *ShadowStore) GetNadmonSales(ctx context.Context, tokenID int64, limit, offset int) (*models.SalePage, error) {
+	result, err := s.Store.GetNadmonSales(ctx, tokenID, limit, offset)
+	return shadow(ctx, s, "GetNadmonSales", result, err, func(ctx context.Context, st Store) (*models.SalePage, error) {
+		return st.GetNadmonSales(ctx, tokenID, limit, offset)
+	})
+}
+
+func (s *ShadowStore) GetMarketStats(ctx context.Context, rarity, element string) (*models.MarketReport, error) {
+	result, err := s.Store.GetMarketStats(ctx, rarity, element)
+	return shadow(ctx, s, "GetMarketStats", result, err, func(ctx context.Context, st Store) (*models.MarketReport, error) {
+		return st.GetMarketStats(ctx, rarity, element)
+	})
+}
+
+func (s *ShadowStore) GetPlayerTransfers(ctx context.Context, address string, limit, offset int) (*models.TransferPage, error) {
+	result, err := s.Store.GetPlayerTransfers(ctx, address, limit, offset)
+	return shadow(ctx, s, "GetPlayerTransfers", result, err, func(ctx context.Context, st Store) (*models.TransferPage, error) {
+		return st.GetPlayerTransfers(ctx, address, limit, offset)
+	})
+}
+
+func (s *ShadowStore) GetActivity(ctx context.Context, types []string, limit, offset int) (*models.ActivityPage, error) {
+	result, err := s.Store.GetActivity(ctx, types, limit, offset)
+	return shadow(ctx, s, "GetActivity", result, err, func(ctx context.Context, st Store) (*models.ActivityPage, error) {
+		return st.GetActivity(ctx, types, limit, offset)
+	})
+}
+
+func (s *ShadowStore) GetPlayerActivity(ctx context.Context, address string, types []string, limit, offset int) (*models.ActivityPage, error) {
+	result, err := s.Store.GetPlayerActivity(ctx, address, types, limit, offset)
+	return shadow(ctx, s, "GetPlayerActivity", result, err, func(ctx context.Context, st Store) (*models.ActivityPage, error) {
+		return st.GetPlayerActivity(ctx, address, types, limit, offset)
+	})
+}
+
+func (s *ShadowStore) GetPackByID(ctx context.Context, packID int64) (*models.Pack, error) {
+	result, err := s.Store.GetPackByID(ctx, packID)
+	return shadow(ctx, s, "GetPackByID", result, err, func(ctx context.Context, st Store) (*models.Pack, error) {
+		return st.GetPackByID(ctx, packID)
+	})
+}
+
+func (s *ShadowStore) GetRecentPacks(ctx context.Context, limit int) ([]models.Pack, error) {
+	result, err := s.Store.GetRecentPacks(ctx, limit)
+	return shadow(ctx, s, "GetRecentPacks", result, err, func(ctx context.Context, st Store) ([]models.Pack, error) {
+		return st.GetRecentPacks(ctx, limit)
+	})
+}
+
+func (s *ShadowStore) GetTopCollectors(ctx context.Context, limit int) ([]models.PlayerProfile, error) {
+	result, err := s.Store.GetTopCollectors(ctx, limit)
+	return shadow(ctx, s, "GetTopCollectors", result, err, func(ctx context.Context, st Store) ([]models.PlayerProfile, error) {
+		return st.GetTopCollectors(ctx, limit)
+	})
+}
+
+func (s *ShadowStore) GetLeaderboard(ctx context.Context, kind, player string, limit, offset int) (*models.Leaderboard, error) {
+	result, err := s.Store.GetLeaderboard(ctx, kind, player, limit, offset)
+	return shadow(ctx, s, "GetLeaderboard", result, err, func(ctx context.Context, st Store) (*models.Leaderboard, error) {
+		return st.GetLeaderboard(ctx, kind, player, limit, offset)
+	})
+}
+
+func (s *ShadowStore) GetGameStats(ctx context.Context) (*models.GameStats, error) {
+	result, err := s.Store.GetGameStats(ctx)
+	return shadow(ctx, s, "GetGameStats", result, err, func(ctx context.Context, st Store) (*models.GameStats, error) {
+		return st.GetGameStats(ctx)
+	})
+}
+
+func (s *ShadowStore) GetPackDistribution(ctx context.Context) (*models.PackDistribution, error) {
+	result, err := s.Store.GetPackDistribution(ctx)
+	return shadow(ctx, s, "GetPackDistribution", result, err, func(ctx context.Context, st Store) (*models.PackDistribution, error) {
+		return st.GetPackDistribution(ctx)
+	})
+}
+
+func (s *ShadowStore) GetOwnershipConcentration(ctx context.Context) (*models.OwnershipConcentration, error) {
+	result, err := s.Store.GetOwnershipConcentration(ctx)
+	return shadow(ctx, s, "GetOwnershipConcentration", result, err, func(ctx context.Context, st Store) (*models.OwnershipConcentration, error) {
+		return st.GetOwnershipConcentration(ctx)
+	})
+}
+
+func (s *ShadowStore) GetSupplyStats(ctx context.Context) (*models.SupplyStats, error) {
+	result, err := s.Store.GetSupplyStats(ctx)
+	return shadow(ctx, s, "GetSupplyStats", result, err, func(ctx context.Context, st Store) (*models.SupplyStats, error) {
+		return st.GetSupplyStats(ctx)
+	})
+}
+
+func (s *ShadowStore) GetTraitStats(ctx context.Context, dimension string) (*models.TraitBreakdown, error) {
+	result, err := s.Store.GetTraitStats(ctx, dimension)
+	return shadow(ctx, s, "GetTraitStats", result, err, func(ctx context.Context, st Store) (*models.TraitBreakdown, error) {
+		return st.GetTraitStats(ctx, dimension)
+	})
+}
+
+func (s *ShadowStore) SearchAllNadmons(ctx context.Context, filters map[string]interface{}, after *SearchCursor, limit int) (*models.NadmonSearchPage, error) {
+	result, err := s.Store.SearchAllNadmons(ctx, filters, after, limit)
+	return shadow(ctx, s, "SearchAllNadmons", result, err, func(ctx context.Context, st Store) (*models.NadmonSearchPage, error) {
+		return st.SearchAllNadmons(ctx, filters, after, limit)
+	})
+}
+
+func (s *ShadowStore) GetSearchSuggestions(ctx context.Context, query string, limit int) ([]models.SearchSuggestion, error) {
+	result, err := s.Store.GetSearchSuggestions(ctx, query, limit)
+	return shadow(ctx, s, "GetSearchSuggestions", result, err, func(ctx context.Context, st Store) ([]models.SearchSuggestion, error) {
+		return st.GetSearchSuggestions(ctx, query, limit)
+	})
+}
+
+func (s *ShadowStore) GetPackOdds(ctx context.Context, from, to time.Time) (*models.PackOddsReport, error) {
+	result, err := s.Store.GetPackOdds(ctx, from, to)
+	return shadow(ctx, s, "GetPackOdds", result, err, func(ctx context.Context, st Store) (*models.PackOddsReport, error) {
+		return st.GetPackOdds(ctx, from, to)
+	})
+}
+
+func (s *ShadowStore) GetEventTimeSeries(ctx context.Context, metric, interval string, from, to time.Time) ([]models.TimeSeriesPoint, error) {
+	result, err := s.Store.GetEventTimeSeries(ctx, metric, interval, from, to)
+	return shadow(ctx, s, "GetEventTimeSeries", result, err, func(ctx context.Context, st Store) ([]models.TimeSeriesPoint, error) {
+		return st.GetEventTimeSeries(ctx, metric, interval, from, to)
+	})
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"nadmon-backend/internal/models"
+)
+
+// StatsRefresher serves GetGameStats from a snapshot recomputed in the background, so the
+// full-table counts behind it run once per interval instead of once per request. Until the
+// first refresh succeeds it computes the stats per request; after that a failed refresh
+// keeps the previous snapshot. Snapshots are shared between callers and must not be modified.
+type StatsRefresher struct {
+	Store
+
+	mu    sync.RWMutex
+	stats *models.GameStats
+}
+
+// NewStatsRefresher wraps store with game stats refreshed by calling Refresh periodically
+func NewStatsRefresher(store Store) *StatsRefresher {
+	return &StatsRefresher{Store: store}
+}
+
+// Bypass returns the store below every cache layer
+func (s *StatsRefresher) Bypass() Store {
+	if bypasser, ok := s.Store.(Bypasser); ok {
+		return bypasser.Bypass()
+	}
+	return s.Store
+}
+
+func (s *StatsRefresher) GetGameStats(ctx context.Context) (*models.GameStats, error) {
+	s.mu.RLock()
+	stats := s.stats
+	s.mu.RUnlock()
+	if stats != nil {
+		return stats, nil
+	}
+	return s.Store.GetGameStats(ctx)
+}
+
+// Refresh recomputes the stats below every cache layer, so the snapshot is never older than
+// its computed_at, and returns them
+func (s *StatsRefresher) Refresh(ctx context.Context) (*models.GameStats, error) {
+	stats, err := s.Bypass().GetGameStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := *stats
+	computedAt := time.Now().UTC()
+	snapshot.ComputedAt = &computedAt
+
+	s.mu.Lock()
+	s.stats = &snapshot
+	s.mu.Unlock()
+	return &snapshot, nil
+}
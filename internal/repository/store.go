@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"nadmon-backend/internal/models"
+)
+
+// Store is the storage backend the API reads Nadmon data from.
+// NadmonRepository (direct Postgres against the Envio schema) is the default implementation;
+// alternate backends only need to satisfy this interface to be used by the handlers.
+type Store interface {
+	// Players
+	GetPlayerNadmons(ctx context.Context, address string) ([]models.Nadmon, error)
+	GetPlayerProfile(ctx context.Context, address string) (*models.PlayerProfile, error)
+	GetPlayerSummary(ctx context.Context, address string) (*models.PlayerSummary, error)
+	GetPlayerPacks(ctx context.Context, address string) ([]models.Pack, error)
+	GetPlayerPackSummary(ctx context.Context, address string, recent int) (*models.PackSummary, error)
+	GetPlayerDex(ctx context.Context, address string) (*models.Dex, error)
+	SearchNadmons(ctx context.Context, address string, filters map[string]interface{}) ([]models.Nadmon, error)
+	SearchAllNadmons(ctx context.Context, filters map[string]interface{}, after *SearchCursor, limit int) (*models.NadmonSearchPage, error)
+	GetInventoryChanges(ctx context.Context, address string, sinceSequence int64) (*models.InventoryChanges, error)
+
+	// NFTs
+	GetSingleNadmon(ctx context.Context, tokenID int64) (*models.Nadmon, error)
+	GetNadmonsByIDs(ctx context.Context, tokenIDs []int64) ([]models.Nadmon, error)
+	GetNadmonHistory(ctx context.Context, tokenID int64) ([]models.StatsChange, error)
+	GetFusions(ctx context.Context, tokenIDs []int64) ([]models.Fusion, error)
+	GetNadmonSnapshot(ctx context.Context, afterTokenID int64, limit int) ([]models.Nadmon, error)
+	GetNadmonStatuses(ctx context.Context, tokenIDs []int64) (map[int64]models.NadmonStatus, error)
+
+	// Transfers
+	GetNadmonTransfers(ctx context.Context, tokenID int64, limit, offset int) (*models.TransferPage, error)
+	GetPlayerTransfers(ctx context.Context, address string, limit, offset int) (*models.TransferPage, error)
+	GetHolderSnapshot(ctx context.Context, asOf time.Time) (*models.HolderSnapshot, error)
+
+	// Marketplace
+	GetNadmonSales(ctx context.Context, tokenID int64, limit, offset int) (*models.SalePage, error)
+	GetMarketStats(ctx context.Context, rarity, element string) (*models.MarketReport, error)
+
+	// Activity
+	GetActivity(ctx context.Context, types []string, limit, offset int) (*models.ActivityPage, error)
+	GetPlayerActivity(ctx context.Context, address string, types []string, limit, offset int) (*models.ActivityPage, error)
+
+	// Packs
+	GetPackByID(ctx context.Context, packID int64) (*models.Pack, error)
+	GetRecentPacks(ctx context.Context, limit int) ([]models.Pack, error)
+
+	// Aggregates
+	GetTopCollectors(ctx context.Context, limit int) ([]models.PlayerProfile, error)
+	GetLeaderboard(ctx context.Context, kind, player string, limit, offset int) (*models.Leaderboard, error)
+	GetGameStats(ctx context.Context) (*models.GameStats, error)
+	GetPackDistribution(ctx context.Context) (*models.PackDistribution, error)
+	GetOwnershipConcentration(ctx context.Context) (*models.OwnershipConcentration, error)
+	GetSupplyStats(ctx context.Context) (*models.SupplyStats, error)
+	GetTraitStats(ctx context.Context, dimension string) (*models.TraitBreakdown, error)
+	GetPackOdds(ctx context.Context, from, to time.Time) (*models.PackOddsReport, error)
+
+	// Search
+	GetSearchSuggestions(ctx context.Context, query string, limit int) ([]models.SearchSuggestion, error)
+
+	// Time series
+	GetEventTimeSeries(ctx context.Context, metric, interval string, from, to time.Time) ([]models.TimeSeriesPoint, error)
+}
+
+// Storage backend names accepted by STORAGE_BACKEND
+const (
+	BackendEnvioPostgres = "envio"
+	BackendEnvioGraphQL  = "graphql"
+)
+
+// Ensure NadmonRepository implements Store
+var _ Store = (*NadmonRepository)(nil)
+
+// AnalyticsStore serves the heavy aggregate queries. It can be backed by a dedicated
+// analytics database so they don't compete with low-latency inventory reads.
+type AnalyticsStore interface {
+	GetPackDistribution(ctx context.Context) (*models.PackDistribution, error)
+	GetOwnershipConcentration(ctx context.Context) (*models.OwnershipConcentration, error)
+	GetEventTimeSeries(ctx context.Context, metric, interval string, from, to time.Time) ([]models.TimeSeriesPoint, error)
+}
+
+// analyticsRoutedStore sends analytics queries to a separate AnalyticsStore
+// and everything else to the primary Store
+type analyticsRoutedStore struct {
+	Store
+	analytics AnalyticsStore
+}
+
+// WithAnalytics returns a Store that routes analytics queries to analytics
+func WithAnalytics(primary Store, analytics AnalyticsStore) Store {
+	return &analyticsRoutedStore{Store: primary, analytics: analytics}
+}
+
+func (s *analyticsRoutedStore) GetPackDistribution(ctx context.Context) (*models.PackDistribution, error) {
+	return s.analytics.GetPackDistribution(ctx)
+}
+
+func (s *analyticsRoutedStore) GetOwnershipConcentration(ctx context.Context) (*models.OwnershipConcentration, error) {
+	return s.analytics.GetOwnershipConcentration(ctx)
+}
+
+func (s *analyticsRoutedStore) GetEventTimeSeries(ctx context.Context, metric, interval string, from, to time.Time) ([]models.TimeSeriesPoint, error) {
+	return s.analytics.GetEventTimeSeries(ctx, metric, interval, from, to)
+}
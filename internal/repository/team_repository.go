@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"nadmon-backend/internal/database"
+	"nadmon-backend/internal/ethaddr"
+	"nadmon-backend/internal/models"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+)
+
+// Team errors
+var (
+	ErrTeamNameTaken = errors.New("team name is already used")
+	ErrTeamLimit     = errors.New("team limit reached")
+)
+
+// TeamStore keeps the squads players save as battle loadouts
+type TeamStore interface {
+	// GetTeams returns a player's teams, oldest first
+	GetTeams(ctx context.Context, address string) ([]models.Team, error)
+	// CreateTeam saves a new team unless the player already has maxTeams
+	CreateTeam(ctx context.Context, team models.Team, maxTeams int) (*models.Team, error)
+	// DeleteTeam removes one of a player's teams; found is false when it doesn't exist
+	DeleteTeam(ctx context.Context, address string, id int64) (found bool, err error)
+}
+
+// TeamRepository stores teams in the backend-owned schema
+type TeamRepository struct {
+	db *sql.DB
+}
+
+// NewTeamRepository creates a team repository; the schema must have been set up with
+// EnvioDB.SetupAppSchema
+func NewTeamRepository(db *sql.DB) *TeamRepository {
+	return &TeamRepository{db: db}
+}
+
+func (r *TeamRepository) GetTeams(ctx context.Context, address string) ([]models.Team, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, address, name, token_ids, created_at
+		FROM `+database.AppSchema+`.teams
+		WHERE address = $1
+		ORDER BY created_at, id
+	`, ethaddr.Normalize(address))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query teams: %w", err)
+	}
+	defer rows.Close()
+
+	teams := []models.Team{}
+	for rows.Next() {
+		var team models.Team
+		if err := rows.Scan(&team.ID, &team.Address, &team.Name, pq.Array(&team.TokenIDs), &team.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan team: %w", err)
+		}
+		teams = append(teams, team)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read teams: %w", err)
+	}
+	return teams, nil
+}
+
+func (r *TeamRepository) CreateTeam(ctx context.Context, team models.Team, maxTeams int) (*models.Team, error) {
+	team.Address = ethaddr.Normalize(team.Address)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin team: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockOwner(ctx, tx, "teams", team.Address); err != nil {
+		return nil, err
+	}
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO `+database.AppSchema+`.teams (address, name, token_ids)
+		SELECT $1, $2, $3
+		WHERE (SELECT COUNT(*) FROM `+database.AppSchema+`.teams WHERE address = $1) < $4
+		RETURNING id, created_at
+	`, team.Address, team.Name, pq.Array(team.TokenIDs), maxTeams).Scan(&team.ID, &team.CreatedAt)
+
+	var pgErr *pgconn.PgError
+	switch {
+	case errors.As(err, &pgErr) && pgErr.Code == "23505": // unique_violation
+		return nil, ErrTeamNameTaken
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, ErrTeamLimit
+	case err != nil:
+		return nil, fmt.Errorf("failed to save team: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit team: %w", err)
+	}
+	return &team, nil
+}
+
+func (r *TeamRepository) DeleteTeam(ctx context.Context, address string, id int64) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM `+database.AppSchema+`.teams WHERE address = $1 AND id = $2
+	`, ethaddr.Normalize(address), id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete team: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete team: %w", err)
+	}
+	return deleted > 0, nil
+}
@@ -0,0 +1,167 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"nadmon-backend/internal/database"
+	"nadmon-backend/internal/ethaddr"
+	"nadmon-backend/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// Trade offer errors
+var (
+	ErrTradeLimit      = errors.New("pending trade offer limit reached")
+	ErrTradeNotPending = errors.New("trade offer is no longer pending")
+)
+
+// Trade offer roles of a player, for filtering their offers
+const (
+	TradeRoleSent     = "sent"
+	TradeRoleReceived = "received"
+)
+
+// TradeStore keeps the trade offers players negotiate before settling swaps on-chain
+type TradeStore interface {
+	// CreateTrade saves a pending offer unless its proposer already has maxPending of them
+	CreateTrade(ctx context.Context, offer models.TradeOffer, maxPending int) (*models.TradeOffer, error)
+	// GetTrade returns an offer, or nil when it doesn't exist
+	GetTrade(ctx context.Context, id int64) (*models.TradeOffer, error)
+	// GetTrades returns a page of the offers a player sent and received, newest first, only
+	// of role and status unless they are empty
+	GetTrades(ctx context.Context, address, role, status string, limit, offset int) (*models.TradePage, error)
+	// SetTradeStatus moves a pending offer to status; ErrTradeNotPending when it was already
+	// answered, cancelled or expired
+	SetTradeStatus(ctx context.Context, id int64, status string) (*models.TradeOffer, error)
+}
+
+// TradeRepository stores trade offers in the backend-owned schema
+type TradeRepository struct {
+	db       *sql.DB
+	offerTTL time.Duration
+}
+
+// NewTradeRepository creates a trade repository whose offers expire offerTTL after being
+// proposed; the schema must have been set up with EnvioDB.SetupAppSchema
+func NewTradeRepository(db *sql.DB, offerTTL time.Duration) *TradeRepository {
+	return &TradeRepository{db: db, offerTTL: offerTTL}
+}
+
+// tradeColumns selects an offer, with pending offers past their expiry reported as expired
+const tradeColumns = `id, proposer, counterparty, offered_token_ids, requested_token_ids,
+	CASE WHEN status = 'pending' AND expires_at <= NOW() THEN 'expired' ELSE status END AS status,
+	created_at, updated_at, expires_at`
+
+// scanTrade scans a row selected with tradeColumns
+func scanTrade(row interface{ Scan(...interface{}) error }) (*models.TradeOffer, error) {
+	var offer models.TradeOffer
+	err := row.Scan(&offer.ID, &offer.Proposer, &offer.Counterparty,
+		pq.Array(&offer.OfferedTokenIDs), pq.Array(&offer.RequestedTokenIDs),
+		&offer.Status, &offer.CreatedAt, &offer.UpdatedAt, &offer.ExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	return &offer, nil
+}
+
+func (r *TradeRepository) CreateTrade(ctx context.Context, offer models.TradeOffer, maxPending int) (*models.TradeOffer, error) {
+	proposer := ethaddr.Normalize(offer.Proposer)
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin trade offer: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := lockOwner(ctx, tx, "trade_offers", proposer); err != nil {
+		return nil, err
+	}
+	created, err := scanTrade(tx.QueryRowContext(ctx, `
+		INSERT INTO `+database.AppSchema+`.trade_offers
+			(proposer, counterparty, offered_token_ids, requested_token_ids, expires_at)
+		SELECT $1, $2, $3, $4, NOW() + $5::double precision * INTERVAL '1 second'
+		WHERE (
+			SELECT COUNT(*) FROM `+database.AppSchema+`.trade_offers
+			WHERE proposer = $1 AND status = 'pending' AND expires_at > NOW()
+		) < $6
+		RETURNING `+tradeColumns,
+		proposer, ethaddr.Normalize(offer.Counterparty),
+		pq.Array(offer.OfferedTokenIDs), pq.Array(offer.RequestedTokenIDs),
+		r.offerTTL.Seconds(), maxPending))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, ErrTradeLimit
+	case err != nil:
+		return nil, fmt.Errorf("failed to save trade offer: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit trade offer: %w", err)
+	}
+	return created, nil
+}
+
+func (r *TradeRepository) GetTrade(ctx context.Context, id int64) (*models.TradeOffer, error) {
+	offer, err := scanTrade(r.db.QueryRowContext(ctx, `
+		SELECT `+tradeColumns+` FROM `+database.AppSchema+`.trade_offers WHERE id = $1
+	`, id))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, nil
+	case err != nil:
+		return nil, fmt.Errorf("failed to fetch trade offer: %w", err)
+	}
+	return offer, nil
+}
+
+func (r *TradeRepository) GetTrades(ctx context.Context, address, role, status string, limit, offset int) (*models.TradePage, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT *, COUNT(*) OVER () FROM (
+			SELECT `+tradeColumns+` FROM `+database.AppSchema+`.trade_offers
+			WHERE ($2 <> 'received' AND proposer = $1) OR ($2 <> 'sent' AND counterparty = $1)
+		) offers
+		WHERE $3 = '' OR status = $3
+		ORDER BY created_at DESC, id DESC
+		LIMIT $4 OFFSET $5
+	`, ethaddr.Normalize(address), role, status, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query trade offers: %w", err)
+	}
+	defer rows.Close()
+
+	page := &models.TradePage{Offers: []models.TradeOffer{}}
+	for rows.Next() {
+		var offer models.TradeOffer
+		if err := rows.Scan(&offer.ID, &offer.Proposer, &offer.Counterparty,
+			pq.Array(&offer.OfferedTokenIDs), pq.Array(&offer.RequestedTokenIDs),
+			&offer.Status, &offer.CreatedAt, &offer.UpdatedAt, &offer.ExpiresAt, &page.Total); err != nil {
+			return nil, fmt.Errorf("failed to scan trade offer: %w", err)
+		}
+		page.Offers = append(page.Offers, offer)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read trade offers: %w", err)
+	}
+	return page, nil
+}
+
+func (r *TradeRepository) SetTradeStatus(ctx context.Context, id int64, status string) (*models.TradeOffer, error) {
+	offer, err := scanTrade(r.db.QueryRowContext(ctx, `
+		UPDATE `+database.AppSchema+`.trade_offers
+		SET status = $2, updated_at = NOW()
+		WHERE id = $1 AND status = 'pending' AND expires_at > NOW()
+		RETURNING `+tradeColumns,
+		id, status))
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, ErrTradeNotPending
+	case err != nil:
+		return nil, fmt.Errorf("failed to update trade offer: %w", err)
+	}
+	return offer, nil
+}
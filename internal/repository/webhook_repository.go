@@ -0,0 +1,214 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"nadmon-backend/internal/database"
+	"nadmon-backend/internal/ethaddr"
+	"nadmon-backend/internal/models"
+
+	"github.com/lib/pq"
+)
+
+// ErrWebhookLimit is returned when a wallet already registered the maximum number of webhooks
+var ErrWebhookLimit = errors.New("webhook limit reached")
+
+// WebhookStore keeps webhook subscriptions and their delivery log
+type WebhookStore interface {
+	// GetWebhooks returns every webhook, including its secret, oldest first
+	GetWebhooks(ctx context.Context) ([]models.Webhook, error)
+	// GetOwnerWebhooks returns the webhooks registered by a wallet, oldest first
+	GetOwnerWebhooks(ctx context.Context, owner string) ([]models.Webhook, error)
+	// GetWebhook returns one webhook, or nil when it doesn't exist
+	GetWebhook(ctx context.Context, id int64) (*models.Webhook, error)
+	// CreateWebhook registers a webhook unless its owner already has maxPerOwner; webhooks
+	// without an owner are not limited
+	CreateWebhook(ctx context.Context, webhook models.Webhook, maxPerOwner int) (*models.Webhook, error)
+	// DeleteWebhook removes a webhook and its delivery log; found is false when it doesn't exist
+	DeleteWebhook(ctx context.Context, id int64) (found bool, err error)
+	// RecordDelivery appends a delivery attempt to the log
+	RecordDelivery(ctx context.Context, delivery models.WebhookDelivery) error
+	// GetDeliveries returns a webhook's latest delivery attempts, newest first
+	GetDeliveries(ctx context.Context, webhookID int64, limit int) ([]models.WebhookDelivery, error)
+	// PruneDeliveries drops delivery attempts made before the given time
+	PruneDeliveries(ctx context.Context, before time.Time) (int64, error)
+}
+
+// WebhookRepository stores webhooks in the backend-owned schema
+type WebhookRepository struct {
+	db *sql.DB
+}
+
+// NewWebhookRepository creates a webhook repository; the schema must have been set up with
+// EnvioDB.SetupAppSchema
+func NewWebhookRepository(db *sql.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+const webhookColumns = `id, url, owner, events, addresses, secret, created_at`
+
+func (r *WebhookRepository) GetWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	return r.queryWebhooks(ctx, `
+		SELECT `+webhookColumns+` FROM `+database.AppSchema+`.webhooks
+		ORDER BY id
+	`)
+}
+
+func (r *WebhookRepository) GetOwnerWebhooks(ctx context.Context, owner string) ([]models.Webhook, error) {
+	return r.queryWebhooks(ctx, `
+		SELECT `+webhookColumns+` FROM `+database.AppSchema+`.webhooks
+		WHERE owner = $1
+		ORDER BY id
+	`, ethaddr.Normalize(owner))
+}
+
+func (r *WebhookRepository) GetWebhook(ctx context.Context, id int64) (*models.Webhook, error) {
+	webhooks, err := r.queryWebhooks(ctx, `
+		SELECT `+webhookColumns+` FROM `+database.AppSchema+`.webhooks
+		WHERE id = $1
+	`, id)
+	if err != nil || len(webhooks) == 0 {
+		return nil, err
+	}
+	return &webhooks[0], nil
+}
+
+// queryWebhooks runs a query selecting webhookColumns
+func (r *WebhookRepository) queryWebhooks(ctx context.Context, query string, args ...interface{}) ([]models.Webhook, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := []models.Webhook{}
+	for rows.Next() {
+		var webhook models.Webhook
+		if err := rows.Scan(&webhook.ID, &webhook.URL, &webhook.Owner, pq.Array(&webhook.Events),
+			pq.Array(&webhook.Addresses), &webhook.Secret, &webhook.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		if webhook.Addresses == nil {
+			webhook.Addresses = []string{}
+		}
+		webhooks = append(webhooks, webhook)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read webhooks: %w", err)
+	}
+	return webhooks, nil
+}
+
+func (r *WebhookRepository) CreateWebhook(ctx context.Context, webhook models.Webhook, maxPerOwner int) (*models.Webhook, error) {
+	if webhook.Owner != "" {
+		webhook.Owner = ethaddr.Normalize(webhook.Owner)
+	}
+	addresses := make([]string, len(webhook.Addresses))
+	for i, address := range webhook.Addresses {
+		addresses[i] = ethaddr.Normalize(address)
+	}
+	webhook.Addresses = addresses
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin webhook: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Webhooks registered with the admin key have no owner and no limit
+	if webhook.Owner != "" {
+		if err := lockOwner(ctx, tx, "webhooks", webhook.Owner); err != nil {
+			return nil, err
+		}
+	}
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO `+database.AppSchema+`.webhooks (url, owner, events, addresses, secret)
+		SELECT $1, $2, $3, $4, $5
+		WHERE $2 = '' OR (SELECT COUNT(*) FROM `+database.AppSchema+`.webhooks WHERE owner = $2) < $6
+		RETURNING id, created_at
+	`, webhook.URL, webhook.Owner, pq.Array(webhook.Events), pq.Array(webhook.Addresses), webhook.Secret, maxPerOwner).
+		Scan(&webhook.ID, &webhook.CreatedAt)
+
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		return nil, ErrWebhookLimit
+	case err != nil:
+		return nil, fmt.Errorf("failed to save webhook: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit webhook: %w", err)
+	}
+	return &webhook, nil
+}
+
+func (r *WebhookRepository) DeleteWebhook(ctx context.Context, id int64) (bool, error) {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM `+database.AppSchema+`.webhooks WHERE id = $1
+	`, id)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	return deleted > 0, nil
+}
+
+func (r *WebhookRepository) RecordDelivery(ctx context.Context, delivery models.WebhookDelivery) error {
+	// The webhook may have been deleted while the delivery was in flight; its log is gone then
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO `+database.AppSchema+`.webhook_deliveries
+			(webhook_id, delivery_id, event, attempt, status_code, error, duration_ms, succeeded)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8
+		WHERE EXISTS (SELECT 1 FROM `+database.AppSchema+`.webhooks WHERE id = $1)
+	`, delivery.WebhookID, delivery.DeliveryID, delivery.Event, delivery.Attempt, delivery.StatusCode,
+		delivery.Error, delivery.DurationMs, delivery.Succeeded)
+	if err != nil {
+		return fmt.Errorf("failed to record webhook delivery: %w", err)
+	}
+	return nil
+}
+
+func (r *WebhookRepository) GetDeliveries(ctx context.Context, webhookID int64, limit int) ([]models.WebhookDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, webhook_id, delivery_id, event, attempt, status_code, error, duration_ms, succeeded, created_at
+		FROM `+database.AppSchema+`.webhook_deliveries
+		WHERE webhook_id = $1
+		ORDER BY id DESC
+		LIMIT $2
+	`, webhookID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []models.WebhookDelivery{}
+	for rows.Next() {
+		var d models.WebhookDelivery
+		if err := rows.Scan(&d.ID, &d.WebhookID, &d.DeliveryID, &d.Event, &d.Attempt, &d.StatusCode,
+			&d.Error, &d.DurationMs, &d.Succeeded, &d.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+func (r *WebhookRepository) PruneDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, `
+		DELETE FROM `+database.AppSchema+`.webhook_deliveries WHERE created_at < $1
+	`, before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune webhook deliveries: %w", err)
+	}
+	return result.RowsAffected()
+}
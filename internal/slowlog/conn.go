@@ -0,0 +1,66 @@
+package slowlog
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+// Conn is the database connection interface queries run through
+type Conn interface {
+	ExecContext(context.Context, string, ...interface{}) (sql.Result, error)
+	PrepareContext(context.Context, string) (*sql.Stmt, error)
+	QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error)
+	QueryRowContext(context.Context, string, ...interface{}) *sql.Row
+}
+
+// loggedConn times the queries run through it. Reads are timed until their first row is
+// available, so a large result set streamed slowly to the handler isn't counted.
+type loggedConn struct {
+	Conn
+	log *Log
+}
+
+// Wrap returns conn with its queries slower than the log's threshold logged and reported;
+// a zero threshold returns conn as is
+func (l *Log) Wrap(conn Conn) Conn {
+	if l == nil || l.threshold <= 0 {
+		return conn
+	}
+	return &loggedConn{Conn: conn, log: l}
+}
+
+// observe records query if it ran longer than the threshold
+func (c *loggedConn) observe(ctx context.Context, start time.Time, query string, args []interface{}) {
+	elapsed := time.Since(start)
+	if elapsed < c.log.threshold {
+		return
+	}
+	c.log.record(ctx, Operation{
+		Kind:       KindQuery,
+		Name:       queryName(query),
+		Params:     formatParams(args),
+		DurationMs: milliseconds(elapsed),
+	})
+}
+
+func (c *loggedConn) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	start := time.Now()
+	result, err := c.Conn.ExecContext(ctx, query, args...)
+	c.observe(ctx, start, query, args)
+	return result, err
+}
+
+func (c *loggedConn) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	start := time.Now()
+	rows, err := c.Conn.QueryContext(ctx, query, args...)
+	c.observe(ctx, start, query, args)
+	return rows, err
+}
+
+func (c *loggedConn) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	start := time.Now()
+	row := c.Conn.QueryRowContext(ctx, query, args...)
+	c.observe(ctx, start, query, args)
+	return row
+}
@@ -0,0 +1,72 @@
+package slowlog
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Budgets are the latency budgets of the API routes
+type Budgets struct {
+	// Default applies to routes without their own budget; zero disables it
+	Default time.Duration
+	// Routes overrides Default for the routes whose pattern ends with the key, e.g.
+	// "/leaderboard/:type"
+	Routes map[string]time.Duration
+}
+
+// ParseBudgets parses "route=duration" entries (e.g. "/leaderboard/:type=800ms")
+func ParseBudgets(entries []string) (map[string]time.Duration, error) {
+	routes := make(map[string]time.Duration, len(entries))
+	for _, entry := range entries {
+		route, value, ok := strings.Cut(entry, "=")
+		route = strings.TrimSpace(route)
+		if !ok || !strings.HasPrefix(route, "/") {
+			return nil, fmt.Errorf("invalid latency budget %q, expected /route=duration", entry)
+		}
+		budget, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || budget <= 0 {
+			return nil, fmt.Errorf("invalid latency budget %q, expected /route=duration", entry)
+		}
+		routes[route] = budget
+	}
+	return routes, nil
+}
+
+// budget returns the budget of route, preferring the longest matching override
+func (b Budgets) budget(route string) time.Duration {
+	budget, matched := b.Default, 0
+	for suffix, routeBudget := range b.Routes {
+		if len(suffix) > matched && strings.HasSuffix(route, suffix) {
+			budget, matched = routeBudget, len(suffix)
+		}
+	}
+	return budget
+}
+
+// Middleware logs and reports requests that take longer than their route's budget.
+// WebSocket and event stream connections, which stay open, have none.
+func (l *Log) Middleware(budgets Budgets) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" || c.IsWebsocket() || strings.HasPrefix(c.Writer.Header().Get("Content-Type"), "text/event-stream") {
+			return
+		}
+		budget := budgets.budget(route)
+		elapsed := time.Since(start)
+		if budget <= 0 || elapsed <= budget {
+			return
+		}
+		l.record(c.Request.Context(), Operation{
+			Kind:       KindRequest,
+			Name:       c.Request.Method + " " + route,
+			DurationMs: milliseconds(elapsed),
+			BudgetMs:   milliseconds(budget),
+		})
+	}
+}
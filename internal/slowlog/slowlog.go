@@ -0,0 +1,168 @@
+// Package slowlog finds slow SQL queries and requests: it logs those over a threshold or
+// latency budget and keeps the slowest recent ones for the admin API.
+package slowlog
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"nadmon-backend/internal/logging"
+	"nadmon-backend/internal/metrics"
+)
+
+// Operation kinds
+const (
+	KindQuery   = "query"
+	KindRequest = "request"
+)
+
+// maxParamLength truncates long query parameters (token ID arrays) in logs and reports
+const maxParamLength = 120
+
+// Operation is one slow query or request
+type Operation struct {
+	Kind       string    `json:"kind"`
+	Name       string    `json:"name"` // the query's sqlc name, or the request's route
+	Params     []string  `json:"params,omitempty"`
+	DurationMs float64   `json:"duration_ms"`
+	BudgetMs   float64   `json:"budget_ms,omitempty"`
+	RequestID  string    `json:"request_id,omitempty"`
+	At         time.Time `json:"at"`
+}
+
+// Log keeps the slowest operations of the last window, at most size of them
+type Log struct {
+	threshold time.Duration
+	size      int
+	window    time.Duration
+
+	mu         sync.Mutex
+	operations []Operation // slowest first
+}
+
+// New creates a log of queries slower than threshold, keeping the size slowest operations of
+// the last window
+func New(threshold time.Duration, size int, window time.Duration) *Log {
+	return &Log{threshold: threshold, size: size, window: window}
+}
+
+// Threshold returns the duration over which queries are logged
+func (l *Log) Threshold() time.Duration {
+	return l.threshold
+}
+
+// Window returns how long operations stay in the report
+func (l *Log) Window() time.Duration {
+	return l.window
+}
+
+// record logs op and adds it to the report if it is among the slowest
+func (l *Log) record(ctx context.Context, op Operation) {
+	op.RequestID = logging.RequestID(ctx)
+	op.At = time.Now()
+	metrics.ObserveSlowOperation(op.Kind, op.Name)
+
+	attrs := []any{"kind", op.Kind, "name", op.Name, "duration_ms", op.DurationMs}
+	if op.BudgetMs > 0 {
+		attrs = append(attrs, "budget_ms", op.BudgetMs)
+	}
+	if len(op.Params) > 0 {
+		attrs = append(attrs, "params", op.Params)
+	}
+	logging.FromContext(ctx).Warn("Slow "+op.Kind, attrs...)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.prune(op.At)
+	i := sort.Search(len(l.operations), func(i int) bool {
+		return l.operations[i].DurationMs < op.DurationMs
+	})
+	if i >= l.size {
+		return
+	}
+	l.operations = append(l.operations, Operation{})
+	copy(l.operations[i+1:], l.operations[i:])
+	l.operations[i] = op
+	if len(l.operations) > l.size {
+		l.operations = l.operations[:l.size]
+	}
+}
+
+// prune drops the operations older than the window; the caller holds l.mu
+func (l *Log) prune(now time.Time) {
+	cutoff := now.Add(-l.window)
+	kept := l.operations[:0]
+	for _, op := range l.operations {
+		if !op.At.Before(cutoff) {
+			kept = append(kept, op)
+		}
+	}
+	l.operations = kept
+}
+
+// Top returns up to limit of the slowest operations of the window, slowest first, only of kind
+// unless it is empty
+func (l *Log) Top(kind string, limit int) []Operation {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.prune(time.Now())
+
+	top := make([]Operation, 0, limit)
+	for _, op := range l.operations {
+		if len(top) == limit {
+			break
+		}
+		if kind == "" || op.Kind == kind {
+			top = append(top, op)
+		}
+	}
+	return top
+}
+
+// queryName returns the sqlc name of query ("-- name: GetLeaderboard :many"), or its first
+// line for hand-written SQL
+func queryName(query string) string {
+	query = strings.TrimSpace(query)
+	if rest, ok := strings.CutPrefix(query, "-- name:"); ok {
+		if fields := strings.Fields(rest); len(fields) > 0 {
+			return fields[0]
+		}
+	}
+	line, _, _ := strings.Cut(query, "\n")
+	line = strings.Join(strings.Fields(line), " ")
+	if len(line) > maxParamLength {
+		line = line[:maxParamLength] + "…"
+	}
+	return line
+}
+
+// formatParams renders query arguments for the log
+func formatParams(args []interface{}) []string {
+	params := make([]string, len(args))
+	for i, arg := range args {
+		if valuer, ok := arg.(driver.Valuer); ok {
+			if value, err := valuer.Value(); err == nil {
+				arg = value
+			}
+		}
+		param := fmt.Sprint(arg)
+		if b, ok := arg.([]byte); ok {
+			param = string(b)
+		}
+		if len(param) > maxParamLength {
+			param = param[:maxParamLength] + "…"
+		}
+		params[i] = param
+	}
+	return params
+}
+
+// milliseconds converts d to fractional milliseconds
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
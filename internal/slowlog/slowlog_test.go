@@ -0,0 +1,88 @@
+package slowlog
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lib/pq"
+)
+
+func TestLog(t *testing.T) {
+	log := New(time.Millisecond, 2, time.Hour)
+	ctx := context.Background()
+	for _, ms := range []float64{5, 20, 10} {
+		log.record(ctx, Operation{Kind: KindQuery, Name: "GetLeaderboard", DurationMs: ms})
+	}
+	log.record(ctx, Operation{Kind: KindRequest, Name: "GET /api/leaderboard/:type", DurationMs: 15})
+
+	top := log.Top("", 10)
+	if len(top) != 2 || top[0].DurationMs != 20 || top[1].DurationMs != 15 {
+		t.Errorf("expected the 2 slowest operations, got %+v", top)
+	}
+	if queries := log.Top(KindQuery, 10); len(queries) != 1 || queries[0].DurationMs != 20 {
+		t.Errorf("expected the slowest query, got %+v", queries)
+	}
+
+	// Operations older than the window leave the report
+	log.mu.Lock()
+	log.operations[0].At = time.Now().Add(-2 * time.Hour)
+	log.mu.Unlock()
+	if top := log.Top("", 10); len(top) != 1 || top[0].Kind != KindRequest {
+		t.Errorf("expected only the recent request, got %+v", top)
+	}
+}
+
+func TestQueryName(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"-- name: GetLeaderboard :many\nSELECT 1", "GetLeaderboard"},
+		{"\n  SELECT owner\n  FROM x", "SELECT owner"},
+	}
+	for _, tt := range tests {
+		if got := queryName(tt.query); got != tt.want {
+			t.Errorf("queryName(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+
+	params := formatParams([]interface{}{"0xabc", int64(7), pq.Array([]int64{1, 2})})
+	if len(params) != 3 || params[0] != "0xabc" || params[1] != "7" || params[2] != "{1,2}" {
+		t.Errorf("unexpected params %q", params)
+	}
+}
+
+func TestBudgets(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	routes, err := ParseBudgets([]string{"/leaderboard/:type=10ms"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParseBudgets([]string{"leaderboard=fast"}); err == nil {
+		t.Error("expected an invalid budget to be rejected")
+	}
+
+	log := New(0, 10, time.Hour)
+	r := gin.New()
+	r.Use(log.Middleware(Budgets{Default: time.Second, Routes: routes}))
+	sleep := func(c *gin.Context) {
+		time.Sleep(20 * time.Millisecond)
+		c.Status(http.StatusOK)
+	}
+	r.GET("/api/leaderboard/:type", sleep)
+	r.GET("/api/stats/game", sleep)
+
+	for _, path := range []string{"/api/leaderboard/packs", "/api/stats/game"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	top := log.Top(KindRequest, 10)
+	if len(top) != 1 || top[0].Name != "GET /api/leaderboard/:type" || top[0].BudgetMs != 10 {
+		t.Errorf("expected only the leaderboard over its budget, got %+v", top)
+	}
+}
@@ -0,0 +1,124 @@
+package status
+
+import (
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Retention is how far back health samples are kept
+const Retention = 30 * 24 * time.Hour
+
+// Sample is the result of one periodic health check
+type Sample struct {
+	Time      time.Time `json:"time"`
+	Healthy   bool      `json:"healthy"`
+	DBLatency float64   `json:"db_latency_ms"`
+	Requests  int64     `json:"requests"`
+	Errors    int64     `json:"errors"` // 5xx responses since the previous sample
+	Error     string    `json:"error,omitempty"`
+}
+
+// Probe checks a dependency and returns an error when it is unhealthy
+type Probe func() error
+
+// Monitor records periodic health samples in a fixed-size ring buffer
+type Monitor struct {
+	probe    Probe
+	interval time.Duration
+
+	mu      sync.RWMutex
+	samples []Sample
+	next    int
+	full    bool
+
+	requests atomic.Int64
+	errors   atomic.Int64
+}
+
+// NewMonitor creates a monitor sampling probe every interval and keeping Retention worth of samples
+func NewMonitor(probe Probe, interval time.Duration) *Monitor {
+	capacity := int(Retention / interval)
+	if capacity < 1 {
+		capacity = 1
+	}
+	return &Monitor{
+		probe:    probe,
+		interval: interval,
+		samples:  make([]Sample, capacity),
+	}
+}
+
+// Middleware counts requests and server errors for the error rate of each sample
+func (m *Monitor) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		m.requests.Add(1)
+		if c.Writer.Status() >= 500 {
+			m.errors.Add(1)
+		}
+	}
+}
+
+// Run samples health every interval until stop is closed
+func (m *Monitor) Run(stop <-chan struct{}) {
+	m.Record()
+
+	ticker := time.NewTicker(m.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			m.Record()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Record takes one health sample
+func (m *Monitor) Record() {
+	start := time.Now()
+	err := m.probe()
+	sample := Sample{
+		Time:      start,
+		Healthy:   err == nil,
+		DBLatency: float64(time.Since(start).Microseconds()) / 1000,
+		Requests:  m.requests.Swap(0),
+		Errors:    m.errors.Swap(0),
+	}
+	if err != nil {
+		sample.Error = err.Error()
+		log.Printf("Warning: health check failed: %v", err)
+	}
+
+	m.mu.Lock()
+	m.samples[m.next] = sample
+	m.next = (m.next + 1) % len(m.samples)
+	if m.next == 0 {
+		m.full = true
+	}
+	m.mu.Unlock()
+}
+
+// Since returns the samples taken after t, oldest first
+func (m *Monitor) Since(t time.Time) []Sample {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	ordered := m.samples[:m.next]
+	if m.full {
+		ordered = append(append([]Sample{}, m.samples[m.next:]...), m.samples[:m.next]...)
+	}
+
+	var result []Sample
+	for _, s := range ordered {
+		if s.Time.After(t) {
+			result = append(result, s)
+		}
+	}
+	return result
+}
@@ -0,0 +1,73 @@
+package status
+
+import "time"
+
+// Windows are the periods uptime is reported for
+var Windows = map[string]time.Duration{
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+}
+
+// Bucket aggregates the samples of one period for status page bars
+type Bucket struct {
+	Start        time.Time `json:"start"`
+	Uptime       float64   `json:"uptime"`
+	AvgDBLatency float64   `json:"avg_db_latency_ms"`
+	ErrorRate    float64   `json:"error_rate"`
+	Samples      int       `json:"samples"`
+}
+
+// Uptime returns the percentage of healthy samples, or 100 when there are none
+func Uptime(samples []Sample) float64 {
+	if len(samples) == 0 {
+		return 100
+	}
+	healthy := 0
+	for _, s := range samples {
+		if s.Healthy {
+			healthy++
+		}
+	}
+	return float64(healthy) / float64(len(samples)) * 100
+}
+
+// Aggregate groups samples into buckets of the given size
+func Aggregate(samples []Sample, size time.Duration) []Bucket {
+	var buckets []Bucket
+	var group []Sample
+
+	flush := func() {
+		if len(group) == 0 {
+			return
+		}
+		var latency float64
+		var requests, errors int64
+		for _, s := range group {
+			latency += s.DBLatency
+			requests += s.Requests
+			errors += s.Errors
+		}
+		bucket := Bucket{
+			Start:        group[0].Time.Truncate(size),
+			Uptime:       Uptime(group),
+			AvgDBLatency: latency / float64(len(group)),
+			Samples:      len(group),
+		}
+		if requests > 0 {
+			bucket.ErrorRate = float64(errors) / float64(requests)
+		}
+		buckets = append(buckets, bucket)
+		group = nil
+	}
+
+	for _, s := range samples {
+		if len(group) > 0 && !s.Time.Truncate(size).Equal(group[0].Time.Truncate(size)) {
+			flush()
+		}
+		group = append(group, s)
+	}
+	flush()
+
+	return buckets
+}
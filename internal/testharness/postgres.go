@@ -0,0 +1,61 @@
+//go:build integration
+
+package testharness
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"nadmon-backend/internal/database"
+	"nadmon-backend/internal/fixtures"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+// StartEnvioDB starts a throwaway Postgres container, creates the Envio schema,
+// loads the fixture dataset and returns a connected EnvioDB.
+// The container is terminated when the test finishes.
+func StartEnvioDB(t *testing.T) *database.EnvioDB {
+	t.Helper()
+
+	ctx := context.Background()
+	container, err := postgres.RunContainer(ctx,
+		testcontainers.WithImage("postgres:15-alpine"),
+		postgres.WithDatabase("envio-test"),
+		postgres.WithUsername("postgres"),
+		postgres.WithPassword("testing"),
+		testcontainers.WithWaitStrategy(
+			wait.ForLog("database system is ready to accept connections").
+				WithOccurrence(2).
+				WithStartupTimeout(60*time.Second),
+		),
+	)
+	if err != nil {
+		t.Fatalf("failed to start postgres container: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := container.Terminate(ctx); err != nil {
+			t.Logf("failed to terminate postgres container: %v", err)
+		}
+	})
+
+	databaseURL, err := container.ConnectionString(ctx, "sslmode=disable")
+	if err != nil {
+		t.Fatalf("failed to get connection string: %v", err)
+	}
+
+	envioDB, err := database.ConnectToEnvio(databaseURL)
+	if err != nil {
+		t.Fatalf("failed to connect to envio database: %v", err)
+	}
+	t.Cleanup(func() { envioDB.Close() })
+
+	if err := fixtures.Load(envioDB.DB); err != nil {
+		t.Fatalf("failed to load fixtures: %v", err)
+	}
+
+	return envioDB
+}
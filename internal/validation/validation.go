@@ -0,0 +1,245 @@
+// Package validation checks the path and query parameters shared by the API routes before
+// handlers run, rejecting bad requests with a machine-readable error code next to the message.
+package validation
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"nadmon-backend/internal/ethaddr"
+	"nadmon-backend/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Error codes of rejected parameters; version 1 responses carry them as error.code
+const (
+	CodeInvalidAddress = "INVALID_ADDRESS"
+	CodeInvalidTokenID = "INVALID_TOKEN_ID"
+	CodeInvalidPackID  = "INVALID_PACK_ID"
+	CodeInvalidPage    = "INVALID_PAGE"
+	CodeInvalidLimit   = "INVALID_LIMIT"
+	CodeLimitExceeded  = "LIMIT_EXCEEDED"
+	CodeInvalidElement = "INVALID_ELEMENT"
+	CodeInvalidRarity  = "INVALID_RARITY"
+	// Rejected with a 413 instead of a 400
+	CodeQueryTooLong = "QUERY_TOO_LONG"
+	CodeBodyTooLarge = "BODY_TOO_LARGE"
+	// Control characters or invalid UTF-8 in a path or query parameter
+	CodeInvalidCharacters = "INVALID_CHARACTERS"
+)
+
+// Rules bounds the parameters the middleware accepts
+type Rules struct {
+	MaxPageSize int // largest ?limit=
+	MaxBatchIDs int // token IDs in one ?ids=
+	// MaxQueryLength bounds the raw query string in bytes and MaxBodyBytes the request body;
+	// 0 leaves either unbounded
+	MaxQueryLength int
+	MaxBodyBytes   int64
+	// RouteLimits overrides MaxPageSize for the routes whose path ends with the key, e.g.
+	// "/webhooks/:id/deliveries"
+	RouteLimits map[string]int
+}
+
+// maxLimit returns the largest ?limit= of the route c matched
+func (r Rules) maxLimit(c *gin.Context) int {
+	for route, max := range r.RouteLimits {
+		if strings.HasSuffix(c.FullPath(), route) {
+			return max
+		}
+	}
+	return r.MaxPageSize
+}
+
+// Error is a rejected parameter
+type Error struct {
+	Code    string
+	Param   string
+	Message string
+	Status  int // HTTP status of the rejection, 400 when zero
+}
+
+// status returns the HTTP status the middleware answers e with
+func (e *Error) status() int {
+	if e.Status == 0 {
+		return http.StatusBadRequest
+	}
+	return e.Status
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Middleware rejects requests with an invalid :address, :tokenId or :packId, an invalid
+// address, page, limit, ids, tokenIds, element, rarity or min_rarity query parameter, or control characters in any
+// parameter with a 400, and requests whose query string or body is over the limits with a
+// 413. The body holds the error, its code and the offending parameter. Bodies sent without a
+// Content-Length are cut off at MaxBodyBytes while the handler reads them.
+func Middleware(rules Rules) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := Check(c, rules); err != nil {
+			c.AbortWithStatusJSON(err.status(), gin.H{
+				"error": err.Message,
+				"code":  err.Code,
+				"param": err.Param,
+			})
+			return
+		}
+		if rules.MaxBodyBytes > 0 && c.Request.Body != nil {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, rules.MaxBodyBytes)
+		}
+		c.Next()
+	}
+}
+
+// Check validates the parameters of c's request against rules
+func Check(c *gin.Context, rules Rules) *Error {
+	// Sizes first, so nothing oversized gets parsed
+	if rules.MaxQueryLength > 0 && len(c.Request.URL.RawQuery) > rules.MaxQueryLength {
+		return &Error{Code: CodeQueryTooLong, Status: http.StatusRequestEntityTooLarge, Message: fmt.Sprintf("Query string too long, expected at most %d bytes", rules.MaxQueryLength)}
+	}
+	if rules.MaxBodyBytes > 0 && c.Request.ContentLength > rules.MaxBodyBytes {
+		return &Error{Code: CodeBodyTooLarge, Status: http.StatusRequestEntityTooLarge, Message: fmt.Sprintf("Request body too large, expected at most %d bytes", rules.MaxBodyBytes)}
+	}
+
+	for _, param := range c.Params {
+		if err := Text(param.Key, param.Value); err != nil {
+			return err
+		}
+	}
+	query := c.Request.URL.Query()
+	for key, values := range query {
+		if err := Text("query", key); err != nil {
+			return err
+		}
+		for _, value := range values {
+			if err := Text(key, value); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, param := range c.Params {
+		var err *Error
+		switch param.Key {
+		case "address":
+			err = Address(param.Key, param.Value)
+		case "tokenId":
+			err = TokenID(param.Key, param.Value)
+		case "packId":
+			if _, parseErr := parseID(param.Value); parseErr != nil {
+				err = &Error{Code: CodeInvalidPackID, Param: param.Key, Message: "Invalid pack ID, expected a non-negative integer"}
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if address := query.Get("address"); address != "" {
+		if err := Address("address", address); err != nil {
+			return err
+		}
+	}
+	if page := query.Get("page"); page != "" {
+		if n, err := strconv.Atoi(page); err != nil || n < 1 {
+			return &Error{Code: CodeInvalidPage, Param: "page", Message: "Invalid page, expected a positive integer"}
+		}
+	}
+	if limit := query.Get("limit"); limit != "" {
+		if err := Limit("limit", limit, rules.maxLimit(c)); err != nil {
+			return err
+		}
+	}
+	for _, param := range []string{"ids", "tokenIds"} {
+		ids := query.Get(param)
+		if ids == "" {
+			continue
+		}
+		// Count before parsing: a list over the limit is rejected without touching its entries
+		if rules.MaxBatchIDs > 0 && strings.Count(ids, ",") >= rules.MaxBatchIDs {
+			return &Error{Code: CodeLimitExceeded, Param: param, Message: fmt.Sprintf("Too many token IDs, expected at most %d", rules.MaxBatchIDs)}
+		}
+		for _, id := range strings.Split(ids, ",") {
+			if err := TokenID(param, strings.TrimSpace(id)); err != nil {
+				return err
+			}
+		}
+	}
+	if element := query.Get("element"); element != "" && !oneOf(element, models.Elements) {
+		return &Error{Code: CodeInvalidElement, Param: "element", Message: "Invalid element, expected one of: " + strings.Join(models.Elements, ", ")}
+	}
+	for _, param := range []string{"rarity", "min_rarity"} {
+		if rarity := query.Get(param); rarity != "" && !oneOf(rarity, models.Rarities) {
+			return &Error{Code: CodeInvalidRarity, Param: param, Message: "Invalid " + param + ", expected one of: " + strings.Join(models.Rarities, ", ")}
+		}
+	}
+	return nil
+}
+
+// Address checks that value is an Ethereum address; mixed-case addresses must carry a valid
+// EIP-55 checksum
+func Address(param, value string) *Error {
+	if ethaddr.Valid(value) {
+		return nil
+	}
+	message := "Invalid Ethereum address"
+	if len(value) == 42 && strings.HasPrefix(value, "0x") && ethaddr.Valid(strings.ToLower(value)) {
+		message = "Invalid Ethereum address checksum, expected " + ethaddr.Checksum(value) + " or all lowercase"
+	}
+	return &Error{Code: CodeInvalidAddress, Param: param, Message: message}
+}
+
+// Text checks that value is valid UTF-8 free of control characters, which no parameter has a
+// use for and which could end up in logs or queries
+func Text(param, value string) *Error {
+	if !utf8.ValidString(value) || strings.IndexFunc(value, unicode.IsControl) >= 0 {
+		return &Error{Code: CodeInvalidCharacters, Param: param, Message: "Invalid " + param + ", control characters and invalid UTF-8 are not allowed"}
+	}
+	return nil
+}
+
+// TokenID checks that value is a token ID: a non-negative integer that fits 64 bits
+func TokenID(param, value string) *Error {
+	if _, err := parseID(value); err != nil {
+		return &Error{Code: CodeInvalidTokenID, Param: param, Message: "Invalid token ID " + strconv.Quote(value) + ", expected a non-negative integer"}
+	}
+	return nil
+}
+
+// Limit checks that value is a page size between 1 and max (unbounded when max is 0)
+func Limit(param, value string, max int) *Error {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return &Error{Code: CodeInvalidLimit, Param: param, Message: "Invalid limit, expected a positive integer"}
+	}
+	if max > 0 && n > max {
+		return &Error{Code: CodeLimitExceeded, Param: param, Message: fmt.Sprintf("Invalid limit, expected at most %d", max)}
+	}
+	return nil
+}
+
+// parseID parses a non-negative 64-bit ID
+func parseID(value string) (int64, error) {
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err == nil && id < 0 {
+		err = fmt.Errorf("negative ID %d", id)
+	}
+	return id, err
+}
+
+// oneOf reports whether value is in values
+func oneOf(value string, values []string) bool {
+	for _, v := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
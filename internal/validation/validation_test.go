@@ -0,0 +1,126 @@
+//go:build integration
+
+package validation
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"nadmon-backend/internal/apiversion"
+	"nadmon-backend/internal/fixtures"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	ok := func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"data": "ok"}) }
+	for _, api := range []*gin.RouterGroup{r.Group("/api"), r.Group("/api/v1", apiversion.Middleware(apiversion.V1))} {
+		api.Use(Middleware(Rules{MaxPageSize: 100, MaxBatchIDs: 3, MaxQueryLength: 100, MaxBodyBytes: 16, RouteLimits: map[string]int{"/webhooks/:id/deliveries": 200}}))
+		api.GET("/players/:address/nadmons", ok)
+		api.GET("/nfts/:tokenId", ok)
+		api.GET("/packs/:packId", ok)
+		api.GET("/nfts", ok)
+		api.GET("/leaderboard/:type", ok)
+		api.GET("/webhooks/:id/deliveries", ok)
+		api.POST("/webhooks", func(c *gin.Context) {
+			if _, err := io.ReadAll(c.Request.Body); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			ok(c)
+		})
+	}
+
+	checksummed := "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"
+	tests := []struct {
+		path string
+		code string // empty when the request passes
+	}{
+		{"/api/players/" + fixtures.Alice + "/nadmons", ""},
+		{"/api/players/" + checksummed + "/nadmons", ""},
+		{"/api/players/" + strings.ToLower(checksummed) + "/nadmons", ""},
+		{"/api/players/0x5aaeb6053F3E94C9b9A09f33669435E7Ef1BeAed/nadmons", CodeInvalidAddress},
+		{"/api/players/0x123/nadmons", CodeInvalidAddress},
+		{"/api/nfts/5", ""},
+		{"/api/nfts/-1", CodeInvalidTokenID},
+		{"/api/nfts/99999999999999999999", CodeInvalidTokenID},
+		{"/api/packs/abc", CodeInvalidPackID},
+		{"/api/nfts?ids=1,2,3", ""},
+		{"/api/nfts?ids=1,x", CodeInvalidTokenID},
+		{"/api/nfts?ids=1,2,3,4", CodeLimitExceeded},
+		{"/api/nfts?ids=1,2,3,x,y", CodeLimitExceeded},
+		{"/api/nfts?tokenIds=1,-2", CodeInvalidTokenID},
+		{"/api/nfts?tokenIds=1,2,3,4", CodeLimitExceeded},
+		{"/api/nfts?min_rarity=Rare", ""},
+		{"/api/nfts?min_rarity=Mythic", CodeInvalidRarity},
+		{"/api/nfts?ids=" + strings.Repeat("9", 101), CodeQueryTooLong},
+		{"/api/nfts?ids=1%00", CodeInvalidCharacters},
+		{"/api/players/" + fixtures.Alice + "/nadmons?element=Fire%0d%0a", CodeInvalidCharacters},
+		{"/api/leaderboard/packs?sort=%ff", CodeInvalidCharacters},
+		{"/api/leaderboard/pa%09cks", CodeInvalidCharacters},
+		{"/api/leaderboard/packs?page=2&limit=100", ""},
+		{"/api/leaderboard/packs?page=0", CodeInvalidPage},
+		{"/api/leaderboard/packs?limit=0", CodeInvalidLimit},
+		{"/api/leaderboard/packs?limit=101", CodeLimitExceeded},
+		{"/api/leaderboard/packs?address=nope", CodeInvalidAddress},
+		{"/api/webhooks/1/deliveries?limit=200", ""},
+		{"/api/webhooks/1/deliveries?limit=201", CodeLimitExceeded},
+		{"/api/players/" + fixtures.Alice + "/nadmons?element=Fire&rarity=Epic", ""},
+		{"/api/players/" + fixtures.Alice + "/nadmons?element=Plasma", CodeInvalidElement},
+		{"/api/players/" + fixtures.Alice + "/nadmons?rarity=epic", CodeInvalidRarity},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		var body map[string]interface{}
+		json.Unmarshal(w.Body.Bytes(), &body)
+		switch {
+		case tt.code == "" && w.Code != http.StatusOK:
+			t.Errorf("%s: expected 200, got %d %v", tt.path, w.Code, body)
+		case tt.code != "" && (w.Code != status(tt.code) || body["code"] != tt.code):
+			t.Errorf("%s: expected %d %s, got %d %v", tt.path, status(tt.code), tt.code, w.Code, body)
+		}
+	}
+
+	// Bodies over the limit are rejected up front when their length is known, and cut off
+	// while they are read when it isn't
+	bodies := []struct {
+		body   io.Reader
+		status int
+	}{
+		{strings.NewReader(`{"url":"x"}`), http.StatusOK},
+		{strings.NewReader(strings.Repeat("x", 17)), http.StatusRequestEntityTooLarge},
+		{io.MultiReader(strings.NewReader(strings.Repeat("x", 17))), http.StatusBadRequest},
+	}
+	for i, tt := range bodies {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/webhooks", tt.body))
+		if w.Code != tt.status {
+			t.Errorf("body %d: expected %d, got %d %s", i, tt.status, w.Code, w.Body.String())
+		}
+	}
+
+	// Version 1 responses carry the code in the error envelope
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/leaderboard/packs?limit=500", nil))
+	var envelope apiversion.Envelope
+	json.Unmarshal(w.Body.Bytes(), &envelope)
+	if w.Code != http.StatusBadRequest || envelope.Error == nil || envelope.Error.Code != CodeLimitExceeded || envelope.Error.Details["param"] != "limit" {
+		t.Errorf("expected a LIMIT_EXCEEDED envelope, got %d %s", w.Code, w.Body.String())
+	}
+}
+
+// status returns the HTTP status requests rejected with code get
+func status(code string) int {
+	if code == CodeQueryTooLong || code == CodeBodyTooLarge {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
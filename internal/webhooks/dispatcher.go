@@ -0,0 +1,284 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"nadmon-backend/internal/events"
+	"nadmon-backend/internal/models"
+	"nadmon-backend/internal/repository"
+)
+
+// queueSize caps the deliveries waiting for a worker; events beyond it are dropped and logged
+const queueSize = 1024
+
+// refreshInterval is how often the webhook list is reloaded, picking up webhooks registered
+// on other instances
+const refreshInterval = 30 * time.Second
+
+// pruneInterval is how often delivery attempts older than the retention are dropped
+const pruneInterval = time.Hour
+
+// maxResponseBytes caps how much of a receiver's response is read before closing it
+const maxResponseBytes = 64 << 10
+
+// Config tunes delivery
+type Config struct {
+	Workers      int           // concurrent deliveries
+	MaxAttempts  int           // attempts per event, including the first
+	RetryBackoff time.Duration // wait before the first retry; doubled after each further failure
+	Timeout      time.Duration // per attempt
+	LogRetention time.Duration // age after which delivery attempts are pruned; 0 keeps them
+}
+
+// job is one pending delivery attempt
+type job struct {
+	webhook    models.Webhook
+	deliveryID string
+	event      string
+	payload    []byte
+	attempt    int
+}
+
+// Dispatcher matches pipeline events against the registered webhooks and delivers them from a
+// pool of workers. Failed attempts (network errors and non-2xx responses) are retried with
+// exponential backoff up to MaxAttempts; every attempt is recorded in the delivery log.
+type Dispatcher struct {
+	store  repository.WebhookStore
+	config Config
+
+	// client delivers webhooks registered with the admin API key, which may target internal
+	// services; publicClient delivers wallet-registered ones and only reaches public IPs
+	client       *http.Client
+	publicClient *http.Client
+
+	isLeader func() bool
+
+	mu       sync.RWMutex
+	webhooks []models.Webhook
+
+	jobs chan job
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewDispatcher creates a dispatcher delivering the webhooks of store; call Reload and Run to
+// start it
+func NewDispatcher(store repository.WebhookStore, config Config) *Dispatcher {
+	if config.Workers < 1 {
+		config.Workers = 1
+	}
+	if config.MaxAttempts < 1 {
+		config.MaxAttempts = 1
+	}
+
+	// Redirects aren't followed: a receiver must answer itself
+	noRedirects := func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+
+	// Without a proxy, so the IP check sees the receiver's address
+	public := http.DefaultTransport.(*http.Transport).Clone()
+	public.Proxy = nil
+	public.DialContext = (&net.Dialer{Timeout: config.Timeout, Control: publicOnly}).DialContext
+
+	return &Dispatcher{
+		store:        store,
+		config:       config,
+		client:       &http.Client{Timeout: config.Timeout, CheckRedirect: noRedirects},
+		publicClient: &http.Client{Timeout: config.Timeout, CheckRedirect: noRedirects, Transport: public},
+		jobs:         make(chan job, queueSize),
+		done:         make(chan struct{}),
+	}
+}
+
+// SetLeader makes the dispatcher deliver new events only while isLeader returns true, so
+// replicas sharing the webhooks deliver each event once
+func (d *Dispatcher) SetLeader(isLeader func() bool) {
+	d.isLeader = isLeader
+}
+
+// Reload reads the registered webhooks from the store
+func (d *Dispatcher) Reload(ctx context.Context) error {
+	webhooks, err := d.store.GetWebhooks(ctx)
+	if err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	d.webhooks = webhooks
+	d.mu.Unlock()
+	return nil
+}
+
+// Handle queues deliveries of event to the matching webhooks; it is a pipeline subscriber
+// and never blocks
+func (d *Dispatcher) Handle(event events.Event) {
+	if d.isLeader != nil && !d.isLeader() {
+		return
+	}
+	eventType, ok := EventType(event)
+	if !ok {
+		return
+	}
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	for _, webhook := range d.webhooks {
+		if !matches(webhook, eventType, event.Addresses) {
+			continue
+		}
+
+		deliveryID := randomHex(16)
+		payload, err := json.Marshal(Payload{DeliveryID: deliveryID, Event: eventType, OccurredAt: event.OccurredAt, Data: event.Data})
+		if err != nil {
+			log.Printf("Warning: failed to encode webhook payload: %v", err)
+			continue
+		}
+		d.enqueue(job{webhook: webhook, deliveryID: deliveryID, event: eventType, payload: payload, attempt: 1})
+	}
+}
+
+// enqueue hands j to the workers, dropping it when the queue is full or the dispatcher stopped
+func (d *Dispatcher) enqueue(j job) {
+	select {
+	case <-d.done:
+		return
+	default:
+	}
+
+	select {
+	case d.jobs <- j:
+	default:
+		log.Printf("Warning: webhook queue full, dropping %s delivery %s to webhook %d", j.event, j.deliveryID, j.webhook.ID)
+		go d.record(j, 0, 0, "dropped: delivery queue full")
+	}
+}
+
+// Run starts the workers and keeps the webhook list and delivery log up to date until stop
+// is closed; deliveries in flight are finished, pending retries are dropped
+func (d *Dispatcher) Run(stop <-chan struct{}) {
+	for i := 0; i < d.config.Workers; i++ {
+		d.wg.Add(1)
+		go d.work()
+	}
+
+	refresh := time.NewTicker(refreshInterval)
+	defer refresh.Stop()
+	prune := time.NewTicker(pruneInterval)
+	defer prune.Stop()
+
+	for {
+		select {
+		case <-refresh.C:
+			if err := d.Reload(context.Background()); err != nil {
+				log.Printf("Warning: webhook reload: %v", err)
+			}
+		case <-prune.C:
+			d.prune()
+		case <-stop:
+			close(d.done)
+			d.wg.Wait()
+			return
+		}
+	}
+}
+
+// work delivers queued jobs until the dispatcher stops
+func (d *Dispatcher) work() {
+	defer d.wg.Done()
+	for {
+		select {
+		case j := <-d.jobs:
+			d.deliver(j)
+		case <-d.done:
+			return
+		}
+	}
+}
+
+// deliver makes one attempt and schedules a retry if it failed
+func (d *Dispatcher) deliver(j job) {
+	start := time.Now()
+	statusCode, err := d.post(j)
+	duration := time.Since(start)
+
+	if err == nil {
+		d.record(j, statusCode, duration, "")
+		return
+	}
+	d.record(j, statusCode, duration, err.Error())
+
+	if j.attempt >= d.config.MaxAttempts {
+		log.Printf("Warning: giving up on %s delivery %s to webhook %d after %d attempts: %v", j.event, j.deliveryID, j.webhook.ID, j.attempt, err)
+		return
+	}
+	backoff := d.config.RetryBackoff << (j.attempt - 1)
+	j.attempt++
+	time.AfterFunc(backoff, func() { d.enqueue(j) })
+}
+
+// post sends j's payload, signed with the webhook's secret, and returns the response status
+func (d *Dispatcher) post(j job) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, j.webhook.URL, bytes.NewReader(j.payload))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "nadmon-webhooks/1")
+	req.Header.Set(EventHeader, j.event)
+	req.Header.Set(DeliveryHeader, j.deliveryID)
+	req.Header.Set(TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(SignatureHeader, Sign(j.webhook.Secret, timestamp, j.payload))
+
+	client := d.client
+	if j.webhook.Owner != "" {
+		client = d.publicClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBytes))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// record appends an attempt to the delivery log
+func (d *Dispatcher) record(j job, statusCode int, duration time.Duration, errMsg string) {
+	err := d.store.RecordDelivery(context.Background(), models.WebhookDelivery{
+		WebhookID:  j.webhook.ID,
+		DeliveryID: j.deliveryID,
+		Event:      j.event,
+		Attempt:    j.attempt,
+		StatusCode: statusCode,
+		Error:      errMsg,
+		DurationMs: duration.Milliseconds(),
+		Succeeded:  errMsg == "",
+	})
+	if err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
+
+// prune drops delivery attempts older than the retention
+func (d *Dispatcher) prune() {
+	if d.config.LogRetention <= 0 {
+		return
+	}
+	if _, err := d.store.PruneDeliveries(context.Background(), time.Now().Add(-d.config.LogRetention)); err != nil {
+		log.Printf("Warning: %v", err)
+	}
+}
@@ -0,0 +1,131 @@
+//go:build integration
+
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"nadmon-backend/internal/events"
+	"nadmon-backend/internal/fixtures"
+	"nadmon-backend/internal/models"
+	"nadmon-backend/internal/repository"
+	"nadmon-backend/internal/testharness"
+)
+
+func TestDispatcher(t *testing.T) {
+	ctx := context.Background()
+	db := testharness.StartEnvioDB(t)
+	if err := db.SetupAppSchema(); err != nil {
+		t.Fatal(err)
+	}
+	store := repository.NewWebhookRepository(db.DB)
+
+	// The receiver fails the first attempt and accepts the retry
+	var mu sync.Mutex
+	var received []*http.Request
+	var bodies [][]byte
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, r)
+		bodies = append(bodies, body)
+		if len(received) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer receiver.Close()
+
+	admin, err := store.CreateWebhook(ctx, models.Webhook{URL: receiver.URL, Events: []string{models.WebhookEventPack}, Addresses: []string{fixtures.Alice}, Secret: "admin-secret"}, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Wallet-registered webhooks can't reach private addresses such as the test server
+	wallet, err := store.CreateWebhook(ctx, models.Webhook{URL: receiver.URL, Owner: fixtures.Bob, Events: []string{models.WebhookEventPack}, Secret: "wallet-secret"}, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dispatcher := NewDispatcher(store, Config{Workers: 2, MaxAttempts: 2, RetryBackoff: 10 * time.Millisecond, Timeout: time.Second})
+	if err := dispatcher.Reload(ctx); err != nil {
+		t.Fatal(err)
+	}
+	stop := make(chan struct{})
+	defer close(stop)
+	go dispatcher.Run(stop)
+
+	pack := events.PackMintedEvent{PackID: 7, Player: fixtures.Alice, TokenIDs: []int64{1, 2}, PaymentType: "MON"}
+	dispatcher.Handle(events.Event{Type: events.TypePackMinted, Addresses: []string{fixtures.Alice}, Data: pack, OccurredAt: time.Now()})
+	// Neither webhook wants fusions
+	dispatcher.Handle(events.Event{Type: events.TypeStatsChanged, Addresses: []string{fixtures.Alice}, Data: events.StatsChangedEvent{ChangeType: "fusion"}})
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		adminLog, _ := store.GetDeliveries(ctx, admin.ID, 10)
+		walletLog, _ := store.GetDeliveries(ctx, wallet.ID, 10)
+		if len(adminLog) == 2 && len(walletLog) == 2 {
+			if !adminLog[0].Succeeded || adminLog[0].Attempt != 2 || adminLog[1].StatusCode != http.StatusInternalServerError {
+				t.Errorf("expected a failed attempt and a successful retry, got %+v", adminLog)
+			}
+			if adminLog[0].DeliveryID != adminLog[1].DeliveryID {
+				t.Errorf("expected retries to share the delivery ID, got %+v", adminLog)
+			}
+			if walletLog[0].Succeeded || !strings.Contains(walletLog[0].Error, errPrivateAddress.Error()) {
+				t.Errorf("expected the private address to be refused, got %+v", walletLog)
+			}
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 2 attempts per webhook, got %+v and %+v", adminLog, walletLog)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(received))
+	}
+	req, body := received[1], bodies[1]
+	timestamp, _ := strconv.ParseInt(req.Header.Get(TimestampHeader), 10, 64)
+	if req.Header.Get(SignatureHeader) != Sign("admin-secret", timestamp, body) {
+		t.Errorf("expected a valid signature, got %q", req.Header.Get(SignatureHeader))
+	}
+	var payload struct {
+		Event string                 `json:"event"`
+		Data  events.PackMintedEvent `json:"data"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if req.Header.Get(EventHeader) != models.WebhookEventPack || payload.Event != models.WebhookEventPack || payload.Data.PackID != 7 {
+		t.Errorf("expected the pack event, got %s %s", req.Header.Get(EventHeader), body)
+	}
+}
+
+func TestEventType(t *testing.T) {
+	tests := []struct {
+		event events.Event
+		want  string
+	}{
+		{events.Event{Type: events.TypeNFTMinted}, models.WebhookEventMint},
+		{events.Event{Type: events.TypePackMinted}, models.WebhookEventPack},
+		{events.Event{Type: events.TypeNFTTransferred}, models.WebhookEventTransfer},
+		{events.Event{Type: events.TypeStatsChanged, Data: events.StatsChangedEvent{ChangeType: "evolution"}}, models.WebhookEventEvolution},
+		{events.Event{Type: events.TypeStatsChanged, Data: events.StatsChangedEvent{ChangeType: "fusion"}}, ""},
+	}
+	for _, tt := range tests {
+		if got, _ := EventType(tt.event); got != tt.want {
+			t.Errorf("EventType(%s): expected %q, got %q", tt.event.Type, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// Package webhooks delivers indexer events to the callback URLs registered by third-party
+// integrations, signing every request and retrying failed deliveries.
+package webhooks
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net"
+	"syscall"
+	"time"
+
+	"nadmon-backend/internal/auth"
+	"nadmon-backend/internal/events"
+	"nadmon-backend/internal/models"
+)
+
+// Headers sent with every delivery
+const (
+	EventHeader     = "X-Nadmon-Event"
+	DeliveryHeader  = "X-Nadmon-Delivery"
+	TimestampHeader = auth.TimestampHeader
+	SignatureHeader = auth.SignatureHeader
+)
+
+// Payload is the JSON body of a delivery; Data is the same payload as the matching WebSocket
+// message
+type Payload struct {
+	DeliveryID string      `json:"delivery_id"`
+	Event      string      `json:"event"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+// EventType returns the webhook event type of a pipeline event; ok is false for events
+// webhooks don't carry, such as fusions
+func EventType(event events.Event) (eventType string, ok bool) {
+	switch event.Type {
+	case events.TypeNFTMinted:
+		return models.WebhookEventMint, true
+	case events.TypePackMinted:
+		return models.WebhookEventPack, true
+	case events.TypeNFTTransferred:
+		return models.WebhookEventTransfer, true
+	case events.TypeStatsChanged:
+		if change, isChange := event.Data.(events.StatsChangedEvent); isChange && change.ChangeType == "evolution" {
+			return models.WebhookEventEvolution, true
+		}
+	}
+	return "", false
+}
+
+// Sign returns the SignatureHeader value of a delivery: "sha256=" and the hex HMAC-SHA256 of
+// the TimestampHeader value, a dot and the body, keyed with the webhook's secret. Receivers
+// should recompute it and reject old timestamps to prevent replays.
+func Sign(secret string, timestamp int64, body []byte) string {
+	return auth.Sign(secret, timestamp, body)
+}
+
+// NewSecret returns a random signing secret for a new webhook
+func NewSecret() string {
+	return randomHex(32)
+}
+
+// randomHex returns n random bytes, hex-encoded
+func randomHex(n int) string {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// matches reports whether webhook subscribed to an event of eventType involving addresses
+func matches(webhook models.Webhook, eventType string, addresses []string) bool {
+	subscribed := false
+	for _, t := range webhook.Events {
+		if t == eventType {
+			subscribed = true
+			break
+		}
+	}
+	if !subscribed {
+		return false
+	}
+	if len(webhook.Addresses) == 0 {
+		return true
+	}
+	for _, filter := range webhook.Addresses {
+		for _, address := range addresses {
+			if filter == address {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// errPrivateAddress is returned when a wallet-registered webhook resolves to a non-public IP
+var errPrivateAddress = errors.New("webhook URL resolves to a non-public address")
+
+// publicOnly is a net.Dialer Control function refusing connections to loopback, private,
+// link-local and other non-public IPs. It runs after DNS resolution, so a public hostname
+// pointing at an internal address is refused too.
+func publicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return errPrivateAddress
+	}
+	return nil
+}
@@ -2,38 +2,102 @@ package websocket
 
 import (
 	"encoding/json"
+	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"nadmon-backend/internal/ethaddr"
+	"nadmon-backend/internal/origins"
+
 	"github.com/gorilla/websocket"
 )
 
-// Message represents a WebSocket message
+// Message represents a WebSocket message. Notifications and broadcasts carry an ID that
+// increases with every message sent, which a reconnecting client passes as resume_from, and
+// the ID of the chain they happened on. Typed payloads carry their schema version as v.
 type Message struct {
+	ID        uint64      `json:"id,omitempty"`
+	Chain     string      `json:"chain,omitempty"`
 	Type      string      `json:"type"`
+	Version   int         `json:"v,omitempty"`
 	Data      interface{} `json:"data"`
 	Timestamp time.Time   `json:"timestamp"`
 }
 
-// Client represents a WebSocket client
+// MessageNotice is the type of operator announcements broadcast to every client
+const MessageNotice = "notice"
+
+// Notice is an operator announcement, e.g. "indexer maintenance in 5 minutes"
+type Notice struct {
+	Message  string     `json:"message"`
+	Level    string     `json:"level"`               // info, warning or critical
+	StartsAt *time.Time `json:"starts_at,omitempty"` // when the announced event begins
+}
+
+// Client represents a subscriber to an address's messages, or to broadcasts only when
+// Address is empty
 type Client struct {
 	ID      string
-	Address string // Ethereum address
-	Conn    *websocket.Conn
+	Address string          // Ethereum address; empty for public clients
+	Conn    *websocket.Conn // nil for streaming clients such as Server-Sent Events
 	Send    chan Message
 	Manager *Manager
+
+	ConnectedAt time.Time
+
+	subscription    Subscription
+	replayedThrough uint64 // live messages up to this ID were replayed already
+}
+
+// Subscription selects the messages a connection receives
+type Subscription struct {
+	// Chain limits the connection to the messages of one chain; empty for every chain
+	Chain string
+	// ResumeFrom replays the messages sent after this ID on connecting; NoResume to skip
+	ResumeFrom int64
+	// Encoding is how a WebSocket connection's messages are encoded, EncodingJSON when empty
+	Encoding string
+}
+
+// wants reports whether message is for c's chain and hasn't been replayed to c already; the
+// caller holds m.mu
+func (c *Client) wants(message Message) bool {
+	if c.subscription.Chain != "" && message.Chain != "" && message.Chain != c.subscription.Chain {
+		return false
+	}
+	return message.ID == 0 || message.ID > c.replayedThrough
 }
 
 // Manager manages WebSocket connections
 type Manager struct {
 	clients        map[string]*Client // Map of address -> client
+	public         map[*Client]bool   // Clients without an address, which only receive broadcasts
 	register       chan *Client
 	unregister     chan *Client
 	broadcast      chan Message
-	allowedOrigins []string
+	allowedOrigins *origins.List
 	mu             sync.RWMutex
+
+	// shouldDrop, when set, decides whether an outgoing notification is discarded (fault injection)
+	shouldDrop func() bool
+
+	// sendBuffer is the number of messages queued per client before it is considered too slow
+	sendBuffer int
+
+	// payloads are the registered payload types by message type
+	payloads map[string]Payload
+
+	// Recent messages per address, and for broadcasts under broadcastReplay, numbered by lastID
+	replayMu     sync.Mutex
+	lastID       uint64
+	replays      map[string]*replayBuffer
+	replaySize   int
+	replayWindow time.Duration
 }
 
 // getWebSocketUpgrader creates a WebSocket upgrader with dynamic CORS support
@@ -42,35 +106,58 @@ func (m *Manager) getWebSocketUpgrader() websocket.Upgrader {
 		ReadBufferSize:  1024,
 		WriteBufferSize: 1024,
 		CheckOrigin: func(r *http.Request) bool {
-			origin := r.Header.Get("Origin")
-			
-			for _, allowed := range m.allowedOrigins {
-				if origin == allowed {
-					return true
-				}
-			}
-			return false
+			return m.allowedOrigins.Allowed(r.Header.Get("Origin"))
 		},
 	}
 }
 
 // NewManager creates a new WebSocket manager
-func NewManager(allowedOrigins []string) *Manager {
+func NewManager(allowedOrigins *origins.List) *Manager {
 	return &Manager{
 		clients:        make(map[string]*Client),
+		public:         make(map[*Client]bool),
 		register:       make(chan *Client),
 		unregister:     make(chan *Client),
 		broadcast:      make(chan Message),
 		allowedOrigins: allowedOrigins,
+		sendBuffer:     256,
+		payloads:       map[string]Payload{MessageNotice: Notice{}},
+		replays:        make(map[string]*replayBuffer),
+		replaySize:     defaultReplaySize,
+		replayWindow:   defaultReplayWindow,
+	}
+}
+
+// SetSendBuffer sets how many outgoing messages are queued per client (at least 1); call it
+// before Start
+func (m *Manager) SetSendBuffer(n int) {
+	if n > 0 {
+		m.sendBuffer = n
 	}
 }
 
+// SetDropFunc installs a hook that can discard outgoing notifications, used for fault injection
+func (m *Manager) SetDropFunc(shouldDrop func() bool) {
+	m.shouldDrop = shouldDrop
+}
+
+// dropped reports whether a notification should be discarded
+func (m *Manager) dropped() bool {
+	return m.shouldDrop != nil && m.shouldDrop()
+}
+
 // Start starts the WebSocket manager
 func (m *Manager) Start() {
 	log.Println("🔌 WebSocket manager started")
 
+	prune := time.NewTicker(time.Minute)
+	defer prune.Stop()
+
 	for {
 		select {
+		case <-prune.C:
+			m.pruneReplays()
+
 		case client := <-m.register:
 			m.registerClient(client)
 
@@ -88,10 +175,15 @@ func (m *Manager) registerClient(client *Client) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
+	if client.Address == "" {
+		m.registerPublicClient(client)
+		return
+	}
+
 	// If there's already a client for this address, close the old connection
 	if existingClient, exists := m.clients[client.Address]; exists {
 		close(existingClient.Send)
-		existingClient.Conn.Close()
+		existingClient.closeConn()
 	}
 
 	m.clients[client.Address] = client
@@ -100,64 +192,134 @@ func (m *Manager) registerClient(client *Client) {
 	// Send welcome message
 	welcomeMsg := Message{
 		Type:      "connected",
-		Data:      map[string]string{"address": client.Address, "status": "connected"},
+		Data:      map[string]string{"address": client.Address, "status": "connected", "scope": "private"},
 		Timestamp: time.Now(),
 	}
 
 	select {
 	case client.Send <- welcomeMsg:
+		m.resume(client)
 	default:
 		close(client.Send)
 		delete(m.clients, client.Address)
 	}
 }
 
+// registerPublicClient registers a client for broadcasts only; the caller holds m.mu
+func (m *Manager) registerPublicClient(client *Client) {
+	m.public[client] = true
+	log.Printf("✅ Public client connected (Total: %d)", len(m.clients)+len(m.public))
+
+	welcomeMsg := Message{
+		Type:      "connected",
+		Data:      map[string]string{"status": "connected", "scope": "public"},
+		Timestamp: time.Now(),
+	}
+
+	select {
+	case client.Send <- welcomeMsg:
+		m.resume(client)
+	default:
+		close(client.Send)
+		delete(m.public, client)
+	}
+}
+
 // unregisterClient unregisters a client
 func (m *Manager) unregisterClient(client *Client) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	if _, exists := m.clients[client.Address]; exists {
+	if client.Address == "" {
+		if m.public[client] {
+			delete(m.public, client)
+			close(client.Send)
+			client.closeConn()
+			log.Printf("❌ Public client disconnected (Total: %d)", len(m.clients)+len(m.public))
+		}
+		return
+	}
+
+	// Only remove the client if it hasn't already been replaced by a newer connection
+	if existing, exists := m.clients[client.Address]; exists && existing == client {
 		delete(m.clients, client.Address)
 		close(client.Send)
-		client.Conn.Close()
+		client.closeConn()
 		log.Printf("❌ Client disconnected: %s (Total: %d)", client.Address, len(m.clients))
 	}
 }
 
 // broadcastMessage broadcasts a message to all clients
 func (m *Manager) broadcastMessage(message Message) {
+	var slow []*Client
 	m.mu.RLock()
-	defer m.mu.RUnlock()
+	for _, client := range m.clients {
+		if m.dropped() || !client.wants(message) {
+			continue
+		}
 
-	for address, client := range m.clients {
 		select {
 		case client.Send <- message:
 		default:
-			close(client.Send)
-			delete(m.clients, address)
+			slow = append(slow, client)
 		}
 	}
-}
 
-// NotifyUser sends a message to a specific user
-func (m *Manager) NotifyUser(address string, messageType string, data interface{}) {
-	m.mu.RLock()
-	client, exists := m.clients[address]
+	for client := range m.public {
+		if m.dropped() || !client.wants(message) {
+			continue
+		}
+
+		select {
+		case client.Send <- message:
+		default:
+			slow = append(slow, client)
+		}
+	}
 	m.mu.RUnlock()
 
-	if !exists {
-		return // User not connected
+	// Clients whose send channel is blocked are removed under the write lock, skipping any
+	// disconnected in the meantime
+	for _, client := range slow {
+		m.unregisterClient(client)
 	}
+}
 
-	message := Message{
-		Type:      messageType,
-		Data:      data,
-		Timestamp: time.Now(),
+// NotifyUser sends a message to a specific user. It is kept for replay even when the user
+// isn't connected, so they get it on reconnecting with resume_from.
+func (m *Manager) NotifyUser(address string, messageType string, data interface{}) {
+	m.NotifyUserOnChain("", address, messageType, data)
+}
+
+// NotifyUserOnChain sends a message about an event on chain to a specific user; connections
+// subscribed to another chain don't receive it
+func (m *Manager) NotifyUserOnChain(chain, address string, messageType string, data interface{}) {
+	address = ethaddr.Normalize(address)
+
+	message := m.record(address, m.newMessage(chain, messageType, data))
+
+	// Sent under the read lock: Send is only closed under the write lock, so the client
+	// can't be disconnected between the lookup and the send
+	m.mu.RLock()
+	client, exists := m.clients[address]
+	wanted := exists && client.wants(message)
+	dropped := wanted && m.dropped()
+	sent := false
+	if wanted && !dropped {
+		select {
+		case client.Send <- message:
+			sent = true
+		default:
+		}
 	}
+	m.mu.RUnlock()
 
-	select {
-	case client.Send <- message:
+	switch {
+	case !wanted:
+		// User not connected, on another chain, or the message was replayed already
+	case dropped:
+		log.Printf("💥 Dropped %s to %s (fault injection)", messageType, address)
+	case sent:
 		log.Printf("📤 Sent %s to %s", messageType, address)
 	default:
 		// Client's send channel is blocked, remove client
@@ -167,11 +329,12 @@ func (m *Manager) NotifyUser(address string, messageType string, data interface{
 
 // BroadcastToAll sends a message to all connected clients
 func (m *Manager) BroadcastToAll(messageType string, data interface{}) {
-	message := Message{
-		Type:      messageType,
-		Data:      data,
-		Timestamp: time.Now(),
-	}
+	m.BroadcastOnChain("", messageType, data)
+}
+
+// BroadcastOnChain sends a message about an event on chain to all clients subscribed to it
+func (m *Manager) BroadcastOnChain(chain, messageType string, data interface{}) {
+	message := m.record(broadcastReplay, m.newMessage(chain, messageType, data))
 
 	m.broadcast <- message
 }
@@ -181,6 +344,11 @@ func (m *Manager) GetConnectedUsers() []string {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
+	return m.connectedUsers()
+}
+
+// connectedUsers lists connected addresses; the caller holds m.mu
+func (m *Manager) connectedUsers() []string {
 	users := make([]string, 0, len(m.clients))
 	for address := range m.clients {
 		users = append(users, address)
@@ -188,6 +356,14 @@ func (m *Manager) GetConnectedUsers() []string {
 	return users
 }
 
+// ConnectedCount returns the number of connected clients, public ones included
+func (m *Manager) ConnectedCount() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return len(m.clients) + len(m.public)
+}
+
 // GetStats returns WebSocket manager statistics
 func (m *Manager) GetStats() map[string]interface{} {
 	m.mu.RLock()
@@ -195,25 +371,126 @@ func (m *Manager) GetStats() map[string]interface{} {
 
 	return map[string]interface{}{
 		"connected_clients": len(m.clients),
-		"connected_users":   m.GetConnectedUsers(),
+		"public_clients":    len(m.public),
+		"connected_users":   m.connectedUsers(),
+	}
+}
+
+// Connection transports
+const (
+	TransportWebSocket = "websocket"
+	TransportSSE       = "sse"
+)
+
+// Subscription topics: private connections get their address's notifications on top of the
+// broadcasts every connection gets
+const (
+	TopicNotifications = "notifications"
+	TopicBroadcasts    = "broadcasts"
+)
+
+// ConnectionInfo describes a connected client
+type ConnectionInfo struct {
+	ID          string    `json:"id"`
+	Address     string    `json:"address,omitempty"`
+	Transport   string    `json:"transport"`
+	Topics      []string  `json:"topics"`
+	Chain       string    `json:"chain,omitempty"` // empty when subscribed to every chain
+	Encoding    string    `json:"encoding"`
+	ConnectedAt time.Time `json:"connected_at"`
+	Queued      int       `json:"queued_messages"`
+}
+
+// Connections lists the connected clients, oldest first
+func (m *Manager) Connections() []ConnectionInfo {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	connections := make([]ConnectionInfo, 0, len(m.clients)+len(m.public))
+	for _, client := range m.clients {
+		connections = append(connections, client.info())
+	}
+	for client := range m.public {
+		connections = append(connections, client.info())
+	}
+	sort.Slice(connections, func(i, j int) bool {
+		return connections[i].ConnectedAt.Before(connections[j].ConnectedAt)
+	})
+	return connections
+}
+
+// info describes c; the caller holds m.mu
+func (c *Client) info() ConnectionInfo {
+	info := ConnectionInfo{
+		ID:          c.ID,
+		Address:     c.Address,
+		Transport:   TransportWebSocket,
+		Topics:      []string{TopicBroadcasts},
+		Chain:       c.subscription.Chain,
+		Encoding:    EncodingJSON,
+		ConnectedAt: c.ConnectedAt,
+		Queued:      len(c.Send),
 	}
+	if c.Conn == nil {
+		info.Transport = TransportSSE
+	} else if c.subscription.Encoding != "" {
+		info.Encoding = c.subscription.Encoding
+	}
+	if c.Address != "" {
+		info.Topics = []string{TopicNotifications, TopicBroadcasts}
+	}
+	return info
+}
+
+// Disconnect closes the connection of address after telling it why with a "disconnected"
+// message, and reports whether address was connected. The client may reconnect right away
+// with a valid stream token.
+func (m *Manager) Disconnect(address, reason string) bool {
+	address = ethaddr.Normalize(address)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	client, exists := m.clients[address]
+	if !exists {
+		return false
+	}
+	delete(m.clients, address)
+
+	select {
+	case client.Send <- Message{
+		Type:      "disconnected",
+		Data:      map[string]string{"reason": reason},
+		Timestamp: time.Now(),
+	}:
+	default:
+	}
+	// Closing Send rather than the connection lets the write pump flush the queued messages,
+	// the notice last, before it sends the close frame
+	close(client.Send)
+	log.Printf("🔌 Client disconnected by an admin: %s (Total: %d)", address, len(m.clients))
+	return true
 }
 
-// UpgradeConnection upgrades HTTP connection to WebSocket
-func (m *Manager) UpgradeConnection(w http.ResponseWriter, r *http.Request, address string) {
+// UpgradeConnection upgrades HTTP connection to WebSocket; an empty address opens a
+// public connection that only receives broadcasts. subscription selects the chain and the
+// messages to replay first.
+func (m *Manager) UpgradeConnection(w http.ResponseWriter, r *http.Request, address string, subscription Subscription) {
 	upgrader := m.getWebSocketUpgrader()
 	conn, err := upgrader.Upgrade(w, r, nil)
 	if err != nil {
-		log.Printf("❌ WebSocket upgrade failed: %v", err)
+		slog.Error("❌ WebSocket upgrade failed", "error", err)
 		return
 	}
 
 	client := &Client{
-		ID:      generateClientID(),
-		Address: address,
-		Conn:    conn,
-		Send:    make(chan Message, 256),
-		Manager: m,
+		ID:           generateClientID(),
+		Address:      ethaddr.Normalize(address),
+		Conn:         conn,
+		Send:         make(chan Message, m.sendBuffer),
+		Manager:      m,
+		ConnectedAt:  time.Now(),
+		subscription: subscription,
 	}
 
 	// Register the client
@@ -224,6 +501,35 @@ func (m *Manager) UpgradeConnection(w http.ResponseWriter, r *http.Request, addr
 	go client.readPump()
 }
 
+// Subscribe registers a streaming client for address (empty for broadcasts only) that
+// receives the same messages as a WebSocket client on its Send channel, selected by
+// subscription. The channel is closed when the client is replaced or unsubscribed; call
+// Unsubscribe when the stream ends.
+func (m *Manager) Subscribe(address string, subscription Subscription) *Client {
+	client := &Client{
+		ID:           generateClientID(),
+		Address:      ethaddr.Normalize(address),
+		Send:         make(chan Message, m.sendBuffer),
+		Manager:      m,
+		ConnectedAt:  time.Now(),
+		subscription: subscription,
+	}
+	m.register <- client
+	return client
+}
+
+// Unsubscribe removes a client registered with Subscribe
+func (m *Manager) Unsubscribe(client *Client) {
+	m.unregister <- client
+}
+
+// closeConn closes the underlying WebSocket connection, if any
+func (c *Client) closeConn() {
+	if c.Conn != nil {
+		c.Conn.Close()
+	}
+}
+
 // readPump handles reading messages from the WebSocket connection
 func (c *Client) readPump() {
 	defer func() {
@@ -240,17 +546,22 @@ func (c *Client) readPump() {
 
 	for {
 		// Read message from client
-		_, messageBytes, err := c.Conn.ReadMessage()
+		frameType, messageBytes, err := c.Conn.ReadMessage()
 		if err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
-				log.Printf("❌ WebSocket error: %v", err)
+				slog.Error("❌ WebSocket error", "address", c.Address, "error", err)
 			}
 			break
 		}
 
-		// Parse client message
+		// Parse client message; binary frames are MessagePack
 		var clientMessage map[string]interface{}
-		if err := json.Unmarshal(messageBytes, &clientMessage); err != nil {
+		if frameType == websocket.BinaryMessage {
+			err = decodeMsgpack(messageBytes, &clientMessage)
+		} else {
+			err = json.Unmarshal(messageBytes, &clientMessage)
+		}
+		if err != nil {
 			log.Printf("⚠️ Invalid message from client %s: %v", c.Address, err)
 			continue
 		}
@@ -278,9 +589,8 @@ func (c *Client) writePump() {
 				return
 			}
 
-			// Send message as JSON
-			if err := c.Conn.WriteJSON(message); err != nil {
-				log.Printf("❌ Write error for client %s: %v", c.Address, err)
+			if err := c.write(message); err != nil {
+				slog.Error("❌ Write error", "address", c.Address, "error", err)
 				return
 			}
 
@@ -293,6 +603,18 @@ func (c *Client) writePump() {
 	}
 }
 
+// write sends message in the connection's encoding
+func (c *Client) write(message Message) error {
+	if c.subscription.Encoding != EncodingMsgpack {
+		return c.Conn.WriteJSON(message)
+	}
+	encoded, err := encodeMsgpack(message)
+	if err != nil {
+		return err
+	}
+	return c.Conn.WriteMessage(websocket.BinaryMessage, encoded)
+}
+
 // handleClientMessage processes messages received from clients
 func (c *Client) handleClientMessage(message map[string]interface{}) {
 	messageType, ok := message["type"].(string)
@@ -322,7 +644,11 @@ func (c *Client) handleClientMessage(message map[string]interface{}) {
 	}
 }
 
+// clientSeq numbers the clients of the process, keeping IDs of clients connecting in the same
+// second apart
+var clientSeq uint64
+
 // generateClientID generates a unique client ID
 func generateClientID() string {
-	return time.Now().Format("20060102150405") + "-" + "client"
+	return fmt.Sprintf("%s-client-%d", time.Now().Format("20060102150405"), atomic.AddUint64(&clientSeq, 1))
 }
@@ -0,0 +1,326 @@
+package websocket
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"nadmon-backend/internal/origins"
+
+	"github.com/gorilla/websocket"
+)
+
+const alice = "0x1111111111111111111111111111111111111111"
+
+// receive returns the next n messages queued for client
+func receive(t *testing.T, client *Client, n int) []Message {
+	t.Helper()
+	var messages []Message
+	for len(messages) < n {
+		select {
+		case message := <-client.Send:
+			messages = append(messages, message)
+		case <-time.After(2 * time.Second):
+			t.Fatalf("expected %d messages, got %+v", n, messages)
+		}
+	}
+	return messages
+}
+
+func TestResume(t *testing.T) {
+	manager := NewManager(nil)
+	go manager.Start()
+
+	// Sent while alice is offline: 1, 2 and 4 are hers, 3 is a broadcast
+	manager.NotifyUser(alice, "pack_purchased", 1)
+	manager.NotifyUser(alice, "pack_purchased", 2)
+	manager.BroadcastToAll("stats_updated", 3)
+	manager.NotifyUser("0x2222222222222222222222222222222222222222", "pack_purchased", 0)
+	manager.NotifyUser(alice, "pack_purchased", 4)
+
+	client := manager.Subscribe(alice, Subscription{ResumeFrom: 1})
+	messages := receive(t, client, 5)
+	if messages[0].Type != "connected" {
+		t.Fatalf("expected the welcome message first, got %+v", messages[0])
+	}
+	var ids []uint64
+	for _, message := range messages[1:4] {
+		ids = append(ids, message.ID)
+	}
+	if len(ids) != 3 || ids[0] != 2 || ids[1] != 3 || ids[2] != 5 {
+		t.Errorf("expected messages 2, 3 and 5 replayed in order, got %v", ids)
+	}
+	resumed, _ := messages[4].Data.(map[string]interface{})
+	if messages[4].Type != "resumed" || resumed["replayed"] != 3 || resumed["complete"] != true || resumed["last_id"] != uint64(5) {
+		t.Errorf("expected a complete resume through 5, got %+v", messages[4])
+	}
+
+	// Live messages keep numbering after the replay
+	manager.NotifyUser(alice, "pack_purchased", 6)
+	if live := receive(t, client, 1)[0]; live.ID != 6 {
+		t.Errorf("expected live message 6, got %+v", live)
+	}
+	manager.Unsubscribe(client)
+}
+
+func TestResumeIncomplete(t *testing.T) {
+	manager := NewManager(nil)
+	manager.SetReplay(2, time.Minute)
+	go manager.Start()
+
+	for i := 0; i < 4; i++ {
+		manager.NotifyUser(alice, "pack_purchased", i)
+	}
+
+	tests := []struct {
+		name       string
+		resumeFrom int64
+		replayed   int
+		complete   bool
+	}{
+		{"evicted", 0, 2, false},
+		{"buffered", 2, 2, true},
+		{"up to date", 4, 0, true},
+		{"restarted", 9, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := manager.Subscribe(alice, Subscription{ResumeFrom: tt.resumeFrom})
+			defer manager.Unsubscribe(client)
+
+			messages := receive(t, client, tt.replayed+2)
+			resumed, _ := messages[len(messages)-1].Data.(map[string]interface{})
+			if resumed["replayed"] != tt.replayed || resumed["complete"] != tt.complete {
+				t.Errorf("expected %d replayed (complete %v), got %+v", tt.replayed, tt.complete, resumed)
+			}
+		})
+	}
+}
+
+func TestChainSubscription(t *testing.T) {
+	manager := NewManager(nil)
+	go manager.Start()
+
+	client := manager.Subscribe(alice, Subscription{Chain: "1", ResumeFrom: NoResume})
+	defer manager.Unsubscribe(client)
+	receive(t, client, 1) // connected
+
+	manager.NotifyUserOnChain("10143", alice, "pack_purchased", 1)
+	manager.NotifyUserOnChain("1", alice, "pack_purchased", 2)
+	manager.NotifyUser(alice, "pack_purchased", 3)
+
+	messages := receive(t, client, 2)
+	if messages[0].Chain != "1" || messages[0].Data != 2 || messages[1].Chain != "" || messages[1].Data != 3 {
+		t.Errorf("expected chain 1's message and the untagged one, got %+v", messages)
+	}
+	select {
+	case message := <-client.Send:
+		t.Errorf("expected no message from another chain, got %+v", message)
+	default:
+	}
+}
+
+func TestDisconnect(t *testing.T) {
+	manager := NewManager(nil)
+	go manager.Start()
+
+	public := manager.Subscribe("", Subscription{Chain: "1", ResumeFrom: NoResume})
+	defer manager.Unsubscribe(public)
+	private := manager.Subscribe(alice, Subscription{ResumeFrom: NoResume})
+	receive(t, public, 1)
+	receive(t, private, 1)
+
+	connections := manager.Connections()
+	if len(connections) != 2 || connections[0].ID == connections[1].ID {
+		t.Fatalf("expected 2 distinct connections, got %+v", connections)
+	}
+	if c := connections[0]; c.Address != "" || c.Chain != "1" || c.Transport != TransportSSE || len(c.Topics) != 1 {
+		t.Errorf("expected the public chain 1 stream first, got %+v", c)
+	}
+	if c := connections[1]; c.Address != alice || len(c.Topics) != 2 || c.ConnectedAt.IsZero() {
+		t.Errorf("expected alice's private stream, got %+v", c)
+	}
+
+	if !manager.Disconnect(alice, "maintenance") {
+		t.Fatal("expected alice to be disconnected")
+	}
+	if notice := receive(t, private, 1)[0]; notice.Type != "disconnected" {
+		t.Errorf("expected the disconnect notice, got %+v", notice)
+	}
+	if _, open := <-private.Send; open {
+		t.Error("expected alice's channel to be closed")
+	}
+	if manager.Disconnect(alice, "maintenance") {
+		t.Error("expected alice to be gone")
+	}
+	if len(manager.Connections()) != 1 {
+		t.Errorf("expected only the public stream left, got %+v", manager.Connections())
+	}
+}
+
+func TestDisconnectWhileNotifying(t *testing.T) {
+	manager := NewManager(nil)
+	go manager.Start()
+
+	client := manager.Subscribe(alice, Subscription{ResumeFrom: NoResume})
+	receive(t, client, 1)
+
+	// An admin disconnect lands while the notification is being sent, where fault injection
+	// is checked; the send must neither panic nor reach the closed channel
+	var once sync.Once
+	manager.SetDropFunc(func() bool {
+		once.Do(func() {
+			go manager.Disconnect(alice, "maintenance")
+			time.Sleep(50 * time.Millisecond)
+		})
+		return false
+	})
+	manager.NotifyUser(alice, "pack_purchased", 1)
+
+	var types []string
+	for message := range client.Send {
+		types = append(types, message.Type)
+	}
+	if len(types) != 2 || types[0] != "pack_purchased" || types[1] != "disconnected" {
+		t.Errorf("expected the notification then the disconnect notice, got %v", types)
+	}
+}
+
+func TestBroadcastDropsSlowClients(t *testing.T) {
+	manager := NewManager(nil)
+	manager.SetSendBuffer(1)
+	go manager.Start()
+
+	// Neither reads its welcome message, so both are too slow for the next broadcast
+	public := manager.Subscribe("", Subscription{ResumeFrom: NoResume})
+	private := manager.Subscribe(alice, Subscription{ResumeFrom: NoResume})
+	for manager.ConnectedCount() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			manager.broadcastMessage(Message{Type: "stats_updated", Data: i, Timestamp: time.Now()})
+		}(i)
+	}
+	wg.Wait()
+
+	if n := manager.ConnectedCount(); n != 0 {
+		t.Errorf("expected the slow clients removed, got %d connected", n)
+	}
+	for _, client := range []*Client{public, private} {
+		<-client.Send // welcome
+		if _, open := <-client.Send; open {
+			t.Error("expected the slow client's channel closed")
+		}
+	}
+}
+
+// sold is a typed payload for the tests
+type sold struct {
+	TokenID int64  `json:"tokenId"`
+	Buyer   string `json:"buyer"`
+	Note    string `json:"note,omitempty"`
+}
+
+func (sold) SchemaVersion() int { return 2 }
+
+func TestTypedPayloads(t *testing.T) {
+	manager := NewManager(nil)
+	manager.RegisterPayload("sold", sold{})
+	go manager.Start()
+
+	client := manager.Subscribe(alice, Subscription{ResumeFrom: NoResume})
+	defer manager.Unsubscribe(client)
+	receive(t, client, 1) // connected
+
+	// Payloads fanned out from another replica arrive as JSON
+	manager.NotifyUser(alice, "sold", json.RawMessage(`{"tokenId":5,"buyer":"0xbob"}`))
+	manager.NotifyUser(alice, "pack_purchased", json.RawMessage(`{"packId":7}`))
+	messages := receive(t, client, 2)
+	if payload, ok := messages[0].Data.(sold); !ok || payload.TokenID != 5 || messages[0].Version != 2 {
+		t.Errorf("expected a typed version 2 payload, got %+v", messages[0])
+	}
+	if payload, ok := messages[1].Data.(map[string]interface{}); !ok || payload["packId"] != float64(7) || messages[1].Version != 0 {
+		t.Errorf("expected an untyped payload without a version, got %+v", messages[1])
+	}
+
+	schemas := manager.Schemas()
+	if len(schemas) != 2 || schemas[0].Type != MessageNotice || schemas[1].Type != "sold" || schemas[1].Version != 2 {
+		t.Fatalf("expected the notice and sold schemas, got %+v", schemas)
+	}
+	required, _ := schemas[1].Schema["required"].([]string)
+	if len(required) != 2 || required[0] != "buyer" || required[1] != "tokenId" {
+		t.Errorf("expected buyer and tokenId required, got %+v", schemas[1].Schema)
+	}
+}
+
+func TestMsgpackEncoding(t *testing.T) {
+	manager := NewManager(origins.New([]string{"http://game.test"}))
+	manager.RegisterPayload("sold", sold{})
+	go manager.Start()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		manager.UpgradeConnection(w, r, alice, Subscription{ResumeFrom: NoResume, Encoding: EncodingMsgpack})
+	}))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), http.Header{"Origin": {"http://game.test"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	read := func(v interface{}) {
+		t.Helper()
+		conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		frameType, data, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if frameType != websocket.BinaryMessage {
+			t.Fatalf("expected a binary frame, got %d: %s", frameType, data)
+		}
+		if err := decodeMsgpack(data, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var welcome Message
+	if read(&welcome); welcome.Type != "connected" {
+		t.Fatalf("expected the welcome message, got %+v", welcome)
+	}
+
+	// Keys are the JSON ones
+	manager.NotifyUser(alice, "sold", sold{TokenID: 5, Buyer: "0xbob"})
+	var message struct {
+		Type      string    `json:"type"`
+		Version   int       `json:"v"`
+		Data      sold      `json:"data"`
+		Timestamp time.Time `json:"timestamp"`
+	}
+	read(&message)
+	if message.Type != "sold" || message.Version != 2 || message.Data.TokenID != 5 || message.Data.Buyer != "0xbob" || message.Timestamp.IsZero() {
+		t.Errorf("expected the sold message, got %+v", message)
+	}
+
+	// Clients may ping in MessagePack
+	ping, err := encodeMsgpack(map[string]interface{}{"type": "ping"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := conn.WriteMessage(websocket.BinaryMessage, ping); err != nil {
+		t.Fatal(err)
+	}
+	var pong Message
+	if read(&pong); pong.Type != "pong" {
+		t.Errorf("expected a pong, got %+v", pong)
+	}
+}
@@ -0,0 +1,176 @@
+package websocket
+
+import "time"
+
+// NoResume is the ResumeFrom of a connection that doesn't replay missed messages
+const NoResume int64 = -1
+
+// Replay defaults; see SetReplay
+const (
+	defaultReplaySize   = 100
+	defaultReplayWindow = 5 * time.Minute
+)
+
+// broadcastReplay is the replays key of the broadcast buffer
+const broadcastReplay = ""
+
+// replayBuffer keeps an address's most recent messages, oldest first, for clients that
+// reconnect with resume_from
+type replayBuffer struct {
+	messages []Message
+	// evicted is the ID of the newest message dropped from the buffer; a client resuming
+	// from an older ID has missed messages that can't be replayed
+	evicted uint64
+}
+
+// add appends message, dropping the oldest beyond size
+func (b *replayBuffer) add(message Message, size int) {
+	b.messages = append(b.messages, message)
+	if over := len(b.messages) - size; over > 0 {
+		b.evicted = b.messages[over-1].ID
+		b.messages = append(b.messages[:0], b.messages[over:]...)
+	}
+}
+
+// prune drops the messages sent before cutoff and reports whether the buffer is now empty
+func (b *replayBuffer) prune(cutoff time.Time) bool {
+	kept := 0
+	for kept < len(b.messages) && b.messages[kept].Timestamp.Before(cutoff) {
+		kept++
+	}
+	if kept > 0 {
+		b.evicted = b.messages[kept-1].ID
+		b.messages = append(b.messages[:0], b.messages[kept:]...)
+	}
+	return len(b.messages) == 0
+}
+
+// since returns the buffered messages after id
+func (b *replayBuffer) since(id uint64) []Message {
+	for i, message := range b.messages {
+		if message.ID > id {
+			return b.messages[i:]
+		}
+	}
+	return nil
+}
+
+// SetReplay keeps up to size messages, sent within window, per address and for broadcasts
+// so reconnecting clients can replay what they missed; a size of 0 disables replay. Call it
+// before Start.
+func (m *Manager) SetReplay(size int, window time.Duration) {
+	if size >= 0 {
+		m.replaySize = size
+	}
+	if window > 0 {
+		m.replayWindow = window
+	}
+}
+
+// record numbers message and keeps it for replay to address (broadcastReplay for everyone)
+func (m *Manager) record(address string, message Message) Message {
+	m.replayMu.Lock()
+	defer m.replayMu.Unlock()
+
+	m.lastID++
+	message.ID = m.lastID
+	if m.replaySize == 0 {
+		return message
+	}
+	buffer, ok := m.replays[address]
+	if !ok {
+		buffer = &replayBuffer{}
+		m.replays[address] = buffer
+	}
+	buffer.add(message, m.replaySize)
+	return message
+}
+
+// replayable returns the messages for address (broadcasts only when empty) sent after from,
+// oldest first, with the ID of the last message sent so far. complete is false when some of
+// them are no longer buffered, or from is ahead of the last ID because the server restarted.
+func (m *Manager) replayable(address string, from uint64) (messages []Message, last uint64, complete bool) {
+	m.replayMu.Lock()
+	defer m.replayMu.Unlock()
+
+	complete = from <= m.lastID
+	var streams [][]Message
+	for _, key := range []string{broadcastReplay, address} {
+		buffer, ok := m.replays[key]
+		if !ok || (key == address && address == broadcastReplay) {
+			continue
+		}
+		if from < buffer.evicted {
+			complete = false
+		}
+		streams = append(streams, buffer.since(from))
+	}
+
+	// Merge the address's messages with the broadcasts by ID
+	if len(streams) == 1 {
+		messages = append(messages, streams[0]...)
+	} else if len(streams) == 2 {
+		a, b := streams[0], streams[1]
+		for len(a) > 0 || len(b) > 0 {
+			if len(b) == 0 || (len(a) > 0 && a[0].ID < b[0].ID) {
+				messages, a = append(messages, a[0]), a[1:]
+			} else {
+				messages, b = append(messages, b[0]), b[1:]
+			}
+		}
+	}
+	return messages, m.lastID, complete
+}
+
+// pruneReplays drops buffered messages older than the replay window
+func (m *Manager) pruneReplays() {
+	m.replayMu.Lock()
+	defer m.replayMu.Unlock()
+
+	cutoff := time.Now().Add(-m.replayWindow)
+	for address, buffer := range m.replays {
+		if buffer.prune(cutoff) {
+			delete(m.replays, address)
+		}
+	}
+}
+
+// resume sends a registering client the messages it missed since its ResumeFrom, then a
+// resumed message reporting how many were replayed and whether any were lost; the caller
+// holds m.mu. Live messages up to the last replayed ID are skipped afterwards, so none is
+// delivered twice.
+func (m *Manager) resume(client *Client) {
+	if client.subscription.ResumeFrom < 0 {
+		return
+	}
+	from := uint64(client.subscription.ResumeFrom)
+	buffered, last, complete := m.replayable(client.Address, from)
+
+	var messages []Message
+	for _, message := range buffered {
+		if client.wants(message) {
+			messages = append(messages, message)
+		}
+	}
+
+	// Keep room for the resumed message; the oldest are left out when the send buffer is short
+	if room := cap(client.Send) - len(client.Send) - 1; len(messages) > room {
+		messages = messages[len(messages)-room:]
+		complete = false
+	}
+	for _, message := range messages {
+		client.Send <- message
+	}
+	client.replayedThrough = last
+
+	client.Send <- Message{
+		Type: "resumed",
+		Data: map[string]interface{}{
+			"resume_from": from,
+			"replayed":    len(messages),
+			"complete":    complete,
+			"last_id":     last,
+		},
+		Timestamp: time.Now(),
+	}
+}
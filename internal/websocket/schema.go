@@ -0,0 +1,179 @@
+package websocket
+
+import (
+	"encoding/json"
+	"log"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ugorji/go/codec"
+)
+
+// Message encodings a WebSocket connection picks with ?encoding= when connecting. Binary
+// connections get each message as a MessagePack binary frame with the same keys as the JSON.
+const (
+	EncodingJSON    = "json"
+	EncodingMsgpack = "msgpack"
+)
+
+// Encodings lists the supported message encodings
+var Encodings = []string{EncodingJSON, EncodingMsgpack}
+
+// msgpackHandle encodes messages for binary connections; times use the MessagePack timestamp
+// extension
+var msgpackHandle = func() *codec.MsgpackHandle {
+	h := &codec.MsgpackHandle{WriteExt: true}
+	h.MapType = reflect.TypeOf(map[string]interface{}(nil))
+	return h
+}()
+
+// encodeMsgpack encodes v as MessagePack
+func encodeMsgpack(v interface{}) ([]byte, error) {
+	var b []byte
+	err := codec.NewEncoderBytes(&b, msgpackHandle).Encode(v)
+	return b, err
+}
+
+// decodeMsgpack decodes MessagePack data into v
+func decodeMsgpack(data []byte, v interface{}) error {
+	return codec.NewDecoderBytes(data, msgpackHandle).Decode(v)
+}
+
+// Payload is a typed message payload. Its schema version is sent with every message as v, so
+// clients can tell a breaking change from an added field.
+type Payload interface {
+	SchemaVersion() int
+}
+
+// Schema is the published JSON Schema of a message type's payload
+type Schema struct {
+	Type    string                 `json:"type"`
+	Version int                    `json:"version"`
+	Schema  map[string]interface{} `json:"schema"`
+}
+
+func (Notice) SchemaVersion() int { return 1 }
+
+// RegisterPayload declares the payload type of a message type: its schema is published and
+// payloads of that type arriving as JSON, e.g. fanned out from another replica, are decoded
+// back into it. Call it before Start.
+func (m *Manager) RegisterPayload(messageType string, payload Payload) {
+	m.payloads[messageType] = payload
+}
+
+// Schemas returns the JSON Schemas of the registered payloads, sorted by message type
+func (m *Manager) Schemas() []Schema {
+	schemas := make([]Schema, 0, len(m.payloads))
+	for messageType, payload := range m.payloads {
+		schema := jsonSchema(reflect.TypeOf(payload))
+		schema["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+		schema["title"] = messageType
+		schemas = append(schemas, Schema{Type: messageType, Version: payload.SchemaVersion(), Schema: schema})
+	}
+	sort.Slice(schemas, func(i, j int) bool {
+		return schemas[i].Type < schemas[j].Type
+	})
+	return schemas
+}
+
+// newMessage builds a message, typing payloads that arrive as raw JSON
+func (m *Manager) newMessage(chain, messageType string, data interface{}) Message {
+	if raw, ok := data.(json.RawMessage); ok {
+		data = m.decodePayload(messageType, raw)
+	}
+
+	message := Message{
+		Chain:     chain,
+		Type:      messageType,
+		Data:      data,
+		Timestamp: time.Now(),
+	}
+	if payload, ok := data.(Payload); ok {
+		message.Version = payload.SchemaVersion()
+	}
+	return message
+}
+
+// decodePayload decodes raw into the payload type registered for messageType, or into
+// generic JSON values for unregistered types
+func (m *Manager) decodePayload(messageType string, raw json.RawMessage) interface{} {
+	if payload, ok := m.payloads[messageType]; ok {
+		typed := reflect.New(reflect.TypeOf(payload))
+		if err := json.Unmarshal(raw, typed.Interface()); err == nil {
+			return typed.Elem().Interface()
+		}
+		log.Printf("⚠️ %s payload doesn't match its schema, sending it untyped", messageType)
+	}
+
+	var data interface{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return raw
+	}
+	return data
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// jsonSchema describes the JSON encoding of t
+func jsonSchema(t reflect.Type) map[string]interface{} {
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Ptr:
+		schema := jsonSchema(t.Elem())
+		schema["type"] = []interface{}{schema["type"], "null"}
+		return schema
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": jsonSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": jsonSchema(t.Elem())}
+	case reflect.Struct:
+		properties := make(map[string]interface{})
+		var required []string
+		addFields(t, properties, &required)
+		schema := map[string]interface{}{"type": "object", "properties": properties}
+		if len(required) > 0 {
+			sort.Strings(required)
+			schema["required"] = required
+		}
+		return schema
+	}
+	return map[string]interface{}{}
+}
+
+// addFields adds the JSON properties of struct t, flattening embedded structs as
+// encoding/json does; fields without omitempty are required
+func addFields(t reflect.Type, properties map[string]interface{}, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || (!field.IsExported() && !field.Anonymous) {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			addFields(field.Type, properties, required)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = jsonSchema(field.Type)
+		if !strings.Contains(options, "omitempty") {
+			*required = append(*required, name)
+		}
+	}
+}